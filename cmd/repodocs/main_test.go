@@ -10,14 +10,19 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/quantmind-br/repodocs/internal/config"
+	"github.com/quantmind-br/repodocs/internal/converter"
+	"github.com/quantmind-br/repodocs/internal/manifest"
+	"github.com/quantmind-br/repodocs/internal/output"
 	"github.com/quantmind-br/repodocs/tests/testutil"
 )
 
@@ -438,6 +443,10 @@ func TestCheckCacheDir(t *testing.T) {
 }
 
 func TestRootCmd(t *testing.T) {
+	oldStdinPiped := stdinPiped
+	defer func() { stdinPiped = oldStdinPiped }()
+	stdinPiped = func() bool { return false }
+
 	tests := []struct {
 		name          string
 		args          []string
@@ -559,6 +568,10 @@ func TestVersionCmd(t *testing.T) {
 }
 
 func TestRun(t *testing.T) {
+	oldStdinPiped := stdinPiped
+	defer func() { stdinPiped = oldStdinPiped }()
+	stdinPiped = func() bool { return false }
+
 	tests := []struct {
 		name          string
 		args          []string
@@ -724,8 +737,8 @@ func BenchmarkCheckWritePermissions(b *testing.B) {
 func TestManifestFlag_Registered(t *testing.T) {
 	flag := rootCmd.PersistentFlags().Lookup("manifest")
 	require.NotNil(t, flag)
-	assert.Equal(t, "string", flag.Value.Type())
-	assert.Equal(t, "", flag.DefValue)
+	assert.Equal(t, "stringArray", flag.Value.Type(), "repeatable")
+	assert.Equal(t, "[]", flag.DefValue)
 }
 
 func TestManifestFlag_MutualExclusivity(t *testing.T) {
@@ -737,10 +750,10 @@ sources:
 `
 	require.NoError(t, os.WriteFile(manifestFile, []byte(content), 0644))
 
-	oldManifestPath := manifestPath
-	defer func() { manifestPath = oldManifestPath }()
+	oldManifestPaths := manifestPaths
+	defer func() { manifestPaths = oldManifestPaths }()
 
-	manifestPath = manifestFile
+	manifestPaths = []string{manifestFile}
 
 	err := run(rootCmd, []string{"https://example.com"})
 
@@ -749,10 +762,10 @@ sources:
 }
 
 func TestManifestPath_FileNotFound(t *testing.T) {
-	oldManifestPath := manifestPath
-	defer func() { manifestPath = oldManifestPath }()
+	oldManifestPaths := manifestPaths
+	defer func() { manifestPaths = oldManifestPaths }()
 
-	manifestPath = "/nonexistent/manifest.yaml"
+	manifestPaths = []string{"/nonexistent/manifest.yaml"}
 
 	err := run(rootCmd, []string{})
 
@@ -765,13 +778,688 @@ func TestManifestPath_InvalidYAML(t *testing.T) {
 	manifestFile := filepath.Join(tmpDir, "manifest.yaml")
 	require.NoError(t, os.WriteFile(manifestFile, []byte("not: valid: yaml: ["), 0644))
 
-	oldManifestPath := manifestPath
-	defer func() { manifestPath = oldManifestPath }()
+	oldManifestPaths := manifestPaths
+	defer func() { manifestPaths = oldManifestPaths }()
 
-	manifestPath = manifestFile
+	manifestPaths = []string{manifestFile}
 
 	err := run(rootCmd, []string{})
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to load manifest")
 }
+
+func TestURLListFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("url-list")
+	require.NotNil(t, flag)
+	assert.Equal(t, "string", flag.Value.Type())
+}
+
+func TestURLList_FileNotFound(t *testing.T) {
+	require.NoError(t, rootCmd.PersistentFlags().Set("url-list", "/nonexistent/urls.txt"))
+	defer func() { _ = rootCmd.PersistentFlags().Set("url-list", "") }()
+
+	err := run(rootCmd, []string{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to open URL list")
+}
+
+func TestURLList_MutualExclusivity(t *testing.T) {
+	require.NoError(t, rootCmd.PersistentFlags().Set("url-list", "urls.txt"))
+	defer func() { _ = rootCmd.PersistentFlags().Set("url-list", "") }()
+
+	err := run(rootCmd, []string{"https://example.com"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot specify both --url-list and URL argument")
+}
+
+func TestURLList_StdinEmpty(t *testing.T) {
+	oldStdin := stdin
+	defer func() { stdin = oldStdin }()
+	stdin = strings.NewReader("# no URLs\n\n")
+
+	err := run(rootCmd, []string{"-"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load URL list")
+}
+
+func TestURLList_StdinPipedWithoutArgs(t *testing.T) {
+	oldStdin, oldStdinPiped := stdin, stdinPiped
+	defer func() { stdin, stdinPiped = oldStdin, oldStdinPiped }()
+	stdin = strings.NewReader("# no URLs\n\n")
+	stdinPiped = func() bool { return true }
+
+	err := run(rootCmd, []string{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load URL list")
+}
+
+func TestURLList_ContinueOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	oldStdin, oldStdinPiped := stdin, stdinPiped
+	defer func() { stdin, stdinPiped = oldStdin, oldStdinPiped }()
+	stdinPiped = func() bool { return false }
+
+	dir := t.TempDir()
+	reportPath := filepath.Join(dir, "report.json")
+	flags := rootCmd.PersistentFlags()
+	require.NoError(t, flags.Set("output", dir))
+	require.NoError(t, flags.Set("report", reportPath))
+	require.NoError(t, flags.Set("strategy", "crawler"))
+	require.NoError(t, flags.Set("no-fallback", "true"))
+	require.NoError(t, flags.Set("no-cache", "true"))
+	t.Cleanup(func() {
+		for _, name := range []string{"output", "report", "strategy", "no-fallback", "no-cache", "continue-on-error"} {
+			f := flags.Lookup(name)
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		}
+	})
+
+	list := server.URL + "/a\n" + server.URL + "/b\n"
+
+	// URL lists continue past failing URLs by default.
+	stdin = strings.NewReader(list)
+	_ = run(rootCmd, []string{"-"})
+	data, err := os.ReadFile(reportPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"continue_on_error": true`)
+	assert.Contains(t, string(data), `"not_run": 0`)
+
+	// --continue-on-error=false stops the sequential batch at the first one.
+	require.NoError(t, flags.Set("continue-on-error", "false"))
+	stdin = strings.NewReader(list)
+	err = run(rootCmd, []string{"-"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), server.URL+"/a")
+	data, err = os.ReadFile(reportPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"not_run": 1`)
+}
+
+func TestChromeArgFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("chrome-arg")
+	require.NotNil(t, flag)
+	assert.Equal(t, "stringArray", flag.Value.Type())
+}
+
+func TestConcurrencySourcesFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("concurrency-sources")
+	require.NotNil(t, flag)
+	assert.Equal(t, "0", flag.DefValue)
+}
+
+func TestDiffManifest(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.yaml")
+	newPath := filepath.Join(dir, "new.yaml")
+	require.NoError(t, os.WriteFile(oldPath, []byte("sources:\n  - url: https://a.example.com\n"), 0644))
+	require.NoError(t, os.WriteFile(newPath, []byte("sources:\n  - url: https://a.example.com/\n"), 0644))
+
+	var out bytes.Buffer
+	diffManifestCmd.SetOut(&out)
+	defer diffManifestCmd.SetOut(nil)
+
+	require.NoError(t, runDiffManifest(diffManifestCmd, []string{oldPath, newPath}))
+	assert.Contains(t, out.String(), "equivalent")
+
+	require.NoError(t, os.WriteFile(newPath, []byte("sources:\n  - url: https://b.example.com\n"), 0644))
+	out.Reset()
+	err := runDiffManifest(diffManifestCmd, []string{oldPath, newPath})
+	require.Error(t, err)
+	assert.Contains(t, out.String(), "+ https://b.example.com")
+	assert.Contains(t, out.String(), "- https://a.example.com")
+
+	err = runDiffManifest(diffManifestCmd, []string{filepath.Join(dir, "missing.yaml"), newPath})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load manifest")
+}
+
+func TestDiffManifest_DoesNotExpandEnv(t *testing.T) {
+	t.Setenv("REPODOCS_DIFF_SECRET", "s3cr3t")
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.yaml")
+	newPath := filepath.Join(dir, "new.yaml")
+	require.NoError(t, os.WriteFile(oldPath, []byte("sources:\n  - url: https://a.example.com/?token=${REPODOCS_DIFF_SECRET}\n"), 0644))
+	require.NoError(t, os.WriteFile(newPath, []byte("sources:\n  - url: https://a.example.com/?token=${REPODOCS_DIFF_SECRET}\n  - url: ${REPODOCS_DIFF_UNSET}/docs\n"), 0644))
+
+	var out bytes.Buffer
+	diffManifestCmd.SetOut(&out)
+	defer diffManifestCmd.SetOut(nil)
+
+	err := runDiffManifest(diffManifestCmd, []string{oldPath, newPath})
+	require.Error(t, err)
+	assert.Equal(t, "manifests differ", err.Error())
+	assert.Contains(t, out.String(), "+ ${REPODOCS_DIFF_UNSET}/docs")
+	assert.NotContains(t, out.String(), "s3cr3t")
+}
+
+func TestSiteBaseURLFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("site-base-url")
+	require.NotNil(t, flag)
+	assert.Equal(t, "", flag.DefValue)
+}
+
+func TestIgnoreDirFlags_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("ignore-dir")
+	require.NotNil(t, flag)
+	assert.Equal(t, "stringArray", flag.Value.Type())
+	require.NotNil(t, rootCmd.PersistentFlags().Lookup("replace-ignore-dirs"))
+}
+
+func TestIncludeGitHubMetaFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("include-github-meta")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestSubmodulesFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("submodules")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestLFSFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("lfs")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestCleanMDXFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("clean-mdx")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestDeadlineFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("deadline")
+	require.NotNil(t, flag)
+	assert.Equal(t, "duration", flag.Value.Type())
+	assert.Equal(t, "0s", flag.DefValue)
+}
+
+func TestMaxErrorsFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("max-errors")
+	require.NotNil(t, flag)
+	assert.Equal(t, "int", flag.Value.Type())
+	assert.Equal(t, "0", flag.DefValue)
+}
+
+func TestContentSelectorStrictFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("content-selector-strict")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestNoEnrichFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("no-enrich")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestLineEndingsFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("line-endings")
+	require.NotNil(t, flag)
+	assert.Equal(t, "lf", flag.DefValue)
+}
+
+func TestUserAgentsFileFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("user-agents-file")
+	require.NotNil(t, flag)
+	assert.Equal(t, "", flag.DefValue)
+}
+
+func TestTruncationRetriesFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("truncation-retries")
+	require.NotNil(t, flag)
+	assert.Equal(t, "int", flag.Value.Type())
+	assert.Equal(t, "2", flag.DefValue)
+}
+
+func TestIncludeHiddenFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("include-hidden")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestOutputFormatFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("output-format")
+	require.NotNil(t, flag)
+	assert.Equal(t, "string", flag.Value.Type())
+	assert.Equal(t, "markdown", flag.DefValue)
+}
+
+func TestChunkFlags_Registered(t *testing.T) {
+	for name, def := range map[string]string{
+		"chunk":         "false",
+		"chunk-size":    "1000",
+		"chunk-overlap": "100",
+		"chunk-unit":    "chars",
+	} {
+		flag := rootCmd.PersistentFlags().Lookup(name)
+		require.NotNil(t, flag, name)
+		assert.Equal(t, def, flag.DefValue, name)
+	}
+}
+
+func TestManifestPath_StdinWithOthers(t *testing.T) {
+	oldManifestPaths := manifestPaths
+	defer func() { manifestPaths = oldManifestPaths }()
+
+	manifestPaths = []string{"-", "/tmp/other.yaml"}
+
+	err := run(rootCmd, []string{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be combined")
+}
+
+func TestManifestFormatFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("manifest-format")
+	require.NotNil(t, flag)
+	assert.Equal(t, "string", flag.Value.Type())
+	assert.Equal(t, "auto", flag.DefValue)
+}
+
+func TestNoManifestEnvFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("no-manifest-env")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestAllowDuplicateSourcesFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("allow-duplicate-sources")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestReportFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("report")
+	require.NotNil(t, flag)
+	assert.Equal(t, "string", flag.Value.Type())
+	assert.Equal(t, "", flag.DefValue)
+}
+
+func TestCloneTimeoutFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("clone-timeout")
+	require.NotNil(t, flag)
+	assert.Equal(t, "duration", flag.Value.Type())
+	assert.Equal(t, "5m0s", flag.DefValue)
+}
+
+func TestKeepTempFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("keep-temp")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestNoSpaceCheckFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("no-space-check")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestStrictFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("strict")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestCrawlDelayFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("crawl-delay")
+	require.NotNil(t, flag)
+	assert.Equal(t, "duration", flag.Value.Type())
+	assert.Equal(t, "0s", flag.DefValue)
+}
+
+func TestRequestJitterFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("request-jitter")
+	require.NotNil(t, flag)
+	assert.Equal(t, "duration", flag.Value.Type())
+	assert.Equal(t, "0s", flag.DefValue)
+}
+
+func TestMaxRedirectsFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("max-redirects")
+	require.NotNil(t, flag)
+	assert.Equal(t, "int", flag.Value.Type())
+	assert.Equal(t, "10", flag.DefValue)
+}
+
+func TestNoCrossHostRedirectsFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("no-cross-host-redirects")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestPruneRemovedFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("prune-removed")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestHashAlgorithmFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("hash-algorithm")
+	require.NotNil(t, flag)
+	assert.Equal(t, "sha256", flag.DefValue)
+}
+
+func TestFollowNextFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("follow-next")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestOpenAPIFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("openapi")
+	require.NotNil(t, flag)
+	assert.Equal(t, "string", flag.Value.Type())
+	assert.Equal(t, "", flag.DefValue)
+}
+
+func TestTagFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("tag")
+	require.NotNil(t, flag)
+	assert.Equal(t, "stringArray", flag.Value.Type())
+}
+
+func TestParseTagFlag(t *testing.T) {
+	newCmd := func(values ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringArray("tag", nil, "")
+		for _, v := range values {
+			require.NoError(t, cmd.Flags().Set("tag", v))
+		}
+		return cmd
+	}
+
+	labels, err := parseTagFlag(newCmd())
+	require.NoError(t, err)
+	assert.Nil(t, labels)
+
+	labels, err = parseTagFlag(newCmd("team=platform", "product = billing", "team=docs", "note=a=b", "empty="))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "docs", "product": "billing", "note": "a=b", "empty": ""}, labels)
+
+	for _, bad := range []string{"team", "=platform"} {
+		_, err = parseTagFlag(newCmd(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestListStrategiesFlag(t *testing.T) {
+	dir := t.TempDir()
+	// An earlier --help case leaves the help flag set on rootCmd.
+	if f := rootCmd.Flags().Lookup("help"); f != nil {
+		_ = f.Value.Set("false")
+	}
+	t.Cleanup(func() {
+		for _, name := range []string{"list-strategies", "output"} {
+			f := rootCmd.PersistentFlags().Lookup(name)
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		}
+	})
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+
+	rootCmd.SetArgs([]string{"--list-strategies", "--output", dir, "https://pkg.go.dev/github.com/owner/module"})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), "Selected: pkggo")
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	rootCmd.SetArgs([]string{"--list-strategies"})
+	assert.Error(t, rootCmd.Execute())
+}
+
+func TestProbeCmd_Registered(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"probe"})
+	require.NoError(t, err)
+	assert.Equal(t, probeCmd, cmd)
+	assert.Error(t, cmd.Args(cmd, []string{}), "probe requires exactly one URL")
+}
+
+func TestStatsCmd(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"stats"})
+	require.NoError(t, err)
+	assert.Equal(t, statsCmd, cmd)
+	assert.Error(t, cmd.Args(cmd, []string{}), "stats requires exactly one directory")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "page.md"), []byte("---\ntitle: Page\n---\n\nHello world\n"), 0644))
+
+	var out bytes.Buffer
+	statsCmd.SetOut(&out)
+	defer statsCmd.SetOut(nil)
+
+	require.NoError(t, runStats(statsCmd, []string{dir}))
+	assert.Contains(t, out.String(), "Documents:    1")
+	assert.Contains(t, out.String(), "Words:        2")
+
+	err = runStats(statsCmd, []string{filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+}
+
+func TestVerifyCmd(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"verify"})
+	require.NoError(t, err)
+	assert.Equal(t, verifyCmd, cmd)
+	assert.Error(t, cmd.Args(cmd, []string{}), "verify requires exactly one directory")
+
+	dir := t.TempDir()
+	page := filepath.Join(dir, "page.md")
+	require.NoError(t, os.WriteFile(page, []byte("# Page\n"), 0644))
+	_, err = output.WriteChecksums(dir, []string{page}, converter.HashSHA256)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	verifyCmd.SetOut(&out)
+	defer verifyCmd.SetOut(nil)
+
+	require.NoError(t, runVerify(verifyCmd, []string{dir}))
+	assert.Contains(t, out.String(), "All files match.")
+
+	require.NoError(t, os.WriteFile(page, []byte("# Tampered\n"), 0644))
+	out.Reset()
+	err = runVerify(verifyCmd, []string{dir})
+	assert.ErrorContains(t, err, "does not match its checksums.txt")
+	assert.Contains(t, out.String(), "Modified (1):\n  page.md")
+}
+
+func TestChecksumsFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("checksums")
+	require.NotNil(t, flag)
+	assert.Equal(t, "bool", flag.Value.Type())
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestForceContentTypeFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("force-content-type")
+	require.NotNil(t, flag)
+	assert.Equal(t, "", flag.DefValue)
+}
+
+func TestPreferMarkdownFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("prefer-markdown")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestImagesFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("images")
+	require.NotNil(t, flag)
+	assert.Equal(t, "keep", flag.DefValue)
+}
+
+func TestRewriteLinksFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("rewrite-links")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestHostBreakerFlags_Registered(t *testing.T) {
+	threshold := rootCmd.PersistentFlags().Lookup("host-breaker-threshold")
+	require.NotNil(t, threshold)
+	assert.Equal(t, "10", threshold.DefValue)
+
+	cooldown := rootCmd.PersistentFlags().Lookup("host-breaker-cooldown")
+	require.NotNil(t, cooldown)
+	assert.Equal(t, "1m0s", cooldown.DefValue)
+}
+
+func TestDryRunStateFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("dry-run-state")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestConfigShow_ResolvesFlags(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	oldDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer os.Chdir(oldDir)
+
+	t.Cleanup(func() {
+		for _, name := range []string{"concurrency-sources", "no-cache"} {
+			f := rootCmd.PersistentFlags().Lookup(name)
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		}
+	})
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"config", "show", "--concurrency-sources", "4", "--no-cache"})
+	require.NoError(t, rootCmd.Execute())
+
+	output := buf.String()
+	assert.Contains(t, output, "# config file: none")
+	assert.Contains(t, output, "sources: 4")
+	assert.Contains(t, output, "host_breaker_threshold: 10")
+	assert.Regexp(t, `cache:\n\s+enabled: false`, output)
+}
+
+func TestConfigInit_RespectsConfigPathAndForce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repodocs.yaml")
+
+	t.Cleanup(func() {
+		cfgFile = ""
+		f := rootCmd.PersistentFlags().Lookup("force")
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+
+	rootCmd.SetArgs([]string{"config", "init", "--config", path})
+	require.NoError(t, rootCmd.Execute())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Number of concurrent page workers")
+
+	rootCmd.SetArgs([]string{"config", "init", "--config", path})
+	assert.Error(t, rootCmd.Execute())
+
+	rootCmd.SetArgs([]string{"config", "init", "--config", path, "--force"})
+	assert.NoError(t, rootCmd.Execute())
+}
+
+func TestInit_WritesConfigAndSources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "repodocs.yaml")
+	sources := filepath.Join(dir, "sources.yaml")
+
+	t.Cleanup(func() {
+		cfgFile = ""
+		f := rootCmd.PersistentFlags().Lookup("force")
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+		f = initCmd.Flags().Lookup("sources")
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+
+	rootCmd.SetArgs([]string{"init", "--config", path, "--sources=" + sources})
+	require.NoError(t, rootCmd.Execute())
+	assert.Contains(t, buf.String(), path)
+	assert.Contains(t, buf.String(), sources)
+	data, err := os.ReadFile(sources)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Template, string(data))
+	assert.FileExists(t, path)
+
+	// An existing manifest is refused before the config is rewritten.
+	require.NoError(t, os.Remove(path))
+	rootCmd.SetArgs([]string{"init", "--config", path, "--sources=" + sources})
+	assert.Error(t, rootCmd.Execute())
+	assert.NoFileExists(t, path)
+
+	rootCmd.SetArgs([]string{"init", "--config", path, "--sources=" + sources, "--force"})
+	assert.NoError(t, rootCmd.Execute())
+	assert.FileExists(t, path)
+}
+
+func TestProfileFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("profile")
+	require.NotNil(t, flag)
+	assert.Equal(t, "", flag.DefValue)
+}
+
+func TestDetectAuthWallsFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("detect-auth-walls")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestHeadingShiftFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("heading-shift")
+	require.NotNil(t, flag)
+	assert.Equal(t, "0", flag.DefValue)
+}
+
+func TestPreserveAdmonitionsFlag_Registered(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("preserve-admonitions")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
+
+func TestSelftestCmd_Registered(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"selftest"})
+	require.NoError(t, err)
+	assert.Equal(t, selftestCmd, cmd)
+	assert.Error(t, cmd.Args(cmd, []string{"extra"}), "selftest takes no arguments")
+
+	flag := selftestCmd.Flags().Lookup("selftest-render")
+	require.NotNil(t, flag)
+	assert.Equal(t, "false", flag.DefValue)
+}
@@ -12,6 +12,7 @@ type StrategyType string
 
 const (
 	StrategyLLMS        StrategyType = "llms"
+	StrategyOpenAPI     StrategyType = "openapi"
 	StrategyPkgGo       StrategyType = "pkggo"
 	StrategyDocsRS      StrategyType = "docsrs"
 	StrategySitemap     StrategyType = "sitemap"
@@ -24,6 +25,7 @@ const (
 
 var validStrategies = map[StrategyType]bool{
 	StrategyLLMS:        true,
+	StrategyOpenAPI:     true,
 	StrategyPkgGo:       true,
 	StrategyDocsRS:      true,
 	StrategySitemap:     true,
@@ -82,6 +84,10 @@ func DetectStrategy(rawURL string) StrategyType {
 		return StrategyLLMS
 	}
 
+	if strategies.IsOpenAPIURL(rawURL) {
+		return StrategyOpenAPI
+	}
+
 	if strings.Contains(lower, "pkg.go.dev") {
 		return StrategyPkgGo
 	}
@@ -129,10 +135,27 @@ func DetectStrategy(rawURL string) StrategyType {
 	return StrategyUnknown
 }
 
+// detectStrategy is DetectStrategy that also routes URLs on the configured
+// self-hosted git hosts (git.self_hosted_hosts) to the git strategy; they
+// would otherwise be crawled. File views (/blob/) are still crawled.
+func (o *Orchestrator) detectStrategy(rawURL string) StrategyType {
+	detected := DetectStrategy(rawURL)
+	if detected != StrategyCrawler || o.gitParser == nil {
+		return detected
+	}
+	rawURL = strings.TrimSpace(rawURL)
+	if o.gitParser.IsSelfHosted(rawURL) && !strings.Contains(strings.ToLower(rawURL), "/blob/") {
+		return StrategyGit
+	}
+	return detected
+}
+
 func CreateStrategy(strategyType StrategyType, deps *strategies.Dependencies) strategies.Strategy {
 	switch strategyType {
 	case StrategyLLMS:
 		return strategies.NewLLMSStrategy(deps)
+	case StrategyOpenAPI:
+		return strategies.NewOpenAPIStrategy(deps)
 	case StrategyPkgGo:
 		return strategies.NewPkgGoStrategy(deps)
 	case StrategyDocsRS:
@@ -155,6 +178,7 @@ func CreateStrategy(strategyType StrategyType, deps *strategies.Dependencies) st
 func GetAllStrategies(deps *strategies.Dependencies) []strategies.Strategy {
 	return []strategies.Strategy{
 		strategies.NewLLMSStrategy(deps),
+		strategies.NewOpenAPIStrategy(deps),
 		strategies.NewPkgGoStrategy(deps),
 		strategies.NewDocsRSStrategy(deps),
 		strategies.NewSitemapStrategy(deps),
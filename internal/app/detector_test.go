@@ -7,6 +7,7 @@ import (
 
 	"github.com/quantmind-br/repodocs/internal/config"
 	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/manifest"
 	"github.com/quantmind-br/repodocs/internal/strategies"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,6 +27,12 @@ func TestDetectStrategy(t *testing.T) {
 		{"llms.txt uppercase", "HTTPS://EXAMPLE.COM/LLMS.TXT", StrategyLLMS},
 		{"llms.txt with params", "https://example.com/llms.txt?v=1", StrategyLLMS},
 
+		// OpenAPI
+		{"openapi.json", "https://api.example.com/openapi.json", StrategyOpenAPI},
+		{"swagger.yaml with path", "https://example.com/api/v1/swagger.yaml?v=2", StrategyOpenAPI},
+		{"versioned openapi.yml", "https://example.com/openapi.v2.yml", StrategyOpenAPI},
+		{"other json is crawler", "https://example.com/data.json", StrategyCrawler},
+
 		// PkgGo
 		{"pkg.go.dev", "https://pkg.go.dev/github.com/pkg/errors", StrategyPkgGo},
 		{"pkg.go.dev uppercase", "HTTPS://PKG.GO.DEV/github.com/pkg/errors", StrategyPkgGo},
@@ -151,7 +158,7 @@ func TestGetAllStrategies(t *testing.T) {
 	defer deps.Close()
 
 	strategies := GetAllStrategies(deps)
-	assert.Len(t, strategies, 8)
+	assert.Len(t, strategies, 9)
 
 	names := make(map[string]bool)
 	for _, s := range strategies {
@@ -159,6 +166,7 @@ func TestGetAllStrategies(t *testing.T) {
 	}
 
 	assert.True(t, names["llms"])
+	assert.True(t, names["openapi"])
 	assert.True(t, names["pkggo"])
 	assert.True(t, names["docsrs"])
 	assert.True(t, names["sitemap"])
@@ -353,14 +361,24 @@ func TestOrchestrator_ValidateURL(t *testing.T) {
 	defer orch.Close()
 
 	t.Run("valid URL", func(t *testing.T) {
-		err := orch.ValidateURL("https://example.com/docs")
+		err := orch.ValidateURL(context.Background(), "https://example.com/docs")
 		assert.NoError(t, err)
 	})
 
 	t.Run("unknown URL format", func(t *testing.T) {
-		err := orch.ValidateURL("ftp://example.com")
+		err := orch.ValidateURL(context.Background(), "ftp://example.com")
 		assert.Error(t, err)
 	})
+
+	t.Run("strategy validation", func(t *testing.T) {
+		err := orch.ValidateURL(context.Background(), "https://github.com/owner")
+		var strategyErr *domain.StrategyError
+		require.ErrorAs(t, err, &strategyErr)
+		assert.Equal(t, "git", strategyErr.Strategy)
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Contains(t, validationErr.Message, "invalid GitHub repo path")
+	})
 }
 
 // TestOrchestrator_Close tests closing the orchestrator
@@ -409,3 +427,40 @@ func (m *mockStrategy) Execute(ctx context.Context, url string, opts strategies.
 	result.Finish()
 	return result, nil
 }
+
+// TestIsValidStrategy_MatchesManifest checks that manifests accept exactly
+// the strategies the orchestrator can run.
+func TestIsValidStrategy_MatchesManifest(t *testing.T) {
+	for _, name := range manifest.Strategies {
+		assert.True(t, IsValidStrategy(StrategyType(name)), name)
+	}
+	assert.Len(t, validStrategies, len(manifest.Strategies))
+}
+
+// TestOrchestrator_DetectStrategy_SelfHostedHosts tests that hosts listed in
+// git.self_hosted_hosts are extracted as git repositories
+func TestOrchestrator_DetectStrategy_SelfHostedHosts(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir()},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+		Git:     config.GitConfig{SelfHostedHosts: map[string]string{"gitlab.internal.corp": "gitlab"}},
+	}
+	o, err := NewOrchestrator(OrchestratorOptions{Config: cfg})
+	require.NoError(t, err)
+	defer o.Close()
+
+	assert.Equal(t, StrategyCrawler, DetectStrategy("https://gitlab.internal.corp/team/repo"))
+	assert.Equal(t, StrategyGit, o.detectStrategy("https://gitlab.internal.corp/team/repo"))
+	assert.Equal(t, StrategyGit, o.detectStrategy("https://GitLab.internal.corp/team/repo/-/tree/main/docs"))
+	assert.Equal(t, StrategyCrawler, o.detectStrategy("https://gitlab.internal.corp/team/repo/-/blob/main/README.md"))
+	assert.Equal(t, StrategyCrawler, o.detectStrategy("https://docs.internal.corp/guide"))
+
+	report, err := o.ListStrategies("https://gitlab.internal.corp/team/repo", "")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyGit, report.Selected)
+	for _, match := range report.Matches {
+		if match.Name == "git" {
+			assert.True(t, match.CanHandle, "the git strategy accepts the self-hosted host")
+		}
+	}
+}
@@ -177,21 +177,41 @@ func (o *Orchestrator) execAttempt(
 		CommonOptions: domain.CommonOptions{
 			Verbose:  opts.Verbose,
 			DryRun:   opts.DryRun,
-			Force:    opts.Force || o.config.Output.Overwrite,
+			Force:    opts.force(o.config),
 			RenderJS: opts.RenderJS || o.config.Rendering.ForceJS,
 			Limit:    opts.Limit,
 		},
-		Output:          o.config.Output.Directory,
-		Concurrency:     o.config.Concurrency.Workers,
-		MaxDepth:        o.config.Concurrency.MaxDepth,
-		Exclude:         append(o.config.Exclude, opts.ExcludePatterns...),
-		NoFolders:       o.config.Output.Flat,
-		Split:           opts.Split,
-		IncludeAssets:   opts.IncludeAssets,
-		ContentSelector: opts.ContentSelector,
-		ExcludeSelector: opts.ExcludeSelector,
-		FilterURL:       a.FilterURL,
+		Output:            o.config.Output.Directory,
+		Concurrency:       o.workers(opts),
+		MaxDepth:          o.config.Concurrency.MaxDepth,
+		Exclude:           append(o.config.Exclude, opts.ExcludePatterns...),
+		Include:           opts.IncludePatterns,
+		ExcludePaths:      opts.ExcludePatterns,
+		NoFolders:         o.config.Output.Flat,
+		Split:             opts.Split,
+		IncludeAssets:     opts.IncludeAssets,
+		ContentSelector:   opts.ContentSelector,
+		ExcludeSelector:   opts.ExcludeSelector,
+		FilterURL:         a.FilterURL,
+		PreserveTree:      opts.PreserveTree,
+		HonorGitignore:    o.config.Git.HonorGitignore,
+		IgnoreDirs:        o.config.Git.IgnoreDirs,
+		ReplaceIgnoreDirs: o.config.Git.ReplaceIgnoreDirs,
+		IncludeGitHubMeta: o.config.Git.IncludeGitHubMeta,
+		Submodules:        o.config.Git.Submodules,
+		LFS:               o.config.Git.LFS,
+		CloneTimeout:      o.config.Git.CloneTimeout,
+		CleanMDX:          o.config.Git.CleanMDX,
+		FrontMatterKeys:   opts.FrontMatterKeys,
+		FollowNext:        opts.FollowNext,
+		NoProgress:        opts.NoProgress,
 	}
 
-	return strategy.Execute(ctx, a.URL, strategyOpts)
+	result, err := strategy.Execute(ctx, a.URL, strategyOpts)
+	if ctx.Err() == nil {
+		// Give pages that failed transiently (e.g. host throttling during a
+		// busy crawl) one bounded final sweep before they count as failures.
+		o.deps.RetryFailedPages(ctx, strategyOpts, result)
+	}
+	return result, err
 }
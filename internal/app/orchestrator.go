@@ -2,16 +2,26 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/quantmind-br/repodocs/internal/config"
+	"github.com/quantmind-br/repodocs/internal/converter"
 	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/fetcher"
 	"github.com/quantmind-br/repodocs/internal/manifest"
+	"github.com/quantmind-br/repodocs/internal/output"
 	"github.com/quantmind-br/repodocs/internal/recovery"
+	"github.com/quantmind-br/repodocs/internal/renderer"
+	"github.com/quantmind-br/repodocs/internal/state"
 	"github.com/quantmind-br/repodocs/internal/strategies"
+	"github.com/quantmind-br/repodocs/internal/strategies/git"
 	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
@@ -24,22 +34,153 @@ type Orchestrator struct {
 	validator       *recovery.Validator
 	planner         *recovery.Planner
 	probeRunner     *recovery.ProbeRunner
+	// gitParser recognizes the self-hosted git hosts of the config for
+	// strategy detection.
+	gitParser *git.Parser
+
+	// partialRun records that a run may not have seen every page of its
+	// source (see pruneBlocker), so a manifest must not prune afterwards.
+	partialRun atomic.Bool
+	// manifestResults holds the per-source outcome of the last RunManifest.
+	manifestResults []ManifestResult
 }
 
 // OrchestratorOptions contains options for creating an orchestrator
 type OrchestratorOptions struct {
 	domain.CommonOptions
-	Config           *config.Config
-	Split            bool
-	IncludeAssets    bool
-	ContentSelector  string
-	ExcludeSelector  string
-	ExcludePatterns  []string
-	FilterURL        string
-	StrategyFactory  func(StrategyType, *strategies.Dependencies) strategies.Strategy
-	StrategyOverride string
-	MinDocs          int
-	NoFallback       bool
+	Config          *config.Config
+	Split           bool
+	IncludeAssets   bool
+	ContentSelector string
+	ExcludeSelector string
+	ExcludePatterns []string
+	// IncludePatterns keeps only the git repository files matching one of
+	// these gitignore-style globs; git sources match ExcludePatterns as
+	// globs too.
+	IncludePatterns     []string
+	FilterURL           string
+	StrategyFactory     func(StrategyType, *strategies.Dependencies) strategies.Strategy
+	StrategyOverride    string
+	MinDocs             int
+	NoFallback          bool
+	StripFrontMatter    bool
+	FrontMatterKeys     []string
+	NormalizeWhitespace bool
+	// StripCommonBlocks removes blocks repeated on at least CommonThreshold of
+	// a site's pages after the run (0 uses output.DefaultCommonThreshold).
+	StripCommonBlocks bool
+	CommonThreshold   float64
+	// OutputName names the written file for single-URL runs; SlugFrom picks
+	// "url" (default) or "title" derived filenames.
+	OutputName string
+	SlugFrom   string
+	// MaxPagesPerHost caps the pages processed per host for the lifetime of
+	// the orchestrator, independently of Limit (0 = no cap).
+	MaxPagesPerHost int
+	// PreserveTree mirrors a git repository's directory structure exactly in
+	// the output. It cannot be combined with a flat output layout.
+	PreserveTree bool
+	// ConcurrencySources is the number of manifest sources extracted in
+	// parallel; it overrides the manifest's options.concurrency_sources
+	// (0 = use the manifest value or the default).
+	ConcurrencySources int
+	// Workers overrides concurrency.workers as the number of page workers of
+	// an extraction (0 = use the config value). Manifest sources set it from
+	// their concurrency field.
+	Workers int
+	// ForceOverride, when set, decides whether existing files are
+	// overwritten, over Force and output.overwrite. Manifest sources set it
+	// from their force field.
+	ForceOverride *bool
+	// ForceContentType overrides the content type servers report for every
+	// fetched page: "html", "markdown", "text" or a media type.
+	ForceContentType string
+	// PreferMarkdown fetches raw markdown where hosts offer it (raw-content
+	// URLs on code hosts, Accept negotiation elsewhere) instead of HTML.
+	PreferMarkdown bool
+	// Images is how images in converted documents are handled: "keep"
+	// (default), "drop" or "alt" (replace with alt text).
+	Images string
+	// HeadingShift moves every heading of converted documents by this many
+	// levels, from -5 to 5: positive demotes (# becomes ##), negative
+	// promotes. Headings stay within # through ######.
+	HeadingShift int
+	// PreserveAdmonitions renders admonitions and callouts of docs themes
+	// such as MkDocs Material and Docusaurus as GitHub callouts (> [!NOTE]),
+	// keeping their type and title, instead of flattening them to paragraphs.
+	PreserveAdmonitions bool
+	// RewriteLinks rewrites links between the written pages to relative
+	// local paths after the run, so the output is browsable offline.
+	RewriteLinks bool
+	// HostBreakerThreshold is the number of consecutive failures to a host
+	// after which its requests fail fast for HostBreakerCooldown (0 disables
+	// the breaker).
+	HostBreakerThreshold int
+	HostBreakerCooldown  time.Duration
+	// CrawlDelay is the minimum time between requests to one host; requests
+	// to different hosts still run in parallel (0 disables it).
+	CrawlDelay time.Duration
+	// RequestJitter is the maximum random delay added before each request,
+	// on top of CrawlDelay (0 disables it).
+	RequestJitter time.Duration
+	// RateLimit is the requests per second allowed to each host across the
+	// run (0 disables it).
+	RateLimit float64
+	// Fetcher replaces the built-in HTTP client for every strategy, e.g. to
+	// go through an authenticated proxy. It must follow the domain.Fetcher
+	// contract; the response cache, crawl delay and rate limit then do not
+	// apply.
+	Fetcher domain.Fetcher
+	// MaxRedirects is the longest redirect chain followed for a page. 0
+	// uses fetch.max_redirects from the config and a negative value follows
+	// none.
+	MaxRedirects int
+	// NoCrossHostRedirects drops pages redirected to another host, logging
+	// the redirect, instead of following it.
+	NoCrossHostRedirects bool
+	// DryRunState previews an incremental run: documents are compared with
+	// the stored state (see StateDelta) but neither documents nor the state
+	// are written. It requires DryRun and Sync, and excludes FullSync.
+	DryRunState bool
+	// Deadline caps the wall-clock duration of Run and RunManifest (0 = no
+	// cap). When it passes, in-flight work is cancelled, completed documents
+	// and state are kept, and the run returns domain.ErrDeadlineExceeded.
+	Deadline time.Duration
+	// MaxErrors aborts Run and RunManifest once this many documents have
+	// failed (0 = unlimited). Skipped and deduplicated documents do not
+	// count. Completed documents and state are kept, and the run returns
+	// domain.ErrTooManyErrors.
+	MaxErrors int
+	// Strict aborts Run and RunManifest at the first document failure, as
+	// MaxErrors 1 would. By default a failed document is recorded in the
+	// StrategyResult and listed at the end of the run, which continues.
+	Strict bool
+	// NoEnrich skips the reading-time and language enrichment of written
+	// documents.
+	NoEnrich bool
+	// NoSpaceCheck skips the free disk space check before git repositories
+	// are downloaded.
+	NoSpaceCheck bool
+	// KeepTemp keeps the temporary directories of git and wiki downloads.
+	KeepTemp bool
+	// ContentSelectorStrict skips pages where ContentSelector yields no
+	// content instead of falling back to common content containers.
+	ContentSelectorStrict bool
+	// FollowNext makes the crawler walk each page's rel="next" link in order
+	// instead of crawling breadth-first.
+	FollowNext bool
+	// NoProgress hides the progress bars of extractions, such as for the
+	// self-test, whose output is its report.
+	NoProgress bool
+	// Labels are key/value tags attached to every written document. Manifest
+	// source tags are merged over them.
+	Labels map[string]string
+	// OutputSubdir writes the documents under this directory of the output
+	// directory (manifest source output).
+	OutputSubdir string
+	// ReportPath is the file RunManifest writes a JSON summary of the run to
+	// (see ManifestReport); empty writes none.
+	ReportPath string
 }
 
 // NewOrchestrator creates a new orchestrator with the given configuration
@@ -50,6 +191,79 @@ func NewOrchestrator(opts OrchestratorOptions) (*Orchestrator, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config is required")
 	}
+	if opts.CommonThreshold < 0 || opts.CommonThreshold > 1 {
+		return nil, fmt.Errorf("common threshold must be between 0 and 1, got %g", opts.CommonThreshold)
+	}
+	if opts.MaxPagesPerHost < 0 {
+		return nil, fmt.Errorf("max pages per host must not be negative, got %d", opts.MaxPagesPerHost)
+	}
+	if opts.PreserveTree && cfg.Output.Flat {
+		return nil, fmt.Errorf("preserve-tree cannot be combined with a flat output layout (--nofolders)")
+	}
+	if opts.HostBreakerThreshold < 0 || opts.HostBreakerCooldown < 0 {
+		return nil, fmt.Errorf("host breaker threshold and cooldown must not be negative")
+	}
+	if opts.CrawlDelay < 0 {
+		return nil, fmt.Errorf("crawl delay must not be negative, got %s", opts.CrawlDelay)
+	}
+	if opts.RequestJitter < 0 {
+		return nil, fmt.Errorf("request jitter must not be negative, got %s", opts.RequestJitter)
+	}
+	if opts.RateLimit < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative, got %g", opts.RateLimit)
+	}
+	if opts.Deadline < 0 {
+		return nil, fmt.Errorf("deadline must not be negative, got %s", opts.Deadline)
+	}
+	if opts.MaxErrors < 0 {
+		return nil, fmt.Errorf("max errors must not be negative, got %d", opts.MaxErrors)
+	}
+	if opts.DryRunState && (!opts.DryRun || !opts.Sync || opts.FullSync) {
+		return nil, fmt.Errorf("dry-run-state requires dry-run and sync, and cannot be combined with full-sync")
+	}
+	if opts.ConcurrencySources < 0 {
+		return nil, fmt.Errorf("source concurrency must not be negative, got %d", opts.ConcurrencySources)
+	}
+	forceContentType, err := converter.NormalizeContentType(opts.ForceContentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid force content type: %w", err)
+	}
+	imageHandling, err := converter.ParseImageHandling(opts.Images)
+	if err != nil {
+		return nil, err
+	}
+	if err := converter.ValidateHeadingShift(opts.HeadingShift); err != nil {
+		return nil, err
+	}
+	lineEndings, err := output.ParseLineEndings(cfg.Output.LineEndings)
+	if err != nil {
+		return nil, err
+	}
+	hashAlgorithm, err := converter.ParseHashAlgorithm(cfg.Output.HashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	outputFormat, err := output.ParseFormat(cfg.Output.Format)
+	if err != nil {
+		return nil, err
+	}
+	var chunks output.ChunkOptions
+	if cfg.Output.Chunk {
+		unit, err := output.ParseChunkUnit(cfg.Output.ChunkUnit)
+		if err != nil {
+			return nil, err
+		}
+		chunks = output.ChunkOptions{Size: cfg.Output.ChunkSize, Overlap: cfg.Output.ChunkOverlap, Unit: unit}
+	}
+	switch opts.SlugFrom {
+	case "", output.SlugFromURL, output.SlugFromTitle:
+	default:
+		return nil, fmt.Errorf("invalid slug source %q (use %q or %q)", opts.SlugFrom, output.SlugFromURL, output.SlugFromTitle)
+	}
+
+	if err := renderer.ValidateChromeArgs(cfg.Rendering.ChromeArgs); err != nil {
+		return nil, fmt.Errorf("invalid rendering.chrome_args: %w", err)
+	}
 
 	// Create logger
 	logLevel := "info"
@@ -82,35 +296,84 @@ func NewOrchestrator(opts OrchestratorOptions) (*Orchestrator, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid proxy configuration: %w", err)
 	}
+	userAgents, err := cfg.Stealth.ResolveUserAgents()
+	if err != nil {
+		return nil, err
+	}
 
 	// Create dependencies
+	selfHostedHosts := make(map[string]git.Platform, len(cfg.Git.SelfHostedHosts))
+	for host, platform := range cfg.Git.SelfHostedHosts {
+		selfHostedHosts[host] = git.Platform(strings.ToLower(platform))
+	}
+
 	deps, err := strategies.NewDependencies(strategies.DependencyOptions{
+		Logger: logger,
 		CommonOptions: domain.CommonOptions{
 			Verbose:  opts.Verbose,
 			DryRun:   opts.DryRun,
-			Force:    opts.Force || cfg.Output.Overwrite,
+			Force:    opts.force(cfg),
 			RenderJS: opts.RenderJS,
 			Limit:    opts.Limit,
 			Sync:     opts.Sync,
 			FullSync: opts.FullSync,
 			Prune:    opts.Prune,
 		},
-		Timeout:         cfg.Concurrency.Timeout,
-		EnableCache:     cfg.Cache.Enabled,
-		CacheTTL:        cfg.Cache.TTL,
-		CacheDir:        cacheDir,
-		UserAgent:       cfg.Stealth.UserAgent,
-		EnableRenderer:  cfg.Rendering.ForceJS || opts.RenderJS,
-		RendererTimeout: cfg.Rendering.JSTimeout,
-		Concurrency:     cfg.Concurrency.Workers,
-		ContentSelector: opts.ContentSelector,
-		ExcludeSelector: opts.ExcludeSelector,
-		OutputDir:       cfg.Output.Directory,
-		Flat:            cfg.Output.Flat,
-		JSONMetadata:    cfg.Output.JSONMetadata,
-		LLMConfig:       &cfg.LLM,
-		ProxyURL:        proxyURL,
-		CDPEndpoint:     cfg.Rendering.CDPEndpoint,
+		Fetcher:             opts.Fetcher,
+		Timeout:             cfg.Concurrency.Timeout,
+		MaxRetries:          cfg.Fetch.MaxRetries,
+		DetectAuthWalls:     cfg.Fetch.DetectAuthWalls,
+		EnableCache:         cfg.Cache.Enabled,
+		CacheTTL:            cfg.Cache.TTL,
+		CacheDir:            cacheDir,
+		UserAgent:           cfg.Stealth.UserAgent,
+		UserAgents:          userAgents,
+		EnableRenderer:      cfg.Rendering.ForceJS || opts.RenderJS,
+		RendererTimeout:     cfg.Rendering.JSTimeout,
+		Concurrency:         cfg.Concurrency.Workers,
+		ContentSelector:     opts.ContentSelector,
+		ExcludeSelector:     opts.ExcludeSelector,
+		StripFrontMatter:    opts.StripFrontMatter,
+		FrontMatterKeys:     opts.FrontMatterKeys,
+		NormalizeWhitespace: opts.NormalizeWhitespace,
+		ImageHandling:       imageHandling,
+		HeadingShift:        opts.HeadingShift,
+		PreserveAdmonitions: opts.PreserveAdmonitions,
+		OutputDir:           cfg.Output.Directory,
+		Flat:                cfg.Output.Flat,
+		JSONMetadata:        cfg.Output.JSONMetadata,
+		SiteBaseURL:         cfg.Output.SiteBaseURL,
+		LineEndings:         lineEndings,
+		OutputFormat:        outputFormat,
+		Chunks:              chunks,
+		HashAlgorithm:       hashAlgorithm,
+		OutputName:          opts.OutputName,
+		SlugFrom:            opts.SlugFrom,
+		LLMConfig:           &cfg.LLM,
+		ProxyURL:            proxyURL,
+		CDPEndpoint:         cfg.Rendering.CDPEndpoint,
+		ChromeArgs:          cfg.Rendering.ChromeArgs,
+		RenderDecisionTTL:   cfg.Rendering.RenderDecisionTTL,
+		TruncationRetries:   cfg.Rendering.TruncationRetries,
+		IncludeHidden:       cfg.Rendering.IncludeHidden,
+		MaxPagesPerHost:     opts.MaxPagesPerHost,
+		ForceContentType:    forceContentType,
+		PreferMarkdown:      opts.PreferMarkdown,
+
+		HostBreakerThreshold: opts.HostBreakerThreshold,
+		HostBreakerCooldown:  opts.HostBreakerCooldown,
+		CrawlDelay:           opts.CrawlDelay,
+		RequestJitter:        opts.RequestJitter,
+		RateLimit:            opts.RateLimit,
+		MaxRedirects:         maxRedirects(opts.MaxRedirects, cfg.Fetch.MaxRedirects),
+		NoCrossHostRedirects: opts.NoCrossHostRedirects,
+		MaxErrors:            opts.errorLimit(),
+		NoEnrich:             opts.NoEnrich,
+		NoSpaceCheck:         opts.NoSpaceCheck,
+		KeepTemp:             opts.KeepTemp,
+		GitSelfHostedHosts:   selfHostedHosts,
+
+		ContentSelectorStrict: opts.ContentSelectorStrict,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create dependencies: %w", err)
@@ -132,17 +395,79 @@ func NewOrchestrator(opts OrchestratorOptions) (*Orchestrator, error) {
 		validator:       recovery.NewValidator(nil),
 		planner:         recovery.NewPlanner(),
 		probeRunner:     recovery.NewProbeRunner(deps.Fetcher),
+		gitParser:       git.NewParserWithOptions(git.ParserOptions{SelfHostedHosts: selfHostedHosts}),
 	}, nil
 }
 
 // Run executes the documentation extraction for the given URL
 func (o *Orchestrator) Run(ctx context.Context, url string, opts OrchestratorOptions) error {
+	ctx, cancel := withDeadline(ctx, opts.Deadline)
+	defer cancel()
+	ctx, stop := o.deps.WithErrorBudget(ctx)
+	defer stop()
+
+	_, err := o.run(ctx, url, opts)
+	o.reportHostBreakers()
+	if err != nil && !stoppedEarly(err) {
+		return err
+	}
+	o.postProcessWritten(opts)
+	o.writeSitemap(opts)
+	o.closeChunks()
+	o.writeChecksums(opts)
+	return err
+}
+
+// withDeadline bounds ctx by deadline; a zero deadline leaves it unbounded.
+func withDeadline(ctx context.Context, deadline time.Duration) (context.Context, context.CancelFunc) {
+	if deadline <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, deadline, domain.ErrDeadlineExceeded)
+}
+
+// stopError returns the error for a run that ctx stopped early, at its
+// deadline or once its error budget was used up, and nil otherwise.
+func stopError(ctx context.Context, opts OrchestratorOptions) error {
+	cause := context.Cause(ctx)
+	switch {
+	case errors.Is(cause, domain.ErrDeadlineExceeded):
+		return fmt.Errorf("run truncated after %s: %w", opts.Deadline, domain.ErrDeadlineExceeded)
+	case errors.Is(cause, domain.ErrTooManyErrors) && opts.Strict:
+		return fmt.Errorf("run aborted at the first document error (strict mode): %w", domain.ErrTooManyErrors)
+	case errors.Is(cause, domain.ErrTooManyErrors):
+		return fmt.Errorf("run aborted after %d document errors: %w", opts.MaxErrors, domain.ErrTooManyErrors)
+	}
+	return nil
+}
+
+// errorLimit returns the number of document failures that abort the run,
+// or 0 for no limit.
+func (opts OrchestratorOptions) errorLimit() int {
+	if opts.Strict {
+		return 1
+	}
+	return opts.MaxErrors
+}
+
+// stoppedEarly reports whether err comes from stopError, so the documents
+// completed before the stop should still be post-processed.
+func stoppedEarly(err error) bool {
+	return errors.Is(err, domain.ErrDeadlineExceeded) || errors.Is(err, domain.ErrTooManyErrors)
+}
+
+// run performs one extraction without the whole-output post-processing that
+// Run and RunManifest apply once all documents are written.
+func (o *Orchestrator) run(ctx context.Context, url string, opts OrchestratorOptions) (*domain.StrategyResult, error) {
 	startTime := time.Now()
+	ctx = strategies.WithLabels(ctx, opts.Labels)
+	ctx = output.WithSubdir(ctx, opts.OutputSubdir)
+	ctx = output.WithForce(ctx, opts.force(o.config))
 
 	o.logger.Info().
 		Str("url", url).
 		Str("output", o.config.Output.Directory).
-		Int("concurrency", o.config.Concurrency.Workers).
+		Int("concurrency", o.workers(opts)).
 		Msg("Starting documentation extraction")
 
 	var strategyType StrategyType
@@ -153,16 +478,16 @@ func (o *Orchestrator) Run(ctx context.Context, url string, opts OrchestratorOpt
 			Msg("Using strategy override from manifest")
 
 		if !IsValidStrategy(strategyType) {
-			return fmt.Errorf("unknown strategy override: %s", opts.StrategyOverride)
+			return nil, fmt.Errorf("unknown strategy override: %s", opts.StrategyOverride)
 		}
 	} else {
-		strategyType = DetectStrategy(url)
+		strategyType = o.detectStrategy(url)
 		o.logger.Debug().
 			Str("strategy", string(strategyType)).
 			Msg("Detected strategy type")
 
 		if strategyType == StrategyUnknown {
-			return fmt.Errorf("unable to determine strategy for URL: %s", url)
+			return nil, fmt.Errorf("unable to determine strategy for URL: %s", url)
 		}
 	}
 
@@ -202,7 +527,7 @@ func (o *Orchestrator) Run(ctx context.Context, url string, opts OrchestratorOpt
 	// misconfigured factory). This is a setup error, not an extraction
 	// outcome, so it is surfaced directly rather than wrapped as a verdict.
 	if o.strategyFactory(strategyType, o.deps) == nil {
-		return fmt.Errorf("failed to create strategy for URL: %s", url)
+		return nil, fmt.Errorf("failed to create strategy for URL: %s", url)
 	}
 
 	// Phase 5: execute the strategy and, when the outcome is judged
@@ -216,50 +541,139 @@ func (o *Orchestrator) Run(ctx context.Context, url string, opts OrchestratorOpt
 	}
 
 	result, verdict, _ := o.runWithFallback(ctx, initial, opts)
+	if err := stopError(ctx, opts); err != nil {
+		o.reportFailures(result)
+		o.reportAuthWalls(result)
+		o.logger.Warn().Err(err).Msg("Run stopped early, keeping completed documents")
+		// The run is incomplete, so unseen pages must not be pruned.
+		o.finishRun(context.WithoutCancel(ctx), opts, false)
+		return result, err
+	}
 	if ctx.Err() != nil {
 		o.logger.Warn().Msg("Extraction cancelled")
-		return ctx.Err()
+		return result, ctx.Err()
 	}
 
 	switch v := verdict.(type) {
 	case recovery.VerdictOK:
 		// Continue to FlushMetadata, prune, SaveState, and success logging below.
 	case recovery.VerdictPropagate:
-		return v.Cause
+		return result, v.Cause
 	case recovery.VerdictRetryAlternative:
-		return recovery.NewOutcomeError(v, result)
+		return result, recovery.NewOutcomeError(v, result)
 	case recovery.VerdictHardFail:
-		return recovery.NewOutcomeError(v, result)
+		return result, recovery.NewOutcomeError(v, result)
 	default:
-		return recovery.NewOutcomeError(recovery.VerdictHardFail{
+		return result, recovery.NewOutcomeError(recovery.VerdictHardFail{
 			Reason: "unknown recovery verdict",
 			Cause:  domain.ErrInsufficientOutput,
 		}, result)
 	}
 
+	prune := opts.Prune
+	if reason := pruneBlocker(opts, result); reason != "" {
+		o.partialRun.Store(true)
+		if prune {
+			o.logger.Warn().Str("reason", reason).Msg("Not pruning removed pages: the run did not cover the whole source")
+			prune = false
+		}
+	}
+	o.finishRun(ctx, opts, prune)
+	o.reportFailures(result)
+	o.reportAuthWalls(result)
+
+	duration := time.Since(startTime)
+	o.logger.Info().
+		Dur("duration", duration).
+		Int("docs_written", result.Snapshot().DocsWritten).
+		Msg("Documentation extraction completed")
+
+	return result, nil
+}
+
+// maxReportedFailures caps the failed documents listed at the end of a run;
+// each failure is also logged when it happens.
+const maxReportedFailures = 20
+
+// reportFailures lists the documents that failed during a run that went on
+// without them, so they are not lost among the logs of a large crawl.
+func (o *Orchestrator) reportFailures(result *domain.StrategyResult) {
+	failures := result.Snapshot().Failures
+	if len(failures) == 0 {
+		return
+	}
+	for i, failure := range failures {
+		if i == maxReportedFailures {
+			o.logger.Warn().Int("more", len(failures)-i).Msg("More documents failed")
+			break
+		}
+		o.logger.Warn().Str("url", failure.URL).Str("error", failure.Error).Msg("Document failed")
+	}
+	o.logger.Warn().Int("failed", len(failures)).Msg("Some documents failed and were left out of the output")
+}
+
+// finishRun flushes metadata, optionally prunes deleted pages and saves the
+// incremental state once a run's documents are written.
+func (o *Orchestrator) finishRun(ctx context.Context, opts OrchestratorOptions, prune bool) {
 	if err := o.deps.FlushMetadata(); err != nil {
 		o.logger.Warn().Err(err).Msg("Failed to flush metadata")
 	}
 
-	if opts.Prune {
-		pruned, err := o.deps.PruneDeletedFiles(ctx)
-		if err != nil {
-			o.logger.Warn().Err(err).Msg("Failed to prune deleted files")
-		} else if pruned > 0 {
-			o.logger.Info().Int("pruned", pruned).Msg("Removed deleted pages")
+	if opts.DryRunState {
+		delta := o.StateDelta()
+		o.logger.Info().
+			Int("new", len(delta.New)).
+			Int("changed", len(delta.Changed)).
+			Int("unchanged", len(delta.Unchanged)).
+			Int("deleted", len(delta.Deleted)).
+			Msg("Dry run compared documents with the stored state")
+	}
+
+	// Dry runs never delete files or persist state.
+	if prune && !opts.DryRun {
+		o.pruneRemoved(ctx)
+	}
+
+	if !opts.DryRun {
+		if err := o.deps.SaveState(ctx); err != nil {
+			o.logger.Warn().Err(err).Msg("Failed to save state")
 		}
 	}
+}
 
-	if err := o.deps.SaveState(ctx); err != nil {
-		o.logger.Warn().Err(err).Msg("Failed to save state")
+// pruneRemoved deletes the output files of pages that were recorded in the
+// stored state but not seen in this run.
+func (o *Orchestrator) pruneRemoved(ctx context.Context) {
+	pruned, err := o.deps.PruneDeletedFiles(ctx)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to prune deleted files")
+	} else if pruned > 0 {
+		o.logger.Info().Int("pruned", pruned).Msg("Removed deleted pages")
 	}
+}
 
-	duration := time.Since(startTime)
-	o.logger.Info().
-		Dur("duration", duration).
-		Msg("Documentation extraction completed")
+// reportAuthWalls notes the pages skipped as login walls or paywall stubs
+// (--detect-auth-walls), which are neither written nor counted as failed.
+func (o *Orchestrator) reportAuthWalls(result *domain.StrategyResult) {
+	if n := result.Snapshot().AuthWalls; n > 0 {
+		o.logger.Warn().Int("pages", n).Msg("Skipped pages behind a login or paywall")
+	}
+}
 
-	return nil
+// pruneBlocker returns why a finished run may have missed pages that still
+// exist in its source, which must then not be pruned, or "" when the run
+// covered the whole source.
+func pruneBlocker(opts OrchestratorOptions, result *domain.StrategyResult) string {
+	switch {
+	case opts.Limit > 0:
+		return "page limit"
+	case opts.MaxPagesPerHost > 0:
+		return "per-host page cap"
+	case result != nil && result.DocsFailed > 0:
+		return "failed documents"
+	default:
+		return ""
+	}
 }
 
 // Close releases all resources held by the orchestrator
@@ -270,17 +684,42 @@ func (o *Orchestrator) Close() error {
 	return nil
 }
 
+// StateDelta compares the documents of the run with the stored incremental
+// state. It is empty when the run did not use sync state.
+func (o *Orchestrator) StateDelta() *state.Delta {
+	if o.deps == nil || o.deps.StateManager == nil {
+		return &state.Delta{}
+	}
+	return o.deps.StateManager.Delta()
+}
+
+// ManifestResults returns the outcome of each source of the last
+// RunManifest, in manifest order, including the counters its strategy
+// reported. It is nil before RunManifest has run.
+func (o *Orchestrator) ManifestResults() []ManifestResult {
+	return o.manifestResults
+}
+
 // GetStrategyName returns the detected strategy name for a URL
 func (o *Orchestrator) GetStrategyName(url string) string {
-	return string(DetectStrategy(url))
+	return string(o.detectStrategy(url))
 }
 
-// ValidateURL checks if the URL can be processed
-func (o *Orchestrator) ValidateURL(url string) error {
-	strategyType := DetectStrategy(url)
+// ValidateURL checks if the URL can be processed: a strategy must handle it
+// and pass the strategy's Validate hook. Hook failures are returned as a
+// *domain.StrategyError wrapping the strategy's *domain.ValidationError.
+func (o *Orchestrator) ValidateURL(ctx context.Context, url string) error {
+	strategyType := o.detectStrategy(url)
 	if strategyType == StrategyUnknown {
 		return fmt.Errorf("unsupported URL format: %s", url)
 	}
+	strategy := o.strategyFactory(strategyType, o.deps)
+	if strategy == nil {
+		return nil
+	}
+	if err := strategies.Validate(ctx, strategy, url); err != nil {
+		return domain.NewStrategyError(strategy.Name(), url, err)
+	}
 	return nil
 }
 
@@ -289,6 +728,54 @@ type ManifestResult struct {
 	Source   manifest.Source
 	Error    error
 	Duration time.Duration
+	// Skipped is set for sources disabled in the manifest.
+	Skipped bool
+	// Result holds the counters reported by the strategy that extracted the
+	// source; it is zero when the source failed before a strategy ran.
+	Result domain.StrategyResultSnapshot
+}
+
+// maxTotalWorkers caps the page workers of all manifest sources running at
+// once (parallel sources × workers per source), since they share one fetcher,
+// cache and rate limiter.
+const maxTotalWorkers = 32
+
+// defaultSourceConcurrency is the number of sources extracted in parallel
+// when neither the flag nor the manifest sets it.
+const defaultSourceConcurrency = 3
+
+// sourceConcurrency returns how many manifest sources run in parallel: the
+// --concurrency-sources value, else options.concurrency_sources, else the
+// default, reduced so the total worker count stays within maxTotalWorkers.
+// Sources with their own concurrency count at their largest value.
+func (o *Orchestrator) sourceConcurrency(manifestCfg *manifest.Config, baseOpts OrchestratorOptions) int {
+	workers := baseOpts.Config.Concurrency.Workers
+	if workers <= 0 {
+		workers = 5
+	}
+	for _, src := range manifestCfg.Sources {
+		if src.IsEnabled() && src.Concurrency > workers {
+			workers = src.Concurrency
+		}
+	}
+
+	sources := baseOpts.ConcurrencySources
+	if sources <= 0 {
+		sources = manifestCfg.Options.ConcurrencySources
+	}
+	if sources <= 0 {
+		sources = min(workers, defaultSourceConcurrency)
+	}
+
+	if limit := max(1, maxTotalWorkers/workers); sources > limit {
+		o.logger.Warn().
+			Int("requested", sources).
+			Int("allowed", limit).
+			Int("workers_per_source", workers).
+			Msg("Reducing source concurrency to cap total workers")
+		sources = limit
+	}
+	return sources
 }
 
 // RunManifest executes all sources defined in the manifest
@@ -296,8 +783,14 @@ func (o *Orchestrator) RunManifest(
 	ctx context.Context,
 	manifestCfg *manifest.Config,
 	baseOpts OrchestratorOptions,
-) error {
+) (runErr error) {
+	ctx, cancelDeadline := withDeadline(ctx, baseOpts.Deadline)
+	defer cancelDeadline()
+	ctx, stopBudget := o.deps.WithErrorBudget(ctx)
+	defer stopBudget()
+
 	startTime := time.Now()
+	defer func() { o.writeReport(baseOpts, manifestCfg, startTime, runErr) }()
 	totalSources := len(manifestCfg.Sources)
 
 	o.logger.Info().
@@ -306,6 +799,10 @@ func (o *Orchestrator) RunManifest(
 		Str("output", manifestCfg.Options.Output).
 		Msg("Starting manifest execution")
 
+	for _, warning := range manifestCfg.Warnings() {
+		o.logger.Warn().Msg("Manifest: " + warning)
+	}
+
 	if totalSources == 0 {
 		o.logger.Info().
 			Dur("total_duration", time.Since(startTime)).
@@ -313,16 +810,11 @@ func (o *Orchestrator) RunManifest(
 			Int("success", 0).
 			Int("failed", 0).
 			Msg("Manifest execution completed")
+		o.manifestResults = []ManifestResult{}
 		return nil
 	}
 
-	concurrency := baseOpts.Config.Concurrency.Workers
-	if concurrency <= 0 {
-		concurrency = 5
-	}
-	if concurrency > 3 {
-		concurrency = 3
-	}
+	concurrency := o.sourceConcurrency(manifestCfg, baseOpts)
 
 	results := make([]ManifestResult, totalSources)
 	var resultsMu sync.Mutex
@@ -343,9 +835,25 @@ func (o *Orchestrator) RunManifest(
 		index  int
 	}
 
-	sourcesWithIndex := make([]sourceWithIndex, totalSources)
+	sourcesWithIndex := make([]sourceWithIndex, 0, totalSources)
+	skippedCount := 0
 	for i, source := range manifestCfg.Sources {
-		sourcesWithIndex[i] = sourceWithIndex{source: source, index: i}
+		if !source.IsEnabled() {
+			results[i] = ManifestResult{Source: source, Skipped: true}
+			skippedCount++
+			o.logger.Info().
+				Int("source_idx", i).
+				Str("source_url", source.URL).
+				Msg("Source disabled, skipping")
+			continue
+		}
+		sourcesWithIndex = append(sourcesWithIndex, sourceWithIndex{source: source, index: i})
+	}
+	if skippedCount > 0 {
+		o.logger.Info().
+			Int("skipped", skippedCount).
+			Int("active", len(sourcesWithIndex)).
+			Msg("Skipping disabled sources")
 	}
 
 	errs := utils.ParallelForEach(cancelCtx, sourcesWithIndex, concurrency, func(ctx context.Context, item sourceWithIndex) error {
@@ -362,14 +870,16 @@ func (o *Orchestrator) RunManifest(
 
 		opts := o.buildSourceOptions(source, baseOpts)
 
-		err := o.Run(ctx, source.URL, opts)
+		result, err := o.run(ctx, source.URL, opts)
 		sourceDuration := time.Since(sourceStart)
+		snap := result.Snapshot()
 
 		resultsMu.Lock()
 		results[idx] = ManifestResult{
 			Source:   source,
 			Error:    err,
 			Duration: sourceDuration,
+			Result:   snap,
 		}
 		resultsMu.Unlock()
 
@@ -379,6 +889,8 @@ func (o *Orchestrator) RunManifest(
 				Int("source_idx", idx).
 				Str("source_url", source.URL).
 				Dur("duration", sourceDuration).
+				Int("docs_written", snap.DocsWritten).
+				Int("docs_failed", snap.DocsFailed).
 				Msg("Source extraction failed")
 
 			if !manifestCfg.Options.ContinueOnError {
@@ -403,12 +915,30 @@ func (o *Orchestrator) RunManifest(
 				Int("source_idx", idx).
 				Str("source_url", source.URL).
 				Dur("duration", sourceDuration).
+				Int("docs_written", snap.DocsWritten).
+				Int("docs_skipped", snap.DocsSkipped).
+				Int("docs_failed", snap.DocsFailed).
+				Int("auth_walls", snap.AuthWalls).
+				Int64("bytes_written", snap.BytesWritten).
 				Msg("Source extraction completed")
 		}
 
 		return nil
 	})
-
+	o.manifestResults = results
+
+	if err := stopError(ctx, baseOpts); err != nil {
+		o.postProcessWritten(baseOpts)
+		o.writeSitemap(baseOpts)
+		o.closeChunks()
+		o.writeChecksums(baseOpts)
+		o.reportHostBreakers()
+		o.logger.Warn().
+			Err(err).
+			Dur("total_duration", time.Since(startTime)).
+			Msg("Manifest stopped early, keeping completed documents")
+		return err
+	}
 	if ctx.Err() != nil {
 		o.logger.Warn().Msg("Manifest execution cancelled")
 		return ctx.Err()
@@ -423,29 +953,196 @@ func (o *Orchestrator) RunManifest(
 		firstError = err
 	}
 
+	// Prune first so chunks.jsonl drops the chunks of removed pages.
+	if baseOpts.Prune && !baseOpts.DryRun {
+		switch {
+		case firstError != nil:
+			o.logger.Warn().Msg("Not pruning removed pages: some sources failed")
+		case skippedCount > 0:
+			o.logger.Warn().Msg("Not pruning removed pages: some sources are disabled")
+		case o.partialRun.Load():
+			o.logger.Warn().Msg("Not pruning removed pages: some sources did not cover their whole source")
+		default:
+			o.pruneRemoved(ctx)
+			if err := o.deps.SaveState(ctx); err != nil {
+				o.logger.Warn().Err(err).Msg("Failed to save state")
+			}
+		}
+	}
+
+	o.postProcessWritten(baseOpts)
+	o.writeSitemap(baseOpts)
+	o.closeChunks()
+	o.writeChecksums(baseOpts)
+
 	duration := time.Since(startTime)
 	successCount := 0
+	docsWritten := 0
 	for _, r := range results {
-		if r.Error == nil {
+		if r.Error == nil && !r.Skipped {
 			successCount++
 		}
+		docsWritten += r.Result.DocsWritten
 	}
+	failedCount := totalSources - successCount - skippedCount
 
+	o.reportHostBreakers()
 	o.logger.Info().
 		Dur("total_duration", duration).
 		Int("total", totalSources).
 		Int("success", successCount).
-		Int("failed", totalSources-successCount).
+		Int("skipped", skippedCount).
+		Int("failed", failedCount).
+		Int("docs_written", docsWritten).
 		Msg("Manifest execution completed")
 
 	if firstError != nil {
 		return fmt.Errorf("manifest completed with %d/%d failures: %w",
-			totalSources-successCount, totalSources, firstError)
+			failedCount, totalSources, firstError)
 	}
 
 	return nil
 }
 
+// postProcessWritten applies the post-run rewrites to the documents written
+// so far and resets the writer's list of written files.
+func (o *Orchestrator) postProcessWritten(opts OrchestratorOptions) {
+	if o.deps == nil || o.deps.Writer == nil {
+		return
+	}
+	written := o.deps.Writer.TakeWritten()
+	if opts.DryRun {
+		return
+	}
+	o.stripCommonBlocks(written, opts)
+	o.rewriteLinks(written, opts)
+}
+
+// stripCommonBlocks removes boilerplate repeated across the written pages.
+// Runs that wrote too few pages per site are left untouched.
+func (o *Orchestrator) stripCommonBlocks(written []output.WrittenFile, opts OrchestratorOptions) {
+	if !opts.StripCommonBlocks || len(written) < 2 {
+		return
+	}
+
+	rewritten, err := output.StripCommonBlocks(written, opts.CommonThreshold)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to strip common blocks")
+	}
+	if rewritten > 0 {
+		o.logger.Info().
+			Int("documents", rewritten).
+			Msg("Removed repeated boilerplate blocks")
+	}
+}
+
+// rewriteLinks points links between the written pages at their local files
+// so the output can be browsed offline.
+func (o *Orchestrator) rewriteLinks(written []output.WrittenFile, opts OrchestratorOptions) {
+	if !opts.RewriteLinks || len(written) == 0 {
+		return
+	}
+
+	rewritten, err := output.RewriteLinks(written)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to rewrite links")
+	}
+	if rewritten > 0 {
+		o.logger.Info().
+			Int("documents", rewritten).
+			Msg("Rewrote links to local paths")
+	}
+}
+
+// reportHostBreakers logs the hosts whose circuit breaker opened during the
+// run, so fast-failed pages are not mistaken for missing content.
+func (o *Orchestrator) reportHostBreakers() {
+	if o.deps == nil {
+		return
+	}
+	trips := o.deps.HostBreaker.Trips()
+	if len(trips) == 0 {
+		return
+	}
+	total := 0
+	for _, n := range trips {
+		total += n
+	}
+	o.logger.Warn().
+		Strs("hosts", o.deps.HostBreaker.TrippedHosts()).
+		Int("trips", total).
+		Msg("Host circuit breaker opened; requests to these hosts failed fast during cooldown")
+}
+
+// writeSitemap emits sitemap.xml for the documents written during the run
+// when a site base URL is configured. Dry runs write nothing.
+func (o *Orchestrator) writeSitemap(opts OrchestratorOptions) {
+	if o.deps == nil || o.deps.Sitemap == nil || opts.DryRun {
+		return
+	}
+	if err := o.deps.FlushSitemap(); err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to write sitemap")
+		return
+	}
+	if n := o.deps.Sitemap.Count(); n > 0 {
+		o.logger.Info().Int("urls", n).Msg("Wrote " + output.SitemapFilename)
+	}
+}
+
+// closeChunks finishes chunks.jsonl when chunking is enabled and reports
+// how many chunks the run wrote.
+func (o *Orchestrator) closeChunks() {
+	if o.deps == nil || o.deps.Chunks == nil {
+		return
+	}
+	if err := o.deps.Chunks.Close(); err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to write " + output.ChunksFilename)
+		return
+	}
+	if chunks, documents := o.deps.Chunks.Count(); chunks > 0 {
+		o.logger.Info().Int("chunks", chunks).Int("documents", documents).Msg("Wrote " + output.ChunksFilename)
+	}
+}
+
+// writeChecksums writes checksums.txt for the documents of the run when
+// enabled. It runs last, once every document, including the pruned and
+// post-processed ones, is final.
+func (o *Orchestrator) writeChecksums(opts OrchestratorOptions) {
+	if o.deps == nil || o.deps.Writer == nil || !o.config.Output.Checksums || opts.DryRun {
+		return
+	}
+	dir := o.deps.Writer.BaseDir()
+	if _, err := os.Stat(dir); err != nil {
+		// Nothing was written.
+		return
+	}
+	// The algorithm was validated with the configuration.
+	algo, _ := converter.ParseHashAlgorithm(o.config.Output.HashAlgorithm)
+	n, err := output.WriteChecksums(dir, o.deps.Writer.Files(), algo)
+	if err != nil {
+		o.logger.Warn().Err(err).Msg("Failed to write " + output.ChecksumsFilename)
+		return
+	}
+	o.logger.Info().Int("files", n).Msg("Wrote " + output.ChecksumsFilename)
+}
+
+// force reports whether existing files are overwritten: ForceOverride when
+// set, else Force or output.overwrite.
+func (opts OrchestratorOptions) force(cfg *config.Config) bool {
+	if opts.ForceOverride != nil {
+		return *opts.ForceOverride
+	}
+	return opts.Force || cfg.Output.Overwrite
+}
+
+// workers returns the number of page workers an extraction with opts uses.
+func (o *Orchestrator) workers(opts OrchestratorOptions) int {
+	if opts.Workers > 0 {
+		return opts.Workers
+	}
+	return o.config.Concurrency.Workers
+}
+
 func (o *Orchestrator) buildSourceOptions(source manifest.Source, baseOpts OrchestratorOptions) OrchestratorOptions {
 	opts := baseOpts
 
@@ -463,6 +1160,9 @@ func (o *Orchestrator) buildSourceOptions(source manifest.Source, baseOpts Orche
 	if len(source.Exclude) > 0 {
 		opts.ExcludePatterns = append(opts.ExcludePatterns, source.Exclude...)
 	}
+	if len(source.Include) > 0 {
+		opts.IncludePatterns = append(opts.IncludePatterns, source.Include...)
+	}
 
 	if source.RenderJS != nil {
 		opts.RenderJS = *source.RenderJS
@@ -472,6 +1172,27 @@ func (o *Orchestrator) buildSourceOptions(source manifest.Source, baseOpts Orche
 		opts.Limit = source.Limit
 	}
 
+	if source.Concurrency > 0 {
+		opts.Workers = source.Concurrency
+	}
+	if source.Force != nil {
+		opts.ForceOverride = source.Force
+	}
+
+	opts.OutputSubdir = source.Output
+
+	// A source never sees the pages of the others; RunManifest prunes once
+	// every source has run.
+	opts.Prune = false
+
+	if len(source.Tags) > 0 {
+		opts.Labels = maps.Clone(opts.Labels)
+		if opts.Labels == nil {
+			opts.Labels = make(map[string]string, len(source.Tags))
+		}
+		maps.Copy(opts.Labels, source.Tags)
+	}
+
 	if source.MaxDepth > 0 {
 		o.logger.Debug().
 			Int("max_depth", source.MaxDepth).
@@ -481,3 +1202,16 @@ func (o *Orchestrator) buildSourceOptions(source manifest.Source, baseOpts Orche
 
 	return opts
 }
+
+// maxRedirects resolves the redirect limit: the option when set, else the
+// config value, else fetcher.DefaultMaxRedirects. Negative values follow
+// no redirects.
+func maxRedirects(opt, cfg int) int {
+	if opt != 0 {
+		return opt
+	}
+	if cfg != 0 {
+		return cfg
+	}
+	return fetcher.DefaultMaxRedirects
+}
@@ -3,11 +3,16 @@ package app
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/quantmind-br/repodocs/internal/config"
 	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/fetcher"
+	"github.com/quantmind-br/repodocs/internal/manifest"
+	"github.com/quantmind-br/repodocs/internal/output"
 	"github.com/quantmind-br/repodocs/internal/strategies"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -127,6 +132,161 @@ func TestOrchestrator_Run_ContextCancellation(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// TestOrchestrator_Run_Deadline tests that a run stops at its deadline and
+// reports a truncated run rather than a cancellation
+func TestOrchestrator_Run_Deadline(t *testing.T) {
+	cfg := &config.Config{
+		Cache: config.CacheConfig{
+			Enabled: false,
+		},
+		Concurrency: config.ConcurrencyConfig{
+			Timeout: 10 * time.Second,
+			Workers: 1,
+		},
+		Output: config.OutputConfig{
+			Directory: t.TempDir(),
+		},
+		Logging: config.LoggingConfig{
+			Level:  "error",
+			Format: "pretty",
+		},
+	}
+
+	mockFactory := func(st StrategyType, deps *strategies.Dependencies) strategies.Strategy {
+		return &mockCancelStrategy{name: string(st)}
+	}
+
+	opts := OrchestratorOptions{
+		Config:          cfg,
+		StrategyFactory: mockFactory,
+		Deadline:        50 * time.Millisecond,
+	}
+	orch, err := NewOrchestrator(opts)
+	require.NoError(t, err)
+	defer orch.Close()
+
+	start := time.Now()
+	err = orch.Run(context.Background(), "https://github.com/user/repo", opts)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrDeadlineExceeded)
+	assert.NotErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	manifestCfg := &manifest.Config{
+		Sources: []manifest.Source{
+			{URL: "https://github.com/user/repo1"},
+			{URL: "https://github.com/user/repo2"},
+		},
+		Options: manifest.Options{ContinueOnError: true},
+	}
+	err = orch.RunManifest(context.Background(), manifestCfg, opts)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrDeadlineExceeded)
+}
+
+// TestNewOrchestrator_NegativeDeadline tests deadline validation
+func TestNewOrchestrator_NegativeDeadline(t *testing.T) {
+	_, err := NewOrchestrator(OrchestratorOptions{
+		Config:   &config.Config{},
+		Deadline: -time.Second,
+	})
+	assert.Error(t, err)
+}
+
+// TestOrchestrator_Run_MaxErrors tests that a run aborts once its document
+// error budget is used up and reports the aborted run
+func TestOrchestrator_Run_MaxErrors(t *testing.T) {
+	cfg := &config.Config{
+		Cache: config.CacheConfig{
+			Enabled: false,
+		},
+		Concurrency: config.ConcurrencyConfig{
+			Timeout: 10 * time.Second,
+			Workers: 1,
+		},
+		Output: config.OutputConfig{
+			Directory: t.TempDir(),
+		},
+		Logging: config.LoggingConfig{
+			Level:  "error",
+			Format: "pretty",
+		},
+	}
+
+	failing := &mockFailingStrategy{}
+	mockFactory := func(st StrategyType, deps *strategies.Dependencies) strategies.Strategy {
+		failing.name = string(st)
+		failing.deps = deps
+		return failing
+	}
+
+	opts := OrchestratorOptions{
+		Config:          cfg,
+		StrategyFactory: mockFactory,
+		MaxErrors:       3,
+	}
+	orch, err := NewOrchestrator(opts)
+	require.NoError(t, err)
+	defer orch.Close()
+
+	err = orch.Run(context.Background(), "https://github.com/user/repo", opts)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrTooManyErrors)
+	assert.Contains(t, err.Error(), "3 document errors")
+	assert.Equal(t, 3, failing.failed, "skipped documents do not count against the budget")
+
+	manifestCfg := &manifest.Config{
+		Sources: []manifest.Source{
+			{URL: "https://github.com/user/repo1"},
+			{URL: "https://github.com/user/repo2"},
+		},
+		Options: manifest.Options{ContinueOnError: true},
+	}
+	err = orch.RunManifest(context.Background(), manifestCfg, opts)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrTooManyErrors)
+}
+
+// TestOrchestrator_Run_Strict tests that strict mode aborts the run at the
+// first document failure
+func TestOrchestrator_Run_Strict(t *testing.T) {
+	cfg := &config.Config{
+		Cache:       config.CacheConfig{Enabled: false},
+		Concurrency: config.ConcurrencyConfig{Timeout: 10 * time.Second, Workers: 1},
+		Output:      config.OutputConfig{Directory: t.TempDir()},
+		Logging:     config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	failing := &mockFailingStrategy{}
+	opts := OrchestratorOptions{
+		Config: cfg,
+		StrategyFactory: func(st StrategyType, deps *strategies.Dependencies) strategies.Strategy {
+			failing.name = string(st)
+			failing.deps = deps
+			return failing
+		},
+		Strict: true,
+	}
+	orch, err := NewOrchestrator(opts)
+	require.NoError(t, err)
+	defer orch.Close()
+
+	err = orch.Run(context.Background(), "https://github.com/user/repo", opts)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrTooManyErrors)
+	assert.Contains(t, err.Error(), "strict mode")
+	assert.Equal(t, 1, failing.failed)
+}
+
+// TestNewOrchestrator_NegativeMaxErrors tests max errors validation
+func TestNewOrchestrator_NegativeMaxErrors(t *testing.T) {
+	_, err := NewOrchestrator(OrchestratorOptions{
+		Config:    &config.Config{},
+		MaxErrors: -1,
+	})
+	assert.Error(t, err)
+}
+
 // TestOrchestrator_Run_VerboseLogging tests verbose logging option
 func TestOrchestrator_Run_VerboseLogging(t *testing.T) {
 	cfg := &config.Config{
@@ -525,6 +685,34 @@ func (m *mockCancelStrategy) Execute(ctx context.Context, url string, opts strat
 	return result, ctx.Err()
 }
 
+// mockFailingStrategy skips and fails documents until its context is
+// cancelled.
+type mockFailingStrategy struct {
+	name   string
+	deps   *strategies.Dependencies
+	failed int
+}
+
+func (m *mockFailingStrategy) Name() string {
+	return m.name
+}
+
+func (m *mockFailingStrategy) CanHandle(url string) bool {
+	return true
+}
+
+func (m *mockFailingStrategy) Execute(ctx context.Context, url string, opts strategies.Options) (*domain.StrategyResult, error) {
+	result := m.deps.NewResult(m.name, url)
+	m.failed = 0
+	for i := 0; i < 100 && ctx.Err() == nil; i++ {
+		result.IncSkipped()
+		result.IncFailed()
+		m.failed++
+	}
+	result.Finish()
+	return result, ctx.Err()
+}
+
 type mockDryRunStrategy struct {
 	name string
 }
@@ -630,3 +818,293 @@ func TestOrchestrator_Run_EmptyOutcomeRegression(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "0 documents")
 }
+
+func TestNewOrchestrator_InvalidChromeArgs(t *testing.T) {
+	cfg := &config.Config{
+		Output:    config.OutputConfig{Directory: t.TempDir()},
+		Logging:   config.LoggingConfig{Level: "error", Format: "pretty"},
+		Rendering: config.RenderingConfig{ChromeArgs: []string{"--remote-debugging-port=9222"}},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rendering.chrome_args")
+}
+
+func TestOrchestrator_SourceConcurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		workers  int
+		flag     int
+		manifest int
+		want     int
+	}{
+		{name: "default", workers: 5, want: 3},
+		{name: "default below three workers", workers: 2, want: 2},
+		{name: "manifest option", workers: 5, manifest: 4, want: 4},
+		{name: "flag overrides manifest", workers: 5, flag: 2, manifest: 4, want: 2},
+		{name: "capped by total workers", workers: 8, flag: 10, want: 4},
+		{name: "at least one source", workers: 64, flag: 2, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Output:      config.OutputConfig{Directory: t.TempDir()},
+				Logging:     config.LoggingConfig{Level: "error", Format: "pretty"},
+				Concurrency: config.ConcurrencyConfig{Workers: tt.workers},
+			}
+			opts := OrchestratorOptions{Config: cfg, ConcurrencySources: tt.flag}
+			orch, err := NewOrchestrator(opts)
+			require.NoError(t, err)
+			defer orch.Close()
+
+			manifestCfg := &manifest.Config{Options: manifest.Options{ConcurrencySources: tt.manifest}}
+			assert.Equal(t, tt.want, orch.sourceConcurrency(manifestCfg, opts))
+		})
+	}
+}
+
+func TestNewOrchestrator_NegativeConcurrencySources(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir()},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg, ConcurrencySources: -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "source concurrency")
+}
+
+func TestNewOrchestrator_PreserveTreeRejectsFlat(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir(), Flat: true},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg, PreserveTree: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--nofolders")
+}
+
+func TestNewOrchestrator_InvalidForceContentType(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir()},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg, ForceContentType: "pdf"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid force content type")
+}
+
+func TestNewOrchestrator_InvalidImages(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir()},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg, Images: "blur"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid image handling")
+}
+
+func TestNewOrchestrator_NegativeHostBreaker(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir()},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg, HostBreakerThreshold: -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "host breaker")
+}
+
+func TestNewOrchestrator_NegativeRateLimit(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir()},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg, RateLimit: -1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limit")
+}
+
+func TestNewOrchestrator_DryRunStateRequiresSync(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir()},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg, DryRunState: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dry-run-state")
+
+	orch, err := NewOrchestrator(OrchestratorOptions{
+		CommonOptions: domain.CommonOptions{DryRun: true, Sync: true},
+		Config:        cfg,
+		DryRunState:   true,
+	})
+	require.NoError(t, err)
+	defer orch.Close()
+	assert.Zero(t, orch.StateDelta().Total())
+}
+
+// TestOrchestrator_BuildSourceOptions_Tags tests that source tags are merged
+// over the run labels without modifying them
+func TestOrchestrator_BuildSourceOptions_Tags(t *testing.T) {
+	o := &Orchestrator{}
+	base := OrchestratorOptions{Labels: map[string]string{"team": "platform", "product": "docs"}}
+
+	opts := o.buildSourceOptions(manifest.Source{URL: "https://example.com", Tags: map[string]string{"product": "billing"}}, base)
+	assert.Equal(t, map[string]string{"team": "platform", "product": "billing"}, opts.Labels)
+	assert.Equal(t, "docs", base.Labels["product"])
+
+	opts = o.buildSourceOptions(manifest.Source{URL: "https://example.com", Tags: map[string]string{"product": "billing"}}, OrchestratorOptions{})
+	assert.Equal(t, map[string]string{"product": "billing"}, opts.Labels)
+
+	opts = o.buildSourceOptions(manifest.Source{URL: "https://example.com"}, base)
+	assert.Equal(t, base.Labels, opts.Labels)
+}
+
+// TestOrchestrator_BuildSourceOptions_Output tests that a source output
+// directory becomes the subdirectory its documents are written to
+func TestOrchestrator_BuildSourceOptions_Output(t *testing.T) {
+	o := &Orchestrator{}
+	opts := o.buildSourceOptions(manifest.Source{URL: "https://example.com/v2/", Output: "v2"}, OrchestratorOptions{})
+	assert.Equal(t, "v2", opts.OutputSubdir)
+
+	opts = o.buildSourceOptions(manifest.Source{URL: "https://example.com"}, OrchestratorOptions{OutputSubdir: "stale"})
+	assert.Empty(t, opts.OutputSubdir)
+}
+
+// TestOrchestrator_BuildSourceOptions_IncludeExclude tests that a source's
+// include and exclude globs are added to the run's
+func TestOrchestrator_BuildSourceOptions_IncludeExclude(t *testing.T) {
+	o := &Orchestrator{config: config.Default()}
+	base := OrchestratorOptions{IncludePatterns: []string{"README.md"}, ExcludePatterns: []string{"*.txt"}}
+
+	opts := o.buildSourceOptions(manifest.Source{
+		URL:     "https://github.com/org/repo",
+		Include: []string{"docs/**"},
+		Exclude: []string{"docs/archive/**"},
+	}, base)
+	assert.Equal(t, []string{"README.md", "docs/**"}, opts.IncludePatterns)
+	assert.Equal(t, []string{"*.txt", "docs/archive/**"}, opts.ExcludePatterns)
+}
+
+// TestOrchestrator_BuildSourceOptions_ConcurrencyAndForce tests that a
+// source's concurrency and force override the run's values only when set
+func TestOrchestrator_BuildSourceOptions_ConcurrencyAndForce(t *testing.T) {
+	o := &Orchestrator{config: config.Default()}
+	// A global --force sets both the option and output.overwrite.
+	o.config.Output.Overwrite = true
+	base := OrchestratorOptions{CommonOptions: domain.CommonOptions{Force: true}}
+
+	off := false
+	opts := o.buildSourceOptions(manifest.Source{URL: "https://example.com", Concurrency: 2, Force: &off}, base)
+	assert.Equal(t, 2, opts.Workers)
+	assert.Equal(t, 2, o.workers(opts))
+	assert.False(t, opts.force(o.config), "force: false wins over a global --force")
+
+	opts = o.buildSourceOptions(manifest.Source{URL: "https://example.com"}, base)
+	assert.Zero(t, opts.Workers)
+	assert.Equal(t, o.config.Concurrency.Workers, o.workers(opts))
+	assert.True(t, opts.force(o.config))
+
+	on := true
+	o.config.Output.Overwrite = false
+	opts = o.buildSourceOptions(manifest.Source{URL: "https://example.com", Force: &on}, OrchestratorOptions{})
+	assert.True(t, opts.force(o.config))
+}
+
+// TestPruneBlocker tests that runs which may have missed pages of their
+// source are not pruned, and that manifest sources never prune on their own
+func TestPruneBlocker(t *testing.T) {
+	assert.Empty(t, pruneBlocker(OrchestratorOptions{}, &domain.StrategyResult{DocsWritten: 3}))
+	assert.Empty(t, pruneBlocker(OrchestratorOptions{}, nil))
+	assert.Equal(t, "page limit", pruneBlocker(OrchestratorOptions{CommonOptions: domain.CommonOptions{Limit: 5}}, nil))
+	assert.Equal(t, "per-host page cap", pruneBlocker(OrchestratorOptions{MaxPagesPerHost: 10}, nil))
+	assert.Equal(t, "failed documents", pruneBlocker(OrchestratorOptions{}, &domain.StrategyResult{DocsFailed: 1}))
+
+	o := &Orchestrator{}
+	base := OrchestratorOptions{CommonOptions: domain.CommonOptions{Prune: true, Sync: true}}
+	opts := o.buildSourceOptions(manifest.Source{URL: "https://example.com"}, base)
+	assert.False(t, opts.Prune)
+	assert.True(t, opts.Sync)
+}
+
+// mockWriterStrategy writes one document through the shared writer.
+type mockWriterStrategy struct {
+	deps *strategies.Dependencies
+}
+
+func (m *mockWriterStrategy) Name() string          { return "crawler" }
+func (m *mockWriterStrategy) CanHandle(string) bool { return true }
+func (m *mockWriterStrategy) Execute(ctx context.Context, url string, opts strategies.Options) (*domain.StrategyResult, error) {
+	result := domain.NewStrategyResult(m.Name(), url)
+	if !opts.DryRun {
+		doc := &domain.Document{URL: url, Title: "Page", Content: "# Page\n\nHello"}
+		if err := m.deps.Writer.Write(ctx, doc); err != nil {
+			return result, err
+		}
+	}
+	result.IncWritten()
+	result.Finish()
+	return result, nil
+}
+
+func TestOrchestrator_Run_WritesChecksums(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		cfg := config.Default()
+		cfg.Cache.Enabled = false
+		cfg.Logging.Level = "error"
+		cfg.Output.Directory = t.TempDir()
+		cfg.Output.Checksums = true
+
+		orch, err := NewOrchestrator(OrchestratorOptions{
+			Config:        cfg,
+			CommonOptions: domain.CommonOptions{DryRun: dryRun},
+			StrategyFactory: func(st StrategyType, deps *strategies.Dependencies) strategies.Strategy {
+				return &mockWriterStrategy{deps: deps}
+			},
+		})
+		require.NoError(t, err)
+
+		err = orch.Run(context.Background(), "https://example.com/docs/page", OrchestratorOptions{
+			CommonOptions: domain.CommonOptions{DryRun: dryRun},
+		})
+		require.NoError(t, err)
+		require.NoError(t, orch.Close())
+
+		_, err = os.Stat(filepath.Join(cfg.Output.Directory, output.ChecksumsFilename))
+		if dryRun {
+			assert.True(t, os.IsNotExist(err), "dry runs write no checksums")
+			continue
+		}
+		require.NoError(t, err)
+		report, err := output.VerifyChecksums(cfg.Output.Directory)
+		require.NoError(t, err)
+		assert.True(t, report.OK())
+		assert.Positive(t, report.Checked)
+	}
+}
+
+func TestNewOrchestrator_InvalidHeadingShift(t *testing.T) {
+	cfg := &config.Config{
+		Output:  config.OutputConfig{Directory: t.TempDir()},
+		Logging: config.LoggingConfig{Level: "error", Format: "pretty"},
+	}
+
+	_, err := NewOrchestrator(OrchestratorOptions{Config: cfg, HeadingShift: 7})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid heading shift")
+}
+
+func TestMaxRedirects(t *testing.T) {
+	assert.Equal(t, 3, maxRedirects(3, 7))
+	assert.Equal(t, -1, maxRedirects(-1, 7))
+	assert.Equal(t, 7, maxRedirects(0, 7))
+	assert.Equal(t, fetcher.DefaultMaxRedirects, maxRedirects(0, 0))
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/strategies/git"
+)
+
+// ProbeResult describes a cheap pre-flight check of a URL: the strategy that
+// would handle it and the response to a single HEAD request.
+type ProbeResult struct {
+	URL      string
+	Strategy StrategyType
+	// Git holds the parsed repository details for git URLs.
+	Git *git.GitURLInfo
+
+	// Target is the URL the HEAD request was sent to; empty when no request
+	// was made (e.g. SSH git URLs).
+	Target      string
+	StatusCode  int
+	ContentType string
+	Server      string
+	Location    string
+	Duration    time.Duration
+	// RequestErr is set when the HEAD request itself failed.
+	RequestErr error
+}
+
+// Probe validates rawURL, reports the strategy that would handle it and sends
+// one HEAD request to check reachability. Redirects are reported, not
+// followed, so at most one request is made.
+func (o *Orchestrator) Probe(ctx context.Context, rawURL string) (*ProbeResult, error) {
+	if err := o.ValidateURL(ctx, rawURL); err != nil {
+		return nil, err
+	}
+
+	result := &ProbeResult{URL: rawURL, Strategy: o.detectStrategy(rawURL)}
+	target := rawURL
+	if result.Strategy == StrategyGit {
+		parser := o.gitParser
+		if parser == nil {
+			parser = git.NewParser()
+		}
+		if info, err := parser.ParseURLWithPath(rawURL); err == nil {
+			result.Git = info
+			target = info.RepoURL
+		}
+	}
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		return result, nil
+	}
+	result.Target = target
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if ua := o.config.Stealth.UserAgent; ua != "" {
+		req.Header.Set("User-Agent", ua)
+	}
+
+	// The fetcher's transport always issues GETs with retries, so the probe
+	// uses a plain transport routed through the same proxy.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL, err := o.config.Proxy.Resolve(); err == nil && proxyURL != "" {
+		if u, err := url.Parse(proxyURL); err == nil {
+			transport.Proxy = http.ProxyURL(u)
+		}
+	}
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   o.config.Concurrency.Timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.RequestErr = err
+		return result, nil
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	result.StatusCode = resp.StatusCode
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.Server = resp.Header.Get("Server")
+	result.Location = resp.Header.Get("Location")
+	return result, nil
+}
+
+// Format writes a concise diagnostic report of the probe to w.
+func (r *ProbeResult) Format(w io.Writer) {
+	fmt.Fprintf(w, "URL:          %s\n", r.URL)
+	fmt.Fprintf(w, "Strategy:     %s\n", r.Strategy)
+	if r.Git != nil {
+		branch := r.Git.Branch
+		if r.Git.Ref != "" && r.Git.RefType != git.RefBranch {
+			branch = fmt.Sprintf("%s (%s)", r.Git.Ref, r.Git.RefType)
+		}
+		if branch == "" {
+			branch = "default (detected at extraction)"
+		}
+		fmt.Fprintf(w, "Platform:     %s\n", r.Git.Platform)
+		fmt.Fprintf(w, "Repository:   %s\n", r.Git.RepoURL)
+		fmt.Fprintf(w, "Branch:       %s\n", branch)
+		if r.Git.SubPath != "" {
+			fmt.Fprintf(w, "Path:         %s\n", r.Git.SubPath)
+		}
+	}
+
+	switch {
+	case r.Target == "":
+		fmt.Fprintln(w, "Reachability: not checked (non-HTTP URL)")
+	case r.RequestErr != nil:
+		fmt.Fprintf(w, "Reachability: FAILED (%v)\n", r.RequestErr)
+	default:
+		fmt.Fprintf(w, "Status:       %d %s (%s)\n", r.StatusCode, http.StatusText(r.StatusCode), r.Duration.Round(time.Millisecond))
+		if r.Location != "" {
+			fmt.Fprintf(w, "Redirects to: %s\n", r.Location)
+		}
+		if r.ContentType != "" {
+			fmt.Fprintf(w, "Content-Type: %s\n", r.ContentType)
+		}
+		if r.Server != "" {
+			fmt.Fprintf(w, "Server:       %s\n", r.Server)
+		}
+	}
+}
+
+// OK reports whether the URL was reachable, or could not be checked over
+// HTTP. Redirects count as reachable; servers that reject HEAD (405) too.
+func (r *ProbeResult) OK() bool {
+	if r.Target == "" {
+		return true
+	}
+	if r.RequestErr != nil {
+		return false
+	}
+	return r.StatusCode < 400 || r.StatusCode == http.StatusMethodNotAllowed
+}
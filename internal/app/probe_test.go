@@ -0,0 +1,112 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/config"
+	"github.com/quantmind-br/repodocs/internal/strategies/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProbeOrchestrator(t *testing.T) *Orchestrator {
+	t.Helper()
+	cfg := &config.Config{
+		Output:      config.OutputConfig{Directory: t.TempDir()},
+		Logging:     config.LoggingConfig{Level: "error", Format: "pretty"},
+		Concurrency: config.ConcurrencyConfig{Timeout: 5 * time.Second},
+	}
+	orch, err := NewOrchestrator(OrchestratorOptions{Config: cfg})
+	require.NoError(t, err)
+	t.Cleanup(func() { orch.Close() })
+	return orch
+}
+
+func TestOrchestrator_Probe(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, http.MethodHead, r.Method)
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/docs", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Server", "docs-server")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	orch := newProbeOrchestrator(t)
+
+	result, err := orch.Probe(context.Background(), server.URL+"/docs")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyCrawler, result.Strategy)
+	assert.Equal(t, http.StatusOK, result.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", result.ContentType)
+	assert.Equal(t, "docs-server", result.Server)
+	assert.True(t, result.OK())
+
+	result, err = orch.Probe(context.Background(), server.URL+"/old")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, result.StatusCode)
+	assert.Equal(t, "/docs", result.Location)
+	assert.Equal(t, int32(2), requests.Load(), "one request per probe; redirects are reported, not followed")
+
+	var buf bytes.Buffer
+	result.Format(&buf)
+	assert.Contains(t, buf.String(), "Redirects to: /docs")
+}
+
+func TestOrchestrator_Probe_Git(t *testing.T) {
+	orch := newProbeOrchestrator(t)
+
+	result, err := orch.Probe(context.Background(), "git@github.com:owner/repo.git")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyGit, result.Strategy)
+	assert.Empty(t, result.Target, "SSH URLs are not requested")
+	assert.True(t, result.OK())
+
+	var buf bytes.Buffer
+	result.Format(&buf)
+	assert.Contains(t, buf.String(), "Reachability: not checked")
+}
+
+func TestProbeResult_Format_GitRef(t *testing.T) {
+	result := &ProbeResult{
+		URL:      "https://github.com/owner/repo/tree/v2.3.0/docs",
+		Strategy: StrategyGit,
+		Git: &git.GitURLInfo{
+			Platform: git.PlatformGitHub,
+			RepoURL:  "https://github.com/owner/repo",
+			Ref:      "v2.3.0",
+			RefType:  git.RefTag,
+			SubPath:  "docs",
+		},
+	}
+
+	var buf bytes.Buffer
+	result.Format(&buf)
+	assert.Contains(t, buf.String(), "Branch:       v2.3.0 (tag)")
+}
+
+func TestOrchestrator_Probe_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	result, err := newProbeOrchestrator(t).Probe(context.Background(), server.URL+"/docs")
+	require.NoError(t, err)
+	assert.Error(t, result.RequestErr)
+	assert.False(t, result.OK())
+}
+
+func TestOrchestrator_Probe_InvalidURL(t *testing.T) {
+	_, err := newProbeOrchestrator(t).Probe(context.Background(), "not a url")
+	assert.Error(t, err)
+}
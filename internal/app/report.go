@@ -0,0 +1,171 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/manifest"
+)
+
+// Batch report statuses (ManifestReport.Status).
+const (
+	// ReportSuccess means every enabled source succeeded.
+	ReportSuccess = "success"
+	// ReportPartial means some sources failed and continue_on_error kept the
+	// run going, so the others were extracted.
+	ReportPartial = "partial"
+	// ReportFailed means the run stopped at a failure or was interrupted,
+	// or no source succeeded.
+	ReportFailed = "failed"
+)
+
+// Source report statuses (SourceReport.Status).
+const (
+	SourceSucceeded = "success"
+	SourceFailed    = "failed"
+	SourceSkipped   = "skipped"
+	// SourceNotRun marks sources that never started because the run
+	// stopped first.
+	SourceNotRun = "not_run"
+)
+
+// ManifestReport is the machine-readable summary of a manifest or URL list
+// run, written to OrchestratorOptions.ReportPath.
+type ManifestReport struct {
+	Status          string         `json:"status"`
+	ContinueOnError bool           `json:"continue_on_error"`
+	StartedAt       time.Time      `json:"started_at"`
+	DurationMS      int64          `json:"duration_ms"`
+	Error           string         `json:"error,omitempty"`
+	Totals          ReportTotals   `json:"totals"`
+	Sources         []SourceReport `json:"sources"`
+}
+
+// ReportTotals aggregates the sources of a ManifestReport.
+type ReportTotals struct {
+	Sources      int   `json:"sources"`
+	Succeeded    int   `json:"succeeded"`
+	Failed       int   `json:"failed"`
+	Skipped      int   `json:"skipped"`
+	NotRun       int   `json:"not_run"`
+	DocsWritten  int   `json:"docs_written"`
+	DocsSkipped  int   `json:"docs_skipped"`
+	DocsFailed   int   `json:"docs_failed"`
+	BytesWritten int64 `json:"bytes_written"`
+}
+
+// SourceReport is the outcome of one manifest source.
+type SourceReport struct {
+	URL          string `json:"url"`
+	Strategy     string `json:"strategy,omitempty"`
+	Status       string `json:"status"`
+	DocsWritten  int    `json:"docs_written"`
+	DocsSkipped  int    `json:"docs_skipped"`
+	DocsFailed   int    `json:"docs_failed"`
+	BytesWritten int64  `json:"bytes_written"`
+	DurationMS   int64  `json:"duration_ms"`
+	Error        string `json:"error,omitempty"`
+	// Failures lists the documents of the source that failed, with why.
+	Failures []domain.DocumentFailure `json:"failures,omitempty"`
+}
+
+// BuildManifestReport summarizes the results of a manifest run over
+// manifestCfg's sources. runErr is the error RunManifest returned.
+func BuildManifestReport(manifestCfg *manifest.Config, results []ManifestResult, startedAt time.Time, runErr error) ManifestReport {
+	report := ManifestReport{
+		ContinueOnError: manifestCfg.Options.ContinueOnError,
+		StartedAt:       startedAt.UTC(),
+		DurationMS:      time.Since(startedAt).Milliseconds(),
+		Sources:         make([]SourceReport, 0, len(manifestCfg.Sources)),
+	}
+	if runErr != nil {
+		report.Error = runErr.Error()
+	}
+
+	for i, source := range manifestCfg.Sources {
+		var r ManifestResult
+		if i < len(results) {
+			r = results[i]
+		}
+		src := SourceReport{
+			URL:          source.URL,
+			Strategy:     r.Result.Strategy,
+			DocsWritten:  r.Result.DocsWritten,
+			DocsSkipped:  r.Result.DocsSkipped,
+			DocsFailed:   r.Result.DocsFailed,
+			BytesWritten: r.Result.BytesWritten,
+			DurationMS:   r.Duration.Milliseconds(),
+			Failures:     r.Result.Failures,
+		}
+		if src.Strategy == "" {
+			src.Strategy = source.Strategy
+		}
+		switch {
+		case r.Skipped:
+			src.Status = SourceSkipped
+			report.Totals.Skipped++
+		case r.Error != nil:
+			src.Status = SourceFailed
+			src.Error = r.Error.Error()
+			report.Totals.Failed++
+		case r.Source.URL == "":
+			src.Status = SourceNotRun
+			report.Totals.NotRun++
+		default:
+			src.Status = SourceSucceeded
+			report.Totals.Succeeded++
+		}
+		report.Totals.DocsWritten += src.DocsWritten
+		report.Totals.DocsSkipped += src.DocsSkipped
+		report.Totals.DocsFailed += src.DocsFailed
+		report.Totals.BytesWritten += src.BytesWritten
+		report.Sources = append(report.Sources, src)
+	}
+	report.Totals.Sources = len(report.Sources)
+
+	switch {
+	case runErr == nil && report.Totals.Failed == 0:
+		report.Status = ReportSuccess
+	case manifestCfg.Options.ContinueOnError && report.Totals.Succeeded > 0 &&
+		report.Totals.Failed > 0 && report.Totals.NotRun == 0:
+		report.Status = ReportPartial
+	default:
+		report.Status = ReportFailed
+	}
+	return report
+}
+
+// WriteManifestReport writes report as indented JSON to path, creating its
+// directory.
+func WriteManifestReport(path string, report ManifestReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// writeReport writes the report of the last RunManifest to opts.ReportPath,
+// when set. Failing to write it is logged, not returned, so it never hides
+// the run's own outcome.
+func (o *Orchestrator) writeReport(opts OrchestratorOptions, manifestCfg *manifest.Config, startedAt time.Time, runErr error) {
+	if opts.ReportPath == "" {
+		return
+	}
+	report := BuildManifestReport(manifestCfg, o.manifestResults, startedAt, runErr)
+	if err := WriteManifestReport(opts.ReportPath, report); err != nil {
+		o.logger.Warn().Err(err).Str("path", opts.ReportPath).Msg("Failed to write manifest report")
+		return
+	}
+	o.logger.Info().Str("path", opts.ReportPath).Str("status", report.Status).Msg("Wrote manifest report")
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/manifest"
+)
+
+func TestBuildManifestReport(t *testing.T) {
+	sources := []manifest.Source{
+		{URL: "https://a.example"},
+		{URL: "https://b.example", Strategy: "git"},
+		{URL: "https://c.example", Disabled: true},
+		{URL: "https://d.example"},
+	}
+	succeeded := ManifestResult{
+		Source:   sources[0],
+		Duration: 1500 * time.Millisecond,
+		Result: domain.StrategyResultSnapshot{
+			Strategy: "crawler", DocsWritten: 4, DocsSkipped: 1, DocsFailed: 1, BytesWritten: 2048,
+			Failures: []domain.DocumentFailure{{URL: "https://a.example/broken", Error: "HTTP 500"}},
+		},
+	}
+	failed := ManifestResult{Source: sources[1], Error: errors.New("clone failed")}
+	skipped := ManifestResult{Source: sources[2], Skipped: true}
+
+	tests := []struct {
+		name            string
+		continueOnError bool
+		results         []ManifestResult
+		runErr          error
+		want            string
+	}{
+		{name: "success", results: []ManifestResult{succeeded, succeeded, skipped, succeeded}, want: ReportSuccess},
+		{name: "failures swallowed", continueOnError: true, results: []ManifestResult{succeeded, failed, skipped, succeeded}, runErr: errors.New("manifest completed with 1/4 failures"), want: ReportPartial},
+		{name: "stopped at failure", results: []ManifestResult{succeeded, failed, skipped, {}}, runErr: errors.New("source failed"), want: ReportFailed},
+		{name: "nothing succeeded", continueOnError: true, results: []ManifestResult{failed, failed, skipped, failed}, runErr: errors.New("failures"), want: ReportFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &manifest.Config{Sources: sources, Options: manifest.Options{ContinueOnError: tt.continueOnError}}
+			report := BuildManifestReport(cfg, tt.results, time.Now(), tt.runErr)
+			assert.Equal(t, tt.want, report.Status)
+			assert.Len(t, report.Sources, 4)
+		})
+	}
+
+	cfg := &manifest.Config{Sources: sources, Options: manifest.Options{ContinueOnError: true}}
+	report := BuildManifestReport(cfg, []ManifestResult{succeeded, failed, skipped}, time.Now(), errors.New("interrupted"))
+	assert.Equal(t, ReportFailed, report.Status, "sources that never ran make the run failed")
+	assert.Equal(t, "interrupted", report.Error)
+	assert.Equal(t, ReportTotals{
+		Sources: 4, Succeeded: 1, Failed: 1, Skipped: 1, NotRun: 1,
+		DocsWritten: 4, DocsSkipped: 1, DocsFailed: 1, BytesWritten: 2048,
+	}, report.Totals)
+	assert.Equal(t, SourceReport{
+		URL: "https://a.example", Strategy: "crawler", Status: SourceSucceeded,
+		DocsWritten: 4, DocsSkipped: 1, DocsFailed: 1, BytesWritten: 2048, DurationMS: 1500,
+		Failures: []domain.DocumentFailure{{URL: "https://a.example/broken", Error: "HTTP 500"}},
+	}, report.Sources[0])
+	assert.Equal(t, SourceReport{URL: "https://b.example", Strategy: "git", Status: SourceFailed, Error: "clone failed"}, report.Sources[1])
+	assert.Equal(t, SourceSkipped, report.Sources[2].Status)
+	assert.Equal(t, SourceNotRun, report.Sources[3].Status)
+}
+
+func TestWriteManifestReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ci", "report.json")
+	report := ManifestReport{Status: ReportSuccess, Sources: []SourceReport{{URL: "https://a.example", Status: SourceSucceeded}}}
+
+	require.NoError(t, WriteManifestReport(path, report))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "success", decoded["status"])
+	assert.Contains(t, decoded, "totals")
+	assert.NotContains(t, decoded, "error", "error is omitted on success")
+}
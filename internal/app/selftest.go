@@ -0,0 +1,310 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/config"
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// selfTestMarker is a phrase of every fixture page, checked for in fetched,
+// converted, written and rendered content.
+const selfTestMarker = "repodocs self-test fixture"
+
+// SelfTestOptions configures SelfTest.
+type SelfTestOptions struct {
+	// Config supplies the fetch, rendering and output settings under test.
+	// The output directory is replaced with a temporary one, and the cache,
+	// proxy and LLM settings are not used.
+	Config *config.Config
+	// Render also renders a page built by JavaScript in the browser.
+	Render bool
+}
+
+// SelfTestStage is the outcome of one stage of SelfTest.
+type SelfTestStage struct {
+	Name   string
+	Detail string
+	// Err is set when the stage failed.
+	Err error
+	// Skipped is set when the stage was not run, with the reason in Detail.
+	Skipped  bool
+	Duration time.Duration
+}
+
+// SelfTestReport lists the stages of a SelfTest run in order.
+type SelfTestReport struct {
+	// ServerURL is the address of the in-process fixture server.
+	ServerURL string
+	Stages    []SelfTestStage
+}
+
+// OK reports whether no stage failed.
+func (r *SelfTestReport) OK() bool {
+	for _, stage := range r.Stages {
+		if stage.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Format writes a pass/fail line per stage to w.
+func (r *SelfTestReport) Format(w io.Writer) {
+	fmt.Fprintf(w, "Fixture server: %s\n\n", r.ServerURL)
+	for _, stage := range r.Stages {
+		switch {
+		case stage.Skipped:
+			fmt.Fprintf(w, "  %-8s SKIPPED  %s\n", stage.Name, stage.Detail)
+		case stage.Err != nil:
+			fmt.Fprintf(w, "  %-8s FAILED   %v\n", stage.Name, stage.Err)
+		default:
+			fmt.Fprintf(w, "  %-8s OK       %s (%s)\n", stage.Name, stage.Detail, stage.Duration.Round(time.Millisecond))
+		}
+	}
+	fmt.Fprintln(w)
+	if r.OK() {
+		fmt.Fprintln(w, "All stages passed.")
+	} else {
+		fmt.Fprintln(w, "Some stages failed.")
+	}
+}
+
+// SelfTest runs the extraction pipeline end to end against a fixture site
+// served in-process, so no network access is needed: it fetches a page,
+// converts it, writes it, extracts the whole site through its sitemap and,
+// with opts.Render, renders a JavaScript-built page in the browser. Each
+// stage is reported on its own; a stage whose input failed is skipped. The
+// returned error is for failures to set up the test.
+func SelfTest(ctx context.Context, opts SelfTestOptions) (*SelfTestReport, error) {
+	if opts.Config == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+	server := httptest.NewServer(selfTestHandler())
+	defer server.Close()
+
+	outputDir, err := os.MkdirTemp("", "repodocs-selftest-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary output directory: %w", err)
+	}
+	defer os.RemoveAll(outputDir)
+
+	cfg := *opts.Config
+	cfg.Output.Directory = outputDir
+	cfg.Output.SiteBaseURL = ""
+	cfg.Output.Checksums = false
+	cfg.Cache.Enabled = false
+	cfg.Proxy = config.ProxyConfig{}
+	cfg.LLM = config.LLMConfig{}
+	cfg.Logging.Level = "error"
+
+	orchOpts := OrchestratorOptions{
+		Config:        &cfg,
+		NoFallback:    true,
+		NoProgress:    true,
+		CommonOptions: domain.CommonOptions{Force: true},
+	}
+	orch, err := NewOrchestrator(orchOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create orchestrator: %w", err)
+	}
+	defer orch.Close()
+
+	report := &SelfTestReport{ServerURL: server.URL}
+	stage := func(name string, fn func() (string, error)) bool {
+		start := time.Now()
+		detail, err := fn()
+		report.Stages = append(report.Stages, SelfTestStage{Name: name, Detail: detail, Err: err, Duration: time.Since(start)})
+		return err == nil
+	}
+	skip := func(name, reason string) {
+		report.Stages = append(report.Stages, SelfTestStage{Name: name, Detail: reason, Skipped: true})
+	}
+
+	pageURL := server.URL + "/guide"
+	var resp *domain.Response
+	var doc *domain.Document
+
+	fetched := stage("fetch", func() (string, error) {
+		resp, err = orch.deps.Fetcher.Get(ctx, pageURL)
+		if err != nil {
+			return "", err
+		}
+		if !strings.Contains(string(resp.Body), selfTestMarker) {
+			return "", fmt.Errorf("unexpected response body (%d bytes)", len(resp.Body))
+		}
+		return fmt.Sprintf("GET /guide: %d, %d bytes", resp.StatusCode, len(resp.Body)), nil
+	})
+
+	converted := fetched && stage("convert", func() (string, error) {
+		doc, err = orch.deps.Converter.Convert(ctx, string(resp.Body), pageURL)
+		if err != nil {
+			return "", err
+		}
+		if doc.Title != "Self-test guide" || !strings.Contains(doc.Content, "## Install") || !strings.Contains(doc.Content, "```go") {
+			return "", fmt.Errorf("converted markdown is missing the title, headings or code block")
+		}
+		return fmt.Sprintf("%q, %d words", doc.Title, doc.WordCount), nil
+	})
+	if !fetched {
+		skip("convert", "fetch failed")
+	}
+
+	if converted {
+		stage("write", func() (string, error) {
+			doc.SourceStrategy = "selftest"
+			doc.FetchedAt = time.Now()
+			if err := orch.deps.WriteDocument(ctx, doc); err != nil {
+				return "", err
+			}
+			files, err := selfTestOutputFiles(outputDir)
+			if err != nil {
+				return "", err
+			}
+			if len(files) != 1 {
+				return "", fmt.Errorf("expected 1 written document, found %d", len(files))
+			}
+			return files[0], nil
+		})
+	} else {
+		skip("write", "convert failed or was skipped")
+	}
+
+	stage("extract", func() (string, error) {
+		if err := os.RemoveAll(outputDir); err != nil {
+			return "", err
+		}
+		if err := orch.Run(ctx, server.URL+"/sitemap.xml", orchOpts); err != nil {
+			return "", err
+		}
+		files, err := selfTestOutputFiles(outputDir)
+		if err != nil {
+			return "", err
+		}
+		if len(files) != 2 {
+			return "", fmt.Errorf("expected 2 documents from the sitemap, found %d", len(files))
+		}
+		return fmt.Sprintf("%d documents via sitemap.xml", len(files)), nil
+	})
+
+	if opts.Render {
+		stage("render", func() (string, error) {
+			r, err := orch.deps.GetRenderer()
+			if err != nil {
+				return "", err
+			}
+			html, err := r.Render(ctx, server.URL+"/app", domain.RenderOptions{
+				Timeout:    30 * time.Second,
+				WaitStable: 500 * time.Millisecond,
+			})
+			if err != nil {
+				return "", err
+			}
+			if !strings.Contains(html, selfTestMarker) {
+				return "", errors.New("rendered page is missing the content built by its script")
+			}
+			return fmt.Sprintf("GET /app: %d bytes after scripts ran", len(html)), nil
+		})
+	} else {
+		skip("render", "pass --selftest-render to check the browser")
+	}
+
+	return report, nil
+}
+
+// selfTestOutputFiles lists the markdown or text documents written under dir
+// that contain the fixture marker, as slash-separated relative paths. Hidden
+// files (such as sync state) and metadata files are ignored.
+func selfTestOutputFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".md" && ext != ".txt" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(data), selfTestMarker) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+// selfTestHandler serves the fixture site: two documentation pages, a
+// sitemap listing them and a page whose content is built by JavaScript.
+func selfTestHandler() http.Handler {
+	page := func(title, body string) string {
+		return `<!DOCTYPE html>
+<html lang="en"><head><meta charset="utf-8"><title>` + title + `</title>
+<meta name="description" content="Page of the ` + selfTestMarker + `."></head>
+<body><nav><a href="/guide">Guide</a> <a href="/reference">Reference</a></nav>
+<main><article><h1>` + title + `</h1>
+<p>This page is part of the ` + selfTestMarker + `. It checks that pages are fetched, converted to markdown and written to disk.</p>
+` + body + `</article></main></body></html>`
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/guide", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, page("Self-test guide", `<h2>Install</h2>
+<p>Download the binary, put it on your path and run it once to create the default configuration file.</p>
+<pre><code class="language-go">package main
+
+func main() {}
+</code></pre>
+<h2>Configure</h2>
+<p>Settings are read from the configuration file, environment variables and command-line flags, in increasing order of precedence.</p>`))
+	})
+	mux.HandleFunc("/reference", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, page("Self-test reference", `<h2>Options</h2>
+<ul><li><code>output</code>: the directory documents are written to.</li>
+<li><code>limit</code>: the maximum number of pages to extract.</li></ul>
+<p>Every option has a default, so an empty configuration file is valid.</p>`))
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		base := "http://" + r.Host
+		w.Header().Set("Content-Type", "application/xml")
+		io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<url><loc>`+base+`/guide</loc></url>
+<url><loc>`+base+`/reference</loc></url>
+</urlset>`)
+	})
+	mux.HandleFunc("/app", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, `<!DOCTYPE html>
+<html><head><title>Self-test app</title></head>
+<body><div id="root"></div>
+<script>
+// The marker is assembled here so it only appears once the script has run.
+document.getElementById("root").innerHTML = "<h1>Rendered</h1><p>Built by the repodocs self-" + "test fixture script.</p>";
+</script>
+</body></html>`)
+	})
+	return mux
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	report, err := SelfTest(context.Background(), SelfTestOptions{Config: config.Default()})
+	require.NoError(t, err)
+
+	var names []string
+	for _, stage := range report.Stages {
+		names = append(names, stage.Name)
+		assert.NoError(t, stage.Err, stage.Name)
+	}
+	assert.Equal(t, []string{"fetch", "convert", "write", "extract", "render"}, names)
+	assert.True(t, report.Stages[4].Skipped, "render runs only when requested")
+	assert.True(t, report.OK())
+
+	var buf bytes.Buffer
+	report.Format(&buf)
+	assert.Contains(t, buf.String(), "extract  OK       2 documents via sitemap.xml")
+	assert.Contains(t, buf.String(), "All stages passed.")
+}
+
+func TestSelfTestReport_Failed(t *testing.T) {
+	report := &SelfTestReport{ServerURL: "http://127.0.0.1:1", Stages: []SelfTestStage{
+		{Name: "fetch", Err: errors.New("connection refused")},
+		{Name: "convert", Skipped: true, Detail: "fetch failed"},
+	}}
+	assert.False(t, report.OK())
+
+	var buf bytes.Buffer
+	report.Format(&buf)
+	assert.Contains(t, buf.String(), "fetch    FAILED   connection refused")
+	assert.Contains(t, buf.String(), "convert  SKIPPED  fetch failed")
+	assert.Contains(t, buf.String(), "Some stages failed.")
+}
+
+func TestSelfTest_RequiresConfig(t *testing.T) {
+	_, err := SelfTest(context.Background(), SelfTestOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"fmt"
+	"io"
+)
+
+// StrategyMatch is the answer of one registered strategy to CanHandle.
+type StrategyMatch struct {
+	Name      string
+	CanHandle bool
+}
+
+// StrategyReport explains which strategy would extract a URL: the CanHandle
+// answer of every registered strategy, in priority order, and the strategy a
+// run would select.
+type StrategyReport struct {
+	URL     string
+	Matches []StrategyMatch
+	// Selected is the strategy a run starts with: the override when one is
+	// given, else the one detected from the URL (StrategyUnknown if none).
+	Selected StrategyType
+	// Override is the --strategy value the selection came from, if any.
+	Override string
+}
+
+// ListStrategies reports how rawURL would be routed without fetching it or
+// writing anything. override is a --strategy or manifest strategy name.
+func (o *Orchestrator) ListStrategies(rawURL, override string) (*StrategyReport, error) {
+	report := &StrategyReport{URL: rawURL, Override: override}
+	for _, strategy := range GetAllStrategies(o.deps) {
+		report.Matches = append(report.Matches, StrategyMatch{
+			Name:      strategy.Name(),
+			CanHandle: strategy.CanHandle(rawURL),
+		})
+	}
+
+	if override != "" {
+		if !IsValidStrategy(StrategyType(override)) {
+			return nil, fmt.Errorf("unknown strategy override: %s", override)
+		}
+		report.Selected = StrategyType(override)
+	} else {
+		report.Selected = o.detectStrategy(rawURL)
+	}
+	return report, nil
+}
+
+// Format writes the report as one line per strategy followed by the
+// selection.
+func (r *StrategyReport) Format(w io.Writer) {
+	fmt.Fprintf(w, "URL: %s\n\n", r.URL)
+	for i, m := range r.Matches {
+		answer := "no"
+		if m.CanHandle {
+			answer = "yes"
+		}
+		fmt.Fprintf(w, "%2d. %-13s %s\n", i+1, m.Name, answer)
+	}
+	fmt.Fprintln(w)
+
+	switch {
+	case r.Override != "":
+		fmt.Fprintf(w, "Selected: %s (--strategy)\n", r.Selected)
+	case r.Selected == StrategyUnknown:
+		fmt.Fprintln(w, "Selected: none (unsupported URL)")
+	case r.Selected == StrategyCrawler:
+		fmt.Fprintln(w, "Selected: crawler (a discovered sitemap switches it to sitemap at run time)")
+	default:
+		fmt.Fprintf(w, "Selected: %s\n", r.Selected)
+	}
+}
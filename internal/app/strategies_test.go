@@ -0,0 +1,58 @@
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrchestrator_ListStrategies(t *testing.T) {
+	orch := newProbeOrchestrator(t)
+
+	report, err := orch.ListStrategies("https://github.com/owner/repo", "")
+	require.NoError(t, err)
+	require.Len(t, report.Matches, len(validStrategies))
+	assert.Equal(t, string(StrategyLLMS), report.Matches[0].Name)
+	assert.Equal(t, string(StrategyCrawler), report.Matches[len(report.Matches)-1].Name)
+	assert.Equal(t, StrategyGit, report.Selected)
+
+	var handled []string
+	for _, m := range report.Matches {
+		if m.CanHandle {
+			handled = append(handled, m.Name)
+		}
+	}
+	assert.Equal(t, []string{"git", "crawler"}, handled)
+
+	var buf bytes.Buffer
+	report.Format(&buf)
+	assert.Contains(t, buf.String(), " 8. git           yes\n")
+	assert.Contains(t, buf.String(), "Selected: git\n")
+}
+
+func TestOrchestrator_ListStrategies_Override(t *testing.T) {
+	orch := newProbeOrchestrator(t)
+
+	report, err := orch.ListStrategies("https://example.com/docs", "sitemap")
+	require.NoError(t, err)
+	assert.Equal(t, StrategySitemap, report.Selected)
+	var buf bytes.Buffer
+	report.Format(&buf)
+	assert.Contains(t, buf.String(), "Selected: sitemap (--strategy)")
+
+	_, err = orch.ListStrategies("https://example.com/docs", "bogus")
+	assert.Error(t, err)
+}
+
+func TestOrchestrator_ListStrategies_Unsupported(t *testing.T) {
+	orch := newProbeOrchestrator(t)
+
+	report, err := orch.ListStrategies("ftp://example.com/docs", "")
+	require.NoError(t, err)
+	assert.Equal(t, StrategyUnknown, report.Selected)
+	var buf bytes.Buffer
+	report.Format(&buf)
+	assert.Contains(t, buf.String(), "Selected: none")
+}
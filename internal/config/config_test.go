@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -44,6 +46,30 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "negative workers is an error",
+			cfg:  Default(),
+			modify: func(c *Config) {
+				c.Concurrency.Workers = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative timeout is an error",
+			cfg:  Default(),
+			modify: func(c *Config) {
+				c.Concurrency.Timeout = -time.Second
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative cache TTL is an error",
+			cfg:  Default(),
+			modify: func(c *Config) {
+				c.Cache.TTL = -time.Hour
+			},
+			wantErr: true,
+		},
 		{
 			name: "max depth below minimum defaults to 3",
 			cfg:  &Config{},
@@ -117,6 +143,8 @@ func TestDefault(t *testing.T) {
 	assert.False(t, cfg.Output.Flat)
 	assert.False(t, cfg.Output.JSONMetadata)
 	assert.False(t, cfg.Output.Overwrite)
+	assert.Equal(t, DefaultLineEndings, cfg.Output.LineEndings)
+	assert.Equal(t, DefaultHashAlgorithm, cfg.Output.HashAlgorithm)
 
 	assert.Equal(t, DefaultWorkers, cfg.Concurrency.Workers)
 	assert.Equal(t, DefaultTimeout, cfg.Concurrency.Timeout)
@@ -129,6 +157,7 @@ func TestDefault(t *testing.T) {
 	assert.False(t, cfg.Rendering.ForceJS)
 	assert.Equal(t, DefaultJSTimeout, cfg.Rendering.JSTimeout)
 	assert.Equal(t, DefaultScrollToEnd, cfg.Rendering.ScrollToEnd)
+	assert.False(t, cfg.Rendering.IncludeHidden)
 
 	assert.Equal(t, "", cfg.Stealth.UserAgent)
 	assert.Equal(t, DefaultRandomDelayMin, cfg.Stealth.RandomDelayMin)
@@ -410,3 +439,250 @@ func TestConstants(t *testing.T) {
 	assert.Greater(t, DefaultCircuitBreakerSuccessThresholdHalfOpen, 0)
 	assert.Greater(t, int(DefaultCircuitBreakerResetTimeout.Seconds()), int(time.Second.Seconds()))
 }
+
+func TestConfig_Validate_SiteBaseURL(t *testing.T) {
+	for _, valid := range []string{"", "https://docs.example.com", "http://localhost:8080/kb/"} {
+		cfg := Default()
+		cfg.Output.SiteBaseURL = valid
+		assert.NoError(t, cfg.Validate(), valid)
+	}
+	for _, invalid := range []string{"docs.example.com", "ftp://example.com", "https://"} {
+		cfg := Default()
+		cfg.Output.SiteBaseURL = invalid
+		assert.Error(t, cfg.Validate(), invalid)
+	}
+}
+
+func TestConfig_Validate_LineEndings(t *testing.T) {
+	for _, valid := range []string{"", "lf", "crlf", "preserve"} {
+		cfg := Default()
+		cfg.Output.LineEndings = valid
+		assert.NoError(t, cfg.Validate(), valid)
+	}
+	cfg := Default()
+	cfg.Output.LineEndings = "cr"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfig_Validate_HashAlgorithm(t *testing.T) {
+	for _, valid := range []string{"", "sha256", "blake3"} {
+		cfg := Default()
+		cfg.Output.HashAlgorithm = valid
+		assert.NoError(t, cfg.Validate(), valid)
+	}
+	cfg := Default()
+	cfg.Output.HashAlgorithm = "md5"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfig_Validate_OutputFormat(t *testing.T) {
+	for _, valid := range []string{"", "markdown", "text"} {
+		cfg := Default()
+		cfg.Output.Format = valid
+		assert.NoError(t, cfg.Validate(), valid)
+	}
+	cfg := Default()
+	cfg.Output.Format = "html"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfig_Validate_Chunk(t *testing.T) {
+	cfg := Default()
+	cfg.Output.Chunk = true
+	assert.NoError(t, cfg.Validate())
+
+	for name, modify := range map[string]func(*Config){
+		"output.chunk_size":    func(c *Config) { c.Output.ChunkSize = 0 },
+		"output.chunk_overlap": func(c *Config) { c.Output.ChunkOverlap = c.Output.ChunkSize },
+		"output.chunk_unit":    func(c *Config) { c.Output.ChunkUnit = "words" },
+	} {
+		cfg := Default()
+		cfg.Output.Chunk = true
+		modify(cfg)
+		err := cfg.Validate()
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), name)
+	}
+}
+
+func TestConfig_Validate_CloneTimeout(t *testing.T) {
+	cfg := Default()
+	assert.Equal(t, DefaultGitCloneTimeout, cfg.Git.CloneTimeout)
+
+	cfg.Git.CloneTimeout = 0
+	assert.NoError(t, cfg.Validate(), "0 disables the clone timeout")
+
+	cfg.Git.CloneTimeout = -time.Second
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "git.clone_timeout")
+}
+
+func TestConfig_Validate_FetchAndConcurrency(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+
+	for name, modify := range map[string]func(*Config){
+		"concurrency.sources":            func(c *Config) { c.Concurrency.Sources = -1 },
+		"concurrency.max_pages_per_host": func(c *Config) { c.Concurrency.MaxPagesPerHost = -1 },
+		"concurrency.rate_limit":         func(c *Config) { c.Concurrency.RateLimit = -1 },
+		"fetch.max_retries":              func(c *Config) { c.Fetch.MaxRetries = -1 },
+		"fetch.host_breaker_threshold":   func(c *Config) { c.Fetch.HostBreakerThreshold = -1 },
+		"fetch.host_breaker_cooldown":    func(c *Config) { c.Fetch.HostBreakerCooldown = -time.Second },
+		"fetch.crawl_delay":              func(c *Config) { c.Fetch.CrawlDelay = -time.Second },
+		"fetch.request_jitter":           func(c *Config) { c.Fetch.RequestJitter = -time.Second },
+		"fetch.max_redirects":            func(c *Config) { c.Fetch.MaxRedirects = -2 },
+		"rendering.truncation_retries":   func(c *Config) { c.Rendering.TruncationRetries = -1 },
+		"git.self_hosted_hosts":          func(c *Config) { c.Git.SelfHostedHosts = map[string]string{"git.corp": "gitea"} },
+	} {
+		cfg := Default()
+		modify(cfg)
+		err := cfg.Validate()
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), name)
+	}
+}
+
+func TestLoad_FetchKeysFromFileAndEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	configContent := `
+concurrency:
+  sources: 4
+  max_pages_per_host: 50
+  rate_limit: 2.5
+fetch:
+  max_retries: 5
+  host_breaker_threshold: 0
+  host_breaker_cooldown: 2m
+  crawl_delay: 1500ms
+  request_jitter: 250ms
+  max_redirects: 3
+  cross_host_redirects: false
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(configContent), 0644))
+
+	originalWd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	// Environment variables take precedence over the config file.
+	t.Setenv("REPODOCS_FETCH_MAX_RETRIES", "7")
+
+	cfg, _, err := LoadWithViper()
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.Concurrency.Sources)
+	assert.Equal(t, 50, cfg.Concurrency.MaxPagesPerHost)
+	assert.Equal(t, 2.5, cfg.Concurrency.RateLimit)
+	assert.Equal(t, 7, cfg.Fetch.MaxRetries)
+	assert.Equal(t, 0, cfg.Fetch.HostBreakerThreshold)
+	assert.Equal(t, 2*time.Minute, cfg.Fetch.HostBreakerCooldown)
+	assert.Equal(t, 1500*time.Millisecond, cfg.Fetch.CrawlDelay)
+	assert.Equal(t, 250*time.Millisecond, cfg.Fetch.RequestJitter)
+	assert.Equal(t, 3, cfg.Fetch.MaxRedirects)
+	assert.False(t, cfg.Fetch.CrossHostRedirects)
+}
+
+func TestConfig_Validate_ReportsEveryField(t *testing.T) {
+	cfg := Default()
+	cfg.Concurrency.Workers = -1
+	cfg.Cache.TTL = -time.Minute
+	cfg.Logging.Format = "xml"
+	cfg.Stealth.RandomDelayMin = 5 * time.Second
+
+	err := cfg.Validate()
+	require.Error(t, err)
+
+	var fields []string
+	for _, ve := range ValidationErrors(fmt.Errorf("failed to load config: %w", err)) {
+		fields = append(fields, ve.Field)
+	}
+	assert.ElementsMatch(t, []string{
+		"concurrency.workers",
+		"cache.ttl",
+		"logging.format",
+		"stealth.random_delay_max",
+	}, fields)
+
+	assert.Nil(t, ValidationErrors(errors.New("unrelated")))
+}
+
+func TestLoad_Profiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	configContent := `
+concurrency:
+  workers: 5
+  max_depth: 2
+profiles:
+  internal:
+    concurrency:
+      workers: 12
+    proxy:
+      enabled: true
+      url: socks5://proxy.internal:1080
+  public:
+    concurrency:
+      max_depth: 6
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "config.yaml"), []byte(configContent), 0644))
+
+	originalWd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	t.Run("no profile uses base config", func(t *testing.T) {
+		cfg, _, err := LoadWithViper()
+		require.NoError(t, err)
+		assert.Equal(t, 5, cfg.Concurrency.Workers)
+		assert.Equal(t, 2, cfg.Concurrency.MaxDepth)
+		assert.False(t, cfg.Proxy.Enabled)
+		assert.Empty(t, cfg.Profile)
+	})
+
+	t.Run("profile merges over base config", func(t *testing.T) {
+		t.Setenv("REPODOCS_PROFILE", "internal")
+		cfg, _, err := LoadWithViper()
+		require.NoError(t, err)
+		assert.Equal(t, "internal", cfg.Profile)
+		assert.Equal(t, 12, cfg.Concurrency.Workers)
+		assert.Equal(t, 2, cfg.Concurrency.MaxDepth)
+		assert.True(t, cfg.Proxy.Enabled)
+		assert.Equal(t, "socks5://proxy.internal:1080", cfg.Proxy.URL)
+	})
+
+	t.Run("environment overrides profile", func(t *testing.T) {
+		t.Setenv("REPODOCS_PROFILE", "internal")
+		t.Setenv("REPODOCS_CONCURRENCY_WORKERS", "3")
+		cfg, _, err := LoadWithViper()
+		require.NoError(t, err)
+		assert.Equal(t, 3, cfg.Concurrency.Workers)
+	})
+
+	t.Run("unknown profile is an error", func(t *testing.T) {
+		t.Setenv("REPODOCS_PROFILE", "staging")
+		_, _, err := LoadWithViper()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown profile "staging" (available: internal, public)`)
+	})
+}
+
+func TestStealthConfig_ResolveUserAgents(t *testing.T) {
+	agents, err := StealthConfig{}.ResolveUserAgents()
+	require.NoError(t, err)
+	assert.Empty(t, agents)
+
+	path := filepath.Join(t.TempDir(), "agents.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# rotation\nagent-b\n\nagent-c\n"), 0644))
+
+	agents, err = StealthConfig{UserAgents: []string{"agent-a"}, UserAgentsFile: path}.ResolveUserAgents()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent-a", "agent-b", "agent-c"}, agents)
+
+	_, err = StealthConfig{UserAgentsFile: filepath.Join(t.TempDir(), "missing.txt")}.ResolveUserAgents()
+	assert.Error(t, err)
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	require.NoError(t, os.WriteFile(empty, []byte("# nothing\n"), 0644))
+	_, err = StealthConfig{UserAgentsFile: empty}.ResolveUserAgents()
+	assert.Error(t, err)
+}
@@ -4,25 +4,43 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/quantmind-br/repodocs/internal/renderer"
 )
 
 // Default values
 const (
 	// Output defaults
-	DefaultOutputDir = "./docs"
+	DefaultOutputDir     = "./docs"
+	DefaultLineEndings   = "lf"
+	DefaultHashAlgorithm = "sha256"
+	DefaultOutputFormat  = "markdown"
+	DefaultChunkSize     = 1000
+	DefaultChunkOverlap  = 100
+	DefaultChunkUnit     = "chars"
 
 	// Concurrency defaults
 	DefaultWorkers  = 5
 	DefaultTimeout  = 90 * time.Second
 	DefaultMaxDepth = 3
 
+	// Fetch defaults
+	DefaultFetchMaxRetries      = 3
+	DefaultHostBreakerThreshold = 10
+	DefaultHostBreakerCooldown  = time.Minute
+	DefaultCrawlDelay           = time.Duration(0)
+	DefaultRequestJitter        = time.Duration(0)
+	DefaultMaxRedirects         = 10
+	DefaultCrossHostRedirects   = true
+
 	// Cache defaults
 	DefaultCacheEnabled = true
 	DefaultCacheTTL     = 24 * time.Hour
 
 	// Rendering defaults
-	DefaultJSTimeout   = 60 * time.Second
-	DefaultScrollToEnd = true
+	DefaultJSTimeout         = 60 * time.Second
+	DefaultScrollToEnd       = true
+	DefaultRenderDecisionTTL = 10 * time.Minute
 
 	// Stealth defaults
 	DefaultRandomDelayMin = 1 * time.Second
@@ -55,7 +73,8 @@ const (
 	DefaultCircuitBreakerResetTimeout             = 30 * time.Second
 
 	// Git defaults
-	DefaultGitMaxFileSize = "10MB"
+	DefaultGitMaxFileSize  = "10MB"
+	DefaultGitCloneTimeout = 5 * time.Minute
 )
 
 // Default exclude patterns
@@ -91,10 +110,16 @@ func ConfigFilePath() string {
 func Default() *Config {
 	return &Config{
 		Output: OutputConfig{
-			Directory:    DefaultOutputDir,
-			Flat:         false,
-			JSONMetadata: false,
-			Overwrite:    false,
+			Directory:     DefaultOutputDir,
+			Flat:          false,
+			JSONMetadata:  false,
+			Overwrite:     false,
+			LineEndings:   DefaultLineEndings,
+			HashAlgorithm: DefaultHashAlgorithm,
+			Format:        DefaultOutputFormat,
+			ChunkSize:     DefaultChunkSize,
+			ChunkOverlap:  DefaultChunkOverlap,
+			ChunkUnit:     DefaultChunkUnit,
 		},
 		Concurrency: ConcurrencyConfig{
 			Workers:  DefaultWorkers,
@@ -107,9 +132,12 @@ func Default() *Config {
 			Directory: CacheDir(),
 		},
 		Rendering: RenderingConfig{
-			ForceJS:     false,
-			JSTimeout:   DefaultJSTimeout,
-			ScrollToEnd: DefaultScrollToEnd,
+			ForceJS:           false,
+			JSTimeout:         DefaultJSTimeout,
+			ScrollToEnd:       DefaultScrollToEnd,
+			RenderDecisionTTL: DefaultRenderDecisionTTL,
+			TruncationRetries: renderer.DefaultTruncationRetries,
+			IncludeHidden:     false,
 		},
 		Stealth: StealthConfig{
 			UserAgent:      "",
@@ -147,7 +175,17 @@ func Default() *Config {
 			},
 		},
 		Git: GitConfig{
-			MaxFileSize: DefaultGitMaxFileSize,
+			MaxFileSize:  DefaultGitMaxFileSize,
+			CloneTimeout: DefaultGitCloneTimeout,
+		},
+		Fetch: FetchConfig{
+			MaxRetries:           DefaultFetchMaxRetries,
+			HostBreakerThreshold: DefaultHostBreakerThreshold,
+			HostBreakerCooldown:  DefaultHostBreakerCooldown,
+			CrawlDelay:           DefaultCrawlDelay,
+			RequestJitter:        DefaultRequestJitter,
+			MaxRedirects:         DefaultMaxRedirects,
+			CrossHostRedirects:   DefaultCrossHostRedirects,
 		},
 	}
 }
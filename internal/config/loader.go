@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/quantmind-br/repodocs/internal/renderer"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
@@ -38,6 +40,11 @@ func Load() (*Config, error) {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
+	// Profile selected with --profile or REPODOCS_PROFILE
+	if err := applyProfile(v, v.GetString("profile")); err != nil {
+		return nil, err
+	}
+
 	// Unmarshal config
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
@@ -79,6 +86,11 @@ func LoadWithViper() (*Config, *viper.Viper, error) {
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
+	// Profile selected with REPODOCS_PROFILE
+	if err := applyProfile(v, v.GetString("profile")); err != nil {
+		return nil, nil, err
+	}
+
 	// Unmarshal config
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
@@ -93,18 +105,75 @@ func LoadWithViper() (*Config, *viper.Viper, error) {
 	return &cfg, v, nil
 }
 
+// applyProfile merges the named entry of the config file's profiles section
+// over the base configuration. Profile values only replace config file values:
+// environment variables and flags still take precedence. An empty name is a
+// no-op; an unknown name is an error.
+func applyProfile(v *viper.Viper, name string) error {
+	if name == "" {
+		return nil
+	}
+	profile := v.Sub("profiles." + name)
+	if profile == nil {
+		names := make([]string, 0)
+		for n := range v.GetStringMap("profiles") {
+			names = append(names, n)
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile %q: no profiles are defined in the config file", name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return v.MergeConfigMap(profile.AllSettings())
+}
+
 // setDefaults sets default values in viper
 func setDefaults(v *viper.Viper) {
+	// Registered so REPODOCS_PROFILE is picked up when unmarshaling
+	v.SetDefault("profile", "")
+
 	// Output defaults
 	v.SetDefault("output.directory", DefaultOutputDir)
 	v.SetDefault("output.flat", false)
 	v.SetDefault("output.json_metadata", false)
 	v.SetDefault("output.overwrite", false)
+	v.SetDefault("output.site_base_url", "")
+	v.SetDefault("output.line_endings", DefaultLineEndings)
+	v.SetDefault("output.hash_algorithm", DefaultHashAlgorithm)
+	v.SetDefault("output.format", DefaultOutputFormat)
+	v.SetDefault("output.chunk", false)
+	v.SetDefault("output.chunk_size", DefaultChunkSize)
+	v.SetDefault("output.chunk_overlap", DefaultChunkOverlap)
+	v.SetDefault("output.chunk_unit", DefaultChunkUnit)
+	v.SetDefault("output.checksums", false)
+	v.SetDefault("git.honor_gitignore", false)
+	v.SetDefault("git.ignore_dirs", []string{})
+	v.SetDefault("git.replace_ignore_dirs", false)
+	v.SetDefault("git.include_github_meta", false)
+	v.SetDefault("git.submodules", false)
+	v.SetDefault("git.lfs", false)
+	v.SetDefault("git.clone_timeout", DefaultGitCloneTimeout)
+	v.SetDefault("git.clean_mdx", false)
+	v.SetDefault("git.self_hosted_hosts", map[string]string{})
 
 	// Concurrency defaults
 	v.SetDefault("concurrency.workers", DefaultWorkers)
 	v.SetDefault("concurrency.timeout", DefaultTimeout)
 	v.SetDefault("concurrency.max_depth", DefaultMaxDepth)
+	v.SetDefault("concurrency.sources", 0)
+	v.SetDefault("concurrency.max_pages_per_host", 0)
+	v.SetDefault("concurrency.rate_limit", 0.0)
+
+	// Fetch defaults
+	v.SetDefault("fetch.max_retries", DefaultFetchMaxRetries)
+	v.SetDefault("fetch.host_breaker_threshold", DefaultHostBreakerThreshold)
+	v.SetDefault("fetch.host_breaker_cooldown", DefaultHostBreakerCooldown)
+	v.SetDefault("fetch.crawl_delay", DefaultCrawlDelay)
+	v.SetDefault("fetch.request_jitter", DefaultRequestJitter)
+	v.SetDefault("fetch.max_redirects", DefaultMaxRedirects)
+	v.SetDefault("fetch.cross_host_redirects", DefaultCrossHostRedirects)
+	v.SetDefault("fetch.detect_auth_walls", false)
 
 	// Cache defaults
 	v.SetDefault("cache.enabled", DefaultCacheEnabled)
@@ -116,9 +185,15 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("rendering.js_timeout", DefaultJSTimeout)
 	v.SetDefault("rendering.scroll_to_end", DefaultScrollToEnd)
 	v.SetDefault("rendering.cdp_endpoint", "")
+	v.SetDefault("rendering.chrome_args", []string{})
+	v.SetDefault("rendering.render_decision_ttl", DefaultRenderDecisionTTL)
+	v.SetDefault("rendering.truncation_retries", renderer.DefaultTruncationRetries)
+	v.SetDefault("rendering.include_hidden", false)
 
 	// Stealth defaults
 	v.SetDefault("stealth.user_agent", "")
+	v.SetDefault("stealth.user_agents", []string{})
+	v.SetDefault("stealth.user_agents_file", "")
 	v.SetDefault("stealth.random_delay_min", DefaultRandomDelayMin)
 	v.SetDefault("stealth.random_delay_max", DefaultRandomDelayMax)
 
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDefaultTemplate_CommentsEveryKey(t *testing.T) {
+	data, err := DefaultTemplate()
+	require.NoError(t, err)
+
+	var doc yaml.Node
+	require.NoError(t, yaml.Unmarshal(data, &doc))
+
+	var walk func(node *yaml.Node, prefix string)
+	walk = func(node *yaml.Node, prefix string) {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			path := strings.TrimPrefix(prefix+"."+key.Value, ".")
+			assert.NotEmpty(t, strings.TrimSpace(key.HeadComment), "missing comment for %s", path)
+			if value.Kind == yaml.MappingNode {
+				walk(value, path)
+			}
+		}
+	}
+	walk(doc.Content[0], "")
+}
+
+func TestDefaultTemplate_LoadsAsDefaults(t *testing.T) {
+	data, err := DefaultTemplate()
+	require.NoError(t, err)
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+
+	want := Default()
+	want.Cache.Directory = "~/.repodocs/cache"
+	want.Rendering.ChromeArgs = []string{}
+	want.Stealth.UserAgents = []string{}
+	assert.Equal(t, want, &cfg)
+}
+
+func TestWriteDefaultTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+
+	require.NoError(t, WriteDefaultTemplate(path, false))
+	assert.FileExists(t, path)
+
+	require.NoError(t, os.WriteFile(path, []byte("custom: true\n"), 0644))
+	err := WriteDefaultTemplate(path, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--force")
+
+	require.NoError(t, WriteDefaultTemplate(path, true))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "concurrency:")
+}
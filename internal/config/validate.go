@@ -0,0 +1,231 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// validLogLevels and validLogFormats list the accepted logging settings,
+// validLineEndings the accepted output line endings, validHashAlgorithms
+// the accepted content hash digests, validOutputFormats the accepted
+// document formats, validChunkUnits the accepted chunk size units and
+// validGitPlatforms the platforms of self-hosted git hosts; an empty value
+// uses the default.
+var (
+	validLogLevels      = []string{"debug", "info", "warn", "error"}
+	validLogFormats     = []string{"pretty", "json"}
+	validLineEndings    = []string{"lf", "crlf", "preserve"}
+	validHashAlgorithms = []string{"sha256", "blake3"}
+	validOutputFormats  = []string{"markdown", "text"}
+	validChunkUnits     = []string{"chars", "tokens"}
+	validGitPlatforms   = []string{"github", "gitlab", "bitbucket"}
+)
+
+// Validate checks the configuration and returns every out-of-range value as a
+// joined set of *domain.ValidationError (see ValidationErrors). Zero values
+// and values below a usable minimum (e.g. a sub-second timeout) are replaced
+// with their defaults; negative and unknown values are errors.
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(field, format string, args ...any) {
+		errs = append(errs, domain.NewValidationError(field, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Concurrency.Workers < 0 {
+		invalid("concurrency.workers", "must be >= 1, got %d", c.Concurrency.Workers)
+	} else if c.Concurrency.Workers == 0 {
+		c.Concurrency.Workers = DefaultWorkers
+	}
+	if c.Concurrency.MaxDepth < 0 {
+		invalid("concurrency.max_depth", "must be >= 1, got %d", c.Concurrency.MaxDepth)
+	} else if c.Concurrency.MaxDepth == 0 {
+		c.Concurrency.MaxDepth = DefaultMaxDepth
+	}
+	if c.Concurrency.Timeout < 0 {
+		invalid("concurrency.timeout", "must be > 0, got %s", c.Concurrency.Timeout)
+	} else if c.Concurrency.Timeout < time.Second {
+		c.Concurrency.Timeout = DefaultTimeout
+	}
+	if c.Concurrency.Sources < 0 {
+		invalid("concurrency.sources", "must be >= 0, got %d", c.Concurrency.Sources)
+	}
+	if c.Concurrency.MaxPagesPerHost < 0 {
+		invalid("concurrency.max_pages_per_host", "must be >= 0, got %d", c.Concurrency.MaxPagesPerHost)
+	}
+	if c.Concurrency.RateLimit < 0 {
+		invalid("concurrency.rate_limit", "must be >= 0, got %g", c.Concurrency.RateLimit)
+	}
+
+	if c.Fetch.MaxRetries < 0 {
+		invalid("fetch.max_retries", "must be >= 0, got %d", c.Fetch.MaxRetries)
+	}
+	if c.Fetch.HostBreakerThreshold < 0 {
+		invalid("fetch.host_breaker_threshold", "must be >= 0, got %d", c.Fetch.HostBreakerThreshold)
+	}
+	if c.Fetch.HostBreakerCooldown < 0 {
+		invalid("fetch.host_breaker_cooldown", "must be >= 0, got %s", c.Fetch.HostBreakerCooldown)
+	}
+	if c.Fetch.CrawlDelay < 0 {
+		invalid("fetch.crawl_delay", "must be >= 0, got %s", c.Fetch.CrawlDelay)
+	}
+	if c.Fetch.RequestJitter < 0 {
+		invalid("fetch.request_jitter", "must be >= 0, got %s", c.Fetch.RequestJitter)
+	}
+	if c.Fetch.MaxRedirects < -1 {
+		invalid("fetch.max_redirects", "must be >= -1, got %d", c.Fetch.MaxRedirects)
+	}
+
+	if c.Cache.TTL < 0 {
+		invalid("cache.ttl", "must be >= 0, got %s", c.Cache.TTL)
+	} else if c.Cache.TTL < time.Minute {
+		c.Cache.TTL = DefaultCacheTTL
+	}
+
+	if c.Rendering.JSTimeout < 0 {
+		invalid("rendering.js_timeout", "must be >= 0, got %s", c.Rendering.JSTimeout)
+	} else if c.Rendering.JSTimeout < time.Second {
+		c.Rendering.JSTimeout = DefaultJSTimeout
+	}
+	if c.Rendering.RenderDecisionTTL < 0 {
+		invalid("rendering.render_decision_ttl", "must be >= 0, got %s", c.Rendering.RenderDecisionTTL)
+	}
+	if c.Rendering.TruncationRetries < 0 {
+		invalid("rendering.truncation_retries", "must be >= 0, got %d", c.Rendering.TruncationRetries)
+	}
+
+	if c.Stealth.RandomDelayMin < 0 {
+		invalid("stealth.random_delay_min", "must be >= 0, got %s", c.Stealth.RandomDelayMin)
+	}
+	if c.Stealth.RandomDelayMax < 0 {
+		invalid("stealth.random_delay_max", "must be >= 0, got %s", c.Stealth.RandomDelayMax)
+	} else if c.Stealth.RandomDelayMax < c.Stealth.RandomDelayMin {
+		invalid("stealth.random_delay_max", "must be >= stealth.random_delay_min (%s), got %s", c.Stealth.RandomDelayMin, c.Stealth.RandomDelayMax)
+	}
+
+	if c.Output.SiteBaseURL != "" {
+		u, err := url.Parse(c.Output.SiteBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("output.site_base_url", "must be an absolute http(s) URL, got %q", c.Output.SiteBaseURL)
+		}
+	}
+	if c.Output.LineEndings != "" && !slices.Contains(validLineEndings, c.Output.LineEndings) {
+		invalid("output.line_endings", "unknown line endings %q (use one of %v)", c.Output.LineEndings, validLineEndings)
+	}
+	if c.Output.HashAlgorithm != "" && !slices.Contains(validHashAlgorithms, c.Output.HashAlgorithm) {
+		invalid("output.hash_algorithm", "unknown hash algorithm %q (use one of %v)", c.Output.HashAlgorithm, validHashAlgorithms)
+	}
+	if c.Output.Format != "" && !slices.Contains(validOutputFormats, c.Output.Format) {
+		invalid("output.format", "unknown output format %q (use one of %v)", c.Output.Format, validOutputFormats)
+	}
+	if c.Output.ChunkSize < 0 {
+		invalid("output.chunk_size", "must be >= 0, got %d", c.Output.ChunkSize)
+	}
+	if c.Output.ChunkOverlap < 0 {
+		invalid("output.chunk_overlap", "must be >= 0, got %d", c.Output.ChunkOverlap)
+	}
+	if c.Output.Chunk && c.Output.ChunkSize <= 0 {
+		invalid("output.chunk_size", "must be > 0 when chunking is enabled, got %d", c.Output.ChunkSize)
+	}
+	if c.Output.ChunkSize > 0 && c.Output.ChunkOverlap >= c.Output.ChunkSize {
+		invalid("output.chunk_overlap", "must be smaller than chunk_size (%d), got %d", c.Output.ChunkSize, c.Output.ChunkOverlap)
+	}
+	if c.Output.ChunkUnit != "" && !slices.Contains(validChunkUnits, c.Output.ChunkUnit) {
+		invalid("output.chunk_unit", "unknown chunk unit %q (use one of %v)", c.Output.ChunkUnit, validChunkUnits)
+	}
+
+	if c.Logging.Level != "" && !slices.Contains(validLogLevels, c.Logging.Level) {
+		invalid("logging.level", "unknown level %q (use one of %v)", c.Logging.Level, validLogLevels)
+	}
+	if c.Logging.Format != "" && !slices.Contains(validLogFormats, c.Logging.Format) {
+		invalid("logging.format", "unknown format %q (use one of %v)", c.Logging.Format, validLogFormats)
+	}
+
+	if c.Git.MaxFileSize == "" {
+		c.Git.MaxFileSize = DefaultGitMaxFileSize
+	} else if _, err := ParseSize(c.Git.MaxFileSize); err != nil {
+		invalid("git.max_file_size", "%v", err)
+	}
+	if c.Git.CloneTimeout < 0 {
+		invalid("git.clone_timeout", "must be >= 0, got %s", c.Git.CloneTimeout)
+	}
+	for _, host := range slices.Sorted(maps.Keys(c.Git.SelfHostedHosts)) {
+		if platform := c.Git.SelfHostedHosts[host]; !slices.Contains(validGitPlatforms, strings.ToLower(platform)) {
+			invalid("git.self_hosted_hosts", "unknown platform %q for %s (use one of %v)", platform, host, validGitPlatforms)
+		}
+	}
+
+	// Note: proxy configuration is intentionally validated lazily, at its point
+	// of use (applyProxyFlag and NewOrchestrator both call Proxy.Resolve and
+	// surface a descriptive error). Validating here would let a broken proxy in
+	// the config file block every command — even an attempt to override it with
+	// the --proxy flag, which runs after config load.
+
+	// Validate rate limit configuration
+	rl := &c.LLM.RateLimit
+	if rl.Enabled {
+		if rl.RequestsPerMinute < 0 {
+			invalid("llm.rate_limit.requests_per_minute", "must be >= 0, got %d", rl.RequestsPerMinute)
+		}
+		if rl.BurstSize < 0 {
+			invalid("llm.rate_limit.burst_size", "must be >= 0, got %d", rl.BurstSize)
+		}
+		if rl.MaxRetries < 0 {
+			invalid("llm.rate_limit.max_retries", "must be >= 0, got %d", rl.MaxRetries)
+		}
+		if rl.InitialDelay < 0 {
+			invalid("llm.rate_limit.initial_delay", "must be >= 0, got %s", rl.InitialDelay)
+		}
+		if rl.MaxDelay < 0 {
+			invalid("llm.rate_limit.max_delay", "must be >= 0, got %s", rl.MaxDelay)
+		}
+		if rl.Multiplier < 0 {
+			invalid("llm.rate_limit.multiplier", "must be >= 0, got %f", rl.Multiplier)
+		}
+		if rl.JitterFactor < 0 || rl.JitterFactor > 1.0 {
+			invalid("llm.rate_limit.jitter_factor", "must be between 0.0 and 1.0, got %f", rl.JitterFactor)
+		}
+
+		// Validate circuit breaker configuration
+		cb := &rl.CircuitBreaker
+		if cb.Enabled {
+			if cb.FailureThreshold < 1 {
+				invalid("llm.rate_limit.circuit_breaker.failure_threshold", "must be >= 1, got %d", cb.FailureThreshold)
+			}
+			if cb.SuccessThresholdHalfOpen < 1 {
+				invalid("llm.rate_limit.circuit_breaker.success_threshold_half_open", "must be >= 1, got %d", cb.SuccessThresholdHalfOpen)
+			}
+			if cb.ResetTimeout < time.Second {
+				invalid("llm.rate_limit.circuit_breaker.reset_timeout", "must be >= 1s, got %s", cb.ResetTimeout)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidationErrors returns the individual validation errors contained in err,
+// as returned by Validate or Load (possibly wrapped). It returns nil when err
+// holds none.
+func ValidationErrors(err error) []*domain.ValidationError {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *domain.ValidationError:
+		return []*domain.ValidationError{e}
+	case interface{ Unwrap() []error }:
+		var out []*domain.ValidationError
+		for _, inner := range e.Unwrap() {
+			out = append(out, ValidationErrors(inner)...)
+		}
+		return out
+	default:
+		return ValidationErrors(errors.Unwrap(err))
+	}
+}
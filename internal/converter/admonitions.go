@@ -0,0 +1,194 @@
+package converter
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	calloutAttr      = "data-repodocs-callout"
+	calloutTitleAttr = "data-repodocs-callout-title"
+)
+
+// admonitionContainerClasses are the class tokens that mark an admonition
+// container: MkDocs (admonition), Docusaurus (theme-admonition, admonition,
+// alert), Obsidian-style callouts and GitHub's rendered markdown alerts.
+var admonitionContainerClasses = map[string]bool{
+	"admonition":       true,
+	"theme-admonition": true,
+	"callout":          true,
+	"alert":            true,
+	"markdown-alert":   true,
+}
+
+// admonitionKindPrefixes are stripped from class tokens before looking them
+// up as an admonition kind, so admonition-tip, alert--success and
+// markdown-alert-note all name their kind.
+var admonitionKindPrefixes = []string{
+	"theme-admonition-",
+	"markdown-alert-",
+	"admonition-",
+	"callout-",
+	"alert--",
+	"alert-",
+	"is-",
+}
+
+// admonitionKinds extends the reStructuredText admonition table with the
+// kinds HTML themes use, mapping each to a GitHub callout type.
+var admonitionKinds = map[string]string{
+	"info":      "NOTE",
+	"abstract":  "NOTE",
+	"summary":   "NOTE",
+	"tldr":      "NOTE",
+	"todo":      "NOTE",
+	"example":   "NOTE",
+	"quote":     "NOTE",
+	"cite":      "NOTE",
+	"question":  "NOTE",
+	"faq":       "NOTE",
+	"help":      "NOTE",
+	"secondary": "NOTE",
+	"success":   "TIP",
+	"check":     "TIP",
+	"done":      "TIP",
+	"failure":   "WARNING",
+	"fail":      "WARNING",
+	"missing":   "WARNING",
+	"bug":       "WARNING",
+}
+
+// admonitionTitleSelector matches the title element of an admonition among
+// its children.
+const admonitionTitleSelector = "summary, .admonition-title, .admonition-heading, " +
+	"[class*='admonitionHeading'], .callout-title, .markdown-alert-title"
+
+// escapedCalloutRegex matches a callout marker whose brackets the markdown
+// converter escaped, along with a blank quote line following it.
+var escapedCalloutRegex = regexp.MustCompile(
+	`(?m)^((?:> ?)+)\\\[!(NOTE|TIP|IMPORTANT|WARNING|CAUTION)\\?\][ \t]*\n(?:(?:> ?)+\n)?`,
+)
+
+// PreserveAdmonitions turns admonition containers into blockquotes carrying
+// their GitHub callout type and custom title in data attributes, and drops
+// their title element. Like PreserveCodeLanguages it runs before Readability
+// and sanitization, which would otherwise strip the classes or remove the
+// containers; RestoreAdmonitions adds the callout marker back.
+func PreserveAdmonitions(sel *goquery.Selection) {
+	findWithRoot(sel, "div, aside, section, details").Each(func(_ int, s *goquery.Selection) {
+		kind, ok := admonitionKind(s)
+		if !ok {
+			return
+		}
+		calloutType := admonitionKinds[kind]
+		if calloutType == "" {
+			calloutType = admonitionTitle[kind]
+		}
+		if calloutType == "" {
+			calloutType = "NOTE"
+		}
+
+		titleSel := s.ChildrenFiltered(admonitionTitleSelector).First()
+		title := strings.Join(strings.Fields(titleSel.Text()), " ")
+		titleSel.Remove()
+		if strings.EqualFold(title, kind) || strings.EqualFold(title, calloutType) {
+			title = ""
+		}
+
+		// Content wrappers such as admonitionContent_x or callout-content
+		// would match the sanitizer's class filters.
+		s.Children().Each(func(_ int, child *goquery.Selection) {
+			if _, nested := admonitionKind(child); nested {
+				return
+			}
+			class, _ := child.Attr("class")
+			lower := strings.ToLower(class)
+			if strings.Contains(lower, "admonition") || strings.Contains(lower, "callout") || strings.Contains(lower, "alert") {
+				child.RemoveAttr("class")
+			}
+		})
+
+		node := s.Get(0)
+		node.Data = "blockquote"
+		node.DataAtom = atom.Blockquote
+		s.RemoveAttr("class")
+		s.RemoveAttr("open")
+		s.SetAttr(calloutAttr, calloutType)
+		if title != "" {
+			s.SetAttr(calloutTitleAttr, title)
+		}
+	})
+}
+
+// RestoreAdmonitions reads back the attributes set by PreserveAdmonitions
+// and starts each blockquote with its [!TYPE] marker, followed by its custom
+// title in bold.
+func RestoreAdmonitions(sel *goquery.Selection) {
+	findWithRoot(sel, "blockquote["+calloutAttr+"]").Each(func(_ int, quote *goquery.Selection) {
+		calloutType, _ := quote.Attr(calloutAttr)
+		title, _ := quote.Attr(calloutTitleAttr)
+		head := "<p>[!" + calloutType + "]</p>"
+		if title != "" {
+			head += "<p><strong>" + html.EscapeString(title) + "</strong></p>"
+		}
+		quote.PrependHtml(head)
+		quote.RemoveAttr(calloutAttr)
+		quote.RemoveAttr(calloutTitleAttr)
+	})
+}
+
+// UnescapeCalloutMarkers undoes the markdown converter's escaping of
+// callout markers (> \[!NOTE]) and joins each marker to the quoted line
+// after it, as GitHub expects.
+func UnescapeCalloutMarkers(markdown string) string {
+	return escapedCalloutRegex.ReplaceAllString(markdown, "${1}[!${2}]\n")
+}
+
+// admonitionKind reports whether s is an admonition container and, if so,
+// the kind named by its classes or data attributes, such as "tip".
+func admonitionKind(s *goquery.Selection) (string, bool) {
+	class, _ := s.Attr("class")
+	tokens := strings.Fields(strings.ToLower(class))
+
+	container := false
+	for _, token := range tokens {
+		if admonitionContainerClasses[token] {
+			container = true
+			break
+		}
+	}
+	if !container && goquery.NodeName(s) != "details" {
+		return "", false
+	}
+
+	for _, attr := range []string{"data-callout", "data-type"} {
+		if kind, ok := s.Attr(attr); ok && knownAdmonitionKind(strings.ToLower(kind)) {
+			return strings.ToLower(kind), true
+		}
+	}
+	for _, token := range tokens {
+		if admonitionContainerClasses[token] {
+			continue
+		}
+		for _, prefix := range admonitionKindPrefixes {
+			if kind, ok := strings.CutPrefix(token, prefix); ok {
+				token = kind
+				break
+			}
+		}
+		if knownAdmonitionKind(token) {
+			return token, true
+		}
+	}
+	// A collapsible MkDocs block is a <details> with its kind as class; any
+	// other <details> is not an admonition.
+	return "note", container
+}
+
+func knownAdmonitionKind(kind string) bool {
+	return kind != "admonition" && (admonitionKinds[kind] != "" || admonitionTitle[kind] != "")
+}
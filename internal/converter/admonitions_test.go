@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAdmonitionFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "tests", "fixtures", "admonitions", name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestPipeline_PreserveAdmonitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		selector string
+		want     []string
+	}{
+		{
+			name:    "mkdocs",
+			fixture: "mkdocs.html",
+			want: []string{
+				"> [!NOTE]\n> The file must be named `mkdocs.yml`.\n",
+				"> [!WARNING]\n> **Breaking change in 9.0**\n> \n> The `theme.palette` key was renamed.\n",
+				"> [!TIP]\n> **Reloading on save**\n> \n> Run `mkdocs serve` to rebuild on every change.\n",
+			},
+		},
+		{
+			name:     "docusaurus",
+			fixture:  "docusaurus.html",
+			selector: ".theme-doc-markdown",
+			want: []string{
+				"> [!TIP]\n> Use the **Fast Track** to get started in 5 minutes.\n",
+				"> [!WARNING]\n> **Node.js version**\n> \n> Node.js 18.0 or above is required.\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline(PipelineOptions{ContentSelector: tt.selector, PreserveAdmonitions: true})
+			doc, err := p.Convert(context.Background(), readAdmonitionFixture(t, tt.fixture), "https://example.com/docs/")
+			require.NoError(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, doc.Content, want)
+			}
+			assert.NotContains(t, doc.Content, `\[!`)
+		})
+	}
+}
+
+func TestPipeline_PreserveAdmonitions_Disabled(t *testing.T) {
+	doc, err := NewPipeline(PipelineOptions{}).
+		Convert(context.Background(), readAdmonitionFixture(t, "mkdocs.html"), "https://example.com/docs/")
+	require.NoError(t, err)
+	assert.NotContains(t, doc.Content, "[!")
+	assert.Contains(t, doc.Content, "The file must be named `mkdocs.yml`.")
+}
+
+func TestPipeline_PreserveAdmonitions_Nested(t *testing.T) {
+	html := `<html><body><article>
+		<p>Intro text for the guide with enough words to be kept as content.</p>
+		<div class="admonition danger"><p class="admonition-title">Danger</p>
+			<p>Outer body.</p>
+			<div class="callout" data-callout="info"><div class="callout-title">Details</div><div class="callout-content"><p>Inner body.</p></div></div>
+		</div>
+		<div class="alert alert-info"><p>Bootstrap alert.</p></div>
+	</article></body></html>`
+
+	doc, err := NewPipeline(PipelineOptions{ContentSelector: "article", PreserveAdmonitions: true}).
+		Convert(context.Background(), html, "https://example.com/guide")
+	require.NoError(t, err)
+	assert.Contains(t, doc.Content, "> [!WARNING]\n> Outer body.\n> \n> > [!NOTE]\n> > **Details**\n> > \n> > Inner body.")
+	assert.Contains(t, doc.Content, "> [!NOTE]\n> Bootstrap alert.")
+}
+
+func TestUnescapeCalloutMarkers(t *testing.T) {
+	in := "> \\[!NOTE]\n>\n> Body\n\n> > \\[!TIP]\n> >\n> > Inner\n\nText \\[!NOTE] stays\n"
+	want := "> [!NOTE]\n> Body\n\n> > [!TIP]\n> > Inner\n\nText \\[!NOTE] stays\n"
+	assert.Equal(t, want, UnescapeCalloutMarkers(in))
+}
@@ -1,6 +1,13 @@
 package converter
 
-import "strings"
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
 
 func IsMarkdownContent(contentType, url string) bool {
 	ct := strings.ToLower(contentType)
@@ -61,3 +68,144 @@ func IsHTMLContent(contentType string) bool {
 	return strings.Contains(ct, "text/html") ||
 		strings.Contains(ct, "application/xhtml")
 }
+
+// Resolved content types returned by ResolveContentType and
+// NormalizeContentType.
+const (
+	ContentTypeHTML     = "text/html"
+	ContentTypeMarkdown = "text/markdown"
+	ContentTypePlain    = "text/plain"
+)
+
+// sniffLen is how much of a body is inspected when sniffing its type.
+const sniffLen = 2048
+
+// genericContentTypes are media types that say nothing reliable about the
+// body, so the URL extension and the body itself are inspected instead.
+var genericContentTypes = map[string]bool{
+	"":                           true,
+	"text/plain":                 true,
+	"application/octet-stream":   true,
+	"binary/octet-stream":        true,
+	"application/unknown":        true,
+	"application/x-unknown":      true,
+	"application/download":       true,
+	"application/force-download": true,
+}
+
+var (
+	markdownHeadingRe = regexp.MustCompile(`(?m)^#{1,6}[ \t]+\S`)
+	markdownFenceRe   = regexp.MustCompile("(?m)^(```|~~~)")
+	markdownLinkRe    = regexp.MustCompile(`\[[^\]\n]+\]\([^)\s]+\)`)
+	markdownListRe    = regexp.MustCompile(`(?m)^[ \t]*([-*+]|\d+\.)[ \t]+\S`)
+)
+
+// NormalizeContentType maps a user supplied content type override to a media
+// type. It accepts the shorthands "html", "markdown" (or "md") and "text" (or
+// "txt"), as well as any media type such as "text/markdown".
+func NormalizeContentType(value string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(value)); v {
+	case "":
+		return "", nil
+	case "html", "htm":
+		return ContentTypeHTML, nil
+	case "markdown", "md":
+		return ContentTypeMarkdown, nil
+	case "text", "txt", "plain":
+		return ContentTypePlain, nil
+	default:
+		if strings.Contains(v, "/") {
+			return v, nil
+		}
+		return "", fmt.Errorf("unsupported content type %q (use html, markdown, text or a media type)", value)
+	}
+}
+
+// ResolveContentType returns the content type to process a response with.
+// A specific Content-Type header is trusted as is. A generic one (missing,
+// text/plain or application/octet-stream) is resolved from the URL extension
+// and then by sniffing the body for HTML or markdown. When nothing can be
+// decided the header is returned unchanged.
+func ResolveContentType(header, url string, body []byte) string {
+	mediaType := strings.ToLower(strings.TrimSpace(header))
+	if idx := strings.Index(mediaType, ";"); idx != -1 {
+		mediaType = strings.TrimSpace(mediaType[:idx])
+	}
+	if !genericContentTypes[mediaType] {
+		return header
+	}
+
+	switch urlExtension(url) {
+	case ".md", ".mdx", ".markdown", ".mdown":
+		return ContentTypeMarkdown
+	case ".html", ".htm", ".xhtml":
+		return ContentTypeHTML
+	case ".txt":
+		return ContentTypePlain
+	}
+
+	head := bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return header
+	}
+	if looksLikeHTML(head) {
+		return ContentTypeHTML
+	}
+	// text/plain stays plain unless the body is HTML: plain text files such
+	// as llms.txt often contain markdown-ish lines by design.
+	if mediaType == "text/plain" {
+		return header
+	}
+	if looksLikeMarkdown(head) {
+		return ContentTypeMarkdown
+	}
+	if mediaType != "" && utf8.Valid(head) && len(bytes.TrimSpace(head)) > 0 {
+		return ContentTypePlain
+	}
+	return header
+}
+
+func urlExtension(url string) string {
+	lowerURL := strings.ToLower(url)
+	if idx := strings.IndexAny(lowerURL, "?#"); idx != -1 {
+		lowerURL = lowerURL[:idx]
+	}
+	if idx := strings.Index(lowerURL, "://"); idx != -1 {
+		lowerURL = lowerURL[idx+3:]
+		if slash := strings.Index(lowerURL, "/"); slash != -1 {
+			lowerURL = lowerURL[slash:]
+		} else {
+			return ""
+		}
+	}
+	return path.Ext(lowerURL)
+}
+
+func looksLikeHTML(head []byte) bool {
+	lower := bytes.ToLower(bytes.TrimSpace(head))
+	if bytes.HasPrefix(lower, []byte("<!doctype html")) {
+		return true
+	}
+	for _, tag := range []string{"<html", "<head", "<body"} {
+		if bytes.Contains(lower, []byte(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeMarkdown reports whether text has markdown structure: a heading,
+// a code fence or front matter, or at least two links or list items.
+func looksLikeMarkdown(head []byte) bool {
+	trimmed := bytes.TrimSpace(head)
+	if bytes.HasPrefix(trimmed, []byte("---\n")) || bytes.HasPrefix(trimmed, []byte("---\r\n")) {
+		return true
+	}
+	if markdownHeadingRe.Match(head) || markdownFenceRe.Match(head) {
+		return true
+	}
+	return len(markdownLinkRe.FindAllIndex(head, 2))+len(markdownListRe.FindAllIndex(head, 2)) >= 2
+}
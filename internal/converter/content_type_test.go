@@ -147,3 +147,54 @@ func TestIsHTMLContent(t *testing.T) {
 		})
 	}
 }
+
+// TestResolveContentType tests content type resolution for generic headers
+func TestResolveContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		url         string
+		body        string
+		expected    string
+	}{
+		{"specific header trusted", "text/html; charset=utf-8", "https://example.com/a", "# Heading", "text/html; charset=utf-8"},
+		{"markdown as octet-stream", "application/octet-stream", "https://example.com/guide", "# Guide\n\nText with a [link](/x).", ContentTypeMarkdown},
+		{"markdown by extension", "application/octet-stream", "https://example.com/guide.md?raw=1", "plain words", ContentTypeMarkdown},
+		{"markdown with front matter", "", "https://example.com/guide", "---\ntitle: Guide\n---\nBody", ContentTypeMarkdown},
+		{"markdown lists and links", "binary/octet-stream", "https://example.com/x", "- [One](/one)\n- [Two](/two)\n", ContentTypeMarkdown},
+		{"html as text/plain", "text/plain; charset=utf-8", "https://example.com/page", "\n  <!DOCTYPE html><html><body>Hi</body></html>", ContentTypeHTML},
+		{"html as octet-stream", "application/octet-stream", "https://example.com/page", "<html><head><title>T</title></head></html>", ContentTypeHTML},
+		{"html by extension", "text/plain", "https://example.com/page.html", "Hello", ContentTypeHTML},
+		{"plain text stays plain", "text/plain", "https://example.com/llms", "# Project\n\n- [Docs](/docs)", "text/plain"},
+		{"text as octet-stream", "application/octet-stream", "https://example.com/notes", "just some notes", ContentTypePlain},
+		{"binary keeps header", "application/octet-stream", "https://example.com/file", "\x00\x01\x02", "application/octet-stream"},
+		{"empty header undecided", "", "https://example.com/", "just words", ""},
+		{"host is not an extension", "", "https://example.md", "just words", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ResolveContentType(tt.contentType, tt.url, []byte(tt.body)))
+		})
+	}
+}
+
+// TestNormalizeContentType tests parsing of content type overrides
+func TestNormalizeContentType(t *testing.T) {
+	for input, expected := range map[string]string{
+		"":            "",
+		"html":        ContentTypeHTML,
+		"Markdown":    ContentTypeMarkdown,
+		"md":          ContentTypeMarkdown,
+		"text":        ContentTypePlain,
+		"text/x-rst":  "text/x-rst",
+		" TEXT/HTML ": "text/html",
+	} {
+		got, err := NormalizeContentType(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, got, input)
+	}
+
+	_, err := NormalizeContentType("pdf")
+	assert.Error(t, err)
+}
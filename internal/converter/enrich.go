@@ -0,0 +1,186 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+const (
+	// wordsPerMinute is the reading speed used for reading-time estimates.
+	wordsPerMinute = 200
+	// maxProseWords caps how much prose DetectLanguage samples.
+	maxProseWords = 1000
+	// minProseWords is the least prose DetectLanguage needs to guess.
+	minProseWords = 10
+	// minStopwordHits is the least stopword evidence a guess needs.
+	minStopwordHits = 3
+)
+
+var (
+	inlineCodeRegex = regexp.MustCompile("`[^`\n]*`")
+	linkTargetRegex = regexp.MustCompile(`\]\([^)]*\)`)
+	bareURLRegex    = regexp.MustCompile(`https?://\S+`)
+)
+
+// stopwords holds frequent function words per ISO 639-1 code. Lists favour
+// words that are distinctive for the language so close relatives (es/pt,
+// nl/de) separate on ordinary prose.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "with", "this", "that", "it", "for", "you", "be", "on", "can", "not"},
+	"es": {"el", "los", "las", "y", "es", "una", "por", "con", "del", "para", "como", "está", "pero", "más", "su", "se"},
+	"pt": {"o", "os", "e", "é", "um", "uma", "com", "não", "do", "da", "dos", "das", "em", "para", "mais", "você"},
+	"fr": {"le", "les", "et", "est", "une", "des", "du", "dans", "pour", "avec", "pas", "sur", "vous", "ce", "qui", "que"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "ein", "eine", "zu", "den", "auf", "für", "sie", "sich", "werden"},
+	"it": {"il", "gli", "della", "di", "che", "è", "per", "con", "non", "una", "sono", "del", "nel", "alla", "questo", "anche"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "met", "voor", "zijn", "op", "te", "ook", "wordt", "deze"},
+}
+
+// stopwordLanguages maps each stopword to the languages listing it.
+var stopwordLanguages = func() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, word := range words {
+			index[word] = append(index[word], lang)
+		}
+	}
+	return index
+}()
+
+// scriptLanguages maps scripts that identify a language on their own.
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Greek, "el"},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+}
+
+// Enrich fills the derived reading-time and language fields of doc. It is
+// cheap and has no side effects beyond doc.
+func Enrich(doc *domain.Document) {
+	if doc == nil {
+		return
+	}
+	doc.ReadingTime = ReadingTime(doc.WordCount)
+	doc.Language = DetectLanguage(doc.Content)
+}
+
+// ReadingTime estimates whole minutes needed to read words, rounding up so
+// any non-empty document takes at least one minute.
+func ReadingTime(words int) int {
+	if words <= 0 {
+		return 0
+	}
+	return (words + wordsPerMinute - 1) / wordsPerMinute
+}
+
+// DetectLanguage guesses the ISO 639-1 code of the markdown's prose. Fenced
+// and indented code, inline code and URLs are dropped first so code-heavy
+// pages are judged on their explanations only. It returns "" when there is
+// too little prose or no clear winner.
+func DetectLanguage(markdown string) string {
+	words := sampleProse(markdown)
+	if lang := dominantScript(words); lang != "" {
+		return lang
+	}
+	if len(words) < minProseWords {
+		return ""
+	}
+
+	scores := make(map[string]int)
+	for _, word := range words {
+		for _, lang := range stopwordLanguages[word] {
+			scores[lang]++
+		}
+	}
+
+	best, bestScore, tied := "", 0, false
+	for lang, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = lang, score, false
+		case score == bestScore:
+			tied = true
+		}
+	}
+	if tied || bestScore < minStopwordHits {
+		return ""
+	}
+	return best
+}
+
+// sampleProse returns up to maxProseWords lowercased words from the prose of
+// markdown, skipping code blocks, inline code and URLs.
+func sampleProse(markdown string) []string {
+	var words []string
+	var fence string
+	for _, line := range strings.Split(markdown, "\n") {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := openingFence(line); marker != "" {
+			fence = marker
+			continue
+		}
+		if strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		line = inlineCodeRegex.ReplaceAllString(line, " ")
+		line = linkTargetRegex.ReplaceAllString(line, "]")
+		line = bareURLRegex.ReplaceAllString(line, " ")
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool { return !unicode.IsLetter(r) }) {
+			words = append(words, strings.ToLower(field))
+			if len(words) == maxProseWords {
+				return words
+			}
+		}
+	}
+	return words
+}
+
+// dominantScript returns the language of a non-Latin script that covers most
+// letters in words, or "". Letters are counted rather than words because
+// scripts such as Han do not separate words with spaces.
+func dominantScript(words []string) string {
+	counts := make(map[string]int)
+	total := 0
+	for _, word := range words {
+		for _, r := range word {
+			total++
+			for _, script := range scriptLanguages {
+				if unicode.Is(script.table, r) {
+					counts[script.lang]++
+					break
+				}
+			}
+		}
+	}
+	if total < minProseWords {
+		return ""
+	}
+	// Japanese text mixes kana with Han, so any kana marks it as Japanese.
+	if counts["ja"] > 0 && counts["ja"]+counts["zh"] > total/2 {
+		return "ja"
+	}
+	for _, script := range scriptLanguages {
+		if counts[script.lang] > total/2 {
+			return script.lang
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadingTime(t *testing.T) {
+	tests := []struct {
+		words int
+		want  int
+	}{
+		{words: 0, want: 0},
+		{words: 1, want: 1},
+		{words: 200, want: 1},
+		{words: 201, want: 2},
+		{words: 1000, want: 5},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ReadingTime(tt.words), "words=%d", tt.words)
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "english",
+			content: "# Install\n\nThis guide explains how to install the tool and configure it for your project. It is quick and you can start with the defaults.",
+			want:    "en",
+		},
+		{
+			name:    "spanish",
+			content: "# Instalación\n\nEsta guía explica cómo instalar la herramienta y configurarla para el proyecto. Es rápida y se puede empezar con los valores por defecto.",
+			want:    "es",
+		},
+		{
+			name:    "portuguese",
+			content: "# Instalação\n\nEste guia explica como instalar a ferramenta e configurá-la para o seu projeto. É rápido e você pode começar com os valores padrão.",
+			want:    "pt",
+		},
+		{
+			name:    "german",
+			content: "# Installation\n\nDiese Anleitung erklärt, wie man das Werkzeug installiert und für das eigene Projekt einrichtet. Es ist nicht schwer und die Standardwerte sind ein guter Anfang.",
+			want:    "de",
+		},
+		{
+			name:    "french",
+			content: "# Installation\n\nCe guide explique comment installer l'outil et le configurer pour votre projet. C'est rapide et vous pouvez commencer avec les valeurs par défaut.",
+			want:    "fr",
+		},
+		{
+			name:    "japanese",
+			content: "# インストール\n\nこのガイドでは、ツールをインストールしてプロジェクト用に設定する方法を説明します。",
+			want:    "ja",
+		},
+		{
+			name:    "russian",
+			content: "# Установка\n\nЭто руководство объясняет, как установить инструмент и настроить его для вашего проекта.",
+			want:    "ru",
+		},
+		{
+			name:    "too little prose",
+			content: "# API\n\nSee below.",
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectLanguage(tt.content))
+		})
+	}
+}
+
+func TestDetectLanguage_CodeHeavy(t *testing.T) {
+	// German-looking identifiers inside code must not outvote the prose.
+	code := "```go\n" + strings.Repeat("die := der(das, und, ist, nicht, mit, ein)\n", 50) + "```\n"
+	content := "# Usage\n\nCall the function with the values you need and check the error it returns.\n\n" +
+		code + "\n    der die das und ist nicht mit\n\nUse `die der das` only in tests, see https://example.com/der/die/das.\n"
+	assert.Equal(t, "en", DetectLanguage(content))
+
+	assert.Equal(t, "", DetectLanguage(code), "code-only documents have no language")
+}
+
+func TestEnrich(t *testing.T) {
+	doc := &domain.Document{
+		WordCount: 450,
+		Content:   "This page is an overview of the project and the way it is built for you.",
+	}
+	Enrich(doc)
+	assert.Equal(t, 3, doc.ReadingTime)
+	assert.Equal(t, "en", doc.Language)
+
+	Enrich(nil)
+}
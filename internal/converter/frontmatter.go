@@ -0,0 +1,144 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterSkipTags lists elements whose text never counts as page content
+// when looking for leaked front-matter.
+var frontMatterSkipTags = map[string]bool{
+	"head":     true,
+	"title":    true,
+	"script":   true,
+	"style":    true,
+	"noscript": true,
+	"template": true,
+}
+
+// StripFrontMatter removes a leading `---`-delimited YAML block that static
+// site generators (Jekyll, Hugo) sometimes leak into the rendered page text.
+// Only the first non-blank text node of sel is inspected, and the block is
+// removed only when it parses as a non-empty YAML mapping, so a leading
+// horizontal rule is left alone. The parsed keys are returned; nil means
+// nothing was stripped.
+func StripFrontMatter(sel *goquery.Selection) map[string]any {
+	if sel == nil {
+		return nil
+	}
+
+	var first *html.Node
+	for _, root := range sel.Nodes {
+		if first = firstTextNode(root); first != nil {
+			break
+		}
+	}
+	if first == nil {
+		return nil
+	}
+
+	fields, rest, ok := SplitFrontMatter(first.Data)
+	if !ok {
+		return nil
+	}
+
+	// The markdown converter cannot handle empty text nodes, so drop the node
+	// entirely when nothing but the front-matter was in it.
+	if strings.TrimSpace(rest) == "" && first.Parent != nil {
+		first.Parent.RemoveChild(first)
+	} else {
+		first.Data = rest
+	}
+	return fields
+}
+
+// firstTextNode returns the first text node under n that contains
+// non-whitespace characters, skipping non-content elements.
+func firstTextNode(n *html.Node) *html.Node {
+	switch n.Type {
+	case html.TextNode:
+		if strings.TrimSpace(n.Data) != "" {
+			return n
+		}
+		return nil
+	case html.ElementNode:
+		if frontMatterSkipTags[n.Data] {
+			return nil
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := firstTextNode(c); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// SplitFrontMatter separates a leading YAML front-matter block from text.
+// It returns the parsed fields, the remaining text, and whether a block was found.
+func SplitFrontMatter(text string) (map[string]any, string, bool) {
+	trimmed := strings.TrimLeft(text, " \t\r\n")
+	lines := strings.Split(trimmed, "\n")
+	if len(lines) < 3 || strings.TrimSpace(lines[0]) != "---" {
+		return nil, text, false
+	}
+
+	closingIdx := -1
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" || line == "..." {
+			closingIdx = i
+			break
+		}
+	}
+	if closingIdx < 2 {
+		return nil, text, false
+	}
+
+	var fields map[string]any
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:closingIdx], "\n")), &fields); err != nil {
+		return nil, text, false
+	}
+	if len(fields) == 0 {
+		return nil, text, false
+	}
+
+	rest := strings.Join(lines[closingIdx+1:], "\n")
+	return fields, rest, true
+}
+
+// FrontMatterMetadata keeps the requested keys from parsed front-matter as
+// flat string metadata. Lists are joined with ", ".
+func FrontMatterMetadata(fields map[string]any, keys []string) map[string]string {
+	if len(fields) == 0 || len(keys) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]string)
+	for _, key := range keys {
+		value, ok := fields[key]
+		if !ok || value == nil {
+			continue
+		}
+		switch v := value.(type) {
+		case []any:
+			parts := make([]string, 0, len(v))
+			for _, item := range v {
+				parts = append(parts, fmt.Sprint(item))
+			}
+			metadata[key] = strings.Join(parts, ", ")
+		default:
+			metadata[key] = fmt.Sprint(v)
+		}
+	}
+
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
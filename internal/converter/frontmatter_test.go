@@ -0,0 +1,133 @@
+package converter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const frontMatterPage = `<!DOCTYPE html>
+<html>
+<head><title>Getting Started</title></head>
+<body>
+<article>
+<p>---
+title: Getting Started
+layout: post
+tags:
+  - intro
+  - setup
+---</p>
+<h1>Getting Started</h1>
+<p>Install the tool and run your first extraction in a few minutes.</p>
+</article>
+</body>
+</html>`
+
+func TestPipeline_Convert_StripFrontMatter(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         PipelineOptions
+		wantLeak     bool
+		wantMetadata map[string]string
+	}{
+		{
+			name:     "disabled by default",
+			opts:     PipelineOptions{},
+			wantLeak: true,
+		},
+		{
+			name:     "stripped with readability",
+			opts:     PipelineOptions{StripFrontMatter: true},
+			wantLeak: false,
+		},
+		{
+			name:     "stripped with content selector",
+			opts:     PipelineOptions{StripFrontMatter: true, ContentSelector: "article"},
+			wantLeak: false,
+		},
+		{
+			name: "preserves selected keys",
+			opts: PipelineOptions{
+				StripFrontMatter: true,
+				ContentSelector:  "article",
+				FrontMatterKeys:  []string{"layout", "tags", "missing"},
+			},
+			wantLeak:     false,
+			wantMetadata: map[string]string{"layout": "post", "tags": "intro, setup"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := NewPipeline(tt.opts).Convert(context.Background(), frontMatterPage, "https://example.com/start")
+			require.NoError(t, err)
+
+			assert.Contains(t, doc.Content, "Install the tool")
+			assert.Equal(t, tt.wantLeak, strings.Contains(doc.Content, "layout: post"))
+			if !tt.wantLeak {
+				assert.False(t, strings.HasPrefix(doc.Content, "---"), "content should not start with front-matter: %q", doc.Content)
+			}
+			assert.Equal(t, tt.wantMetadata, doc.Metadata)
+		})
+	}
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantOK   bool
+		wantRest string
+	}{
+		{
+			name:     "yaml block",
+			text:     "\n---\ntitle: Hello\n---\nBody",
+			wantOK:   true,
+			wantRest: "Body",
+		},
+		{
+			name:     "dots terminator",
+			text:     "---\ntitle: Hello\n...\n",
+			wantOK:   true,
+			wantRest: "",
+		},
+		{
+			name:   "horizontal rule",
+			text:   "---\nJust a paragraph after a rule\n---",
+			wantOK: false,
+		},
+		{
+			name:   "unterminated",
+			text:   "---\ntitle: Hello\nBody",
+			wantOK: false,
+		},
+		{
+			name:   "empty block",
+			text:   "---\n---\nBody",
+			wantOK: false,
+		},
+		{
+			name:   "not at start",
+			text:   "Intro\n---\ntitle: Hello\n---",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, rest, ok := SplitFrontMatter(tt.text)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.NotEmpty(t, fields)
+				assert.Equal(t, tt.wantRest, rest)
+			} else {
+				assert.Nil(t, fields)
+				assert.Equal(t, tt.text, rest)
+			}
+		})
+	}
+}
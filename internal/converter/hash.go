@@ -0,0 +1,52 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"lukechampine.com/blake3"
+)
+
+// HashAlgorithm selects the digest used for document content hashes
+type HashAlgorithm string
+
+const (
+	// HashSHA256 hashes content with SHA-256 (the default)
+	HashSHA256 HashAlgorithm = "sha256"
+	// HashBLAKE3 hashes content with BLAKE3-256
+	HashBLAKE3 HashAlgorithm = "blake3"
+)
+
+// ParseHashAlgorithm validates a hash algorithm name; an empty value selects
+// HashSHA256.
+func ParseHashAlgorithm(value string) (HashAlgorithm, error) {
+	switch algo := HashAlgorithm(strings.ToLower(strings.TrimSpace(value))); algo {
+	case "":
+		return HashSHA256, nil
+	case HashSHA256, HashBLAKE3:
+		return algo, nil
+	default:
+		return "", fmt.Errorf("invalid hash algorithm %q (use sha256 or blake3)", value)
+	}
+}
+
+// ContentHash returns the SHA-256 hex digest of content after normalizing
+// line endings and whitespace (see NormalizeWhitespace), so pages that differ
+// only cosmetically hash identically across runs.
+func ContentHash(content string) string {
+	return ContentHashWith(content, HashSHA256)
+}
+
+// ContentHashWith is ContentHash with the digest chosen by algo. Unknown
+// algorithms fall back to SHA-256.
+func ContentHashWith(content string, algo HashAlgorithm) string {
+	normalized := []byte(NormalizeWhitespace(strings.ReplaceAll(content, "\r\n", "\n")))
+	if algo == HashBLAKE3 {
+		hash := blake3.Sum256(normalized)
+		return hex.EncodeToString(hash[:])
+	}
+	hash := sha256.Sum256(normalized)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,58 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentHash(t *testing.T) {
+	base := ContentHash("# Title\n\nSome text.\n")
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "CRLF line endings", content: "# Title\r\n\r\nSome text.\r\n"},
+		{name: "trailing whitespace", content: "# Title  \n\nSome text.\t\n"},
+		{name: "extra blank lines", content: "\n# Title\n\n\n\nSome text.\n\n\n"},
+		{name: "missing final newline", content: "# Title\n\nSome text."},
+		{name: "non-breaking space", content: "# Title\n\nSome text.\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, base, ContentHash(tt.content))
+		})
+	}
+
+	assert.NotEqual(t, base, ContentHash("# Title\n\nOther text.\n"))
+	assert.NotEqual(t, ContentHash("```\na  \n```\n"), ContentHash("```\na\n```\n"),
+		"code blocks are hashed byte-for-byte")
+}
+
+func TestParseHashAlgorithm(t *testing.T) {
+	for input, want := range map[string]HashAlgorithm{
+		"":         HashSHA256,
+		"sha256":   HashSHA256,
+		" BLAKE3 ": HashBLAKE3,
+	} {
+		got, err := ParseHashAlgorithm(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseHashAlgorithm("md5")
+	assert.Error(t, err)
+}
+
+func TestContentHashWith(t *testing.T) {
+	content := "# Title\n\nSome text.\n"
+
+	assert.Equal(t, ContentHash(content), ContentHashWith(content, HashSHA256))
+
+	blake := ContentHashWith(content, HashBLAKE3)
+	assert.Len(t, blake, 64)
+	assert.NotEqual(t, ContentHash(content), blake)
+	assert.Equal(t, blake, ContentHashWith("# Title\r\n\r\nSome text.", HashBLAKE3),
+		"normalization applies to every algorithm")
+}
@@ -0,0 +1,68 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxHeadingShift is the largest heading shift, in either direction, that
+// can still move a heading between the six markdown levels.
+const MaxHeadingShift = 5
+
+// ValidateHeadingShift checks a heading shift for ShiftHeadings.
+func ValidateHeadingShift(shift int) error {
+	if shift < -MaxHeadingShift || shift > MaxHeadingShift {
+		return fmt.Errorf("invalid heading shift %d (use -%d to %d)", shift, MaxHeadingShift, MaxHeadingShift)
+	}
+	return nil
+}
+
+// ShiftHeadings moves every ATX heading in markdown by shift levels: a
+// positive shift demotes (# becomes ##), a negative one promotes. Levels are
+// clamped to 1 through 6, so no heading goes past ###### or above #. Fenced
+// code blocks are left untouched.
+func ShiftHeadings(markdown string, shift int) string {
+	if shift == 0 {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	var fence string
+	for i, line := range lines {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := openingFence(line); marker != "" {
+			fence = marker
+			continue
+		}
+		lines[i] = shiftHeading(line, shift)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// shiftHeading shifts line by shift levels if it is an ATX heading, keeping
+// its indentation and text.
+func shiftHeading(line string, shift int) string {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := len(line) - len(trimmed)
+	if indent > 3 {
+		return line
+	}
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return line
+	}
+	if rest := trimmed[level:]; rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return line
+	}
+
+	newLevel := min(max(level+shift, 1), 6)
+	return line[:indent] + strings.Repeat("#", newLevel) + trimmed[level:]
+}
@@ -0,0 +1,103 @@
+package converter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShiftHeadings(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		shift int
+		want  string
+	}{
+		{
+			name:  "no shift",
+			input: "# Title\n\n## Section\n",
+			shift: 0,
+			want:  "# Title\n\n## Section\n",
+		},
+		{
+			name:  "demote",
+			input: "# Title\n\nText\n\n## Section\n",
+			shift: 1,
+			want:  "## Title\n\nText\n\n### Section\n",
+		},
+		{
+			name:  "promote",
+			input: "### Deep\n\n#### Deeper\n",
+			shift: -2,
+			want:  "# Deep\n\n## Deeper\n",
+		},
+		{
+			name:  "clamped at six",
+			input: "##### Five\n###### Six\n",
+			shift: 3,
+			want:  "###### Five\n###### Six\n",
+		},
+		{
+			name:  "clamped at one",
+			input: "## Two\n",
+			shift: -4,
+			want:  "# Two\n",
+		},
+		{
+			name:  "code fences untouched",
+			input: "# Title\n\n```bash\n# comment\n```\n\n~~~\n## not a heading\n~~~\n",
+			shift: 1,
+			want:  "## Title\n\n```bash\n# comment\n```\n\n~~~\n## not a heading\n~~~\n",
+		},
+		{
+			name:  "not headings",
+			input: "#hashtag\n####### seven\n    # indented code\n",
+			shift: 1,
+			want:  "#hashtag\n####### seven\n    # indented code\n",
+		},
+		{
+			name:  "indented and empty headings",
+			input: "  ## Indented\n#\n",
+			shift: 1,
+			want:  "  ### Indented\n##\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ShiftHeadings(tt.input, tt.shift))
+		})
+	}
+}
+
+func TestValidateHeadingShift(t *testing.T) {
+	assert.NoError(t, ValidateHeadingShift(0))
+	assert.NoError(t, ValidateHeadingShift(-5))
+	assert.NoError(t, ValidateHeadingShift(5))
+	assert.ErrorContains(t, ValidateHeadingShift(6), "invalid heading shift 6")
+	assert.Error(t, ValidateHeadingShift(-6))
+}
+
+func TestPipeline_HeadingShift(t *testing.T) {
+	html := `<html><body><article>
+		<h1>Guide</h1>
+		<p>Intro text for the guide with enough words to be kept as content.</p>
+		<h2>Install</h2>
+		<p>Run the installer and follow the prompts until it finishes.</p>
+	</article></body></html>`
+
+	doc, err := NewPipeline(PipelineOptions{HeadingShift: 1}).
+		Convert(context.Background(), html, "https://example.com/guide")
+	require.NoError(t, err)
+	assert.Contains(t, doc.Content, "### Install")
+	assert.NotContains(t, doc.Content, "\n## Install")
+	assert.Contains(t, doc.Headers["h2"], "Install", "headers keep the source levels")
+
+	reader := NewPipeline(PipelineOptions{HeadingShift: 1}).MarkdownReader()
+	mdDoc, err := reader.Read("# Title\n\n## Section\n\nBody\n", "https://example.com/a.md")
+	require.NoError(t, err)
+	assert.Equal(t, "Title", mdDoc.Title)
+	assert.Equal(t, "## Title\n\n### Section\n\nBody", mdDoc.Content)
+	assert.Equal(t, []string{"Section"}, mdDoc.Headers["h2"])
+}
@@ -0,0 +1,142 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ImageHandling selects what happens to images in converted markdown.
+type ImageHandling string
+
+const (
+	// ImageHandlingKeep leaves image references untouched (the default).
+	ImageHandlingKeep ImageHandling = "keep"
+	// ImageHandlingDrop removes images entirely.
+	ImageHandlingDrop ImageHandling = "drop"
+	// ImageHandlingAltText replaces each image with its alt text.
+	ImageHandlingAltText ImageHandling = "alt-text"
+)
+
+var (
+	// inlineImageRe matches ![alt](src "title") and ![alt][ref].
+	inlineImageRe = regexp.MustCompile(`!\[((?:[^\[\]]|\[[^\[\]]*\])*)\](?:\([^()\s]*(?:\([^()\s]*\))?[^()]*\)|\[[^\]]*\])`)
+	htmlImageRe   = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	htmlAltRe     = regexp.MustCompile(`(?i)\balt\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	emptyLinkRe   = regexp.MustCompile(`\[\s*\]\([^()]*\)`)
+)
+
+// ParseImageHandling parses an image handling mode. Empty means keep, and
+// "alt" is accepted as a short form of "alt-text".
+func ParseImageHandling(value string) (ImageHandling, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", string(ImageHandlingKeep):
+		return ImageHandlingKeep, nil
+	case string(ImageHandlingDrop):
+		return ImageHandlingDrop, nil
+	case "alt", string(ImageHandlingAltText):
+		return ImageHandlingAltText, nil
+	default:
+		return "", fmt.Errorf("invalid image handling %q (use keep, drop or alt)", value)
+	}
+}
+
+// ApplyImageHandling drops images from markdown or replaces them with their
+// alt text, depending on mode. Fenced code blocks and inline code spans are
+// left untouched, and links that only wrapped a dropped image are removed.
+func ApplyImageHandling(markdown string, mode ImageHandling) string {
+	if mode == "" || mode == ImageHandlingKeep {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	var fence string
+	for i, line := range lines {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := openingFence(line); marker != "" {
+			fence = marker
+			continue
+		}
+		lines[i] = mapOutsideInlineCode(line, func(text string) string {
+			return replaceImages(text, mode)
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+func replaceImages(text string, mode ImageHandling) string {
+	if !strings.Contains(text, "![") && !strings.Contains(strings.ToLower(text), "<img") {
+		return text
+	}
+
+	text = inlineImageRe.ReplaceAllStringFunc(text, func(img string) string {
+		if mode == ImageHandlingDrop {
+			return ""
+		}
+		return strings.TrimSpace(inlineImageRe.FindStringSubmatch(img)[1])
+	})
+	text = htmlImageRe.ReplaceAllStringFunc(text, func(img string) string {
+		if mode == ImageHandlingDrop {
+			return ""
+		}
+		if m := htmlAltRe.FindStringSubmatch(img); m != nil {
+			return strings.TrimSpace(m[1] + m[2])
+		}
+		return ""
+	})
+	return emptyLinkRe.ReplaceAllString(text, "")
+}
+
+// mapOutsideInlineCode applies fn to the parts of line that are not inside
+// backtick code spans. A span closes at the next backtick run of the same
+// length; an unmatched run is literal text.
+func mapOutsideInlineCode(line string, fn func(string) string) string {
+	if !strings.Contains(line, "`") {
+		return fn(line)
+	}
+
+	var b strings.Builder
+	start, i := 0, 0
+	for i < len(line) {
+		if line[i] != '`' {
+			i++
+			continue
+		}
+		n := backtickRun(line, i)
+		closeAt := -1
+		for j := i + n; j < len(line); {
+			if line[j] != '`' {
+				j++
+				continue
+			}
+			m := backtickRun(line, j)
+			if m == n {
+				closeAt = j
+				break
+			}
+			j += m
+		}
+		if closeAt < 0 {
+			i += n
+			continue
+		}
+		b.WriteString(fn(line[start:i]))
+		b.WriteString(line[i : closeAt+n])
+		start, i = closeAt+n, closeAt+n
+	}
+	b.WriteString(fn(line[start:]))
+	return b.String()
+}
+
+func backtickRun(line string, i int) int {
+	n := 0
+	for i+n < len(line) && line[i+n] == '`' {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,117 @@
+package converter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyImageHandling(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		drop  string
+		alt   string
+	}{
+		{
+			name:  "inline image",
+			input: "See ![Architecture diagram](/img/arch.png \"Arch\") below.",
+			drop:  "See  below.",
+			alt:   "See Architecture diagram below.",
+		},
+		{
+			name:  "image without alt",
+			input: "![](/img/spacer.gif)Text",
+			drop:  "Text",
+			alt:   "Text",
+		},
+		{
+			name:  "linked image",
+			input: "[![Build status](https://ci/badge.svg)](https://ci/job) passing",
+			drop:  " passing",
+			alt:   "[Build status](https://ci/job) passing",
+		},
+		{
+			name:  "reference image",
+			input: "Logo: ![Logo][logo]",
+			drop:  "Logo: ",
+			alt:   "Logo: Logo",
+		},
+		{
+			name:  "html image",
+			input: `Icon <img src="i.png" alt="Warning icon"> here`,
+			drop:  "Icon  here",
+			alt:   "Icon Warning icon here",
+		},
+		{
+			name:  "inline code untouched",
+			input: "Use `![alt](src)` syntax, not ![x](y.png)",
+			drop:  "Use `![alt](src)` syntax, not ",
+			alt:   "Use `![alt](src)` syntax, not x",
+		},
+		{
+			name:  "fenced code untouched",
+			input: "```md\n![alt](src)\n```\n![x](y.png)",
+			drop:  "```md\n![alt](src)\n```\n",
+			alt:   "```md\n![alt](src)\n```\nx",
+		},
+		{
+			name:  "regular links kept",
+			input: "[Guide](/guide) and [![](a.png)](/b)",
+			drop:  "[Guide](/guide) and ",
+			alt:   "[Guide](/guide) and ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.input, ApplyImageHandling(tt.input, ImageHandlingKeep))
+			assert.Equal(t, tt.drop, ApplyImageHandling(tt.input, ImageHandlingDrop))
+			assert.Equal(t, tt.alt, ApplyImageHandling(tt.input, ImageHandlingAltText))
+		})
+	}
+}
+
+func TestParseImageHandling(t *testing.T) {
+	for input, expected := range map[string]ImageHandling{
+		"":         ImageHandlingKeep,
+		"keep":     ImageHandlingKeep,
+		"DROP":     ImageHandlingDrop,
+		"alt":      ImageHandlingAltText,
+		"alt-text": ImageHandlingAltText,
+	} {
+		got, err := ParseImageHandling(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, got, input)
+	}
+
+	_, err := ParseImageHandling("blur")
+	assert.Error(t, err)
+}
+
+func TestPipeline_ImageHandling(t *testing.T) {
+	html := `<html><body><article>
+		<h1>Guide</h1>
+		<p>Intro text for the guide with enough words to be kept as content.</p>
+		<p><img src="/img/flow.png" alt="Request flow"></p>
+		<pre><code>![kept](in/code.png)</code></pre>
+	</article></body></html>`
+
+	doc, err := NewPipeline(PipelineOptions{ImageHandling: ImageHandlingAltText}).
+		Convert(context.Background(), html, "https://example.com/guide")
+	require.NoError(t, err)
+	assert.NotContains(t, doc.Content, "flow.png")
+	assert.Contains(t, doc.Content, "Request flow")
+	assert.Contains(t, doc.Content, "![kept](in/code.png)")
+
+	reader := NewPipeline(PipelineOptions{ImageHandling: ImageHandlingDrop}).MarkdownReader()
+	mdDoc, err := reader.Read("# Title\n\n![Diagram](d.png)\n\nBody `![x](y)`\n", "https://example.com/a.md")
+	require.NoError(t, err)
+	assert.NotContains(t, mdDoc.Content, "d.png")
+	assert.Contains(t, mdDoc.Content, "`![x](y)`")
+
+	var nilPipeline *Pipeline
+	assert.NotNil(t, nilPipeline.MarkdownReader())
+}
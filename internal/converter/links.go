@@ -0,0 +1,52 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// linkTargetRe matches the target of an inline markdown link or image,
+// optionally wrapped in angle brackets: the "](target" part of [text](target).
+var linkTargetRe = regexp.MustCompile(`\]\(\s*(<[^>\n]*>|[^()\s]+)`)
+
+// RewriteLinkTargets calls rewrite for the target of every inline link and
+// image in markdown and substitutes the result. Fenced code blocks and inline
+// code spans are left untouched.
+func RewriteLinkTargets(markdown string, rewrite func(target string) string) string {
+	lines := strings.Split(markdown, "\n")
+	var fence string
+	for i, line := range lines {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := openingFence(line); marker != "" {
+			fence = marker
+			continue
+		}
+		if !strings.Contains(line, "](") {
+			continue
+		}
+		lines[i] = mapOutsideInlineCode(line, func(text string) string {
+			return linkTargetRe.ReplaceAllStringFunc(text, func(m string) string {
+				sub := linkTargetRe.FindStringSubmatch(m)
+				target := sub[1]
+				bracketed := strings.HasPrefix(target, "<")
+				if bracketed {
+					target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+				}
+				replaced := rewrite(target)
+				if replaced == target {
+					return m
+				}
+				if bracketed || strings.ContainsAny(replaced, " ()") {
+					replaced = "<" + replaced + ">"
+				}
+				return m[:len(m)-len(sub[1])] + replaced
+			})
+		})
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,37 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewriteLinkTargets(t *testing.T) {
+	upper := func(target string) string {
+		if strings.HasPrefix(target, "http") {
+			return strings.ToUpper(target)
+		}
+		return target
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"inline link", "See [docs](http://a/b) now", "See [docs](HTTP://A/B) now"},
+		{"link with title", `[x](http://a "Title")`, `[x](HTTP://A "Title")`},
+		{"angle brackets", "[x](<http://a b>)", "[x](<HTTP://A B>)"},
+		{"relative unchanged", "[x](../y.md)", "[x](../y.md)"},
+		{"inline code untouched", "`[x](http://a)` and [y](http://b)", "`[x](http://a)` and [y](HTTP://B)"},
+		{"fenced code untouched", "```\n[x](http://a)\n```\n[y](http://b)", "```\n[x](http://a)\n```\n[y](HTTP://B)"},
+		{"linked image", "[![i](http://img)](http://a)", "[![i](HTTP://IMG)](HTTP://A)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RewriteLinkTargets(tt.input, upper))
+		})
+	}
+}
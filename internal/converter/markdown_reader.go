@@ -1,8 +1,6 @@
 package converter
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"net/url"
 	"regexp"
 	"strings"
@@ -14,7 +12,10 @@ import (
 
 // MarkdownReader reads and extracts metadata from markdown content
 // without using HTML parsing (avoids the 512 node limit issue).
-type MarkdownReader struct{}
+type MarkdownReader struct {
+	imageHandling ImageHandling
+	headingShift  int
+}
 
 // NewMarkdownReader creates a new markdown reader.
 func NewMarkdownReader() *MarkdownReader {
@@ -35,10 +36,12 @@ type Frontmatter struct {
 // Read processes markdown content and returns a Document.
 func (r *MarkdownReader) Read(content, sourceURL string) (*domain.Document, error) {
 	frontmatter, body := r.parseFrontmatter(content)
+	body = ApplyImageHandling(body, r.imageHandling)
 	title := r.extractTitle(frontmatter, body)
 	description := r.extractDescription(frontmatter, body)
 	headers := r.extractHeaders(body)
 	links := r.extractLinks(body, sourceURL)
+	body = ShiftHeadings(body, r.headingShift)
 
 	plainText := StripMarkdown(body)
 	wordCount := CountWords(plainText)
@@ -294,6 +297,5 @@ func (r *MarkdownReader) extractLinks(body, baseURL string) []string {
 }
 
 func (r *MarkdownReader) calculateHash(content string) string {
-	hash := sha256.Sum256([]byte(content))
-	return hex.EncodeToString(hash[:])
+	return ContentHash(content)
 }
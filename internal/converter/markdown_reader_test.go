@@ -381,9 +381,9 @@ func TestMarkdownReader_CalculateHash(t *testing.T) {
 			sameAs:  "content",
 		},
 		{
-			name:    "whitespace matters",
-			content: "Content",
-			sameAs:  "Content ",
+			name:    "inner whitespace matters",
+			content: "Con tent",
+			sameAs:  "Content",
 		},
 	}
 
@@ -0,0 +1,156 @@
+package converter
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	atxHeadingRe      = regexp.MustCompile(`^ {0,3}#{1,6}(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	setextUnderlineRe = regexp.MustCompile(`^ {0,3}(?:=+|-+)\s*$`)
+	thematicBreakRe   = regexp.MustCompile(`^ {0,3}(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	blockquoteRe      = regexp.MustCompile(`^ {0,3}(?:>\s?)+`)
+	bulletRe          = regexp.MustCompile(`^(\s*)[*+-]\s+(?:\[[ xX]\]\s+)?`)
+	tableDividerRe    = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?\s*$`)
+	linkDefinitionRe  = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:\s+\S+`)
+
+	imageRe         = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	inlineLinkRe    = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	referenceLinkRe = regexp.MustCompile(`\[([^\]]+)\]\[[^\]]*\]`)
+	autolinkRe      = regexp.MustCompile(`<((?:https?|ftp|mailto):[^>\s]+)>`)
+	htmlTagRe       = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	strongStarRe    = regexp.MustCompile(`\*\*([^\s*](?:.*?[^\s*])?)\*\*`)
+	strongUnderRe   = regexp.MustCompile(`(^|[^\w])__([^\s_](?:.*?[^\s_])?)__([^\w]|$)`)
+	starEmphasisRe  = regexp.MustCompile(`\*([^\s*](?:[^*]*[^\s*])?)\*`)
+	underEmphasisRe = regexp.MustCompile(`(^|[^\w])_([^\s_](?:[^_]*[^\s_])?)_([^\w]|$)`)
+	strikeRe        = regexp.MustCompile(`~~([^~]+)~~`)
+	escapeRe        = regexp.MustCompile("\\\\[\\\\`*_{}\\[\\]()#+\\-.!|>~]")
+)
+
+// escapedRuneBase offsets backslash-escaped ASCII characters into the
+// private use area while inline markup is removed, so escaped characters are
+// not mistaken for markup.
+const escapedRuneBase = '\uE000'
+
+// MarkdownToText renders markdown as plain text for consumers that cannot use
+// markup, such as embedding models. Unlike StripMarkdown, which drops code
+// for word counts and summaries, it keeps all of the content. Headings become plain lines, links and
+// images their text, and emphasis, inline code and HTML tags their content.
+// Fenced code blocks lose their fences and are indented by four spaces with
+// their content untouched; lists keep their bullets and numbers and tables
+// their cells, so the document structure stays readable.
+func MarkdownToText(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+
+	var fence string
+	for _, line := range lines {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+				continue
+			}
+			if strings.TrimSpace(line) == "" {
+				out = append(out, "")
+			} else {
+				out = append(out, "    "+line)
+			}
+			continue
+		}
+		if marker := openingFence(line); marker != "" {
+			fence = marker
+			continue
+		}
+
+		switch {
+		case setextUnderlineRe.MatchString(line) && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) != "":
+			// The previous line was a setext heading; drop its underline.
+			continue
+		case tableDividerRe.MatchString(line) && strings.Contains(line, "|"):
+			continue
+		case thematicBreakRe.MatchString(line), linkDefinitionRe.MatchString(line):
+			out = append(out, "")
+			continue
+		}
+
+		if m := atxHeadingRe.FindStringSubmatch(line); m != nil {
+			out = append(out, stripInline(m[1]))
+			continue
+		}
+
+		line = blockquoteRe.ReplaceAllString(line, "")
+		line = bulletRe.ReplaceAllString(line, "$1- ")
+		if isTableRow(line) {
+			line = tableRowText(line)
+		}
+		out = append(out, stripInline(line))
+	}
+
+	return NormalizeWhitespace(strings.Join(out, "\n"))
+}
+
+// stripInline removes inline markup from one line. Inline code spans are
+// kept verbatim, without their backticks.
+func stripInline(line string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(line, "`")
+		if start < 0 {
+			break
+		}
+		ticks := 1
+		for start+ticks < len(line) && line[start+ticks] == '`' {
+			ticks++
+		}
+		end := strings.Index(line[start+ticks:], line[start:start+ticks])
+		if end < 0 {
+			break
+		}
+		b.WriteString(stripInlineMarkup(line[:start]))
+		b.WriteString(strings.TrimSpace(line[start+ticks : start+ticks+end]))
+		line = line[start+ticks+end+ticks:]
+	}
+	b.WriteString(stripInlineMarkup(line))
+	return b.String()
+}
+
+// stripInlineMarkup removes links, images, emphasis, HTML tags and escapes
+// from text outside code spans.
+func stripInlineMarkup(text string) string {
+	text = escapeRe.ReplaceAllStringFunc(text, func(escape string) string {
+		return string(escapedRuneBase + rune(escape[1]))
+	})
+	text = imageRe.ReplaceAllString(text, "$1")
+	text = inlineLinkRe.ReplaceAllString(text, "$1")
+	text = referenceLinkRe.ReplaceAllString(text, "$1")
+	text = autolinkRe.ReplaceAllString(text, "$1")
+	text = htmlTagRe.ReplaceAllString(text, "")
+	text = strongStarRe.ReplaceAllString(text, "$1")
+	text = strongUnderRe.ReplaceAllString(text, "$1$2$3")
+	text = starEmphasisRe.ReplaceAllString(text, "$1")
+	text = underEmphasisRe.ReplaceAllString(text, "$1$2$3")
+	text = strikeRe.ReplaceAllString(text, "$1")
+	return strings.Map(func(r rune) rune {
+		if r > escapedRuneBase && r < escapedRuneBase+0x80 {
+			return r - escapedRuneBase
+		}
+		return r
+	}, text)
+}
+
+// isTableRow reports whether line looks like a pipe table row.
+func isTableRow(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "|") && strings.HasSuffix(trimmed, "|") && len(trimmed) > 1
+}
+
+// tableRowText joins the cells of a table row with " | ", without the outer
+// pipes.
+func tableRowText(line string) string {
+	trimmed := strings.TrimSpace(line)
+	cells := strings.Split(trimmed[1:len(trimmed)-1], "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return strings.Join(cells, " | ")
+}
@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownToText(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "headings",
+			markdown: "# Title #\n\nSetext\n======\n\n### **Bold** heading",
+			want:     "Title\n\nSetext\n\nBold heading\n",
+		},
+		{
+			name:     "links and images",
+			markdown: "Read [the guide](https://a.example/g \"Guide\") and [ref][1], ![logo](logo.png) <https://a.example>.\n\n[1]: https://a.example/ref",
+			want:     "Read the guide and ref, logo https://a.example.\n",
+		},
+		{
+			name:     "emphasis keeps identifiers",
+			markdown: "Some **bold**, *italic*, __strong__, _em_ and ~~old~~ text about snake_case_name and a * b * c.",
+			want:     "Some bold, italic, strong, em and old text about snake_case_name and a * b * c.\n",
+		},
+		{
+			name:     "inline code is verbatim",
+			markdown: "Call `fn(*args, **kwargs)` or ``a ` b``.",
+			want:     "Call fn(*args, **kwargs) or a ` b.\n",
+		},
+		{
+			name:     "escapes",
+			markdown: `Literal \*stars\* and \[brackets\]`,
+			want:     "Literal *stars* and [brackets]\n",
+		},
+		{
+			name:     "lists and quotes",
+			markdown: "> Note: **careful**\n\n* one\n  + two\n- [x] done\n1. first",
+			want:     "Note: careful\n\n- one\n  - two\n- done\n1. first\n",
+		},
+		{
+			name:     "tables",
+			markdown: "| Name | Value |\n|:-----|------:|\n| `a` | [b](x) |",
+			want:     "Name | Value\na | b\n",
+		},
+		{
+			name:     "code fences are indented verbatim",
+			markdown: "Example:\n\n```go\nx := a*b*c // [not](a link)\n\n\tindented()\n```\n\n---\n\nAfter",
+			want:     "Example:\n\n    x := a*b*c // [not](a link)\n\n    \tindented()\n\nAfter\n",
+		},
+		{
+			name:     "html tags",
+			markdown: "Line<br/>break and <kbd>Ctrl</kbd>",
+			want:     "Linebreak and Ctrl\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MarkdownToText(tt.markdown))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ExtractCanonicalURL returns the URL declared by <link rel="canonical">,
+// resolved against pageURL, or "" when the page declares none or it is not
+// an http(s) URL.
+func ExtractCanonicalURL(doc *goquery.Document, pageURL string) string {
+	var href string
+	doc.Find("link[rel]").EachWithBreak(func(_ int, link *goquery.Selection) bool {
+		rel, _ := link.Attr("rel")
+		for _, value := range strings.Fields(rel) {
+			if strings.EqualFold(value, "canonical") {
+				href, _ = link.Attr("href")
+				href = strings.TrimSpace(href)
+				return false
+			}
+		}
+		return true
+	})
+	if href == "" {
+		return ""
+	}
+
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if base, err := url.Parse(pageURL); err == nil {
+		ref = base.ResolveReference(ref)
+	}
+	if ref.Scheme != "http" && ref.Scheme != "https" || ref.Host == "" {
+		return ""
+	}
+	ref.Fragment = ""
+	return ref.String()
+}
+
+// ExtractKeywords returns the comma-separated entries of
+// <meta name="keywords">, trimmed, without blanks or repeats.
+func ExtractKeywords(doc *goquery.Document) []string {
+	content, _ := doc.Find(`meta[name="keywords" i]`).First().Attr("content")
+
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, keyword := range strings.Split(content, ",") {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" || seen[strings.ToLower(keyword)] {
+			continue
+		}
+		seen[strings.ToLower(keyword)] = true
+		keywords = append(keywords, keyword)
+	}
+	return keywords
+}
+
+// ExtractOpenGraph returns the page's Open Graph tags (<meta property="og:*">)
+// keyed by property without the "og:" prefix, such as "title" or "type".
+// Only the first value of a repeated property is kept. It returns nil when
+// the page has no Open Graph tags.
+func ExtractOpenGraph(doc *goquery.Document) map[string]string {
+	var tags map[string]string
+	doc.Find("meta[property]").Each(func(_ int, meta *goquery.Selection) {
+		property, _ := meta.Attr("property")
+		name, ok := strings.CutPrefix(strings.ToLower(strings.TrimSpace(property)), "og:")
+		if !ok || name == "" {
+			return
+		}
+		content := strings.TrimSpace(meta.AttrOr("content", ""))
+		if content == "" {
+			return
+		}
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		if _, exists := tags[name]; !exists {
+			tags[name] = content
+		}
+	})
+	return tags
+}
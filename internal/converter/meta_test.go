@@ -0,0 +1,87 @@
+package converter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseHTML(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestExtractCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"absolute", `<link rel="canonical" href="https://example.com/docs/guide">`, "https://example.com/docs/guide"},
+		{"relative", `<link rel="canonical" href="/docs/guide#top">`, "https://example.com/docs/guide"},
+		{"rel list", `<link rel="alternate canonical" href="guide">`, "https://example.com/docs/guide"},
+		{"non-http", `<link rel="canonical" href="mailto:docs@example.com">`, ""},
+		{"missing", `<link rel="stylesheet" href="/style.css">`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseHTML(t, "<html><head>"+tt.html+"</head><body></body></html>")
+			assert.Equal(t, tt.want, ExtractCanonicalURL(doc, "https://example.com/docs/page?ref=nav"))
+		})
+	}
+}
+
+func TestExtractKeywords(t *testing.T) {
+	doc := parseHTML(t, `<html><head><meta name="Keywords" content="go, cli , ,Go,docs"></head></html>`)
+	assert.Equal(t, []string{"go", "cli", "docs"}, ExtractKeywords(doc))
+
+	assert.Nil(t, ExtractKeywords(parseHTML(t, `<html><head></head></html>`)))
+}
+
+func TestExtractOpenGraph(t *testing.T) {
+	doc := parseHTML(t, `<html><head>
+		<meta property="og:title" content="Guide">
+		<meta property="og:type" content="article">
+		<meta property="og:image" content="https://example.com/a.png">
+		<meta property="og:image" content="https://example.com/b.png">
+		<meta property="og:locale" content="">
+		<meta property="twitter:card" content="summary">
+	</head></html>`)
+	assert.Equal(t, map[string]string{
+		"title": "Guide",
+		"type":  "article",
+		"image": "https://example.com/a.png",
+	}, ExtractOpenGraph(doc))
+
+	assert.Nil(t, ExtractOpenGraph(parseHTML(t, `<html><head></head></html>`)))
+}
+
+func TestPipeline_PageMetadata(t *testing.T) {
+	html := `<html><head>
+		<title>Guide</title>
+		<meta name="description" content="How to use the client.">
+		<meta name="keywords" content="client, setup">
+		<meta property="og:site_name" content="Example Docs">
+		<link rel="canonical" href="https://example.com/docs/guide">
+	</head><body><article>
+		<h1>Guide</h1>
+		<p>Intro text for the guide with enough words to be kept as content.</p>
+	</article></body></html>`
+
+	doc, err := NewPipeline(PipelineOptions{}).Convert(context.Background(), html, "https://example.com/docs/guide?utm_source=x")
+	require.NoError(t, err)
+	assert.Equal(t, "How to use the client.", doc.Description)
+	assert.Equal(t, "https://example.com/docs/guide", doc.CanonicalURL)
+	assert.Equal(t, []string{"client", "setup"}, doc.Keywords)
+	assert.Equal(t, map[string]string{"site_name": "Example Docs"}, doc.OpenGraph)
+
+	fm := doc.ToFrontmatter()
+	assert.Equal(t, "https://example.com/docs/guide", fm.CanonicalURL)
+	assert.Equal(t, "How to use the client.", fm.Description)
+}
@@ -2,9 +2,6 @@ package converter
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
-	"errors"
 	"strings"
 	"time"
 
@@ -19,13 +16,45 @@ type Pipeline struct {
 	extractor       *ExtractContent
 	mdConverter     *MarkdownConverter
 	excludeSelector string
+
+	stripFrontMatter    bool
+	frontMatterKeys     []string
+	normalizeWhitespace bool
+	imageHandling       ImageHandling
+	headingShift        int
+	preserveAdmonitions bool
 }
 
 // PipelineOptions contains options for the conversion pipeline
 type PipelineOptions struct {
 	BaseURL         string
 	ContentSelector string
-	ExcludeSelector string
+	// ContentSelectorStrict fails the conversion with ErrSelectorNotFound
+	// when ContentSelector yields no content, instead of falling back to
+	// common content containers and the page body.
+	ContentSelectorStrict bool
+	ExcludeSelector       string
+	// StripFrontMatter removes a leading `---`-delimited YAML block leaked
+	// into the page text by static site generators.
+	StripFrontMatter bool
+	// FrontMatterKeys lists front-matter keys preserved as document metadata
+	// when StripFrontMatter removes the block.
+	FrontMatterKeys []string
+	// NormalizeWhitespace collapses blank-line runs, trims trailing spaces and
+	// replaces non-breaking spaces outside code fences.
+	NormalizeWhitespace bool
+	// ImageHandling keeps (default), drops or replaces images with their alt
+	// text, both in converted HTML and in markdown read by MarkdownReader.
+	ImageHandling ImageHandling
+	// HeadingShift moves every heading by this many levels (positive
+	// demotes, negative promotes, clamped to # through ######), both in
+	// converted HTML and in markdown read by MarkdownReader. Document.Headers
+	// keep the levels of the source.
+	HeadingShift int
+	// PreserveAdmonitions renders admonition and callout containers, such as
+	// MkDocs `!!! note` and Docusaurus `:::tip` blocks, as GitHub callouts
+	// (> [!NOTE]) keeping their type and title.
+	PreserveAdmonitions bool
 }
 
 // NewPipeline creates a new conversion pipeline
@@ -37,6 +66,7 @@ func NewPipeline(opts PipelineOptions) *Pipeline {
 	})
 
 	extractor := NewExtractContent(opts.ContentSelector)
+	extractor.strict = opts.ContentSelectorStrict
 
 	mdConverter := NewMarkdownConverter(MarkdownOptions{
 		Domain:          opts.BaseURL,
@@ -50,7 +80,45 @@ func NewPipeline(opts PipelineOptions) *Pipeline {
 		extractor:       extractor,
 		mdConverter:     mdConverter,
 		excludeSelector: opts.ExcludeSelector,
+
+		stripFrontMatter:    opts.StripFrontMatter,
+		frontMatterKeys:     opts.FrontMatterKeys,
+		normalizeWhitespace: opts.NormalizeWhitespace,
+		imageHandling:       opts.ImageHandling,
+		headingShift:        opts.HeadingShift,
+		preserveAdmonitions: opts.PreserveAdmonitions,
+	}
+}
+
+// ContentSelector returns the content selector the pipeline extracts with.
+func (p *Pipeline) ContentSelector() string {
+	return p.extractor.selector
+}
+
+// ExcludeSelector returns the selector of elements the pipeline removes.
+func (p *Pipeline) ExcludeSelector() string {
+	return p.excludeSelector
+}
+
+// WithSelectors returns a copy of the pipeline that extracts content with
+// contentSelector and removes elements matching excludeSelector instead,
+// keeping every other option.
+func (p *Pipeline) WithSelectors(contentSelector, excludeSelector string) *Pipeline {
+	clone := *p
+	clone.extractor = NewExtractContent(contentSelector)
+	clone.extractor.strict = p.extractor.strict
+	clone.excludeSelector = excludeSelector
+	return &clone
+}
+
+// MarkdownReader returns a reader for markdown passthrough that applies the
+// pipeline's image handling and heading shift. It is safe to call on a nil
+// Pipeline.
+func (p *Pipeline) MarkdownReader() *MarkdownReader {
+	if p == nil {
+		return NewMarkdownReader()
 	}
+	return &MarkdownReader{imageHandling: p.imageHandling, headingShift: p.headingShift}
 }
 
 // Convert processes HTML content and returns a Document
@@ -68,8 +136,19 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 		return nil, err
 	}
 
+	// Step 2.25: Remove excluded elements before extraction, so neither the
+	// content selectors nor Readability can pick them
+	p.removeExcludedFromSelection(origDoc.Selection)
+
+	// Step 2.3: Flatten tab groups, whose hidden panels would otherwise be
+	// dropped along with other hidden elements
+	FlattenTabs(origDoc.Selection)
+
 	// Step 2.5: Preserve code language info before Readability can strip it
 	PreserveCodeLanguages(origDoc.Selection)
+	if p.preserveAdmonitions {
+		PreserveAdmonitions(origDoc.Selection)
+	}
 
 	// Re-serialize for Readability (which expects a string)
 	preservedHTML, err := origDoc.Html()
@@ -84,17 +163,12 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 	usedSelector := false
 
 	if p.extractor.selector != "" {
-		contentHTML, title, err = p.extractor.ExtractFromDocument(origDoc, sourceURL)
+		contentSel, _, err = p.extractor.SelectContent(origDoc, sourceURL)
 		if err != nil {
-			if errors.Is(err, ErrSelectorNotFound) {
-				contentHTML, title, err = p.extractor.extractWithReadability(preservedHTML, sourceURL)
-			} else {
-				return nil, err
-			}
-		} else {
-			usedSelector = true
-			contentSel = origDoc.Find(p.extractor.selector)
+			return nil, err
 		}
+		usedSelector = true
+		title = extractTitle(origDoc)
 	} else {
 		contentHTML, title, err = p.extractor.extractWithReadability(preservedHTML, sourceURL)
 	}
@@ -102,15 +176,6 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 		return nil, err
 	}
 
-	// Step 3.5: Apply exclusion selector (remove unwanted elements)
-	if p.excludeSelector != "" {
-		if usedSelector {
-			contentSel = p.removeExcludedFromSelection(contentSel)
-		} else {
-			contentHTML = p.removeExcluded(contentHTML)
-		}
-	}
-
 	if usedSelector && contentSel == nil {
 		return nil, ErrSelectorNotFound
 	}
@@ -121,12 +186,16 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 	var links []string
 
 	description := ExtractDescription(origDoc)
+	canonicalURL := ExtractCanonicalURL(origDoc, sourceURL)
+	keywords := ExtractKeywords(origDoc)
+	openGraph := ExtractOpenGraph(origDoc)
 
 	if usedSelector {
 		// Pre-process code blocks before sanitization
 		RestoreCodeLanguages(contentSel)
 		NormalizeCodeLanguages(contentSel)
 		StripLineNumbers(contentSel)
+		RestoreAdmonitions(contentSel)
 
 		sanitizedSel, selErr := p.sanitizer.SanitizeSelection(contentSel)
 		if selErr != nil {
@@ -146,6 +215,7 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 		RestoreCodeLanguages(contentDoc.Selection)
 		NormalizeCodeLanguages(contentDoc.Selection)
 		StripLineNumbers(contentDoc.Selection)
+		RestoreAdmonitions(contentDoc.Selection)
 
 		sanitizedDoc, docErr := p.sanitizer.SanitizeDocument(contentDoc)
 		if docErr != nil {
@@ -157,6 +227,13 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 		contentNode = sanitizedDoc.Selection
 	}
 
+	// Step 4.5: Remove leaked front-matter, keeping selected keys as metadata
+	var metadata map[string]string
+	if p.stripFrontMatter {
+		fields := StripFrontMatter(contentNode)
+		metadata = FrontMatterMetadata(fields, p.frontMatterKeys)
+	}
+
 	// Step 5: Convert to Markdown using DOM node directly (avoids reparsing)
 	var markdown string
 	if contentNode != nil && contentNode.Length() > 0 {
@@ -172,6 +249,15 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 		}
 	}
 
+	if p.preserveAdmonitions {
+		markdown = UnescapeCalloutMarkers(markdown)
+	}
+	markdown = ApplyImageHandling(markdown, p.imageHandling)
+	markdown = ShiftHeadings(markdown, p.headingShift)
+	if p.normalizeWhitespace {
+		markdown = NormalizeWhitespace(markdown)
+	}
+
 	// Step 6: Calculate statistics
 	plainText := StripMarkdown(markdown)
 	wordCount := CountWords(plainText)
@@ -183,6 +269,9 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 		URL:            sourceURL,
 		Title:          title,
 		Description:    description,
+		CanonicalURL:   canonicalURL,
+		Keywords:       keywords,
+		OpenGraph:      openGraph,
 		Content:        markdown,
 		HTMLContent:    html,
 		FetchedAt:      time.Now(),
@@ -191,6 +280,7 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 		CharCount:      charCount,
 		Links:          links,
 		Headers:        headers,
+		Metadata:       metadata,
 		RenderedWithJS: false,
 		SourceStrategy: "",
 		CacheHit:       false,
@@ -199,10 +289,9 @@ func (p *Pipeline) Convert(ctx context.Context, html string, sourceURL string) (
 	return document, nil
 }
 
-// calculateHash calculates SHA256 hash of content
+// calculateHash calculates the normalized content hash (see ContentHash)
 func calculateHash(content string) string {
-	hash := sha256.Sum256([]byte(content))
-	return hex.EncodeToString(hash[:])
+	return ContentHash(content)
 }
 
 // ConvertHTML is a convenience function for simple HTML to Markdown conversion
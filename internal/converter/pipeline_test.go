@@ -307,6 +307,104 @@ func TestPipeline_RemoveExcluded(t *testing.T) {
 	}
 }
 
+// TestPipeline_Convert_SelectorFallback tests the fallback chain used when
+// the content selector yields no content
+func TestPipeline_Convert_SelectorFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		selector    string
+		html        string
+		contains    string
+		notContains string
+	}{
+		{
+			name:        "selector matches",
+			selector:    ".docs",
+			html:        `<html><body><nav>Menu</nav><div class="docs"><p>Docs text</p></div><main><p>Main text</p></main></body></html>`,
+			contains:    "Docs text",
+			notContains: "Main text",
+		},
+		{
+			name:        "missing selector falls back to main",
+			selector:    ".docs",
+			html:        `<html><body><aside>Aside text</aside><main><p>Main text</p></main></body></html>`,
+			contains:    "Main text",
+			notContains: "Aside text",
+		},
+		{
+			name:        "empty match falls back to article",
+			selector:    ".docs",
+			html:        `<html><body><div class="docs"> </div><aside>Aside text</aside><article><p>Article text</p></article></body></html>`,
+			contains:    "Article text",
+			notContains: "Aside text",
+		},
+		{
+			name:     "content id",
+			selector: ".docs",
+			html:     `<html><body><div id="content"><p>Content text</p></div></body></html>`,
+			contains: "Content text",
+		},
+		{
+			name:     "body as last resort",
+			selector: ".docs",
+			html:     `<html><body><div><p>Body text</p></div></body></html>`,
+			contains: "Body text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := NewPipeline(PipelineOptions{ContentSelector: tt.selector})
+			doc, err := pipeline.Convert(context.Background(), tt.html, "https://example.com/page")
+			require.NoError(t, err)
+			assert.Contains(t, doc.Content, tt.contains)
+			if tt.notContains != "" {
+				assert.NotContains(t, doc.Content, tt.notContains)
+			}
+		})
+	}
+}
+
+// TestPipeline_Convert_SelectorStrict tests that strict mode reports a
+// selector miss instead of falling back
+func TestPipeline_Convert_SelectorStrict(t *testing.T) {
+	pipeline := NewPipeline(PipelineOptions{ContentSelector: ".docs", ContentSelectorStrict: true})
+	html := `<html><body><main><p>Main text</p></main></body></html>`
+
+	_, err := pipeline.Convert(context.Background(), html, "https://example.com/page")
+	assert.ErrorIs(t, err, ErrSelectorNotFound)
+
+	doc, err := pipeline.Convert(context.Background(), `<html><body><div class="docs"><p>Docs text</p></div></body></html>`, "https://example.com/page")
+	require.NoError(t, err)
+	assert.Contains(t, doc.Content, "Docs text")
+}
+
+// TestPipeline_Convert_ExcludeBeforeSelector tests that exclude selectors are
+// applied before the content selector is resolved
+func TestPipeline_Convert_ExcludeBeforeSelector(t *testing.T) {
+	pipeline := NewPipeline(PipelineOptions{ContentSelector: "nav, main", ExcludeSelector: "nav, .sidebar"})
+	html := `<html><body>
+<nav><p>Navigation links</p></nav>
+<main><div class="sidebar"><p>Sidebar text</p></div><p>Main text</p></main>
+</body></html>`
+
+	doc, err := pipeline.Convert(context.Background(), html, "https://example.com/page")
+	require.NoError(t, err)
+	assert.Contains(t, doc.Content, "Main text")
+	assert.NotContains(t, doc.Content, "Navigation links")
+	assert.NotContains(t, doc.Content, "Sidebar text")
+}
+
+// TestPipeline_MarkdownReader_IgnoresExclude tests that markdown sources are
+// unaffected by exclude selectors
+func TestPipeline_MarkdownReader_IgnoresExclude(t *testing.T) {
+	pipeline := NewPipeline(PipelineOptions{ExcludeSelector: "nav, .sidebar"})
+
+	doc, err := pipeline.MarkdownReader().Read("# Title\n\nThe nav and .sidebar words stay.\n", "https://example.com/page.md")
+	require.NoError(t, err)
+	assert.Contains(t, doc.Content, "The nav and .sidebar words stay.")
+}
+
 // TestPipeline_Convert_Metadata tests metadata extraction
 func TestPipeline_Convert_Metadata(t *testing.T) {
 	html := `<!DOCTYPE html>
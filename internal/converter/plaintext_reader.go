@@ -1,8 +1,6 @@
 package converter
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"net/url"
 	"path"
 	"regexp"
@@ -147,6 +145,5 @@ func (r *PlainTextReader) extractLinks(content, baseURL string) []string {
 }
 
 func (r *PlainTextReader) calculateHash(content string) string {
-	hash := sha256.Sum256([]byte(content))
-	return hex.EncodeToString(hash[:])
+	return ContentHash(content)
 }
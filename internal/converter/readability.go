@@ -14,9 +14,19 @@ import (
 // ErrSelectorNotFound indicates no elements matched the selector.
 var ErrSelectorNotFound = errors.New("selector not found")
 
+// fallbackContentSelectors are tried, in order, when the configured content
+// selector yields no content: common main-content containers, then the whole
+// body.
+var fallbackContentSelectors = []string{"main", "article", ".content", "#content", "body"}
+
 // ExtractContent extracts the main content from HTML
 type ExtractContent struct {
 	selector string
+	// selectors are the alternatives of selector, tried in order.
+	selectors []string
+	// strict disables the fallback chain of SelectContent, so a selector
+	// miss surfaces as ErrSelectorNotFound.
+	strict bool
 }
 
 // ExtractOptions contains options for content extraction
@@ -27,7 +37,7 @@ type ExtractOptions struct {
 
 // NewExtractContent creates a new content extractor
 func NewExtractContent(selector string) *ExtractContent {
-	return &ExtractContent{selector: selector}
+	return &ExtractContent{selector: selector, selectors: ParseContentSelectors(selector)}
 }
 
 // Extract extracts main content from HTML
@@ -64,16 +74,8 @@ func (e *ExtractContent) ExtractFromDocument(doc *goquery.Document, sourceURL st
 		return "", "", fmt.Errorf("extract from document requires document")
 	}
 
-	content := doc.Find(e.selector)
-	matchCount := content.Length()
-
-	log.Debug().
-		Str("selector", e.selector).
-		Int("matches", matchCount).
-		Str("url", sourceURL).
-		Msg("Content selector applied")
-
-	if matchCount == 0 {
+	content, _ := e.matchSelectors(doc, sourceURL)
+	if content == nil {
 		log.Debug().
 			Str("selector", e.selector).
 			Str("url", sourceURL).
@@ -93,6 +95,60 @@ func (e *ExtractContent) ExtractFromDocument(doc *goquery.Document, sourceURL st
 	return combined.String(), title, nil
 }
 
+// SelectContent returns the elements holding the page's main content and the
+// selector that matched them. The configured selectors are tried in order and
+// the first one yielding non-empty content wins. When none does,
+// fallbackContentSelectors are tried, or in strict mode ErrSelectorNotFound
+// is returned.
+func (e *ExtractContent) SelectContent(doc *goquery.Document, sourceURL string) (*goquery.Selection, string, error) {
+	if doc == nil {
+		return nil, "", fmt.Errorf("select content requires document")
+	}
+
+	if content, selector := e.matchSelectors(doc, sourceURL); content != nil {
+		return content, selector, nil
+	}
+	if e.strict {
+		return nil, "", fmt.Errorf("%w: %s", ErrSelectorNotFound, e.selector)
+	}
+
+	for _, selector := range fallbackContentSelectors {
+		content := doc.Find(selector).First()
+		if !hasText(content) {
+			continue
+		}
+		log.Warn().
+			Str("selector", e.selector).
+			Str("fallback", selector).
+			Str("url", sourceURL).
+			Msg("Content selector matched no content, using fallback")
+		return content, selector, nil
+	}
+	return nil, "", fmt.Errorf("%w: %s", ErrSelectorNotFound, e.selector)
+}
+
+// matchSelectors returns the content of the first configured selector that
+// yields non-empty text, or nil when none does.
+func (e *ExtractContent) matchSelectors(doc *goquery.Document, sourceURL string) (*goquery.Selection, string) {
+	for _, selector := range e.selectors {
+		content := doc.Find(selector)
+		log.Debug().
+			Str("selector", selector).
+			Int("matches", content.Length()).
+			Str("url", sourceURL).
+			Msg("Content selector applied")
+		if hasText(content) {
+			return content, selector
+		}
+	}
+	return nil, ""
+}
+
+// hasText reports whether sel holds any non-whitespace text.
+func hasText(sel *goquery.Selection) bool {
+	return sel.Length() > 0 && strings.TrimSpace(sel.Text()) != ""
+}
+
 // extractWithSelector extracts content using a CSS selector
 func (e *ExtractContent) extractWithSelector(html, sourceURL string) (string, string, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
@@ -0,0 +1,40 @@
+package converter
+
+import "strings"
+
+// ParseContentSelectors splits a content selector into the alternatives tried
+// in order, such as "article, main, .md-content". Commas inside brackets,
+// parentheses or quotes (e.g. `:is(h1, h2)` or `[title="a,b"]`) do not split.
+func ParseContentSelectors(selector string) []string {
+	var selectors []string
+	var quote rune
+	depth, start := 0, 0
+
+	add := func(part string) {
+		if part = strings.TrimSpace(part); part != "" {
+			selectors = append(selectors, part)
+		}
+	}
+
+	for i, r := range selector {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			if depth > 0 {
+				depth--
+			}
+		case r == ',' && depth == 0:
+			add(selector[start:i])
+			start = i + 1
+		}
+	}
+	add(selector[start:])
+	return selectors
+}
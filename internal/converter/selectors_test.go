@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentSelectors(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{selector: "", want: nil},
+		{selector: "article.main", want: []string{"article.main"}},
+		{selector: "article, main ,.md-content", want: []string{"article", "main", ".md-content"}},
+		{selector: ":is(h1, h2), main", want: []string{":is(h1, h2)", "main"}},
+		{selector: `[title="a,b"], main`, want: []string{`[title="a,b"]`, "main"}},
+		{selector: "article,,", want: []string{"article"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseContentSelectors(tt.selector))
+		})
+	}
+}
+
+func TestExtractContent_SelectContent_Order(t *testing.T) {
+	html := `<html><body><main><p>Main text</p></main><div class="md-content"> </div><article><p>Article text</p></article></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	require.NoError(t, err)
+
+	content, selector, err := NewExtractContent(".md-content, article, main").SelectContent(doc, "https://example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "article", selector, "the first selector with non-empty content wins")
+	assert.Contains(t, content.Text(), "Article text")
+	assert.NotContains(t, content.Text(), "Main text")
+
+	_, selector, err = NewExtractContent("main, article").SelectContent(doc, "https://example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "main", selector)
+}
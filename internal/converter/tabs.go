@@ -0,0 +1,145 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxTabPanelDepth bounds how far up from an ARIA tablist FlattenTabs looks
+// for the siblings holding its panels.
+const maxTabPanelDepth = 3
+
+// FlattenTabs replaces tab groups with their panels in sequence, each
+// headed by a bold "Tab: <label>" line, so that tabs hidden by default (such
+// as the non-default language of a code sample) are kept: the sanitizer and
+// Readability drop hidden elements. It handles MkDocs Material tabbed sets
+// and ARIA tab widgets (role="tablist"/"tab"/"tabpanel"), used by
+// Docusaurus, Starlight, Bootstrap and most other themes. Nested groups are
+// flattened inside out.
+func FlattenTabs(sel *goquery.Selection) {
+	flattenReverse(findWithRoot(sel, ".tabbed-set"), flattenTabbedSet)
+	flattenReverse(findWithRoot(sel, "[role='tablist']"), flattenTablist)
+}
+
+// flattenReverse applies flatten to each element of groups, last first, so
+// inner groups are flattened before the groups containing them.
+func flattenReverse(groups *goquery.Selection, flatten func(*goquery.Selection)) {
+	for i := groups.Length() - 1; i >= 0; i-- {
+		flatten(groups.Eq(i))
+	}
+}
+
+// flattenTabbedSet flattens a pymdownx.tabbed set, in either the alternate
+// style (labels in .tabbed-labels, panels as .tabbed-block) or the original
+// one (label and .tabbed-content pairs as direct children).
+func flattenTabbedSet(set *goquery.Selection) {
+	labels := set.ChildrenFiltered("label")
+	if labels.Length() == 0 {
+		labels = set.ChildrenFiltered(".tabbed-labels").ChildrenFiltered("label")
+	}
+	panels := set.ChildrenFiltered(".tabbed-content")
+	if blocks := panels.ChildrenFiltered(".tabbed-block"); blocks.Length() > 0 {
+		panels = blocks
+	}
+	if panels.Length() == 0 {
+		return
+	}
+	set.ReplaceWithHtml(flattenedTabsHTML(labels, func(i int, _ *goquery.Selection) *goquery.Selection {
+		return panels.Eq(i)
+	}))
+}
+
+// flattenTablist flattens an ARIA tab widget. Each tab's panel is the
+// element named by its aria-controls, or else the tabpanel at the same
+// position among those following the tablist (see siblingTabPanels). Only
+// the panels used are removed, so other tab groups keep theirs.
+func flattenTablist(tablist *goquery.Selection) {
+	root := tablist.Parents().Last()
+	if root.Length() == 0 {
+		root = tablist
+	}
+	siblings := siblingTabPanels(tablist)
+	var used []*goquery.Selection
+	tabs := tablist.Find("[role='tab']")
+	flattened := flattenedTabsHTML(tabs, func(i int, tab *goquery.Selection) *goquery.Selection {
+		panel := tab.Slice(0, 0)
+		if i < len(siblings) {
+			panel = siblings[i]
+		}
+		if id, ok := tab.Attr("aria-controls"); ok && id != "" {
+			if controlled := root.Find("[role='tabpanel']").FilterFunction(func(_ int, p *goquery.Selection) bool {
+				return p.AttrOr("id", "") == id
+			}).First(); controlled.Length() > 0 {
+				panel = controlled
+			}
+		}
+		if panel.Length() > 0 {
+			used = append(used, panel)
+		}
+		return panel
+	})
+	if len(used) == 0 {
+		return
+	}
+	for _, panel := range used {
+		panel.Remove()
+	}
+	tablist.ReplaceWithHtml(flattened)
+}
+
+// siblingTabPanels returns the tabpanels following tablist, or following the
+// nearest of its ancestors (up to maxTabPanelDepth) that has any, such as a
+// Bootstrap .tab-content. The search stops at the next tablist, so groups
+// sharing a parent do not take each other's panels.
+func siblingTabPanels(tablist *goquery.Selection) []*goquery.Selection {
+	var panels []*goquery.Selection
+	node := tablist
+	for depth := 0; depth < maxTabPanelDepth && node.Length() > 0; depth++ {
+		node.NextAll().EachWithBreak(func(_ int, sib *goquery.Selection) bool {
+			if sib.Is("[role='tablist']") || sib.Find("[role='tablist']").Length() > 0 {
+				return false
+			}
+			if sib.Is("[role='tabpanel']") {
+				panels = append(panels, sib)
+			} else {
+				sib.Find("[role='tabpanel']").Each(func(_ int, panel *goquery.Selection) {
+					panels = append(panels, panel)
+				})
+			}
+			return true
+		})
+		if len(panels) > 0 {
+			return panels
+		}
+		node = node.Parent()
+	}
+	return panels
+}
+
+// flattenedTabsHTML renders each tab as a "Tab: <label>" line followed by
+// the contents of its panel, found by panelFor.
+func flattenedTabsHTML(labels *goquery.Selection, panelFor func(int, *goquery.Selection) *goquery.Selection) string {
+	var b strings.Builder
+	b.WriteString("<div>")
+	labels.Each(func(i int, label *goquery.Selection) {
+		panel := panelFor(i, label)
+		if panel.Length() == 0 {
+			return
+		}
+		content, err := panel.Html()
+		if err != nil {
+			return
+		}
+		name := strings.Join(strings.Fields(label.Text()), " ")
+		if name == "" {
+			name = fmt.Sprintf("%d", i+1)
+		}
+		b.WriteString("<p><strong>Tab: " + html.EscapeString(name) + "</strong></p>")
+		b.WriteString(content)
+	})
+	b.WriteString("</div>")
+	return b.String()
+}
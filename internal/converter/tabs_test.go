@@ -0,0 +1,135 @@
+package converter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenTabs(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "mkdocs alternate style",
+			html: `<div class="tabbed-set tabbed-alternate" data-tabs="1:2">
+				<input checked="checked" id="__tabbed_1_1" name="__tabbed_1" type="radio">
+				<input id="__tabbed_1_2" name="__tabbed_1" type="radio">
+				<div class="tabbed-labels"><label for="__tabbed_1_1">Python</label><label for="__tabbed_1_2">Go</label></div>
+				<div class="tabbed-content">
+					<div class="tabbed-block"><p>py body</p></div>
+					<div class="tabbed-block"><p>go body</p></div>
+				</div>
+			</div>`,
+			want: "<div><p><strong>Tab: Python</strong></p><p>py body</p><p><strong>Tab: Go</strong></p><p>go body</p></div>",
+		},
+		{
+			name: "mkdocs original style",
+			html: `<div class="tabbed-set" data-tabs="1:2">` +
+				`<input checked="checked" id="__tabbed_1_1" name="__tabbed_1" type="radio"><label for="__tabbed_1_1">pip</label><div class="tabbed-content"><p>pip body</p></div>` +
+				`<input id="__tabbed_1_2" name="__tabbed_1" type="radio"><label for="__tabbed_1_2">conda</label><div class="tabbed-content"><p>conda body</p></div>` +
+				`</div>`,
+			want: "<div><p><strong>Tab: pip</strong></p><p>pip body</p><p><strong>Tab: conda</strong></p><p>conda body</p></div>",
+		},
+		{
+			name: "docusaurus hidden panels",
+			html: `<div class="tabs-container tabList__CuJ">` +
+				`<ul role="tablist" aria-orientation="horizontal" class="tabs">` +
+				`<li role="tab" tabindex="0" aria-selected="true" class="tabs__item tabs__item--active">npm</li>` +
+				`<li role="tab" tabindex="-1" aria-selected="false" class="tabs__item">Yarn</li></ul>` +
+				`<div class="margin-top--md"><div role="tabpanel" class="tabItem_Ymn6"><p>npm body</p></div>` +
+				`<div role="tabpanel" class="tabItem_Ymn6" hidden=""><p>yarn body</p></div></div></div>`,
+			want: `<div class="tabs-container tabList__CuJ"><div><p><strong>Tab: npm</strong></p><p>npm body</p>` +
+				`<p><strong>Tab: Yarn</strong></p><p>yarn body</p></div><div class="margin-top--md"></div></div>`,
+		},
+		{
+			name: "aria-controls out of order",
+			html: `<starlight-tabs><div class="tablist-wrapper"><ul role="tablist">` +
+				`<li role="presentation"><a role="tab" href="#tab-panel-1" aria-controls="tab-panel-1">macOS</a></li>` +
+				`<li role="presentation"><a role="tab" href="#tab-panel-0" aria-controls="tab-panel-0">Linux</a></li></ul></div>` +
+				`<div id="tab-panel-0" role="tabpanel" hidden><p>linux body</p></div>` +
+				`<div id="tab-panel-1" role="tabpanel"><p>mac body</p></div></starlight-tabs>`,
+			want: `<starlight-tabs><div class="tablist-wrapper"><div><p><strong>Tab: macOS</strong></p><p>mac body</p>` +
+				`<p><strong>Tab: Linux</strong></p><p>linux body</p></div></div></starlight-tabs>`,
+		},
+		{
+			name: "nested groups",
+			html: `<div class="tabbed-set" data-tabs="1:1"><label>Outer</label><div class="tabbed-content">` +
+				`<div class="tabbed-set" data-tabs="2:2"><label>A</label><div class="tabbed-content"><p>a</p></div>` +
+				`<label>B</label><div class="tabbed-content"><p>b</p></div></div>` +
+				`</div></div>`,
+			want: "<div><p><strong>Tab: Outer</strong></p><div><p><strong>Tab: A</strong></p><p>a</p>" +
+				"<p><strong>Tab: B</strong></p><p>b</p></div></div>",
+		},
+		{
+			name: "sibling bootstrap groups keep their own panels",
+			html: `<article><ul class="nav nav-tabs" role="tablist">` +
+				`<li><a role="tab" href="#py-one">Python</a></li><li><a role="tab" href="#py-two">Python 2</a></li></ul>` +
+				`<div class="tab-content"><div role="tabpanel" class="tab-pane active" id="py-one"><p>PY-ONE</p></div>` +
+				`<div role="tabpanel" class="tab-pane" id="py-two"><p>PY-TWO</p></div></div>` +
+				`<ul class="nav nav-tabs" role="tablist">` +
+				`<li><a role="tab" href="#go-one">Go</a></li><li><a role="tab" href="#go-two">Go 2</a></li></ul>` +
+				`<div class="tab-content"><div role="tabpanel" class="tab-pane active" id="go-one"><p>GO-ONE</p></div>` +
+				`<div role="tabpanel" class="tab-pane" id="go-two"><p>GO-TWO</p></div></div></article>`,
+			want: `<article><div><p><strong>Tab: Python</strong></p><p>PY-ONE</p><p><strong>Tab: Python 2</strong></p><p>PY-TWO</p></div>` +
+				`<div class="tab-content"></div>` +
+				`<div><p><strong>Tab: Go</strong></p><p>GO-ONE</p><p><strong>Tab: Go 2</strong></p><p>GO-TWO</p></div>` +
+				`<div class="tab-content"></div></article>`,
+		},
+		{
+			name: "sibling groups with aria-controls",
+			html: `<article><ul role="tablist"><li role="tab" aria-controls="a1">A</li></ul>` +
+				`<div role="tabpanel" id="a1"><p>A-ONE</p></div>` +
+				`<ul role="tablist"><li role="tab" aria-controls="b1">B</li></ul>` +
+				`<div role="tabpanel" id="b1"><p>B-ONE</p></div></article>`,
+			want: `<article><div><p><strong>Tab: A</strong></p><p>A-ONE</p></div>` +
+				`<div><p><strong>Tab: B</strong></p><p>B-ONE</p></div></article>`,
+		},
+		{
+			name: "tablist without panels untouched",
+			html: `<ul role="tablist"><li role="tab">Only</li></ul>`,
+			want: `<ul role="tablist"><li role="tab">Only</li></ul>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><body>" + tt.html + "</body></html>"))
+			require.NoError(t, err)
+			FlattenTabs(doc.Selection)
+			got, err := doc.Find("body").Html()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, strings.Join(strings.Fields(strings.ReplaceAll(got, ">\n", ">")), " "))
+		})
+	}
+}
+
+func TestPipeline_FlattenTabs_KeepsHiddenTabs(t *testing.T) {
+	html := `<html><body><article>
+		<h1>Quickstart</h1>
+		<p>Create a client with the SDK for your language and send a first request.</p>
+		<div class="tabs-container">
+			<ul role="tablist" class="tabs">
+				<li role="tab" aria-selected="true">Python</li>
+				<li role="tab" aria-selected="false">Go</li>
+			</ul>
+			<div>
+				<div role="tabpanel"><pre><code class="language-python">client = Client()</code></pre></div>
+				<div role="tabpanel" hidden><pre><code class="language-go">client := NewClient()</code></pre></div>
+			</div>
+		</div>
+	</article></body></html>`
+
+	for _, selector := range []string{"", "article"} {
+		doc, err := NewPipeline(PipelineOptions{ContentSelector: selector}).
+			Convert(context.Background(), html, "https://example.com/quickstart")
+		require.NoError(t, err)
+		assert.Contains(t, doc.Content, "**Tab: Python**\n\n```python\nclient = Client()\n```", "selector %q", selector)
+		assert.Contains(t, doc.Content, "**Tab: Go**\n\n```go\nclient := NewClient()\n```", "selector %q", selector)
+	}
+}
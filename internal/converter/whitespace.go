@@ -0,0 +1,87 @@
+package converter
+
+import "strings"
+
+// NormalizeWhitespace tidies converted markdown so repeated runs produce
+// clean diffs: runs of three or more blank lines collapse to one (single and
+// double blank lines are kept), trailing whitespace is trimmed, non-breaking
+// spaces become regular spaces, and the result ends with exactly one
+// newline. Fenced code blocks are copied byte-for-byte, and a two-space
+// hard line break followed by more text is kept.
+func NormalizeWhitespace(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	out := make([]string, 0, len(lines))
+
+	var fence string
+	blankRun := 0
+	for i, line := range lines {
+		if fence != "" {
+			out = append(out, line)
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+
+		line = strings.ReplaceAll(line, "\u00a0", " ")
+		trimmed := strings.TrimRight(line, " \t\r")
+		if trimmed == "" {
+			blankRun++
+			continue
+		}
+		// Blank lines are emitted once the run ends; leading ones are dropped.
+		if blankRun > 2 {
+			blankRun = 1
+		}
+		for ; blankRun > 0 && len(out) > 0; blankRun-- {
+			out = append(out, "")
+		}
+		blankRun = 0
+
+		if marker := openingFence(lines[i]); marker != "" {
+			fence = marker
+			out = append(out, lines[i])
+			continue
+		}
+
+		if strings.HasSuffix(line, "  ") && !strings.HasSuffix(line, "   ") &&
+			i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+			trimmed += "  "
+		}
+		out = append(out, trimmed)
+	}
+
+	result := strings.Join(out, "\n")
+	if fence == "" {
+		result = strings.TrimRight(result, "\n")
+	}
+	return result + "\n"
+}
+
+// openingFence returns the fence marker (e.g. "```" or "~~~~") when line
+// opens a fenced code block, or "" otherwise.
+func openingFence(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, ch := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == ch {
+			n++
+		}
+		if n >= 3 {
+			return trimmed[:n]
+		}
+	}
+	return ""
+}
+
+// isClosingFence reports whether line closes a block opened with fence.
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, fence) {
+		return false
+	}
+	return strings.Trim(trimmed, fence[:1]) == ""
+}
@@ -0,0 +1,84 @@
+package converter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeWhitespace(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "collapses runs of three or more blank lines",
+			input: "# Title\n\n\n\n\nParagraph\n\n\n\nNext",
+			want:  "# Title\n\nParagraph\n\nNext\n",
+		},
+		{
+			name:  "keeps single and double blank lines",
+			input: "One\n\nTwo\n\n\nThree\n \n\t\nFour",
+			want:  "One\n\nTwo\n\n\nThree\n\n\nFour\n",
+		},
+		{
+			name:  "trims trailing whitespace",
+			input: "Line one   \nLine two\t\n",
+			want:  "Line one\nLine two\n",
+		},
+		{
+			name:  "replaces non-breaking spaces",
+			input: "Hello world",
+			want:  "Hello world\n",
+		},
+		{
+			name:  "keeps hard line breaks",
+			input: "line one  \nline two",
+			want:  "line one  \nline two\n",
+		},
+		{
+			name:  "drops hard break before blank line",
+			input: "line one  \n\nline two",
+			want:  "line one\n\nline two\n",
+		},
+		{
+			name:  "single trailing newline",
+			input: "\n\nText\n\n\n",
+			want:  "Text\n",
+		},
+		{
+			name:  "code fence left intact",
+			input: "Intro\n\n```go\nfunc main() {   \n\n\n\n\tx := 1\n}\n```\n\n\n\nOutro",
+			want:  "Intro\n\n```go\nfunc main() {   \n\n\n\n\tx := 1\n}\n```\n\nOutro\n",
+		},
+		{
+			name:  "tilde fence requires matching close",
+			input: "~~~~\n```\n  \n~~~~\nText  ",
+			want:  "~~~~\n```\n  \n~~~~\nText\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeWhitespace(tt.input))
+		})
+	}
+}
+
+func TestPipeline_Convert_NormalizeWhitespace(t *testing.T) {
+	html := `<html><body><main><h1>Title</h1><p>Hello&nbsp;world</p><pre><code>keep&nbsp;this</code></pre></main></body></html>`
+
+	doc, err := NewPipeline(PipelineOptions{NormalizeWhitespace: true}).Convert(context.Background(), html, "https://example.com")
+	require.NoError(t, err)
+
+	assert.Contains(t, doc.Content, "Hello world")
+	assert.Contains(t, doc.Content, "keep this")
+	assert.Regexp(t, `[^\n]\n$`, doc.Content)
+
+	plain, err := NewPipeline(PipelineOptions{}).Convert(context.Background(), html, "https://example.com")
+	require.NoError(t, err)
+	assert.Contains(t, plain.Content, "Hello world")
+}
@@ -48,6 +48,18 @@ var (
 
 	// ErrPlanExhausted indicates the recovery plan has no remaining alternatives
 	ErrPlanExhausted = errors.New("recovery plan exhausted")
+
+	// ErrHostCircuitOpen indicates requests to a host are failing fast after
+	// repeated failures
+	ErrHostCircuitOpen = errors.New("host circuit breaker is open")
+
+	// ErrDeadlineExceeded indicates a run was truncated at its wall-clock
+	// deadline; documents completed before it were kept
+	ErrDeadlineExceeded = errors.New("run deadline exceeded")
+
+	// ErrTooManyErrors indicates a run was aborted once its document error
+	// budget was used up; documents completed before it were kept
+	ErrTooManyErrors = errors.New("too many document errors")
 )
 
 // FetchError represents an error during fetching
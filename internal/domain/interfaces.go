@@ -19,17 +19,31 @@ type Strategy interface {
 // StrategyOptions contains options for strategy execution
 type StrategyOptions struct {
 	CommonOptions
-	Output          string
-	Concurrency     int
-	MaxDepth        int
-	Exclude         []string
+	Output      string
+	Concurrency int
+	MaxDepth    int
+	Exclude     []string
+	// Include keeps only the git repository files matching one of its
+	// gitignore-style globs.
+	Include         []string
 	NoFolders       bool
 	Split           bool
 	IncludeAssets   bool
 	ContentSelector string
 }
 
-// Fetcher defines the interface for HTTP fetching with stealth capabilities
+// Fetcher defines the interface for HTTP fetching with stealth capabilities.
+// The built-in implementation is fetcher.Client; a custom one, such as a
+// client for an authenticated egress proxy or mTLS, is injected with
+// strategies.DependencyOptions.Fetcher or a strategy's SetFetcher.
+//
+// Implementations must be safe for concurrent use. Get and GetWithHeaders
+// return the decoded body of a successful (2xx) response, or of a 3xx
+// response when redirects are not followed; any other status is an error,
+// a *FetchError carrying the StatusCode. Redirects are followed by the
+// fetcher, which reports where the chain ended in Response.FinalURL. A
+// fetcher may answer from its own cache, setting Response.FromCache; callers
+// never cache responses themselves. Requests end when ctx does.
 type Fetcher interface {
 	// Get fetches content from a URL
 	Get(ctx context.Context, url string) (*Response, error)
@@ -37,9 +51,12 @@ type Fetcher interface {
 	GetWithHeaders(ctx context.Context, url string, headers map[string]string) (*Response, error)
 	// GetCookies returns cookies for a URL (for sharing with renderer)
 	GetCookies(url string) []*http.Cookie
-	// Transport returns an http.RoundTripper for integration with other HTTP clients (e.g., colly)
+	// Transport returns an http.RoundTripper for integration with other
+	// HTTP clients (e.g., colly, and git archive downloads of an injected
+	// fetcher). It follows the same caching and error rules as Get.
 	Transport() http.RoundTripper
-	// Close releases resources
+	// Close releases resources. Dependencies.Close closes the fetcher it
+	// holds, including an injected one.
 	Close() error
 }
 
@@ -50,7 +67,20 @@ type Response struct {
 	Headers     http.Header
 	ContentType string
 	URL         string
-	FromCache   bool
+	// FinalURL is where a followed redirect chain ended, or the resolved
+	// target of a 3xx response left unfollowed; empty when the URL was not
+	// redirected.
+	FinalURL  string
+	FromCache bool
+}
+
+// PageURL returns the URL the body was served from: FinalURL after a
+// followed redirect, and URL otherwise.
+func (r *Response) PageURL() string {
+	if r.FinalURL != "" && (r.StatusCode < 300 || r.StatusCode >= 400) {
+		return r.FinalURL
+	}
+	return r.URL
 }
 
 // Renderer defines the interface for JavaScript rendering
@@ -67,7 +97,10 @@ type RenderOptions struct {
 	WaitFor     string        // CSS selector to wait for
 	WaitStable  time.Duration // Wait for network idle
 	ScrollToEnd bool          // Scroll to load lazy content
-	Cookies     []*http.Cookie
+	// IncludeHidden expands collapsed <details> and accordions before the
+	// HTML is captured; renderers created with it set do so for every page.
+	IncludeHidden bool
+	Cookies       []*http.Cookie
 }
 
 // Cache defines the interface for content caching
@@ -4,15 +4,24 @@ import "time"
 
 // Document represents a processed documentation page
 type Document struct {
+	// ID is a stable identifier derived from the canonical URL (see
+	// utils.DocumentID), unaffected by the content hash algorithm.
+	ID             string              `json:"id,omitempty"`
 	URL            string              `json:"url"`
 	Title          string              `json:"title"`
 	Description    string              `json:"description,omitempty"`
+	CanonicalURL   string              `json:"canonical_url,omitempty"`
+	Keywords       []string            `json:"keywords,omitempty"`
+	OpenGraph      map[string]string   `json:"open_graph,omitempty"`
 	Content        string              `json:"-"` // Markdown content (not in JSON)
 	HTMLContent    string              `json:"-"` // Original HTML (not in JSON)
 	FetchedAt      time.Time           `json:"fetched_at"`
 	ContentHash    string              `json:"content_hash"`
+	HashAlgorithm  string              `json:"hash_algorithm,omitempty"` // Digest of ContentHash (sha256, blake3)
 	WordCount      int                 `json:"word_count"`
 	CharCount      int                 `json:"char_count"`
+	ReadingTime    int                 `json:"reading_time_minutes,omitempty"` // Estimated minutes to read
+	Language       string              `json:"language,omitempty"`             // Detected ISO 639-1 code
 	Links          []string            `json:"links,omitempty"`
 	Headers        map[string][]string `json:"headers,omitempty"` // h1, h2, h3...
 	RenderedWithJS bool                `json:"rendered_with_js"`
@@ -20,6 +29,15 @@ type Document struct {
 	CacheHit       bool                `json:"cache_hit"`
 	RelativePath   string              `json:"-"`
 	IsRawFile      bool                `json:"-"`
+	// PreserveTree writes the document at RelativePath exactly, mirroring the
+	// source tree instead of the writer's sanitized or flat layout.
+	PreserveTree bool `json:"-"`
+	// Metadata holds extra key/value pairs carried into the output
+	// frontmatter (e.g. preserved source front-matter keys).
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Labels holds the run's key/value tags (--tag, manifest source tags)
+	// so downstream systems can filter the corpus by provenance.
+	Labels map[string]string `json:"labels,omitempty"`
 
 	// LLM-enhanced metadata fields
 	Summary  string   `json:"summary,omitempty"`  // AI-generated summary
@@ -74,13 +92,20 @@ type LLMSLink struct {
 // Deprecated: Metadata is replaced by SimpleMetadata for JSON output.
 // Use SimpleMetadata for cleaner, LLM-evaluation-friendly metadata.
 type Metadata struct {
+	ID             string              `json:"id,omitempty"`
 	URL            string              `json:"url"`
 	Title          string              `json:"title"`
 	Description    string              `json:"description,omitempty"`
+	CanonicalURL   string              `json:"canonical_url,omitempty"`
+	Keywords       []string            `json:"keywords,omitempty"`
+	OpenGraph      map[string]string   `json:"open_graph,omitempty"`
 	FetchedAt      time.Time           `json:"fetched_at"`
 	ContentHash    string              `json:"content_hash"`
+	HashAlgorithm  string              `json:"hash_algorithm,omitempty"`
 	WordCount      int                 `json:"word_count"`
 	CharCount      int                 `json:"char_count"`
+	ReadingTime    int                 `json:"reading_time_minutes,omitempty"`
+	Language       string              `json:"language,omitempty"`
 	Links          []string            `json:"links,omitempty"`
 	Headers        map[string][]string `json:"headers,omitempty"`
 	RenderedWithJS bool                `json:"rendered_with_js"`
@@ -89,18 +114,26 @@ type Metadata struct {
 	Summary        string              `json:"summary,omitempty"`
 	Tags           []string            `json:"tags,omitempty"`
 	Category       string              `json:"category,omitempty"`
+	Labels         map[string]string   `json:"labels,omitempty"`
 }
 
 // ToMetadata converts a Document to Metadata
 func (d *Document) ToMetadata() *Metadata {
 	return &Metadata{
+		ID:             d.ID,
 		URL:            d.URL,
 		Title:          d.Title,
 		Description:    d.Description,
+		CanonicalURL:   d.CanonicalURL,
+		Keywords:       d.Keywords,
+		OpenGraph:      d.OpenGraph,
 		FetchedAt:      d.FetchedAt,
 		ContentHash:    d.ContentHash,
+		HashAlgorithm:  d.HashAlgorithm,
 		WordCount:      d.WordCount,
 		CharCount:      d.CharCount,
+		ReadingTime:    d.ReadingTime,
+		Language:       d.Language,
 		Links:          d.Links,
 		Headers:        d.Headers,
 		RenderedWithJS: d.RenderedWithJS,
@@ -109,11 +142,13 @@ func (d *Document) ToMetadata() *Metadata {
 		Summary:        d.Summary,
 		Tags:           d.Tags,
 		Category:       d.Category,
+		Labels:         d.Labels,
 	}
 }
 
 // Frontmatter represents YAML frontmatter for markdown files
 type Frontmatter struct {
+	ID         string    `yaml:"id,omitempty"`
 	Title      string    `yaml:"title"`
 	URL        string    `yaml:"url"`
 	Source     string    `yaml:"source"`
@@ -123,11 +158,20 @@ type Frontmatter struct {
 	Summary    string    `yaml:"summary,omitempty"`
 	Tags       []string  `yaml:"tags,omitempty"`
 	Category   string    `yaml:"category,omitempty"`
+
+	Description  string            `yaml:"description,omitempty"`
+	CanonicalURL string            `yaml:"canonical_url,omitempty"`
+	Keywords     []string          `yaml:"keywords,omitempty"`
+	OpenGraph    map[string]string `yaml:"open_graph,omitempty"`
+
+	Metadata map[string]string `yaml:"metadata,omitempty"`
+	Labels   map[string]string `yaml:"labels,omitempty"`
 }
 
 // ToFrontmatter converts a Document to Frontmatter
 func (d *Document) ToFrontmatter() *Frontmatter {
 	return &Frontmatter{
+		ID:         d.ID,
 		Title:      d.Title,
 		URL:        d.URL,
 		Source:     d.SourceStrategy,
@@ -137,6 +181,13 @@ func (d *Document) ToFrontmatter() *Frontmatter {
 		Summary:    d.Summary,
 		Tags:       d.Tags,
 		Category:   d.Category,
+		Metadata:   d.Metadata,
+		Labels:     d.Labels,
+
+		Description:  d.Description,
+		CanonicalURL: d.CanonicalURL,
+		Keywords:     d.Keywords,
+		OpenGraph:    d.OpenGraph,
 	}
 }
 
@@ -171,8 +222,10 @@ func (d *Document) ToDocumentMetadata(filePath string) *DocumentMetadata {
 
 // SimpleMetadata represents simplified document metadata for JSON output
 // This is a cleaner structure optimized for LLM evaluation, containing only
-// essential fields without technical metadata like content_hash, word_count, etc.
+// essential fields without technical metadata like word_count, etc. The ID
+// and content hash are kept so documents can be deduplicated and tracked.
 type SimpleMetadata struct {
+	ID          string    `json:"id,omitempty"`
 	Title       string    `json:"title"`
 	URL         string    `json:"url"`
 	Source      string    `json:"source"`
@@ -181,6 +234,17 @@ type SimpleMetadata struct {
 	Summary     string    `json:"summary,omitempty"`
 	Tags        []string  `json:"tags,omitempty"`
 	Category    string    `json:"category,omitempty"`
+	ReadingTime int       `json:"reading_time_minutes,omitempty"`
+	Language    string    `json:"language,omitempty"`
+
+	CanonicalURL string            `json:"canonical_url,omitempty"`
+	Keywords     []string          `json:"keywords,omitempty"`
+	OpenGraph    map[string]string `json:"open_graph,omitempty"`
+
+	ContentHash   string `json:"content_hash,omitempty"`
+	HashAlgorithm string `json:"hash_algorithm,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // SimpleDocumentMetadata adds file_path to SimpleMetadata for document indexing
@@ -201,6 +265,7 @@ type SimpleMetadataIndex struct {
 // ToSimpleMetadata converts a Document to SimpleMetadata
 func (d *Document) ToSimpleMetadata() *SimpleMetadata {
 	return &SimpleMetadata{
+		ID:          d.ID,
 		Title:       d.Title,
 		URL:         d.URL,
 		Source:      d.SourceStrategy,
@@ -209,6 +274,17 @@ func (d *Document) ToSimpleMetadata() *SimpleMetadata {
 		Summary:     d.Summary,
 		Tags:        d.Tags,
 		Category:    d.Category,
+		ReadingTime: d.ReadingTime,
+		Language:    d.Language,
+
+		CanonicalURL: d.CanonicalURL,
+		Keywords:     d.Keywords,
+		OpenGraph:    d.OpenGraph,
+
+		ContentHash:   d.ContentHash,
+		HashAlgorithm: d.HashAlgorithm,
+
+		Labels: d.Labels,
 	}
 }
 
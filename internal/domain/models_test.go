@@ -107,6 +107,9 @@ func TestDocument_ToSimpleMetadata(t *testing.T) {
 		Summary:        "Test summary",
 		Tags:           []string{"test", "example"},
 		Category:       "testing",
+		ReadingTime:    3,
+		Language:       "en",
+		Labels:         map[string]string{"team": "platform"},
 	}
 
 	simple := doc.ToSimpleMetadata()
@@ -119,6 +122,10 @@ func TestDocument_ToSimpleMetadata(t *testing.T) {
 	assert.Equal(t, doc.Summary, simple.Summary)
 	assert.Equal(t, doc.Tags, simple.Tags)
 	assert.Equal(t, doc.Category, simple.Category)
+	assert.Equal(t, 3, simple.ReadingTime)
+	assert.Equal(t, "en", simple.Language)
+	assert.Equal(t, doc.Labels, simple.Labels)
+	assert.Equal(t, doc.Labels, doc.ToFrontmatter().Labels)
 }
 
 // TestDocument_ToSimpleDocumentMetadata tests converting Document to SimpleDocumentMetadata
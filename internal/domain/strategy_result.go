@@ -11,6 +11,7 @@ import (
 type StrategyResult struct {
 	mu        sync.Mutex
 	startedAt time.Time
+	onFailure func()
 
 	Strategy       string
 	EntryURL       string
@@ -19,11 +20,20 @@ type StrategyResult struct {
 	DocsWritten    int
 	DocsSkipped    int
 	DocsFailed     int
+	AuthWalls      int // pages skipped as login walls or paywall stubs
 	BytesWritten   int64
 	Diagnostics    []Diagnostic
+	Failures       []DocumentFailure
 	Duration       time.Duration
 }
 
+// DocumentFailure records a document that could not be fetched, converted
+// or written. The run continues past it unless it runs in strict mode.
+type DocumentFailure struct {
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
 // Diagnostic is a structured signal emitted by a strategy for the recovery
 // validator and later fallback planner phases.
 type Diagnostic struct {
@@ -112,12 +122,85 @@ func (r *StrategyResult) IncSkipped() {
 	r.mu.Unlock()
 }
 
+// IncAuthWall counts a page skipped as a login wall or paywall stub.
+func (r *StrategyResult) IncAuthWall() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.AuthWalls++
+	r.mu.Unlock()
+}
+
 func (r *StrategyResult) IncFailed() {
 	if r == nil {
 		return
 	}
 	r.mu.Lock()
 	r.DocsFailed++
+	onFailure := r.onFailure
+	r.mu.Unlock()
+	if onFailure != nil {
+		onFailure()
+	}
+}
+
+// FailDocument counts a failed document like IncFailed and records pageURL
+// with err (which may be nil) in Failures.
+func (r *StrategyResult) FailDocument(pageURL string, err error) {
+	if r == nil {
+		return
+	}
+	failure := DocumentFailure{URL: pageURL, Error: "unknown error"}
+	if err != nil {
+		failure.Error = err.Error()
+	}
+	r.mu.Lock()
+	r.Failures = append(r.Failures, failure)
+	r.mu.Unlock()
+	r.IncFailed()
+}
+
+// OnFailure registers fn to run after every IncFailed, e.g. to enforce a
+// run-wide error budget.
+func (r *StrategyResult) OnFailure(fn func()) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.onFailure = fn
+	r.mu.Unlock()
+}
+
+// ClearFailed undoes one IncFailed, for a page that later succeeded (e.g. in
+// the end-of-run retry sweep).
+func (r *StrategyResult) ClearFailed() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.DocsFailed > 0 {
+		r.DocsFailed--
+	}
+	r.mu.Unlock()
+}
+
+// ClearFailure undoes FailDocument for pageURL, for a page that later
+// succeeded.
+func (r *StrategyResult) ClearFailure(pageURL string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	for i, failure := range r.Failures {
+		if failure.URL == pageURL {
+			r.Failures = append(r.Failures[:i], r.Failures[i+1:]...)
+			break
+		}
+	}
+	if r.DocsFailed > 0 {
+		r.DocsFailed--
+	}
 	r.mu.Unlock()
 }
 
@@ -178,8 +261,10 @@ type StrategyResultSnapshot struct {
 	DocsWritten    int
 	DocsSkipped    int
 	DocsFailed     int
+	AuthWalls      int
 	BytesWritten   int64
 	Diagnostics    []Diagnostic
+	Failures       []DocumentFailure
 	Duration       time.Duration
 }
 
@@ -199,8 +284,10 @@ func (r *StrategyResult) Snapshot() StrategyResultSnapshot {
 		DocsWritten:    r.DocsWritten,
 		DocsSkipped:    r.DocsSkipped,
 		DocsFailed:     r.DocsFailed,
+		AuthWalls:      r.AuthWalls,
 		BytesWritten:   r.BytesWritten,
 		Diagnostics:    append([]Diagnostic(nil), r.Diagnostics...),
+		Failures:       append([]DocumentFailure(nil), r.Failures...),
 		Duration:       r.Duration,
 	}
 }
@@ -0,0 +1,205 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// Defaults for the per-host circuit breaker.
+const (
+	DefaultHostBreakerThreshold = 10
+	DefaultHostBreakerCooldown  = time.Minute
+)
+
+// HostBreakerOptions configures a HostBreaker.
+type HostBreakerOptions struct {
+	// Threshold is the number of consecutive failures to a host that opens
+	// its breaker. Zero or less disables the breaker.
+	Threshold int
+	// Cooldown is how long an open breaker fails requests fast before one
+	// probe request is let through.
+	Cooldown time.Duration
+	// Window is the longest gap between failures that still counts them as
+	// consecutive; it defaults to Cooldown.
+	Window time.Duration
+}
+
+// HostBreaker is a per-host circuit breaker for fetches, mirroring the LLM
+// circuit breaker: after Threshold consecutive failures to a host, requests to
+// it fail with domain.ErrHostCircuitOpen for Cooldown. Then a single probe is
+// allowed; success closes the breaker, failure opens it again. A nil
+// *HostBreaker allows everything.
+//
+// Only transport errors, 5xx and 429 responses count as failures; other 4xx
+// responses show the host is answering and reset the count.
+type HostBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	window    time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostCircuit
+}
+
+type hostCircuit struct {
+	failures    int
+	lastFailure time.Time
+	openedAt    time.Time // zero while closed
+	probing     bool
+	probeAt     time.Time
+	trips       int
+}
+
+// NewHostBreaker creates a breaker, or returns nil when opts.Threshold
+// disables it.
+func NewHostBreaker(opts HostBreakerOptions) *HostBreaker {
+	if opts.Threshold <= 0 {
+		return nil
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = DefaultHostBreakerCooldown
+	}
+	if opts.Window <= 0 {
+		opts.Window = opts.Cooldown
+	}
+	return &HostBreaker{
+		threshold: opts.Threshold,
+		cooldown:  opts.Cooldown,
+		window:    opts.Window,
+		now:       time.Now,
+		hosts:     make(map[string]*hostCircuit),
+	}
+}
+
+// Allow reports whether a request to rawURL may proceed. It returns a
+// *domain.FetchError wrapping domain.ErrHostCircuitOpen when the host's
+// breaker is open.
+func (b *HostBreaker) Allow(rawURL string) error {
+	if b == nil {
+		return nil
+	}
+	host := breakerHost(rawURL)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.hosts[host]
+	if c == nil || c.openedAt.IsZero() {
+		return nil
+	}
+	now := b.now()
+	if now.Sub(c.openedAt) < b.cooldown {
+		return &domain.FetchError{URL: rawURL, Err: domain.ErrHostCircuitOpen}
+	}
+	// Half-open: let one probe through. A probe that never reports back
+	// (e.g. cancelled) is replaced after another cooldown.
+	if c.probing && now.Sub(c.probeAt) < b.cooldown {
+		return &domain.FetchError{URL: rawURL, Err: domain.ErrHostCircuitOpen}
+	}
+	c.probing = true
+	c.probeAt = now
+	return nil
+}
+
+// Record updates the breaker of rawURL's host with the outcome of a request.
+func (b *HostBreaker) Record(rawURL string, err error) {
+	if b == nil || errors.Is(err, domain.ErrHostCircuitOpen) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	host := breakerHost(rawURL)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.hosts[host]
+	if c == nil {
+		c = &hostCircuit{}
+		b.hosts[host] = c
+	}
+
+	if !isHostFailure(err) {
+		c.failures = 0
+		c.openedAt = time.Time{}
+		c.probing = false
+		return
+	}
+
+	now := b.now()
+	if !c.openedAt.IsZero() {
+		if c.probing {
+			// The half-open probe failed: open again for a full cooldown.
+			c.probing = false
+			c.openedAt = now
+			c.trips++
+		}
+		return
+	}
+	if !c.lastFailure.IsZero() && now.Sub(c.lastFailure) > b.window {
+		c.failures = 0
+	}
+	c.failures++
+	c.lastFailure = now
+	if c.failures >= b.threshold {
+		c.openedAt = now
+		c.failures = 0
+		c.trips++
+	}
+}
+
+// Trips returns how many times each host's breaker opened, for hosts that
+// tripped at least once.
+func (b *HostBreaker) Trips() map[string]int {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	trips := make(map[string]int)
+	for host, c := range b.hosts {
+		if c.trips > 0 {
+			trips[host] = c.trips
+		}
+	}
+	return trips
+}
+
+// TrippedHosts returns the hosts whose breaker opened, sorted.
+func (b *HostBreaker) TrippedHosts() []string {
+	trips := b.Trips()
+	hosts := make([]string, 0, len(trips))
+	for host := range trips {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// isHostFailure reports whether err suggests the host itself is failing,
+// as opposed to answering with a client error for one page.
+func isHostFailure(err error) bool {
+	if err == nil || isRedirectPolicyError(err) {
+		return false
+	}
+	var fetchErr *domain.FetchError
+	if errors.As(err, &fetchErr) && fetchErr.StatusCode >= 400 && fetchErr.StatusCode < 500 {
+		return fetchErr.StatusCode == 429
+	}
+	return true
+}
+
+func breakerHost(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return strings.ToLower(u.Host)
+	}
+	return rawURL
+}
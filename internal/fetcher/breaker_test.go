@@ -0,0 +1,110 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBreaker(threshold int, cooldown time.Duration) (*HostBreaker, *time.Time) {
+	b := NewHostBreaker(HostBreakerOptions{Threshold: threshold, Cooldown: cooldown})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestHostBreaker_OpensAfterThreshold(t *testing.T) {
+	b, now := newTestBreaker(3, time.Minute)
+	serverErr := &domain.FetchError{URL: "https://a.example/x", StatusCode: 500, Err: errors.New("HTTP 500")}
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Allow("https://a.example/x"))
+		b.Record("https://a.example/x", serverErr)
+	}
+
+	err := b.Allow("https://a.example/other")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrHostCircuitOpen)
+	assert.NoError(t, b.Allow("https://b.example/"), "other hosts are unaffected")
+	assert.Equal(t, map[string]int{"a.example": 1}, b.Trips())
+
+	// After the cooldown one probe is allowed; others still fail fast.
+	*now = now.Add(time.Minute)
+	require.NoError(t, b.Allow("https://a.example/probe"))
+	assert.ErrorIs(t, b.Allow("https://a.example/y"), domain.ErrHostCircuitOpen)
+
+	// A failed probe opens the breaker again.
+	b.Record("https://a.example/probe", serverErr)
+	assert.ErrorIs(t, b.Allow("https://a.example/y"), domain.ErrHostCircuitOpen)
+	assert.Equal(t, 2, b.Trips()["a.example"])
+
+	// A successful probe closes it.
+	*now = now.Add(time.Minute)
+	require.NoError(t, b.Allow("https://a.example/probe"))
+	b.Record("https://a.example/probe", nil)
+	assert.NoError(t, b.Allow("https://a.example/y"))
+	assert.Equal(t, []string{"a.example"}, b.TrippedHosts())
+}
+
+func TestHostBreaker_ClientErrorsAndGapsReset(t *testing.T) {
+	b, now := newTestBreaker(2, time.Minute)
+	timeout := errors.New("connection reset")
+	notFound := &domain.FetchError{URL: "https://a.example/x", StatusCode: 404, Err: errors.New("HTTP 404")}
+
+	b.Record("https://a.example/1", timeout)
+	b.Record("https://a.example/2", notFound)
+	b.Record("https://a.example/3", timeout)
+	assert.NoError(t, b.Allow("https://a.example/4"), "a 404 means the host answered")
+
+	*now = now.Add(2 * time.Minute)
+	b.Record("https://a.example/5", timeout)
+	assert.NoError(t, b.Allow("https://a.example/6"), "failures outside the window are not consecutive")
+
+	b.Record("https://a.example/7", &domain.FetchError{StatusCode: 429, Err: errors.New("HTTP 429")})
+	assert.ErrorIs(t, b.Allow("https://a.example/8"), domain.ErrHostCircuitOpen)
+
+	b.Record("https://c.example/", context.Canceled)
+	assert.Empty(t, b.Trips()["c.example"])
+}
+
+func TestHostBreaker_Disabled(t *testing.T) {
+	b := NewHostBreaker(HostBreakerOptions{Threshold: 0})
+	assert.Nil(t, b)
+	assert.NoError(t, b.Allow("https://a.example/"))
+	b.Record("https://a.example/", errors.New("boom"))
+	assert.Empty(t, b.Trips())
+}
+
+func TestClient_HostBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientOptions{
+		EnableCache: false,
+		HostBreaker: NewHostBreaker(HostBreakerOptions{Threshold: 2, Cooldown: time.Hour}),
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		_, err := client.Get(ctx, server.URL+"/page")
+		require.Error(t, err)
+	}
+
+	assert.Equal(t, int32(2), requests.Load(), "requests fail fast once the breaker is open")
+	_, err = client.Get(ctx, server.URL+"/other")
+	assert.ErrorIs(t, err, domain.ErrHostCircuitOpen)
+}
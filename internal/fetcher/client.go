@@ -11,17 +11,30 @@ import (
 	fhttp "github.com/bogdanfinn/fhttp"
 	tls_client "github.com/bogdanfinn/tls-client"
 	"github.com/bogdanfinn/tls-client/profiles"
+	"github.com/quantmind-br/repodocs/internal/converter"
 	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
 // Client is a stealth HTTP client using tls-client
 type Client struct {
 	tlsClient    tls_client.HttpClient
 	userAgent    string
+	userAgents   *utils.UserAgentRotator
 	retrier      *Retrier
 	cache        domain.Cache
 	cacheEnabled bool
 	cacheTTL     time.Duration
+	// forceContentType, when set, replaces the resolved content type of
+	// every response.
+	forceContentType string
+	// preferMarkdown asks servers for markdown before HTML.
+	preferMarkdown bool
+	breaker        *HostBreaker
+	throttle       *HostThrottle
+	rateLimiter    *HostRateLimiter
+	// flights coalesces concurrent cache misses for the same URL.
+	flights flightGroup
 }
 
 // ClientOptions contains options for creating a Client
@@ -32,7 +45,22 @@ type ClientOptions struct {
 	CacheTTL    time.Duration
 	Cache       domain.Cache
 	UserAgent   string
-	ProxyURL    string
+	// UserAgents are rotated per request instead of UserAgent when set; all
+	// retries of a request keep the same one.
+	UserAgents []string
+	ProxyURL   string
+	// ForceContentType overrides the content type of every response (e.g.
+	// "text/markdown"). Empty resolves it from the headers, URL and body.
+	ForceContentType string
+	// PreferMarkdown fetches raw markdown where a host offers it: known code
+	// hosts are read from their raw-content URLs and other servers are sent
+	// an Accept header preferring text/markdown. HTML is used otherwise.
+	PreferMarkdown bool
+	// HostBreaker, when set, fails requests fast to hosts that keep failing.
+	HostBreaker *HostBreaker
+	// Redirects decides which redirects are followed. The zero policy follows
+	// none: redirect responses are returned with FinalURL set to their target.
+	Redirects RedirectPolicy
 }
 
 // DefaultClientOptions returns default client options
@@ -62,7 +90,9 @@ func NewClient(opts ClientOptions) (*Client, error) {
 		tls_client.WithTimeoutSeconds(int(tlsTimeout.Seconds())),
 		tls_client.WithClientProfile(profiles.Chrome_131),
 		tls_client.WithRandomTLSExtensionOrder(),
-		tls_client.WithNotFollowRedirects(),
+		tls_client.WithCustomRedirectFunc(opts.Redirects.checkRedirect),
+		// Bodies are decoded by doRequest; see decodeBody.
+		tls_client.WithTransportOptions(&tls_client.TransportOptions{DisableCompression: true}),
 	}
 
 	if opts.ProxyURL != "" {
@@ -85,10 +115,15 @@ func NewClient(opts ClientOptions) (*Client, error) {
 	return &Client{
 		tlsClient:    tlsClient,
 		userAgent:    opts.UserAgent,
+		userAgents:   utils.NewUserAgentRotator(opts.UserAgents),
 		retrier:      retrier,
 		cache:        opts.Cache,
 		cacheEnabled: opts.EnableCache,
 		cacheTTL:     opts.CacheTTL,
+
+		forceContentType: opts.ForceContentType,
+		preferMarkdown:   opts.PreferMarkdown,
+		breaker:          opts.HostBreaker,
 	}, nil
 }
 
@@ -99,44 +134,116 @@ func (c *Client) Get(ctx context.Context, url string) (*domain.Response, error)
 
 // GetWithHeaders fetches content with custom headers
 func (c *Client) GetWithHeaders(ctx context.Context, url string, extraHeaders map[string]string) (*domain.Response, error) {
+	if !c.cacheEnabled || c.cache == nil {
+		resp, err := c.fetch(ctx, url, extraHeaders)
+		if err != nil {
+			return nil, err
+		}
+		c.resolveContentType(resp)
+		return resp, nil
+	}
+
 	// Check cache first
-	if c.cacheEnabled && c.cache != nil {
-		cached, err := c.getFromCache(ctx, url)
-		if err == nil && cached != nil {
-			return cached, nil
+	cached, err := c.getFromCache(ctx, url)
+	if err == nil && cached != nil {
+		c.resolveContentType(cached)
+		return cached, nil
+	}
+
+	// Concurrent misses for the same URL share one fetch, which outlives any
+	// single caller's context.
+	resp, shared, err := c.flights.do(ctx, flightKey(url, extraHeaders), func(ctx context.Context) (*domain.Response, error) {
+		return c.fetch(ctx, url, extraHeaders)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the response; callers that shared the fetch leave it to the one
+	// that started it.
+	if !shared && resp != nil {
+		_ = c.saveToCache(ctx, url, resp)
+	}
+
+	c.resolveContentType(resp)
+	return resp, nil
+}
+
+// fetch performs the request with retry. When markdown is preferred, a raw
+// markdown URL is tried first and the original URL is then requested with a
+// markdown-first Accept header.
+func (c *Client) fetch(ctx context.Context, url string, extraHeaders map[string]string) (*domain.Response, error) {
+	if c.preferMarkdown {
+		if rawURL, ok := RawMarkdownURL(url); ok {
+			if resp, err := c.retryRequest(ctx, rawURL, extraHeaders); err == nil {
+				return resp, nil
+			}
 		}
+		extraHeaders = withMarkdownAccept(extraHeaders)
 	}
+	return c.retryRequest(ctx, url, extraHeaders)
+}
+
+func (c *Client) retryRequest(ctx context.Context, url string, extraHeaders map[string]string) (*domain.Response, error) {
+	// Pick the User-Agent once so every retry of the page presents the same.
+	userAgent := c.nextUserAgent()
 
-	// Perform request with retry
 	var resp *domain.Response
 	err := c.retrier.Retry(ctx, func() error {
+		if err := c.rateLimiter.Wait(ctx, url); err != nil {
+			return err
+		}
+		if err := c.throttle.Wait(ctx, url); err != nil {
+			return err
+		}
+		if err := c.breaker.Allow(url); err != nil {
+			return err
+		}
 		var err error
-		resp, err = c.doRequest(ctx, url, extraHeaders)
+		resp, err = c.doRequest(ctx, url, userAgent, extraHeaders)
+		c.breaker.Record(url, err)
 		return err
 	})
-
 	if err != nil {
 		return nil, err
 	}
+	return resp, nil
+}
 
-	// Cache the response
-	if c.cacheEnabled && c.cache != nil && resp != nil {
-		_ = c.saveToCache(ctx, url, resp)
+// nextUserAgent returns the next User-Agent of the rotation, or the fixed
+// one. An empty result makes each request pick a random browser User-Agent.
+func (c *Client) nextUserAgent() string {
+	if userAgent := c.userAgents.Next(); userAgent != "" {
+		return userAgent
 	}
+	return c.userAgent
+}
 
-	return resp, nil
+// hasUserAgent reports whether a fixed or rotated User-Agent is configured
+func (c *Client) hasUserAgent() bool {
+	return c.userAgent != "" || c.userAgents != nil
+}
+
+// resolveContentType sets resp.ContentType to the forced content type, or
+// else to the type resolved from a generic header, the URL and the body.
+func (c *Client) resolveContentType(resp *domain.Response) {
+	if c.forceContentType != "" {
+		resp.ContentType = c.forceContentType
+		return
+	}
+	resp.ContentType = converter.ResolveContentType(resp.ContentType, resp.URL, resp.Body)
 }
 
 // doRequest performs the actual HTTP request
-func (c *Client) doRequest(ctx context.Context, targetURL string, extraHeaders map[string]string) (*domain.Response, error) {
+func (c *Client) doRequest(ctx context.Context, targetURL, userAgent string, extraHeaders map[string]string) (*domain.Response, error) {
 	// Create request using fhttp (tls-client's http package)
-	req, err := fhttp.NewRequest(fhttp.MethodGet, targetURL, nil)
+	req, err := fhttp.NewRequestWithContext(ctx, fhttp.MethodGet, targetURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Apply stealth headers
-	headers := StealthHeaders(c.userAgent)
+	headers := StealthHeaders(userAgent)
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
@@ -146,6 +253,14 @@ func (c *Client) doRequest(ctx context.Context, targetURL string, extraHeaders m
 		req.Header.Set(k, v)
 	}
 
+	// Over HTTP/1.1 the transport decodes the response whenever the
+	// canonical Accept-Encoding names gzip, and hangs on deflate bodies.
+	// Sending the header under its lowercase name leaves decoding to us.
+	if acceptEncoding := req.Header.Get("Accept-Encoding"); acceptEncoding != "" {
+		req.Header.Del("Accept-Encoding")
+		req.Header["accept-encoding"] = []string{acceptEncoding}
+	}
+
 	// Perform request
 	resp, err := c.tlsClient.Do(req)
 	if err != nil {
@@ -177,18 +292,43 @@ func (c *Client) doRequest(ctx context.Context, targetURL string, extraHeaders m
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Decode the body unless the transport already did, and drop the
+	// header so callers don't decode it again.
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != "" {
+		if !resp.Uncompressed {
+			body, err = decodeBody(body, encoding)
+			if err != nil {
+				return nil, &domain.FetchError{URL: targetURL, StatusCode: resp.StatusCode, Err: err}
+			}
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+	}
+
 	// Convert fhttp.Header to http.Header
 	httpHeaders := make(http.Header)
 	for k, v := range resp.Header {
 		httpHeaders[k] = v
 	}
 
+	// A followed redirect ends at the last request; one left unfollowed
+	// points at its Location.
+	var finalURL string
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		if loc, err := resp.Location(); err == nil && loc.String() != targetURL {
+			finalURL = loc.String()
+		}
+	} else if resp.Request != nil && resp.Request.URL.String() != targetURL {
+		finalURL = resp.Request.URL.String()
+	}
+
 	return &domain.Response{
 		StatusCode:  resp.StatusCode,
 		Body:        body,
 		Headers:     httpHeaders,
 		ContentType: resp.Header.Get("Content-Type"),
 		URL:         targetURL,
+		FinalURL:    finalURL,
 		FromCache:   false,
 	}, nil
 }
@@ -232,12 +372,12 @@ func (c *Client) getFromCache(ctx context.Context, url string) (*domain.Response
 		return nil, err
 	}
 
+	// The cache stores bodies only; the content type is resolved by sniffing.
 	return &domain.Response{
-		StatusCode:  200,
-		Body:        data,
-		ContentType: "text/html",
-		URL:         url,
-		FromCache:   true,
+		StatusCode: 200,
+		Body:       data,
+		URL:        url,
+		FromCache:  true,
 	}, nil
 }
 
@@ -254,6 +394,18 @@ func (c *Client) SetCache(cache domain.Cache) {
 	c.cache = cache
 }
 
+// SetHostThrottle spaces the requests to each host by the throttle's crawl
+// delay; nil disables it. Cached responses are never delayed.
+func (c *Client) SetHostThrottle(throttle *HostThrottle) {
+	c.throttle = throttle
+}
+
+// SetHostRateLimiter limits the requests per second to each host; nil
+// disables it. Cached responses are never delayed.
+func (c *Client) SetHostRateLimiter(limiter *HostRateLimiter) {
+	c.rateLimiter = limiter
+}
+
 // SetCacheEnabled enables or disables caching
 func (c *Client) SetCacheEnabled(enabled bool) {
 	c.cacheEnabled = enabled
@@ -2,6 +2,7 @@
 // transport customization.
 //
 // It implements the domain Fetcher interface, applies browser-like headers and
-// stealth behavior, reuses cached responses when available, and retries
+// stealth behavior, reuses cached responses when available, coalesces
+// concurrent cache misses for the same URL into one request, and retries
 // transient failures through configurable HTTP transports.
 package fetcher
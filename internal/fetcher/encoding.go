@@ -0,0 +1,51 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// decodeBody undoes contentEncoding, a Content-Encoding header listing the
+// codings in the order they were applied to body. It supports gzip, deflate
+// (zlib-wrapped, or raw as some servers send it) and br; identity and an
+// empty header leave body as is. A coding whose data does not decode is
+// skipped, as servers sometimes list codings they did not apply; an unknown
+// coding is an error.
+func decodeBody(body []byte, contentEncoding string) ([]byte, error) {
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		var decoded []byte
+		var err error
+		switch coding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			var gz *gzip.Reader
+			if gz, err = gzip.NewReader(bytes.NewReader(body)); err == nil {
+				decoded, err = io.ReadAll(gz)
+			}
+		case "deflate":
+			if zr, zerr := zlib.NewReader(bytes.NewReader(body)); zerr == nil {
+				decoded, err = io.ReadAll(zr)
+			} else {
+				decoded, err = io.ReadAll(flate.NewReader(bytes.NewReader(body)))
+			}
+		case "br":
+			decoded, err = io.ReadAll(brotli.NewReader(bytes.NewReader(body)))
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %q", coding)
+		}
+		if err == nil {
+			body = decoded
+		}
+	}
+	return body, nil
+}
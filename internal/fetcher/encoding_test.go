@@ -0,0 +1,61 @@
+package fetcher
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compress(t *testing.T, newWriter func(io.Writer) io.WriteCloser, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := newWriter(&buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestDecodeBody(t *testing.T) {
+	html := []byte("<html><body><h1>Guide</h1><p>Install the package.</p></body></html>")
+	gzipped := compress(t, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }, html)
+	brotlied := compress(t, func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) }, html)
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{"identity", "identity", html},
+		{"gzip", "gzip", gzipped},
+		{"x-gzip", "X-Gzip", gzipped},
+		{"br", "br", brotlied},
+		{"zlib deflate", "deflate", compress(t, func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }, html)},
+		{"raw deflate", "deflate", compress(t, func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		}, html)},
+		{"gzip then br", "gzip, br", compress(t, func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) }, gzipped)},
+		{"coding not applied", "gzip, deflate", gzipped},
+		{"not encoded", "gzip", html},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := decodeBody(tt.body, tt.encoding)
+			require.NoError(t, err)
+			assert.Equal(t, string(html), string(decoded))
+		})
+	}
+}
+
+func TestDecodeBody_Unsupported(t *testing.T) {
+	_, err := decodeBody([]byte("data"), "compress")
+	assert.ErrorContains(t, err, `unsupported content encoding "compress"`)
+}
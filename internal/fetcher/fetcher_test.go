@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -145,6 +147,76 @@ func TestClient_Get(t *testing.T) {
 	})
 }
 
+// TestClient_Get_UserAgentRotation tests that User-Agents rotate per request
+// and stay the same across the retries of one request
+func TestClient_Get_UserAgentRotation(t *testing.T) {
+	var mu sync.Mutex
+	var agents []string
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		agents = append(agents, r.Header.Get("User-Agent"))
+		if r.URL.Path == "/flaky" {
+			attempts++
+			if attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientOptions{EnableCache: false, UserAgents: []string{"agent-a", "agent-b"}})
+	require.NoError(t, err)
+	defer client.Close()
+	client.retrier = NewRetrier(RetrierOptions{MaxRetries: 2, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 1})
+
+	ctx := context.Background()
+	for _, path := range []string{"/one", "/two", "/flaky"} {
+		_, err := client.Get(ctx, server.URL+path)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"agent-a", "agent-b", "agent-a", "agent-a"}, agents)
+}
+
+// TestStealthTransport_UserAgent tests that a configured User-Agent replaces
+// the caller's
+func TestStealthTransport_UserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		name string
+		opts ClientOptions
+		want string
+	}{
+		{name: "caller User-Agent without configuration", opts: ClientOptions{}, want: "colly"},
+		{name: "fixed User-Agent", opts: ClientOptions{UserAgent: "fixed"}, want: "fixed"},
+		{name: "rotated User-Agent", opts: ClientOptions{UserAgents: []string{"rotated"}}, want: "rotated"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.opts)
+			require.NoError(t, err)
+			defer client.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			req.Header.Set("User-Agent", "colly")
+			resp, err := client.Transport().RoundTrip(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 // TestClient_GetWithHeaders tests fetching with custom headers
 func TestClient_GetWithHeaders(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -421,14 +493,21 @@ func TestParseRetryAfter(t *testing.T) {
 		{"empty string", "", 0},
 		{"zero value", "0", 0},
 		{"large value", "3600", 3600 * time.Second},
+		{"surrounding space", " 5 ", 5 * time.Second},
+		{"negative value", "-5", 0},
+		{"invalid value", "soon", 0},
+		{"HTTP date", "Wed, 21 Oct 2015 07:28:30 GMT", 30 * time.Second},
+		{"HTTP date in the past", "Wed, 21 Oct 2015 07:27:00 GMT", 0},
 	}
 
+	now := time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := ParseRetryAfter(tt.header)
+			result := parseRetryAfterAt(tt.header, now)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
+	assert.Equal(t, 120*time.Second, ParseRetryAfter("120"))
 }
 
 // TestRandomUserAgent tests random user agent generation
@@ -567,3 +646,138 @@ func (m *mockCache) Size() int64 {
 	}
 	return 0
 }
+
+// TestClient_Get_ContentTypeResolution tests that mislabeled responses get a
+// resolved content type and that a forced type wins.
+func TestClient_Get_ContentTypeResolution(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/guide":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte("# Guide\n\nSome *markdown* text.\n"))
+		case "/page":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("<!DOCTYPE html><html><body><h1>Page</h1></body></html>"))
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("# Not really HTML"))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	t.Run("sniffed", func(t *testing.T) {
+		client, err := NewClient(ClientOptions{EnableCache: false, MaxRetries: 0})
+		require.NoError(t, err)
+		defer client.Close()
+
+		resp, err := client.Get(ctx, server.URL+"/guide")
+		require.NoError(t, err)
+		assert.Equal(t, "text/markdown", resp.ContentType, "markdown sent as octet-stream")
+
+		resp, err = client.Get(ctx, server.URL+"/page")
+		require.NoError(t, err)
+		assert.Equal(t, "text/html", resp.ContentType, "HTML sent as text/plain")
+
+		resp, err = client.Get(ctx, server.URL+"/specific")
+		require.NoError(t, err)
+		assert.Equal(t, "text/html; charset=utf-8", resp.ContentType, "specific headers are trusted")
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		client, err := NewClient(ClientOptions{EnableCache: false, MaxRetries: 0, ForceContentType: "text/markdown"})
+		require.NoError(t, err)
+		defer client.Close()
+
+		resp, err := client.Get(ctx, server.URL+"/page")
+		require.NoError(t, err)
+		assert.Equal(t, "text/markdown", resp.ContentType)
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		client, err := NewClient(ClientOptions{
+			EnableCache: true,
+			Cache:       &mockCache{data: []byte("## Cached\n\n```go\nfmt.Println()\n```\n")},
+		})
+		require.NoError(t, err)
+		defer client.Close()
+
+		resp, err := client.Get(ctx, server.URL+"/guide")
+		require.NoError(t, err)
+		assert.True(t, resp.FromCache)
+		assert.Equal(t, "text/markdown", resp.ContentType)
+	})
+}
+
+// TestRawMarkdownURL tests rewriting code host file views to raw content
+func TestRawMarkdownURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"https://github.com/owner/repo/blob/main/docs/guide.md", "https://raw.githubusercontent.com/owner/repo/main/docs/guide.md", true},
+		{"https://github.com/owner/repo/blob/main/README.md?plain=1#usage", "https://raw.githubusercontent.com/owner/repo/main/README.md", true},
+		{"https://gitlab.com/group/sub/repo/-/blob/main/doc/index.md", "https://gitlab.com/group/sub/repo/-/raw/main/doc/index.md", true},
+		{"https://bitbucket.org/owner/repo/src/main/docs/intro.md", "https://bitbucket.org/owner/repo/raw/main/docs/intro.md", true},
+		{"https://codeberg.org/owner/repo/src/branch/main/README.md", "https://codeberg.org/owner/repo/raw/branch/main/README.md", true},
+		{"https://github.com/owner/repo/blob/main/main.go", "", false},
+		{"https://github.com/owner/repo/tree/main/docs.md", "", false},
+		{"https://github.com/owner/repo", "", false},
+		{"https://docs.example.com/guide.md", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := RawMarkdownURL(tt.input)
+		assert.Equal(t, tt.ok, ok, tt.input)
+		assert.Equal(t, tt.expected, got, tt.input)
+	}
+}
+
+// TestClient_Get_PreferMarkdown tests Accept negotiation and HTML fallback
+func TestClient_Get_PreferMarkdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/negotiates" && strings.Contains(r.Header.Get("Accept"), "text/markdown") {
+			w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+			w.Write([]byte("# Guide"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body><h1>Guide</h1></body></html>"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+
+	client, err := NewClient(ClientOptions{EnableCache: false, MaxRetries: 0, PreferMarkdown: true})
+	require.NoError(t, err)
+	defer client.Close()
+
+	resp, err := client.Get(ctx, server.URL+"/negotiates")
+	require.NoError(t, err)
+	assert.Equal(t, "text/markdown; charset=utf-8", resp.ContentType)
+	assert.Equal(t, "# Guide", string(resp.Body))
+
+	resp, err = client.Get(ctx, server.URL+"/html-only")
+	require.NoError(t, err)
+	assert.Equal(t, "text/html", resp.ContentType, "falls back to HTML")
+
+	// Without the option the stealth Accept header is sent unchanged.
+	plain, err := NewClient(ClientOptions{EnableCache: false, MaxRetries: 0})
+	require.NoError(t, err)
+	defer plain.Close()
+
+	resp, err = plain.Get(ctx, server.URL+"/negotiates")
+	require.NoError(t, err)
+	assert.Equal(t, "text/html", resp.ContentType)
+}
+
+// TestWithMarkdownAccept tests that explicit Accept headers are kept
+func TestWithMarkdownAccept(t *testing.T) {
+	assert.Equal(t, MarkdownAccept, withMarkdownAccept(nil)["Accept"])
+	assert.Equal(t, MarkdownAccept, withMarkdownAccept(map[string]string{"accept": "*/*"})["Accept"])
+
+	custom := map[string]string{"Accept": "application/json", "X-Key": "v"}
+	assert.Equal(t, custom, withMarkdownAccept(custom))
+}
@@ -0,0 +1,132 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// errFlightAborted is returned to the callers of a shared fetch that ended
+// without a result.
+var errFlightAborted = errors.New("shared fetch aborted")
+
+// flightGroup coalesces concurrent fetches of the same key into a single
+// network request whose response is shared by every caller. The shared
+// fetch is detached from the contexts of its callers: one caller giving up
+// does not cancel it for the others, and it is only cancelled once every
+// caller has given up.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is an in-flight fetch and the callers waiting for it.
+type flightCall struct {
+	done    chan struct{}
+	resp    *domain.Response
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do returns the response of fn for key, running fn only when no fetch of
+// key is in flight. shared reports whether the response came from another
+// caller's fetch. Each caller gets its own copy of the response.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) (*domain.Response, error)) (resp *domain.Response, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, shared := g.calls[key]
+	if shared {
+		call.waiters++
+	} else {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = call
+		go g.run(flightCtx, key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, shared, call.err
+		}
+		return cloneResponse(call.resp), shared, nil
+	case <-ctx.Done():
+		g.leave(key, call)
+		return nil, shared, ctx.Err()
+	}
+}
+
+func (g *flightGroup) run(ctx context.Context, key string, call *flightCall, fn func(context.Context) (*domain.Response, error)) {
+	// Release the waiters even when fn never returns normally.
+	call.err = errFlightAborted
+	defer func() {
+		g.mu.Lock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		call.cancel()
+		close(call.done)
+	}()
+	call.resp, call.err = fn(ctx)
+}
+
+// leave drops a caller that stopped waiting. The last one cancels the fetch
+// and forgets it, so later callers start a fresh fetch instead of joining a
+// cancelled one.
+func (g *flightGroup) leave(key string, call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	call.cancel()
+}
+
+// flightKey identifies a request by its URL and extra headers, so requests
+// that differ only in headers are not coalesced.
+func flightKey(url string, extraHeaders map[string]string) string {
+	if len(extraHeaders) == 0 {
+		return url
+	}
+	names := make([]string, 0, len(extraHeaders))
+	for name := range extraHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.WriteString(url)
+	for _, name := range names {
+		key.WriteString("\n")
+		key.WriteString(strings.ToLower(name))
+		key.WriteString(": ")
+		key.WriteString(extraHeaders[name])
+	}
+	return key.String()
+}
+
+// cloneResponse copies resp so callers sharing a fetch can modify their
+// response independently.
+func cloneResponse(resp *domain.Response) *domain.Response {
+	if resp == nil {
+		return nil
+	}
+	clone := *resp
+	clone.Body = bytes.Clone(resp.Body)
+	clone.Headers = resp.Headers.Clone()
+	return &clone
+}
@@ -0,0 +1,164 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightGroup_CoalescesConcurrentCalls(t *testing.T) {
+	var group flightGroup
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (*domain.Response, error) {
+		calls.Add(1)
+		<-release
+		return &domain.Response{StatusCode: 200, Body: []byte("body")}, nil
+	}
+
+	const waiters = 5
+	var wg sync.WaitGroup
+	results := make([]*domain.Response, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, _, err := group.do(context.Background(), "https://a.example/", fetch)
+			assert.NoError(t, err)
+			results[i] = resp
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, resp := range results {
+		require.NotNil(t, resp)
+		assert.Equal(t, "body", string(resp.Body))
+	}
+	results[0].Body[0] = 'X'
+	assert.Equal(t, "body", string(results[1].Body), "each caller gets its own copy")
+}
+
+func TestFlightGroup_CancelledWaiterDoesNotCancelFetch(t *testing.T) {
+	var group flightGroup
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (*domain.Response, error) {
+		close(started)
+		select {
+		case <-release:
+			return &domain.Response{StatusCode: 200}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, _, err := group.do(ctx, "key", fetch)
+		first <- err
+	}()
+	<-started
+
+	second := make(chan error, 1)
+	go func() {
+		_, shared, err := group.do(context.Background(), "key", fetch)
+		assert.True(t, shared)
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	close(release)
+	assert.NoError(t, <-second)
+}
+
+func TestFlightGroup_LastWaiterCancelsFetch(t *testing.T) {
+	var group flightGroup
+	cancelled := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	_, _, err := group.do(ctx, "key", func(ctx context.Context) (*domain.Response, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("fetch was not cancelled after its last waiter left")
+	}
+
+	// A new call starts a fresh fetch rather than joining the cancelled one.
+	resp, shared, err := group.do(context.Background(), "key", func(ctx context.Context) (*domain.Response, error) {
+		return &domain.Response{StatusCode: 200}, nil
+	})
+	require.NoError(t, err)
+	assert.False(t, shared)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+// missCache never holds anything, so every Get of the client is a miss.
+type missCache struct{ mockCache }
+
+func (m *missCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+func TestClient_CoalescesConcurrentCacheMisses(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>shared</body></html>"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientOptions{EnableCache: true, Cache: &missCache{}})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(context.Background(), server.URL)
+			if assert.NoError(t, err) {
+				assert.Contains(t, string(resp.Body), "shared")
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestFlightKey(t *testing.T) {
+	assert.Equal(t, "https://a.example/", flightKey("https://a.example/", nil))
+	assert.Equal(t,
+		flightKey("https://a.example/", map[string]string{"Accept": "text/html", "X-A": "1"}),
+		flightKey("https://a.example/", map[string]string{"X-A": "1", "Accept": "text/html"}))
+	assert.NotEqual(t,
+		flightKey("https://a.example/", map[string]string{"Accept": "text/html"}),
+		flightKey("https://a.example/", map[string]string{"Accept": "text/markdown"}))
+}
@@ -0,0 +1,96 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// LimitedFetcherOptions holds the per-host controls a LimitedFetcher
+// applies; nil fields are disabled.
+type LimitedFetcherOptions struct {
+	RateLimiter *HostRateLimiter
+	Throttle    *HostThrottle
+	Breaker     *HostBreaker
+}
+
+// LimitedFetcher applies the per-host rate limit, crawl delay and circuit
+// breaker of the built-in Client to another domain.Fetcher, both to its
+// Get calls and to requests sent through its Transport. Responses the
+// wrapped fetcher serves from its own cache are delayed too.
+type LimitedFetcher struct {
+	domain.Fetcher
+	rateLimiter *HostRateLimiter
+	throttle    *HostThrottle
+	breaker     *HostBreaker
+}
+
+// NewLimitedFetcher wraps f with the controls of opts, or returns f
+// unchanged when all of them are disabled.
+func NewLimitedFetcher(f domain.Fetcher, opts LimitedFetcherOptions) domain.Fetcher {
+	if opts.RateLimiter == nil && opts.Throttle == nil && opts.Breaker == nil {
+		return f
+	}
+	return &LimitedFetcher{
+		Fetcher:     f,
+		rateLimiter: opts.RateLimiter,
+		throttle:    opts.Throttle,
+		breaker:     opts.Breaker,
+	}
+}
+
+// Get fetches url through the wrapped fetcher once the host allows it.
+func (l *LimitedFetcher) Get(ctx context.Context, url string) (*domain.Response, error) {
+	return l.GetWithHeaders(ctx, url, nil)
+}
+
+// GetWithHeaders fetches url with headers through the wrapped fetcher once
+// the host allows it.
+func (l *LimitedFetcher) GetWithHeaders(ctx context.Context, url string, headers map[string]string) (*domain.Response, error) {
+	if err := l.wait(ctx, url); err != nil {
+		return nil, err
+	}
+	var resp *domain.Response
+	var err error
+	if headers == nil {
+		resp, err = l.Fetcher.Get(ctx, url)
+	} else {
+		resp, err = l.Fetcher.GetWithHeaders(ctx, url, headers)
+	}
+	l.breaker.Record(url, err)
+	return resp, err
+}
+
+// Transport returns the wrapped fetcher's transport with the same controls.
+func (l *LimitedFetcher) Transport() http.RoundTripper {
+	return &limitedTransport{next: l.Fetcher.Transport(), limits: l}
+}
+
+// wait blocks until a request to url may start, in the order the Client
+// applies its controls.
+func (l *LimitedFetcher) wait(ctx context.Context, url string) error {
+	if err := l.rateLimiter.Wait(ctx, url); err != nil {
+		return err
+	}
+	if err := l.throttle.Wait(ctx, url); err != nil {
+		return err
+	}
+	return l.breaker.Allow(url)
+}
+
+type limitedTransport struct {
+	next   http.RoundTripper
+	limits *LimitedFetcher
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	if err := t.limits.wait(req.Context(), url); err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	t.limits.breaker.Record(url, err)
+	return resp, err
+}
@@ -0,0 +1,81 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingFetcher fails every request with a server error and counts them.
+type failingFetcher struct {
+	calls int
+}
+
+func (f *failingFetcher) Get(ctx context.Context, url string) (*domain.Response, error) {
+	return f.GetWithHeaders(ctx, url, nil)
+}
+
+func (f *failingFetcher) GetWithHeaders(_ context.Context, url string, _ map[string]string) (*domain.Response, error) {
+	f.calls++
+	return nil, &domain.FetchError{URL: url, StatusCode: 503, Err: errors.New("HTTP 503")}
+}
+
+func (f *failingFetcher) GetCookies(string) []*http.Cookie { return nil }
+
+func (f *failingFetcher) Transport() http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		_, err := f.GetWithHeaders(req.Context(), req.URL.String(), nil)
+		return nil, err
+	})
+}
+
+func (f *failingFetcher) Close() error { return nil }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return fn(req) }
+
+func TestNewLimitedFetcher_Disabled(t *testing.T) {
+	inner := &failingFetcher{}
+	assert.Same(t, inner, NewLimitedFetcher(inner, LimitedFetcherOptions{}))
+}
+
+func TestLimitedFetcher_Breaker(t *testing.T) {
+	inner := &failingFetcher{}
+	breaker, _ := newTestBreaker(2, time.Minute)
+	f := NewLimitedFetcher(inner, LimitedFetcherOptions{Breaker: breaker})
+
+	_, err := f.Get(context.Background(), "https://a.example/one")
+	require.Error(t, err)
+	_, err = (&http.Client{Transport: f.Transport()}).Get("https://a.example/two")
+	require.Error(t, err)
+
+	_, err = f.Get(context.Background(), "https://a.example/three")
+	assert.ErrorIs(t, err, domain.ErrHostCircuitOpen)
+	_, err = (&http.Client{Transport: f.Transport()}).Get("https://a.example/four")
+	assert.ErrorIs(t, err, domain.ErrHostCircuitOpen)
+	assert.Equal(t, 2, inner.calls, "an open breaker fails fast without reaching the fetcher")
+}
+
+func TestLimitedFetcher_Throttle(t *testing.T) {
+	inner := &failingFetcher{}
+	throttle := NewHostThrottle(HostThrottleOptions{Delay: 50 * time.Millisecond})
+	f := NewLimitedFetcher(inner, LimitedFetcherOptions{Throttle: throttle})
+
+	start := time.Now()
+	f.Get(context.Background(), "https://a.example/one")
+	f.Get(context.Background(), "https://a.example/two")
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := f.Get(ctx, "https://a.example/three")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, inner.calls)
+}
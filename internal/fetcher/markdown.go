@@ -0,0 +1,93 @@
+package fetcher
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// MarkdownAccept is the Accept header sent when markdown is preferred: servers
+// that support content negotiation return markdown, others fall back to HTML.
+const MarkdownAccept = "text/markdown, text/x-markdown;q=0.95, text/html;q=0.9, */*;q=0.8"
+
+// RawMarkdownURL rewrites the rendered view of a markdown file on a
+// recognized code host to the URL serving its raw content:
+//
+//	github.com/o/r/blob/ref/doc.md     -> raw.githubusercontent.com/o/r/ref/doc.md
+//	gitlab.com/o/r/-/blob/ref/doc.md   -> gitlab.com/o/r/-/raw/ref/doc.md
+//	bitbucket.org/o/r/src/ref/doc.md   -> bitbucket.org/o/r/raw/ref/doc.md
+//	codeberg.org/o/r/src/branch/x/d.md -> codeberg.org/o/r/raw/branch/x/d.md
+//
+// It reports false for other URLs and for files that are not markdown.
+func RawMarkdownURL(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".md", ".mdx", ".markdown":
+	default:
+		return "", false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 5 {
+		return "", false
+	}
+
+	switch strings.ToLower(u.Hostname()) {
+	case "github.com", "www.github.com":
+		if parts[2] != "blob" {
+			return "", false
+		}
+		u.Host = "raw.githubusercontent.com"
+		u.Path = "/" + strings.Join(append(parts[:2:2], parts[3:]...), "/")
+	case "gitlab.com":
+		idx := indexPair(parts, "-", "blob")
+		if idx < 0 {
+			return "", false
+		}
+		parts[idx+1] = "raw"
+		u.Path = "/" + strings.Join(parts, "/")
+	case "bitbucket.org", "codeberg.org":
+		if parts[2] != "src" {
+			return "", false
+		}
+		parts[2] = "raw"
+		u.Path = "/" + strings.Join(parts, "/")
+	default:
+		return "", false
+	}
+
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), true
+}
+
+// indexPair returns the index of the first a immediately followed by b.
+func indexPair(parts []string, a, b string) int {
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == a && parts[i+1] == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// withMarkdownAccept returns headers with the Accept header set to
+// MarkdownAccept, unless the caller already asked for a specific type.
+func withMarkdownAccept(headers map[string]string) map[string]string {
+	merged := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		if strings.EqualFold(k, "Accept") {
+			if v != "" && v != "*/*" {
+				return headers
+			}
+			continue
+		}
+		merged[k] = v
+	}
+	merged["Accept"] = MarkdownAccept
+	return merged
+}
@@ -0,0 +1,109 @@
+package fetcher
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// HostRateLimiterOptions configures a HostRateLimiter.
+type HostRateLimiterOptions struct {
+	// Rate is the sustained number of requests per second allowed to one
+	// host. Zero or less disables the limiter.
+	Rate float64
+	// Logger receives the per-host waits at debug level.
+	Logger *utils.Logger
+}
+
+// HostRateLimiter is a token bucket per host: each host may burst up to one
+// second's worth of requests (at least one), then requests are admitted at
+// Rate as tokens refill. Hosts have separate buckets, so a manifest that
+// touches several domains never starves one of them. Callers reserve their
+// token, so concurrent waiters are admitted in order. A nil
+// *HostRateLimiter never waits.
+type HostRateLimiter struct {
+	rate   float64
+	burst  float64
+	logger *utils.Logger
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// tokenBucket is the state of one host. tokens goes negative while callers
+// wait for tokens they have already reserved.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewHostRateLimiter creates a limiter, or returns nil when opts.Rate
+// disables it.
+func NewHostRateLimiter(opts HostRateLimiterOptions) *HostRateLimiter {
+	if opts.Rate <= 0 {
+		return nil
+	}
+	return &HostRateLimiter{
+		rate:    opts.Rate,
+		burst:   max(1, math.Floor(opts.Rate)),
+		logger:  opts.Logger,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// Rate returns the requests per second allowed to one host.
+func (l *HostRateLimiter) Rate() float64 {
+	if l == nil {
+		return 0
+	}
+	return l.rate
+}
+
+// Wait blocks until a request to rawURL may start. It returns ctx's error
+// when ctx ends first; the reserved token is not returned, so the host's
+// later requests still keep to the rate.
+func (l *HostRateLimiter) Wait(ctx context.Context, rawURL string) error {
+	if l == nil {
+		return nil
+	}
+	host := breakerHost(rawURL)
+
+	l.mu.Lock()
+	now := l.now()
+	bucket, ok := l.buckets[host]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[host] = bucket
+	}
+	if elapsed := now.Sub(bucket.last); elapsed > 0 {
+		bucket.tokens = min(l.burst, bucket.tokens+elapsed.Seconds()*l.rate)
+		bucket.last = now
+	}
+	bucket.tokens--
+	var wait time.Duration
+	if bucket.tokens < 0 {
+		wait = time.Duration(-bucket.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if l.logger != nil {
+		l.logger.Debug().Str("host", host).Dur("wait", wait).Float64("rate_limit", l.rate).Msg("Waiting for host rate limit")
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
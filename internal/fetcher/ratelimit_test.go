@@ -0,0 +1,125 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHostRateLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, NewHostRateLimiter(HostRateLimiterOptions{}))
+	assert.Nil(t, NewHostRateLimiter(HostRateLimiterOptions{Rate: -1}))
+
+	var limiter *HostRateLimiter
+	assert.NoError(t, limiter.Wait(context.Background(), "https://a.example/"))
+	assert.Zero(t, limiter.Rate())
+}
+
+func TestHostRateLimiter_SpacesRequestsPerHost(t *testing.T) {
+	limiter := NewHostRateLimiter(HostRateLimiterOptions{Rate: 20})
+	ctx := context.Background()
+
+	// The burst of one second's worth of requests is admitted at once, for
+	// each host.
+	start := time.Now()
+	for range 20 {
+		require.NoError(t, limiter.Wait(ctx, "https://a.example/"))
+		require.NoError(t, limiter.Wait(ctx, "https://b.example/"))
+	}
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+
+	// Then requests to a host are admitted 1/20s apart.
+	var gaps []time.Duration
+	last := time.Now()
+	for range 4 {
+		require.NoError(t, limiter.Wait(ctx, "https://A.example/"))
+		now := time.Now()
+		gaps = append(gaps, now.Sub(last))
+		last = now
+	}
+	for _, gap := range gaps {
+		assert.GreaterOrEqual(t, gap, 40*time.Millisecond)
+		assert.Less(t, gap, 250*time.Millisecond)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestHostRateLimiter_ReservesTokens(t *testing.T) {
+	limiter := NewHostRateLimiter(HostRateLimiterOptions{Rate: 0.5})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+
+	require.NoError(t, limiter.Wait(context.Background(), "https://a.example/"))
+	assert.InDelta(t, 0, limiter.buckets["a.example"].tokens, 1e-9)
+
+	// A waiter that gives up keeps its token, so the next one waits behind
+	// it: two seconds per request at half a request per second.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx, "https://a.example/"), context.Canceled)
+	assert.InDelta(t, -1, limiter.buckets["a.example"].tokens, 1e-9)
+
+	// Tokens refill at the rate, capped at the burst of one.
+	now = now.Add(time.Minute)
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, limiter.Wait(ctx, "https://a.example/"))
+	assert.InDelta(t, 0, limiter.buckets["a.example"].tokens, 1e-9)
+}
+
+func TestHostRateLimiter_WaitHonorsCancellation(t *testing.T) {
+	limiter := NewHostRateLimiter(HostRateLimiterOptions{Rate: 0.001})
+	require.NoError(t, limiter.Wait(context.Background(), "https://a.example/"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := limiter.Wait(ctx, "https://a.example/")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_HostRateLimiterSpacesConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientOptions{EnableCache: false})
+	require.NoError(t, err)
+	defer client.Close()
+	client.SetHostRateLimiter(NewHostRateLimiter(HostRateLimiterOptions{Rate: 5}))
+
+	// Eight concurrent workers share the host's bucket: a burst of five,
+	// then one request every 200ms.
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Get(context.Background(), server.URL+"/"+string(rune('a'+i)))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, arrivals, 8)
+	slices.SortFunc(arrivals, func(a, b time.Time) int { return a.Compare(b) })
+	assert.Less(t, arrivals[4].Sub(arrivals[0]), 100*time.Millisecond)
+	for i := 5; i < len(arrivals); i++ {
+		assert.GreaterOrEqual(t, arrivals[i].Sub(arrivals[i-1]), 150*time.Millisecond)
+	}
+	assert.GreaterOrEqual(t, arrivals[7].Sub(arrivals[0]), 550*time.Millisecond)
+}
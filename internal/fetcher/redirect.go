@@ -0,0 +1,100 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	fhttp "github.com/bogdanfinn/fhttp"
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// DefaultMaxRedirects matches the redirect limit of net/http and colly.
+const DefaultMaxRedirects = 10
+
+// ErrCrossHostRedirect reports a redirect to another host that was not
+// followed because cross-host redirects are disabled.
+var ErrCrossHostRedirect = errors.New("cross-host redirect not followed")
+
+// errTooManyRedirects reports a redirect chain longer than the policy allows.
+var errTooManyRedirects = errors.New("too many redirects")
+
+// RedirectPolicy decides which redirects are followed.
+type RedirectPolicy struct {
+	// MaxRedirects is the longest redirect chain followed. 0 uses
+	// DefaultMaxRedirects and a negative value follows none.
+	MaxRedirects int
+	// NoCrossHost drops redirects to a host other than the one first
+	// requested. A change of scheme or port on the same host, such as http
+	// to https, is still followed.
+	NoCrossHost bool
+	// Logger receives dropped cross-host redirects.
+	Logger *utils.Logger
+}
+
+// limit returns the longest redirect chain the policy follows.
+func (p RedirectPolicy) limit() int {
+	switch {
+	case p.MaxRedirects < 0:
+		return 0
+	case p.MaxRedirects == 0:
+		return DefaultMaxRedirects
+	}
+	return p.MaxRedirects
+}
+
+// CheckRedirect applies the policy to a redirect, for use as
+// http.Client.CheckRedirect.
+func (p RedirectPolicy) CheckRedirect(req *http.Request, via []*http.Request) error {
+	return p.check(req.URL, via[0].URL, len(via))
+}
+
+// checkRedirect is CheckRedirect for the tls-client. Redirects are returned
+// unfollowed when the policy follows none or the request asked for that.
+func (p RedirectPolicy) checkRedirect(req *fhttp.Request, via []*fhttp.Request) error {
+	if p.limit() == 0 || !followsRedirects(req.Context()) {
+		return fhttp.ErrUseLastResponse
+	}
+	return p.check(req.URL, via[0].URL, len(via))
+}
+
+// check decides whether the hops-th redirect of a chain started at origin
+// may go on to target.
+func (p RedirectPolicy) check(target, origin *url.URL, hops int) error {
+	if limit := p.limit(); hops > limit {
+		return fmt.Errorf("%w: stopped after %d", errTooManyRedirects, limit)
+	}
+	if p.NoCrossHost && !strings.EqualFold(target.Hostname(), origin.Hostname()) {
+		if p.Logger != nil {
+			p.Logger.Info().
+				Str("url", origin.String()).
+				Str("location", target.String()).
+				Msg("Dropping cross-host redirect")
+		}
+		return fmt.Errorf("%w: %s", ErrCrossHostRedirect, target)
+	}
+	return nil
+}
+
+// isRedirectPolicyError reports whether err is a redirect the policy
+// refused, which says nothing about the health of the host.
+func isRedirectPolicyError(err error) bool {
+	return errors.Is(err, ErrCrossHostRedirect) || errors.Is(err, errTooManyRedirects)
+}
+
+type noRedirectsKey struct{}
+
+// withoutRedirects marks ctx so the client returns redirect responses
+// unfollowed, for callers such as StealthTransport whose own client follows
+// them.
+func withoutRedirects(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRedirectsKey{}, true)
+}
+
+func followsRedirects(ctx context.Context) bool {
+	noRedirects, _ := ctx.Value(noRedirectsKey{}).(bool)
+	return !noRedirects
+}
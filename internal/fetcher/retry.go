@@ -3,6 +3,9 @@ package fetcher
 import (
 	"context"
 	"errors"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -194,32 +197,32 @@ func ShouldRetryStatus(statusCode int) bool {
 	return false
 }
 
-// ParseRetryAfter parses the Retry-After header value
+// ParseRetryAfter parses the Retry-After header value, given as seconds or
+// as an HTTP date. It is 0 when the header is missing, invalid or in the
+// past.
 func ParseRetryAfter(retryAfter string) time.Duration {
+	return parseRetryAfterAt(retryAfter, time.Now())
+}
+
+// parseRetryAfterAt is ParseRetryAfter with HTTP dates taken relative to now.
+func parseRetryAfterAt(retryAfter string, now time.Time) time.Duration {
+	retryAfter = strings.TrimSpace(retryAfter)
 	if retryAfter == "" {
 		return 0
 	}
 
-	// Try to parse as seconds
-	var seconds int
-	if _, err := parseRetryAfterInt(retryAfter, &seconds); err == nil && seconds > 0 {
-		return time.Duration(seconds) * time.Second
+	if at, err := http.ParseTime(retryAfter); err == nil {
+		return max(at.Sub(now), 0)
 	}
 
-	// Try to parse as HTTP date (simplified)
-	// Full parsing would use time.Parse with HTTP date format
-	return 0
-}
-
-// parseRetryAfterInt is a helper to parse retry-after as int
-func parseRetryAfterInt(s string, result *int) (int, error) {
-	n := 0
-	for _, c := range s {
-		if c < '0' || c > '9' {
-			break
-		}
-		n = n*10 + int(c-'0')
+	// Seconds are read up to the first non-digit, so "3.5" waits 3s.
+	end := 0
+	for end < len(retryAfter) && retryAfter[end] >= '0' && retryAfter[end] <= '9' {
+		end++
+	}
+	seconds, err := strconv.Atoi(retryAfter[:end])
+	if err != nil {
+		return 0
 	}
-	*result = n
-	return n, nil
+	return time.Duration(seconds) * time.Second
 }
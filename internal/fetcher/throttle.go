@@ -0,0 +1,120 @@
+package fetcher
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// HostThrottleOptions configures a HostThrottle.
+type HostThrottleOptions struct {
+	// Delay is the minimum time between the starts of consecutive requests
+	// to one host. Zero or less disables the throttle.
+	Delay time.Duration
+	// Jitter is the maximum random delay added before each request, on top
+	// of Delay, so concurrent workers do not start in lockstep. Zero or less
+	// disables it.
+	Jitter time.Duration
+	// Logger receives the per-host delays at debug level.
+	Logger *utils.Logger
+}
+
+// HostThrottle spaces consecutive requests to the same host by a fixed
+// crawl delay, while requests to different hosts proceed in parallel. Each
+// caller reserves the next free slot of its host, so concurrent callers
+// queue up one delay apart. With jitter, each slot starts a random time
+// later and the next slot is a full delay after it, so jitter never brings
+// two requests closer than the delay. A nil *HostThrottle never waits.
+type HostThrottle struct {
+	delay  time.Duration
+	jitter time.Duration
+	logger *utils.Logger
+	now    func() time.Time
+	// randN returns a random duration in [0, n).
+	randN func(n time.Duration) time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time // host -> earliest start of its next request
+}
+
+// NewHostThrottle creates a throttle, or returns nil when opts.Delay and
+// opts.Jitter both disable it.
+func NewHostThrottle(opts HostThrottleOptions) *HostThrottle {
+	if opts.Delay <= 0 && opts.Jitter <= 0 {
+		return nil
+	}
+	return &HostThrottle{
+		delay:  max(opts.Delay, 0),
+		jitter: max(opts.Jitter, 0),
+		logger: opts.Logger,
+		now:    time.Now,
+		randN:  rand.N[time.Duration],
+		next:   make(map[string]time.Time),
+	}
+}
+
+// Delay returns the crawl delay between requests to one host.
+func (t *HostThrottle) Delay() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return t.delay
+}
+
+// Jitter returns the maximum random delay added before each request.
+func (t *HostThrottle) Jitter() time.Duration {
+	if t == nil {
+		return 0
+	}
+	return t.jitter
+}
+
+// Wait blocks until a request to rawURL may start. It returns ctx's error
+// when ctx ends first.
+func (t *HostThrottle) Wait(ctx context.Context, rawURL string) error {
+	if t == nil {
+		return nil
+	}
+	host := breakerHost(rawURL)
+
+	t.mu.Lock()
+	now := t.now()
+	start, seen := t.next[host]
+	if start.Before(now) {
+		start = now
+	}
+	if t.jitter > 0 {
+		start = start.Add(t.randN(t.jitter))
+	}
+	if t.delay > 0 {
+		t.next[host] = start.Add(t.delay)
+	} else {
+		// Without a delay, jittered requests do not queue behind each other.
+		t.next[host] = now
+	}
+	t.mu.Unlock()
+
+	if !seen && t.logger != nil {
+		t.logger.Debug().Str("host", host).Dur("crawl_delay", t.delay).Dur("request_jitter", t.jitter).Msg("Applying crawl delay to host")
+	}
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	if t.logger != nil && t.delay > 0 {
+		t.logger.Debug().Str("host", host).Dur("wait", wait).Dur("crawl_delay", t.delay).Msg("Waiting for host crawl delay")
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
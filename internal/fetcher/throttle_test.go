@@ -0,0 +1,112 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHostThrottle_Disabled(t *testing.T) {
+	assert.Nil(t, NewHostThrottle(HostThrottleOptions{}))
+	assert.Nil(t, NewHostThrottle(HostThrottleOptions{Delay: -time.Second}))
+	assert.Nil(t, NewHostThrottle(HostThrottleOptions{Jitter: -time.Second}))
+
+	var throttle *HostThrottle
+	assert.NoError(t, throttle.Wait(context.Background(), "https://a.example/"))
+	assert.Zero(t, throttle.Delay())
+	assert.Zero(t, throttle.Jitter())
+}
+
+func TestHostThrottle_SpacesRequestsPerHost(t *testing.T) {
+	throttle := NewHostThrottle(HostThrottleOptions{Delay: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	start := time.Now()
+	require.NoError(t, throttle.Wait(ctx, "https://a.example/1"))
+	require.NoError(t, throttle.Wait(ctx, "https://b.example/1"))
+	assert.Less(t, time.Since(start), 40*time.Millisecond, "first requests to each host do not wait")
+
+	require.NoError(t, throttle.Wait(ctx, "https://A.example/2"))
+	require.NoError(t, throttle.Wait(ctx, "https://a.example/3"))
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond, "requests to one host are a delay apart")
+}
+
+func TestHostThrottle_ReservesSlots(t *testing.T) {
+	throttle := NewHostThrottle(HostThrottleOptions{Delay: time.Minute})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	throttle.now = func() time.Time { return now }
+
+	require.NoError(t, throttle.Wait(context.Background(), "https://a.example/"))
+	assert.Equal(t, now.Add(time.Minute), throttle.next["a.example"])
+
+	// A waiter that gives up still holds its slot, so the next one queues
+	// behind it.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, throttle.Wait(ctx, "https://a.example/"), context.Canceled)
+	assert.Equal(t, now.Add(2*time.Minute), throttle.next["a.example"])
+}
+
+func TestHostThrottle_WaitHonorsCancellation(t *testing.T) {
+	throttle := NewHostThrottle(HostThrottleOptions{Delay: time.Hour})
+	require.NoError(t, throttle.Wait(context.Background(), "https://a.example/"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := throttle.Wait(ctx, "https://a.example/")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestHostThrottle_JitterOnly(t *testing.T) {
+	throttle := NewHostThrottle(HostThrottleOptions{Jitter: 30 * time.Millisecond})
+	require.NotNil(t, throttle)
+	assert.Zero(t, throttle.Delay())
+	assert.Equal(t, 30*time.Millisecond, throttle.Jitter())
+
+	var drawn []time.Duration
+	throttle.randN = func(n time.Duration) time.Duration {
+		drawn = append(drawn, n)
+		return 20 * time.Millisecond
+	}
+
+	start := time.Now()
+	require.NoError(t, throttle.Wait(context.Background(), "https://a.example/1"))
+	require.NoError(t, throttle.Wait(context.Background(), "https://a.example/2"))
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 40*time.Millisecond, "every request waits its jitter")
+	assert.Equal(t, []time.Duration{30 * time.Millisecond, 30 * time.Millisecond}, drawn)
+}
+
+func TestHostThrottle_JitterKeepsDelaySpacing(t *testing.T) {
+	throttle := NewHostThrottle(HostThrottleOptions{Delay: time.Minute, Jitter: 10 * time.Second})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	throttle.now = func() time.Time { return now }
+	throttle.randN = func(time.Duration) time.Duration { return 5 * time.Second }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// The first request starts 5s late, and the next slot is a full delay
+	// after that start.
+	assert.ErrorIs(t, throttle.Wait(ctx, "https://a.example/"), context.Canceled)
+	assert.Equal(t, now.Add(time.Minute+5*time.Second), throttle.next["a.example"])
+
+	assert.ErrorIs(t, throttle.Wait(ctx, "https://a.example/"), context.Canceled)
+	assert.Equal(t, now.Add(2*time.Minute+10*time.Second), throttle.next["a.example"])
+}
+
+func TestHostThrottle_JitterHonorsCancellation(t *testing.T) {
+	throttle := NewHostThrottle(HostThrottleOptions{Jitter: time.Hour})
+	throttle.randN = func(n time.Duration) time.Duration { return n - 1 }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := throttle.Wait(ctx, "https://a.example/")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -52,9 +52,14 @@ func (t *StealthTransport) RoundTrip(req *http.Request) (*http.Response, error)
 			extraHeaders[k] = v[0]
 		}
 	}
+	// A configured User-Agent replaces the caller's (e.g. Colly's default).
+	if t.client.hasUserAgent() {
+		delete(extraHeaders, "User-Agent")
+	}
 
 	// Use the stealth client to make the request
-	resp, err := t.client.GetWithHeaders(req.Context(), req.URL.String(), extraHeaders)
+	// The caller's client follows redirects itself.
+	resp, err := t.client.GetWithHeaders(withoutRedirects(req.Context()), req.URL.String(), extraHeaders)
 	if err != nil {
 		// Attempt renderer fallback on HTTP 403 (Cloudflare Managed Challenge)
 		if t.rendererFallback != nil {
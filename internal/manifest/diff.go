@@ -0,0 +1,223 @@
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Diff describes how one manifest differs from another.
+type Diff struct {
+	Added   []Source
+	Removed []Source
+	Changed []SourceChange
+	Options []FieldChange
+}
+
+// SourceChange lists the field differences of a source present in both
+// manifests.
+type SourceChange struct {
+	URL    string
+	Fields []FieldChange
+}
+
+// FieldChange is a single field whose value differs. Old or New is empty when
+// the field is unset on that side.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Empty reports whether the manifests are equivalent.
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Options) == 0
+}
+
+// Compare returns the differences from old to new. Sources are matched by
+// normalized URL (case-insensitive scheme and host, no trailing slash), and
+// list fields are compared without regard to order, so reformatting or
+// reordering a manifest is not reported as drift.
+func Compare(old, new *Config) *Diff {
+	d := &Diff{}
+
+	oldSources := indexSources(old.Sources)
+	newSources := indexSources(new.Sources)
+
+	for _, key := range sortedKeys(newSources) {
+		if _, ok := oldSources[key]; !ok {
+			d.Added = append(d.Added, newSources[key])
+		}
+	}
+	for _, key := range sortedKeys(oldSources) {
+		oldSrc := oldSources[key]
+		newSrc, ok := newSources[key]
+		if !ok {
+			d.Removed = append(d.Removed, oldSrc)
+			continue
+		}
+		if fields := compareFields(sourceFields(oldSrc), sourceFields(newSrc)); len(fields) > 0 {
+			d.Changed = append(d.Changed, SourceChange{URL: newSrc.URL, Fields: fields})
+		}
+	}
+
+	d.Options = compareFields(optionFields(old.Options), optionFields(new.Options))
+	return d
+}
+
+// Format writes a human-readable report of the differences to w.
+func (d *Diff) Format(w io.Writer) {
+	if d.Empty() {
+		fmt.Fprintln(w, "Manifests are equivalent")
+		return
+	}
+	for _, src := range d.Added {
+		fmt.Fprintf(w, "+ %s\n", src.URL)
+	}
+	for _, src := range d.Removed {
+		fmt.Fprintf(w, "- %s\n", src.URL)
+	}
+	for _, change := range d.Changed {
+		fmt.Fprintf(w, "~ %s\n", change.URL)
+		writeFieldChanges(w, "    ", change.Fields)
+	}
+	if len(d.Options) > 0 {
+		fmt.Fprintln(w, "~ options")
+		writeFieldChanges(w, "    ", d.Options)
+	}
+	fmt.Fprintf(w, "\n%d added, %d removed, %d changed", len(d.Added), len(d.Removed), len(d.Changed))
+	if len(d.Options) > 0 {
+		fmt.Fprintf(w, ", %d option(s) changed", len(d.Options))
+	}
+	fmt.Fprintln(w)
+}
+
+func writeFieldChanges(w io.Writer, indent string, fields []FieldChange) {
+	for _, f := range fields {
+		fmt.Fprintf(w, "%s%s: %s -> %s\n", indent, f.Field, displayValue(f.Old), displayValue(f.New))
+	}
+}
+
+func displayValue(v string) string {
+	if v == "" {
+		return "(unset)"
+	}
+	return v
+}
+
+// indexSources keys sources by normalized URL. A URL listed more than once
+// keeps every entry, distinguished by its occurrence number.
+func indexSources(sources []Source) map[string]Source {
+	index := make(map[string]Source, len(sources))
+	seen := make(map[string]int)
+	for _, src := range sources {
+		key := normalizeSourceURL(src.URL)
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key += "#" + strconv.Itoa(n)
+		}
+		index[key] = src
+	}
+	return index
+}
+
+func normalizeSourceURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(raw, "/")
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
+
+func sortedKeys(m map[string]Source) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type namedValue struct {
+	name  string
+	value string
+}
+
+func compareFields(old, new []namedValue) []FieldChange {
+	var changes []FieldChange
+	for i := range old {
+		if old[i].value != new[i].value {
+			changes = append(changes, FieldChange{Field: old[i].name, Old: old[i].value, New: new[i].value})
+		}
+	}
+	return changes
+}
+
+// sourceFields lists the comparable fields of a source, named as in the
+// manifest file.
+func sourceFields(s Source) []namedValue {
+	renderJS := ""
+	if s.RenderJS != nil {
+		renderJS = strconv.FormatBool(*s.RenderJS)
+	}
+	force := ""
+	if s.Force != nil {
+		force = strconv.FormatBool(*s.Force)
+	}
+	return []namedValue{
+		{"enabled", strconv.FormatBool(s.IsEnabled())},
+		{"strategy", s.Strategy},
+		{"content_selector", s.ContentSelector},
+		{"exclude_selector", s.ExcludeSelector},
+		{"exclude", sortedList(s.Exclude)},
+		{"include", sortedList(s.Include)},
+		{"max_depth", intValue(s.MaxDepth)},
+		{"render_js", renderJS},
+		{"limit", intValue(s.Limit)},
+		{"concurrency", intValue(s.Concurrency)},
+		{"force", force},
+		{"tags", tagsValue(s.Tags)},
+		{"output", s.Output},
+	}
+}
+
+func optionFields(o Options) []namedValue {
+	return []namedValue{
+		{"continue_on_error", strconv.FormatBool(o.ContinueOnError)},
+		{"output", o.Output},
+		{"concurrency", intValue(o.Concurrency)},
+		{"cache_ttl", o.CacheTTL.String()},
+		{"concurrency_sources", intValue(o.ConcurrencySources)},
+	}
+}
+
+func sortedList(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return "[" + strings.Join(sorted, ", ") + "]"
+}
+
+func tagsValue(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	return sortedList(pairs)
+}
+
+func intValue(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,101 @@
+package manifest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare_Equivalent(t *testing.T) {
+	loader := NewLoader()
+	old, err := loader.LoadFromBytes([]byte(`
+sources:
+  - url: https://Docs.Example.com/guide/
+    exclude: ["/b", "/a"]
+  - url: https://other.example.com
+`), ".yaml")
+	require.NoError(t, err)
+	new, err := loader.LoadFromBytes([]byte(`{
+  "sources": [
+    {"url": "https://other.example.com"},
+    {"url": "https://docs.example.com/guide", "exclude": ["/a", "/b"]}
+  ],
+  "options": {"concurrency": 5}
+}`), ".json")
+	require.NoError(t, err)
+
+	diff := Compare(old, new)
+	assert.True(t, diff.Empty(), "order, URL case/trailing slash and defaults are normalized: %+v", diff)
+
+	var buf bytes.Buffer
+	diff.Format(&buf)
+	assert.Equal(t, "Manifests are equivalent\n", buf.String())
+}
+
+func TestCompare_Differences(t *testing.T) {
+	renderJS := true
+	old := &Config{
+		Sources: []Source{
+			{URL: "https://a.example.com", Limit: 10},
+			{URL: "https://removed.example.com"},
+		},
+		Options: Options{Concurrency: 5, Output: "./docs"},
+	}
+	new := &Config{
+		Sources: []Source{
+			{URL: "https://a.example.com", Limit: 20, RenderJS: &renderJS},
+			{URL: "https://added.example.com"},
+		},
+		Options: Options{Concurrency: 2, Output: "./docs", ContinueOnError: true},
+	}
+
+	diff := Compare(old, new)
+	require.False(t, diff.Empty())
+
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, "https://added.example.com", diff.Added[0].URL)
+	require.Len(t, diff.Removed, 1)
+	assert.Equal(t, "https://removed.example.com", diff.Removed[0].URL)
+	require.Len(t, diff.Changed, 1)
+	assert.Equal(t, []FieldChange{
+		{Field: "render_js", Old: "", New: "true"},
+		{Field: "limit", Old: "10", New: "20"},
+	}, diff.Changed[0].Fields)
+	assert.Equal(t, []FieldChange{
+		{Field: "continue_on_error", Old: "false", New: "true"},
+		{Field: "concurrency", Old: "5", New: "2"},
+	}, diff.Options)
+
+	var buf bytes.Buffer
+	diff.Format(&buf)
+	out := buf.String()
+	assert.Contains(t, out, "+ https://added.example.com\n")
+	assert.Contains(t, out, "- https://removed.example.com\n")
+	assert.Contains(t, out, "~ https://a.example.com\n    render_js: (unset) -> true\n    limit: 10 -> 20\n")
+	assert.Contains(t, out, "1 added, 1 removed, 1 changed, 2 option(s) changed")
+}
+
+func TestCompare_Tags(t *testing.T) {
+	old := &Config{Sources: []Source{{URL: "https://a.example.com", Tags: map[string]string{"team": "docs", "product": "billing"}}}}
+	same := &Config{Sources: []Source{{URL: "https://a.example.com", Tags: map[string]string{"product": "billing", "team": "docs"}}}}
+	changed := &Config{Sources: []Source{{URL: "https://a.example.com", Tags: map[string]string{"team": "platform"}}}}
+
+	assert.True(t, Compare(old, same).Empty())
+
+	diff := Compare(old, changed)
+	require.Len(t, diff.Changed, 1)
+	assert.Equal(t, []FieldChange{
+		{Field: "tags", Old: "[product=billing, team=docs]", New: "[team=platform]"},
+	}, diff.Changed[0].Fields)
+}
+
+func TestCompare_DuplicateURLs(t *testing.T) {
+	old := &Config{Sources: []Source{{URL: "https://a.example.com"}}}
+	new := &Config{Sources: []Source{{URL: "https://a.example.com"}, {URL: "https://a.example.com", Strategy: "git"}}}
+
+	diff := Compare(old, new)
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, "git", diff.Added[0].Strategy)
+}
@@ -6,17 +6,34 @@
 //
 // Manifests can be written in YAML or JSON format:
 //
+//	version: "1"
 //	sources:
 //	  - url: https://docs.example.com
 //	    strategy: crawler
 //	    content_selector: "article.main"
+//	  - url: https://other.example.com
+//	    content_selector: ["article", "main", ".md-content"]
 //	  - url: https://github.com/org/repo
 //	    strategy: git
 //	    include: ["docs/**/*.md"]
+//	  - url: https://docs.example.com/{version}/
+//	    matrix:
+//	      version: [v1, v2]
 //	options:
 //	  continue_on_error: true
 //	  output: ./knowledge-base
 //
+// The optional version names the manifest format; a missing version is
+// CurrentVersion, and versions not in SupportedVersions are rejected.
+//
+// A source with a matrix is a template expanded at load time into one source
+// per combination of values (see Source.Expand).
+//
+// URLs, selectors, include/exclude globs and outputs may reference
+// environment variables as ${VAR}, $VAR or ${VAR:-default}; they are
+// expanded before validation (see ExpandEnv) unless the loader is created
+// with LoaderOptions.NoExpandEnv set.
+//
 // # Usage
 //
 // Load a manifest file:
@@ -31,6 +48,24 @@
 //	    // Process each source
 //	}
 //
+// Manifests from stdin, memory or files without a .yaml, .yml or .json
+// extension are loaded with an explicit or sniffed format:
+//
+//	loader := manifest.NewLoaderWithOptions(manifest.LoaderOptions{Format: manifest.FormatAuto})
+//	cfg, err := loader.LoadReader(os.Stdin, manifest.FormatAuto)
+//
+// Several manifests are merged into one batch with LoadAll, or Merge for
+// manifests already loaded:
+//
+//	cfg, err := loader.LoadAll("base.yaml", "team.yaml")
+//
+// Manifests built in code are checked with Validate, which runs the loader's
+// checks and joins every problem found:
+//
+//	if err := manifest.Validate(cfg); err != nil {
+//	    log.Fatal(err)
+//	}
+//
 // # Error Handling
 //
 // The package defines sentinel errors for common failure cases:
@@ -39,4 +74,12 @@
 //   - ErrInvalidFormat: file is not valid YAML/JSON
 //   - ErrFileNotFound: manifest file does not exist
 //   - ErrUnsupportedExt: unsupported file extension
+//   - ErrDuplicateSource: a source URL is listed twice, after matrix and glob expansion (LoaderOptions.AllowDuplicates accepts it)
+//   - ErrInvalidStrategy: a source or the defaults name an unknown strategy
+//   - ErrInvalidMatrix: a source template cannot be expanded
+//   - ErrGlobNoMatch: a file:// glob source matches no directory
+//   - ErrInvalidOutput: a source output directory leaves the output directory
+//   - ErrUnresolvedVariable: an unset environment variable has no default
+//   - ErrOutputCollision: two sources set the same output directory
+//   - ErrUnsupportedVersion: the manifest version is not supported
 package manifest
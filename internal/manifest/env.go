@@ -0,0 +1,126 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envPattern matches $$ (a literal $), ${VAR}, ${VAR:-default} and $VAR.
+var envPattern = regexp.MustCompile(`\$(?:\$|\{([A-Za-z_][A-Za-z0-9_]*)(:-[^}]*)?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// expandEnv replaces environment variable references in the string fields
+// of the manifest that hold locations and patterns: the url, selectors,
+// include/exclude globs and output of every source and of the defaults, and
+// the output option. Numeric and boolean fields are never touched. Errors
+// name the source by index.
+func (c *Config) expandEnv() error {
+	for i := range c.Sources {
+		src := &c.Sources[i]
+		err := expandEnvFields(
+			envField{"url", &src.URL},
+			envField{"content_selector", &src.ContentSelector},
+			envField{"exclude_selector", &src.ExcludeSelector},
+			envField{"output", &src.Output},
+		)
+		if err == nil {
+			err = expandEnvLists(envList{"include", src.Include}, envList{"exclude", src.Exclude})
+		}
+		if err != nil {
+			return fmt.Errorf("source %d: %w", i, err)
+		}
+	}
+
+	d := &c.Defaults
+	err := expandEnvFields(
+		envField{"content_selector", &d.ContentSelector},
+		envField{"exclude_selector", &d.ExcludeSelector},
+	)
+	if err == nil {
+		err = expandEnvLists(envList{"include", d.Include}, envList{"exclude", d.Exclude})
+	}
+	if err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+
+	if err := expandEnvFields(envField{"output", &c.Options.Output}); err != nil {
+		return fmt.Errorf("options: %w", err)
+	}
+	return nil
+}
+
+// envField is a string field expanded by expandEnvFields.
+type envField struct {
+	name  string
+	value *string
+}
+
+// envList is a list whose entries expandEnvLists expands in place.
+type envList struct {
+	name   string
+	values []string
+}
+
+func expandEnvFields(fields ...envField) error {
+	for _, field := range fields {
+		expanded, err := ExpandEnv(*field.value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.name, err)
+		}
+		*field.value = expanded
+	}
+	return nil
+}
+
+func expandEnvLists(lists ...envList) error {
+	for _, list := range lists {
+		for i, value := range list.values {
+			expanded, err := ExpandEnv(value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", list.name, err)
+			}
+			list.values[i] = expanded
+		}
+	}
+	return nil
+}
+
+// ExpandEnv replaces ${VAR} and $VAR in s with the value of the environment
+// variable VAR, and ${VAR:-default} with default when VAR is unset or
+// empty. $$ stands for a literal $, and a $ not followed by a name is kept.
+// A variable that is unset and has no default is an ErrUnresolvedVariable.
+func ExpandEnv(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range envPattern.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(s[last:m[0]])
+		last = m[1]
+
+		var name string
+		switch {
+		case m[2] >= 0:
+			name = s[m[2]:m[3]]
+		case m[6] >= 0:
+			name = s[m[6]:m[7]]
+		default:
+			b.WriteByte('$')
+			continue
+		}
+
+		value, ok := os.LookupEnv(name)
+		if m[4] >= 0 && value == "" {
+			value, ok = s[m[4]+len(":-"):m[5]], true
+		}
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnresolvedVariable, name)
+		}
+		b.WriteString(value)
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
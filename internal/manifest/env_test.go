@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("DOCS_HOST", "docs.example.com")
+	t.Setenv("EMPTY_VAR", "")
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "no references", input: "https://example.com/a", want: "https://example.com/a"},
+		{name: "braced", input: "https://${DOCS_HOST}/guide", want: "https://docs.example.com/guide"},
+		{name: "bare", input: "https://$DOCS_HOST/guide", want: "https://docs.example.com/guide"},
+		{name: "default unused", input: "${DOCS_HOST:-fallback}", want: "docs.example.com"},
+		{name: "default for unset", input: "${REPODOCS_UNSET_VAR:-https://fallback.example}", want: "https://fallback.example"},
+		{name: "default for empty", input: "${EMPTY_VAR:-article}", want: "article"},
+		{name: "empty default", input: "a${REPODOCS_UNSET_VAR:-}b", want: "ab"},
+		{name: "set but empty", input: "a${EMPTY_VAR}b", want: "ab"},
+		{name: "escaped dollar", input: "price$$5", want: "price$5"},
+		{name: "lone dollar kept", input: "a $ b $1 ${", want: "a $ b $1 ${"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandEnv(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ExpandEnv("https://${REPODOCS_UNSET_VAR}/")
+	assert.ErrorIs(t, err, ErrUnresolvedVariable)
+	assert.ErrorContains(t, err, "REPODOCS_UNSET_VAR")
+}
+
+func TestLoader_ExpandsEnv(t *testing.T) {
+	t.Setenv("DOCS_BASE_URL", "https://docs.example.com")
+	t.Setenv("DOCS_SELECTOR", "main")
+
+	data := []byte(`
+sources:
+  - url: ${DOCS_BASE_URL}/guide
+    content_selector: $DOCS_SELECTOR
+    include: ["${DOCS_BASE_URL}/guide/**"]
+    output: ${SECTION:-guide}
+    max_depth: 2
+    render_js: true
+defaults:
+  exclude: ["${DOCS_BASE_URL}/blog/**"]
+options:
+  output: ./${OUT_DIR:-kb}
+`)
+	cfg, err := NewLoader().LoadFromBytes(data, ".yaml")
+	require.NoError(t, err)
+
+	src := cfg.Sources[0]
+	assert.Equal(t, "https://docs.example.com/guide", src.URL)
+	assert.Equal(t, "main", src.ContentSelector)
+	assert.Equal(t, []string{"https://docs.example.com/guide/**"}, src.Include)
+	assert.Equal(t, []string{"https://docs.example.com/blog/**"}, src.Exclude)
+	assert.Equal(t, "guide", src.Output)
+	assert.Equal(t, 2, src.MaxDepth)
+	assert.Equal(t, "./kb", cfg.Options.Output)
+}
+
+func TestLoader_ExpandEnv_Unresolved(t *testing.T) {
+	data := []byte("sources:\n  - url: https://a.example\n  - url: https://${REPODOCS_UNSET_VAR}/docs\n")
+
+	_, err := NewLoader().LoadFromBytes(data, ".yaml")
+	assert.ErrorIs(t, err, ErrUnresolvedVariable)
+	assert.EqualError(t, err, "source 1: url: unresolved environment variable: REPODOCS_UNSET_VAR")
+}
+
+func TestLoader_ExpandEnv_Disabled(t *testing.T) {
+	data := []byte("sources:\n  - url: https://example.com/${REPODOCS_UNSET_VAR}\n")
+
+	cfg, err := NewLoaderWithOptions(LoaderOptions{NoExpandEnv: true}).LoadFromBytes(data, ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/${REPODOCS_UNSET_VAR}", cfg.Sources[0].URL)
+
+	_, err = NewLoaderWithOptions(LoaderOptions{Format: FormatAuto}).LoadFromBytes(data, ".yaml")
+	assert.ErrorIs(t, err, ErrUnresolvedVariable, "loaders built with options expand by default")
+}
+
+func TestLoader_ExpandEnv_BeforeValidation(t *testing.T) {
+	t.Setenv("EMPTY_URL", "")
+
+	_, err := NewLoader().LoadFromBytes([]byte("sources:\n  - url: ${EMPTY_URL}\n"), ".yaml")
+	assert.ErrorIs(t, err, ErrEmptyURL)
+}
@@ -16,6 +16,46 @@ var (
 	// ErrFileNotFound indicates the manifest file does not exist
 	ErrFileNotFound = errors.New("manifest file not found")
 
+	// ErrEmptyTagKey indicates a source tag without a key
+	ErrEmptyTagKey = errors.New("tag keys cannot be empty")
+
+	// ErrInvalidListMerge indicates an unknown defaults.list_merge mode
+	ErrInvalidListMerge = errors.New("list_merge must be \"replace\" or \"append\"")
+
+	// ErrInvalidConcurrency indicates a negative options.concurrency_sources
+	// or source concurrency
+	ErrInvalidConcurrency = errors.New("concurrency must not be negative")
+
+	// ErrInvalidOutput indicates a source output directory outside the
+	// output directory
+	ErrInvalidOutput = errors.New("output must be a relative directory inside the output directory")
+
+	// ErrOutputCollision indicates two sources writing to the same output
+	// directory
+	ErrOutputCollision = errors.New("sources must not share an output directory (set continue_on_error to allow it)")
+
+	// ErrInvalidStrategy indicates a source or defaults strategy that is
+	// not one of Strategies
+	ErrInvalidStrategy = errors.New("unknown strategy (use llms, openapi, pkggo, docsrs, sitemap, wiki, github_pages, git or crawler)")
+
+	// ErrInvalidMatrix indicates a source matrix that cannot be expanded
+	ErrInvalidMatrix = errors.New("invalid source matrix")
+
+	// ErrGlobNoMatch indicates a file:// glob source matching no directory
+	ErrGlobNoMatch = errors.New("file glob matches no directories")
+
+	// ErrDuplicateSource indicates a source URL listed twice, in one
+	// manifest or across merged ones
+	ErrDuplicateSource = errors.New("duplicate source URL")
+
+	// ErrUnresolvedVariable indicates a ${VAR} reference to an unset
+	// environment variable without a ${VAR:-default}
+	ErrUnresolvedVariable = errors.New("unresolved environment variable")
+
+	// ErrUnsupportedVersion indicates a manifest format version this release
+	// cannot read
+	ErrUnsupportedVersion = errors.New("unsupported manifest version")
+
 	// ErrUnsupportedExt indicates an unsupported file extension
 	ErrUnsupportedExt = errors.New("unsupported file extension (use .yaml, .yml, or .json)")
 )
@@ -0,0 +1,186 @@
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MaxMatrixSources caps the number of sources one matrix expands to, so a
+// typo in a large matrix does not silently queue thousands of extractions.
+const MaxMatrixSources = 256
+
+// placeholderPattern matches the {key} placeholders of a source template.
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Expand returns the sources the template s stands for: one per
+// combination of its matrix values, in matrix key order with values in the
+// order listed, and with every {key} placeholder of the url, output,
+// include, exclude, content selectors and tag values replaced. Unless the
+// template sets output, each source is written to a subdirectory named
+// after its values (e.g. "v2" or "v2-en"). A source without a matrix is
+// returned as is.
+func (s Source) Expand() ([]Source, error) {
+	if len(s.Matrix) == 0 {
+		return []Source{s}, nil
+	}
+	if err := s.validateMatrix(); err != nil {
+		return nil, err
+	}
+
+	keys := slices.Sorted(maps.Keys(s.Matrix))
+	combos := []map[string]string{{}}
+	for _, key := range keys {
+		next := make([]map[string]string, 0, len(combos)*len(s.Matrix[key]))
+		for _, combo := range combos {
+			for _, value := range s.Matrix[key] {
+				c := maps.Clone(combo)
+				c[key] = value
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+
+	sources := make([]Source, 0, len(combos))
+	for _, combo := range combos {
+		src := s.substitute(combo)
+		src.Matrix = nil
+		if s.Output == "" {
+			values := make([]string, len(keys))
+			for i, key := range keys {
+				values[i] = combo[key]
+			}
+			src.Output = strings.Join(values, "-")
+		}
+		if err := validateOutput(src.Output); err != nil {
+			return nil, fmt.Errorf("%s: %w", src.URL, err)
+		}
+		sources = append(sources, src)
+	}
+	return sources, nil
+}
+
+// validateMatrix checks that a matrix has values for every key, that its
+// placeholders and keys match, so every expanded source has a distinct url,
+// and that it stays within MaxMatrixSources.
+func (s Source) validateMatrix() error {
+	if len(s.Matrix) == 0 {
+		return nil
+	}
+
+	count := 1
+	for key, values := range s.Matrix {
+		if !placeholderPattern.MatchString("{" + key + "}") {
+			return fmt.Errorf("%w: key %q is not a valid placeholder name", ErrInvalidMatrix, key)
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("%w: key %q has no values", ErrInvalidMatrix, key)
+		}
+		seen := make(map[string]bool, len(values))
+		for _, value := range values {
+			if value == "" {
+				return fmt.Errorf("%w: key %q has an empty value", ErrInvalidMatrix, key)
+			}
+			if seen[value] {
+				return fmt.Errorf("%w: key %q lists %q twice", ErrInvalidMatrix, key, value)
+			}
+			seen[value] = true
+		}
+		count *= len(values)
+		if count > MaxMatrixSources {
+			return fmt.Errorf("%w: expands to more than %d sources", ErrInvalidMatrix, MaxMatrixSources)
+		}
+	}
+
+	inURL := placeholders(s.URL)
+	for key := range s.Matrix {
+		if !inURL[key] {
+			return fmt.Errorf("%w: url %q does not use {%s}", ErrInvalidMatrix, s.URL, key)
+		}
+	}
+	for _, field := range s.templateFields() {
+		for key := range placeholders(field) {
+			if _, ok := s.Matrix[key]; !ok {
+				return fmt.Errorf("%w: placeholder {%s} has no matrix values", ErrInvalidMatrix, key)
+			}
+		}
+	}
+	return nil
+}
+
+// templateFields returns the fields of s whose placeholders are replaced.
+func (s Source) templateFields() []string {
+	fields := []string{s.URL, s.Output, s.ContentSelector, s.ExcludeSelector}
+	fields = append(fields, s.Include...)
+	fields = append(fields, s.Exclude...)
+	for _, value := range s.Tags {
+		fields = append(fields, value)
+	}
+	return fields
+}
+
+// substitute returns s with the placeholders of values replaced.
+func (s Source) substitute(values map[string]string) Source {
+	replace := func(text string) string {
+		return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+			return values[match[1:len(match)-1]]
+		})
+	}
+	replaceAll := func(list []string) []string {
+		if list == nil {
+			return nil
+		}
+		out := make([]string, len(list))
+		for i, text := range list {
+			out[i] = replace(text)
+		}
+		return out
+	}
+
+	s.URL = replace(s.URL)
+	s.Output = replace(s.Output)
+	s.ContentSelector = replace(s.ContentSelector)
+	s.ExcludeSelector = replace(s.ExcludeSelector)
+	s.Include = replaceAll(s.Include)
+	s.Exclude = replaceAll(s.Exclude)
+	if s.Tags != nil {
+		tags := make(map[string]string, len(s.Tags))
+		for key, value := range s.Tags {
+			tags[key] = replace(value)
+		}
+		s.Tags = tags
+	}
+	return s
+}
+
+// placeholders returns the set of placeholder keys used in text.
+func placeholders(text string) map[string]bool {
+	keys := make(map[string]bool)
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		keys[match[1]] = true
+	}
+	return keys
+}
+
+// expandSources expands every source template of sources (see Expand),
+// then every file:// glob (see ExpandGlob).
+func expandSources(sources []Source) ([]Source, error) {
+	expanded := make([]Source, 0, len(sources))
+	for i, src := range sources {
+		srcs, err := src.Expand()
+		if err != nil {
+			return nil, fmt.Errorf("source %d: %w", i, err)
+		}
+		for _, src := range srcs {
+			globbed, err := src.ExpandGlob()
+			if err != nil {
+				return nil, fmt.Errorf("source %d: %w", i, err)
+			}
+			expanded = append(expanded, globbed...)
+		}
+	}
+	return expanded, nil
+}
@@ -0,0 +1,92 @@
+package manifest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource_Expand(t *testing.T) {
+	src := Source{
+		URL:     "https://docs.example.com/{version}/{lang}/",
+		Include: []string{"/{version}/**"},
+		Tags:    map[string]string{"version": "{version}", "team": "docs"},
+		Matrix:  map[string][]string{"version": {"v1", "v2"}, "lang": {"en", "de"}},
+	}
+
+	sources, err := src.Expand()
+	require.NoError(t, err)
+	require.Len(t, sources, 4)
+
+	// Keys expand in sorted order: lang, then version.
+	assert.Equal(t, "https://docs.example.com/v1/en/", sources[0].URL)
+	assert.Equal(t, "https://docs.example.com/v2/en/", sources[1].URL)
+	assert.Equal(t, "https://docs.example.com/v1/de/", sources[2].URL)
+	assert.Equal(t, "en-v1", sources[0].Output)
+	assert.Equal(t, []string{"/v2/**"}, sources[1].Include)
+	assert.Equal(t, map[string]string{"version": "v2", "team": "docs"}, sources[1].Tags)
+	assert.Nil(t, sources[0].Matrix)
+	assert.Equal(t, "{version}", src.Tags["version"], "the template is not modified")
+
+	src = Source{URL: "https://a.example/{v}", Output: "product/{v}", Matrix: map[string][]string{"v": {"1.0"}}}
+	sources, err = src.Expand()
+	require.NoError(t, err)
+	assert.Equal(t, "product/1.0", sources[0].Output)
+
+	plain := Source{URL: "https://a.example"}
+	sources, err = plain.Expand()
+	require.NoError(t, err)
+	assert.Equal(t, []Source{plain}, sources)
+}
+
+func TestSource_Expand_Invalid(t *testing.T) {
+	many := make([]string, 17)
+	for i := range many {
+		many[i] = fmt.Sprint(i)
+	}
+
+	for name, src := range map[string]Source{
+		"no values":           {URL: "https://a.example/{v}", Matrix: map[string][]string{"v": {}}},
+		"empty value":         {URL: "https://a.example/{v}", Matrix: map[string][]string{"v": {""}}},
+		"repeated value":      {URL: "https://a.example/{v}", Matrix: map[string][]string{"v": {"1", "1"}}},
+		"key unused in url":   {URL: "https://a.example/{v}", Matrix: map[string][]string{"v": {"1"}, "lang": {"en"}}},
+		"unknown placeholder": {URL: "https://a.example/{v}", Output: "{lang}", Matrix: map[string][]string{"v": {"1"}}},
+		"invalid key":         {URL: "https://a.example/{v}", Matrix: map[string][]string{"v": {"1"}, "my-key": {"x"}}},
+		"too many":            {URL: "https://a.example/{a}/{b}", Matrix: map[string][]string{"a": many, "b": many}},
+	} {
+		_, err := src.Expand()
+		assert.ErrorIs(t, err, ErrInvalidMatrix, name)
+	}
+
+	_, err := Source{URL: "https://a.example/{v}", Matrix: map[string][]string{"v": {".."}}}.Expand()
+	assert.ErrorIs(t, err, ErrInvalidOutput)
+}
+
+func TestLoader_ExpandsSourceTemplates(t *testing.T) {
+	data := []byte(`
+defaults:
+  strategy: crawler
+sources:
+  - url: https://docs.example.com/{version}/
+    matrix:
+      version: [v1, v2, v3]
+  - url: https://other.example.com
+`)
+	cfg, err := NewLoader().LoadFromBytes(data, ".yaml")
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 4)
+	for i, version := range []string{"v1", "v2", "v3"} {
+		assert.Equal(t, "https://docs.example.com/"+version+"/", cfg.Sources[i].URL)
+		assert.Equal(t, version, cfg.Sources[i].Output)
+		assert.Equal(t, "crawler", cfg.Sources[i].Strategy)
+	}
+	assert.Empty(t, cfg.Sources[3].Output)
+
+	_, err = NewLoader().LoadFromBytes([]byte("sources:\n  - url: https://a.example/{v}\n    matrix:\n      v: []\n"), ".yaml")
+	assert.ErrorIs(t, err, ErrInvalidMatrix)
+
+	_, err = NewLoader().LoadFromBytes([]byte("sources:\n  - url: https://a.example\n    output: ../outside\n"), ".yaml")
+	assert.ErrorIs(t, err, ErrInvalidOutput)
+}
@@ -0,0 +1,63 @@
+package manifest
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const fileScheme = "file://"
+
+// localGlob returns the path pattern of a file:// url holding glob
+// metacharacters (*, ? or [...]).
+func localGlob(url string) (string, bool) {
+	path, ok := strings.CutPrefix(url, fileScheme)
+	if !ok || !strings.ContainsAny(path, "*?[") {
+		return "", false
+	}
+	return filepath.FromSlash(path), true
+}
+
+// ExpandGlob returns the sources a file:// glob source stands for: one per
+// matching directory, in lexical order, each a copy of s with the url of
+// the directory. Unless s sets them, the sources use the git strategy and
+// are written to a subdirectory named after their directory. A source
+// without a file:// glob is returned as is; a glob matching no directory is
+// an ErrGlobNoMatch.
+func (s Source) ExpandGlob() ([]Source, error) {
+	pattern, ok := localGlob(s.URL)
+	if !ok {
+		return []Source{s}, nil
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("url %q: %w", s.URL, err)
+	}
+
+	var sources []Source
+	for _, match := range matches {
+		if info, err := os.Stat(match); err != nil || !info.IsDir() {
+			continue
+		}
+		src := s
+		src.URL = fileScheme + filepath.ToSlash(match)
+		src.Include = slices.Clone(s.Include)
+		src.Exclude = slices.Clone(s.Exclude)
+		src.Tags = maps.Clone(s.Tags)
+		if src.Strategy == "" {
+			src.Strategy = "git"
+		}
+		if src.Output == "" {
+			src.Output = filepath.Base(match)
+		}
+		sources = append(sources, src)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrGlobNoMatch, s.URL)
+	}
+	return sources, nil
+}
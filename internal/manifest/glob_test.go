@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// globRepos creates the directories names under a temporary directory, plus
+// a regular file, and returns the directory.
+func globRepos(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, name), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes-file"), nil, 0o644))
+	return dir
+}
+
+func TestSource_ExpandGlob(t *testing.T) {
+	dir := globRepos(t, "api-docs", "cli-docs", "web")
+
+	src := Source{
+		URL:             "file://" + filepath.ToSlash(dir) + "/*-docs",
+		ContentSelector: "main",
+		Include:         []string{"docs/**"},
+		Tags:            map[string]string{"team": "platform"},
+	}
+	sources, err := src.ExpandGlob()
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+
+	for i, name := range []string{"api-docs", "cli-docs"} {
+		assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(dir, name)), sources[i].URL)
+		assert.Equal(t, "git", sources[i].Strategy)
+		assert.Equal(t, name, sources[i].Output)
+		assert.Equal(t, "main", sources[i].ContentSelector)
+		assert.Equal(t, []string{"docs/**"}, sources[i].Include)
+		assert.Equal(t, map[string]string{"team": "platform"}, sources[i].Tags)
+	}
+
+	sources[0].Include[0] = "changed"
+	sources[0].Tags["team"] = "changed"
+	assert.Equal(t, "docs/**", sources[1].Include[0], "sources do not share lists")
+	assert.Equal(t, "platform", src.Tags["team"], "sources do not share tags")
+
+	sources, err = Source{URL: "file://" + filepath.ToSlash(dir) + "/*", Output: "all", Strategy: "git"}.ExpandGlob()
+	require.NoError(t, err)
+	assert.Len(t, sources, 3, "regular files are skipped")
+	assert.Equal(t, "all", sources[0].Output, "an explicit output is kept")
+}
+
+func TestSource_ExpandGlob_NoMatch(t *testing.T) {
+	url := "file://" + filepath.ToSlash(globRepos(t)) + "/*-docs"
+
+	_, err := Source{URL: url}.ExpandGlob()
+	assert.ErrorIs(t, err, ErrGlobNoMatch)
+	assert.ErrorContains(t, err, url)
+
+	_, err = Source{URL: "file:///tmp/[docs"}.ExpandGlob()
+	assert.Error(t, err, "a malformed pattern is reported")
+}
+
+func TestSource_ExpandGlob_NotGlob(t *testing.T) {
+	for _, url := range []string{"file:///srv/repos/docs", "https://example.com/docs?page=*"} {
+		src := Source{URL: url}
+		sources, err := src.ExpandGlob()
+		require.NoError(t, err)
+		assert.Equal(t, []Source{src}, sources)
+	}
+}
+
+func TestLoader_ExpandsFileGlobs(t *testing.T) {
+	dir := globRepos(t, "alpha", "beta")
+	url := "file://" + filepath.ToSlash(dir) + "/*"
+
+	cfg, err := NewLoader().LoadFromBytes([]byte(fmt.Sprintf(`
+sources:
+  - url: https://example.com/docs
+  - url: %s
+    max_depth: 2
+`, url)), ".yaml")
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 3)
+	assert.Equal(t, "alpha", cfg.Sources[1].Output)
+	assert.Equal(t, "beta", cfg.Sources[2].Output)
+	assert.Equal(t, 2, cfg.Sources[2].MaxDepth)
+
+	_, err = NewLoader().LoadFromBytes([]byte(fmt.Sprintf("sources:\n  - url: %s\n    output: repos\n", url)), ".yaml")
+	assert.ErrorIs(t, err, ErrOutputCollision, "an explicit output is shared by every match")
+
+	_, err = NewLoader().LoadFromBytes([]byte("sources:\n  - url: https://example.com\n  - url: file:///nonexistent-repodocs/*\n"), ".yaml")
+	assert.ErrorIs(t, err, ErrGlobNoMatch)
+	assert.ErrorContains(t, err, "source 1: ")
+}
@@ -1,8 +1,10 @@
 package manifest
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,16 +12,111 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// Format selects how a manifest is parsed.
+type Format string
+
+const (
+	// FormatAuto parses by file extension and, when the extension is unknown
+	// or there is none, by content: JSON when it starts with '{' or '[',
+	// YAML otherwise.
+	FormatAuto Format = "auto"
+	// FormatYAML parses manifests as YAML regardless of the extension.
+	FormatYAML Format = "yaml"
+	// FormatJSON parses manifests as JSON regardless of the extension.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses a manifest format. Empty means auto.
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return FormatAuto, nil
+	case FormatAuto, FormatYAML, FormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid manifest format %q (use auto, yaml or json)", value)
+	}
+}
+
 // Loader loads and validates manifest files
-type Loader struct{}
+type Loader struct {
+	format          Format
+	expandEnv       bool
+	defaultOutput   string
+	allowDuplicates bool
+}
+
+// LoaderOptions configures a Loader.
+type LoaderOptions struct {
+	// Format forces YAML or JSON parsing, or with FormatAuto sniffs the
+	// content of files without a known extension. Empty accepts only .yaml,
+	// .yml and .json files.
+	Format Format
+	// NoExpandEnv reads manifests with literal $ characters as they are.
+	// By default ${VAR}, ${VAR:-default} and $VAR references to environment
+	// variables in source URLs, selectors, globs and outputs are resolved
+	// before validation (see ExpandEnv).
+	NoExpandEnv bool
+	// DefaultOutput is the output directory of manifests that set no
+	// options.output, such as the -o flag; empty uses DefaultOptions.
+	DefaultOutput string
+	// AllowDuplicates accepts sources that share a URL, such as the same
+	// site extracted with two content selectors (--allow-duplicate-sources).
+	// By default a repeated URL is an ErrDuplicateSource.
+	AllowDuplicates bool
+}
 
-// NewLoader creates a new manifest loader
+// NewLoader creates a new manifest loader that expands environment
+// variables.
 func NewLoader() *Loader {
-	return &Loader{}
+	return &Loader{expandEnv: true}
+}
+
+// NewLoaderWithOptions creates a manifest loader configured by opts.
+func NewLoaderWithOptions(opts LoaderOptions) *Loader {
+	return &Loader{
+		format:          opts.Format,
+		expandEnv:       !opts.NoExpandEnv,
+		defaultOutput:   opts.DefaultOutput,
+		allowDuplicates: opts.AllowDuplicates,
+	}
 }
 
 // Load reads and parses a manifest file from the given path
 func (l *Loader) Load(path string) (*Config, error) {
+	cfg, err := l.loadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.finish(cfg)
+}
+
+// LoadAll reads the manifest files at paths and merges them into one batch
+// (see Merge). Errors name the file they come from.
+func (l *Loader) LoadAll(paths ...string) (*Config, error) {
+	if len(paths) == 1 {
+		return l.Load(paths[0])
+	}
+
+	cfgs := make([]*Config, 0, len(paths))
+	for _, path := range paths {
+		cfg, err := l.loadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		cfgs = append(cfgs, cfg)
+	}
+
+	merged, err := merge(l.allowDuplicates, cfgs...)
+	if err != nil {
+		return nil, err
+	}
+	return l.finish(merged)
+}
+
+// loadFile reads and decodes the manifest file at path, without option
+// defaults.
+func (l *Loader) loadFile(path string) (*Config, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, path)
 	}
@@ -29,41 +126,132 @@ func (l *Loader) Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to read manifest file: %w", err)
 	}
 
-	return l.LoadFromBytes(data, filepath.Ext(path))
+	format, err := l.formatFor(filepath.Ext(path), data)
+	if err != nil {
+		return nil, err
+	}
+	return l.decode(data, format)
+}
+
+// LoadReader reads and parses a manifest from r, such as stdin. An empty
+// format or FormatAuto detects YAML or JSON from the content.
+func (l *Loader) LoadReader(r io.Reader, format Format) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if format == "" || format == FormatAuto {
+		format = sniffFormat(data)
+	}
+	return l.parse(data, format)
 }
 
 // LoadFromBytes parses manifest configuration from raw bytes
 func (l *Loader) LoadFromBytes(data []byte, ext string) (*Config, error) {
-	ext = strings.ToLower(ext)
+	format, err := l.formatFor(ext, data)
+	if err != nil {
+		return nil, err
+	}
+	return l.parse(data, format)
+}
 
-	var cfg Config
+// formatFor returns the format of a manifest with extension ext: the
+// loader's forced format, else the one the extension names, else with
+// FormatAuto the one the content looks like.
+func (l *Loader) formatFor(ext string, data []byte) (Format, error) {
+	if l.format == FormatYAML || l.format == FormatJSON {
+		return l.format, nil
+	}
+
+	ext = strings.ToLower(ext)
 	switch ext {
 	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	if l.format == FormatAuto {
+		return sniffFormat(data), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedExt, ext)
+}
+
+// sniffFormat guesses the format of a manifest from its first non-blank
+// character: JSON documents start with '{' or '['.
+func sniffFormat(data []byte) Format {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\uFEFF")), " \t\r\n")
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// parse decodes a manifest in format, validates it and applies defaults.
+func (l *Loader) parse(data []byte, format Format) (*Config, error) {
+	cfg, err := l.decode(data, format)
+	if err != nil {
+		return nil, err
+	}
+	return l.finish(cfg)
+}
+
+// finish applies the version and option defaults to a decoded, or merged,
+// manifest and checks that its sources do not share an output directory.
+func (l *Loader) finish(cfg *Config) (*Config, error) {
+	if cfg.Version == "" {
+		cfg.Version = CurrentVersion
+	}
+	l.applyOptionDefaults(cfg)
+	if err := cfg.CheckOutputCollisions(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decode decodes a manifest in format, expands its environment variables
+// when enabled, validates it, expands its source templates, checks the
+// expanded sources for duplicates and applies its source defaults to them.
+func (l *Loader) decode(data []byte, format Format) (*Config, error) {
+	var cfg Config
+	switch format {
+	case FormatYAML:
 		if err := yaml.Unmarshal(data, &cfg); err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
 		}
-	case ".json":
+	case FormatJSON:
 		if err := json.Unmarshal(data, &cfg); err != nil {
 			return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
 		}
 	default:
-		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExt, ext)
+		return nil, fmt.Errorf("invalid manifest format %q (use auto, yaml or json)", format)
 	}
 
-	l.applyDefaults(&cfg)
+	if l.expandEnv {
+		if err := cfg.expandEnv(); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := cfg.Validate(); err != nil {
+	sources, err := validateExpanded(&cfg, l.allowDuplicates)
+	if err != nil {
 		return nil, err
 	}
+	for i, src := range sources {
+		sources[i] = cfg.Defaults.Apply(src)
+	}
+	cfg.Sources = sources
 
 	return &cfg, nil
 }
 
-func (l *Loader) applyDefaults(cfg *Config) {
+func (l *Loader) applyOptionDefaults(cfg *Config) {
 	defaults := DefaultOptions()
 
 	if cfg.Options.Output == "" {
 		cfg.Options.Output = defaults.Output
+		if l.defaultOutput != "" {
+			cfg.Options.Output = l.defaultOutput
+		}
 	}
 	if cfg.Options.Concurrency == 0 {
 		cfg.Options.Concurrency = defaults.Concurrency
@@ -3,6 +3,7 @@ package manifest
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -201,6 +202,26 @@ options:
 	assert.Equal(t, "./custom", cfg.Options.Output)
 }
 
+func TestLoadFromBytes_Tags(t *testing.T) {
+	loader := NewLoader()
+
+	yamlContent := `
+sources:
+  - url: https://example.com
+    tags:
+      team: platform
+      version: 2
+`
+
+	cfg, err := loader.LoadFromBytes([]byte(yamlContent), ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "platform", "version": "2"}, cfg.Sources[0].Tags)
+
+	cfg, err = loader.LoadFromBytes([]byte(`{"sources": [{"url": "https://example.com", "tags": {"product": "billing"}}]}`), ".json")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"product": "billing"}, cfg.Sources[0].Tags)
+}
+
 func TestLoadFromBytes_JSON(t *testing.T) {
 	loader := NewLoader()
 
@@ -401,6 +422,7 @@ func TestErrors(t *testing.T) {
 	}{
 		{"ErrNoSources", ErrNoSources},
 		{"ErrEmptyURL", ErrEmptyURL},
+		{"ErrEmptyTagKey", ErrEmptyTagKey},
 		{"ErrInvalidFormat", ErrInvalidFormat},
 		{"ErrFileNotFound", ErrFileNotFound},
 		{"ErrUnsupportedExt", ErrUnsupportedExt},
@@ -413,3 +435,361 @@ func TestErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestLoader_LoadFromBytes_EnabledToggle(t *testing.T) {
+	loader := NewLoader()
+
+	cfg, err := loader.LoadFromBytes([]byte(`
+sources:
+  - url: https://example.com
+  - url: https://disabled.example.com
+    enabled: false
+`), ".yaml")
+
+	require.NoError(t, err)
+	assert.True(t, cfg.Sources[0].IsEnabled())
+	assert.False(t, cfg.Sources[1].IsEnabled())
+
+	cfg, err = loader.LoadFromBytes([]byte(`{"sources": [{"url": "https://a.example"}, {"url": "https://b.example", "disabled": true}]}`), ".json")
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 2, "disabled sources are kept")
+	assert.True(t, cfg.Sources[1].Disabled)
+	assert.Len(t, cfg.ActiveSources(), 1)
+}
+
+func TestLoader_LoadFromBytes_Defaults(t *testing.T) {
+	loader := NewLoader()
+
+	cfg, err := loader.LoadFromBytes([]byte(`
+defaults:
+  content_selector: article.main
+  exclude: ["/blog"]
+  list_merge: append
+sources:
+  - url: https://a.example.com
+  - url: https://b.example.com
+    content_selector: main
+    exclude: ["/changelog"]
+`), ".yaml")
+
+	require.NoError(t, err)
+	assert.Equal(t, "article.main", cfg.Sources[0].ContentSelector)
+	assert.Equal(t, []string{"/blog"}, cfg.Sources[0].Exclude)
+	assert.Equal(t, "main", cfg.Sources[1].ContentSelector)
+	assert.Equal(t, []string{"/blog", "/changelog"}, cfg.Sources[1].Exclude)
+}
+
+func TestLoader_LoadFromBytes_ContentSelectorList(t *testing.T) {
+	loader := NewLoader()
+
+	cfg, err := loader.LoadFromBytes([]byte(`
+defaults:
+  content_selector: ["article", "main"]
+sources:
+  - url: https://a.example.com
+  - url: https://b.example.com
+    content_selector:
+      - .md-content
+      - main
+  - url: https://c.example.com
+    content_selector: "div.docs, main"
+    exclude_selector: [nav, .sidebar]
+`), ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "article, main", cfg.Sources[0].ContentSelector)
+	assert.Equal(t, ".md-content, main", cfg.Sources[1].ContentSelector)
+	assert.Equal(t, "div.docs, main", cfg.Sources[2].ContentSelector)
+	assert.Equal(t, "nav, .sidebar", cfg.Sources[2].ExcludeSelector)
+
+	cfg, err = loader.LoadFromBytes([]byte(`{
+		"defaults": {"content_selector": "article"},
+		"sources": [
+			{"url": "https://a.example.com", "content_selector": [".md-content", "main"], "exclude_selector": ["nav", ".sidebar"]},
+			{"url": "https://b.example.com", "strategy": "crawler"}
+		]
+	}`), ".json")
+	require.NoError(t, err)
+	assert.Equal(t, ".md-content, main", cfg.Sources[0].ContentSelector)
+	assert.Equal(t, "nav, .sidebar", cfg.Sources[0].ExcludeSelector)
+	assert.Equal(t, "article", cfg.Sources[1].ContentSelector)
+	assert.Equal(t, "crawler", cfg.Sources[1].Strategy)
+
+	_, err = loader.LoadFromBytes([]byte(`{"sources": [{"url": "https://a.example.com", "content_selector": 3}]}`), ".json")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestLoader_LoadFromBytes_InvalidListMerge(t *testing.T) {
+	_, err := NewLoader().LoadFromBytes([]byte(`
+defaults:
+  list_merge: prepend
+sources:
+  - url: https://a.example.com
+`), ".yaml")
+
+	assert.ErrorIs(t, err, ErrInvalidListMerge)
+}
+
+func TestLoader_LoadFromBytes_ConcurrencySources(t *testing.T) {
+	cfg, err := NewLoader().LoadFromBytes([]byte(`
+sources:
+  - url: https://a.example.com
+options:
+  concurrency_sources: 4
+`), ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.Options.ConcurrencySources)
+
+	_, err = NewLoader().LoadFromBytes([]byte(`{"sources": [{"url": "https://a.example.com"}], "options": {"concurrency_sources": -1}}`), ".json")
+	assert.ErrorIs(t, err, ErrInvalidConcurrency)
+}
+
+func TestLoader_LoadFromBytes_SourceConcurrencyAndForce(t *testing.T) {
+	cfg, err := NewLoader().LoadFromBytes([]byte(`
+sources:
+  - url: https://a.example.com
+    concurrency: 2
+    force: true
+  - url: https://b.example.com
+`), ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Sources[0].Concurrency)
+	require.NotNil(t, cfg.Sources[0].Force)
+	assert.True(t, *cfg.Sources[0].Force)
+	assert.Zero(t, cfg.Sources[1].Concurrency)
+	assert.Nil(t, cfg.Sources[1].Force)
+
+	_, err = NewLoader().LoadFromBytes([]byte(`{"sources": [{"url": "https://a.example.com", "concurrency": -2}]}`), ".json")
+	assert.ErrorIs(t, err, ErrInvalidConcurrency)
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{
+		"":     FormatAuto,
+		"auto": FormatAuto,
+		"YAML": FormatYAML,
+		"json": FormatJSON,
+	} {
+		got, err := ParseFormat(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseFormat("toml")
+	assert.Error(t, err)
+}
+
+func TestLoader_FormatAuto_SniffsUnknownExtensions(t *testing.T) {
+	loader := NewLoaderWithOptions(LoaderOptions{Format: FormatAuto})
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "sources.manifest")
+	require.NoError(t, os.WriteFile(jsonPath, []byte("\n  {\"sources\": [{\"url\": \"https://a.example\"}]}"), 0644))
+	cfg, err := loader.Load(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example", cfg.Sources[0].URL)
+
+	yamlPath := filepath.Join(dir, "sources")
+	require.NoError(t, os.WriteFile(yamlPath, []byte("sources:\n  - url: https://b.example\n"), 0644))
+	cfg, err = loader.Load(yamlPath)
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.example", cfg.Sources[0].URL)
+	assert.Equal(t, DefaultOptions().Output, cfg.Options.Output, "defaults are applied")
+}
+
+func TestLoader_ForcedFormat(t *testing.T) {
+	data := []byte(`{"sources": [{"url": "https://a.example"}]}`)
+
+	cfg, err := NewLoaderWithOptions(LoaderOptions{Format: FormatJSON}).LoadFromBytes(data, ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example", cfg.Sources[0].URL)
+
+	_, err = NewLoaderWithOptions(LoaderOptions{Format: FormatJSON}).LoadFromBytes([]byte("sources:\n  - url: x\n"), ".json")
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestLoader_LoadReader(t *testing.T) {
+	loader := NewLoader()
+
+	cfg, err := loader.LoadReader(strings.NewReader(`{"sources": [{"url": "https://a.example"}]}`), FormatAuto)
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example", cfg.Sources[0].URL)
+
+	cfg, err = loader.LoadReader(strings.NewReader("sources:\n  - url: https://b.example\n"), "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.example", cfg.Sources[0].URL)
+
+	_, err = loader.LoadReader(strings.NewReader("sources:\n  - url: https://b.example\n"), FormatJSON)
+	assert.ErrorIs(t, err, ErrInvalidFormat)
+
+	_, err = loader.LoadReader(strings.NewReader("sources: []\n"), FormatYAML)
+	assert.ErrorIs(t, err, ErrNoSources)
+}
+
+func TestLoader_OutputCollision(t *testing.T) {
+	loader := NewLoader()
+
+	_, err := loader.LoadFromBytes([]byte(`
+sources:
+  - url: https://a.example
+    output: api
+  - url: https://b.example
+  - url: https://c.example
+    output: ./api/
+`), ".yaml")
+	assert.ErrorIs(t, err, ErrOutputCollision)
+	assert.ErrorContains(t, err, `source 2: output "./api/" already used by source 0`)
+
+	_, err = loader.LoadFromBytes([]byte(`
+sources:
+  - url: https://a.example/{v}
+    output: docs
+    matrix:
+      v: ["1", "2"]
+`), ".yaml")
+	assert.ErrorIs(t, err, ErrOutputCollision, "expanded templates are compared")
+
+	cfg, err := loader.LoadFromBytes([]byte(`
+sources:
+  - url: https://a.example
+  - url: https://b.example
+  - url: https://c.example
+    output: api
+  - url: https://d.example
+    output: api
+    enabled: false
+`), ".yaml")
+	require.NoError(t, err, "sources without output and disabled sources do not collide")
+	assert.Len(t, cfg.Sources, 4)
+
+	_, err = loader.LoadFromBytes([]byte(`
+sources:
+  - url: https://a.example
+    output: api
+  - url: https://b.example
+    output: api
+options:
+  continue_on_error: true
+`), ".yaml")
+	assert.NoError(t, err, "continue_on_error allows shared outputs")
+}
+
+func TestLoader_LoadAll_OutputCollision(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.yaml")
+	second := filepath.Join(dir, "b.yaml")
+	require.NoError(t, os.WriteFile(first, []byte("sources:\n  - url: https://a.example\n    output: api\n"), 0o644))
+	require.NoError(t, os.WriteFile(second, []byte("sources:\n  - url: https://b.example\n    output: api\n"), 0o644))
+
+	_, err := NewLoader().LoadAll(first, second)
+	assert.ErrorIs(t, err, ErrOutputCollision)
+}
+
+func TestLoader_DefaultOutput(t *testing.T) {
+	loader := NewLoaderWithOptions(LoaderOptions{DefaultOutput: "./kb"})
+
+	cfg, err := loader.LoadFromBytes([]byte("sources:\n  - url: https://a.example\n"), ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "./kb", cfg.Options.Output)
+
+	cfg, err = loader.LoadFromBytes([]byte("sources:\n  - url: https://a.example\noptions:\n  output: ./manifest\n"), ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "./manifest", cfg.Options.Output, "options.output wins over the default")
+}
+
+func TestLoader_Version(t *testing.T) {
+	loader := NewLoader()
+
+	cfg, err := loader.LoadFromBytes([]byte("sources:\n  - url: https://a.example\n"), ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, cfg.Version, "a missing version is version 1")
+
+	cfg, err = loader.LoadFromBytes([]byte("version: 1\nsources:\n  - url: https://a.example\n"), ".yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "1", cfg.Version)
+
+	cfg, err = loader.LoadFromBytes([]byte(`{"version": "1", "sources": [{"url": "https://a.example"}]}`), ".json")
+	require.NoError(t, err)
+	assert.Equal(t, "1", cfg.Version)
+
+	_, err = loader.LoadFromBytes([]byte("version: \"2\"\nsources:\n  - url: https://a.example\n"), ".yaml")
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+	assert.EqualError(t, err, `unsupported manifest version "2" (supported: 1)`)
+
+	_, err = loader.LoadFromBytes([]byte("version: \"2\"\nsources: []\n"), ".yaml")
+	assert.ErrorIs(t, err, ErrUnsupportedVersion, "the version is checked before the rest of the manifest")
+}
+
+func TestLoader_ValidatesLikeValidate(t *testing.T) {
+	_, err := NewLoader().LoadFromBytes([]byte(`
+sources:
+  - url: https://a.example
+    strategy: scraper
+  - url: https://a.example/
+`), ".yaml")
+	assert.ErrorIs(t, err, ErrInvalidStrategy)
+	assert.ErrorIs(t, err, ErrDuplicateSource, "every problem is reported")
+}
+
+func TestLoader_DuplicateSources(t *testing.T) {
+	data := []byte(`
+sources:
+  - url: https://github.com/org/repo
+    content_selector: main
+  - url: https://b.example
+  - url: " https://github.com/org/repo.git/"
+    content_selector: article
+    output: article
+  - url: https://GitHub.com/org/repo
+    content_selector: section
+    output: section
+`)
+
+	_, err := NewLoader().LoadFromBytes(data, ".yaml")
+	assert.ErrorIs(t, err, ErrDuplicateSource)
+	assert.ErrorContains(t, err, "source 2: duplicate source URL:  https://github.com/org/repo.git/ (also source 0)")
+	assert.ErrorContains(t, err, "source 3: duplicate source URL: https://GitHub.com/org/repo (also source 0)",
+		"hosts compare case-insensitively")
+
+	cfg, err := NewLoaderWithOptions(LoaderOptions{AllowDuplicates: true}).LoadFromBytes(data, ".yaml")
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 4)
+	assert.Equal(t, "article", cfg.Sources[2].ContentSelector)
+}
+
+func TestLoader_DuplicateSources_AfterExpansion(t *testing.T) {
+	data := []byte(`
+sources:
+  - url: https://a.example/2
+  - url: https://a.example/{v}
+    output: "{v}"
+    matrix:
+      v: ["1", "2"]
+`)
+
+	_, err := NewLoader().LoadFromBytes(data, ".yaml")
+	assert.ErrorIs(t, err, ErrDuplicateSource)
+	assert.ErrorContains(t, err, "source 2: duplicate source URL: https://a.example/2 (also source 0)")
+	assert.Error(t, Validate(&Config{Sources: []Source{
+		{URL: "https://a.example/2"},
+		{URL: "https://a.example/{v}", Matrix: map[string][]string{"v": {"1", "2"}}},
+	}}), "Validate compares expanded sources too")
+
+	cfg, err := NewLoaderWithOptions(LoaderOptions{AllowDuplicates: true}).LoadFromBytes(data, ".yaml")
+	require.NoError(t, err)
+	assert.Len(t, cfg.Sources, 3)
+}
+
+func TestLoader_LoadAll_AllowDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "a.yaml")
+	second := filepath.Join(dir, "b.yaml")
+	require.NoError(t, os.WriteFile(first, []byte("sources:\n  - url: https://a.example\n    content_selector: main\n"), 0o644))
+	require.NoError(t, os.WriteFile(second, []byte("sources:\n  - url: https://a.example/\n    content_selector: article\n    output: article\n"), 0o644))
+
+	_, err := NewLoader().LoadAll(first, second)
+	assert.ErrorIs(t, err, ErrDuplicateSource)
+
+	cfg, err := NewLoaderWithOptions(LoaderOptions{AllowDuplicates: true}).LoadAll(first, second)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Sources, 2)
+}
@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// Merge combines manifests into one batch. Each manifest's defaults are
+// applied to its own sources, and the sources are concatenated in order.
+// Options set in later manifests override earlier ones; unset (zero)
+// options keep the earlier values, so continue_on_error can be turned on
+// but not back off. A source URL listed more than once, in the same or
+// different manifests, is an error naming every duplicate.
+func Merge(cfgs ...*Config) (*Config, error) {
+	return merge(false, cfgs...)
+}
+
+// merge is Merge, keeping sources that share a URL when allowDuplicates is
+// set.
+func merge(allowDuplicates bool, cfgs ...*Config) (*Config, error) {
+	merged := &Config{}
+	first := make(map[string]int) // source URL -> manifest it came from
+	var duplicates []string
+
+	for i, cfg := range cfgs {
+		if cfg == nil {
+			continue
+		}
+		for _, src := range cfg.Sources {
+			key := sourceKey(src.URL)
+			prev, seen := first[key]
+			if seen && !allowDuplicates {
+				duplicates = append(duplicates, fmt.Sprintf("%s (manifests %d and %d)", src.URL, prev+1, i+1))
+				continue
+			}
+			if !seen {
+				first[key] = i
+			}
+			merged.Sources = append(merged.Sources, cfg.Defaults.Apply(src))
+		}
+		mergeOptions(&merged.Options, cfg.Options)
+	}
+
+	if len(duplicates) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateSource, strings.Join(duplicates, ", "))
+	}
+	// The sources are expanded already, and duplicates were checked above.
+	if err := validate(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// mergeOptions overrides the options in dst that src sets.
+func mergeOptions(dst *Options, src Options) {
+	if src.ContinueOnError {
+		dst.ContinueOnError = true
+	}
+	if src.Output != "" {
+		dst.Output = src.Output
+	}
+	if src.Concurrency != 0 {
+		dst.Concurrency = src.Concurrency
+	}
+	if src.CacheTTL != 0 {
+		dst.CacheTTL = src.CacheTTL
+	}
+	if src.ConcurrencySources != 0 {
+		dst.ConcurrencySources = src.ConcurrencySources
+	}
+}
+
+// sourceKey identifies a source URL for duplicate detection (see
+// utils.CanonicalRepoURL).
+func sourceKey(url string) string {
+	return utils.CanonicalRepoURL(url)
+}
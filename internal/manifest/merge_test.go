@@ -0,0 +1,96 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge_ConcatenatesSources(t *testing.T) {
+	a := &Config{
+		Sources:  []Source{{URL: "https://a.example"}, {URL: "https://b.example", Strategy: "git"}},
+		Defaults: SourceDefaults{Strategy: "crawler"},
+	}
+	b := &Config{
+		Sources:  []Source{{URL: "https://c.example"}},
+		Defaults: SourceDefaults{MaxDepth: 2},
+	}
+
+	cfg, err := Merge(a, nil, b)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 3)
+	assert.Equal(t, "https://a.example", cfg.Sources[0].URL)
+	assert.Equal(t, "crawler", cfg.Sources[0].Strategy)
+	assert.Equal(t, "git", cfg.Sources[1].Strategy)
+	assert.Equal(t, "https://c.example", cfg.Sources[2].URL)
+	assert.Empty(t, cfg.Sources[2].Strategy, "defaults only apply to their own manifest")
+	assert.Equal(t, 2, cfg.Sources[2].MaxDepth)
+	assert.Zero(t, cfg.Sources[0].MaxDepth)
+}
+
+func TestMerge_LaterOptionsOverride(t *testing.T) {
+	a := &Config{
+		Sources: []Source{{URL: "https://a.example"}},
+		Options: Options{ContinueOnError: true, Output: "./a", Concurrency: 2, CacheTTL: time.Hour},
+	}
+	b := &Config{
+		Sources: []Source{{URL: "https://b.example"}},
+		Options: Options{Output: "./b", ConcurrencySources: 4},
+	}
+
+	cfg, err := Merge(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, Options{
+		ContinueOnError:    true,
+		Output:             "./b",
+		Concurrency:        2,
+		CacheTTL:           time.Hour,
+		ConcurrencySources: 4,
+	}, cfg.Options)
+}
+
+func TestMerge_DuplicateSources(t *testing.T) {
+	a := &Config{Sources: []Source{{URL: "https://a.example/docs"}, {URL: "https://b.example"}}}
+	b := &Config{Sources: []Source{{URL: "https://a.example/docs/"}}}
+
+	_, err := Merge(a, b)
+	require.ErrorIs(t, err, ErrDuplicateSource)
+	assert.Contains(t, err.Error(), "https://a.example/docs/ (manifests 1 and 2)")
+
+	_, err = Merge(&Config{Sources: []Source{{URL: "https://a.example"}, {URL: "https://a.example"}}})
+	assert.ErrorIs(t, err, ErrDuplicateSource)
+
+	_, err = Merge(a, &Config{Sources: []Source{{URL: "https://b.example.git"}}})
+	assert.ErrorIs(t, err, ErrDuplicateSource, "a trailing .git is ignored")
+}
+
+func TestMerge_NoSources(t *testing.T) {
+	_, err := Merge()
+	assert.ErrorIs(t, err, ErrNoSources)
+}
+
+func TestLoader_LoadAll(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	team := filepath.Join(dir, "team.json")
+	require.NoError(t, os.WriteFile(base, []byte("sources:\n  - url: https://a.example\noptions:\n  output: ./kb\n  concurrency: 8\n"), 0644))
+	require.NoError(t, os.WriteFile(team, []byte(`{"sources": [{"url": "https://b.example"}], "options": {"concurrency": 3}}`), 0644))
+
+	loader := NewLoader()
+	cfg, err := loader.LoadAll(base, team)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 2)
+	assert.Equal(t, "./kb", cfg.Options.Output)
+	assert.Equal(t, 3, cfg.Options.Concurrency)
+	assert.Equal(t, DefaultOptions().CacheTTL, cfg.Options.CacheTTL, "option defaults apply after merging")
+
+	_, err = loader.LoadAll(base, filepath.Join(dir, "missing.yaml"))
+	require.ErrorIs(t, err, ErrFileNotFound)
+
+	_, err = loader.LoadAll(base, base)
+	assert.ErrorIs(t, err, ErrDuplicateSource)
+}
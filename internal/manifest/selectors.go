@@ -0,0 +1,108 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// selectorKeys are the manifest fields that accept a selector list.
+var selectorKeys = []string{"content_selector", "exclude_selector"}
+
+// selectorList decodes a selector field given either as a string or as a list
+// of selectors. A list is joined with ", ": content selectors are then tried
+// in order (see converter.ParseContentSelectors) and every exclude selector
+// is removed.
+type selectorList string
+
+// UnmarshalJSON accepts a string or an array of strings.
+func (l *selectorList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*l = selectorList(strings.Join(list, ", "))
+		return nil
+	}
+	var selector string
+	if err := json.Unmarshal(data, &selector); err != nil {
+		return fmt.Errorf("selectors must be a string or a list of strings")
+	}
+	*l = selectorList(selector)
+	return nil
+}
+
+// joinSelectorList rewrites selector lists in the YAML mapping node into
+// their string form before the node is decoded.
+func joinSelectorList(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !slices.Contains(selectorKeys, key.Value) || value.Kind != yaml.SequenceNode {
+			continue
+		}
+		var list []string
+		if err := value.Decode(&list); err != nil {
+			return fmt.Errorf("%s must be a string or a list of strings: %w", key.Value, err)
+		}
+		*value = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: strings.Join(list, ", ")}
+	}
+	return nil
+}
+
+// UnmarshalYAML accepts content_selector and exclude_selector as a string or
+// a list of selectors.
+func (s *Source) UnmarshalYAML(node *yaml.Node) error {
+	type plain Source
+	if err := joinSelectorList(node); err != nil {
+		return err
+	}
+	return node.Decode((*plain)(s))
+}
+
+// UnmarshalJSON accepts content_selector and exclude_selector as a string or
+// a list of selectors.
+func (s *Source) UnmarshalJSON(data []byte) error {
+	type plain Source
+	aux := struct {
+		*plain
+		ContentSelector selectorList `json:"content_selector,omitempty"`
+		ExcludeSelector selectorList `json:"exclude_selector,omitempty"`
+	}{plain: (*plain)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	s.ContentSelector = string(aux.ContentSelector)
+	s.ExcludeSelector = string(aux.ExcludeSelector)
+	return nil
+}
+
+// UnmarshalYAML accepts content_selector and exclude_selector as a string or
+// a list of selectors.
+func (d *SourceDefaults) UnmarshalYAML(node *yaml.Node) error {
+	type plain SourceDefaults
+	if err := joinSelectorList(node); err != nil {
+		return err
+	}
+	return node.Decode((*plain)(d))
+}
+
+// UnmarshalJSON accepts content_selector and exclude_selector as a string or
+// a list of selectors.
+func (d *SourceDefaults) UnmarshalJSON(data []byte) error {
+	type plain SourceDefaults
+	aux := struct {
+		*plain
+		ContentSelector selectorList `json:"content_selector,omitempty"`
+		ExcludeSelector selectorList `json:"exclude_selector,omitempty"`
+	}{plain: (*plain)(d)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	d.ContentSelector = string(aux.ContentSelector)
+	d.ExcludeSelector = string(aux.ExcludeSelector)
+	return nil
+}
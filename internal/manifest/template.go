@@ -0,0 +1,80 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Template is a commented manifest skeleton with one example source per
+// strategy, written by `repodocs init --sources`.
+const Template = `# RepoDocs manifest: run every source with
+#   repodocs --manifest sources.yaml
+# Replace the example URLs with your own and delete the sources you do not
+# need. "strategy" may be omitted to detect it from the URL.
+version: "1"
+
+sources:
+  # Crawl a documentation site by following its links.
+  - url: https://docs.example.com
+    strategy: crawler
+    content_selector: "article"
+    max_depth: 3
+
+  # Clone a repository and convert its documentation files.
+  - url: https://github.com/owner/repo
+    strategy: git
+    include:
+      - "docs/**/*.md"
+      - "README.md"
+
+  # Fetch every page listed in a sitemap.
+  - url: https://example.com/sitemap.xml
+    strategy: sitemap
+
+  # Fetch the pages linked from an llms.txt file.
+  - url: https://example.com/llms.txt
+    strategy: llms
+
+  # Convert an OpenAPI or Swagger specification.
+  - url: https://example.com/openapi.json
+    strategy: openapi
+
+  # Extract Go package documentation from pkg.go.dev.
+  - url: https://pkg.go.dev/github.com/owner/module
+    strategy: pkggo
+
+  # Extract Rust crate documentation from docs.rs.
+  - url: https://docs.rs/crate/latest/crate/
+    strategy: docsrs
+
+  # Clone a GitHub wiki.
+  - url: https://github.com/owner/repo/wiki
+    strategy: wiki
+
+  # Crawl a GitHub Pages site.
+  - url: https://owner.github.io/project/
+    strategy: github_pages
+
+options:
+  output: ./docs-output
+  continue_on_error: true
+`
+
+// WriteTemplate writes Template to path, creating its directory. It refuses
+// to replace an existing file unless force is set.
+func WriteTemplate(path string, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("manifest already exists at %s (use --force to overwrite)", path)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(Template), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
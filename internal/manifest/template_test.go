@@ -0,0 +1,41 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_LoadsWithEveryStrategy(t *testing.T) {
+	cfg, err := NewLoader().LoadFromBytes([]byte(Template), ".yaml")
+	require.NoError(t, err)
+
+	var strategies []string
+	for _, src := range cfg.Sources {
+		strategies = append(strategies, src.Strategy)
+	}
+	assert.ElementsMatch(t, Strategies, strategies)
+}
+
+func TestWriteTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "sources.yaml")
+
+	require.NoError(t, WriteTemplate(path, false))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, Template, string(data))
+
+	require.NoError(t, os.WriteFile(path, []byte("sources: []\n"), 0644))
+	assert.Error(t, WriteTemplate(path, false))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "sources: []\n", string(data))
+
+	require.NoError(t, WriteTemplate(path, true))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, Template, string(data))
+}
@@ -1,14 +1,28 @@
 package manifest
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 )
 
+// CurrentVersion is the manifest format version written by this release,
+// and the one assumed for manifests without a version.
+const CurrentVersion = "1"
+
+// SupportedVersions lists the manifest format versions the loader reads.
+var SupportedVersions = []string{CurrentVersion}
+
 // Config represents the complete manifest configuration
 type Config struct {
-	Sources []Source `yaml:"sources" json:"sources"`
-	Options Options  `yaml:"options" json:"options"`
+	// Version is the manifest format version; empty means CurrentVersion.
+	Version  string         `yaml:"version,omitempty" json:"version,omitempty"`
+	Sources  []Source       `yaml:"sources" json:"sources"`
+	Options  Options        `yaml:"options" json:"options"`
+	Defaults SourceDefaults `yaml:"defaults,omitempty" json:"defaults,omitempty"`
 }
 
 // Source represents an individual documentation source
@@ -22,6 +36,101 @@ type Source struct {
 	MaxDepth        int      `yaml:"max_depth,omitempty" json:"max_depth,omitempty"`
 	RenderJS        *bool    `yaml:"render_js,omitempty" json:"render_js,omitempty"`
 	Limit           int      `yaml:"limit,omitempty" json:"limit,omitempty"`
+	// Concurrency is the number of page workers for the source, over
+	// concurrency.workers and --concurrency (0 keeps the run's value).
+	Concurrency int `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	// Force re-extracts the source's pages that were already written, like
+	// --force; nil keeps the run's setting.
+	Force *bool `yaml:"force,omitempty" json:"force,omitempty"`
+	// Tags are key/value labels attached to every document of the source,
+	// over the run's --tag labels.
+	Tags map[string]string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Enabled toggles the source without removing it; nil means enabled.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// Disabled skips the source without removing it, like enabled: false.
+	// It wins over Enabled.
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	// Output is the directory, relative to the output directory, the
+	// source's documents are written to; empty writes them to the output
+	// directory itself.
+	Output string `yaml:"output,omitempty" json:"output,omitempty"`
+	// Matrix turns the source into a template: it is expanded at load time
+	// into one source per combination of values, with {key} placeholders
+	// replaced (see Expand).
+	Matrix map[string][]string `yaml:"matrix,omitempty" json:"matrix,omitempty"`
+}
+
+// List merge modes for SourceDefaults.ListMerge.
+const (
+	ListMergeReplace = "replace"
+	ListMergeAppend  = "append"
+)
+
+// SourceDefaults holds source fields inherited by every source that does not
+// set them itself. ListMerge controls the include/exclude lists: "replace"
+// (default) uses a source's own list instead of the default one, "append"
+// adds the source's entries to the defaults.
+type SourceDefaults struct {
+	Strategy        string   `yaml:"strategy,omitempty" json:"strategy,omitempty"`
+	ContentSelector string   `yaml:"content_selector,omitempty" json:"content_selector,omitempty"`
+	ExcludeSelector string   `yaml:"exclude_selector,omitempty" json:"exclude_selector,omitempty"`
+	Exclude         []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+	Include         []string `yaml:"include,omitempty" json:"include,omitempty"`
+	MaxDepth        int      `yaml:"max_depth,omitempty" json:"max_depth,omitempty"`
+	RenderJS        *bool    `yaml:"render_js,omitempty" json:"render_js,omitempty"`
+	Limit           int      `yaml:"limit,omitempty" json:"limit,omitempty"`
+	ListMerge       string   `yaml:"list_merge,omitempty" json:"list_merge,omitempty"`
+}
+
+// Apply returns src with unset fields filled from the defaults.
+func (d SourceDefaults) Apply(src Source) Source {
+	if src.Strategy == "" {
+		src.Strategy = d.Strategy
+	}
+	if src.ContentSelector == "" {
+		src.ContentSelector = d.ContentSelector
+	}
+	if src.ExcludeSelector == "" {
+		src.ExcludeSelector = d.ExcludeSelector
+	}
+	if src.MaxDepth == 0 {
+		src.MaxDepth = d.MaxDepth
+	}
+	if src.RenderJS == nil && d.RenderJS != nil {
+		renderJS := *d.RenderJS
+		src.RenderJS = &renderJS
+	}
+	if src.Limit == 0 {
+		src.Limit = d.Limit
+	}
+	src.Exclude = mergeList(d.Exclude, src.Exclude, d.ListMerge)
+	src.Include = mergeList(d.Include, src.Include, d.ListMerge)
+	return src
+}
+
+func mergeList(defaults, own []string, mode string) []string {
+	if mode == ListMergeAppend {
+		if len(defaults) == 0 {
+			return own
+		}
+		merged := append([]string(nil), defaults...)
+		for _, v := range own {
+			if !slices.Contains(merged, v) {
+				merged = append(merged, v)
+			}
+		}
+		return merged
+	}
+	if len(own) > 0 {
+		return own
+	}
+	return append([]string(nil), defaults...)
+}
+
+// IsEnabled reports whether the source should be processed. Sources are
+// enabled unless they set enabled: false or disabled: true.
+func (s Source) IsEnabled() bool {
+	return !s.Disabled && (s.Enabled == nil || *s.Enabled)
 }
 
 // Options represents global manifest options
@@ -30,21 +139,204 @@ type Options struct {
 	Output          string        `yaml:"output,omitempty" json:"output,omitempty"`
 	Concurrency     int           `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
 	CacheTTL        time.Duration `yaml:"cache_ttl,omitempty" json:"cache_ttl,omitempty"`
+	// ConcurrencySources is the number of sources extracted in parallel
+	// (0 = default).
+	ConcurrencySources int `yaml:"concurrency_sources,omitempty" json:"concurrency_sources,omitempty"`
 }
 
-// Validate validates the manifest configuration
+// Strategies lists the strategy names a source, or the defaults, may set.
+// An empty strategy is detected from the URL.
+var Strategies = []string{"llms", "openapi", "pkggo", "docsrs", "sitemap", "wiki", "github_pages", "git", "crawler"}
+
+// Validate validates the manifest configuration (see Validate).
 func (c *Config) Validate() error {
-	if len(c.Sources) == 0 {
+	return Validate(c)
+}
+
+// Validate checks a manifest, such as one built in code, the way the loader
+// does after parsing it: a supported version, at least one source, a URL for
+// every source, known strategy names, valid tags, matrices, outputs and
+// options, and no URL listed twice once matrices and file:// globs are
+// expanded (ignoring trailing slashes, ".git" and the case of the host, see
+// utils.CanonicalRepoURL). Every problem found is returned, joined with
+// errors.Join, except for an unsupported version, which is reported alone
+// since the rest of the manifest cannot be interpreted.
+func Validate(cfg *Config) error {
+	_, err := validateExpanded(cfg, false)
+	return err
+}
+
+// validateExpanded validates cfg, expands its sources (see expandSources)
+// and checks the expanded sources for duplicate URLs unless
+// allowDuplicates is set. It returns the expanded sources.
+func validateExpanded(cfg *Config, allowDuplicates bool) ([]Source, error) {
+	err := validate(cfg)
+	if cfg == nil || errors.Is(err, ErrUnsupportedVersion) {
+		return nil, err
+	}
+	sources, expandErr := expandSources(cfg.Sources)
+	if expandErr != nil {
+		// A template that does not expand is already reported by validate.
+		if err != nil {
+			return nil, err
+		}
+		return nil, expandErr
+	}
+	if dupErr := checkDuplicates(sources, allowDuplicates); dupErr != nil {
+		// Keep one flat list of problems, as validate returns them.
+		var errs []error
+		for _, e := range []error{err, dupErr} {
+			if joined, ok := e.(interface{ Unwrap() []error }); ok {
+				errs = append(errs, joined.Unwrap()...)
+			} else if e != nil {
+				errs = append(errs, e)
+			}
+		}
+		err = errors.Join(errs...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// checkDuplicates reports every source whose URL an earlier source already
+// lists as ErrDuplicateSource, unless allowDuplicates is set.
+func checkDuplicates(sources []Source, allowDuplicates bool) error {
+	if allowDuplicates {
+		return nil
+	}
+	var errs []error
+	first := make(map[string]int) // source URL -> index of its first source
+	for i, src := range sources {
+		if src.URL == "" {
+			continue
+		}
+		if j, ok := first[sourceKey(src.URL)]; ok {
+			errs = append(errs, fmt.Errorf("source %d: %w: %s (also source %d)", i, ErrDuplicateSource, src.URL, j))
+		} else {
+			first[sourceKey(src.URL)] = i
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validate is Validate without the duplicate check, on sources as written.
+func validate(cfg *Config) error {
+	if cfg == nil {
 		return ErrNoSources
 	}
-	for i, src := range c.Sources {
+	if cfg.Version != "" && !slices.Contains(SupportedVersions, cfg.Version) {
+		return fmt.Errorf("%w %q (supported: %s)", ErrUnsupportedVersion, cfg.Version, strings.Join(SupportedVersions, ", "))
+	}
+
+	var errs []error
+	if len(cfg.Sources) == 0 {
+		errs = append(errs, ErrNoSources)
+	}
+	switch cfg.Defaults.ListMerge {
+	case "", ListMergeReplace, ListMergeAppend:
+	default:
+		errs = append(errs, fmt.Errorf("defaults.list_merge %q: %w", cfg.Defaults.ListMerge, ErrInvalidListMerge))
+	}
+	if err := validateStrategy(cfg.Defaults.Strategy); err != nil {
+		errs = append(errs, fmt.Errorf("defaults: %w", err))
+	}
+	if cfg.Options.ConcurrencySources < 0 {
+		errs = append(errs, fmt.Errorf("options.concurrency_sources %d: %w", cfg.Options.ConcurrencySources, ErrInvalidConcurrency))
+	}
+
+	for i, src := range cfg.Sources {
 		if src.URL == "" {
-			return fmt.Errorf("source %d: %w", i, ErrEmptyURL)
+			errs = append(errs, fmt.Errorf("source %d: %w", i, ErrEmptyURL))
+		}
+		if err := validateStrategy(src.Strategy); err != nil {
+			errs = append(errs, fmt.Errorf("source %d: %w", i, err))
+		}
+		if src.Concurrency < 0 {
+			errs = append(errs, fmt.Errorf("source %d: concurrency %d: %w", i, src.Concurrency, ErrInvalidConcurrency))
+		}
+		if _, ok := src.Tags[""]; ok {
+			errs = append(errs, fmt.Errorf("source %d: %w", i, ErrEmptyTagKey))
+		}
+		if err := src.validateMatrix(); err != nil {
+			errs = append(errs, fmt.Errorf("source %d: %w", i, err))
+		}
+		if len(src.Matrix) == 0 {
+			if err := validateOutput(src.Output); err != nil {
+				errs = append(errs, fmt.Errorf("source %d: %w", i, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateStrategy checks that a strategy name is empty or one of
+// Strategies.
+func validateStrategy(name string) error {
+	if name == "" || slices.Contains(Strategies, name) {
+		return nil
+	}
+	return fmt.Errorf("strategy %q: %w", name, ErrInvalidStrategy)
+}
+
+// validateOutput checks that a source output directory stays inside the
+// output directory.
+func validateOutput(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	clean := filepath.Clean(dir)
+	if filepath.IsAbs(dir) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("output %q: %w", dir, ErrInvalidOutput)
+	}
+	return nil
+}
+
+// CheckOutputCollisions reports enabled sources that set the same output
+// directory, after template expansion, as ErrOutputCollision. Sources
+// without an output share the output directory by design and are not
+// compared. With continue_on_error shared directories are allowed.
+func (c *Config) CheckOutputCollisions() error {
+	if c.Options.ContinueOnError {
+		return nil
+	}
+	owners := make(map[string]int)
+	for i, src := range c.Sources {
+		if src.Output == "" || !src.IsEnabled() {
+			continue
+		}
+		dir := filepath.Clean(src.Output)
+		if j, ok := owners[dir]; ok {
+			return fmt.Errorf("source %d: output %q already used by source %d: %w", i, src.Output, j, ErrOutputCollision)
 		}
+		owners[dir] = i
 	}
 	return nil
 }
 
+// ActiveSources returns the sources to process, leaving out disabled ones
+// (see Source.IsEnabled). The loader keeps disabled sources in Sources.
+func (c *Config) ActiveSources() []Source {
+	active := make([]Source, 0, len(c.Sources))
+	for _, src := range c.Sources {
+		if src.IsEnabled() {
+			active = append(active, src)
+		}
+	}
+	return active
+}
+
+// Warnings returns non-fatal problems with a valid manifest, such as every
+// source being disabled.
+func (c *Config) Warnings() []string {
+	var warnings []string
+	if len(c.Sources) > 0 && len(c.ActiveSources()) == 0 {
+		warnings = append(warnings, "all sources are disabled; nothing will be extracted")
+	}
+	return warnings
+}
+
 // DefaultOptions returns options with sensible defaults
 func DefaultOptions() Options {
 	return Options{
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultOptions(t *testing.T) {
@@ -57,6 +58,20 @@ func TestConfig_Validate_EmptyURLFirstSource(t *testing.T) {
 	assert.ErrorIs(t, err, ErrEmptyURL)
 }
 
+func TestConfig_Validate_EmptyTagKey(t *testing.T) {
+	cfg := &Config{
+		Sources: []Source{
+			{URL: "https://example.com", Tags: map[string]string{"": "platform"}},
+		},
+		Options: DefaultOptions(),
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "source 0")
+	assert.ErrorIs(t, err, ErrEmptyTagKey)
+}
+
 func TestConfig_Validate_Valid(t *testing.T) {
 	cfg := &Config{
 		Sources: []Source{
@@ -141,3 +156,127 @@ func TestOptions_Fields(t *testing.T) {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestSource_IsEnabled(t *testing.T) {
+	enabled, disabled := true, false
+
+	assert.True(t, Source{URL: "https://example.com"}.IsEnabled(), "sources are enabled by default")
+	assert.True(t, Source{URL: "https://example.com", Enabled: &enabled}.IsEnabled())
+	assert.False(t, Source{URL: "https://example.com", Enabled: &disabled}.IsEnabled())
+	assert.False(t, Source{URL: "https://example.com", Disabled: true}.IsEnabled())
+	assert.False(t, Source{URL: "https://example.com", Enabled: &enabled, Disabled: true}.IsEnabled(), "disabled wins")
+}
+
+func TestConfig_ActiveSources(t *testing.T) {
+	off := false
+	cfg := &Config{Sources: []Source{
+		{URL: "https://a.com"},
+		{URL: "https://b.com", Disabled: true},
+		{URL: "https://c.com", Enabled: &off},
+		{URL: "https://d.com"},
+	}}
+
+	active := cfg.ActiveSources()
+	require.Len(t, active, 2)
+	assert.Equal(t, "https://a.com", active[0].URL)
+	assert.Equal(t, "https://d.com", active[1].URL)
+	assert.Len(t, cfg.Sources, 4, "sources are left as they are")
+
+	assert.Empty(t, (&Config{}).ActiveSources())
+}
+
+func TestConfig_Warnings(t *testing.T) {
+	disabled := false
+
+	cfg := &Config{Sources: []Source{{URL: "https://a.com", Enabled: &disabled}, {URL: "https://b.com"}}}
+	assert.Empty(t, cfg.Warnings())
+
+	cfg.Sources[1].Enabled = &disabled
+	assert.Equal(t, []string{"all sources are disabled; nothing will be extracted"}, cfg.Warnings())
+}
+
+func TestSourceDefaults_Apply(t *testing.T) {
+	renderJS, noJS := true, false
+	defaults := SourceDefaults{
+		Strategy:        "crawler",
+		ContentSelector: "article.main",
+		Exclude:         []string{"/blog"},
+		MaxDepth:        2,
+		RenderJS:        &renderJS,
+	}
+
+	inherited := defaults.Apply(Source{URL: "https://a.com"})
+	assert.Equal(t, "crawler", inherited.Strategy)
+	assert.Equal(t, "article.main", inherited.ContentSelector)
+	assert.Equal(t, []string{"/blog"}, inherited.Exclude)
+	assert.Equal(t, 2, inherited.MaxDepth)
+	require.NotNil(t, inherited.RenderJS)
+	assert.True(t, *inherited.RenderJS)
+
+	overridden := defaults.Apply(Source{
+		URL:             "https://b.com",
+		Strategy:        "git",
+		ContentSelector: "main",
+		Exclude:         []string{"/changelog"},
+		RenderJS:        &noJS,
+	})
+	assert.Equal(t, "git", overridden.Strategy)
+	assert.Equal(t, "main", overridden.ContentSelector)
+	assert.Equal(t, []string{"/changelog"}, overridden.Exclude, "lists are replaced by default")
+	assert.False(t, *overridden.RenderJS)
+
+	defaults.ListMerge = ListMergeAppend
+	appended := defaults.Apply(Source{URL: "https://c.com", Exclude: []string{"/changelog", "/blog"}})
+	assert.Equal(t, []string{"/blog", "/changelog"}, appended.Exclude, "append keeps defaults first without duplicates")
+}
+
+func TestConfig_Validate_ListMerge(t *testing.T) {
+	cfg := &Config{Sources: []Source{{URL: "https://a.com"}}, Defaults: SourceDefaults{ListMerge: "merge"}}
+	assert.ErrorIs(t, cfg.Validate(), ErrInvalidListMerge)
+
+	cfg.Defaults.ListMerge = ListMergeAppend
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_JoinsErrors(t *testing.T) {
+	cfg := &Config{
+		Sources: []Source{
+			{URL: "https://a.com/docs"},
+			{URL: ""},
+			{URL: "https://a.com/docs/", Strategy: "scraper"},
+			{URL: "https://b.com", Output: "../b"},
+		},
+		Defaults: SourceDefaults{Strategy: "git", ListMerge: "merge"},
+	}
+
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrEmptyURL)
+	assert.ErrorIs(t, err, ErrDuplicateSource)
+	assert.ErrorIs(t, err, ErrInvalidStrategy)
+	assert.ErrorIs(t, err, ErrInvalidOutput)
+	assert.ErrorIs(t, err, ErrInvalidListMerge)
+	assert.NotErrorIs(t, err, ErrNoSources)
+	assert.Contains(t, err.Error(), "source 1: source URL cannot be empty")
+	assert.Contains(t, err.Error(), "source 2: duplicate source URL: https://a.com/docs/ (also source 0)")
+	assert.Contains(t, err.Error(), `source 2: strategy "scraper"`)
+
+	assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 5, "one error per problem")
+}
+
+func TestValidate_Strategies(t *testing.T) {
+	for _, name := range append([]string{""}, Strategies...) {
+		cfg := &Config{Sources: []Source{{URL: "https://a.com", Strategy: name}}}
+		assert.NoError(t, Validate(cfg), "strategy %q", name)
+	}
+
+	cfg := &Config{Sources: []Source{{URL: "https://a.com"}}, Defaults: SourceDefaults{Strategy: "auto"}}
+	err := Validate(cfg)
+	assert.ErrorIs(t, err, ErrInvalidStrategy)
+	assert.ErrorContains(t, err, `defaults: strategy "auto"`)
+}
+
+func TestValidate_Nil(t *testing.T) {
+	assert.ErrorIs(t, Validate(nil), ErrNoSources)
+	assert.ErrorIs(t, Validate(&Config{Version: "9"}), ErrUnsupportedVersion)
+}
@@ -0,0 +1,40 @@
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FromURLList builds a manifest from a plain list of URLs, one per line.
+// Blank lines and lines starting with "#" are ignored, and duplicate URLs are
+// dropped keeping the first occurrence. Each URL becomes a source with an
+// auto-detected strategy, and the batch continues past failing URLs,
+// extracting one URL at a time in list order. Other options are left unset
+// so the caller's configuration applies.
+func FromURLList(r io.Reader) (*Config, error) {
+	cfg := &Config{Options: Options{ContinueOnError: true, ConcurrencySources: 1}}
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		cfg.Sources = append(cfg.Sources, Source{URL: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URL list: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,35 @@
+package manifest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromURLList(t *testing.T) {
+	input := `# docs to refresh
+https://docs.example.com/a
+
+  https://github.com/org/repo
+https://docs.example.com/a
+# trailing comment
+`
+	cfg, err := FromURLList(strings.NewReader(input))
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Sources, 2)
+	assert.Equal(t, "https://docs.example.com/a", cfg.Sources[0].URL)
+	assert.Equal(t, "https://github.com/org/repo", cfg.Sources[1].URL)
+	assert.Empty(t, cfg.Sources[0].Strategy)
+	assert.True(t, cfg.Options.ContinueOnError)
+	assert.Equal(t, 1, cfg.Options.ConcurrencySources)
+	assert.Empty(t, cfg.Options.Output)
+}
+
+func TestFromURLList_Empty(t *testing.T) {
+	_, err := FromURLList(strings.NewReader("\n# nothing here\n"))
+	assert.True(t, errors.Is(err, ErrNoSources))
+}
@@ -0,0 +1,280 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/quantmind-br/repodocs/internal/converter"
+)
+
+// ChecksumsFilename is the name of the checksum list written to the output
+// directory.
+const ChecksumsFilename = "checksums.txt"
+
+// ErrNoChecksums is returned by VerifyChecksums for a directory without a
+// checksum list.
+var ErrNoChecksums = errors.New("no " + ChecksumsFilename + " found")
+
+// WriteChecksums writes checksums.txt to dir, listing the content hash of
+// every document in files (see Writer.Files) with algo. A document's hash is
+// taken over its body without front matter and normalized like
+// domain.Document.ContentHash (see converter.ContentHashWith), so it matches
+// the content_hash the document was written with unless post-processing,
+// such as link rewriting, changed the body. Entries of an earlier list whose
+// files still exist are kept for the documents a run skips, such as pages an
+// incremental sync found unchanged. It returns the number of files listed.
+func WriteChecksums(dir string, files []string, algo converter.HashAlgorithm) (int, error) {
+	if algo == "" {
+		algo = converter.HashSHA256
+	}
+
+	sums := make(map[string]string)
+	if previous, previousAlgo, err := readChecksums(filepath.Join(dir, ChecksumsFilename)); err == nil {
+		for rel, sum := range previous {
+			path := filepath.Join(dir, filepath.FromSlash(rel))
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			if previousAlgo != algo {
+				if sum, err = fileContentHash(path, algo); err != nil {
+					return 0, err
+				}
+			}
+			sums[rel] = sum
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+
+	for _, path := range files {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		sum, err := fileContentHash(path, algo)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		sums[filepath.ToSlash(rel)] = sum
+	}
+
+	paths := make([]string, 0, len(sums))
+	for path := range sums {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s%s\n", checksumAlgorithmHeader, algo)
+	for _, path := range paths {
+		fmt.Fprintf(&buf, "%s  %s\n", sums[path], path)
+	}
+
+	// Write through a temporary file so an interrupted run never leaves a
+	// truncated list behind.
+	target := filepath.Join(dir, ChecksumsFilename)
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+	return len(paths), nil
+}
+
+// ChecksumReport is the outcome of VerifyChecksums. Paths are relative to
+// Dir, with forward slashes.
+type ChecksumReport struct {
+	Dir string
+	// Checked is the number of files listed in checksums.txt.
+	Checked int
+	// Mismatched lists files whose content no longer matches their digest.
+	Mismatched []string
+	// Missing lists files in checksums.txt that no longer exist.
+	Missing []string
+	// Untracked lists files that are not in checksums.txt.
+	Untracked []string
+}
+
+// OK reports whether every listed file matches and no file was added.
+func (r *ChecksumReport) OK() bool {
+	return len(r.Mismatched) == 0 && len(r.Missing) == 0 && len(r.Untracked) == 0
+}
+
+// VerifyChecksums recomputes the content hashes of the documents listed in
+// dir's checksums.txt and compares them with the list. Files outside the
+// list are reported as untracked, except hidden files and the files repodocs
+// generates next to the documents (metadata.json, sitemap.xml and
+// chunks.jsonl).
+func VerifyChecksums(dir string) (*ChecksumReport, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	expected, algo, err := readChecksums(filepath.Join(dir, ChecksumsFilename))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w in %s", ErrNoChecksums, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ChecksumReport{Dir: dir, Checked: len(expected)}
+	for rel, sum := range expected {
+		got, err := fileContentHash(filepath.Join(dir, filepath.FromSlash(rel)), algo)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			report.Missing = append(report.Missing, rel)
+		case err != nil:
+			return nil, err
+		case got != sum:
+			report.Mismatched = append(report.Mismatched, rel)
+		}
+	}
+
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range files {
+		if _, ok := expected[rel]; !ok && !generatedFiles[rel] {
+			report.Untracked = append(report.Untracked, rel)
+		}
+	}
+	sort.Strings(report.Mismatched)
+	sort.Strings(report.Missing)
+	sort.Strings(report.Untracked)
+	return report, nil
+}
+
+// Format writes a human-readable report of the verification to w.
+func (r *ChecksumReport) Format(w io.Writer) {
+	fmt.Fprintf(w, "Directory:    %s\n", r.Dir)
+	fmt.Fprintf(w, "Checked:      %d files\n", r.Checked)
+	for _, section := range []struct {
+		title string
+		paths []string
+	}{
+		{"Modified", r.Mismatched},
+		{"Missing", r.Missing},
+		{"Not in " + ChecksumsFilename, r.Untracked},
+	} {
+		if len(section.paths) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s (%d):\n", section.title, len(section.paths))
+		for _, path := range section.paths {
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+	}
+	if r.OK() {
+		fmt.Fprintln(w, "\nAll files match.")
+	}
+}
+
+// checksumAlgorithmHeader starts the first line of checksums.txt, which
+// names the hash algorithm of the list.
+const checksumAlgorithmHeader = "# algorithm: "
+
+// generatedFiles are the files in the output directory that are not
+// documents, by slash-separated relative path.
+var generatedFiles = map[string]bool{
+	ChecksumsFilename:          true,
+	ChecksumsFilename + ".tmp": true,
+	SitemapFilename:            true,
+	ChunksFilename:             true,
+	"metadata.json":            true,
+}
+
+// listFiles returns the slash-separated relative paths of the non-hidden
+// regular files under dir.
+func listFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files, err
+}
+
+// fileContentHash returns the content hash of the document at path: its
+// body after any front matter, hashed with algo like
+// domain.Document.ContentHash.
+func fileContentHash(path string, algo converter.HashAlgorithm) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	content := strings.ReplaceAll(strings.TrimPrefix(string(data), utf8BOM), "\r\n", "\n")
+	_, body := splitFrontmatter(content)
+	return converter.ContentHashWith(body, algo), nil
+}
+
+// readChecksums parses checksums.txt into hashes keyed by path, with the
+// algorithm its header names (sha256 without one).
+func readChecksums(path string) (map[string]string, converter.HashAlgorithm, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	sums := make(map[string]string)
+	algo := converter.HashSHA256
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if name, ok := strings.CutPrefix(text, checksumAlgorithmHeader); ok {
+			if algo, err = converter.ParseHashAlgorithm(name); err != nil {
+				return nil, "", fmt.Errorf("%s:%d: %w", ChecksumsFilename, line, err)
+			}
+			continue
+		}
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		sum, file, ok := strings.Cut(text, "  ")
+		if !ok || len(sum) != sha256.Size*2 || file == "" {
+			return nil, "", fmt.Errorf("%s:%d: malformed checksum line", ChecksumsFilename, line)
+		}
+		sums[file] = strings.ToLower(sum)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	return sums, algo, nil
+}
@@ -0,0 +1,132 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteChecksums(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) string {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	intro := write("guide/intro.md", "---\ntitle: Intro\n---\n\nHello\n")
+	index := write("index.md", "# Index\r\n")
+	write("stray.md", "not a document of the run")
+	write(".repodocs-state.json", "{}")
+
+	n, err := WriteChecksums(dir, []string{intro, index}, converter.HashSHA256)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	data, err := os.ReadFile(filepath.Join(dir, ChecksumsFilename))
+	require.NoError(t, err)
+	assert.Equal(t,
+		"# algorithm: sha256\n"+
+			converter.ContentHash("Hello\n")+"  guide/intro.md\n"+
+			converter.ContentHash("# Index\n")+"  index.md\n",
+		string(data), "content hashes of the bodies, without front matter")
+
+	// A run that skips a document keeps its entry while the file exists.
+	n, err = WriteChecksums(dir, []string{index}, converter.HashSHA256)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	require.NoError(t, os.Remove(intro))
+	n, err = WriteChecksums(dir, []string{index}, converter.HashSHA256)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestWriteChecksums_Algorithm(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.md")
+	require.NoError(t, os.WriteFile(path, []byte("alpha"), 0644))
+
+	_, err := WriteChecksums(dir, []string{path}, converter.HashSHA256)
+	require.NoError(t, err)
+
+	// Switching algorithms rehashes the entries carried over.
+	_, err = WriteChecksums(dir, nil, converter.HashBLAKE3)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, ChecksumsFilename))
+	require.NoError(t, err)
+	assert.Equal(t,
+		"# algorithm: blake3\n"+converter.ContentHashWith("alpha", converter.HashBLAKE3)+"  a.md\n",
+		string(data))
+
+	report, err := VerifyChecksums(dir)
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) string {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	files := []string{
+		write("a.md", "---\ntitle: A\n---\n\nalpha\n"),
+		write("b.md", "beta"),
+		write("docs/c.md", "gamma"),
+	}
+	write(SitemapFilename, "<urlset/>")
+	write("metadata.json", "{}")
+	_, err := WriteChecksums(dir, files, converter.HashSHA256)
+	require.NoError(t, err)
+
+	report, err := VerifyChecksums(dir)
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, 3, report.Checked)
+
+	// Front matter is not part of the content hash.
+	write("a.md", "---\ntitle: A\nfetched_at: later\n---\n\nalpha\n")
+	report, err = VerifyChecksums(dir)
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+
+	write("a.md", "---\ntitle: A\n---\n\ntampered\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "b.md")))
+	write("docs/new.md", "added")
+	write(".repodocs-state.json", "{}")
+
+	report, err = VerifyChecksums(dir)
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Equal(t, []string{"a.md"}, report.Mismatched)
+	assert.Equal(t, []string{"b.md"}, report.Missing)
+	assert.Equal(t, []string{"docs/new.md"}, report.Untracked)
+
+	var buf bytes.Buffer
+	report.Format(&buf)
+	assert.Contains(t, buf.String(), "Modified (1):\n  a.md")
+	assert.Contains(t, buf.String(), "Missing (1):\n  b.md")
+	assert.Contains(t, buf.String(), "Not in checksums.txt (1):\n  docs/new.md")
+}
+
+func TestVerifyChecksums_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := VerifyChecksums(dir)
+	assert.ErrorIs(t, err, ErrNoChecksums)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ChecksumsFilename), []byte("nonsense\n"), 0644))
+	_, err = VerifyChecksums(dir)
+	assert.ErrorContains(t, err, "checksums.txt:1: malformed checksum line")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ChecksumsFilename), []byte("# algorithm: md5\n"), 0644))
+	_, err = VerifyChecksums(dir)
+	assert.ErrorContains(t, err, "checksums.txt:1: invalid hash algorithm")
+}
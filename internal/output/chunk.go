@@ -0,0 +1,717 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// ChunksFilename is the name of the JSONL file chunks are written to.
+const ChunksFilename = "chunks.jsonl"
+
+// charsPerToken is the ratio used to estimate token counts from characters.
+const charsPerToken = 4
+
+// ChunkUnit selects how chunk sizes are measured.
+type ChunkUnit string
+
+const (
+	// ChunkUnitChars measures chunks in characters (the default).
+	ChunkUnitChars ChunkUnit = "chars"
+	// ChunkUnitTokens measures chunks in estimated tokens, about four
+	// characters each.
+	ChunkUnitTokens ChunkUnit = "tokens"
+)
+
+// ParseChunkUnit parses a chunk size unit. Empty means chars.
+func ParseChunkUnit(value string) (ChunkUnit, error) {
+	switch unit := ChunkUnit(strings.ToLower(strings.TrimSpace(value))); unit {
+	case "":
+		return ChunkUnitChars, nil
+	case ChunkUnitChars, ChunkUnitTokens:
+		return unit, nil
+	default:
+		return "", fmt.Errorf("invalid chunk unit %q (use chars or tokens)", value)
+	}
+}
+
+// ChunkOptions configures how documents are split into chunks.
+type ChunkOptions struct {
+	// Size is the maximum chunk size in Unit. Zero or less disables
+	// chunking.
+	Size int
+	// Overlap is how much of the end of a chunk is repeated at the start of
+	// the next one, in Unit. It is kept below Size.
+	Overlap int
+	Unit    ChunkUnit
+}
+
+// limits returns the chunk size and overlap in characters.
+func (o ChunkOptions) limits() (size, overlap int) {
+	scale := 1
+	if o.Unit == ChunkUnitTokens {
+		scale = charsPerToken
+	}
+	size = o.Size * scale
+	overlap = o.Overlap * scale
+	if overlap >= size {
+		overlap = size / 2
+	}
+	if overlap < 0 {
+		overlap = 0
+	}
+	return size, overlap
+}
+
+// TextChunk is one chunk of a markdown document and the headings it falls
+// under, outermost first.
+type TextChunk struct {
+	Headings []string
+	Content  string
+}
+
+// Chunk is a JSONL record of one chunk of a document.
+type Chunk struct {
+	// ID is the document ID and the chunk index, e.g. "<document_id>-3".
+	ID         string `json:"id"`
+	DocumentID string `json:"document_id"`
+	URL        string `json:"url"`
+	Title      string `json:"title,omitempty"`
+	// Path is the document's output file, relative to the output directory.
+	Path            string   `json:"path,omitempty"`
+	Index           int      `json:"chunk_index"`
+	Count           int      `json:"chunk_count"`
+	Headings        []string `json:"headings,omitempty"`
+	Content         string   `json:"content"`
+	Chars           int      `json:"chars"`
+	EstimatedTokens int      `json:"estimated_tokens"`
+}
+
+// chunkBlock is a paragraph, heading or fenced code block of a document.
+type chunkBlock struct {
+	text     string
+	code     bool
+	heading  bool
+	headings []string
+}
+
+// SplitChunks splits markdown into chunks of at most opts.Size, overlapping
+// by up to opts.Overlap. Chunks break between paragraphs where possible;
+// longer paragraphs break between words and longer fenced code blocks
+// between lines, with every piece re-fenced so no chunk has an unbalanced
+// fence. Headings never end a chunk, and each chunk records the headings in
+// effect where it starts.
+func SplitChunks(markdown string, opts ChunkOptions) []TextChunk {
+	size, overlap := opts.limits()
+	if size <= 0 {
+		return nil
+	}
+
+	var blocks []chunkBlock
+	for _, block := range splitBlocks(markdown) {
+		blocks = append(blocks, splitBlock(block, size)...)
+	}
+
+	var chunks []TextChunk
+	var current []chunkBlock
+	var headings []string
+	currentLen := 0
+	for i, block := range blocks {
+		blockLen := utf8.RuneCountInString(block.text)
+		if len(current) > 0 && currentLen+2+blockLen > size {
+			// Move trailing headings to the next chunk, with the content
+			// they introduce.
+			cut := len(current)
+			for cut > 0 && current[cut-1].heading {
+				cut--
+			}
+			carry := current[cut:]
+			if cut == 0 || blocksLen(carry)+2+blockLen > size {
+				cut, carry = len(current), nil
+			}
+
+			chunks = append(chunks, TextChunk{Headings: headings, Content: joinBlocks(current[:cut])})
+			room := size - blockLen - 2
+			if len(carry) > 0 {
+				room -= blocksLen(carry) + 2
+			}
+			current = append(overlapBlocks(current[:cut], overlap, room), carry...)
+			currentLen = blocksLen(current)
+			headings = block.headings
+			if len(carry) > 0 {
+				headings = carry[0].headings
+			}
+		} else if i == 0 {
+			headings = block.headings
+		}
+		if len(current) > 0 {
+			currentLen += 2
+		}
+		current = append(current, block)
+		currentLen += blockLen
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, TextChunk{Headings: headings, Content: joinBlocks(current)})
+	}
+	return chunks
+}
+
+// splitBlocks splits markdown at blank lines into blocks, keeping fenced
+// code blocks whole, and tracks the heading path of each block.
+func splitBlocks(markdown string) []chunkBlock {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	var blocks []chunkBlock
+	var path []string // heading text by level - 1
+	var buf []string
+	var fence string
+	code, heading := false, false
+
+	flush := func() {
+		if len(buf) > 0 {
+			blocks = append(blocks, chunkBlock{text: strings.Join(buf, "\n"), code: code, heading: heading, headings: headingPath(path)})
+		}
+		buf = nil
+		code, heading = false, false
+	}
+
+	for _, line := range lines {
+		if fence != "" {
+			buf = append(buf, line)
+			if strings.HasPrefix(strings.TrimSpace(line), fence) && strings.Trim(strings.TrimSpace(line), fence[:1]) == "" {
+				fence = ""
+				flush()
+			}
+			continue
+		}
+		if marker := chunkFence(line); marker != "" {
+			flush()
+			fence = marker
+			code = true
+			buf = append(buf, line)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if level, text := chunkHeading(line); level > 0 {
+			flush()
+			if len(path) >= level {
+				path = path[:level-1]
+			}
+			for len(path) < level-1 {
+				path = append(path, "")
+			}
+			path = append(path, text)
+			buf = append(buf, line)
+			heading = true
+			flush()
+			continue
+		}
+		buf = append(buf, line)
+	}
+	flush()
+	return blocks
+}
+
+// headingPath returns the non-empty headings of path as a new slice.
+func headingPath(path []string) []string {
+	var headings []string
+	for _, heading := range path {
+		if heading != "" {
+			headings = append(headings, heading)
+		}
+	}
+	return headings
+}
+
+// chunkFence returns the fence marker when line opens a fenced code block.
+func chunkFence(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, ch := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == ch {
+			n++
+		}
+		if n >= 3 {
+			return trimmed[:n]
+		}
+	}
+	return ""
+}
+
+// chunkHeading returns the level and text of an ATX heading line, or 0.
+func chunkHeading(line string) (int, string) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return 0, ""
+	}
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ' && trimmed[level] != '\t') {
+		return 0, ""
+	}
+	text := strings.TrimSpace(trimmed[level:])
+	text = strings.TrimSpace(strings.TrimRight(text, "#"))
+	return level, text
+}
+
+// splitBlock breaks a block longer than size into pieces: code between
+// lines, each piece re-fenced, and prose between words.
+func splitBlock(block chunkBlock, size int) []chunkBlock {
+	if utf8.RuneCountInString(block.text) <= size {
+		return []chunkBlock{block}
+	}
+	if block.code {
+		return splitCodeBlock(block, size)
+	}
+
+	var pieces []chunkBlock
+	var lines []string
+	n := 0
+	emit := func() {
+		if len(lines) > 0 {
+			pieces = append(pieces, chunkBlock{text: strings.Join(lines, "\n"), headings: block.headings})
+		}
+		lines = nil
+		n = 0
+	}
+	for _, line := range strings.Split(block.text, "\n") {
+		for _, part := range splitWords(line, size) {
+			partLen := utf8.RuneCountInString(part)
+			if len(lines) > 0 && n+1+partLen > size {
+				emit()
+			}
+			if len(lines) > 0 {
+				n++
+			}
+			lines = append(lines, part)
+			n += partLen
+		}
+	}
+	emit()
+	return pieces
+}
+
+// splitWords breaks a line longer than size between words; a single word
+// longer than size is cut.
+func splitWords(line string, size int) []string {
+	if utf8.RuneCountInString(line) <= size {
+		return []string{line}
+	}
+
+	var parts []string
+	var b strings.Builder
+	n := 0
+	for _, word := range strings.Fields(line) {
+		wordLen := utf8.RuneCountInString(word)
+		if n > 0 && n+1+wordLen > size {
+			parts = append(parts, b.String())
+			b.Reset()
+			n = 0
+		}
+		for wordLen > size {
+			runes := []rune(word)
+			parts = append(parts, string(runes[:size]))
+			word = string(runes[size:])
+			wordLen -= size
+		}
+		if n > 0 {
+			b.WriteByte(' ')
+			n++
+		}
+		b.WriteString(word)
+		n += wordLen
+	}
+	if n > 0 {
+		parts = append(parts, b.String())
+	}
+	return parts
+}
+
+// splitCodeBlock breaks a fenced code block between lines, wrapping every
+// piece in the block's opening and closing fence lines.
+func splitCodeBlock(block chunkBlock, size int) []chunkBlock {
+	lines := strings.Split(block.text, "\n")
+	open := lines[0]
+	close := chunkFence(open)
+	body := lines[1:]
+	if len(body) > 0 && strings.HasPrefix(strings.TrimSpace(body[len(body)-1]), close) {
+		close = strings.TrimSpace(body[len(body)-1])
+		body = body[:len(body)-1]
+	}
+
+	// Room left for the code once the fences and their newlines are added.
+	room := size - utf8.RuneCountInString(open) - utf8.RuneCountInString(close) - 2
+	if room < 1 {
+		room = 1
+	}
+
+	var pieces []chunkBlock
+	var piece []string
+	n := 0
+	emit := func() {
+		text := open + "\n" + strings.Join(piece, "\n") + "\n" + close
+		pieces = append(pieces, chunkBlock{text: text, code: true, headings: block.headings})
+		piece = nil
+		n = 0
+	}
+	for _, line := range body {
+		lineLen := utf8.RuneCountInString(line)
+		if len(piece) > 0 && n+1+lineLen > room {
+			emit()
+		}
+		if len(piece) > 0 {
+			n++
+		}
+		piece = append(piece, line)
+		n += lineLen
+	}
+	if len(piece) > 0 || len(pieces) == 0 {
+		emit()
+	}
+	return pieces
+}
+
+// overlapBlocks returns the trailing blocks of a finished chunk to repeat
+// at the start of the next one: whole blocks totalling at most overlap
+// characters, or else the last words of a trailing prose block. The result
+// never exceeds room, so the next block still fits.
+func overlapBlocks(blocks []chunkBlock, overlap, room int) []chunkBlock {
+	if overlap > room {
+		overlap = room
+	}
+	if overlap <= 0 {
+		return nil
+	}
+
+	start := len(blocks)
+	total := 0
+	for start > 0 {
+		n := utf8.RuneCountInString(blocks[start-1].text)
+		if start < len(blocks) {
+			n += 2
+		}
+		if total+n > overlap {
+			break
+		}
+		total += n
+		start--
+	}
+	if start < len(blocks) {
+		return append([]chunkBlock(nil), blocks[start:]...)
+	}
+
+	last := blocks[len(blocks)-1]
+	if last.code {
+		return nil
+	}
+	words := strings.Fields(last.text)
+	var tail []string
+	n := 0
+	for i := len(words) - 1; i >= 0; i-- {
+		wordLen := utf8.RuneCountInString(words[i])
+		if n+wordLen+1 > overlap {
+			break
+		}
+		tail = append([]string{words[i]}, tail...)
+		n += wordLen + 1
+	}
+	if len(tail) == 0 {
+		return nil
+	}
+	return []chunkBlock{{text: strings.Join(tail, " "), headings: last.headings}}
+}
+
+func joinBlocks(blocks []chunkBlock) string {
+	texts := make([]string, len(blocks))
+	for i, block := range blocks {
+		texts[i] = block.text
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+func blocksLen(blocks []chunkBlock) int {
+	n := 0
+	for i, block := range blocks {
+		if i > 0 {
+			n += 2
+		}
+		n += utf8.RuneCountInString(block.text)
+	}
+	return n
+}
+
+// ChunkWriter collects the chunks of written documents and writes them to
+// chunks.jsonl in the output directory, one JSON record per line, when it is
+// closed. Records are ordered by document path, then document ID and chunk
+// index, so identical runs give identical files whatever order the documents
+// were added in. A later Add and Close rewrite the file with the new records
+// included. With ChunkWriterOptions.Keep, the records of documents the run
+// does not rewrite are carried over. It is safe for concurrent use.
+type ChunkWriter struct {
+	baseDir     string
+	opts        ChunkOptions
+	lineEndings LineEndings
+	keep        bool
+
+	mu      sync.Mutex
+	started bool
+	count   int
+	docs    int
+	// records holds the records of each document by document ID.
+	records map[string]chunkRecords
+
+	// kept holds the earlier records of chunks.jsonl by document ID until
+	// the document is added again or the file is closed.
+	kept map[string]chunkRecords
+}
+
+// chunkRecords is the encoded records of one document, in chunk order.
+type chunkRecords struct {
+	path  string
+	lines [][]byte
+}
+
+// ChunkWriterOptions configures a ChunkWriter.
+type ChunkWriterOptions struct {
+	BaseDir string
+	Chunk   ChunkOptions
+	// LineEndings is the line terminator of chunks.jsonl (default lf).
+	LineEndings LineEndings
+	// Keep carries over the records of documents already in chunks.jsonl
+	// that this run does not add again, such as the pages an incremental
+	// sync skips as unchanged, as long as their files still exist. Without
+	// it the file holds only the documents of this run.
+	Keep bool
+}
+
+// NewChunkWriter creates a chunk writer, or returns nil when opts.Chunk
+// disables chunking.
+func NewChunkWriter(opts ChunkWriterOptions) *ChunkWriter {
+	if opts.Chunk.Size <= 0 {
+		return nil
+	}
+	return &ChunkWriter{baseDir: opts.BaseDir, opts: opts.Chunk, lineEndings: opts.LineEndings, keep: opts.Keep}
+}
+
+// Path returns the path of chunks.jsonl.
+func (c *ChunkWriter) Path() string {
+	return filepath.Join(c.baseDir, ChunksFilename)
+}
+
+// Add splits doc, written to filePath, into chunks for chunks.jsonl. A
+// document added again replaces its earlier chunks.
+func (c *ChunkWriter) Add(doc *domain.Document, filePath string) error {
+	if c == nil || doc == nil {
+		return nil
+	}
+
+	documentID := doc.ID
+	if documentID == "" {
+		documentID = utils.DocumentID(doc.URL)
+	}
+	relPath, err := filepath.Rel(c.baseDir, filePath)
+	if err != nil {
+		relPath = filePath
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	texts := SplitChunks(doc.Content, c.opts)
+	lines := make([][]byte, 0, len(texts))
+	for i, text := range texts {
+		chars := utf8.RuneCountInString(text.Content)
+		line, err := json.Marshal(Chunk{
+			ID:              documentID + "-" + strconv.Itoa(i),
+			DocumentID:      documentID,
+			URL:             doc.URL,
+			Title:           doc.Title,
+			Path:            relPath,
+			Index:           i,
+			Count:           len(texts),
+			Headings:        text.Headings,
+			Content:         text.Content,
+			Chars:           chars,
+			EstimatedTokens: (chars + charsPerToken - 1) / charsPerToken,
+		})
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.start(); err != nil {
+		return err
+	}
+	delete(c.kept, documentID)
+	if c.records == nil {
+		c.records = make(map[string]chunkRecords)
+	}
+	c.records[documentID] = chunkRecords{path: relPath, lines: lines}
+	c.count += len(lines)
+	c.docs++
+	return nil
+}
+
+// start loads the records to keep the first time it is called.
+func (c *ChunkWriter) start() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	if c.keep {
+		return c.loadKept()
+	}
+	return nil
+}
+
+// loadKept reads the records of an existing chunks.jsonl into kept.
+// Unreadable lines are dropped.
+func (c *ChunkWriter) loadKept() error {
+	file, err := os.Open(c.Path())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	c.kept = make(map[string]chunkRecords)
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		line = bytes.TrimRight(line, "\r\n")
+		var record struct {
+			DocumentID string `json:"document_id"`
+			Path       string `json:"path"`
+		}
+		if len(line) > 0 && json.Unmarshal(line, &record) == nil && record.DocumentID != "" {
+			kept, ok := c.kept[record.DocumentID]
+			if !ok {
+				kept.path = record.Path
+			}
+			kept.lines = append(kept.lines, line)
+			c.kept[record.DocumentID] = kept
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// mergeKept moves the kept records of documents whose files still exist
+// into records and forgets the rest.
+func (c *ChunkWriter) mergeKept() {
+	for id, kept := range c.kept {
+		if _, err := os.Stat(filepath.Join(c.baseDir, filepath.FromSlash(kept.path))); err != nil {
+			continue
+		}
+		if c.records == nil {
+			c.records = make(map[string]chunkRecords)
+		}
+		c.records[id] = kept
+	}
+	c.kept = nil
+}
+
+// sortedIDs returns the document IDs of records ordered by path, then ID.
+func (c *ChunkWriter) sortedIDs() []string {
+	ids := make([]string, 0, len(c.records))
+	for id := range c.records {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := c.records[ids[i]], c.records[ids[j]]
+		if a.path != b.path {
+			return a.path < b.path
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func (c *ChunkWriter) writeLines(w *bufio.Writer, lines [][]byte) error {
+	terminator := "\n"
+	if c.lineEndings == LineEndingsCRLF {
+		terminator = "\r\n"
+	}
+	for _, line := range lines {
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+		if _, err := w.WriteString(terminator); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Count returns the number of chunks written and the number of documents
+// they came from.
+func (c *ChunkWriter) Count() (chunks, documents int) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count, c.docs
+}
+
+// Close writes chunks.jsonl with the records added so far. It does nothing
+// when no document was added, unless kept records of removed files have to
+// be dropped from an existing file.
+func (c *ChunkWriter) Close() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.started {
+		if _, err := os.Stat(c.Path()); !c.keep || err != nil {
+			return nil
+		}
+		if err := c.start(); err != nil {
+			return err
+		}
+	}
+	c.mergeKept()
+
+	file, err := os.Create(c.Path())
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(file)
+	for _, id := range c.sortedIDs() {
+		if err = c.writeLines(buf, c.records[id].lines); err != nil {
+			break
+		}
+	}
+	if flushErr := buf.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,235 @@
+package output
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChunkUnit(t *testing.T) {
+	for input, want := range map[string]ChunkUnit{
+		"":       ChunkUnitChars,
+		"chars":  ChunkUnitChars,
+		"Tokens": ChunkUnitTokens,
+	} {
+		got, err := ParseChunkUnit(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseChunkUnit("words")
+	assert.Error(t, err)
+}
+
+func TestSplitChunks_HeadingContext(t *testing.T) {
+	markdown := "Intro text.\n\n# Guide\n\nFirst paragraph of the guide.\n\n## Install\n\nRun the installer now.\n\n## Usage\n\nCall it."
+	chunks := SplitChunks(markdown, ChunkOptions{Size: 50})
+
+	require.Len(t, chunks, 4)
+	assert.Nil(t, chunks[0].Headings)
+	assert.Equal(t, "Intro text.", chunks[0].Content)
+	assert.Equal(t, []string{"Guide"}, chunks[1].Headings)
+	assert.Equal(t, "# Guide\n\nFirst paragraph of the guide.", chunks[1].Content, "headings move to the chunk they introduce")
+	assert.Equal(t, []string{"Guide", "Install"}, chunks[2].Headings)
+	assert.Equal(t, []string{"Guide", "Usage"}, chunks[3].Headings)
+	assert.Equal(t, "## Usage\n\nCall it.", chunks[3].Content)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk.Content), 50)
+	}
+}
+
+func TestSplitChunks_Overlap(t *testing.T) {
+	markdown := "Alpha one.\n\nBravo two.\n\nCharlie three.\n\nDelta four."
+	chunks := SplitChunks(markdown, ChunkOptions{Size: 30, Overlap: 14})
+
+	require.Len(t, chunks, 3)
+	assert.Equal(t, "Alpha one.\n\nBravo two.", chunks[0].Content)
+	assert.Equal(t, "Bravo two.\n\nCharlie three.", chunks[1].Content, "the last paragraph is repeated")
+	assert.Equal(t, "Charlie three.\n\nDelta four.", chunks[2].Content)
+}
+
+func TestSplitChunks_Tokens(t *testing.T) {
+	markdown := strings.Repeat("word ", 60)
+	chars := SplitChunks(markdown, ChunkOptions{Size: 100})
+	tokens := SplitChunks(markdown, ChunkOptions{Size: 25, Unit: ChunkUnitTokens})
+
+	assert.Equal(t, chars, tokens, "a token is estimated at four characters")
+	for _, chunk := range tokens {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk.Content), 100)
+	}
+}
+
+func TestSplitChunks_CodeFences(t *testing.T) {
+	var code strings.Builder
+	code.WriteString("```go\n")
+	for i := 0; i < 20; i++ {
+		code.WriteString("fmt.Println(\"line\")\n\n")
+	}
+	code.WriteString("```")
+	markdown := "# API\n\n" + code.String() + "\n\nAfter the code."
+
+	chunks := SplitChunks(markdown, ChunkOptions{Size: 120, Overlap: 20})
+	require.Greater(t, len(chunks), 2)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk.Content), 120)
+		assert.Zero(t, strings.Count(chunk.Content, "```")%2, "fences are balanced: %q", chunk.Content)
+		if strings.Contains(chunk.Content, "fmt.Println") {
+			assert.Contains(t, chunk.Content, "```go\n", "split code keeps its language")
+		}
+		assert.Equal(t, []string{"API"}, chunk.Headings)
+	}
+}
+
+func TestSplitChunks_LongParagraph(t *testing.T) {
+	markdown := strings.Repeat("lorem ipsum ", 30) + "\n\n- item one\n- item two"
+	chunks := SplitChunks(markdown, ChunkOptions{Size: 64})
+
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk.Content), 64)
+	}
+	assert.Contains(t, chunks[len(chunks)-1].Content, "- item one\n- item two", "lines are kept")
+}
+
+func TestChunkWriter(t *testing.T) {
+	assert.Nil(t, NewChunkWriter(ChunkWriterOptions{}))
+	var disabled *ChunkWriter
+	assert.NoError(t, disabled.Add(&domain.Document{}, ""))
+	assert.NoError(t, disabled.Close())
+
+	dir := t.TempDir()
+	chunks := NewChunkWriter(ChunkWriterOptions{BaseDir: dir, Chunk: ChunkOptions{Size: 40}})
+	w := NewWriter(WriterOptions{BaseDir: dir, Chunks: chunks})
+	ctx := context.Background()
+
+	doc := &domain.Document{
+		URL:     "https://example.com/docs/guide",
+		Title:   "Guide",
+		Content: "# Guide\n\nThe first paragraph.\n\nThe second paragraph.",
+	}
+	require.NoError(t, w.Write(ctx, doc))
+	require.NoError(t, w.Write(ctx, &domain.Document{URL: "https://example.com/raw", Content: "raw", IsRawFile: true, RelativePath: "raw.txt"}))
+	require.NoError(t, chunks.Close())
+
+	records := readChunks(t, filepath.Join(dir, ChunksFilename))
+	require.Len(t, records, 2, "raw files are not chunked")
+	id := utils.DocumentID(doc.URL)
+	for i, record := range records {
+		assert.Equal(t, id, record.DocumentID)
+		assert.Equal(t, id+"-"+strconv.Itoa(i), record.ID)
+		assert.Equal(t, i, record.Index)
+		assert.Equal(t, 2, record.Count)
+		assert.Equal(t, "docs/guide.md", record.Path)
+		assert.Equal(t, []string{"Guide"}, record.Headings)
+		assert.Equal(t, utf8.RuneCountInString(record.Content), record.Chars)
+	}
+
+	chunkCount, documents := chunks.Count()
+	assert.Equal(t, 2, chunkCount)
+	assert.Equal(t, 1, documents)
+
+	// Writing after Close adds to the file.
+	require.NoError(t, w.Write(ctx, &domain.Document{ID: "other", URL: "https://example.com/other", Content: "Other."}))
+	require.NoError(t, chunks.Close())
+	records = readChunks(t, filepath.Join(dir, ChunksFilename))
+	require.Len(t, records, 3)
+	assert.Equal(t, "other-0", records[2].ID)
+}
+
+func TestChunkWriter_Order(t *testing.T) {
+	write := func(docs ...*domain.Document) string {
+		dir := t.TempDir()
+		chunks := NewChunkWriter(ChunkWriterOptions{BaseDir: dir, Chunk: ChunkOptions{Size: 20}})
+		for _, doc := range docs {
+			require.NoError(t, chunks.Add(doc, filepath.Join(dir, doc.ID+".md")))
+		}
+		require.NoError(t, chunks.Close())
+		return filepath.Join(dir, ChunksFilename)
+	}
+	a := &domain.Document{ID: "a", URL: "https://example.com/a", Content: "First paragraph.\n\nSecond paragraph."}
+	b := &domain.Document{ID: "b", URL: "https://example.com/b", Content: "Only paragraph."}
+	c := &domain.Document{ID: "c", URL: "https://example.com/c", Content: "Only paragraph."}
+
+	inOrder, err := os.ReadFile(write(a, b, c))
+	require.NoError(t, err)
+	shuffledPath := write(c, a, b)
+	shuffled, err := os.ReadFile(shuffledPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(inOrder), string(shuffled), "the add order does not change the file")
+
+	var ids []string
+	for _, record := range readChunks(t, shuffledPath) {
+		ids = append(ids, record.ID)
+	}
+	assert.Equal(t, []string{"a-0", "a-1", "b-0", "c-0"}, ids)
+}
+
+func TestChunkWriter_Keep(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	guide := &domain.Document{URL: "https://example.com/guide", Content: "# Guide\n\nGuide text."}
+	faq := &domain.Document{URL: "https://example.com/faq", Content: "# FAQ\n\nFAQ text."}
+	removed := &domain.Document{URL: "https://example.com/removed", Content: "# Removed\n\nGone."}
+
+	chunks := NewChunkWriter(ChunkWriterOptions{BaseDir: dir, Chunk: ChunkOptions{Size: 1000}, Keep: true})
+	w := NewWriter(WriterOptions{BaseDir: dir, Chunks: chunks})
+	for _, doc := range []*domain.Document{guide, faq, removed} {
+		require.NoError(t, w.Write(ctx, doc))
+	}
+	require.NoError(t, chunks.Close())
+	require.Len(t, readChunks(t, filepath.Join(dir, ChunksFilename)), 3)
+
+	// A later run skips the existing guide, rewrites the FAQ and no longer
+	// has the removed page.
+	require.NoError(t, os.Remove(w.GetPath(removed.URL)))
+	chunks = NewChunkWriter(ChunkWriterOptions{BaseDir: dir, Chunk: ChunkOptions{Size: 1000}, Keep: true})
+	w = NewWriter(WriterOptions{BaseDir: dir, Chunks: chunks})
+	require.NoError(t, w.Write(ctx, guide))
+	faq.Content = "# FAQ\n\nNew FAQ text."
+	require.NoError(t, w.Write(WithForce(ctx, true), faq))
+	require.NoError(t, chunks.Close())
+
+	records := readChunks(t, filepath.Join(dir, ChunksFilename))
+	require.Len(t, records, 2)
+	assert.Equal(t, utils.DocumentID(faq.URL), records[0].DocumentID)
+	assert.Contains(t, records[0].Content, "New FAQ text.")
+	assert.Equal(t, utils.DocumentID(guide.URL), records[1].DocumentID, "skipped documents keep their chunks")
+	chunkCount, documents := chunks.Count()
+	assert.Equal(t, 1, chunkCount)
+	assert.Equal(t, 1, documents)
+
+	// A run that adds nothing still drops the chunks of removed files.
+	require.NoError(t, os.Remove(w.GetPath(faq.URL)))
+	chunks = NewChunkWriter(ChunkWriterOptions{BaseDir: dir, Chunk: ChunkOptions{Size: 1000}, Keep: true})
+	require.NoError(t, chunks.Close())
+	records = readChunks(t, filepath.Join(dir, ChunksFilename))
+	require.Len(t, records, 1)
+	assert.Equal(t, utils.DocumentID(guide.URL), records[0].DocumentID)
+}
+
+func readChunks(t *testing.T, path string) []Chunk {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var records []Chunk
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Chunk
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -19,6 +20,9 @@ type MetadataCollector struct {
 	baseDir   string
 	filename  string
 	enabled   bool
+	now       func() time.Time
+
+	lineEndings LineEndings
 }
 
 // CollectorOptions configures metadata collection output, source context, and enablement.
@@ -28,6 +32,8 @@ type CollectorOptions struct {
 	SourceURL string
 	Strategy  string
 	Enabled   bool
+	// LineEndings is the line terminator of the written index (default lf).
+	LineEndings LineEndings
 }
 
 // NewMetadataCollector creates a metadata collector with the supplied options.
@@ -43,6 +49,9 @@ func NewMetadataCollector(opts CollectorOptions) *MetadataCollector {
 		baseDir:   opts.BaseDir,
 		filename:  filename,
 		enabled:   opts.Enabled,
+		now:       time.Now,
+
+		lineEndings: opts.LineEndings,
 	}
 }
 
@@ -84,18 +93,21 @@ func (c *MetadataCollector) Flush() error {
 	}
 
 	outputPath := filepath.Join(c.baseDir, c.filename)
-	return os.WriteFile(outputPath, data, 0644)
+	return os.WriteFile(outputPath, []byte(NormalizeText(string(data), c.lineEndings)), 0644)
 }
 
+// buildIndex orders documents by file path so the index does not depend on
+// the order concurrent workers finished in.
 func (c *MetadataCollector) buildIndex() *domain.SimpleMetadataIndex {
 	docs := make([]domain.SimpleDocumentMetadata, len(c.documents))
 
 	for i, doc := range c.documents {
 		docs[i] = *doc
 	}
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].FilePath < docs[j].FilePath })
 
 	return &domain.SimpleMetadataIndex{
-		GeneratedAt:    time.Now(),
+		GeneratedAt:    c.now(),
 		SourceURL:      c.sourceURL,
 		Strategy:       c.strategy,
 		TotalDocuments: len(c.documents),
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -530,3 +531,52 @@ func TestMetadataCollector_Integration(t *testing.T) {
 		assert.Contains(t, titles, "API Overview")
 	})
 }
+
+// TestMetadataCollector_DeterministicOutput tests that two runs adding the same
+// documents in different orders write byte-identical indexes
+func TestMetadataCollector_DeterministicOutput(t *testing.T) {
+	fetchedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	pages := []string{"guide/intro", "api/overview", "guide/advanced", "index"}
+
+	run := func(order []int) []byte {
+		tmpDir := t.TempDir()
+		c := NewMetadataCollector(CollectorOptions{
+			BaseDir:   tmpDir,
+			SourceURL: "https://docs.example.com",
+			Strategy:  "crawler",
+			Enabled:   true,
+		})
+		c.now = func() time.Time { return fetchedAt }
+
+		var wg sync.WaitGroup
+		for _, i := range order {
+			wg.Add(1)
+			go func(page string) {
+				defer wg.Done()
+				c.Add(&domain.Document{
+					URL:       "https://docs.example.com/" + page,
+					Title:     page,
+					FetchedAt: fetchedAt,
+				}, filepath.Join(tmpDir, page+".md"))
+			}(pages[i])
+		}
+		wg.Wait()
+		require.NoError(t, c.Flush())
+
+		data, err := os.ReadFile(filepath.Join(tmpDir, "metadata.json"))
+		require.NoError(t, err)
+		return data
+	}
+
+	first := run([]int{0, 1, 2, 3})
+	second := run([]int{3, 2, 1, 0})
+	assert.Equal(t, string(first), string(second))
+
+	var index domain.SimpleMetadataIndex
+	require.NoError(t, json.Unmarshal(first, &index))
+	paths := make([]string, len(index.Documents))
+	for i, doc := range index.Documents {
+		paths[i] = doc.FilePath
+	}
+	assert.Equal(t, []string{"api/overview.md", "guide/advanced.md", "guide/intro.md", "index.md"}, paths)
+}
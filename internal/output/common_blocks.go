@@ -0,0 +1,176 @@
+package output
+
+import (
+	"crypto/sha256"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultCommonThreshold is the fraction of a site's pages a block must
+// appear on before it is treated as shared boilerplate.
+const DefaultCommonThreshold = 0.8
+
+// minCommonBlockPages is the smallest page set worth analysing; with fewer
+// pages there is no way to tell boilerplate from genuine content.
+const minCommonBlockPages = 3
+
+// WrittenFile records a markdown document written during a run.
+type WrittenFile struct {
+	Path string
+	URL  string
+}
+
+// StripCommonBlocks removes blocks (header, footer, sidebar text) that repeat
+// across a high fraction of the written pages of each host. Blocks are compared
+// by a hash of their whitespace-normalized text. Headings and fenced code
+// blocks are never removed, and the YAML frontmatter of each file is kept.
+// It returns the number of files rewritten.
+func StripCommonBlocks(files []WrittenFile, threshold float64) (int, error) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultCommonThreshold
+	}
+
+	groups := make(map[string][]WrittenFile)
+	var hosts []string
+	for _, f := range files {
+		host := ""
+		if u, err := url.Parse(f.URL); err == nil {
+			host = strings.ToLower(u.Host)
+		}
+		if _, ok := groups[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		groups[host] = append(groups[host], f)
+	}
+
+	rewritten := 0
+	for _, host := range hosts {
+		n, err := stripCommonBlocksInGroup(groups[host], threshold)
+		rewritten += n
+		if err != nil {
+			return rewritten, err
+		}
+	}
+	return rewritten, nil
+}
+
+type markdownPage struct {
+	path        string
+	frontmatter string
+	blocks      []string
+	trailingNL  bool
+	crlf        bool
+}
+
+func stripCommonBlocksInGroup(files []WrittenFile, threshold float64) (int, error) {
+	if len(files) < minCommonBlockPages {
+		return 0, nil
+	}
+
+	pages := make([]*markdownPage, 0, len(files))
+	counts := make(map[[32]byte]int)
+	for _, f := range files {
+		data, crlf, err := readTextFile(f.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return 0, err
+		}
+
+		page := parseMarkdownPage(f.Path, data)
+		page.crlf = crlf
+		pages = append(pages, page)
+
+		seen := make(map[[32]byte]bool)
+		for _, block := range page.blocks {
+			key, ok := commonBlockKey(block)
+			if !ok || seen[key] {
+				continue
+			}
+			seen[key] = true
+			counts[key]++
+		}
+	}
+
+	if len(pages) < minCommonBlockPages {
+		return 0, nil
+	}
+
+	minCount := int(math.Ceil(threshold * float64(len(pages))))
+	if minCount < 2 {
+		minCount = 2
+	}
+
+	rewritten := 0
+	for _, page := range pages {
+		kept := make([]string, 0, len(page.blocks))
+		for _, block := range page.blocks {
+			if key, ok := commonBlockKey(block); ok && counts[key] >= minCount {
+				continue
+			}
+			kept = append(kept, block)
+		}
+		if len(kept) == len(page.blocks) {
+			continue
+		}
+
+		content := page.frontmatter + strings.Join(kept, "\n\n")
+		if page.trailingNL {
+			content += "\n"
+		}
+		if err := writeTextFile(page.path, content, page.crlf); err != nil {
+			return rewritten, err
+		}
+		rewritten++
+	}
+
+	return rewritten, nil
+}
+
+// parseMarkdownPage splits a written document into its frontmatter and
+// blank-line separated body blocks. Fenced code blocks stay in one block even
+// when they contain blank lines.
+func parseMarkdownPage(path, content string) *markdownPage {
+	page := &markdownPage{path: path, trailingNL: strings.HasSuffix(content, "\n")}
+
+	var body string
+	page.frontmatter, body = splitFrontmatter(content)
+
+	var current []string
+	inFence := false
+	flush := func() {
+		if len(current) > 0 {
+			page.blocks = append(page.blocks, strings.Join(current, "\n"))
+			current = nil
+		}
+	}
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if trimmed == "" && !inFence {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return page
+}
+
+// commonBlockKey hashes the normalized text of a block. Headings and code
+// blocks are not candidates for removal.
+func commonBlockKey(block string) ([32]byte, bool) {
+	trimmed := strings.TrimSpace(block)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") ||
+		strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		return [32]byte{}, false
+	}
+	normalized := strings.ToLower(strings.Join(strings.Fields(trimmed), " "))
+	return sha256.Sum256([]byte(normalized)), true
+}
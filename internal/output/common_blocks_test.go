@@ -0,0 +1,94 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCommonBlockPages(t *testing.T, w *Writer, host string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		doc := &domain.Document{
+			URL:   fmt.Sprintf("https://%s/page%d", host, i),
+			Title: fmt.Sprintf("Page %d", i),
+			Content: fmt.Sprintf("Home | Guides | API Reference\n\n# Page %d\n\nUnique body for page %d.\n\n"+
+				"```sh\nrepodocs --help\n```\n\n## See also\n\n© 2024 Example Corp. All rights reserved.", i, i),
+		}
+		require.NoError(t, w.Write(context.Background(), doc))
+	}
+}
+
+func TestStripCommonBlocks(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir})
+	writeCommonBlockPages(t, w, "docs.example.com", 4)
+
+	written := w.TakeWritten()
+	require.Len(t, written, 4)
+	assert.Empty(t, w.TakeWritten())
+
+	rewritten, err := StripCommonBlocks(written, 0.8)
+	require.NoError(t, err)
+	assert.Equal(t, 4, rewritten)
+
+	for i, f := range written {
+		data, err := os.ReadFile(f.Path)
+		require.NoError(t, err)
+		content := string(data)
+
+		assert.NotContains(t, content, "Home | Guides")
+		assert.NotContains(t, content, "All rights reserved")
+		assert.Contains(t, content, fmt.Sprintf("Unique body for page %d.", i))
+		assert.Contains(t, content, "repodocs --help", "code blocks are never stripped")
+		assert.Contains(t, content, "## See also", "headings are never stripped")
+		assert.Contains(t, content, "title: Page", "frontmatter is preserved")
+	}
+}
+
+func TestStripCommonBlocks_GroupsByHost(t *testing.T) {
+	a := NewWriter(WriterOptions{BaseDir: t.TempDir()})
+	b := NewWriter(WriterOptions{BaseDir: t.TempDir()})
+	writeCommonBlockPages(t, a, "a.example.com", 3)
+	writeCommonBlockPages(t, b, "b.example.com", 2)
+
+	written := append(a.TakeWritten(), b.TakeWritten()...)
+	require.Len(t, written, 5)
+	rewritten, err := StripCommonBlocks(written, 0.8)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rewritten, "hosts with too few pages are left alone")
+
+	data, err := os.ReadFile(written[len(written)-1].Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "All rights reserved")
+}
+
+func TestStripCommonBlocks_BelowThreshold(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir})
+	writeCommonBlockPages(t, w, "docs.example.com", 3)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, w.Write(context.Background(), &domain.Document{
+			URL:     fmt.Sprintf("https://docs.example.com/other%d", i),
+			Content: "Different layout entirely.",
+		}))
+	}
+
+	rewritten, err := StripCommonBlocks(w.TakeWritten(), 0.8)
+	require.NoError(t, err)
+	assert.Equal(t, 0, rewritten, "3 of 5 pages is below an 80% threshold")
+}
+
+func TestParseMarkdownPage(t *testing.T) {
+	content := "---\ntitle: Test\n---\n\nIntro\n\n```go\nfunc a() {}\n\nfunc b() {}\n```\n\nOutro\n"
+	page := parseMarkdownPage("x.md", content)
+
+	assert.Equal(t, "---\ntitle: Test\n---\n\n", page.frontmatter)
+	assert.Equal(t, []string{"Intro", "```go\nfunc a() {}\n\nfunc b() {}\n```", "Outro"}, page.blocks)
+	assert.True(t, page.trailingNL)
+}
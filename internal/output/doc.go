@@ -1,6 +1,9 @@
 // Package output provides Markdown file writing and metadata collection.
 //
 // Writer persists extracted documents to the selected output directory with
-// stable paths and front matter. MetadataCollector gathers document metadata and
-// builds the index consumed by later tooling and readers.
+// stable paths and front matter. Each document is written to its own file as
+// soon as it is produced, so memory use does not grow with the size of a
+// crawl; only per-file bookkeeping (paths and metadata) is kept until the run
+// ends. MetadataCollector gathers document metadata and builds the index
+// consumed by later tooling and readers.
 package output
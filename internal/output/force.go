@@ -0,0 +1,22 @@
+package output
+
+import "context"
+
+// forceKey is the context key of the overwrite flag attached by WithForce.
+type forceKey struct{}
+
+// WithForce returns a context whose documents overwrite existing files when
+// force is set, whatever the writer was created with. Manifest sources share
+// one Writer, so a source's force setting travels with its context.
+func WithForce(ctx context.Context, force bool) context.Context {
+	return context.WithValue(ctx, forceKey{}, force)
+}
+
+// forceFor reports whether documents written with ctx overwrite existing
+// files.
+func (w *Writer) forceFor(ctx context.Context) bool {
+	if force, ok := ctx.Value(forceKey{}).(bool); ok {
+		return force
+	}
+	return w.force
+}
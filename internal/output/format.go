@@ -0,0 +1,46 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format selects how documents are written.
+type Format string
+
+const (
+	// FormatMarkdown writes markdown files with YAML frontmatter (the
+	// default).
+	FormatMarkdown Format = "markdown"
+	// FormatText writes plain-text .txt files with the markdown syntax
+	// stripped and no frontmatter.
+	FormatText Format = "text"
+)
+
+// ParseFormat parses an output format. Empty means markdown.
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return FormatMarkdown, nil
+	case FormatMarkdown, FormatText:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid output format %q (use markdown or text)", value)
+	}
+}
+
+// documentPath returns the path a converted document is written to in
+// format: markdown paths are kept and text paths get a .txt extension in
+// place of .md or .mdx.
+func (f Format) documentPath(path string) string {
+	if f != FormatText {
+		return path
+	}
+	switch ext := filepath.Ext(path); strings.ToLower(ext) {
+	case ".md", ".mdx":
+		return strings.TrimSuffix(path, ext) + ".txt"
+	default:
+		return path
+	}
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{
+		"":         FormatMarkdown,
+		"markdown": FormatMarkdown,
+		"Text":     FormatText,
+	} {
+		got, err := ParseFormat(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseFormat("html")
+	assert.Error(t, err)
+}
+
+func TestWriter_Write_TextFormat(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir, Format: FormatText})
+	ctx := context.Background()
+
+	doc := &domain.Document{
+		URL:     "https://example.com/docs/intro",
+		Title:   "Intro",
+		Content: "# Intro\n\nSee the [guide](https://example.com/guide).\n\n```go\nfmt.Println(\"hi\")\n```\n",
+	}
+	require.NoError(t, w.Write(ctx, doc))
+
+	path := w.GetPath(doc.URL)
+	assert.Equal(t, ".txt", filepath.Ext(path))
+	assert.True(t, w.Exists(doc.URL))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Intro\n\nSee the guide.\n\n    fmt.Println(\"hi\")\n", string(data), "no frontmatter or markdown syntax")
+
+	raw := &domain.Document{URL: "https://example.com/raw", Content: "# kept", IsRawFile: true, RelativePath: "notes.md"}
+	require.NoError(t, w.Write(ctx, raw))
+	data, err = os.ReadFile(filepath.Join(dir, "notes.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# kept", string(data), "raw files are written unchanged")
+}
+
+func TestWriter_Write_TextFormatNamed(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir, Format: FormatText, OutputName: "hooks"})
+
+	require.NoError(t, w.Write(context.Background(), &domain.Document{URL: "https://example.com/hooks", Content: "**Hooks**"}))
+
+	assert.FileExists(t, filepath.Join(dir, "hooks.txt"))
+	assert.Equal(t, filepath.Join(dir, "hooks.txt"), w.GetPath("https://example.com/hooks"))
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LineEndings selects the line terminator of written text files.
+type LineEndings string
+
+const (
+	// LineEndingsLF writes "\n" line endings (the default).
+	LineEndingsLF LineEndings = "lf"
+	// LineEndingsCRLF writes "\r\n" line endings.
+	LineEndingsCRLF LineEndings = "crlf"
+	// LineEndingsPreserve keeps line endings as produced by the source.
+	LineEndingsPreserve LineEndings = "preserve"
+)
+
+// utf8BOM is the byte order mark some editors put at the start of UTF-8 files.
+const utf8BOM = "\uFEFF"
+
+// ParseLineEndings parses a line-ending mode. Empty means lf.
+func ParseLineEndings(value string) (LineEndings, error) {
+	switch mode := LineEndings(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return LineEndingsLF, nil
+	case LineEndingsLF, LineEndingsCRLF, LineEndingsPreserve:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid line endings %q (use lf, crlf or preserve)", value)
+	}
+}
+
+// NormalizeText returns content as valid UTF-8 without a byte order mark and
+// with line endings converted to mode. Invalid UTF-8 sequences become U+FFFD.
+func NormalizeText(content string, mode LineEndings) string {
+	content = strings.TrimPrefix(strings.ToValidUTF8(content, "\uFFFD"), utf8BOM)
+	switch mode {
+	case LineEndingsPreserve:
+		return content
+	case LineEndingsCRLF:
+		return strings.ReplaceAll(toLF(content), "\n", "\r\n")
+	default:
+		return toLF(content)
+	}
+}
+
+// toLF converts CRLF line endings to LF.
+func toLF(content string) string {
+	return strings.ReplaceAll(content, "\r\n", "\n")
+}
+
+// readTextFile reads a written file as LF text for post-processing and
+// reports whether it used CRLF line endings, so writeTextFile can restore them.
+func readTextFile(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, err
+	}
+	content := string(data)
+	return toLF(content), strings.Contains(content, "\r\n"), nil
+}
+
+// writeTextFile writes LF content read by readTextFile, restoring CRLF line
+// endings when the file had them.
+func writeTextFile(path, content string, crlf bool) error {
+	mode := LineEndingsLF
+	if crlf {
+		mode = LineEndingsCRLF
+	}
+	return os.WriteFile(path, []byte(NormalizeText(content, mode)), 0644)
+}
@@ -0,0 +1,103 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLineEndings(t *testing.T) {
+	for input, want := range map[string]LineEndings{
+		"":         LineEndingsLF,
+		"lf":       LineEndingsLF,
+		"CRLF":     LineEndingsCRLF,
+		"preserve": LineEndingsPreserve,
+	} {
+		got, err := ParseLineEndings(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseLineEndings("cr")
+	assert.Error(t, err)
+}
+
+func TestNormalizeText(t *testing.T) {
+	mixed := "\uFEFFa\r\nb\nc\xff\r\n"
+
+	assert.Equal(t, "a\nb\nc\uFFFD\n", NormalizeText(mixed, LineEndingsLF))
+	assert.Equal(t, "a\nb\nc\uFFFD\n", NormalizeText(mixed, ""), "empty mode means lf")
+	assert.Equal(t, "a\r\nb\r\nc\uFFFD\r\n", NormalizeText(mixed, LineEndingsCRLF))
+	assert.Equal(t, "a\r\nb\nc\uFFFD\r\n", NormalizeText(mixed, LineEndingsPreserve))
+}
+
+func TestWriter_Write_LineEndings(t *testing.T) {
+	doc := &domain.Document{
+		URL:     "https://example.com/page",
+		Title:   "Page",
+		Content: "\uFEFF# Page\r\n\r\nWindows text.\nUnix text.\n",
+	}
+
+	t.Run("lf", func(t *testing.T) {
+		dir := t.TempDir()
+		w := NewWriter(WriterOptions{BaseDir: dir, Force: true})
+		require.NoError(t, w.Write(context.Background(), doc))
+
+		data, err := os.ReadFile(w.GetPath(doc.URL))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "\r")
+		assert.NotContains(t, string(data), "\uFEFF")
+		assert.Contains(t, string(data), "# Page\n\nWindows text.\nUnix text.\n")
+	})
+
+	t.Run("crlf", func(t *testing.T) {
+		dir := t.TempDir()
+		collector := NewMetadataCollector(CollectorOptions{BaseDir: dir, Enabled: true, LineEndings: LineEndingsCRLF})
+		w := NewWriter(WriterOptions{
+			BaseDir:      dir,
+			Force:        true,
+			JSONMetadata: true,
+			Collector:    collector,
+			LineEndings:  LineEndingsCRLF,
+		})
+		require.NoError(t, w.Write(context.Background(), doc))
+		require.NoError(t, collector.Flush())
+
+		for _, path := range []string{w.GetPath(doc.URL), filepath.Join(dir, "metadata.json")} {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			content := string(data)
+			assert.NotContains(t, content, "\uFEFF", path)
+			assert.Equal(t, strings.Count(content, "\n"), strings.Count(content, "\r\n"), "%s has bare LF", path)
+		}
+	})
+}
+
+func TestRewriteLinks_CRLF(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir, Flat: true, LineEndings: LineEndingsCRLF})
+
+	require.NoError(t, w.Write(context.Background(), &domain.Document{
+		URL: "https://docs.example.com/a", Title: "A", Content: "# A\n\nSee [B](https://docs.example.com/b).\n",
+	}))
+	require.NoError(t, w.Write(context.Background(), &domain.Document{
+		URL: "https://docs.example.com/b", Title: "B", Content: "# B\n",
+	}))
+
+	rewritten, err := RewriteLinks(w.TakeWritten())
+	require.NoError(t, err)
+	assert.Equal(t, 1, rewritten)
+
+	data, err := os.ReadFile(w.GetPath("https://docs.example.com/a"))
+	require.NoError(t, err)
+	content := string(data)
+	assert.True(t, strings.HasPrefix(content, "---\r\n"), "frontmatter is kept")
+	assert.Contains(t, content, "[B]("+filepath.Base(w.GetPath("https://docs.example.com/b"))+")")
+	assert.Equal(t, strings.Count(content, "\n"), strings.Count(content, "\r\n"), "CRLF line endings are kept")
+}
@@ -0,0 +1,120 @@
+package output
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/quantmind-br/repodocs/internal/converter"
+)
+
+// RewriteLinks makes the written documents navigable offline: links between
+// them are rewritten from absolute page URLs to relative paths of the local
+// files, keeping any #fragment. Links to pages that were not written in this
+// run, and links to other sites, are left as they are. Frontmatter is never
+// changed. It returns the number of files rewritten.
+func RewriteLinks(files []WrittenFile) (int, error) {
+	paths := make(map[string]string, len(files))
+	for _, f := range files {
+		if key := pageKey(f.URL); key != "" {
+			paths[key] = f.Path
+		}
+	}
+
+	rewritten := 0
+	for _, f := range files {
+		base, err := url.Parse(f.URL)
+		if err != nil || base.Host == "" {
+			continue
+		}
+
+		data, crlf, err := readTextFile(f.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return rewritten, err
+		}
+		frontmatter, body := splitFrontmatter(data)
+
+		updated := converter.RewriteLinkTargets(body, func(target string) string {
+			return localLink(base, f.Path, target, paths)
+		})
+		if updated == body {
+			continue
+		}
+		if err := writeTextFile(f.Path, frontmatter+updated, crlf); err != nil {
+			return rewritten, err
+		}
+		rewritten++
+	}
+	return rewritten, nil
+}
+
+// localLink returns the relative path from the file at fromPath to the local
+// copy of target, or target unchanged when it is not a written page.
+func localLink(base *url.URL, fromPath, target string, paths map[string]string) string {
+	if target == "" || strings.HasPrefix(target, "#") {
+		return target
+	}
+	ref, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	abs := base.ResolveReference(ref)
+	if !strings.EqualFold(abs.Host, base.Host) {
+		return target
+	}
+
+	destPath, ok := paths[pageKey(abs.String())]
+	if !ok {
+		return target
+	}
+	rel, err := filepath.Rel(filepath.Dir(fromPath), destPath)
+	if err != nil {
+		return target
+	}
+
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	link := strings.Join(segments, "/")
+	if abs.Fragment != "" {
+		link += "#" + abs.EscapedFragment()
+	}
+	return link
+}
+
+// pageKey normalizes a page URL for matching links against written pages:
+// the scheme and fragment are ignored, the host is lowercased and trailing
+// slashes are trimmed.
+func pageKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	key := strings.ToLower(u.Host) + strings.TrimRight(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
+
+// splitFrontmatter separates a leading YAML frontmatter block, including the
+// blank lines after it, from the document body.
+func splitFrontmatter(content string) (string, string) {
+	if !strings.HasPrefix(content, "---\n") {
+		return "", content
+	}
+	end := strings.Index(content[4:], "\n---\n")
+	if end < 0 {
+		return "", content
+	}
+	cut := 4 + end + len("\n---\n")
+	for cut < len(content) && content[cut] == '\n' {
+		cut++
+	}
+	return content[:cut], content[cut:]
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteLinks(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir})
+
+	docs := []*domain.Document{
+		{
+			URL:   "https://docs.example.com/guide/intro",
+			Title: "Intro",
+			Content: "# Intro\n\nRead [setup](https://docs.example.com/guide/setup/#install), " +
+				"the [API](../api) and [GitHub](https://github.com/example/repo).\n\n" +
+				"Also [unwritten](https://docs.example.com/blog) and [self](#top).\n\n" +
+				"```md\n[setup](https://docs.example.com/guide/setup)\n```\n",
+		},
+		{URL: "https://docs.example.com/guide/setup", Title: "Setup", Content: "# Setup\n\nBack to [intro](/guide/intro)."},
+		{URL: "https://docs.example.com/api", Title: "API", Content: "# API\n\nNo links here."},
+	}
+	for _, doc := range docs {
+		require.NoError(t, w.Write(context.Background(), doc))
+	}
+	written := w.TakeWritten()
+
+	rewritten, err := RewriteLinks(written)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rewritten)
+
+	read := func(url string) string {
+		for _, f := range written {
+			if f.URL == url {
+				data, err := os.ReadFile(f.Path)
+				require.NoError(t, err)
+				return string(data)
+			}
+		}
+		t.Fatalf("%s not written", url)
+		return ""
+	}
+
+	intro := read("https://docs.example.com/guide/intro")
+	setupRel, err := filepath.Rel(filepath.Dir(w.GetPath("https://docs.example.com/guide/intro")), w.GetPath("https://docs.example.com/guide/setup"))
+	require.NoError(t, err)
+	apiRel, err := filepath.Rel(filepath.Dir(w.GetPath("https://docs.example.com/guide/intro")), w.GetPath("https://docs.example.com/api"))
+	require.NoError(t, err)
+
+	assert.Contains(t, intro, "[setup]("+filepath.ToSlash(setupRel)+"#install)")
+	assert.Contains(t, intro, "[API]("+filepath.ToSlash(apiRel)+")")
+	assert.Contains(t, intro, "[GitHub](https://github.com/example/repo)", "external links stay absolute")
+	assert.Contains(t, intro, "[unwritten](https://docs.example.com/blog)")
+	assert.Contains(t, intro, "[self](#top)")
+	assert.Contains(t, intro, "```md\n[setup](https://docs.example.com/guide/setup)\n```", "code is untouched")
+	assert.True(t, strings.HasPrefix(intro, "---\n"), "frontmatter is kept")
+	assert.Contains(t, intro, "url: https://docs.example.com/guide/intro")
+
+	setup := read("https://docs.example.com/guide/setup")
+	assert.NotContains(t, setup, "(/guide/intro)")
+	assert.Contains(t, setup, ".md)")
+}
@@ -0,0 +1,147 @@
+package output
+
+import (
+	"encoding/xml"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// SitemapFilename is the name of the sitemap written to the output directory.
+const SitemapFilename = "sitemap.xml"
+
+// SitemapBuilder records written documents and renders them as a sitemap.xml
+// whose URLs are the documents' output paths under a public base URL.
+type SitemapBuilder struct {
+	mu      sync.Mutex
+	baseDir string
+	baseURL string
+	entries map[string]time.Time // loc -> lastmod
+
+	lineEndings LineEndings
+}
+
+// SitemapOptions configures where the sitemap is written and the URL the
+// output directory is published under.
+type SitemapOptions struct {
+	BaseDir string
+	BaseURL string
+	// LineEndings is the line terminator of sitemap.xml (default lf).
+	LineEndings LineEndings
+}
+
+// NewSitemapBuilder creates a sitemap builder. It returns nil when no base
+// URL is configured, which disables sitemap generation.
+func NewSitemapBuilder(opts SitemapOptions) *SitemapBuilder {
+	if opts.BaseURL == "" {
+		return nil
+	}
+	return &SitemapBuilder{
+		baseDir: opts.BaseDir,
+		baseURL: strings.TrimSuffix(opts.BaseURL, "/"),
+		entries: make(map[string]time.Time),
+
+		lineEndings: opts.LineEndings,
+	}
+}
+
+// Add records doc, written to filePath, as a sitemap entry. A file written
+// more than once keeps its latest fetch time.
+func (b *SitemapBuilder) Add(doc *domain.Document, filePath string) {
+	if b == nil || doc == nil {
+		return
+	}
+
+	loc := b.loc(filePath)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if prev, ok := b.entries[loc]; !ok || doc.FetchedAt.After(prev) {
+		b.entries[loc] = doc.FetchedAt
+	}
+}
+
+// Remove drops the entry of filePath, such as a pruned page.
+func (b *SitemapBuilder) Remove(filePath string) {
+	if b == nil {
+		return
+	}
+
+	loc := b.loc(filePath)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.entries, loc)
+}
+
+// loc returns the public URL of filePath.
+func (b *SitemapBuilder) loc(filePath string) string {
+	relPath, err := filepath.Rel(b.baseDir, filePath)
+	if err != nil {
+		relPath = filePath
+	}
+	segments := strings.Split(filepath.ToSlash(relPath), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return b.baseURL + "/" + strings.Join(segments, "/")
+}
+
+// Count returns the number of entries recorded so far.
+func (b *SitemapBuilder) Count() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.entries)
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Flush writes sitemap.xml to the base directory, sorted by URL. Nothing is
+// written when no documents were recorded.
+func (b *SitemapBuilder) Flush() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	set := sitemapURLSet{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+	for loc, fetchedAt := range b.entries {
+		entry := sitemapURL{Loc: loc}
+		if !fetchedAt.IsZero() {
+			entry.LastMod = fetchedAt.UTC().Format(time.RFC3339)
+		}
+		set.URLs = append(set.URLs, entry)
+	}
+	b.mu.Unlock()
+
+	if len(set.URLs) == 0 {
+		return nil
+	}
+	sort.Slice(set.URLs, func(i, j int) bool { return set.URLs[i].Loc < set.URLs[j].Loc })
+
+	data, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+
+	content := NormalizeText(string(data), b.lineEndings)
+	return os.WriteFile(filepath.Join(b.baseDir, SitemapFilename), []byte(content), 0644)
+}
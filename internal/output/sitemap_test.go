@@ -0,0 +1,75 @@
+package output
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSitemapBuilder_DisabledWithoutBaseURL(t *testing.T) {
+	b := NewSitemapBuilder(SitemapOptions{BaseDir: t.TempDir()})
+	assert.Nil(t, b)
+
+	b.Add(&domain.Document{URL: "https://example.com"}, "x.md")
+	assert.Equal(t, 0, b.Count())
+	assert.NoError(t, b.Flush())
+}
+
+func TestSitemapBuilder_Flush(t *testing.T) {
+	dir := t.TempDir()
+	sitemap := NewSitemapBuilder(SitemapOptions{BaseDir: dir, BaseURL: "https://kb.example.com/docs/"})
+	writer := NewWriter(WriterOptions{BaseDir: dir, Force: true, Sitemap: sitemap})
+
+	fetched := time.Date(2026, 3, 1, 12, 30, 0, 0, time.FixedZone("BRT", -3*3600))
+	docs := []*domain.Document{
+		{URL: "https://example.com/guide/intro", Title: "Intro", Content: "# Intro", FetchedAt: fetched},
+		{URL: "https://example.com/api", Title: "API", Content: "# API", RelativePath: "api.md"},
+	}
+	for _, doc := range docs {
+		require.NoError(t, writer.Write(context.Background(), doc))
+	}
+	sitemap.Add(&domain.Document{URL: "https://example.com/notes"}, filepath.Join(dir, "release notes.md"))
+	require.NoError(t, sitemap.Flush())
+
+	data, err := os.ReadFile(filepath.Join(dir, SitemapFilename))
+	require.NoError(t, err)
+	out := string(data)
+
+	assert.Contains(t, out, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	assert.Contains(t, out, "<loc>https://kb.example.com/docs/api.md</loc>")
+	assert.Contains(t, out, "<loc>https://kb.example.com/docs/release%20notes.md</loc>")
+	assert.Contains(t, out, "<lastmod>2026-03-01T15:30:00Z</lastmod>")
+	assert.Equal(t, 3, sitemap.Count())
+	assert.Less(t, strings.Index(out, "api.md"), strings.Index(out, "guide/intro.md"), "entries are sorted")
+}
+
+func TestSitemapBuilder_Remove(t *testing.T) {
+	dir := t.TempDir()
+	sitemap := NewSitemapBuilder(SitemapOptions{BaseDir: dir, BaseURL: "https://kb.example.com"})
+	sitemap.Add(&domain.Document{URL: "https://example.com/a"}, filepath.Join(dir, "a.md"))
+	sitemap.Add(&domain.Document{URL: "https://example.com/b"}, filepath.Join(dir, "b.md"))
+	sitemap.Remove(filepath.Join(dir, "a.md"))
+	assert.Equal(t, 1, sitemap.Count())
+
+	var disabled *SitemapBuilder
+	disabled.Remove("a.md")
+}
+
+func TestSitemapBuilder_SkipsDryRun(t *testing.T) {
+	dir := t.TempDir()
+	sitemap := NewSitemapBuilder(SitemapOptions{BaseDir: dir, BaseURL: "https://kb.example.com"})
+	writer := NewWriter(WriterOptions{BaseDir: dir, DryRun: true, Sitemap: sitemap})
+
+	require.NoError(t, writer.Write(context.Background(), &domain.Document{URL: "https://example.com/a", Content: "a"}))
+	require.NoError(t, sitemap.Flush())
+
+	_, err := os.Stat(filepath.Join(dir, SitemapFilename))
+	assert.True(t, os.IsNotExist(err))
+}
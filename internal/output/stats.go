@@ -0,0 +1,276 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// DefaultSmallDocumentChars is the body length, in characters, below which
+// Analyze reports a document as suspiciously small.
+const DefaultSmallDocumentChars = 200
+
+// topPathsLimit is the number of directories listed in OutputStats.TopPaths.
+const topPathsLimit = 10
+
+// sizeBuckets are the upper bounds (exclusive) of the file size
+// distribution; the last bucket is unbounded.
+var sizeBuckets = []struct {
+	label string
+	max   int64
+}{
+	{"< 1 KB", 1 << 10},
+	{"1-10 KB", 10 << 10},
+	{"10-100 KB", 100 << 10},
+	{"100 KB-1 MB", 1 << 20},
+	{">= 1 MB", -1},
+}
+
+// StatsOptions configures Analyze.
+type StatsOptions struct {
+	// SmallChars is the body length below which a document is reported as
+	// small (0 uses DefaultSmallDocumentChars).
+	SmallChars int
+	// MetadataFilename is the name of the JSON metadata index in the
+	// directory (default metadata.json).
+	MetadataFilename string
+}
+
+// SizeBucket counts the documents of one file size range.
+type SizeBucket struct {
+	Label string
+	Count int
+}
+
+// PathCount counts the documents in one directory.
+type PathCount struct {
+	Path  string
+	Count int
+}
+
+// SmallDocument is a document whose body has fewer characters than
+// StatsOptions.SmallChars.
+type SmallDocument struct {
+	Path  string
+	Chars int
+}
+
+// OutputStats summarizes the markdown documents of an output directory
+// written by Writer.
+type OutputStats struct {
+	Dir       string
+	Documents int
+	Words     int
+	// Chars counts the characters of document bodies, without frontmatter.
+	Chars int
+	Bytes int64
+
+	Sizes    []SizeBucket
+	TopPaths []PathCount
+	// Empty lists documents without body content; Small lists the others
+	// below the small threshold. Paths are relative to Dir.
+	Empty      []string
+	Small      []SmallDocument
+	SmallChars int
+
+	// Indexed is the number of documents listed in the metadata index, or -1
+	// when the directory has none. MissingFromIndex lists indexed paths
+	// without a file.
+	Indexed          int
+	MissingFromIndex []string
+}
+
+// Analyze scans the markdown documents under dir, and its metadata index
+// when present, and summarizes them. Hidden files and directories (such as
+// the sync state) are ignored.
+func Analyze(dir string, opts StatsOptions) (*OutputStats, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	smallChars := opts.SmallChars
+	if smallChars <= 0 {
+		smallChars = DefaultSmallDocumentChars
+	}
+	stats := &OutputStats{Dir: dir, SmallChars: smallChars, Indexed: -1}
+	for _, bucket := range sizeBuckets {
+		stats.Sizes = append(stats.Sizes, SizeBucket{Label: bucket.label})
+	}
+	dirCounts := make(map[string]int)
+
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		_, body := splitFrontmatter(strings.ReplaceAll(string(data), "\r\n", "\n"))
+		body = strings.TrimSpace(body)
+		chars := utf8.RuneCountInString(body)
+
+		stats.Documents++
+		stats.Words += len(strings.Fields(body))
+		stats.Chars += chars
+		stats.Bytes += int64(len(data))
+		stats.Sizes[sizeBucket(int64(len(data)))].Count++
+		dirCounts[filepath.ToSlash(filepath.Dir(rel))]++
+
+		switch {
+		case chars == 0:
+			stats.Empty = append(stats.Empty, rel)
+		case chars < smallChars:
+			stats.Small = append(stats.Small, SmallDocument{Path: rel, Chars: chars})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats.TopPaths = topPaths(dirCounts, topPathsLimit)
+	if err := stats.readIndex(opts.MetadataFilename); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// readIndex records how many documents the metadata index lists and which
+// of them have no file.
+func (s *OutputStats) readIndex(filename string) error {
+	if filename == "" {
+		filename = "metadata.json"
+	}
+	data, err := os.ReadFile(filepath.Join(s.Dir, filename))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var index domain.SimpleMetadataIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return fmt.Errorf("failed to parse metadata index %s: %w", filename, err)
+	}
+	s.Indexed = len(index.Documents)
+	for _, doc := range index.Documents {
+		if _, err := os.Stat(filepath.Join(s.Dir, filepath.FromSlash(doc.FilePath))); err != nil {
+			s.MissingFromIndex = append(s.MissingFromIndex, doc.FilePath)
+		}
+	}
+	return nil
+}
+
+func sizeBucket(size int64) int {
+	for i, bucket := range sizeBuckets {
+		if bucket.max < 0 || size < bucket.max {
+			return i
+		}
+	}
+	return len(sizeBuckets) - 1
+}
+
+// topPaths returns the limit directories holding the most documents, most
+// first and then by path.
+func topPaths(counts map[string]int, limit int) []PathCount {
+	paths := make([]PathCount, 0, len(counts))
+	for path, count := range counts {
+		paths = append(paths, PathCount{Path: path, Count: count})
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if paths[i].Count != paths[j].Count {
+			return paths[i].Count > paths[j].Count
+		}
+		return paths[i].Path < paths[j].Path
+	})
+	if len(paths) > limit {
+		paths = paths[:limit]
+	}
+	return paths
+}
+
+// Format writes a human-readable report of the statistics to w.
+func (s *OutputStats) Format(w io.Writer) {
+	fmt.Fprintf(w, "Directory:    %s\n", s.Dir)
+	fmt.Fprintf(w, "Documents:    %d\n", s.Documents)
+	if s.Indexed >= 0 {
+		fmt.Fprintf(w, "Indexed:      %d in metadata index\n", s.Indexed)
+	}
+	if s.Documents == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Words:        %d (avg %d per document)\n", s.Words, s.Words/s.Documents)
+	fmt.Fprintf(w, "Characters:   %d\n", s.Chars)
+	fmt.Fprintf(w, "Size:         %s\n", formatBytes(s.Bytes))
+
+	fmt.Fprintln(w, "\nFile sizes:")
+	for _, bucket := range s.Sizes {
+		fmt.Fprintf(w, "  %-12s %d\n", bucket.Label, bucket.Count)
+	}
+
+	fmt.Fprintln(w, "\nTop paths:")
+	for _, path := range s.TopPaths {
+		fmt.Fprintf(w, "  %-40s %d\n", path.Path, path.Count)
+	}
+
+	if len(s.Empty) > 0 {
+		fmt.Fprintf(w, "\nEmpty documents (%d):\n", len(s.Empty))
+		for _, path := range s.Empty {
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+	}
+	if len(s.Small) > 0 {
+		fmt.Fprintf(w, "\nSmall documents (< %d chars, %d):\n", s.SmallChars, len(s.Small))
+		for _, doc := range s.Small {
+			fmt.Fprintf(w, "  %s (%d chars)\n", doc.Path, doc.Chars)
+		}
+	}
+	if len(s.MissingFromIndex) > 0 {
+		fmt.Fprintf(w, "\nIndexed but missing (%d):\n", len(s.MissingFromIndex))
+		for _, path := range s.MissingFromIndex {
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+	}
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	long := strings.Repeat("word ", 300)
+	write("guide/intro.md", "---\ntitle: Intro\n---\n\n"+long)
+	write("guide/setup.md", "---\r\ntitle: Setup\r\n---\r\n\r\nshort page\r\n")
+	write("api/empty.md", "---\ntitle: Empty\n---\n\n")
+	write("guide/diagram.png", "not a document")
+	write(".repodocs-state.json", "{}")
+	write(".hidden/skipped.md", long)
+
+	index := domain.SimpleMetadataIndex{Documents: []domain.SimpleDocumentMetadata{
+		{FilePath: "guide/intro.md", SimpleMetadata: &domain.SimpleMetadata{}},
+		{FilePath: "guide/removed.md", SimpleMetadata: &domain.SimpleMetadata{}},
+	}}
+	data, err := json.Marshal(index)
+	require.NoError(t, err)
+	write("metadata.json", string(data))
+
+	stats, err := Analyze(dir, StatsOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, stats.Documents)
+	assert.Equal(t, 302, stats.Words)
+	assert.Equal(t, []PathCount{{Path: "guide", Count: 2}, {Path: "api", Count: 1}}, stats.TopPaths)
+	assert.Equal(t, []string{"api/empty.md"}, stats.Empty)
+	assert.Equal(t, []SmallDocument{{Path: "guide/setup.md", Chars: len("short page")}}, stats.Small)
+	assert.Equal(t, 2, stats.Indexed)
+	assert.Equal(t, []string{"guide/removed.md"}, stats.MissingFromIndex)
+	assert.Equal(t, 2, stats.Sizes[0].Count)
+	assert.Equal(t, 1, stats.Sizes[1].Count)
+
+	var out bytes.Buffer
+	stats.Format(&out)
+	assert.Contains(t, out.String(), "Documents:    3")
+	assert.Contains(t, out.String(), "Indexed:      2 in metadata index")
+	assert.Contains(t, out.String(), "guide/setup.md (10 chars)")
+}
+
+func TestAnalyze_NoIndex(t *testing.T) {
+	stats, err := Analyze(t.TempDir(), StatsOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Documents)
+	assert.Equal(t, -1, stats.Indexed)
+
+	_, err = Analyze(filepath.Join(t.TempDir(), "missing"), StatsOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,28 @@
+package output
+
+import (
+	"context"
+	"path/filepath"
+)
+
+// subdirKey is the context key of the directory attached by WithSubdir.
+type subdirKey struct{}
+
+// WithSubdir returns a context whose documents are written under dir,
+// relative to the writer's base directory. Manifest sources share one
+// Writer, so the subdirectory of each source travels with its context.
+func WithSubdir(ctx context.Context, dir string) context.Context {
+	if dir == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, subdirKey{}, dir)
+}
+
+// baseDirFor returns the directory documents written with ctx go to.
+func (w *Writer) baseDirFor(ctx context.Context) string {
+	dir, _ := ctx.Value(subdirKey{}).(string)
+	if dir == "" {
+		return w.baseDir
+	}
+	return filepath.Join(w.baseDir, dir)
+}
@@ -2,8 +2,12 @@ package output
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/quantmind-br/repodocs/internal/converter"
 	"github.com/quantmind-br/repodocs/internal/domain"
@@ -18,6 +22,19 @@ type Writer struct {
 	force        bool
 	dryRun       bool
 	collector    *MetadataCollector
+	sitemap      *SitemapBuilder
+	chunks       *ChunkWriter
+
+	outputName  string
+	slugFrom    string
+	lineEndings LineEndings
+	format      Format
+
+	mu      sync.Mutex
+	written []WrittenFile
+	files   map[string]struct{} // every document path of the run, for checksums.txt
+	claimed map[string]string   // output path -> URL, for named/slugged paths
+	paths   map[string]string   // URL -> output path, for named/slugged/subdir paths
 }
 
 // WriterOptions configures document output paths, overwrite behavior, dry-run mode, and metadata collection.
@@ -28,8 +45,31 @@ type WriterOptions struct {
 	Force        bool
 	DryRun       bool
 	Collector    *MetadataCollector
+	// Sitemap, when set, records every written document for sitemap.xml.
+	Sitemap *SitemapBuilder
+	// Chunks, when set, splits every written document into chunks.jsonl.
+	Chunks *ChunkWriter
+	// OutputName fixes the filename (without extension) of written pages,
+	// intended for single-document extractions. Repeated writes get a
+	// numeric suffix.
+	OutputName string
+	// SlugFrom selects how filenames of URL-sourced pages are derived:
+	// SlugFromURL (default) or SlugFromTitle.
+	SlugFrom string
+	// LineEndings is the line terminator of written files (default lf).
+	// Files are always written as UTF-8 without a byte order mark.
+	LineEndings LineEndings
+	// Format selects markdown (default) or plain-text documents. Raw files
+	// are written unchanged in either format.
+	Format Format
 }
 
+// Filename sources accepted by WriterOptions.SlugFrom.
+const (
+	SlugFromURL   = "url"
+	SlugFromTitle = "title"
+)
+
 // NewWriter creates a writer with the supplied options and default output directory.
 func NewWriter(opts WriterOptions) *Writer {
 	if opts.BaseDir == "" {
@@ -43,56 +83,127 @@ func NewWriter(opts WriterOptions) *Writer {
 		force:        opts.Force,
 		dryRun:       opts.DryRun,
 		collector:    opts.Collector,
+		sitemap:      opts.Sitemap,
+		chunks:       opts.Chunks,
+		outputName:   opts.OutputName,
+		slugFrom:     opts.SlugFrom,
+		lineEndings:  opts.LineEndings,
+		format:       opts.Format,
+		claimed:      make(map[string]string),
+		paths:        make(map[string]string),
 	}
 }
 
 // Write saves a document to the output directory
 func (w *Writer) Write(ctx context.Context, doc *domain.Document) error {
+	baseDir := w.baseDirFor(ctx)
 	var path string
-	if doc.IsRawFile && doc.RelativePath != "" {
-		path = utils.GenerateRawPathFromRelative(w.baseDir, doc.RelativePath, w.flat)
+	if doc.PreserveTree && doc.RelativePath != "" {
+		path = utils.GenerateTreePath(baseDir, doc.RelativePath, doc.IsRawFile)
+	} else if doc.IsRawFile && doc.RelativePath != "" {
+		path = utils.GenerateRawPathFromRelative(baseDir, doc.RelativePath, w.flat)
 	} else if doc.RelativePath != "" {
-		path = utils.GeneratePathFromRelative(w.baseDir, doc.RelativePath, w.flat)
+		path = utils.GeneratePathFromRelative(baseDir, doc.RelativePath, w.flat)
+	} else if w.outputName != "" {
+		name := strings.TrimSuffix(utils.SanitizeFilename(w.outputName), ".md")
+		path = w.claimPath(w.format.documentPath(filepath.Join(baseDir, name+".md")), doc.URL)
+	} else if w.slugFrom == SlugFromTitle {
+		path = w.claimPath(w.format.documentPath(utils.GenerateTitlePath(baseDir, doc.URL, doc.Title, w.flat)), doc.URL)
 	} else {
-		path = utils.GeneratePath(w.baseDir, doc.URL, w.flat)
+		path = utils.GeneratePath(baseDir, doc.URL, w.flat)
 	}
-
-	if !w.force {
-		if _, err := os.Stat(path); err == nil {
-			return nil
-		}
+	if !doc.IsRawFile {
+		path = w.format.documentPath(path)
+	}
+	if baseDir != w.baseDir {
+		// GetPath has no context to find the subdirectory from.
+		w.mu.Lock()
+		w.paths[doc.URL] = path
+		w.mu.Unlock()
 	}
 
 	if w.dryRun {
 		return nil
 	}
 
+	if !w.forceFor(ctx) {
+		if _, err := os.Stat(path); err == nil {
+			w.addFile(path)
+			return nil
+		}
+	}
+
 	if err := utils.EnsureDir(path); err != nil {
 		return err
 	}
 
-	var content string
-	if doc.IsRawFile {
-		content = doc.Content
-	} else {
+	body := strings.TrimPrefix(doc.Content, utf8BOM)
+	content := body
+	if !doc.IsRawFile && w.format == FormatText {
+		content = converter.MarkdownToText(body)
+	} else if !doc.IsRawFile {
 		var err error
-		content, err = converter.AddFrontmatter(doc.Content, doc)
+		content, err = converter.AddFrontmatter(body, doc)
 		if err != nil {
 			return err
 		}
 	}
 
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+	if err := os.WriteFile(path, []byte(NormalizeText(content, w.lineEndings)), 0644); err != nil {
 		return err
 	}
 
 	if w.jsonMetadata && w.collector != nil {
 		w.collector.Add(doc, path)
 	}
+	w.sitemap.Add(doc, path)
+	w.addFile(path)
+
+	if !doc.IsRawFile {
+		if err := w.chunks.Add(doc, path); err != nil {
+			return fmt.Errorf("failed to write chunks: %w", err)
+		}
+		w.mu.Lock()
+		w.written = append(w.written, WrittenFile{Path: path, URL: doc.URL})
+		w.mu.Unlock()
+	}
 
 	return nil
 }
 
+// TakeWritten returns the markdown documents written since the last call and
+// resets the list.
+func (w *Writer) TakeWritten() []WrittenFile {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	written := w.written
+	w.written = nil
+	return written
+}
+
+// addFile records path as a document of the run.
+func (w *Writer) addFile(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.files == nil {
+		w.files = make(map[string]struct{})
+	}
+	w.files[path] = struct{}{}
+}
+
+// Files returns the sorted paths of every document the writer wrote or kept
+// because it already existed.
+func (w *Writer) Files() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	files := make([]string, 0, len(w.files))
+	for path := range w.files {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
 // FlushMetadata writes collected metadata through the configured collector.
 func (w *Writer) FlushMetadata() error {
 	if w.collector != nil {
@@ -116,9 +227,43 @@ func (w *Writer) WriteMultiple(ctx context.Context, docs []*domain.Document) err
 	return nil
 }
 
-// GetPath returns the output path for a URL
+// claimPath reserves path for url within this run. Named and title-slugged
+// paths can collide across different pages, so a later page gets a numeric
+// suffix (name-2.md, name-3.md, ...) instead of being dropped.
+func (w *Writer) claimPath(path, url string) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if existing, ok := w.paths[url]; ok {
+		return existing
+	}
+
+	candidate := path
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	for i := 2; ; i++ {
+		if _, taken := w.claimed[candidate]; !taken {
+			break
+		}
+		candidate = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+
+	w.claimed[candidate] = url
+	w.paths[url] = candidate
+	return candidate
+}
+
+// GetPath returns the output path for a URL. Pages written under a fixed
+// output name, title slug or subdirectory (see WithSubdir) report the path
+// they were written to.
 func (w *Writer) GetPath(url string) string {
-	return utils.GeneratePath(w.baseDir, url, w.flat)
+	w.mu.Lock()
+	path, ok := w.paths[url]
+	w.mu.Unlock()
+	if ok {
+		return path
+	}
+	return w.format.documentPath(utils.GeneratePath(w.baseDir, url, w.flat))
 }
 
 // Exists checks if a document already exists
@@ -128,6 +273,37 @@ func (w *Writer) Exists(url string) bool {
 	return err == nil
 }
 
+// Contains reports whether path lies inside the output directory (and is
+// not the directory itself).
+func (w *Writer) Contains(path string) bool {
+	base, err := filepath.Abs(w.baseDir)
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(base, abs)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// RemoveEmptyDirs removes dir and its parents while they are empty and
+// inside the output directory.
+func (w *Writer) RemoveEmptyDirs(dir string) {
+	for w.Contains(dir) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// BaseDir returns the output directory.
+func (w *Writer) BaseDir() string {
+	return w.baseDir
+}
+
 // EnsureBaseDir creates the base directory if it doesn't exist
 func (w *Writer) EnsureBaseDir() error {
 	return os.MkdirAll(w.baseDir, 0755)
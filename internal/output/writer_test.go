@@ -362,6 +362,33 @@ func TestWriter_Exists(t *testing.T) {
 	})
 }
 
+// TestWriter_Contains tests the output directory containment check
+func TestWriter_Contains(t *testing.T) {
+	tmpDir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: tmpDir})
+
+	assert.True(t, w.Contains(filepath.Join(tmpDir, "page.md")))
+	assert.True(t, w.Contains(filepath.Join(tmpDir, "docs", "page.md")))
+	assert.False(t, w.Contains(tmpDir))
+	assert.False(t, w.Contains(filepath.Join(tmpDir, "..", "page.md")))
+	assert.False(t, w.Contains(filepath.Join(filepath.Dir(tmpDir), filepath.Base(tmpDir)+"-other", "page.md")))
+}
+
+// TestWriter_RemoveEmptyDirs tests removing directories left empty
+func TestWriter_RemoveEmptyDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: tmpDir})
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "a", "b", "c"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a", "keep.md"), []byte("x"), 0644))
+
+	w.RemoveEmptyDirs(filepath.Join(tmpDir, "a", "b", "c"))
+
+	assert.NoDirExists(t, filepath.Join(tmpDir, "a", "b"))
+	assert.FileExists(t, filepath.Join(tmpDir, "a", "keep.md"))
+	assert.DirExists(t, tmpDir)
+}
+
 // TestWriter_EnsureBaseDir tests creating base directory
 func TestWriter_EnsureBaseDir(t *testing.T) {
 	t.Run("creates base directory", func(t *testing.T) {
@@ -561,3 +588,89 @@ func TestWriter_Write_RawFile_FlatMode(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "key: value", string(content))
 }
+
+func TestWriter_Write_PreserveTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: tmpDir, Flat: true, SlugFrom: SlugFromTitle})
+
+	doc := &domain.Document{
+		URL:          "https://github.com/user/repo/blob/main/docs/Getting Started.md",
+		Title:        "Getting started",
+		RelativePath: "docs/Getting Started.md",
+		Content:      "# Getting started",
+		PreserveTree: true,
+	}
+	require.NoError(t, w.Write(context.Background(), doc))
+
+	_, err := os.Stat(filepath.Join(tmpDir, "docs", "Getting Started.md"))
+	assert.NoError(t, err, "the repository path wins over flat and slug layouts")
+}
+
+func TestWriter_Write_SlugFromTitle(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir, Flat: true, SlugFrom: SlugFromTitle})
+	ctx := context.Background()
+
+	require.NoError(t, w.Write(ctx, &domain.Document{URL: "https://example.com/a", Title: "Introduction", Content: "A"}))
+	require.NoError(t, w.Write(ctx, &domain.Document{URL: "https://example.com/b", Title: "Introduction", Content: "B"}))
+	require.NoError(t, w.Write(ctx, &domain.Document{URL: "https://example.com/c", Content: "C"}))
+
+	assert.FileExists(t, filepath.Join(dir, "introduction.md"))
+	assert.FileExists(t, filepath.Join(dir, "introduction-2.md"))
+	assert.FileExists(t, filepath.Join(dir, "c.md"), "untitled pages fall back to the URL path")
+	assert.Equal(t, filepath.Join(dir, "introduction-2.md"), w.GetPath("https://example.com/b"))
+	assert.True(t, w.Exists("https://example.com/b"))
+}
+
+func TestWriter_Write_OutputName(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir, OutputName: "react-hooks.md"})
+	ctx := context.Background()
+
+	require.NoError(t, w.Write(ctx, &domain.Document{URL: "https://example.com/docs/hooks", Title: "Hooks", Content: "A"}))
+	require.NoError(t, w.Write(ctx, &domain.Document{URL: "https://example.com/docs/other", Content: "B"}))
+
+	assert.FileExists(t, filepath.Join(dir, "react-hooks.md"))
+	assert.FileExists(t, filepath.Join(dir, "react-hooks-2.md"))
+	assert.Equal(t, filepath.Join(dir, "react-hooks.md"), w.GetPath("https://example.com/docs/hooks"))
+}
+
+func TestWriter_Write_WithForce(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir})
+	doc := &domain.Document{URL: "https://example.com/docs/guide", Content: "old"}
+	require.NoError(t, w.Write(context.Background(), doc))
+	path := w.GetPath(doc.URL)
+
+	doc.Content = "new"
+	require.NoError(t, w.Write(context.Background(), doc))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "old")
+
+	require.NoError(t, w.Write(WithForce(context.Background(), true), doc))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "new")
+
+	forced := NewWriter(WriterOptions{BaseDir: dir, Force: true})
+	doc.Content = "newer"
+	require.NoError(t, forced.Write(WithForce(context.Background(), false), doc))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "newer")
+}
+
+func TestWriter_Write_Subdir(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(WriterOptions{BaseDir: dir})
+	ctx := WithSubdir(context.Background(), "v2")
+
+	require.NoError(t, w.Write(ctx, &domain.Document{URL: "https://example.com/docs/guide", Content: "A"}))
+	require.NoError(t, w.Write(context.Background(), &domain.Document{URL: "https://example.com/docs/other", Content: "B"}))
+
+	assert.FileExists(t, filepath.Join(dir, "v2", "docs", "guide.md"))
+	assert.FileExists(t, filepath.Join(dir, "docs", "other.md"))
+	assert.Equal(t, filepath.Join(dir, "v2", "docs", "guide.md"), w.GetPath("https://example.com/docs/guide"))
+	assert.True(t, w.Exists("https://example.com/docs/guide"))
+}
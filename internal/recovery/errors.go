@@ -7,6 +7,9 @@ import (
 	"github.com/quantmind-br/repodocs/internal/domain"
 )
 
+// maxListedFailures caps the failed documents listed in an OutcomeError.
+const maxListedFailures = 10
+
 // OutcomeError wraps a strategy result and verdict into a user-facing,
 // actionable error message.
 type OutcomeError struct {
@@ -42,6 +45,13 @@ func (e *OutcomeError) Error() string {
 		fmt.Fprintf(&b, "\nWritten:     %d docs", snapshot.DocsWritten)
 		fmt.Fprintf(&b, "\nSkipped:     %d docs", snapshot.DocsSkipped)
 		fmt.Fprintf(&b, "\nFailed:      %d docs", snapshot.DocsFailed)
+		for i, failure := range snapshot.Failures {
+			if i == maxListedFailures {
+				fmt.Fprintf(&b, "\n  - ... and %d more", len(snapshot.Failures)-i)
+				break
+			}
+			fmt.Fprintf(&b, "\n  - %s: %s", failure.URL, failure.Error)
+		}
 		if len(snapshot.Diagnostics) > 0 {
 			b.WriteString("\nDiagnostics:")
 			for _, d := range snapshot.Diagnostics {
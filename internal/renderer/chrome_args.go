@@ -0,0 +1,82 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+)
+
+// managedChromeFlags are launch flags repodocs (or the rod launcher) sets
+// itself. Overriding them through ExtraArgs would break the DevTools
+// connection, the profile cleanup, or the dedicated options that own them
+// (--proxy, NoSandbox, Headless).
+var managedChromeFlags = map[string]string{
+	"remote-debugging-port":    "managed by the launcher",
+	"remote-debugging-address": "managed by the launcher",
+	"remote-debugging-pipe":    "managed by the launcher",
+	"user-data-dir":            "managed by the launcher",
+	"headless":                 "controlled by the renderer's headless setting",
+	"no-sandbox":               "controlled by the renderer's sandbox setting",
+	"proxy-server":             "use --proxy instead",
+}
+
+// chromeArg is a parsed "--name[=value]" launch flag.
+type chromeArg struct {
+	name  string
+	value string
+	set   bool
+}
+
+// ValidateChromeArgs checks extra Chrome launch flags without launching a
+// browser. Each flag must have the form "--name" or "--name=value" and may not
+// override a flag repodocs manages itself.
+func ValidateChromeArgs(args []string) error {
+	_, err := parseChromeArgs(args)
+	return err
+}
+
+func parseChromeArgs(args []string) ([]chromeArg, error) {
+	parsed := make([]chromeArg, 0, len(args))
+	seen := make(map[string]bool)
+	for _, raw := range args {
+		arg := strings.TrimSpace(raw)
+		if !strings.HasPrefix(arg, "--") {
+			return nil, fmt.Errorf("invalid chrome arg %q: must start with --", raw)
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid chrome arg %q", raw)
+		}
+		if strings.HasPrefix(name, "rod-") {
+			return nil, fmt.Errorf("chrome arg %q is reserved by the launcher", raw)
+		}
+		if reason, ok := managedChromeFlags[name]; ok {
+			return nil, fmt.Errorf("chrome arg %q cannot be overridden (%s)", raw, reason)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("chrome arg --%s given more than once", name)
+		}
+		seen[name] = true
+
+		parsed = append(parsed, chromeArg{name: name, value: value, set: hasValue})
+	}
+	return parsed, nil
+}
+
+// applyChromeArgs adds extra launch flags to l. They are applied after the
+// renderer's own flags, so a user-supplied value wins over defaults such as
+// the stealth --disable-blink-features setting.
+func applyChromeArgs(l *launcher.Launcher, args []chromeArg) *launcher.Launcher {
+	for _, arg := range args {
+		if arg.set {
+			l = l.Set(flags.Flag(arg.name), arg.value)
+		} else {
+			l = l.Set(flags.Flag(arg.name))
+		}
+	}
+	return l
+}
@@ -0,0 +1,63 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChromeArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []chromeArg
+		wantErr string
+	}{
+		{name: "empty", args: nil, want: []chromeArg{}},
+		{
+			name: "bare and valued flags",
+			args: []string{"--disable-gpu", "--lang=de-DE", "--window-size=1920,1080"},
+			want: []chromeArg{
+				{name: "disable-gpu"},
+				{name: "lang", value: "de-DE", set: true},
+				{name: "window-size", value: "1920,1080", set: true},
+			},
+		},
+		{name: "missing dashes", args: []string{"disable-gpu"}, wantErr: "must start with --"},
+		{name: "empty name", args: []string{"--=x"}, wantErr: "invalid chrome arg"},
+		{name: "managed debugging port", args: []string{"--remote-debugging-port=9222"}, wantErr: "cannot be overridden"},
+		{name: "managed proxy", args: []string{"--proxy-server=http://p:1"}, wantErr: "use --proxy"},
+		{name: "managed case-insensitive", args: []string{"--User-Data-Dir=/tmp/x"}, wantErr: "cannot be overridden"},
+		{name: "launcher internal", args: []string{"--rod-bin=/bin/chrome"}, wantErr: "reserved"},
+		{name: "duplicate", args: []string{"--lang=en", "--lang=de"}, wantErr: "more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChromeArgs(tt.args)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Error(t, ValidateChromeArgs(tt.args))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestApplyChromeArgs(t *testing.T) {
+	args, err := parseChromeArgs([]string{"--disable-gpu", "--lang=de-DE", "--disable-blink-features=Foo"})
+	require.NoError(t, err)
+
+	l := launcher.New().Set("disable-blink-features", "AutomationControlled")
+	l = applyChromeArgs(l, args)
+
+	_, has := l.GetFlags("disable-gpu")
+	assert.True(t, has)
+	assert.Equal(t, "de-DE", l.Get("lang"))
+	assert.Equal(t, "Foo", l.Get("disable-blink-features"), "user flags override renderer defaults")
+}
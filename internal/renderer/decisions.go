@@ -0,0 +1,133 @@
+package renderer
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// decisionSamples is how many consecutive pages of a host must agree before
+	// the host gets a verdict; until then every page is evaluated on its own.
+	decisionSamples = 3
+
+	// decisionRecheckEvery forces a fresh per-page evaluation every N lookups
+	// so a host whose pages change shape mid-run is noticed.
+	decisionRecheckEvery = 25
+
+	// maxDecisionHosts caps the memo so broad multi-host crawls do not grow it
+	// without bound; hosts beyond the cap are always evaluated per page.
+	maxDecisionHosts = 1024
+)
+
+// RenderDecisions memoizes, per host, whether pages need JavaScript
+// rendering. Once enough pages of a host agree, later pages reuse the verdict
+// instead of fetching statically and inspecting the HTML first. A verdict
+// expires after the TTL and is periodically re-checked against a live page.
+// A nil *RenderDecisions is valid and never returns a verdict.
+type RenderDecisions struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostDecision
+}
+
+type hostDecision struct {
+	needsJS   bool
+	streak    int // consecutive agreeing observations
+	decided   bool
+	decidedAt time.Time
+	lookups   int // verdict lookups since the last re-check
+}
+
+// NewRenderDecisions creates a per-host decision cache whose verdicts live for
+// ttl. It returns nil when ttl is not positive, which disables caching.
+func NewRenderDecisions(ttl time.Duration) *RenderDecisions {
+	if ttl <= 0 {
+		return nil
+	}
+	return &RenderDecisions{
+		ttl:   ttl,
+		now:   time.Now,
+		hosts: make(map[string]*hostDecision),
+	}
+}
+
+// Lookup returns the cached verdict for rawURL's host. ok is false when the
+// host is uncertain, the verdict expired, or a periodic re-check is due; the
+// caller should then evaluate the page and Record the result.
+func (d *RenderDecisions) Lookup(rawURL string) (needsJS, ok bool) {
+	if d == nil {
+		return false, false
+	}
+	host := decisionHost(rawURL)
+	if host == "" {
+		return false, false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	h, exists := d.hosts[host]
+	if !exists || !h.decided {
+		return false, false
+	}
+	if d.now().Sub(h.decidedAt) >= d.ttl {
+		h.decided = false
+		h.streak = 0
+		return false, false
+	}
+	h.lookups++
+	if h.lookups >= decisionRecheckEvery {
+		h.lookups = 0
+		return false, false
+	}
+	return h.needsJS, true
+}
+
+// Record stores a per-page evaluation for rawURL's host. A verdict is set once
+// decisionSamples consecutive pages agree; a disagreeing page clears it so the
+// host is evaluated per page again.
+func (d *RenderDecisions) Record(rawURL string, needsJS bool) {
+	if d == nil {
+		return
+	}
+	host := decisionHost(rawURL)
+	if host == "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	h, exists := d.hosts[host]
+	if !exists {
+		if len(d.hosts) >= maxDecisionHosts {
+			return
+		}
+		h = &hostDecision{}
+		d.hosts[host] = h
+	}
+
+	if h.streak > 0 && h.needsJS != needsJS {
+		h.streak = 0
+		h.decided = false
+	}
+	h.needsJS = needsJS
+	h.streak++
+	if h.streak >= decisionSamples && !h.decided {
+		h.decided = true
+		h.decidedAt = d.now()
+		h.lookups = 0
+	}
+}
+
+func decisionHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,99 @@
+package renderer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDecisions(ttl time.Duration) (*RenderDecisions, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewRenderDecisions(ttl)
+	d.now = func() time.Time { return now }
+	return d, &now
+}
+
+func TestNewRenderDecisions_DisabledWithoutTTL(t *testing.T) {
+	assert.Nil(t, NewRenderDecisions(0))
+
+	var d *RenderDecisions
+	d.Record("https://a.example.com/x", true)
+	_, ok := d.Lookup("https://a.example.com/x")
+	assert.False(t, ok, "nil cache never returns a verdict")
+}
+
+func TestRenderDecisions_VerdictAfterAgreeingSamples(t *testing.T) {
+	d, _ := newTestDecisions(time.Minute)
+
+	for i := 0; i < decisionSamples; i++ {
+		_, ok := d.Lookup("https://spa.example.com/page")
+		assert.False(t, ok, "host is uncertain before %d samples", decisionSamples)
+		d.Record(fmt.Sprintf("https://spa.example.com/p%d", i), true)
+	}
+
+	needsJS, ok := d.Lookup("https://SPA.example.com/other")
+	assert.True(t, ok)
+	assert.True(t, needsJS)
+
+	_, ok = d.Lookup("https://static.example.com/")
+	assert.False(t, ok, "verdicts are per host")
+}
+
+func TestRenderDecisions_DisagreementResetsVerdict(t *testing.T) {
+	d, _ := newTestDecisions(time.Minute)
+	for i := 0; i < decisionSamples; i++ {
+		d.Record("https://docs.example.com/a", false)
+	}
+	_, ok := d.Lookup("https://docs.example.com/b")
+	assert.True(t, ok)
+
+	d.Record("https://docs.example.com/c", true)
+	_, ok = d.Lookup("https://docs.example.com/d")
+	assert.False(t, ok)
+}
+
+func TestRenderDecisions_ExpiresAfterTTL(t *testing.T) {
+	d, now := newTestDecisions(time.Minute)
+	for i := 0; i < decisionSamples; i++ {
+		d.Record("https://docs.example.com/a", true)
+	}
+
+	*now = now.Add(time.Minute)
+	_, ok := d.Lookup("https://docs.example.com/b")
+	assert.False(t, ok)
+
+	d.Record("https://docs.example.com/c", true)
+	_, ok = d.Lookup("https://docs.example.com/d")
+	assert.False(t, ok, "an expired host needs fresh samples")
+}
+
+func TestRenderDecisions_PeriodicRecheck(t *testing.T) {
+	d, _ := newTestDecisions(time.Hour)
+	for i := 0; i < decisionSamples; i++ {
+		d.Record("https://docs.example.com/a", true)
+	}
+
+	misses := 0
+	for i := 0; i < decisionRecheckEvery*2; i++ {
+		if _, ok := d.Lookup("https://docs.example.com/b"); !ok {
+			misses++
+		}
+	}
+	assert.Equal(t, 2, misses)
+}
+
+func TestRenderDecisions_HostCap(t *testing.T) {
+	d, _ := newTestDecisions(time.Hour)
+	for i := 0; i < maxDecisionHosts; i++ {
+		d.Record(fmt.Sprintf("https://h%d.example.com/", i), true)
+	}
+	for i := 0; i < decisionSamples; i++ {
+		d.Record("https://overflow.example.com/", true)
+	}
+
+	assert.Len(t, d.hosts, maxDecisionHosts)
+	_, ok := d.Lookup("https://overflow.example.com/")
+	assert.False(t, ok)
+}
@@ -3,6 +3,10 @@ package renderer
 import (
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 // SPA detection patterns
@@ -181,3 +185,56 @@ func HasDynamicContent(html string) bool {
 
 	return false
 }
+
+// truncationMinSentence is the length, in characters, from which a final
+// paragraph without closing punctuation counts as cut off mid-sentence;
+// shorter ones are usually captions or labels.
+const truncationMinSentence = 40
+
+// TruncationReason reports why rendered html looks like its content stopped
+// loading partway, or "" when it looks complete. A page looks truncated when
+// its main content still carries loading markers (see HasDynamicContent) or
+// its last paragraph ends mid-sentence. Pages without a main, article or
+// role=main element are only checked for loading markers outside scripts
+// and styles.
+func TruncationReason(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	doc.Find("script, style, noscript, template").Remove()
+
+	content := doc.Find("main, article, [role=main]").First()
+	isMain := content.Length() > 0
+	if !isMain {
+		content = doc.Find("body")
+	}
+	contentHTML, _ := content.Html()
+	if HasDynamicContent(contentHTML) {
+		return "loading markers in content"
+	}
+	if !isMain {
+		return ""
+	}
+
+	var last string
+	content.Find("p").Each(func(_ int, p *goquery.Selection) {
+		if text := strings.TrimSpace(p.Text()); text != "" {
+			last = text
+		}
+	})
+	if endsMidSentence(last) {
+		return "content ends mid-sentence"
+	}
+	return ""
+}
+
+// endsMidSentence reports whether text is a long run of prose whose last
+// character is a letter or a comma.
+func endsMidSentence(text string) bool {
+	if utf8.RuneCountInString(text) < truncationMinSentence {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return unicode.IsLetter(last) || last == ','
+}
@@ -235,6 +235,53 @@ func TestHasDynamicContent(t *testing.T) {
 	}
 }
 
+// TestTruncationReason tests the detection of rendered pages whose content
+// stopped loading partway
+func TestTruncationReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "complete article",
+			html:     `<html><body><main><p>This page explains how the configuration file is loaded at startup.</p></main></body></html>`,
+			expected: "",
+		},
+		{
+			name:     "ends mid-sentence",
+			html:     `<html><body><main><p>Intro.</p><p>The configuration file is loaded at startup and then merged with</p></main></body></html>`,
+			expected: "content ends mid-sentence",
+		},
+		{
+			name:     "short trailing label",
+			html:     `<html><body><main><p>Full sentence here.</p><p>Edit this page</p></main></body></html>`,
+			expected: "",
+		},
+		{
+			name:     "loading marker in content",
+			html:     `<html><body><article><p>Done.</p><div class="spinner"></div></article></body></html>`,
+			expected: "loading markers in content",
+		},
+		{
+			name:     "loading marker only in scripts",
+			html:     `<html><head><script>window.lazyload = true</script></head><body><main><p>Done.</p></main></body></html>`,
+			expected: "",
+		},
+		{
+			name:     "no main content ignores trailing text",
+			html:     `<html><body><p>Copyright Example Corporation and its many affiliates worldwide</p></body></html>`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TruncationReason(tt.html))
+		})
+	}
+}
+
 // TestDefaultRendererOptions tests default renderer options
 func TestDefaultRendererOptions(t *testing.T) {
 	opts := DefaultRendererOptions()
@@ -244,6 +291,7 @@ func TestDefaultRendererOptions(t *testing.T) {
 	assert.True(t, opts.Stealth)
 	assert.True(t, opts.Headless)
 	assert.Empty(t, opts.BrowserPath)
+	assert.Equal(t, DefaultTruncationRetries, opts.TruncationRetries)
 	// NoSandbox depends on environment, so we just check it's a bool
 	assert.IsType(t, false, opts.NoSandbox)
 }
@@ -1037,6 +1085,39 @@ func TestScrollToEnd(t *testing.T) {
 	})
 }
 
+func TestExpandHidden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping browser-dependent test in short mode")
+	}
+
+	r, err := NewRenderer(RendererOptions{
+		Timeout:       60 * time.Second,
+		MaxTabs:       1,
+		Headless:      true,
+		NoSandbox:     true,
+		IncludeHidden: true,
+	})
+	require.NoError(t, err)
+	defer r.Close()
+
+	html := `<!DOCTYPE html><html><body>
+		<details><summary>FAQ</summary><p>Collapsed answer</p></details>
+		<button aria-expanded="false" onclick="document.getElementById('panel').hidden = false; this.setAttribute('aria-expanded', 'true')">More</button>
+		<div id="panel" hidden><p>Accordion body</p></div>
+		<button aria-expanded="false" onclick="this.dataset.clicks = (+this.dataset.clicks || 0) + 1">Stuck</button>
+		<nav><button aria-expanded="false" onclick="throw new Error('menu')">Menu</button></nav>
+	</body></html>`
+
+	rendered, err := r.Render(context.Background(), "data:text/html;base64,"+encodeBase64(html), domain.RenderOptions{
+		Timeout: 30 * time.Second,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, rendered, "<details open")
+	assert.Contains(t, rendered, `<div id="panel">`)
+	assert.Contains(t, rendered, `data-clicks="1"`, "a toggle is clicked once")
+	assert.Contains(t, rendered, `aria-expanded="false" onclick="throw`, "navigation toggles are left alone")
+}
+
 // TestApplyStealthMode tests the ApplyStealthMode function
 func TestApplyStealthMode(t *testing.T) {
 	if testing.Short() {
@@ -13,6 +13,7 @@ import (
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
 const (
@@ -27,8 +28,57 @@ const (
 	// scrollToEndStableThreshold requires repeated unchanged heights so one slow layout tick
 	// does not stop scrolling before client-rendered content appears.
 	scrollToEndStableThreshold = 2
+
+	// DefaultTruncationRetries is the number of extra wait-and-scroll passes
+	// given to a rendered page that looks truncated.
+	DefaultTruncationRetries = 2
+
+	// truncationBaseWait is the network idle time of the first truncation
+	// retry when the render sets no WaitStable; each retry doubles it.
+	truncationBaseWait = 2 * time.Second
+
+	// expandHiddenMaxToggles bounds the toggles opened per pass, so a page
+	// with thousands of collapsed rows cannot stall the render.
+	expandHiddenMaxToggles = 200
+
+	// expandHiddenPasses repeats expansion to open toggles that only appear
+	// once their parent section is expanded.
+	expandHiddenPasses = 2
 )
 
+// expandHiddenScript opens collapsed <details> (through their summary, so
+// script-driven ones expand too) and clicks common accordion toggles, up to
+// limit, returning how many it opened. Toggles in navigation and tab lists,
+// links leaving the page and toggles clicked by an earlier pass (which would
+// collapse again) are left alone.
+const expandHiddenScript = `(limit) => {
+	let opened = 0;
+	const clicked = window.__repodocsExpanded = window.__repodocsExpanded || new WeakSet();
+	const skip = 'nav, header, footer, [role="navigation"], [role="menubar"], [role="tablist"]';
+	for (const details of document.querySelectorAll('details:not([open])')) {
+		if (opened >= limit) break;
+		const summary = details.querySelector(':scope > summary');
+		try { if (summary) summary.click(); } catch (e) {}
+		if (!details.open) details.open = true;
+		opened++;
+	}
+	const toggles = document.querySelectorAll(
+		'button[aria-expanded="false"], [role="button"][aria-expanded="false"], ' +
+		'.accordion-button.collapsed, [data-toggle="collapse"].collapsed, [data-bs-toggle="collapse"].collapsed');
+	for (const toggle of toggles) {
+		if (opened >= limit) break;
+		if (clicked.has(toggle) || toggle.closest(skip)) continue;
+		const href = toggle.getAttribute('href');
+		if (toggle.tagName === 'A' && href && !href.startsWith('#')) continue;
+		clicked.add(toggle);
+		try { toggle.click(); opened++; } catch (e) {}
+	}
+	for (const panel of document.querySelectorAll('.collapse:not(.show)')) {
+		if (!panel.closest(skip)) panel.classList.add('show');
+	}
+	return opened;
+}`
+
 // Renderer provides JavaScript rendering using headless Chrome
 type Renderer struct {
 	browser  *rod.Browser
@@ -36,6 +86,13 @@ type Renderer struct {
 	timeout  time.Duration
 	stealth  bool
 	headless bool
+	// userAgents rotates the User-Agent per render; nil keeps the browser's.
+	userAgents *utils.UserAgentRotator
+	// truncationRetries bounds the extra passes for truncated-looking pages.
+	truncationRetries int
+	// includeHidden expands collapsed content before capturing each page.
+	includeHidden bool
+	logger        *utils.Logger
 	// ownsBrowser is false when the renderer connected to an externally managed
 	// CDP browser (a sidecar). In that case Close must not terminate the browser.
 	ownsBrowser bool
@@ -62,6 +119,24 @@ type RendererOptions struct {
 	// Headless, NoSandbox and the local Chrome launch flags are not applied, and
 	// the sidecar is left running when the renderer is closed.
 	CDPEndpoint string
+	// ExtraArgs are additional Chrome launch flags ("--name" or "--name=value"),
+	// e.g. "--lang=de-DE" or "--window-size=1920,1080". They only apply when
+	// repodocs launches the browser and are ignored with CDPEndpoint. Flags the
+	// renderer manages itself (debugging port, profile dir, headless, sandbox,
+	// proxy) are rejected.
+	ExtraArgs []string
+	// UserAgents, when set, are rotated per rendered page in place of the
+	// browser's own User-Agent.
+	UserAgents []string
+	// TruncationRetries is the number of extra passes, each waiting twice as
+	// long for the network to go idle and scrolling again, given to a page
+	// that looks truncated (see TruncationReason). 0 disables the retries.
+	TruncationRetries int
+	// IncludeHidden expands collapsed <details> and accordions before the
+	// HTML is captured (see Renderer.expandHidden).
+	IncludeHidden bool
+	// Logger receives the pages suspected to be truncated.
+	Logger *utils.Logger
 }
 
 // DefaultRendererOptions returns default renderer options
@@ -73,6 +148,8 @@ func DefaultRendererOptions() RendererOptions {
 		Headless:    true,
 		BrowserPath: "",
 		NoSandbox:   isCI(), // Auto-detect CI environment
+
+		TruncationRetries: DefaultTruncationRetries,
 	}
 }
 
@@ -112,7 +189,12 @@ func NewRenderer(opts RendererOptions) (*Renderer, error) {
 		timeout:     opts.Timeout,
 		stealth:     opts.Stealth,
 		headless:    opts.Headless,
+		userAgents:  utils.NewUserAgentRotator(opts.UserAgents),
 		ownsBrowser: ownsBrowser,
+
+		truncationRetries: opts.TruncationRetries,
+		includeHidden:     opts.IncludeHidden,
+		logger:            opts.Logger,
 	}, nil
 }
 
@@ -142,6 +224,11 @@ func connectBrowser(opts RendererOptions) (*rod.Browser, bool, error) {
 		return nil, false, err
 	}
 
+	extraArgs, err := parseChromeArgs(opts.ExtraArgs)
+	if err != nil {
+		return nil, false, err
+	}
+
 	// Create launcher
 	l := launcher.New()
 
@@ -169,6 +256,8 @@ func connectBrowser(opts RendererOptions) (*rod.Browser, bool, error) {
 		l = l.Proxy(proxy.server)
 	}
 
+	l = applyChromeArgs(l, extraArgs)
+
 	// Launch browser
 	controlURL, err := l.Launch()
 	if err != nil {
@@ -217,6 +306,13 @@ func (r *Renderer) Render(ctx context.Context, url string, opts domain.RenderOpt
 		}
 	}
 
+	// Rotate the User-Agent; pooled tabs keep the previous override otherwise
+	if userAgent := r.userAgents.Next(); userAgent != "" {
+		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{UserAgent: userAgent}); err != nil {
+			return "", fmt.Errorf("failed to set user agent: %w", err)
+		}
+	}
+
 	// Set cookies if provided
 	if len(opts.Cookies) > 0 {
 		if err := r.setCookies(page, url, opts.Cookies); err != nil {
@@ -248,6 +344,11 @@ func (r *Renderer) Render(ctx context.Context, url string, opts domain.RenderOpt
 		}
 	}
 
+	// Expand collapsed sections so their content is in the captured HTML
+	if opts.IncludeHidden || r.includeHidden {
+		r.expandHidden(page, url)
+	}
+
 	// Scroll to bottom to load lazy content
 	if opts.ScrollToEnd {
 		if err := r.scrollToEnd(page); err != nil {
@@ -261,7 +362,57 @@ func (r *Renderer) Render(ctx context.Context, url string, opts domain.RenderOpt
 		return "", fmt.Errorf("failed to get HTML: %w", err)
 	}
 
-	return html, nil
+	return r.completeTruncated(page, url, html, opts), nil
+}
+
+// completeTruncated gives a page whose html looks truncated (see
+// TruncationReason) up to truncationRetries more passes of waiting for the
+// network to go idle and scrolling to the end, each waiting twice as long as
+// the last, and returns the latest HTML. A pass that leaves the HTML
+// unchanged ends the retries.
+func (r *Renderer) completeTruncated(page *rod.Page, url, html string, opts domain.RenderOptions) string {
+	reason := TruncationReason(html)
+	if reason == "" {
+		return html
+	}
+	if r.logger != nil {
+		r.logger.Info().Str("url", url).Str("reason", reason).Msg("Rendered page looks truncated")
+	}
+
+	wait := opts.WaitStable
+	if wait <= 0 {
+		wait = truncationBaseWait
+	}
+	for attempt := 1; attempt <= r.truncationRetries && reason != ""; attempt++ {
+		wait *= 2
+		waitRequestIdle(page, wait)
+		_ = r.scrollToEnd(page)
+
+		retried, err := page.HTML()
+		if err != nil || retried == html {
+			break
+		}
+		html = retried
+		reason = TruncationReason(html)
+		if r.logger != nil {
+			r.logger.Debug().Str("url", url).Int("attempt", attempt).Dur("wait", wait).
+				Bool("truncated", reason != "").Msg("Re-rendered truncated page")
+		}
+	}
+
+	if reason != "" && r.logger != nil {
+		r.logger.Warn().Str("url", url).Str("reason", reason).Msg("Rendered page may be incomplete")
+	}
+	return html
+}
+
+// waitRequestIdle waits until the page made no network request for idle.
+// Pages that keep polling never go idle, so the wait gives up after twice
+// idle, or earlier when the render times out.
+func waitRequestIdle(page *rod.Page, idle time.Duration) {
+	bounded := page.Timeout(2 * idle)
+	defer bounded.CancelTimeout()
+	bounded.WaitRequestIdle(idle, nil, nil, nil)()
 }
 
 // setCookies sets cookies on a page
@@ -340,6 +491,33 @@ func (r *Renderer) scrollToEnd(page *rod.Page) error {
 	return nil
 }
 
+// expandHidden opens collapsed <details> and accordion toggles, in up to
+// expandHiddenPasses passes that each let the page settle, and returns how
+// many it opened. It is best-effort: a script error ends the expansion and
+// the page is captured as it is.
+func (r *Renderer) expandHidden(page *rod.Page, url string) int {
+	total := 0
+	for pass := 0; pass < expandHiddenPasses; pass++ {
+		result, err := page.Eval(expandHiddenScript, expandHiddenMaxToggles)
+		if err != nil {
+			if r.logger != nil {
+				r.logger.Debug().Err(err).Str("url", url).Msg("Expanding hidden content failed")
+			}
+			break
+		}
+		opened := result.Value.Int()
+		if opened == 0 {
+			break
+		}
+		total += opened
+		time.Sleep(scrollToEndPause)
+	}
+	if total > 0 && r.logger != nil {
+		r.logger.Debug().Str("url", url).Int("opened", total).Msg("Expanded hidden content")
+	}
+	return total
+}
+
 // DefaultRenderOptions returns default render options
 func DefaultRenderOptions() domain.RenderOptions {
 	return domain.RenderOptions{
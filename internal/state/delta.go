@@ -0,0 +1,81 @@
+package state
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Change classifies a page of the current run against the stored state.
+type Change string
+
+const (
+	ChangeNew       Change = "new"
+	ChangeChanged   Change = "changed"
+	ChangeUnchanged Change = "unchanged"
+)
+
+// Delta lists the pages of a run by how they compare with the stored state.
+// Deleted holds stored pages that were not seen during the run.
+type Delta struct {
+	New       []string
+	Changed   []string
+	Unchanged []string
+	Deleted   []string
+}
+
+// Total returns the number of pages in the delta.
+func (d *Delta) Total() int {
+	return len(d.New) + len(d.Changed) + len(d.Unchanged) + len(d.Deleted)
+}
+
+// Format writes a report of the delta to w: a summary line followed by the
+// new, changed and deleted pages. Unchanged pages are only counted.
+func (d *Delta) Format(w io.Writer) {
+	fmt.Fprintf(w, "Incremental delta: %d new, %d changed, %d unchanged, %d deleted\n",
+		len(d.New), len(d.Changed), len(d.Unchanged), len(d.Deleted))
+	for _, group := range []struct {
+		label string
+		urls  []string
+	}{
+		{"new", d.New},
+		{"changed", d.Changed},
+		{"deleted", d.Deleted},
+	} {
+		for _, url := range group.urls {
+			fmt.Fprintf(w, "  %-8s %s\n", group.label, url)
+		}
+	}
+}
+
+// Delta returns how the pages checked with ShouldProcess during this run
+// compare with the stored state. It does not modify the state.
+func (m *Manager) Delta() *Delta {
+	delta := &Delta{}
+	m.changes.Range(func(key, value any) bool {
+		url := key.(string)
+		switch value.(Change) {
+		case ChangeNew:
+			delta.New = append(delta.New, url)
+		case ChangeChanged:
+			delta.Changed = append(delta.Changed, url)
+		case ChangeUnchanged:
+			delta.Unchanged = append(delta.Unchanged, url)
+		}
+		return true
+	})
+
+	m.mu.RLock()
+	for url := range m.state.Pages {
+		if _, seen := m.seenURLs.Load(url); !seen {
+			delta.Deleted = append(delta.Deleted, url)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(delta.New)
+	sort.Strings(delta.Changed)
+	sort.Strings(delta.Unchanged)
+	sort.Strings(delta.Deleted)
+	return delta
+}
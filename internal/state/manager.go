@@ -23,6 +23,7 @@ type Manager struct {
 	logger   *utils.Logger
 	disabled bool
 	seenURLs sync.Map
+	changes  sync.Map // URL -> Change, for Delta
 }
 
 // ManagerOptions configures sync-state storage, source identity, logging, and disabled mode.
@@ -32,15 +33,22 @@ type ManagerOptions struct {
 	Strategy  string
 	Logger    *utils.Logger
 	Disabled  bool
+	// HashAlgorithm is the digest of the content hashes passed to
+	// ShouldProcess and Update (empty means sha256).
+	HashAlgorithm string
 }
 
 // NewManager creates a sync-state manager initialized for the configured source.
 func NewManager(opts ManagerOptions) *Manager {
+	state := NewSyncState(opts.SourceURL, opts.Strategy)
+	if opts.HashAlgorithm != "" {
+		state.HashAlgorithm = opts.HashAlgorithm
+	}
 	return &Manager{
 		baseDir:  opts.BaseDir,
 		logger:   opts.Logger,
 		disabled: opts.Disabled,
-		state:    NewSyncState(opts.SourceURL, opts.Strategy),
+		state:    state,
 	}
 }
 
@@ -77,6 +85,24 @@ func (m *Manager) Load(ctx context.Context) error {
 		return ErrVersionMismatch
 	}
 
+	// Hashes of another algorithm can never match: keep the pages (so
+	// deletions are still detected) but drop their hashes, which makes every
+	// page process once and be re-recorded with the configured algorithm.
+	if want := m.state.hashAlgorithm(); state.hashAlgorithm() != want {
+		if m.logger != nil {
+			m.logger.Info().
+				Str("file_algorithm", state.hashAlgorithm()).
+				Str("algorithm", want).
+				Msg("Content hash algorithm changed, migrating state")
+		}
+		for url, page := range state.Pages {
+			page.ContentHash = ""
+			state.Pages[url] = page
+		}
+		state.HashAlgorithm = want
+		m.dirty = true
+	}
+
 	m.state = &state
 	return nil
 }
@@ -127,14 +153,20 @@ func (m *Manager) ShouldProcess(url, contentHash string) bool {
 	}
 
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
 	page, exists := m.state.Pages[url]
-	if !exists {
+	m.mu.RUnlock()
+
+	switch {
+	case !exists:
+		m.changes.Store(url, ChangeNew)
+		return true
+	case page.ContentHash != contentHash:
+		m.changes.Store(url, ChangeChanged)
 		return true
+	default:
+		m.changes.Store(url, ChangeUnchanged)
+		return false
 	}
-
-	return page.ContentHash != contentHash
 }
 
 // Update stores page state for url and marks the manager dirty.
@@ -150,6 +182,18 @@ func (m *Manager) Update(url string, page PageState) {
 	m.dirty = true
 }
 
+// Pages returns a copy of the recorded pages by URL.
+func (m *Manager) Pages() map[string]PageState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pages := make(map[string]PageState, len(m.state.Pages))
+	for url, page := range m.state.Pages {
+		pages[url] = page
+	}
+	return pages
+}
+
 // MarkSeen records that url was observed during the current sync run.
 func (m *Manager) MarkSeen(url string) {
 	m.seenURLs.Store(url, true)
@@ -173,6 +217,21 @@ func (m *Manager) GetDeletedPages() []PageState {
 	return deleted
 }
 
+// SeenFilePaths returns the file paths of pages seen during the current sync
+// run, which must survive pruning even when a deleted page shared the path.
+func (m *Manager) SeenFilePaths() map[string]bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paths := make(map[string]bool)
+	for url, page := range m.state.Pages {
+		if _, seen := m.seenURLs.Load(url); seen && page.FilePath != "" {
+			paths[page.FilePath] = true
+		}
+	}
+	return paths
+}
+
 // RemoveDeletedFromState removes unseen pages from the persisted state and marks it dirty.
 func (m *Manager) RemoveDeletedFromState() {
 	if m.disabled {
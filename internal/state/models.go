@@ -7,13 +7,19 @@ const StateVersion = 1
 
 // SyncState represents the complete synchronization state for a source
 type SyncState struct {
-	Version   int                  `json:"version"`
-	SourceURL string               `json:"source_url"`
-	Strategy  string               `json:"strategy,omitempty"`
-	LastSync  time.Time            `json:"last_sync"`
-	Pages     map[string]PageState `json:"pages"`
+	Version   int    `json:"version"`
+	SourceURL string `json:"source_url"`
+	Strategy  string `json:"strategy,omitempty"`
+	// HashAlgorithm is the digest of every page's ContentHash. Empty (state
+	// files written before it was configurable) means sha256.
+	HashAlgorithm string               `json:"hash_algorithm,omitempty"`
+	LastSync      time.Time            `json:"last_sync"`
+	Pages         map[string]PageState `json:"pages"`
 }
 
+// DefaultHashAlgorithm is the content hash digest assumed when none is set
+const DefaultHashAlgorithm = "sha256"
+
 // PageState represents the state of an individual processed page
 type PageState struct {
 	ContentHash string    `json:"content_hash"`
@@ -24,12 +30,21 @@ type PageState struct {
 // NewSyncState creates a new empty sync state
 func NewSyncState(sourceURL, strategy string) *SyncState {
 	return &SyncState{
-		Version:   StateVersion,
-		SourceURL: sourceURL,
-		Strategy:  strategy,
-		LastSync:  time.Now(),
-		Pages:     make(map[string]PageState),
+		Version:       StateVersion,
+		SourceURL:     sourceURL,
+		Strategy:      strategy,
+		HashAlgorithm: DefaultHashAlgorithm,
+		LastSync:      time.Now(),
+		Pages:         make(map[string]PageState),
+	}
+}
+
+// hashAlgorithm returns the state's hash algorithm, defaulting to sha256
+func (s *SyncState) hashAlgorithm() string {
+	if s.HashAlgorithm == "" {
+		return DefaultHashAlgorithm
 	}
+	return s.HashAlgorithm
 }
 
 // PageCount returns the number of pages in the state
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -148,6 +149,57 @@ func TestManager_Load_VersionMismatch(t *testing.T) {
 	assert.ErrorIs(t, err, state.ErrVersionMismatch)
 }
 
+func TestManager_Load_HashAlgorithm(t *testing.T) {
+	// State files written before hash_algorithm existed hold sha256 hashes.
+	writeLegacy := func(t *testing.T, dir string) {
+		data, err := json.Marshal(map[string]interface{}{
+			"version":    state.StateVersion,
+			"source_url": "https://example.com",
+			"last_sync":  time.Now(),
+			"pages": map[string]interface{}{
+				"https://example.com/page1": map[string]interface{}{
+					"content_hash": "sha256hash",
+					"file_path":    "page1.md",
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, state.StateFileName), data, 0644))
+	}
+
+	t.Run("legacy state is read as sha256", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeLegacy(t, tmpDir)
+
+		manager := state.NewManager(state.ManagerOptions{BaseDir: tmpDir, HashAlgorithm: "sha256"})
+		require.NoError(t, manager.Load(context.Background()))
+
+		assert.False(t, manager.ShouldProcess("https://example.com/page1", "sha256hash"))
+	})
+
+	t.Run("changed algorithm migrates state", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeLegacy(t, tmpDir)
+
+		manager := state.NewManager(state.ManagerOptions{BaseDir: tmpDir, HashAlgorithm: "blake3"})
+		require.NoError(t, manager.Load(context.Background()))
+
+		total, _ := manager.Stats()
+		assert.Equal(t, 1, total, "pages are kept for deletion detection")
+		assert.True(t, manager.ShouldProcess("https://example.com/page1", "sha256hash"),
+			"hashes of the old algorithm no longer match")
+
+		require.NoError(t, manager.Save(context.Background()))
+		data, err := os.ReadFile(filepath.Join(tmpDir, state.StateFileName))
+		require.NoError(t, err)
+
+		var saved state.SyncState
+		require.NoError(t, json.Unmarshal(data, &saved))
+		assert.Equal(t, "blake3", saved.HashAlgorithm)
+		assert.Empty(t, saved.Pages["https://example.com/page1"].ContentHash)
+	})
+}
+
 func TestManager_Save_Disabled(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -316,6 +368,18 @@ func TestManager_Update_AddsPage(t *testing.T) {
 	assert.Equal(t, 1, total)
 }
 
+func TestManager_Pages(t *testing.T) {
+	manager := state.NewManager(state.ManagerOptions{BaseDir: t.TempDir()})
+	page := state.PageState{ContentHash: "hash123", FilePath: "page1.md"}
+	manager.Update("https://example.com/page1", page)
+
+	pages := manager.Pages()
+	assert.Equal(t, map[string]state.PageState{"https://example.com/page1": page}, pages)
+
+	delete(pages, "https://example.com/page1")
+	assert.Len(t, manager.Pages(), 1, "the copy does not alias the state")
+}
+
 func TestManager_Update_UpdatesExistingPage(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -621,6 +685,7 @@ func TestNewSyncState(t *testing.T) {
 	assert.Equal(t, state.StateVersion, syncState.Version)
 	assert.Equal(t, "https://example.com", syncState.SourceURL)
 	assert.Equal(t, "crawler", syncState.Strategy)
+	assert.Equal(t, state.DefaultHashAlgorithm, syncState.HashAlgorithm)
 	assert.NotNil(t, syncState.Pages)
 	assert.Empty(t, syncState.Pages)
 	assert.False(t, syncState.LastSync.IsZero())
@@ -828,3 +893,32 @@ func BenchmarkManager_Load(b *testing.B) {
 		newManager.Load(context.Background())
 	}
 }
+
+func TestManager_Delta(t *testing.T) {
+	manager := state.NewManager(state.ManagerOptions{BaseDir: t.TempDir()})
+	manager.Update("https://example.com/same", state.PageState{ContentHash: "h1"})
+	manager.Update("https://example.com/edited", state.PageState{ContentHash: "h2"})
+	manager.Update("https://example.com/gone", state.PageState{ContentHash: "h3"})
+
+	for url, hash := range map[string]string{
+		"https://example.com/same":   "h1",
+		"https://example.com/edited": "h2-new",
+		"https://example.com/added":  "h4",
+	} {
+		manager.MarkSeen(url)
+		manager.ShouldProcess(url, hash)
+	}
+
+	delta := manager.Delta()
+	assert.Equal(t, []string{"https://example.com/added"}, delta.New)
+	assert.Equal(t, []string{"https://example.com/edited"}, delta.Changed)
+	assert.Equal(t, []string{"https://example.com/same"}, delta.Unchanged)
+	assert.Equal(t, []string{"https://example.com/gone"}, delta.Deleted)
+	assert.Equal(t, 4, delta.Total())
+
+	var out strings.Builder
+	delta.Format(&out)
+	assert.Contains(t, out.String(), "1 new, 1 changed, 1 unchanged, 1 deleted")
+	assert.Contains(t, out.String(), "changed  https://example.com/edited")
+	assert.NotContains(t, out.String(), "example.com/same\n")
+}
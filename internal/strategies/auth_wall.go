@@ -0,0 +1,138 @@
+package strategies
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+const (
+	// authWallFormMaxChars is the body text length below which a page with
+	// a password field is taken for a login form rather than documentation.
+	authWallFormMaxChars = 3000
+	// authWallStubMaxChars is the body text length below which a page
+	// mentioning signing in or subscribing is taken for an auth wall stub.
+	authWallStubMaxChars = 600
+)
+
+// errAuthWallRedirect aborts a crawler request redirected to a login page.
+var errAuthWallRedirect = errors.New("redirected to a login page")
+
+// loginPathSegments are URL path segments of sign-in pages.
+var loginPathSegments = map[string]bool{
+	"login":   true,
+	"log-in":  true,
+	"signin":  true,
+	"sign-in": true,
+	"sign_in": true,
+	"sso":     true,
+	"session": true,
+	"auth":    true,
+}
+
+// authWallPhrases are phrases of login walls and paywall stubs.
+var authWallPhrases = []string{
+	"sign in",
+	"log in",
+	"login required",
+	"please log in",
+	"you must be logged in",
+	"you need to sign in",
+	"members only",
+	"subscribers only",
+	"subscribe to continue",
+	"subscribe to read",
+	"create an account",
+	"access this content",
+}
+
+// isLoginURL reports whether rawURL looks like a sign-in page, such as
+// /login or /users/sign_in.
+func isLoginURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, segment := range strings.Split(strings.ToLower(parsed.Path), "/") {
+		if loginPathSegments[segment] {
+			return true
+		}
+	}
+	return false
+}
+
+// detectAuthWall returns why the page requested as pageURL looks like a login
+// wall or paywall stub rather than documentation, or "" when it does not.
+// finalURL is the URL it was served from after redirects (empty when not
+// redirected); html may be empty to check only the redirect.
+func detectAuthWall(pageURL, finalURL, html string) string {
+	if finalURL != "" && finalURL != pageURL && isLoginURL(finalURL) && !isLoginURL(pageURL) {
+		return "redirected to a login page"
+	}
+	if html == "" {
+		return ""
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	hasPassword := doc.Find(`input[type="password" i]`).Length() > 0
+	doc.Find("script, style, noscript").Remove()
+	text := strings.Join(strings.Fields(doc.Find("body").Text()), " ")
+	chars := utf8.RuneCountInString(text)
+
+	if hasPassword && chars < authWallFormMaxChars {
+		return "login form"
+	}
+	if chars < authWallStubMaxChars {
+		lower := strings.ToLower(text)
+		for _, phrase := range authWallPhrases {
+			if strings.Contains(lower, phrase) {
+				return "sign-in or subscription prompt"
+			}
+		}
+	}
+	return ""
+}
+
+// SkipAuthWall reports whether the page requested as pageURL should be
+// skipped as a login wall or paywall stub (see detectAuthWall). It always
+// returns false unless auth wall detection is enabled. Skipped pages are
+// counted in result.AuthWalls, not as failures.
+func (d *Dependencies) SkipAuthWall(result *domain.StrategyResult, pageURL, finalURL, html string) bool {
+	if d == nil || !d.detectAuthWalls {
+		return false
+	}
+	reason := detectAuthWall(pageURL, finalURL, html)
+	if reason == "" {
+		return false
+	}
+	d.recordAuthWall(result, pageURL, reason)
+	return true
+}
+
+// recordAuthWall counts and logs a page skipped as an auth wall.
+func (d *Dependencies) recordAuthWall(result *domain.StrategyResult, pageURL, reason string) {
+	result.IncAuthWall()
+	if d.Logger != nil {
+		d.Logger.Info().
+			Str("url", pageURL).
+			Str("reason", reason).
+			Msg("Skipping page behind a login or paywall")
+	}
+}
+
+// authWallRedirectHandler stops crawler redirects to login pages with
+// errAuthWallRedirect.
+func authWallRedirectHandler(req *http.Request, via []*http.Request) error {
+	if len(via) > 0 && isLoginURL(req.URL.String()) && !isLoginURL(via[0].URL.String()) {
+		return errAuthWallRedirect
+	}
+	return nil
+}
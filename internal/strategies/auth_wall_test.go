@@ -0,0 +1,137 @@
+package strategies
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLoginURL(t *testing.T) {
+	assert.True(t, isLoginURL("https://example.com/login"))
+	assert.True(t, isLoginURL("https://example.com/users/sign_in?next=/docs"))
+	assert.True(t, isLoginURL("https://example.com/Auth/"))
+	assert.False(t, isLoginURL("https://example.com/docs/authentication"))
+	assert.False(t, isLoginURL("https://example.com/docs/login-flows"))
+}
+
+func TestDetectAuthWall(t *testing.T) {
+	longText := strings.Repeat("Configure the client before sending requests. ", 100)
+
+	tests := []struct {
+		name     string
+		pageURL  string
+		finalURL string
+		html     string
+		want     string
+	}{
+		{
+			name:     "redirect to login",
+			pageURL:  "https://example.com/docs/guide",
+			finalURL: "https://example.com/login?next=/docs/guide",
+			want:     "redirected to a login page",
+		},
+		{
+			name:     "login page requested directly",
+			pageURL:  "https://example.com/login",
+			finalURL: "https://example.com/login/",
+		},
+		{
+			name:    "login form",
+			pageURL: "https://example.com/docs/guide",
+			html:    `<html><body><form><input name="user"><input type="password" name="pw"></form></body></html>`,
+			want:    "login form",
+		},
+		{
+			name:    "paywall stub",
+			pageURL: "https://example.com/docs/guide",
+			html:    `<html><body><p>This article is for subscribers only. Subscribe to continue reading.</p></body></html>`,
+			want:    "sign-in or subscription prompt",
+		},
+		{
+			name:    "long page mentioning sign in",
+			pageURL: "https://example.com/docs/guide",
+			html:    `<html><body><p>` + longText + ` Sign in with your API key.</p></body></html>`,
+		},
+		{
+			name:    "long page with password field",
+			pageURL: "https://example.com/docs/guide",
+			html:    `<html><body><p>` + longText + `</p><input type="password"></body></html>`,
+		},
+		{
+			name:    "documentation",
+			pageURL: "https://example.com/docs/guide",
+			html:    `<html><body><h1>Guide</h1><p>Install the package.</p></body></html>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectAuthWall(tt.pageURL, tt.finalURL, tt.html))
+		})
+	}
+}
+
+func TestDependencies_SkipAuthWall(t *testing.T) {
+	html := `<html><body><p>Please log in to access this content.</p></body></html>`
+
+	disabled := &Dependencies{}
+	result := disabled.NewResult("crawler", "https://example.com")
+	assert.False(t, disabled.SkipAuthWall(result, "https://example.com/docs", "", html))
+	assert.Zero(t, result.Snapshot().AuthWalls)
+
+	enabled := &Dependencies{detectAuthWalls: true}
+	result = enabled.NewResult("crawler", "https://example.com")
+	assert.True(t, enabled.SkipAuthWall(result, "https://example.com/docs", "", html))
+	assert.False(t, enabled.SkipAuthWall(result, "https://example.com/docs/ok", "", `<html><body><h1>API</h1></body></html>`))
+	snap := result.Snapshot()
+	assert.Equal(t, 1, snap.AuthWalls)
+	assert.Zero(t, snap.DocsFailed, "auth walls are not failures")
+}
+
+func TestCrawlerStrategy_Execute_SkipsAuthWalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<html><body><h1>Docs</h1><a href="/private">Private</a><a href="/members">Members</a></body></html>`))
+		case "/private":
+			http.Redirect(w, r, "/login?next=/private", http.StatusFound)
+		case "/members":
+			w.Write([]byte(`<html><body><p>Members only. Sign in to continue.</p></body></html>`))
+		case "/login":
+			w.Write([]byte(`<html><body><form><input type="password"></form></body></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:         5 * time.Second,
+		Concurrency:     1,
+		OutputDir:       t.TempDir(),
+		Flat:            true,
+		DetectAuthWalls: true,
+		CommonOptions: domain.CommonOptions{
+			DryRun: true,
+		},
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	result, err := NewCrawlerStrategy(deps).Execute(context.Background(), server.URL+"/", Options{
+		CommonOptions: domain.CommonOptions{DryRun: true},
+		Concurrency:   1,
+		MaxDepth:      2,
+	})
+	require.NoError(t, err)
+	snap := result.Snapshot()
+	assert.Equal(t, 2, snap.AuthWalls)
+	assert.Zero(t, snap.DocsFailed)
+}
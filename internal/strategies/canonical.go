@@ -0,0 +1,67 @@
+package strategies
+
+import (
+	"sync"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// canonicalSet records the canonical URL of every page processed over the
+// lifetime of a Dependencies set, so a page reachable under several URLs
+// (query variants, aliases, mirrors) is written once.
+type canonicalSet struct {
+	mu    sync.Mutex
+	pages map[string]string // canonical key → first page URL
+}
+
+func newCanonicalSet() *canonicalSet {
+	return &canonicalSet{pages: make(map[string]string)}
+}
+
+// claim records the canonical URL of pageURL and returns the page that
+// claimed it first, or "" when pageURL is the first (or the same page seen
+// again).
+func (c *canonicalSet) claim(canonicalURL, pageURL string) string {
+	key, err := utils.NormalizeURL(canonicalURL)
+	if err != nil {
+		key = canonicalURL
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if first, ok := c.pages[key]; ok && first != pageURL {
+		return first
+	}
+	c.pages[key] = pageURL
+	return ""
+}
+
+// SkipDuplicateCanonical reports whether doc should be skipped because a page
+// with the same canonical URL was already processed. The canonical URL is
+// doc.CanonicalURL when the page declares one and doc.URL otherwise, so both
+// a page and its aliases pointing at it are caught. Skipped pages are
+// counted in result as skipped.
+func (d *Dependencies) SkipDuplicateCanonical(result *domain.StrategyResult, doc *domain.Document) bool {
+	if d == nil || d.canonicals == nil || doc == nil {
+		return false
+	}
+	canonicalURL := doc.CanonicalURL
+	if canonicalURL == "" {
+		canonicalURL = doc.URL
+	}
+	first := d.canonicals.claim(canonicalURL, doc.URL)
+	if first == "" {
+		return false
+	}
+
+	result.IncSkipped()
+	if d.Logger != nil {
+		d.Logger.Debug().
+			Str("url", doc.URL).
+			Str("canonical", canonicalURL).
+			Str("duplicate_of", first).
+			Msg("Skipping page with an already processed canonical URL")
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package strategies
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencies_SkipDuplicateCanonical(t *testing.T) {
+	deps := &Dependencies{canonicals: newCanonicalSet()}
+	result := deps.NewResult("crawler", "https://example.com")
+
+	page := &domain.Document{URL: "https://example.com/docs/guide"}
+	alias := &domain.Document{URL: "https://example.com/docs/guide?ref=nav", CanonicalURL: "https://EXAMPLE.com/docs/guide"}
+	other := &domain.Document{URL: "https://example.com/docs/other", CanonicalURL: "https://example.com/docs/other"}
+
+	assert.False(t, deps.SkipDuplicateCanonical(result, page))
+	assert.True(t, deps.SkipDuplicateCanonical(result, alias))
+	assert.False(t, deps.SkipDuplicateCanonical(result, other))
+	assert.False(t, deps.SkipDuplicateCanonical(result, page), "the same page is not its own duplicate")
+	assert.Equal(t, 1, result.Snapshot().DocsSkipped)
+
+	var nilDeps *Dependencies
+	assert.False(t, nilDeps.SkipDuplicateCanonical(result, page))
+}
+
+func TestCrawlerStrategy_Execute_DedupsByCanonicalURL(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<html><body><a href="/guide">Guide</a><a href="/latest/guide">Latest</a></body></html>`))
+		case "/guide", "/latest/guide":
+			w.Write([]byte(`<html><head><link rel="canonical" href="` + server.URL + `/guide"></head>` +
+				`<body><h1>Guide</h1><p>Install the package and configure it.</p></body></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:     5 * time.Second,
+		Concurrency: 1,
+		OutputDir:   t.TempDir(),
+		Flat:        true,
+		CommonOptions: domain.CommonOptions{
+			DryRun: true,
+		},
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	result, err := NewCrawlerStrategy(deps).Execute(context.Background(), server.URL+"/", Options{
+		CommonOptions: domain.CommonOptions{DryRun: true},
+		Concurrency:   1,
+		MaxDepth:      2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Snapshot().DocsSkipped, "one of the two guide URLs is a duplicate")
+}
@@ -2,7 +2,9 @@ package strategies
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
 	"sync"
@@ -15,7 +17,6 @@ import (
 	"github.com/quantmind-br/repodocs/internal/domain"
 	"github.com/quantmind-br/repodocs/internal/fetcher"
 	"github.com/quantmind-br/repodocs/internal/output"
-	"github.com/quantmind-br/repodocs/internal/renderer"
 	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
@@ -61,7 +62,7 @@ func newCrawlContext(ctx context.Context, baseURL string, opts Options, result *
 		visited:        &sync.Map{},
 		processedCount: &processedCount,
 		mu:             &sync.Mutex{},
-		bar:            utils.NewProgressBar(-1, utils.DescExtracting),
+		bar:            newProgressBar(opts, -1, utils.DescExtracting),
 		barMu:          &sync.Mutex{},
 		excludeRegexps: excludeRegexps,
 		result:         result,
@@ -94,6 +95,11 @@ func (s *CrawlerStrategy) shouldProcessURL(link, baseURL string, cctx *crawlCont
 	}
 	cctx.mu.Unlock()
 
+	if s.deps.HostExhausted(link) {
+		s.logger.Debug().Str("url", link).Msg("Dropping queued URL: host page cap reached")
+		return false
+	}
+
 	if _, exists := cctx.visited.LoadOrStore(link, true); exists {
 		return false
 	}
@@ -114,11 +120,14 @@ func (s *CrawlerStrategy) processMarkdownResponse(body []byte, url string) (*dom
 	return doc, nil
 }
 
-func (s *CrawlerStrategy) processHTMLResponse(ctx context.Context, body []byte, url string, opts Options) (*domain.Document, error) {
+// processHTMLResponse converts an HTML page, rendering it with JavaScript
+// first when required. rendered marks a body that already is the rendered
+// page.
+func (s *CrawlerStrategy) processHTMLResponse(ctx context.Context, body []byte, url string, rendered bool, opts Options) (*domain.Document, error) {
 	html := string(body)
 
-	renderedWithJS := false
-	if opts.RenderJS || renderer.NeedsJSRendering(html) {
+	renderedWithJS := rendered
+	if !rendered && (opts.RenderJS || s.deps.NeedsJSRendering(url, html)) {
 		if r, err := s.deps.GetRenderer(); err == nil {
 			s.renderer = r
 			rendered, err := s.renderer.Render(ctx, url, domain.RenderOptions{
@@ -135,7 +144,6 @@ func (s *CrawlerStrategy) processHTMLResponse(ctx context.Context, body []byte,
 
 	doc, err := s.converter.Convert(ctx, html, url)
 	if err != nil {
-		s.logger.Warn().Err(err).Str("url", url).Msg("Failed to convert page")
 		return nil, err
 	}
 
@@ -145,14 +153,19 @@ func (s *CrawlerStrategy) processHTMLResponse(ctx context.Context, body []byte,
 }
 
 func (s *CrawlerStrategy) processResponse(ctx context.Context, r *colly.Response, cctx *crawlContext) {
+	s.processPage(ctx, r.Request.URL.String(), r.Headers.Get("Content-Type"), r.Body, false, cctx)
+}
+
+// processPage converts and writes one fetched HTML or markdown page. rendered
+// marks an HTML body already rendered with JavaScript.
+func (s *CrawlerStrategy) processPage(ctx context.Context, currentURL, contentTypeHeader string, body []byte, rendered bool, cctx *crawlContext) {
 	select {
 	case <-ctx.Done():
 		return
 	default:
 	}
 
-	contentType := r.Headers.Get("Content-Type")
-	currentURL := r.Request.URL.String()
+	contentType := s.deps.ResolveContentType(contentTypeHeader, currentURL, body)
 	isMarkdown := converter.IsMarkdownContent(contentType, currentURL)
 	isHTML := IsHTMLContentType(contentType)
 
@@ -165,6 +178,10 @@ func (s *CrawlerStrategy) processResponse(ctx context.Context, r *colly.Response
 		cctx.mu.Unlock()
 		return
 	}
+	if !s.deps.TakeHostSlot(currentURL) {
+		cctx.mu.Unlock()
+		return
+	}
 	*cctx.processedCount++
 	cctx.mu.Unlock()
 
@@ -179,28 +196,39 @@ func (s *CrawlerStrategy) processResponse(ctx context.Context, r *colly.Response
 	cctx.bar.Add(1)
 	cctx.barMu.Unlock()
 
+	s.deps.MarkSeen(currentURL)
 	if !cctx.opts.Force && s.writer.Exists(currentURL) {
 		if cctx.result != nil {
 			cctx.result.IncSkipped()
 		}
 		return
 	}
+	if isHTML && s.deps.SkipAuthWall(cctx.result, currentURL, "", string(body)) {
+		return
+	}
 
 	var doc *domain.Document
 	var err error
 
 	if isMarkdown {
-		doc, err = s.processMarkdownResponse(r.Body, currentURL)
+		doc, err = s.processMarkdownResponse(body, currentURL)
 	} else {
-		doc, err = s.processHTMLResponse(ctx, r.Body, currentURL, cctx.opts)
+		doc, err = s.processHTMLResponse(ctx, body, currentURL, rendered, cctx.opts)
+		if err != nil {
+			s.deps.RecordConvertError(cctx.result, currentURL, err)
+			return
+		}
 	}
 
 	if err != nil || doc == nil {
 		if cctx.result != nil {
-			cctx.result.IncFailed()
+			cctx.result.FailDocument(currentURL, err)
 		}
 		return
 	}
+	if s.deps.SkipDuplicateCanonical(cctx.result, doc) {
+		return
+	}
 
 	if doc.RenderedWithJS && cctx.collector != nil && len(doc.Links) > 0 {
 		var queued int
@@ -223,21 +251,14 @@ func (s *CrawlerStrategy) processResponse(ctx context.Context, r *colly.Response
 	doc.SourceStrategy = s.Name()
 	doc.FetchedAt = time.Now()
 
-	if s.deps.StateManager != nil {
-		s.deps.StateManager.MarkSeen(currentURL)
-		if doc.ContentHash != "" && !s.deps.StateManager.ShouldProcess(currentURL, doc.ContentHash) {
-			if cctx.result != nil {
-				cctx.result.IncSkipped()
-			}
-			s.logger.Debug().Str("url", currentURL).Msg("Skipping unchanged page")
-			return
-		}
+	if s.deps.SkipUnchanged(cctx.result, doc) {
+		return
 	}
 
 	if !cctx.opts.DryRun {
 		if err := s.deps.WriteDocument(ctx, doc); err != nil {
 			if cctx.result != nil {
-				cctx.result.IncFailed()
+				cctx.result.FailDocument(currentURL, err)
 			}
 			s.logger.Warn().Err(err).Str("url", currentURL).Msg("Failed to write document")
 			return
@@ -259,7 +280,7 @@ func NewCrawlerStrategy(deps *Dependencies) *CrawlerStrategy {
 		fetcher:        deps.Fetcher,
 		renderer:       deps.Renderer,
 		converter:      deps.Converter,
-		markdownReader: converter.NewMarkdownReader(),
+		markdownReader: deps.Converter.MarkdownReader(),
 		writer:         deps.Writer,
 		logger:         deps.Logger,
 	}
@@ -275,7 +296,8 @@ func (s *CrawlerStrategy) CanHandle(url string) bool {
 	return utils.IsHTTPURL(url)
 }
 
-// SetFetcher allows setting a custom fetcher for testing
+// SetFetcher replaces the fetcher the strategy requests pages with, such as
+// a client for an authenticated proxy (see domain.Fetcher).
 func (s *CrawlerStrategy) SetFetcher(f domain.Fetcher) {
 	s.fetcher = f
 }
@@ -304,12 +326,24 @@ func (s *CrawlerStrategy) makeRendererFallback() fetcher.RendererFallback {
 }
 
 func (s *CrawlerStrategy) Execute(ctx context.Context, url string, opts Options) (*domain.StrategyResult, error) {
-	result := domain.NewStrategyResult(s.Name(), url)
+	result := s.deps.NewResult(s.Name(), url)
+	s.converter = converterFor(s.converter, opts)
 	err := s.execute(ctx, url, opts, result)
 	result.Finish()
 	return result, err
 }
 
+// crawlRedirectHandler applies the redirect policy to crawler requests,
+// after stopping redirects to login pages when auth walls are detected.
+func (d *Dependencies) crawlRedirectHandler(req *http.Request, via []*http.Request) error {
+	if d.detectAuthWalls {
+		if err := authWallRedirectHandler(req, via); err != nil {
+			return err
+		}
+	}
+	return d.redirects.CheckRedirect(req, via)
+}
+
 func (s *CrawlerStrategy) execute(ctx context.Context, url string, opts Options, result *domain.StrategyResult) error {
 	s.logger.Info().Str("url", url).Msg("Starting web crawl")
 
@@ -317,6 +351,10 @@ func (s *CrawlerStrategy) execute(ctx context.Context, url string, opts Options,
 		s.logger.Info().Str("filter", opts.FilterURL).Msg("URL filter active - only crawling URLs under this path")
 	}
 
+	if opts.FollowNext {
+		return s.followNext(ctx, url, opts, result)
+	}
+
 	cctx := newCrawlContext(ctx, url, opts, result)
 
 	c := colly.NewCollector(
@@ -325,6 +363,7 @@ func (s *CrawlerStrategy) execute(ctx context.Context, url string, opts Options,
 	)
 
 	cctx.collector = c
+	c.SetRedirectHandler(s.deps.crawlRedirectHandler)
 
 	if fetcherClient, ok := s.fetcher.(*fetcher.Client); ok {
 		c.WithTransport(fetcherClient.TransportWithOptions(fetcher.StealthTransportOptions{
@@ -348,6 +387,21 @@ func (s *CrawlerStrategy) execute(ctx context.Context, url string, opts Options,
 		}
 	})
 
+	// Pages of hosts known to need JavaScript rendering are rendered without
+	// a static fetch; processPage queues the links of the rendered page.
+	// Existing pages that are not rewritten are still fetched statically, as
+	// only that finds their links.
+	c.OnRequest(func(r *colly.Request) {
+		pageURL := r.URL.String()
+		if !opts.Force && s.writer.Exists(pageURL) {
+			return
+		}
+		if html, ok := s.deps.renderKnownJSPage(ctx, pageURL); ok {
+			r.Abort()
+			s.processPage(ctx, pageURL, "text/html", []byte(html), true, cctx)
+		}
+	})
+
 	c.OnResponse(func(r *colly.Response) {
 		s.processResponse(ctx, r, cctx)
 	})
@@ -356,8 +410,20 @@ func (s *CrawlerStrategy) execute(ctx context.Context, url string, opts Options,
 		// A transport-level failure is still an attempt; count it so the validator
 		// can distinguish "all fetches failed" from "nothing was attempted".
 		result.IncAttempted()
-		result.IncFailed()
-		s.logger.Debug().Err(err).Str("url", r.Request.URL.String()).Msg("Request failed")
+		failedURL := r.Request.URL.String()
+		if errors.Is(err, errAuthWallRedirect) {
+			s.deps.recordAuthWall(result, failedURL, err.Error())
+			return
+		}
+		if fetcher.ShouldRetryStatus(r.StatusCode) {
+			err = &domain.FetchError{URL: failedURL, StatusCode: r.StatusCode, Err: err}
+		}
+		result.FailDocument(failedURL, err)
+		if s.deps.RecordFailure(result, failedURL, err) {
+			s.logger.Debug().Err(err).Str("url", failedURL).Msg("Request failed transiently, queued for retry")
+			return
+		}
+		s.logger.Debug().Err(err).Str("url", failedURL).Msg("Request failed")
 	})
 
 	if err := c.Visit(url); err != nil {
@@ -377,8 +443,12 @@ func (s *CrawlerStrategy) execute(ctx context.Context, url string, opts Options,
 	}
 
 	s.logger.Info().Int("pages", *cctx.processedCount).Msg("Crawl completed")
+	addCrawlDiagnostics(result)
+	return nil
+}
 
-	// Phase 2 diagnostics
+// addCrawlDiagnostics reports crawls that fetched nothing usable.
+func addCrawlDiagnostics(result *domain.StrategyResult) {
 	snap := result.Snapshot()
 	if snap.URLsAttempted > 0 && snap.DocsWritten == 0 && snap.DocsSkipped == 0 && snap.DocsFailed > 0 {
 		result.AddDiagnostic(domain.DiagAllFetchesFailed,
@@ -390,8 +460,6 @@ func (s *CrawlerStrategy) execute(ctx context.Context, url string, opts Options,
 			"No pages discovered during crawl",
 			"Check URL filter, depth, or exclusions")
 	}
-
-	return nil
 }
 
 // IsHTMLContentType checks if content type is HTML
@@ -0,0 +1,106 @@
+package strategies
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// nextLinkSelectors locate a page's link to the next page of its
+// documentation sequence, most explicit first.
+var nextLinkSelectors = []string{`link[rel~="next"]`, `a[rel~="next"]`, "a.next"}
+
+// followNext walks the documentation sequence starting at startURL one page
+// at a time, following each page's next link, so documents are written in
+// reading order. The walk stops at a page without a next link, at a link the
+// crawl filters reject or that was already visited, and at opts.Limit pages.
+func (s *CrawlerStrategy) followNext(ctx context.Context, startURL string, opts Options, result *domain.StrategyResult) error {
+	cctx := newCrawlContext(ctx, startURL, opts, result)
+	cctx.visited.Store(startURL, true)
+	result.IncDiscovered()
+
+	for current := startURL; current != ""; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Hosts known to need JavaScript rendering skip the static fetch.
+		if html, ok := s.deps.renderKnownJSPage(ctx, current); ok {
+			s.processPage(ctx, current, "text/html", []byte(html), true, cctx)
+			next := nextLink([]byte(html), current)
+			if next == "" || !s.shouldProcessURL(next, startURL, cctx) {
+				break
+			}
+			current = next
+			continue
+		}
+
+		resp, err := s.fetcher.Get(ctx, current)
+		if err != nil {
+			result.IncAttempted()
+			result.FailDocument(current, err)
+			if s.deps.RecordFailure(result, current, err) {
+				s.logger.Debug().Err(err).Str("url", current).Msg("Request failed transiently, queued for retry")
+			} else {
+				s.logger.Debug().Err(err).Str("url", current).Msg("Request failed")
+			}
+			break
+		}
+
+		if s.deps.SkipAuthWall(result, current, resp.FinalURL, "") {
+			// The rest of the sequence is behind the same wall.
+			result.IncAttempted()
+			break
+		}
+
+		contentType := resp.ContentType
+		if contentType == "" && resp.Headers != nil {
+			contentType = resp.Headers.Get("Content-Type")
+		}
+		pageURL := resp.PageURL()
+		s.processPage(ctx, pageURL, contentType, resp.Body, false, cctx)
+
+		next := nextLink(resp.Body, pageURL)
+		if next == "" || !s.shouldProcessURL(next, startURL, cctx) {
+			break
+		}
+		current = next
+	}
+
+	s.logger.Info().Int("pages", *cctx.processedCount).Msg("Next-link walk completed")
+	addCrawlDiagnostics(result)
+	return nil
+}
+
+// nextLink returns the absolute URL of the next page linked from body, or ""
+// when the page has none. Fragment-only links are ignored.
+func nextLink(body []byte, pageURL string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	for _, selector := range nextLinkSelectors {
+		href, ok := doc.Find(selector).First().Attr("href")
+		href = strings.TrimSpace(href)
+		if !ok || href == "" || strings.HasPrefix(href, "#") {
+			continue
+		}
+		ref, err := url.Parse(href)
+		if err != nil {
+			continue
+		}
+		next := base.ResolveReference(ref)
+		next.Fragment = ""
+		return next.String()
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package strategies
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"link rel next", `<html><head><link rel="next" href="/b"></head><body><a class="next" href="/c">c</a></body></html>`, "https://docs.example.com/b"},
+		{"anchor rel next", `<a href="/x">x</a><a rel="prev next" href="b.html#top">b</a>`, "https://docs.example.com/guide/b.html"},
+		{"anchor class next", `<a class="btn next" href="../c">c</a>`, "https://docs.example.com/c"},
+		{"fragment only", `<a rel="next" href="#more">more</a>`, ""},
+		{"none", `<a href="/x">x</a>`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextLink([]byte(tt.body), "https://docs.example.com/guide/a.html"))
+		})
+	}
+}
+
+func TestCrawlerStrategy_Execute_FollowNext(t *testing.T) {
+	pages := map[string]string{
+		"/":   `<html><head><link rel="next" href="/p2"></head><body><a href="/other">Other</a><p>One</p></body></html>`,
+		"/p2": `<html><body><a href="/other">Other</a><p>Two</p><a class="next" href="/p3">Next</a></body></html>`,
+		"/p3": `<html><body><p>Three</p><a rel="next" href="/">Start over</a></body></html>`,
+	}
+
+	var mu sync.Mutex
+	var visited []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		visited = append(visited, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected []string
+	}{
+		{"stops at repeated page", 0, []string{"/", "/p2", "/p3"}},
+		{"bounded by limit", 2, []string{"/", "/p2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			visited = nil
+			deps, err := NewDependencies(DependencyOptions{
+				Timeout:     5 * time.Second,
+				Concurrency: 1,
+				OutputDir:   t.TempDir(),
+				Flat:        true,
+				CommonOptions: domain.CommonOptions{
+					DryRun: true,
+				},
+			})
+			require.NoError(t, err)
+			defer deps.Close()
+
+			opts := Options{
+				CommonOptions: domain.CommonOptions{
+					Limit:  tt.limit,
+					DryRun: true,
+				},
+				Concurrency: 5,
+				MaxDepth:    1,
+				FollowNext:  true,
+			}
+			result, err := NewCrawlerStrategy(deps).Execute(context.Background(), server.URL+"/", opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, visited)
+			assert.Equal(t, len(tt.expected), result.Snapshot().URLsDiscovered)
+		})
+	}
+}
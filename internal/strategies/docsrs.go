@@ -2,6 +2,7 @@ package strategies
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -45,6 +46,8 @@ func (s *DocsRSStrategy) Name() string {
 	return "docsrs"
 }
 
+// SetFetcher replaces the fetcher the strategy requests pages with, such as
+// a client for an authenticated proxy (see domain.Fetcher).
 func (s *DocsRSStrategy) SetFetcher(f domain.Fetcher) {
 	s.fetcher = f
 }
@@ -132,7 +135,7 @@ func parseDocsRSPathWithHost(rawURL, expectedHost string) (*DocsRSURL, error) {
 }
 
 func (s *DocsRSStrategy) Execute(ctx context.Context, rawURL string, opts Options) (*domain.StrategyResult, error) {
-	result := domain.NewStrategyResult(s.Name(), rawURL)
+	result := s.deps.NewResult(s.Name(), rawURL)
 	err := s.execute(ctx, rawURL, opts, result)
 	result.Finish()
 	return result, err
@@ -190,7 +193,7 @@ func (s *DocsRSStrategy) execute(ctx context.Context, rawURL string, opts Option
 
 	result.AddAttempted(len(items))
 
-	bar := utils.NewProgressBar(len(items), utils.DescExtracting)
+	bar := newProgressBar(opts, len(items), utils.DescExtracting)
 
 	errors := utils.ParallelForEach(ctx, items, opts.Concurrency, func(ctx context.Context, item *RustdocItem) error {
 		defer bar.Add(1)
@@ -215,6 +218,7 @@ func (s *DocsRSStrategy) execute(ctx context.Context, rawURL string, opts Option
 func (s *DocsRSStrategy) processItem(ctx context.Context, item *RustdocItem, renderer *RustdocRenderer, baseInfo *DocsRSURL, opts Options, result *domain.StrategyResult) error {
 	itemURL := s.buildItemURL(item, baseInfo)
 
+	s.deps.MarkSeen(itemURL)
 	if !opts.Force && s.writer.Exists(itemURL) {
 		result.IncSkipped()
 		return nil
@@ -222,7 +226,7 @@ func (s *DocsRSStrategy) processItem(ctx context.Context, item *RustdocItem, ren
 
 	markdown := renderer.RenderItem(item)
 	if markdown == "" {
-		result.IncFailed()
+		result.FailDocument(itemURL, errors.New("item rendered no markdown"))
 		return nil
 	}
 
@@ -235,10 +239,13 @@ func (s *DocsRSStrategy) processItem(ctx context.Context, item *RustdocItem, ren
 		FetchedAt:      time.Now(),
 		Tags:           s.buildItemTags(item, baseInfo),
 	}
+	if s.deps.SkipUnchanged(result, doc) {
+		return nil
+	}
 
 	if !opts.DryRun {
 		if err := s.deps.WriteDocument(ctx, doc); err != nil {
-			result.IncFailed()
+			result.FailDocument(itemURL, err)
 			s.logger.Warn().Err(err).Str("url", itemURL).Msg("Failed to write document")
 			return nil
 		}
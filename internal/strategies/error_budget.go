@@ -0,0 +1,55 @@
+package strategies
+
+import (
+	"context"
+	"sync"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// errorBudget aborts a run once max document-level failures have been
+// recorded, so a misconfigured run with continue_on_error cannot churn
+// through thousands of failing URLs. Only StrategyResult.IncFailed counts;
+// skips and dedups never do.
+type errorBudget struct {
+	max int
+
+	mu     sync.Mutex
+	failed int
+	cancel context.CancelCauseFunc
+}
+
+// newErrorBudget returns nil (unlimited) when max is not positive.
+func newErrorBudget(max int) *errorBudget {
+	if max <= 0 {
+		return nil
+	}
+	return &errorBudget{max: max}
+}
+
+// start resets the count and returns a context that is cancelled with
+// domain.ErrTooManyErrors once the budget is used up.
+func (b *errorBudget) start(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b == nil {
+		return context.WithCancel(ctx)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	b.mu.Lock()
+	b.failed = 0
+	b.cancel = cancel
+	b.mu.Unlock()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// fail records one document failure and reports whether it exhausted the
+// budget.
+func (b *errorBudget) fail() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failed++
+	if b.failed != b.max || b.cancel == nil {
+		return false
+	}
+	b.cancel(domain.ErrTooManyErrors)
+	return true
+}
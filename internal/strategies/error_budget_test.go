@@ -0,0 +1,48 @@
+package strategies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencies_ErrorBudget(t *testing.T) {
+	deps := &Dependencies{errorBudget: newErrorBudget(2)}
+	ctx, cancel := deps.WithErrorBudget(context.Background())
+	defer cancel()
+
+	first := deps.NewResult("crawler", "https://example.com")
+	second := deps.NewResult("crawler", "https://example.com/other")
+	first.IncSkipped()
+	first.IncFailed()
+	assert.NoError(t, ctx.Err(), "one failure is within the budget")
+
+	second.IncFailed()
+	assert.Error(t, ctx.Err())
+	assert.ErrorIs(t, context.Cause(ctx), domain.ErrTooManyErrors)
+
+	// The count restarts for the next run.
+	ctx, cancel = deps.WithErrorBudget(context.Background())
+	defer cancel()
+	deps.NewResult("crawler", "https://example.com").IncFailed()
+	assert.NoError(t, ctx.Err())
+}
+
+func TestDependencies_ErrorBudgetUnlimited(t *testing.T) {
+	assert.Nil(t, newErrorBudget(0), "zero means unlimited")
+
+	deps := &Dependencies{}
+	ctx, cancel := deps.WithErrorBudget(context.Background())
+	defer cancel()
+	result := deps.NewResult("crawler", "https://example.com")
+	for i := 0; i < 10; i++ {
+		result.IncFailed()
+	}
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, 10, result.Snapshot().DocsFailed)
+
+	var nilDeps *Dependencies
+	assert.NotNil(t, nilDeps.NewResult("crawler", "https://example.com"))
+}
@@ -0,0 +1,120 @@
+package strategies
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/fetcher"
+	gitstrat "github.com/quantmind-br/repodocs/internal/strategies/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closeCountingFetcher is a mockFetcher that records Close calls and hands
+// out a fixed transport.
+type closeCountingFetcher struct {
+	mockFetcher
+	transport http.RoundTripper
+	closed    int
+}
+
+func (f *closeCountingFetcher) Transport() http.RoundTripper { return f.transport }
+
+func (f *closeCountingFetcher) Close() error {
+	f.closed++
+	return nil
+}
+
+// recordingTransport answers every request with body and records the URLs.
+type recordingTransport struct {
+	body string
+	mu   sync.Mutex
+	urls []string
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.urls = append(rt.urls, req.URL.String())
+	rt.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(rt.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestNewDependencies_InjectedFetcher(t *testing.T) {
+	transport := &recordingTransport{body: `{"default_branch":"trunk"}`}
+	injected := &closeCountingFetcher{transport: transport}
+
+	deps, err := NewDependencies(DependencyOptions{
+		Fetcher:     injected,
+		Timeout:     10 * time.Second,
+		EnableCache: true,
+		CacheDir:    t.TempDir(),
+		CrawlDelay:  time.Second,
+		RateLimit:   2,
+		OutputDir:   t.TempDir(),
+		CommonOptions: domain.CommonOptions{
+			DryRun: true,
+		},
+	})
+	require.NoError(t, err)
+
+	limited, ok := deps.Fetcher.(*fetcher.LimitedFetcher)
+	require.True(t, ok, "the crawl delay and rate limit wrap the injected fetcher")
+	assert.Same(t, injected, limited.Fetcher)
+	assert.Nil(t, deps.Cache, "an injected fetcher does its own caching")
+
+	git := NewGitStrategy(deps)
+	_, err = git.httpClient.Get("https://codeload.github.com/owner/repo/tar.gz/main")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://codeload.github.com/owner/repo/tar.gz/main"}, transport.urls,
+		"archive downloads go through the injected fetcher")
+
+	branch, err := deps.gitBranches.FromAPI(context.Background(), &gitstrat.RepoInfo{
+		Platform: gitstrat.PlatformGitHub, Owner: "owner", Repo: "repo",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", branch)
+	assert.Len(t, transport.urls, 2, "default-branch lookups go through the injected fetcher")
+
+	require.NoError(t, deps.Close())
+	assert.Equal(t, 1, injected.closed)
+}
+
+func TestSetFetcher_AllHTTPStrategies(t *testing.T) {
+	deps := &Dependencies{}
+	injected := &closeCountingFetcher{transport: &http.Transport{}}
+
+	setters := map[string]interface{ SetFetcher(domain.Fetcher) }{
+		"crawler":      NewCrawlerStrategy(deps),
+		"sitemap":      NewSitemapStrategy(deps),
+		"llms":         NewLLMSStrategy(deps),
+		"pkggo":        NewPkgGoStrategy(deps),
+		"docsrs":       NewDocsRSStrategy(deps),
+		"openapi":      NewOpenAPIStrategy(deps),
+		"github_pages": NewGitHubPagesStrategy(deps),
+		"git":          NewGitStrategy(deps),
+	}
+	for name, s := range setters {
+		t.Run(name, func(t *testing.T) {
+			s.SetFetcher(injected)
+		})
+	}
+
+	llms := NewLLMSStrategy(deps)
+	llms.SetFetcher(injected)
+	assert.Same(t, injected, llms.fetcher)
+
+	git := NewGitStrategy(deps)
+	git.SetFetcher(injected)
+	assert.Same(t, injected.transport, git.httpClient.Transport)
+}
@@ -4,33 +4,111 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/quantmind-br/repodocs/internal/fetcher"
 	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
+const (
+	// DefaultArchiveMaxRetries is the number of times a failed archive
+	// download is retried before it fails.
+	DefaultArchiveMaxRetries = 2
+	// DefaultArchiveRetryBackoff is the wait before the first retry of an
+	// archive download; it doubles with each further retry.
+	DefaultArchiveRetryBackoff = 500 * time.Millisecond
+	// maxArchiveRetryAfter caps the wait a Retry-After header can ask for.
+	maxArchiveRetryAfter = time.Minute
+	// DefaultMaxExtractBytes bounds the total size of the files extracted
+	// from one archive.
+	DefaultMaxExtractBytes int64 = 500 << 20
+	// DefaultMaxExtractFileBytes bounds the size of one extracted file.
+	DefaultMaxExtractFileBytes int64 = 100 << 20
+)
+
+// ErrArchiveSizeMismatch is returned when a downloaded archive does not have
+// the length the server announced.
+var ErrArchiveSizeMismatch = errors.New("archive size mismatch")
+
+// ErrArchiveTooLarge is returned when the files of an archive add up to more
+// than the extraction limit, such as for a decompression bomb.
+var ErrArchiveTooLarge = errors.New("archive exceeds the extraction size limit")
+
 // ArchiveFetcher downloads repository source archives over HTTP and extracts them locally.
 type ArchiveFetcher struct {
 	httpClient *http.Client
 	logger     *utils.Logger
+	spaceCheck *SpaceChecker
+	attempts   int
+	backoff    time.Duration
+	// maxBytes and maxFileBytes bound the extracted bytes, in total and
+	// per file; negative is unlimited.
+	maxBytes     int64
+	maxFileBytes int64
 }
 
 // ArchiveFetcherOptions configures an ArchiveFetcher.
 type ArchiveFetcherOptions struct {
 	HTTPClient *http.Client
 	Logger     *utils.Logger
+	// SpaceCheck, when set, verifies there is room for an archive before
+	// it is extracted.
+	SpaceCheck *SpaceChecker
+	// MaxRetries bounds the retries after the first try of a download (0
+	// uses DefaultArchiveMaxRetries, negative disables retries). Downloads
+	// are retried after network errors, interrupted transfers and 429, 502,
+	// 503 or 504 responses, never after 401 or 404.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for each
+	// further one (0 uses DefaultArchiveRetryBackoff). A longer Retry-After
+	// header, up to a minute, is honored instead.
+	RetryBackoff time.Duration
+	// MaxExtractBytes bounds the total size of the extracted files; an
+	// archive holding more fails with ErrArchiveTooLarge (0 uses
+	// DefaultMaxExtractBytes, negative is unlimited).
+	MaxExtractBytes int64
+	// MaxExtractFileBytes bounds the size of each extracted file; larger
+	// files are skipped (0 uses DefaultMaxExtractFileBytes, negative is
+	// unlimited).
+	MaxExtractFileBytes int64
 }
 
 // NewArchiveFetcher creates an archive-based repository fetcher.
 func NewArchiveFetcher(opts ArchiveFetcherOptions) *ArchiveFetcher {
+	retries := opts.MaxRetries
+	if retries == 0 {
+		retries = DefaultArchiveMaxRetries
+	}
+	attempts := max(retries, 0) + 1
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultArchiveRetryBackoff
+	}
+	maxBytes := opts.MaxExtractBytes
+	if maxBytes == 0 {
+		maxBytes = DefaultMaxExtractBytes
+	}
+	maxFileBytes := opts.MaxExtractFileBytes
+	if maxFileBytes == 0 {
+		maxFileBytes = DefaultMaxExtractFileBytes
+	}
 	return &ArchiveFetcher{
-		httpClient: opts.HTTPClient,
-		logger:     opts.Logger,
+		httpClient:   opts.HTTPClient,
+		logger:       opts.Logger,
+		spaceCheck:   opts.SpaceCheck,
+		attempts:     attempts,
+		backoff:      backoff,
+		maxBytes:     maxBytes,
+		maxFileBytes: maxFileBytes,
 	}
 }
 
@@ -41,7 +119,13 @@ func (f *ArchiveFetcher) Name() string {
 
 // Fetch downloads and extracts the requested branch archive into destDir.
 func (f *ArchiveFetcher) Fetch(ctx context.Context, info *RepoInfo, branch, destDir string) (*FetchResult, error) {
-	archiveURL := f.BuildArchiveURL(info, branch)
+	return f.FetchRef(ctx, info, branch, RefBranch, destDir)
+}
+
+// FetchRef downloads and extracts the archive of a branch, tag, or commit
+// into destDir. The result reports ref as its branch.
+func (f *ArchiveFetcher) FetchRef(ctx context.Context, info *RepoInfo, ref string, refType RefType, destDir string) (*FetchResult, error) {
+	archiveURL := f.BuildRefArchiveURL(info, ref, refType)
 	if f.logger != nil {
 		f.logger.Debug().Str("archive_url", archiveURL).Msg("Downloading archive")
 	}
@@ -52,60 +136,292 @@ func (f *ArchiveFetcher) Fetch(ctx context.Context, info *RepoInfo, branch, dest
 
 	return &FetchResult{
 		LocalPath: destDir,
-		Branch:    branch,
+		Branch:    ref,
 		Method:    "archive",
 	}, nil
 }
 
 // BuildArchiveURL returns the platform-specific tar.gz archive URL for a repository branch.
+// Repositories on self-hosted instances are fetched from their BaseURL.
 func (f *ArchiveFetcher) BuildArchiveURL(info *RepoInfo, branch string) string {
+	return f.BuildRefArchiveURL(info, branch, RefBranch)
+}
+
+// BuildRefArchiveURL returns the platform-specific tar.gz archive URL for a
+// branch, tag, or commit. Only GitHub spells the kind of ref out in the path;
+// GitLab and Bitbucket resolve any ref name or SHA, as GitHub does for
+// commits and RefName refs.
+func (f *ArchiveFetcher) BuildRefArchiveURL(info *RepoInfo, ref string, refType RefType) string {
+	base := info.BaseURL
+	if base == "" {
+		host, ok := platformHosts[info.Platform]
+		if !ok {
+			host = platformHosts[PlatformGitHub]
+		}
+		base = "https://" + host.host
+	}
+
 	switch info.Platform {
-	case PlatformGitHub:
-		return fmt.Sprintf("https://github.com/%s/%s/archive/refs/heads/%s.tar.gz",
-			info.Owner, info.Repo, branch)
 	case PlatformGitLab:
-		return fmt.Sprintf("https://gitlab.com/%s/%s/-/archive/%s/%s-%s.tar.gz",
-			info.Owner, info.Repo, branch, info.Repo, branch)
+		return fmt.Sprintf("%s/%s/%s/-/archive/%s/%s-%s.tar.gz",
+			base, info.Owner, info.Repo, ref, info.Repo, ref)
 	case PlatformBitbucket:
-		return fmt.Sprintf("https://bitbucket.org/%s/%s/get/%s.tar.gz",
-			info.Owner, info.Repo, branch)
+		return fmt.Sprintf("%s/%s/%s/get/%s.tar.gz",
+			base, info.Owner, info.Repo, ref)
+	}
+
+	switch refType {
+	case RefTag:
+		return fmt.Sprintf("%s/%s/%s/archive/refs/tags/%s.tar.gz",
+			base, info.Owner, info.Repo, ref)
+	case RefCommit, RefName:
+		return fmt.Sprintf("%s/%s/%s/archive/%s.tar.gz",
+			base, info.Owner, info.Repo, ref)
 	default:
-		return fmt.Sprintf("https://github.com/%s/%s/archive/refs/heads/%s.tar.gz",
-			info.Owner, info.Repo, branch)
+		return fmt.Sprintf("%s/%s/%s/archive/refs/heads/%s.tar.gz",
+			base, info.Owner, info.Repo, ref)
 	}
 }
 
 // DownloadAndExtract downloads a tar.gz archive URL and extracts its contents into destDir.
+// The archive is downloaded to a temporary file next to destDir first. A
+// transfer that breaks off is retried; when the server accepts byte ranges
+// it resumes where it stopped, otherwise it starts over. Network errors and
+// 429, 502, 503 and 504 responses are retried too. With a SpaceCheck,
+// an archive that would not fit fails with ErrInsufficientSpace before
+// anything is downloaded. A failed extraction leaves destDir empty.
 func (f *ArchiveFetcher) DownloadAndExtract(ctx context.Context, archiveURL, destDir string) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", archiveURL, nil)
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return fmt.Errorf("mkdir failed: %w", err)
+	}
+	file, err := os.CreateTemp(filepath.Dir(destDir), ".repodocs-archive-*.tar.gz")
 	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	if err := f.download(ctx, archiveURL, destDir, file); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := f.ExtractTarGz(file, destDir); err != nil {
+		// Leave nothing half extracted for a clone into destDir.
+		clearDir(destDir)
 		return err
 	}
+	return nil
+}
+
+// archiveDownload is the progress of one archive download across attempts.
+type archiveDownload struct {
+	url     string
+	destDir string
+	file    *os.File
+	written int64
+	// total is the announced archive length, or -1 when unknown.
+	total int64
+	// resumable is set when the server accepts byte ranges; validator is
+	// its ETag or Last-Modified, so a resumed range is only served from
+	// the same archive (If-Range).
+	resumable bool
+	validator string
+	checked   bool
+	// retryAfter is the wait the last response asked for, if any.
+	retryAfter time.Duration
+}
+
+// download writes the archive at archiveURL to file, trying up to
+// f.attempts times.
+func (f *ArchiveFetcher) download(ctx context.Context, archiveURL, destDir string, file *os.File) error {
+	dl := &archiveDownload{url: archiveURL, destDir: destDir, file: file, total: -1}
+	var err error
+	backoff := f.backoff
+	for attempt := 1; attempt <= f.attempts; attempt++ {
+		if attempt > 1 {
+			wait := max(backoff, dl.retryAfter)
+			backoff *= 2
+			if f.logger != nil {
+				f.logger.Debug().
+					Err(err).
+					Str("archive_url", archiveURL).
+					Int64("resume_from", dl.written).
+					Int("attempt", attempt).
+					Dur("wait", wait).
+					Msg("Retrying archive download")
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		var retry bool
+		retry, err = f.downloadAttempt(ctx, dl)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return fmt.Errorf("archive download failed after %d attempts: %w", f.attempts, err)
+}
+
+// downloadAttempt sends one request for the rest of the archive and appends
+// the response to the file. It reports whether a failure may be retried.
+func (f *ArchiveFetcher) downloadAttempt(ctx context.Context, dl *archiveDownload) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", dl.url, nil)
+	if err != nil {
+		return false, err
+	}
 
 	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
 		req.Header.Set("Authorization", "token "+token)
 	}
+	resuming := dl.written > 0
+	if resuming {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", dl.written))
+		if dl.validator != "" {
+			req.Header.Set("If-Range", dl.validator)
+		}
+	}
 
+	dl.retryAfter = 0
 	resp, err := f.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("download request failed: %w", err)
+		// A host that does not exist will not appear on the next attempt.
+		var dnsErr *net.DNSError
+		notFound := errors.As(err, &dnsErr) && dnsErr.IsNotFound
+		return ctx.Err() == nil && !notFound, fmt.Errorf("download request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("archive not found (404)")
+	switch {
+	case resuming && resp.StatusCode == http.StatusPartialContent:
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != dl.written {
+			dl.resumable = false
+			return true, dl.restart(fmt.Errorf("unexpected Content-Range %q", resp.Header.Get("Content-Range")))
+		}
+		if total >= 0 {
+			dl.total = total
+		}
+	case resuming && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		dl.resumable = false
+		return true, dl.restart(fmt.Errorf("range not satisfiable (416)"))
+	case resp.StatusCode == http.StatusNotFound:
+		return false, fmt.Errorf("archive not found (404)")
+	case resp.StatusCode == http.StatusUnauthorized:
+		return false, fmt.Errorf("authentication required (401)")
+	case isRetryableArchiveStatus(resp.StatusCode):
+		dl.retryAfter = min(fetcher.ParseRetryAfter(resp.Header.Get("Retry-After")), maxArchiveRetryAfter)
+		return true, fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	default:
+		// A full response, also when the server ignored the range or the
+		// archive changed since the first attempt.
+		if err := dl.restart(nil); err != nil {
+			return false, err
+		}
+		dl.total = resp.ContentLength
+		dl.resumable = resp.Header.Get("Accept-Ranges") == "bytes"
+		dl.validator = resp.Header.Get("ETag")
+		if dl.validator == "" {
+			dl.validator = resp.Header.Get("Last-Modified")
+		}
+		if !dl.checked {
+			if err := f.spaceCheck.Check(dl.destDir, resp.ContentLength); err != nil {
+				return false, err
+			}
+			dl.checked = true
+		}
+	}
+
+	n, err := io.Copy(dl.file, resp.Body)
+	dl.written += n
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		err = fmt.Errorf("archive download interrupted after %d bytes: %w", dl.written, err)
+		if !dl.resumable {
+			return true, dl.restart(err)
+		}
+		return true, err
 	}
-	if resp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("authentication required (401)")
+	if dl.total >= 0 && dl.written != dl.total {
+		return true, dl.restart(fmt.Errorf("%w: got %d of %d bytes", ErrArchiveSizeMismatch, dl.written, dl.total))
 	}
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	return false, nil
+}
+
+// restart discards the downloaded bytes so the next attempt starts over. It
+// returns cause, or the error of truncating the file.
+func (dl *archiveDownload) restart(cause error) error {
+	if dl.written == 0 {
+		return cause
+	}
+	if err := dl.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := dl.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	dl.written = 0
+	return cause
+}
+
+// isRetryableArchiveStatus reports whether a response status is transient:
+// rate limiting or an unavailable upstream.
+func isRetryableArchiveStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
 	}
+	return false
+}
 
-	return f.ExtractTarGz(resp.Body, destDir)
+// parseContentRange parses a "bytes start-end/total" Content-Range header.
+// total is -1 when the header gives it as "*".
+func parseContentRange(header string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	first, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if size == "*" {
+		return start, -1, true
+	}
+	total, err = strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
 }
 
 // ExtractTarGz extracts a repository tar.gz stream into destDir while stripping the archive root directory.
+// Entries are streamed to disk: the extraction stops with ErrArchiveTooLarge
+// once the files would exceed the total size limit, and files over the
+// per-file limit are skipped. Entries that would land outside destDir are
+// skipped. Symbolic links are created once every file is in place, and
+// only when they resolve inside destDir at that moment; entries below a
+// link are skipped rather than written through it.
 func (f *ArchiveFetcher) ExtractTarGz(r io.Reader, destDir string) error {
 	gzr, err := gzip.NewReader(r)
 	if err != nil {
@@ -113,7 +429,18 @@ func (f *ArchiveFetcher) ExtractTarGz(r io.Reader, destDir string) error {
 	}
 	defer gzr.Close()
 
+	root, err := filepath.Abs(destDir)
+	if err != nil {
+		return err
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
 	tr := tar.NewReader(gzr)
+	var written int64
+	var links []string
+	pending := make(map[string]string)
 
 	for {
 		header, err := tr.Next()
@@ -130,34 +457,161 @@ func (f *ArchiveFetcher) ExtractTarGz(r io.Reader, destDir string) error {
 		}
 		relativePath := parts[1]
 
-		targetPath := filepath.Join(destDir, relativePath)
+		targetPath := filepath.Join(root, relativePath)
 
-		if !strings.HasPrefix(filepath.Clean(targetPath), filepath.Clean(destDir)) {
+		if !withinDir(root, targetPath) || !f.resolvesWithin(root, targetPath) {
+			f.skipEntry(header.Name, "outside the destination")
+			continue
+		}
+		if belowLink(root, targetPath, pending) {
+			f.skipEntry(header.Name, "below a symbolic link")
 			continue
 		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
+			delete(pending, targetPath)
 			if err := os.MkdirAll(targetPath, 0755); err != nil {
 				return fmt.Errorf("mkdir failed: %w", err)
 			}
 		case tar.TypeReg:
+			if f.maxFileBytes >= 0 && header.Size > f.maxFileBytes {
+				f.skipEntry(header.Name, fmt.Sprintf("larger than %d bytes", f.maxFileBytes))
+				continue
+			}
+			if f.maxBytes >= 0 && written+header.Size > f.maxBytes {
+				return fmt.Errorf("%w: %s would bring the extracted size past %d bytes", ErrArchiveTooLarge, relativePath, f.maxBytes)
+			}
+
+			delete(pending, targetPath)
 			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 				return fmt.Errorf("mkdir failed: %w", err)
 			}
+			// Never write through a link already sitting at the path.
+			if info, err := os.Lstat(targetPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(targetPath); err != nil {
+					return fmt.Errorf("remove link failed: %w", err)
+				}
+			}
 
 			file, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
 			if err != nil {
 				return fmt.Errorf("create file failed: %w", err)
 			}
 
-			if _, err := io.Copy(file, tr); err != nil {
-				file.Close()
+			n, err := io.CopyN(file, tr, header.Size)
+			written += n
+			file.Close()
+			if err != nil {
 				return fmt.Errorf("copy failed: %w", err)
 			}
-			file.Close()
+		case tar.TypeSymlink:
+			if filepath.IsAbs(header.Linkname) || !withinDir(root, filepath.Join(filepath.Dir(targetPath), header.Linkname)) {
+				f.skipEntry(header.Name, "link target outside the destination")
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+				return fmt.Errorf("mkdir failed: %w", err)
+			}
+			if _, seen := pending[targetPath]; !seen {
+				links = append(links, targetPath)
+			}
+			pending[targetPath] = header.Linkname
 		}
 	}
 
+	f.createLinks(root, links, pending)
 	return nil
 }
+
+// createLinks creates the symbolic links collected during an extraction.
+// Each link is checked when it is created: one that resolves outside root
+// is removed, and one whose target does not exist yet is retried after
+// the others, since it may point at a link created later. Links that never
+// resolve are skipped. A link that is kept resolves through existing paths
+// only, so creating further links cannot change where it leads.
+func (f *ArchiveFetcher) createLinks(root string, links []string, pending map[string]string) {
+	for progress := true; progress && len(pending) > 0; {
+		progress = false
+		for _, link := range links {
+			target, ok := pending[link]
+			if !ok {
+				continue
+			}
+			if _, err := os.Lstat(link); err == nil {
+				delete(pending, link)
+				f.skipEntry(link, "path already exists")
+				continue
+			}
+			if err := os.Symlink(target, link); err != nil {
+				delete(pending, link)
+				f.skipEntry(link, err.Error())
+				continue
+			}
+			resolved, err := filepath.EvalSymlinks(link)
+			if err != nil {
+				os.Remove(link)
+				continue
+			}
+			delete(pending, link)
+			progress = true
+			if !withinDir(root, resolved) {
+				os.Remove(link)
+				f.skipEntry(link, "link target outside the destination")
+			}
+		}
+	}
+
+	for _, link := range links {
+		if _, ok := pending[link]; ok {
+			f.skipEntry(link, "link target does not exist")
+		}
+	}
+}
+
+// belowLink reports whether one of path's parents under root is a link
+// still waiting to be created.
+func belowLink(root, path string, pending map[string]string) bool {
+	for dir := filepath.Dir(path); dir != root && withinDir(root, dir); dir = filepath.Dir(dir) {
+		if _, ok := pending[dir]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// withinDir reports whether path is dir or lies under it, lexically.
+func withinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// resolvesWithin reports whether path, with the symbolic links among its
+// existing components followed, stays under root. Components that do not
+// exist yet are created as plain directories or files, so they cannot
+// lead elsewhere; a link that cannot be resolved counts as outside.
+func (f *ArchiveFetcher) resolvesWithin(root, path string) bool {
+	existing := path
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing || !withinDir(root, parent) {
+			return true
+		}
+		existing = parent
+	}
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return false
+	}
+	return withinDir(root, resolved)
+}
+
+// skipEntry logs an archive entry left out of the extraction.
+func (f *ArchiveFetcher) skipEntry(name, reason string) {
+	if f.logger != nil {
+		f.logger.Warn().Str("entry", name).Str("reason", reason).Msg("Skipping archive entry")
+	}
+}
@@ -0,0 +1,225 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// DefaultBranchLookupTimeout bounds a single default-branch lookup.
+const DefaultBranchLookupTimeout = 10 * time.Second
+
+// DefaultAPIBaseURLs are the hosting API endpoints queried for a repository's
+// default branch.
+var DefaultAPIBaseURLs = map[Platform]string{
+	PlatformGitHub:    "https://api.github.com",
+	PlatformGitLab:    "https://gitlab.com/api/v4",
+	PlatformBitbucket: "https://api.bitbucket.org/2.0",
+}
+
+// BranchDetector resolves the default branch of hosted repositories and
+// remembers the result for the rest of the run, so repeated sources from the
+// same repository download the right archive on the first try.
+type BranchDetector struct {
+	httpClient  *http.Client
+	logger      *utils.Logger
+	timeout     time.Duration
+	apiBaseURLs map[Platform]string
+	lsRemote    func(ctx context.Context, url string) (string, error)
+
+	mu       sync.Mutex
+	branches map[string]string
+}
+
+// BranchDetectorOptions configures a BranchDetector.
+type BranchDetectorOptions struct {
+	// HTTPClient queries the hosting APIs; nil uses NewHTTPClient with
+	// ProxyURL.
+	HTTPClient *http.Client
+	ProxyURL   string
+	Logger     *utils.Logger
+	// Timeout bounds each API or ls-remote lookup; 0 uses
+	// DefaultBranchLookupTimeout.
+	Timeout time.Duration
+	// APIBaseURLs overrides entries of DefaultAPIBaseURLs.
+	APIBaseURLs map[Platform]string
+	// LsRemote resolves the branch through git; nil uses
+	// DetectDefaultBranchWithOptions with Timeout bounding each attempt.
+	LsRemote func(ctx context.Context, url string) (string, error)
+}
+
+// NewBranchDetector creates a default-branch detector with an empty cache.
+func NewBranchDetector(opts BranchDetectorOptions) *BranchDetector {
+	client := opts.HTTPClient
+	if client == nil {
+		client = NewHTTPClient(opts.ProxyURL)
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultBranchLookupTimeout
+	}
+	apiBaseURLs := make(map[Platform]string, len(DefaultAPIBaseURLs))
+	for platform, base := range DefaultAPIBaseURLs {
+		apiBaseURLs[platform] = base
+	}
+	for platform, base := range opts.APIBaseURLs {
+		apiBaseURLs[platform] = strings.TrimSuffix(base, "/")
+	}
+	lsRemote := opts.LsRemote
+	if lsRemote == nil {
+		lsRemote = func(ctx context.Context, url string) (string, error) {
+			return DetectDefaultBranchWithOptions(ctx, url, LsRemoteOptions{Timeout: timeout})
+		}
+	} else {
+		custom := lsRemote
+		lsRemote = func(ctx context.Context, url string) (string, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return custom(ctx, url)
+		}
+	}
+
+	return &BranchDetector{
+		httpClient:  client,
+		logger:      opts.Logger,
+		timeout:     timeout,
+		apiBaseURLs: apiBaseURLs,
+		lsRemote:    lsRemote,
+		branches:    make(map[string]string),
+	}
+}
+
+// WithHTTPClient returns a detector that queries the hosting APIs with
+// client and otherwise matches d, starting from the branches d remembers.
+func (d *BranchDetector) WithHTTPClient(client *http.Client) *BranchDetector {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	branches := make(map[string]string, len(d.branches))
+	for key, branch := range d.branches {
+		branches[key] = branch
+	}
+	return &BranchDetector{
+		httpClient:  client,
+		logger:      d.logger,
+		timeout:     d.timeout,
+		apiBaseURLs: d.apiBaseURLs,
+		lsRemote:    d.lsRemote,
+		branches:    branches,
+	}
+}
+
+// Cached returns the branch remembered for the repository, if any.
+func (d *BranchDetector) Cached(info *RepoInfo) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	branch, ok := d.branches[branchCacheKey(info)]
+	return branch, ok
+}
+
+// Remember records the branch that worked for the repository.
+func (d *BranchDetector) Remember(info *RepoInfo, branch string) {
+	if branch == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.branches[branchCacheKey(info)] = branch
+}
+
+// FromAPI asks the hosting platform's API for the repository's default branch.
+func (d *BranchDetector) FromAPI(ctx context.Context, info *RepoInfo) (string, error) {
+	apiURL, err := d.apiURL(info)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if info.Platform == PlatformGitHub {
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		}
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("branch lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("branch lookup failed with status: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		DefaultBranch string `json:"default_branch"`
+		MainBranch    struct {
+			Name string `json:"name"`
+		} `json:"mainbranch"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode branch lookup response: %w", err)
+	}
+
+	branch := body.DefaultBranch
+	if info.Platform == PlatformBitbucket {
+		branch = body.MainBranch.Name
+	}
+	if branch == "" {
+		return "", fmt.Errorf("branch lookup response has no default branch")
+	}
+
+	if d.logger != nil {
+		d.logger.Debug().Str("repo", info.Owner+"/"+info.Repo).Str("branch", branch).Msg("Detected default branch from API")
+	}
+	return branch, nil
+}
+
+// FromRemote resolves the default branch with git ls-remote, retrying once
+// if it times out (see DetectDefaultBranchWithOptions).
+func (d *BranchDetector) FromRemote(ctx context.Context, repoURL string) (string, error) {
+	return d.lsRemote(ctx, repoURL)
+}
+
+func (d *BranchDetector) apiURL(info *RepoInfo) (string, error) {
+	base, ok := d.apiBaseURLs[info.Platform]
+	// Self-hosted instances are left to git ls-remote rather than sent to
+	// the public API, which would also receive the public host's token.
+	if !ok || info.Owner == "" || info.Repo == "" || info.BaseURL != "" {
+		return "", fmt.Errorf("no branch API for platform %q", info.Platform)
+	}
+
+	switch info.Platform {
+	case PlatformGitLab:
+		return fmt.Sprintf("%s/projects/%s", base, url.PathEscape(info.Owner+"/"+info.Repo)), nil
+	case PlatformBitbucket:
+		return fmt.Sprintf("%s/repositories/%s/%s", base, info.Owner, info.Repo), nil
+	default:
+		return fmt.Sprintf("%s/repos/%s/%s", base, info.Owner, info.Repo), nil
+	}
+}
+
+func branchCacheKey(info *RepoInfo) string {
+	if info.Owner == "" || info.Repo == "" {
+		return utils.CanonicalRepoURL(info.URL)
+	}
+	key := string(info.Platform) + "/" + info.Owner + "/" + info.Repo
+	if info.BaseURL != "" {
+		key = info.BaseURL + "/" + key
+	}
+	return strings.ToLower(key)
+}
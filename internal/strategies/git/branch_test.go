@@ -0,0 +1,296 @@
+package git_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	gitstrat "github.com/quantmind-br/repodocs/internal/strategies/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rewriteTransport sends every request to target, keeping the original path.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBranchDetector_FromAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/github/repos/acme/docs":
+			fmt.Fprint(w, `{"default_branch":"trunk"}`)
+		case "/gitlab/projects/acme%2Fdocs":
+			fmt.Fprint(w, `{"default_branch":"develop"}`)
+		case "/bitbucket/repositories/acme/docs":
+			fmt.Fprint(w, `{"mainbranch":{"name":"stable"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	detector := gitstrat.NewBranchDetector(gitstrat.BranchDetectorOptions{
+		HTTPClient: server.Client(),
+		APIBaseURLs: map[gitstrat.Platform]string{
+			gitstrat.PlatformGitHub:    server.URL + "/github",
+			gitstrat.PlatformGitLab:    server.URL + "/gitlab",
+			gitstrat.PlatformBitbucket: server.URL + "/bitbucket/",
+		},
+	})
+
+	tests := []struct {
+		platform gitstrat.Platform
+		want     string
+	}{
+		{gitstrat.PlatformGitHub, "trunk"},
+		{gitstrat.PlatformGitLab, "develop"},
+		{gitstrat.PlatformBitbucket, "stable"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.platform), func(t *testing.T) {
+			branch, err := detector.FromAPI(context.Background(), &gitstrat.RepoInfo{
+				Platform: tt.platform,
+				Owner:    "acme",
+				Repo:     "docs",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, branch)
+		})
+	}
+}
+
+func TestBranchDetector_FromAPI_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		fmt.Fprint(w, `{"default_branch":"trunk"}`)
+	}))
+	defer proxy.Close()
+
+	detector := gitstrat.NewBranchDetector(gitstrat.BranchDetectorOptions{
+		ProxyURL:    proxy.URL,
+		APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitHub: "http://api.github.invalid"},
+	})
+	branch, err := detector.FromAPI(context.Background(), &gitstrat.RepoInfo{
+		Platform: gitstrat.PlatformGitHub,
+		Owner:    "acme",
+		Repo:     "docs",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "trunk", branch)
+	assert.Equal(t, "http://api.github.invalid/repos/acme/docs", proxied, "the lookup goes through the proxy")
+}
+
+func TestBranchDetector_FromAPI_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/acme/empty" {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		if r.URL.Path == "/repos/acme/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	detector := gitstrat.NewBranchDetector(gitstrat.BranchDetectorOptions{
+		HTTPClient:  server.Client(),
+		Timeout:     50 * time.Millisecond,
+		APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitHub: server.URL},
+	})
+	ctx := context.Background()
+
+	for _, repo := range []string{"missing", "empty", "slow"} {
+		_, err := detector.FromAPI(ctx, &gitstrat.RepoInfo{Platform: gitstrat.PlatformGitHub, Owner: "acme", Repo: repo})
+		assert.Error(t, err, repo)
+	}
+
+	_, err := detector.FromAPI(ctx, &gitstrat.RepoInfo{Platform: gitstrat.PlatformGeneric, URL: "https://git.example.com/acme/docs.git"})
+	assert.Error(t, err)
+
+	_, err = detector.FromAPI(ctx, &gitstrat.RepoInfo{Platform: gitstrat.PlatformGitHub, Owner: "acme", Repo: "empty", BaseURL: "https://ghe.example.com"})
+	assert.Error(t, err, "self-hosted repositories are not looked up in the public API")
+}
+
+func TestBranchDetector_Cache(t *testing.T) {
+	detector := gitstrat.NewBranchDetector(gitstrat.BranchDetectorOptions{})
+	info := &gitstrat.RepoInfo{Platform: gitstrat.PlatformGitHub, Owner: "Acme", Repo: "Docs"}
+
+	_, ok := detector.Cached(info)
+	assert.False(t, ok)
+
+	detector.Remember(info, "trunk")
+	branch, ok := detector.Cached(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGitHub, Owner: "acme", Repo: "docs"})
+	assert.True(t, ok)
+	assert.Equal(t, "trunk", branch)
+
+	_, ok = detector.Cached(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGitLab, Owner: "acme", Repo: "docs"})
+	assert.False(t, ok)
+
+	detector.Remember(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGeneric, URL: "https://git.example.com/acme/docs.git"}, "develop")
+	branch, ok = detector.Cached(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGeneric, URL: "https://git.example.com/acme/docs/"})
+	assert.True(t, ok, "generic repositories are keyed by their canonical URL")
+	assert.Equal(t, "develop", branch)
+
+	_, ok = detector.Cached(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGitHub, Owner: "acme", Repo: "docs", BaseURL: "https://ghe.example.com"})
+	assert.False(t, ok, "self-hosted repositories are keyed by their instance")
+}
+
+func TestTryArchiveDownload_UsesAPIBranchAndCachesIt(t *testing.T) {
+	archive := createTestTarGz(t, map[string]string{"docs-trunk/README.md": "# Docs"}).Bytes()
+
+	var mu sync.Mutex
+	var apiCalls int
+	var archivePaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/api/repos/acme/docs":
+			apiCalls++
+			fmt.Fprint(w, `{"default_branch":"trunk"}`)
+		case "/acme/docs/archive/refs/heads/trunk.tar.gz":
+			archivePaths = append(archivePaths, r.URL.Path)
+			w.Write(archive)
+		default:
+			archivePaths = append(archivePaths, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	deps := setupTestDependencies(t, t.TempDir())
+	deps.HTTPClient = &http.Client{Transport: rewriteTransport{target: target}}
+	deps.Branches = gitstrat.NewBranchDetector(gitstrat.BranchDetectorOptions{
+		HTTPClient:  server.Client(),
+		APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitHub: server.URL + "/api"},
+		LsRemote: func(ctx context.Context, url string) (string, error) {
+			t.Fatal("ls-remote must not run when the API answered")
+			return "", nil
+		},
+	})
+	strategy := gitstrat.NewStrategy(deps)
+
+	for i := 0; i < 2; i++ {
+		destDir := t.TempDir()
+		branch, method, err := strategy.TryArchiveDownload(context.Background(), "https://github.com/acme/docs", destDir)
+		require.NoError(t, err)
+		assert.Equal(t, "trunk", branch)
+		assert.Equal(t, "archive", method)
+		assert.FileExists(t, filepath.Join(destDir, "README.md"))
+	}
+
+	assert.Equal(t, 1, apiCalls, "the detected branch is cached for the run")
+	assert.Equal(t, []string{
+		"/acme/docs/archive/refs/heads/trunk.tar.gz",
+		"/acme/docs/archive/refs/heads/trunk.tar.gz",
+	}, archivePaths)
+}
+
+func TestTryArchiveDownload_FallsBackToLsRemote(t *testing.T) {
+	archive := createTestTarGz(t, map[string]string{"docs-release/README.md": "# Docs"}).Bytes()
+
+	var archivePaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/acme/docs/archive/refs/heads/release.tar.gz" {
+			w.Write(archive)
+			return
+		}
+		if r.URL.Path != "/api/repos/acme/docs" {
+			archivePaths = append(archivePaths, r.URL.Path)
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	deps := setupTestDependencies(t, t.TempDir())
+	deps.HTTPClient = &http.Client{Transport: rewriteTransport{target: target}}
+	deps.Branches = gitstrat.NewBranchDetector(gitstrat.BranchDetectorOptions{
+		HTTPClient:  server.Client(),
+		APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitHub: server.URL + "/api"},
+		LsRemote: func(ctx context.Context, url string) (string, error) {
+			return "release", nil
+		},
+	})
+	strategy := gitstrat.NewStrategy(deps)
+
+	destDir := t.TempDir()
+	branch, _, err := strategy.TryArchiveDownload(context.Background(), "https://github.com/acme/docs", destDir)
+	require.NoError(t, err)
+	assert.Equal(t, "release", branch)
+	assert.Equal(t, []string{
+		"/acme/docs/archive/refs/heads/main.tar.gz",
+		"/acme/docs/archive/refs/heads/master.tar.gz",
+	}, archivePaths)
+
+	_, err = os.Stat(filepath.Join(destDir, "README.md"))
+	assert.NoError(t, err)
+}
+
+func TestExecute_PinnedTagHasNoBranchFallback(t *testing.T) {
+	archive := createTestTarGz(t, map[string]string{"docs-2.3.0/README.md": "# Docs 2.3.0"}).Bytes()
+
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/acme/docs/archive/v2.3.0.tar.gz" {
+			w.Write(archive)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	var docs []string
+	deps := setupTestDependencies(t, t.TempDir())
+	deps.HTTPClient = &http.Client{Transport: rewriteTransport{target: target}}
+	deps.Branches = gitstrat.NewBranchDetector(gitstrat.BranchDetectorOptions{
+		HTTPClient: server.Client(),
+		LsRemote: func(ctx context.Context, url string) (string, error) {
+			t.Fatal("ls-remote must not run for a pinned ref")
+			return "", nil
+		},
+	})
+	deps.WriteFunc = func(ctx context.Context, doc *domain.Document) error {
+		docs = append(docs, doc.RelativePath)
+		return nil
+	}
+	strategy := gitstrat.NewStrategy(deps)
+
+	err = strategy.Execute(context.Background(), "https://github.com/acme/docs/tree/v2.3.0", gitstrat.ExecuteOptions{
+		Output:      t.TempDir(),
+		Concurrency: 1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md"}, docs)
+	assert.Equal(t, []string{"/acme/docs/archive/v2.3.0.tar.gz"}, requests)
+}
@@ -3,30 +3,54 @@ package git
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
 
 	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
+// ErrCloneTimeout is returned when a clone does not finish within the
+// CloneFetcher's timeout.
+var ErrCloneTimeout = errors.New("git clone timed out")
+
 // CloneFetcher clones repositories with go-git when archive download is unavailable.
 type CloneFetcher struct {
-	logger *utils.Logger
+	logger     *utils.Logger
+	submodules bool
+	timeout    time.Duration
+	spaceCheck *SpaceChecker
 }
 
 // CloneFetcherOptions configures a CloneFetcher.
 type CloneFetcherOptions struct {
 	Logger *utils.Logger
+	// Submodules recursively initializes submodules after cloning so their
+	// files are discovered with the rest of the repository.
+	Submodules bool
+	// Timeout bounds the whole clone, independently of the per-request
+	// timeout; 0 means no limit.
+	Timeout time.Duration
+	// SpaceCheck, when set, verifies there is room for a clone before it
+	// starts. The size of a clone is not known up front, so only a minimum
+	// of free space is required.
+	SpaceCheck *SpaceChecker
 }
 
 // NewCloneFetcher creates a git clone-based repository fetcher.
 func NewCloneFetcher(opts CloneFetcherOptions) *CloneFetcher {
-	return &CloneFetcher{logger: opts.Logger}
+	return &CloneFetcher{logger: opts.Logger, submodules: opts.Submodules, timeout: opts.Timeout, spaceCheck: opts.SpaceCheck}
 }
 
 // Name returns the fetch method name used in FetchResult values and logs.
@@ -35,9 +59,26 @@ func (f *CloneFetcher) Name() string {
 }
 
 // Fetch clones the repository into destDir and reports the checked-out branch.
+// A failed clone leaves destDir empty. A clone that exceeds the fetcher's
+// timeout fails with ErrCloneTimeout.
 func (f *CloneFetcher) Fetch(ctx context.Context, info *RepoInfo, branch, destDir string) (*FetchResult, error) {
+	return f.clone(ctx, info, branch, "", "", destDir)
+}
+
+// FetchRef clones the repository into destDir and checks out ref, a branch,
+// tag, or commit SHA; an empty ref checks out the default branch. Commits
+// need the full history, so only branches and tags are cloned shallowly.
+// The result reports ref as its branch.
+func (f *CloneFetcher) FetchRef(ctx context.Context, info *RepoInfo, ref string, refType RefType, destDir string) (*FetchResult, error) {
+	return f.clone(ctx, info, "", ref, refType, destDir)
+}
+
+func (f *CloneFetcher) clone(ctx context.Context, info *RepoInfo, branch, ref string, refType RefType, destDir string) (*FetchResult, error) {
+	if err := f.spaceCheck.Check(destDir, -1); err != nil {
+		return nil, err
+	}
 	if f.logger != nil {
-		f.logger.Info().Str("url", info.URL).Msg("Cloning repository")
+		f.logger.Info().Str("url", info.URL).Str("ref", ref).Msg("Cloning repository")
 	}
 
 	cloneOpts := &git.CloneOptions{
@@ -45,7 +86,6 @@ func (f *CloneFetcher) Fetch(ctx context.Context, info *RepoInfo, branch, destDi
 		Depth:    1,
 		Progress: os.Stdout,
 	}
-
 	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
 		cloneOpts.Auth = &githttp.BasicAuth{
 			Username: "token",
@@ -53,11 +93,43 @@ func (f *CloneFetcher) Fetch(ctx context.Context, info *RepoInfo, branch, destDi
 		}
 	}
 
-	repo, err := git.PlainCloneContext(ctx, destDir, false, cloneOpts)
+	if ref != "" {
+		if refType == RefCommit {
+			cloneOpts.Depth = 0
+		} else {
+			cloneOpts.ReferenceName = resolveRefName(ctx, info.URL, ref, refType, cloneOpts.Auth)
+			cloneOpts.SingleBranch = true
+		}
+	}
+	// Submodules of a commit are updated after it is checked out.
+	if f.submodules && refType != RefCommit {
+		cloneOpts.RecurseSubmodules = git.DefaultSubmoduleRecursionDepth
+		cloneOpts.ShallowSubmodules = true
+	}
+
+	cloneCtx := ctx
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		cloneCtx, cancel = context.WithTimeout(ctx, f.timeout)
+		defer cancel()
+	}
+
+	repo, err := git.PlainCloneContext(cloneCtx, destDir, false, cloneOpts)
+	if err == nil && ref != "" && refType == RefCommit {
+		err = f.checkoutCommit(cloneCtx, repo, ref, cloneOpts.Auth)
+	}
 	if err != nil {
+		clearDir(destDir)
+		if ctx.Err() == nil && errors.Is(cloneCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s: %s", ErrCloneTimeout, f.timeout, info.URL)
+		}
 		return nil, err
 	}
 
+	if ref != "" {
+		return &FetchResult{LocalPath: destDir, Branch: ref, Method: "clone"}, nil
+	}
+
 	detectedBranch := branch
 	head, err := repo.Head()
 	if err == nil {
@@ -78,11 +150,142 @@ func (f *CloneFetcher) Fetch(ctx context.Context, info *RepoInfo, branch, destDi
 	}, nil
 }
 
+// checkoutCommit checks out sha, which may be abbreviated, in a full clone
+// and updates its submodules when the fetcher includes them.
+func (f *CloneFetcher) checkoutCommit(ctx context.Context, repo *git.Repository, sha string, auth transport.AuthMethod) error {
+	hash, err := repo.ResolveRevision(plumbing.Revision(sha))
+	if err != nil {
+		return fmt.Errorf("commit %s not found: %w", sha, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+		return fmt.Errorf("failed to check out commit %s: %w", sha, err)
+	}
+	if !f.submodules {
+		return nil
+	}
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return err
+	}
+	return submodules.UpdateContext(ctx, &git.SubmoduleUpdateOptions{
+		Init:              true,
+		RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+		Auth:              auth,
+	})
+}
+
+// resolveRefName returns the full name of the branch or tag ref on the
+// remote at url. Tags are looked up first for RefTag and branches first
+// otherwise; when neither exists or the remote cannot be listed, the
+// preferred name is returned so the clone reports the error.
+func resolveRefName(ctx context.Context, url, ref string, refType RefType, auth transport.AuthMethod) plumbing.ReferenceName {
+	candidates := []plumbing.ReferenceName{plumbing.NewBranchReferenceName(ref), plumbing.NewTagReferenceName(ref)}
+	if refType == RefTag {
+		candidates[0], candidates[1] = candidates[1], candidates[0]
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{url}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if err != nil {
+		return candidates[0]
+	}
+	for _, candidate := range candidates {
+		for _, r := range refs {
+			if r.Name() == candidate {
+				return candidate
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// clearDir removes the contents of dir, such as a partial clone, keeping dir
+// itself for its owner to remove.
+func clearDir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		_ = os.RemoveAll(filepath.Join(dir, entry.Name()))
+	}
+}
+
+// HasSubmodules reports whether the repository at dir declares submodules.
+func HasSubmodules(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, ".gitmodules"))
+	return err == nil && info.Size() > 0
+}
+
+const (
+	// DefaultLsRemoteTimeout bounds one git ls-remote attempt.
+	DefaultLsRemoteTimeout = 10 * time.Second
+	// DefaultLsRemoteBackoff is the wait before git ls-remote is retried.
+	DefaultLsRemoteBackoff = time.Second
+	// processWaitDelay bounds the wait for the output of a killed git
+	// process.
+	processWaitDelay = 2 * time.Second
+)
+
+// ErrLsRemoteTimeout is returned when git ls-remote does not answer within
+// its timeout, on the first attempt and on the retry.
+var ErrLsRemoteTimeout = errors.New("git ls-remote timed out")
+
+// LsRemoteOptions configures DetectDefaultBranchWithOptions.
+type LsRemoteOptions struct {
+	// Timeout bounds each attempt; 0 uses DefaultLsRemoteTimeout.
+	Timeout time.Duration
+	// Backoff is the wait before the retry; 0 uses DefaultLsRemoteBackoff.
+	Backoff time.Duration
+}
+
 // DetectDefaultBranch asks the remote repository for its HEAD branch name.
 func DetectDefaultBranch(ctx context.Context, url string) (string, error) {
-	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--symref", url, "HEAD")
+	return DetectDefaultBranchWithOptions(ctx, url, LsRemoteOptions{})
+}
+
+// DetectDefaultBranchWithOptions asks the remote repository for its HEAD
+// branch name with git ls-remote. An attempt that times out is retried once
+// after the backoff, so a hanging remote costs a bounded delay instead of
+// blocking the extraction. The git process and its helpers are killed when
+// an attempt times out or ctx is cancelled.
+func DetectDefaultBranchWithOptions(ctx context.Context, url string, opts LsRemoteOptions) (string, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultLsRemoteTimeout
+	}
+	backoff := opts.Backoff
+	if backoff <= 0 {
+		backoff = DefaultLsRemoteBackoff
+	}
+
+	branch, err := lsRemoteHead(ctx, url, timeout)
+	if !errors.Is(err, ErrLsRemoteTimeout) {
+		return branch, err
+	}
+	select {
+	case <-ctx.Done():
+		return "", err
+	case <-time.After(backoff):
+	}
+	return lsRemoteHead(ctx, url, timeout)
+}
+
+// lsRemoteHead runs one git ls-remote attempt bounded by timeout.
+func lsRemoteHead(ctx context.Context, url string, timeout time.Duration) (string, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(attemptCtx, "git", "ls-remote", "--symref", url, "HEAD")
+	killProcessGroup(cmd)
 	output, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("git ls-remote failed: %w after %s: %s", ErrLsRemoteTimeout, timeout, url)
+		}
 		return "", fmt.Errorf("git ls-remote failed: %w", err)
 	}
 
@@ -11,6 +11,10 @@
 //     branch/subpath information from tree URLs.
 //   - ArchiveFetcher uses platform-specific tar.gz archive URLs for the fast
 //     path and strips archive root directories during extraction.
+//   - BranchDetector asks the hosting platform's API (or git ls-remote) for a
+//     repository's default branch and caches it for the run.
+//   - TreeFetcher lists a subdirectory through the GitHub or GitLab API and
+//     downloads only its documentation files.
 //   - CloneFetcher falls back to a shallow go-git clone when archives fail or
 //     cannot be used.
 //   - Processor walks the fetched repository, ignores dependency/build
@@ -18,9 +22,11 @@
 //     domain.Document values, and cooperates with sync state to skip unchanged
 //     files.
 //
-// Strategy first tries archive download for non-SSH URLs, including default
-// branch detection and a main-to-master fallback. If archive acquisition fails,
-// it clones the repository and processes the local checkout through the same
+// When a subdirectory is requested, Strategy first tries TreeFetcher.
+// Otherwise, or when the API is unavailable, it tries archive download for
+// non-SSH URLs: the API-reported default branch, then main and master, then
+// the branch from git ls-remote. If archive acquisition fails, it clones the
+// repository and processes the local checkout through the same
 // Processor path. Output writing is supplied by StrategyDependencies so the
 // package stays independent from CLI orchestration details.
 //
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// sniffLen is how much of a file is inspected to tell text from binary.
+const sniffLen = 8000
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// decodeText returns content as UTF-8 text. A UTF-8 byte order mark is
+// removed, UTF-16 files with a byte order mark are transcoded, and other
+// non-UTF-8 text is read as Latin-1. It reports false for binary content,
+// which should be skipped.
+func decodeText(content []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(content, utf8BOM):
+		content = content[len(utf8BOM):]
+	case bytes.HasPrefix(content, utf16LEBOM):
+		return decodeUTF16(content[len(utf16LEBOM):], false), true
+	case bytes.HasPrefix(content, utf16BEBOM):
+		return decodeUTF16(content[len(utf16BEBOM):], true), true
+	}
+
+	if isBinary(content) {
+		return "", false
+	}
+	if utf8.Valid(content) {
+		return string(content), true
+	}
+
+	var b strings.Builder
+	b.Grow(len(content))
+	for _, c := range content {
+		b.WriteRune(rune(c))
+	}
+	return b.String(), true
+}
+
+// isBinary reports whether the start of content looks like binary data: it
+// contains a NUL byte or sniffs as a non-text media type.
+func isBinary(content []byte) bool {
+	head := content
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+	return !strings.HasPrefix(http.DetectContentType(head), "text/")
+}
+
+func decodeUTF16(content []byte, bigEndian bool) string {
+	units := make([]uint16, len(content)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(content[2*i])<<8 | uint16(content[2*i+1])
+		} else {
+			units[i] = uint16(content[2*i+1])<<8 | uint16(content[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
+}
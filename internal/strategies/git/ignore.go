@@ -0,0 +1,112 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// RepodocsIgnoreFile is the repository-root file, in gitignore syntax, that
+// excludes paths from documentation discovery.
+const RepodocsIgnoreFile = ".repodocsignore"
+
+// loadIgnoreMatcher reads the ignore files at the root of repoDir: always
+// .repodocsignore, and .gitignore too when honorGitignore is set. It returns
+// nil when no patterns apply. Missing files are not an error.
+func loadIgnoreMatcher(repoDir string, honorGitignore bool) (gitignore.Matcher, error) {
+	files := []string{RepodocsIgnoreFile}
+	if honorGitignore {
+		files = []string{".gitignore", RepodocsIgnoreFile}
+	}
+
+	var patterns []gitignore.Pattern
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(repoDir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		patterns = append(patterns, parseIgnorePatterns(data)...)
+	}
+
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	return gitignore.NewMatcher(patterns), nil
+}
+
+func parseIgnorePatterns(data []byte) []gitignore.Pattern {
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return patterns
+}
+
+// ignored reports whether path, relative to the repository root, is excluded
+// by matcher.
+func ignored(matcher gitignore.Matcher, relPath string, isDir bool) bool {
+	if matcher == nil || relPath == "." {
+		return false
+	}
+	return matcher.Match(strings.Split(filepath.ToSlash(relPath), "/"), isDir)
+}
+
+// globFilter applies the Include and Exclude patterns of DiscoveryOptions.
+// Patterns use gitignore syntax, including ** and ! negation, and are
+// matched against paths relative to the repository root; backslashes are
+// read as path separators.
+type globFilter struct {
+	include gitignore.Matcher
+	exclude gitignore.Matcher
+}
+
+func newGlobFilter(include, exclude []string) globFilter {
+	return globFilter{include: globMatcher(include), exclude: globMatcher(exclude)}
+}
+
+// globMatcher returns nil when patterns holds no pattern.
+func globMatcher(patterns []string) gitignore.Matcher {
+	var parsed []gitignore.Pattern
+	for _, pattern := range patterns {
+		pattern = strings.ReplaceAll(strings.TrimSpace(pattern), "\\", "/")
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		parsed = append(parsed, gitignore.ParsePattern(pattern, nil))
+	}
+	if len(parsed) == 0 {
+		return nil
+	}
+	return gitignore.NewMatcher(parsed)
+}
+
+// skipDir reports whether the directory at relPath is excluded as a whole.
+func (f globFilter) skipDir(relPath string) bool {
+	return f.exclude != nil && relPath != "." && f.exclude.Match(globPath(relPath), true)
+}
+
+// allows reports whether the file at relPath matches the include patterns,
+// if any, and none of the exclude patterns.
+func (f globFilter) allows(relPath string) bool {
+	parts := globPath(relPath)
+	if f.include != nil && !f.include.Match(parts, false) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.Match(parts, false)
+}
+
+func globPath(relPath string) []string {
+	return strings.Split(strings.ReplaceAll(filepath.ToSlash(relPath), "\\", "/"), "/")
+}
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// lfsPointerMaxSize bounds the size of a Git LFS pointer file; real pointers
+// are around 130 bytes.
+const lfsPointerMaxSize = 1024
+
+// lfsPointerPrefix starts every Git LFS pointer file.
+var lfsPointerPrefix = []byte("version https://git-lfs.github.com/spec/")
+
+// isLFSPointer reports whether content is a Git LFS pointer standing in for
+// the real file, as found in archives and clones without LFS.
+func isLFSPointer(content []byte) bool {
+	return len(content) <= lfsPointerMaxSize &&
+		bytes.HasPrefix(content, lfsPointerPrefix) &&
+		bytes.Contains(content, []byte("\noid sha256:"))
+}
+
+// LFSAvailable reports whether the git-lfs extension is installed.
+func LFSAvailable() bool {
+	_, err := exec.LookPath("git-lfs")
+	return err == nil
+}
+
+// PullLFS replaces the LFS pointer files of the cloned repository at dir
+// with their content.
+func PullLFS(ctx context.Context, dir string) error {
+	cmd := exec.CommandContext(ctx, "git", "lfs", "pull")
+	cmd.Dir = dir
+	killProcessGroup(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git lfs pull failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
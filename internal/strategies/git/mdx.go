@@ -0,0 +1,115 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/quantmind-br/repodocs/internal/converter"
+)
+
+var (
+	// mdxCommentPattern matches HTML comments and MDX expression comments.
+	mdxCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->|\{/\*.*?\*/\}`)
+	// mdxComponentLinePattern matches a line holding only a JSX component
+	// tag, such as <Tabs>, </TabItem> or <Callout type="info" />.
+	mdxComponentLinePattern = regexp.MustCompile(`^</?[A-Z][\w.]*(\s[^<>]*)?/?>$`)
+	// mdxComponentOpenPattern matches the first line of a component tag whose
+	// attributes continue on the following lines.
+	mdxComponentOpenPattern = regexp.MustCompile(`^<[A-Z][\w.]*(\s[^<>]*)?$`)
+	mdxBlankLinesPattern    = regexp.MustCompile(`\n{3,}`)
+)
+
+// cleanMDX removes MDX-only syntax from content while keeping prose and code:
+// import/export statements, lines holding only JSX component tags, and HTML
+// or MDX comments. Code fences are left untouched. A leading YAML
+// front-matter block is removed and its parsed fields are returned.
+func cleanMDX(content string) (string, map[string]any) {
+	fields, rest, ok := converter.SplitFrontMatter(content)
+	if ok {
+		content = rest
+	}
+
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	var fence string
+	var prose []string
+
+	flushProse := func() {
+		if len(prose) > 0 {
+			text := mdxCommentPattern.ReplaceAllString(strings.Join(prose, "\n"), "")
+			out = append(out, text)
+			prose = prose[:0]
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			out = append(out, line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			flushProse()
+			fence = marker
+			out = append(out, line)
+			continue
+		}
+
+		switch {
+		case isMDXStatement(trimmed):
+			i = skipMDXStatement(lines, i)
+		case mdxComponentLinePattern.MatchString(trimmed):
+		case mdxComponentOpenPattern.MatchString(trimmed):
+			for i+1 < len(lines) && !strings.Contains(lines[i], ">") {
+				i++
+			}
+		default:
+			prose = append(prose, line)
+		}
+	}
+	flushProse()
+
+	cleaned := strings.Join(out, "\n")
+	cleaned = mdxBlankLinesPattern.ReplaceAllString(cleaned, "\n\n")
+	return strings.TrimSpace(cleaned) + "\n", fields
+}
+
+// fenceMarker returns the fence that opens a code block on line, if any.
+func fenceMarker(line string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// isMDXStatement reports whether line starts an ESM import or export.
+func isMDXStatement(line string) bool {
+	return strings.HasPrefix(line, "import ") || strings.HasPrefix(line, "import{") ||
+		strings.HasPrefix(line, "export ")
+}
+
+// skipMDXStatement returns the index of the last line of the import or
+// export statement starting at lines[start], following brackets that span
+// lines such as `export const meta = {`.
+func skipMDXStatement(lines []string, start int) int {
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if i > start && line == "" {
+			return i - 1
+		}
+		depth += strings.Count(line, "{") + strings.Count(line, "(") + strings.Count(line, "[")
+		depth -= strings.Count(line, "}") + strings.Count(line, ")") + strings.Count(line, "]")
+		if depth <= 0 && !strings.HasSuffix(line, ",") && !strings.HasSuffix(line, "=") {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
@@ -5,13 +5,31 @@ import (
 	"net/url"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
 type platformPattern struct {
 	platform    Platform
+	host        string
+	selfHosted  bool
 	repoPattern *regexp.Regexp
-	treePattern *regexp.Regexp
+	// urlPattern matches the owner and repository of http(s) and SSH URLs
+	// for ParseURL.
+	urlPattern    *regexp.Regexp
+	treePattern   *regexp.Regexp
+	commitPattern *regexp.Regexp
+}
+
+// ParserOptions configures a Parser.
+type ParserOptions struct {
+	// SelfHostedHosts maps the hosts of self-hosted instances, such as
+	// "gitlab.internal.corp", to the platform they run. Their URLs are
+	// parsed like those of the platform's public host.
+	SelfHostedHosts map[string]Platform
 }
 
 // Parser extracts repository, branch, and subpath information from git hosting URLs.
@@ -19,65 +37,192 @@ type Parser struct {
 	patterns []platformPattern
 }
 
+// treePatterns match the branch and subpath of each platform's tree URLs.
+var treePatterns = map[Platform]*regexp.Regexp{
+	PlatformGitHub:    regexp.MustCompile(`/tree/([^/]+)(?:/(.+))?$`),
+	PlatformGitLab:    regexp.MustCompile(`/-/tree/([^/]+)(?:/(.+))?$`),
+	PlatformBitbucket: regexp.MustCompile(`/src/([^/]+)(?:/(.+))?$`),
+}
+
+// commitPatterns match the SHA of each platform's commit URLs.
+var commitPatterns = map[Platform]*regexp.Regexp{
+	PlatformGitHub:    regexp.MustCompile(`/commit/([0-9a-fA-F]{7,40})(?:/|$)`),
+	PlatformGitLab:    regexp.MustCompile(`/-/commit/([0-9a-fA-F]{7,40})(?:/|$)`),
+	PlatformBitbucket: regexp.MustCompile(`/commits/([0-9a-fA-F]{7,40})(?:/|$)`),
+}
+
+var (
+	// versionTagPattern matches version names such as v2.3.0 or 1.0-rc1,
+	// used for release tags and maintenance branches alike.
+	versionTagPattern = regexp.MustCompile(`^v?\d+(\.\d+)+([-+][0-9A-Za-z.-]+)?$`)
+	// commitSHAPattern matches a full commit SHA.
+	commitSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+)
+
 // NewParser creates a Parser with patterns for GitHub, GitLab, and Bitbucket URLs.
 func NewParser() *Parser {
-	return &Parser{
-		patterns: []platformPattern{
-			{
-				platform:    PlatformGitHub,
-				repoPattern: regexp.MustCompile(`^(https?://github\.com/([^/]+)/([^/]+?))(\.git)?(/|$)`),
-				treePattern: regexp.MustCompile(`/tree/([^/]+)(?:/(.+))?$`),
-			},
-			{
-				platform:    PlatformGitLab,
-				repoPattern: regexp.MustCompile(`^(https?://gitlab\.com/([^/]+)/([^/]+?))(\.git)?(/|$)`),
-				treePattern: regexp.MustCompile(`/-/tree/([^/]+)(?:/(.+))?$`),
-			},
-			{
-				platform:    PlatformBitbucket,
-				repoPattern: regexp.MustCompile(`^(https?://bitbucket\.org/([^/]+)/([^/]+?))(\.git)?(/|$)`),
-				treePattern: regexp.MustCompile(`/src/([^/]+)(?:/(.+))?$`),
-			},
-		},
+	return NewParserWithOptions(ParserOptions{})
+}
+
+// NewParserWithOptions creates a Parser that also recognizes the self-hosted
+// instances in opts. Hosts mapped to a platform other than GitHub, GitLab, or
+// Bitbucket are ignored.
+func NewParserWithOptions(opts ParserOptions) *Parser {
+	p := &Parser{}
+	for _, platform := range []Platform{PlatformGitHub, PlatformGitLab, PlatformBitbucket} {
+		p.patterns = append(p.patterns, newPlatformPattern(platform, platformHosts[platform].host, false))
+	}
+
+	hosts := make([]string, 0, len(opts.SelfHostedHosts))
+	for host := range opts.SelfHostedHosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		platform := opts.SelfHostedHosts[host]
+		host = strings.ToLower(strings.TrimSpace(host))
+		if _, ok := treePatterns[platform]; !ok || host == "" || p.platformFor(host) != "" {
+			continue
+		}
+		p.patterns = append(p.patterns, newPlatformPattern(platform, host, true))
 	}
+	return p
 }
 
-// ParseURL parses a repository URL into platform, owner, repository, and original URL fields.
-func (p *Parser) ParseURL(rawURL string) (*RepoInfo, error) {
-	patterns := []struct {
-		platform Platform
-		regex    *regexp.Regexp
-	}{
-		{PlatformGitHub, regexp.MustCompile(`github\.com[:/]([^/]+)/([^/.]+)`)},
-		{PlatformGitLab, regexp.MustCompile(`gitlab\.com[:/]([^/]+)/([^/.]+)`)},
-		{PlatformBitbucket, regexp.MustCompile(`bitbucket\.org[:/]([^/]+)/([^/.]+)`)},
+func newPlatformPattern(platform Platform, host string, selfHosted bool) platformPattern {
+	urlExpr := regexp.QuoteMeta(host) + `[:/]([^/]+)/([^/.]+)`
+	if selfHosted {
+		urlExpr = `(?i)` + urlExpr
 	}
+	return platformPattern{
+		platform:      platform,
+		host:          host,
+		selfHosted:    selfHosted,
+		repoPattern:   regexp.MustCompile(`^(https?://` + regexp.QuoteMeta(host) + `/([^/]+)/([^/]+?))(\.git)?(/|$)`),
+		urlPattern:    regexp.MustCompile(urlExpr),
+		treePattern:   treePatterns[platform],
+		commitPattern: commitPatterns[platform],
+	}
+}
 
-	for _, pat := range patterns {
-		if matches := pat.regex.FindStringSubmatch(rawURL); len(matches) == 3 {
-			return &RepoInfo{
+// platformFor returns the platform served at host, or "" when host is not
+// a known platform host.
+func (p *Parser) platformFor(host string) Platform {
+	host = strings.ToLower(host)
+	for _, pat := range p.patterns {
+		if pat.host == host {
+			return pat.platform
+		}
+	}
+	return ""
+}
+
+// CanHandle reports whether rawURL is an http(s) or SSH URL on a public or
+// registered self-hosted platform host.
+func (p *Parser) CanHandle(rawURL string) bool {
+	return p.platformFor(urlHost(rawURL)) != ""
+}
+
+// IsSelfHosted reports whether rawURL is on a registered self-hosted host.
+func (p *Parser) IsSelfHosted(rawURL string) bool {
+	host := urlHost(rawURL)
+	for _, pat := range p.patterns {
+		if pat.selfHosted && pat.host == host {
+			return true
+		}
+	}
+	return false
+}
+
+// urlHost returns the lower-cased host of an http(s) URL or of an SCP-like
+// SSH URL (git@host:owner/repo), or "" for other URLs.
+func urlHost(rawURL string) string {
+	if rest, ok := strings.CutPrefix(rawURL, "git@"); ok {
+		host, _, _ := strings.Cut(rest, ":")
+		return strings.ToLower(host)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ssh") {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// baseURL returns the scheme and host of a self-hosted repository URL,
+// defaulting to https for SSH URLs.
+func baseURL(rawURL, host string) string {
+	if strings.HasPrefix(rawURL, "http://") {
+		return "http://" + host
+	}
+	return "https://" + host
+}
+
+// ParseURL parses a repository URL into platform, owner, repository, and original URL fields.
+// URLs on self-hosted instances also get their BaseURL.
+func (p *Parser) ParseURL(rawURL string) (*RepoInfo, error) {
+	host := urlHost(rawURL)
+	selfHosted := p.IsSelfHosted(rawURL)
+	canonical := utils.CanonicalRepoURL(rawURL)
+	for _, pat := range p.patterns {
+		if pat.selfHosted != selfHosted || (selfHosted && pat.host != host) {
+			continue
+		}
+		if matches := pat.urlPattern.FindStringSubmatch(canonical); len(matches) == 3 {
+			info := &RepoInfo{
 				Platform: pat.platform,
 				Owner:    matches[1],
-				Repo:     strings.TrimSuffix(matches[2], ".git"),
+				Repo:     matches[2],
 				URL:      rawURL,
-			}, nil
+			}
+			if pat.selfHosted {
+				info.BaseURL = baseURL(rawURL, pat.host)
+			}
+			return info, nil
 		}
 	}
 
 	return nil, fmt.Errorf("unsupported git URL format: %s", rawURL)
 }
 
+// platformHosts maps hosted platforms to their host and display name.
+var platformHosts = map[Platform]struct{ host, name string }{
+	PlatformGitHub:    {host: "github.com", name: "GitHub"},
+	PlatformGitLab:    {host: "gitlab.com", name: "GitLab"},
+	PlatformBitbucket: {host: "bitbucket.org", name: "Bitbucket"},
+}
+
+// Validate returns a *domain.ValidationError when rawURL is an http(s) URL on
+// a hosted platform without both an owner and a repository in the path.
+// Other URLs are left to ParseURLWithPath.
+func (p *Parser) Validate(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil
+	}
+
+	host := strings.ToLower(u.Host)
+	for _, pat := range p.patterns {
+		if host != pat.host || pat.repoPattern.MatchString(rawURL) {
+			continue
+		}
+		return domain.NewValidationError("url", fmt.Sprintf("invalid %s repo path %q: expected https://%s/{owner}/{repo}",
+			platformHosts[pat.platform].name, u.Path, pat.host))
+	}
+	return nil
+}
+
 // ParseURLWithPath parses a repository URL plus optional tree path into structured git URL information.
 func (p *Parser) ParseURLWithPath(rawURL string) (*GitURLInfo, error) {
 	info := &GitURLInfo{}
-	lower := strings.ToLower(rawURL)
+	canonical := utils.CanonicalRepoURL(rawURL)
+	lower := strings.ToLower(canonical)
 
 	for _, pat := range p.patterns {
-		if !strings.Contains(lower, string(pat.platform)) {
+		if !strings.Contains(lower, pat.host) {
 			continue
 		}
 
-		repoMatches := pat.repoPattern.FindStringSubmatch(rawURL)
+		repoMatches := pat.repoPattern.FindStringSubmatch(canonical)
 		if len(repoMatches) < 4 {
 			continue
 		}
@@ -85,20 +230,32 @@ func (p *Parser) ParseURLWithPath(rawURL string) (*GitURLInfo, error) {
 		info.Platform = pat.platform
 		info.RepoURL = repoMatches[1]
 		info.Owner = repoMatches[2]
-		info.Repo = strings.TrimSuffix(repoMatches[3], ".git")
+		info.Repo = repoMatches[3]
+		if pat.selfHosted {
+			info.BaseURL = baseURL(rawURL, pat.host)
+		}
 
-		treeMatches := pat.treePattern.FindStringSubmatch(rawURL)
+		treeMatches := pat.treePattern.FindStringSubmatch(canonical)
 		if len(treeMatches) >= 2 {
-			info.Branch = treeMatches[1]
+			info.Ref = treeMatches[1]
+			info.RefType = treeRefType(info.Ref)
+			if info.RefType == RefBranch {
+				info.Branch = info.Ref
+			}
 			if len(treeMatches) >= 3 && treeMatches[2] != "" {
 				info.SubPath = NormalizeFilterPath(treeMatches[2])
 			}
+		} else if commitMatches := pat.commitPattern.FindStringSubmatch(canonical); len(commitMatches) >= 2 {
+			info.Ref = commitMatches[1]
+			info.RefType = RefCommit
 		}
 
 		return info, nil
 	}
 
-	if strings.HasPrefix(rawURL, "http://") || strings.HasPrefix(rawURL, "https://") {
+	// Local repositories (file://) are cloned like any other generic URL.
+	if strings.HasPrefix(rawURL, "http://") || strings.HasPrefix(rawURL, "https://") ||
+		strings.HasPrefix(rawURL, "file://") {
 		info.Platform = PlatformGeneric
 		info.RepoURL = rawURL
 		return info, nil
@@ -107,6 +264,21 @@ func (p *Parser) ParseURLWithPath(rawURL string) (*GitURLInfo, error) {
 	return nil, fmt.Errorf("unsupported git URL format: %s", rawURL)
 }
 
+// treeRefType guesses the kind of ref in a tree URL, which names branches,
+// tags, and commits alike: full SHAs are commits, version numbers may be
+// either a tag or a branch (such as CPython's 3.12), and anything else is a
+// branch.
+func treeRefType(ref string) RefType {
+	switch {
+	case commitSHAPattern.MatchString(ref):
+		return RefCommit
+	case versionTagPattern.MatchString(ref):
+		return RefName
+	default:
+		return RefBranch
+	}
+}
+
 // NormalizeFilterPath converts a URL or path string into a clean repository-relative path.
 func NormalizeFilterPath(path string) string {
 	if path == "" {
@@ -2,9 +2,8 @@ package git
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -19,17 +18,83 @@ import (
 
 // Processor discovers documentation files in fetched repositories and converts them to documents.
 type Processor struct {
-	logger *utils.Logger
+	logger          *utils.Logger
+	ignoreDirs      map[string]bool
+	extensions      map[string]bool
+	cleanMDX        bool
+	frontMatterKeys []string
 }
 
 // ProcessorOptions configures a Processor.
 type ProcessorOptions struct {
 	Logger *utils.Logger
+	// IgnoreDirs names extra directories skipped during discovery, added to
+	// the default IgnoreDirs.
+	IgnoreDirs []string
+	// ReplaceIgnoreDirs makes IgnoreDirs replace the defaults instead of
+	// extending them.
+	ReplaceIgnoreDirs bool
+	// CleanMDX strips import/export statements, JSX-only lines and comments
+	// from .mdx files and lifts their front-matter title and description
+	// into the document.
+	CleanMDX bool
+	// FrontMatterKeys names further MDX front-matter keys kept as document
+	// metadata when CleanMDX is set.
+	FrontMatterKeys []string
+	// Extensions replaces DocumentExtensions as the set of document file
+	// extensions (lower-case, with the leading dot) discovered; nil keeps
+	// the defaults. Configuration files are discovered either way.
+	Extensions map[string]bool
 }
 
 // NewProcessor creates a repository documentation processor.
 func NewProcessor(opts ProcessorOptions) *Processor {
-	return &Processor{logger: opts.Logger}
+	return &Processor{
+		logger:          opts.Logger,
+		ignoreDirs:      buildIgnoreDirs(opts.IgnoreDirs, opts.ReplaceIgnoreDirs),
+		extensions:      buildExtensions(opts.Extensions),
+		cleanMDX:        opts.CleanMDX,
+		frontMatterKeys: opts.FrontMatterKeys,
+	}
+}
+
+// buildIgnoreDirs returns the directory names skipped during discovery.
+func buildIgnoreDirs(extra []string, replace bool) map[string]bool {
+	if len(extra) == 0 && !replace {
+		return IgnoreDirs
+	}
+	dirs := make(map[string]bool, len(IgnoreDirs)+len(extra))
+	if !replace {
+		for name := range IgnoreDirs {
+			dirs[name] = true
+		}
+	}
+	for _, name := range extra {
+		if name = strings.Trim(strings.TrimSpace(name), "/"); name != "" {
+			dirs[name] = true
+		}
+	}
+	return dirs
+}
+
+// buildExtensions returns the document extensions discovered, normalizing
+// an override to lower case with a leading dot.
+func buildExtensions(override map[string]bool) map[string]bool {
+	if override == nil {
+		return DocumentExtensions
+	}
+	exts := make(map[string]bool, len(override))
+	for ext, ok := range override {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if !ok || ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts[ext] = true
+	}
+	return exts
 }
 
 // ProcessOptions controls file processing and output for a fetched repository.
@@ -41,14 +106,58 @@ type ProcessOptions struct {
 	Limit        int
 	DryRun       bool
 	MaxFileSize  int64
+	PreserveTree bool
+	// NoProgress hides the progress bar of ProcessFiles.
+	NoProgress   bool
 	WriteFunc    func(ctx context.Context, doc *domain.Document) error
 	StateManager *state.Manager
-	Result       *domain.StrategyResult
+	// HashAlgorithm is the digest of document content hashes, matching the
+	// one the sync state is written with (empty means sha256).
+	HashAlgorithm converter.HashAlgorithm
+	Result        *domain.StrategyResult
+}
+
+// DiscoveryOptions controls which repository files FindFiles returns.
+type DiscoveryOptions struct {
+	// FilterPath restricts discovery to a subdirectory of the repository.
+	FilterPath string
+	// HonorGitignore also excludes paths matched by the repository's root
+	// .gitignore. A root .repodocsignore is always honored.
+	HonorGitignore bool
+	// IncludeGitHubMeta also extracts issue/discussion template forms and
+	// CODEOWNERS from .github. Markdown under .github is always extracted.
+	IncludeGitHubMeta bool
+	// Include, when set, keeps only files matching one of its patterns,
+	// such as "docs/**"; Exclude then removes matching files, such as
+	// "docs/archive/**". Both use gitignore syntax relative to the
+	// repository root, so a later "!pattern" re-adds what an earlier one
+	// matched.
+	Include []string
+	Exclude []string
 }
 
 // FindDocumentationFiles walks dir or filterPath and returns documentation and configuration files.
-func (p *Processor) FindDocumentationFiles(dir string, filterPath string) ([]string, error) {
+func (p *Processor) FindDocumentationFiles(ctx context.Context, dir string, filterPath string) ([]string, error) {
+	return p.FindFiles(ctx, dir, DiscoveryOptions{FilterPath: filterPath})
+}
+
+// FindFiles walks the repository at dir and returns documentation and
+// configuration files. The processor's ignored directories are always
+// skipped; ignore files at the repository root exclude further paths. The
+// walk stops at the next entry once ctx is cancelled and returns ctx.Err().
+func (p *Processor) FindFiles(ctx context.Context, dir string, opts DiscoveryOptions) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	var files []string
+	filterPath := opts.FilterPath
+
+	matcher, err := loadIgnoreMatcher(dir, opts.HonorGitignore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignore file: %w", err)
+	}
+	globs := newGlobFilter(opts.Include, opts.Exclude)
 
 	walkDir := dir
 	if filterPath != "" {
@@ -70,34 +179,82 @@ func (p *Processor) FindDocumentationFiles(dir string, filterPath string) ([]str
 		}
 	}
 
-	err := filepath.WalkDir(walkDir, func(path string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(walkDir, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
 			return err
 		}
 
+		relPath, _ := filepath.Rel(dir, path)
 		if d.IsDir() {
-			if IgnoreDirs[d.Name()] {
+			if p.ignoreDirs[d.Name()] || ignored(matcher, relPath, true) || globs.skipDir(relPath) {
 				return fs.SkipDir
 			}
 			return nil
 		}
+		if !globs.allows(relPath) {
+			return nil
+		}
+		if ignored(matcher, relPath, false) {
+			if p.logger != nil {
+				p.logger.Debug().Str("file", relPath).Msg("Skipping ignored file")
+			}
+			return nil
+		}
 
 		ext := strings.ToLower(filepath.Ext(path))
-		if DocumentExtensions[ext] || ConfigExtensions[ext] {
+		if inGitHub, meta := githubPath(relPath); inGitHub && !p.extensions[ext] {
+			if opts.IncludeGitHubMeta && meta {
+				files = append(files, path)
+			}
+			return nil
+		}
+		if p.extensions[ext] || ConfigExtensions[ext] {
 			files = append(files, path)
 		}
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
 
-	return files, err
+// githubPath reports whether relPath lies in the repository's .github
+// directory and, if so, whether it is repository metadata worth extracting
+// with IncludeGitHubMeta (issue and discussion templates, CODEOWNERS) rather
+// than CI configuration such as workflows.
+func githubPath(relPath string) (inGitHub, meta bool) {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	if len(parts) < 2 || parts[0] != ".github" {
+		return false, false
+	}
+	switch {
+	case parts[1] == "ISSUE_TEMPLATE", parts[1] == "DISCUSSION_TEMPLATE":
+		return true, len(parts) == 3
+	case len(parts) == 2 && parts[1] == "CODEOWNERS":
+		return true, true
+	}
+	return true, false
 }
 
 // ProcessFiles processes files concurrently and writes each resulting document through ProcessOptions.WriteFunc.
+// Once ctx is cancelled no further file is started and ctx.Err() is returned.
 func (p *Processor) ProcessFiles(ctx context.Context, files []string, tmpDir string, opts ProcessOptions) error {
 	bar := utils.NewProgressBar(len(files), utils.DescExtracting)
+	if opts.NoProgress {
+		bar = utils.NewProgressBarTo(io.Discard, len(files), utils.DescExtracting)
+	}
 
 	errors := utils.ParallelForEach(ctx, files, opts.Concurrency, func(ctx context.Context, file string) error {
+		// Queued files are still handed out after cancellation.
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		defer bar.Add(1)
 
 		if err := p.ProcessFile(ctx, file, tmpDir, opts); err != nil {
@@ -108,6 +265,9 @@ func (p *Processor) ProcessFiles(ctx context.Context, files []string, tmpDir str
 		return nil
 	})
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if err := utils.FirstError(errors); err != nil {
 		return err
 	}
@@ -124,7 +284,7 @@ func (p *Processor) ProcessFile(ctx context.Context, path, tmpDir string, opts P
 
 	info, err := os.Stat(path)
 	if err != nil {
-		opts.Result.IncFailed()
+		opts.Result.FailDocument(path, err)
 		return err
 	}
 	maxSize := opts.MaxFileSize
@@ -138,7 +298,7 @@ func (p *Processor) ProcessFile(ctx context.Context, path, tmpDir string, opts P
 
 	content, err := os.ReadFile(path)
 	if err != nil {
-		opts.Result.IncFailed()
+		opts.Result.FailDocument(path, err)
 		return err
 	}
 
@@ -146,18 +306,33 @@ func (p *Processor) ProcessFile(ctx context.Context, path, tmpDir string, opts P
 	relPathURL := strings.ReplaceAll(relPath, "\\", "/")
 	fileURL := opts.RepoURL + "/blob/" + opts.Branch + "/" + relPathURL
 
-	contentHash := computeHash(content)
+	if isLFSPointer(content) {
+		if p.logger != nil {
+			p.logger.Warn().Str("file", relPath).Msg("Skipping Git LFS pointer file; use --lfs to fetch its content")
+		}
+		opts.Result.IncSkipped()
+		return nil
+	}
+
+	text, ok := decodeText(content)
+	if !ok {
+		if p.logger != nil {
+			p.logger.Debug().Str("file", relPath).Msg("Skipping binary file")
+		}
+		opts.Result.IncSkipped()
+		return nil
+	}
 
 	doc := &domain.Document{
 		URL:            fileURL,
 		Title:          ExtractTitleFromPath(relPath),
-		Content:        string(content),
-		ContentHash:    contentHash,
+		Content:        text,
 		FetchedAt:      time.Now(),
-		WordCount:      len(strings.Fields(string(content))),
-		CharCount:      len(content),
+		WordCount:      len(strings.Fields(text)),
+		CharCount:      len(text),
 		SourceStrategy: "git",
 		RelativePath:   relPath,
+		PreserveTree:   opts.PreserveTree,
 	}
 
 	ext := strings.ToLower(filepath.Ext(path))
@@ -165,12 +340,12 @@ func (p *Processor) ProcessFile(ctx context.Context, path, tmpDir string, opts P
 	case ConfigExtensions[ext]:
 		doc.IsRawFile = true
 	case ext == ".rst":
-		md, convErr := converter.ConvertRST(content)
+		md, convErr := converter.ConvertRST([]byte(text))
 		if convErr != nil {
 			if p.logger != nil {
 				p.logger.Warn().Err(convErr).Str("file", relPath).Msg("RST conversion failed, falling back to raw")
 			}
-			doc.Content = "```\n" + string(content) + "\n```"
+			doc.Content = "```\n" + text + "\n```"
 			doc.WordCount = len(strings.Fields(doc.Content))
 			doc.CharCount = len(doc.Content)
 		} else {
@@ -178,15 +353,30 @@ func (p *Processor) ProcessFile(ctx context.Context, path, tmpDir string, opts P
 			doc.WordCount = len(strings.Fields(doc.Content))
 			doc.CharCount = len(doc.Content)
 		}
+	case ext == ".mdx" && p.cleanMDX:
+		content, fields := cleanMDX(text)
+		doc.Content = content
+		doc.WordCount = len(strings.Fields(doc.Content))
+		doc.CharCount = len(doc.Content)
+		applyFrontMatter(doc, fields, p.frontMatterKeys)
 	case ext != ".md" && ext != ".mdx":
-		doc.Content = "```\n" + string(content) + "\n```"
+		doc.Content = "```\n" + text + "\n```"
 		doc.WordCount = len(strings.Fields(doc.Content))
 		doc.CharCount = len(doc.Content)
 	}
 
+	// Hash the converted content, as stored in the sync state, with the
+	// configured algorithm so unchanged files match on the next run.
+	algo := opts.HashAlgorithm
+	if algo == "" {
+		algo = converter.HashSHA256
+	}
+	doc.ContentHash = converter.ContentHashWith(doc.Content, algo)
+	doc.HashAlgorithm = string(algo)
+
 	if opts.StateManager != nil {
 		opts.StateManager.MarkSeen(fileURL)
-		if !opts.StateManager.ShouldProcess(fileURL, contentHash) {
+		if !opts.StateManager.ShouldProcess(fileURL, doc.ContentHash) {
 			if p.logger != nil {
 				p.logger.Debug().Str("file", relPath).Msg("Skipping unchanged file")
 			}
@@ -197,7 +387,7 @@ func (p *Processor) ProcessFile(ctx context.Context, path, tmpDir string, opts P
 
 	if !opts.DryRun && opts.WriteFunc != nil {
 		if err := opts.WriteFunc(ctx, doc); err != nil {
-			opts.Result.IncFailed()
+			opts.Result.FailDocument(fileURL, err)
 			return err
 		}
 		opts.Result.IncWritten()
@@ -212,9 +402,18 @@ func (p *Processor) ProcessFile(ctx context.Context, path, tmpDir string, opts P
 	return nil
 }
 
-func computeHash(content []byte) string {
-	hash := sha256.Sum256(content)
-	return hex.EncodeToString(hash[:])
+// applyFrontMatter lifts the title and description of parsed front-matter
+// into doc and keeps keys as metadata.
+func applyFrontMatter(doc *domain.Document, fields map[string]any, keys []string) {
+	if title, ok := fields["title"].(string); ok && strings.TrimSpace(title) != "" {
+		doc.Title = strings.TrimSpace(title)
+	}
+	if description, ok := fields["description"].(string); ok {
+		doc.Description = strings.TrimSpace(description)
+	}
+	if metadata := converter.FrontMatterMetadata(fields, keys); len(metadata) > 0 {
+		doc.Metadata = metadata
+	}
 }
 
 // ExtractTitleFromPath creates a display title from a repository-relative file path.
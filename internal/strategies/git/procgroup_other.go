@@ -0,0 +1,11 @@
+//go:build !unix
+
+package git
+
+import "os/exec"
+
+// killProcessGroup bounds how long a cancelled cmd is waited for; only the
+// git process itself is killed on this platform.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.WaitDelay = processWaitDelay
+}
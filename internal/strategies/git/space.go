@@ -0,0 +1,82 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// ErrInsufficientSpace is returned when a repository download would not fit
+// in the free space of the temp or output directory.
+var ErrInsufficientSpace = errors.New("insufficient disk space")
+
+const (
+	// archiveExpansion estimates the extracted size of a repository archive
+	// from its compressed size.
+	archiveExpansion = 4
+	// minFreeSpace is required when the download size is unknown, as for
+	// clones and archives served without a Content-Length.
+	minFreeSpace = 100 << 20
+)
+
+// SpaceChecker verifies, before a repository is downloaded, that the temp
+// and output directories have room for it. The needs are estimates: an
+// archive is assumed to extract to archiveExpansion times its size, and the
+// written documents to take at most the size of the archive.
+type SpaceChecker struct {
+	// OutputDir is the directory documents are written to.
+	OutputDir string
+	// FreeSpace reports the free bytes of a directory; nil uses
+	// utils.FreeSpace.
+	FreeSpace func(dir string) (uint64, error)
+}
+
+// Check returns ErrInsufficientSpace when a download of downloadSize bytes
+// (negative if unknown) into tempDir would not fit. Directories whose free
+// space cannot be measured are not checked. A nil checker checks nothing.
+func (c *SpaceChecker) Check(tempDir string, downloadSize int64) error {
+	if c == nil {
+		return nil
+	}
+
+	tempNeed, outputNeed := uint64(minFreeSpace), uint64(minFreeSpace)
+	if downloadSize > 0 {
+		tempNeed = uint64(downloadSize) * archiveExpansion
+		outputNeed = uint64(downloadSize)
+	}
+
+	if err := c.check(tempDir, tempNeed, "download the repository"); err != nil {
+		return err
+	}
+	if c.OutputDir == "" {
+		return nil
+	}
+	return c.check(c.OutputDir, outputNeed, "write its documents")
+}
+
+func (c *SpaceChecker) check(dir string, need uint64, purpose string) error {
+	freeSpace := c.FreeSpace
+	if freeSpace == nil {
+		freeSpace = utils.FreeSpace
+	}
+	free, err := freeSpace(dir)
+	if err != nil || free >= need {
+		return nil
+	}
+	return fmt.Errorf("%w: %s has %s free, about %s is needed to %s (use --no-space-check to skip this check)",
+		ErrInsufficientSpace, dir, formatSize(free), formatSize(need), purpose)
+}
+
+func formatSize(n uint64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
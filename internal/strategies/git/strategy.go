@@ -2,12 +2,16 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/quantmind-br/repodocs/internal/converter"
 	"github.com/quantmind-br/repodocs/internal/domain"
 	"github.com/quantmind-br/repodocs/internal/output"
 	"github.com/quantmind-br/repodocs/internal/state"
@@ -21,6 +25,25 @@ type StrategyDependencies struct {
 	HTTPClient   *http.Client
 	WriteFunc    func(ctx context.Context, doc *domain.Document) error
 	StateManager *state.Manager
+	// HashAlgorithm is the digest of document content hashes used for sync
+	// checks (empty means sha256).
+	HashAlgorithm converter.HashAlgorithm
+	// Branches resolves and caches default branches; nil creates a detector
+	// for this strategy unless a custom HTTPClient is supplied.
+	Branches *BranchDetector
+	// SpaceCheck verifies there is disk space for archives and clones
+	// before they are downloaded; nil skips the check.
+	SpaceCheck *SpaceChecker
+	// KeepTemp leaves the temporary directory a repository is downloaded
+	// to in place after Execute, for troubleshooting.
+	KeepTemp bool
+	// SelfHostedHosts maps the hosts of self-hosted GitHub, GitLab, and
+	// Bitbucket instances to their platform (see ParserOptions).
+	SelfHostedHosts map[string]Platform
+	// ProxyURL routes archive downloads, tree listings and default-branch
+	// lookups through a proxy when no HTTPClient is supplied (see
+	// NewHTTPClient).
+	ProxyURL string
 }
 
 // Strategy coordinates git URL parsing, repository acquisition, file discovery, and document output.
@@ -29,10 +52,14 @@ type Strategy struct {
 	parser           *Parser
 	archiveFetcher   *ArchiveFetcher
 	cloneFetcher     *CloneFetcher
+	treeFetcher      *TreeFetcher
 	processor        *Processor
 	logger           *utils.Logger
 	httpClient       *http.Client
+	branches         *BranchDetector
 	skipBranchDetect bool
+	spaceCheck       *SpaceChecker
+	keepTemp         bool
 }
 
 // NewStrategy creates a git extraction strategy with archive, clone, parser, and processor components.
@@ -41,7 +68,7 @@ func NewStrategy(deps *StrategyDependencies) *Strategy {
 	var skipBranchDetect bool
 
 	if deps == nil {
-		client = createDefaultHTTPClient()
+		client = NewHTTPClient("")
 		return &Strategy{
 			httpClient: client,
 			parser:     NewParser(),
@@ -50,30 +77,62 @@ func NewStrategy(deps *StrategyDependencies) *Strategy {
 
 	client = deps.HTTPClient
 	if client == nil {
-		client = createDefaultHTTPClient()
+		client = NewHTTPClient(deps.ProxyURL)
 	} else {
 		skipBranchDetect = true
 	}
 
 	logger := deps.Logger
 
+	branches := deps.Branches
+	if branches == nil && !skipBranchDetect {
+		branches = NewBranchDetector(BranchDetectorOptions{HTTPClient: client, Logger: logger})
+	}
+
 	return &Strategy{
 		deps:   deps,
-		parser: NewParser(),
+		parser: NewParserWithOptions(ParserOptions{SelfHostedHosts: deps.SelfHostedHosts}),
 		archiveFetcher: NewArchiveFetcher(ArchiveFetcherOptions{
 			HTTPClient: client,
 			Logger:     logger,
+			SpaceCheck: deps.SpaceCheck,
 		}),
 		cloneFetcher: NewCloneFetcher(CloneFetcherOptions{
-			Logger: logger,
+			Logger:     logger,
+			SpaceCheck: deps.SpaceCheck,
+		}),
+		treeFetcher: NewTreeFetcher(TreeFetcherOptions{
+			HTTPClient: client,
+			Logger:     logger,
 		}),
 		processor: NewProcessor(ProcessorOptions{
 			Logger: logger,
 		}),
 		logger:           logger,
 		httpClient:       client,
+		branches:         branches,
 		skipBranchDetect: skipBranchDetect,
+		spaceCheck:       deps.SpaceCheck,
+		keepTemp:         deps.KeepTemp,
+	}
+}
+
+// SetHTTPClient replaces the client archives, the GitHub tree API and the
+// default-branch API lookups go through. Clones use git's own transport.
+func (s *Strategy) SetHTTPClient(client *http.Client) {
+	s.httpClient = client
+	if s.branches != nil {
+		s.branches = s.branches.WithHTTPClient(client)
 	}
+	s.archiveFetcher = NewArchiveFetcher(ArchiveFetcherOptions{
+		HTTPClient: client,
+		Logger:     s.logger,
+		SpaceCheck: s.spaceCheck,
+	})
+	s.treeFetcher = NewTreeFetcher(TreeFetcherOptions{
+		HTTPClient: client,
+		Logger:     s.logger,
+	})
 }
 
 // Name returns the strategy identifier used by the extraction orchestrator.
@@ -97,6 +156,10 @@ func (s *Strategy) CanHandle(url string) bool {
 		return false
 	}
 
+	if s.parser != nil && s.parser.IsSelfHosted(url) {
+		return !strings.Contains(lower, "/blob/")
+	}
+
 	return strings.HasPrefix(url, "git@") ||
 		strings.HasSuffix(lower, ".git") ||
 		(strings.Contains(lower, "github.com") && !strings.Contains(lower, "/blob/")) ||
@@ -104,6 +167,12 @@ func (s *Strategy) CanHandle(url string) bool {
 		strings.Contains(lower, "bitbucket.org")
 }
 
+// Validate reports URLs that CanHandle accepts but that do not name a
+// repository, before any network access.
+func (s *Strategy) Validate(_ context.Context, url string) error {
+	return s.parser.Validate(url)
+}
+
 // ExecuteOptions configures one git strategy execution.
 type ExecuteOptions struct {
 	Output      string
@@ -111,7 +180,40 @@ type ExecuteOptions struct {
 	Limit       int
 	DryRun      bool
 	FilterURL   string
-	Result      *domain.StrategyResult
+	// PreserveTree writes files at their exact repository paths under the
+	// output root, bypassing the writer's filename sanitizing.
+	PreserveTree bool
+	// HonorGitignore excludes paths matched by the repository's .gitignore
+	// in addition to its .repodocsignore.
+	HonorGitignore bool
+	// IgnoreDirs names extra directories to skip, replacing the defaults
+	// when ReplaceIgnoreDirs is set.
+	IgnoreDirs        []string
+	ReplaceIgnoreDirs bool
+	// IncludeGitHubMeta extracts issue/discussion templates and CODEOWNERS
+	// from .github in addition to its Markdown.
+	IncludeGitHubMeta bool
+	// Include and Exclude filter discovered files by repository path (see
+	// DiscoveryOptions).
+	Include []string
+	Exclude []string
+	// Submodules clones the repository with its submodules initialized.
+	// Archives and the tree API never include submodule contents, so they
+	// are skipped.
+	Submodules bool
+	// LFS clones the repository and fetches Git LFS content with git-lfs
+	// when it is installed. LFS pointer files are always skipped.
+	LFS bool
+	// CloneTimeout bounds a git clone, independently of the per-request
+	// timeout; 0 means no limit.
+	CloneTimeout time.Duration
+	// CleanMDX strips MDX-only syntax from .mdx files, keeping
+	// FrontMatterKeys of their front-matter as document metadata.
+	CleanMDX        bool
+	FrontMatterKeys []string
+	// NoProgress hides the progress bar of file processing.
+	NoProgress bool
+	Result     *domain.StrategyResult
 }
 
 // Execute extracts repository documentation from rawURL and writes matching documents.
@@ -134,27 +236,59 @@ func (s *Strategy) Execute(ctx context.Context, rawURL string, opts ExecuteOptio
 		s.logger.Info().Str("filter_path", filterPath).Msg("Path filter active")
 	}
 
-	tmpDir, err := os.MkdirTemp("", "repodocs-git-*")
+	tmpDir, cleanup, err := utils.MakeTempDir("repodocs-git-*", s.keepTemp, s.logger)
 	if err != nil {
 		return fmt.Errorf("failed to create temp dir: %w", err)
 	}
-	defer os.RemoveAll(tmpDir)
+	defer cleanup()
+
+	pullLFS := opts.LFS && LFSAvailable()
+	if opts.LFS && !pullLFS && s.logger != nil {
+		s.logger.Warn().Msg("git-lfs is not installed; Git LFS files will be skipped")
+	}
+	// Archives and the tree API carry neither submodules nor LFS content.
+	cloneOnly := opts.Submodules || pullLFS
 
 	repoURL := urlInfo.RepoURL
-	branch, method, err := s.TryArchiveDownload(ctx, repoURL, tmpDir)
-	if err != nil {
-		if s.logger != nil {
-			s.logger.Info().Err(err).Msg("Archive download failed, using git clone")
+	repoDir := tmpDir
+	var branch, method string
+	if filterPath != "" && !cloneOnly {
+		treeDir, treeBranch, treeErr := s.tryTreeDownload(ctx, urlInfo, filterPath, tmpDir)
+		if treeErr == nil {
+			repoDir, branch, method = treeDir, treeBranch, s.treeFetcher.Name()
+		} else if s.logger != nil {
+			s.logger.Debug().Err(treeErr).Msg("API download unavailable, downloading the repository")
+		}
+	}
+
+	if method == "" && !cloneOnly {
+		branch, method, err = s.tryArchiveDownload(ctx, repoURL, urlInfo.Ref, urlInfo.RefType, tmpDir)
+		if errors.Is(err, ErrInsufficientSpace) {
+			// A clone would not fit either.
+			return err
+		}
+		if err != nil {
+			if s.logger != nil {
+				s.logger.Info().Err(err).Msg("Archive download failed, using git clone")
+			}
+			method = ""
 		}
-		branch, err = s.CloneRepository(ctx, repoURL, tmpDir)
+	}
+	if method == "" {
+		branch, err = s.cloneRepository(ctx, repoURL, urlInfo.Ref, urlInfo.RefType, tmpDir, opts.Submodules, opts.CloneTimeout)
 		if err != nil {
 			return fmt.Errorf("failed to acquire repository: %w", err)
 		}
 		method = "clone"
+		if pullLFS {
+			if err := PullLFS(ctx, tmpDir); err != nil && s.logger != nil {
+				s.logger.Warn().Err(err).Msg("Failed to fetch Git LFS content; LFS files will be skipped")
+			}
+		}
 	}
 
-	if urlInfo.Branch != "" {
-		branch = urlInfo.Branch
+	if urlInfo.Ref != "" {
+		branch = urlInfo.Ref
 	}
 
 	if s.logger != nil {
@@ -163,8 +297,28 @@ func (s *Strategy) Execute(ctx context.Context, rawURL string, opts ExecuteOptio
 			Str("branch", branch).
 			Msg("Repository acquired successfully")
 	}
+	if !opts.Submodules && HasSubmodules(repoDir) && s.logger != nil {
+		s.logger.Warn().Msg("Repository has git submodules that are not extracted; use --submodules to include them")
+	}
+
+	processor := s.processor
+	if len(opts.IgnoreDirs) > 0 || opts.ReplaceIgnoreDirs || opts.CleanMDX {
+		processor = NewProcessor(ProcessorOptions{
+			Logger:            s.logger,
+			IgnoreDirs:        opts.IgnoreDirs,
+			ReplaceIgnoreDirs: opts.ReplaceIgnoreDirs,
+			CleanMDX:          opts.CleanMDX,
+			FrontMatterKeys:   opts.FrontMatterKeys,
+		})
+	}
 
-	files, err := s.processor.FindDocumentationFiles(tmpDir, filterPath)
+	files, err := processor.FindFiles(ctx, repoDir, DiscoveryOptions{
+		FilterPath:        filterPath,
+		HonorGitignore:    opts.HonorGitignore,
+		IncludeGitHubMeta: opts.IncludeGitHubMeta,
+		Include:           opts.Include,
+		Exclude:           opts.Exclude,
+	})
 	if err != nil {
 		return err
 	}
@@ -192,16 +346,64 @@ func (s *Strategy) Execute(ctx context.Context, rawURL string, opts ExecuteOptio
 		Concurrency:  opts.Concurrency,
 		Limit:        opts.Limit,
 		DryRun:       opts.DryRun,
+		PreserveTree: opts.PreserveTree,
+		NoProgress:   opts.NoProgress,
 		WriteFunc:    s.deps.WriteFunc,
 		StateManager: s.deps.StateManager,
 		Result:       opts.Result,
+
+		HashAlgorithm: s.deps.HashAlgorithm,
 	}
 
-	return s.processor.ProcessFiles(ctx, files, tmpDir, processOpts)
+	return processor.ProcessFiles(ctx, files, repoDir, processOpts)
+}
+
+// tryTreeDownload fetches only filterPath through the hosting API into a
+// directory under tmpDir and returns that directory and the branch used.
+func (s *Strategy) tryTreeDownload(ctx context.Context, urlInfo *GitURLInfo, filterPath, tmpDir string) (repoDir, branch string, err error) {
+	info := &RepoInfo{
+		Platform: urlInfo.Platform,
+		Owner:    urlInfo.Owner,
+		Repo:     urlInfo.Repo,
+		URL:      urlInfo.RepoURL,
+		BaseURL:  urlInfo.BaseURL,
+	}
+	if s.treeFetcher == nil || !s.treeFetcher.Supports(info) {
+		return "", "", fmt.Errorf("no tree API for platform %q", info.Platform)
+	}
+
+	branch = urlInfo.Ref
+	if branch == "" && s.branches != nil {
+		if cached, ok := s.branches.Cached(info); ok {
+			branch = cached
+		} else if detected, apiErr := s.branches.FromAPI(ctx, info); apiErr == nil {
+			branch = detected
+			s.branches.Remember(info, branch)
+		}
+	}
+	if branch == "" {
+		return "", "", fmt.Errorf("default branch unknown")
+	}
+
+	repoDir = filepath.Join(tmpDir, "tree")
+	if _, err := s.treeFetcher.Fetch(ctx, info, branch, filterPath, repoDir); err != nil {
+		os.RemoveAll(repoDir)
+		return "", "", err
+	}
+	return repoDir, branch, nil
 }
 
 // TryArchiveDownload attempts to fetch a repository through an HTTP source archive.
+// The branch reported by the hosting API is tried first, then main and master,
+// and finally the branch reported by git ls-remote. The branch that worked is
+// remembered for later sources from the same repository.
 func (s *Strategy) TryArchiveDownload(ctx context.Context, url, destDir string) (branch, method string, err error) {
+	return s.tryArchiveDownload(ctx, url, "", "", destDir)
+}
+
+// tryArchiveDownload fetches the archive of ref when the URL pins one, with
+// no fallback to other branches, and otherwise behaves like TryArchiveDownload.
+func (s *Strategy) tryArchiveDownload(ctx context.Context, url, ref string, refType RefType, destDir string) (branch, method string, err error) {
 	if strings.HasPrefix(url, "git@") {
 		return "", "", fmt.Errorf("SSH URLs not supported for archive download")
 	}
@@ -211,39 +413,91 @@ func (s *Strategy) TryArchiveDownload(ctx context.Context, url, destDir string)
 		return "", "", err
 	}
 
-	if !s.skipBranchDetect {
-		branch, err = DetectDefaultBranch(ctx, url)
+	if ref != "" {
+		result, err := s.archiveFetcher.FetchRef(ctx, info, ref, refType, destDir)
 		if err != nil {
-			if s.logger != nil {
-				s.logger.Warn().Err(err).Msg("Failed to detect branch, using 'main'")
-			}
-			branch = "main"
+			return "", "", err
 		}
-	} else {
-		branch = "main"
+		return result.Branch, result.Method, nil
 	}
 
-	result, err := s.archiveFetcher.Fetch(ctx, info, branch, destDir)
-	if err != nil {
-		if branch == "main" {
-			if s.logger != nil {
-				s.logger.Debug().Msg("Trying 'master' branch")
-			}
-			result, err = s.archiveFetcher.Fetch(ctx, info, "master", destDir)
-			if err == nil {
-				return "master", "archive", nil
-			}
+	var candidates []string
+	if s.branches != nil {
+		if cached, ok := s.branches.Cached(info); ok {
+			candidates = append(candidates, cached)
+		} else if detected, apiErr := s.branches.FromAPI(ctx, info); apiErr == nil {
+			candidates = append(candidates, detected)
+		} else if s.logger != nil {
+			s.logger.Debug().Err(apiErr).Msg("Failed to detect branch from API, probing 'main' and 'master'")
 		}
-		return "", "", err
+	}
+	candidates = append(candidates, "main", "master")
+
+	tried := make(map[string]bool)
+	for _, candidate := range candidates {
+		if tried[candidate] {
+			continue
+		}
+		tried[candidate] = true
+		if s.logger != nil {
+			s.logger.Debug().Str("branch", candidate).Msg("Trying archive branch")
+		}
+		result, fetchErr := s.archiveFetcher.Fetch(ctx, info, candidate, destDir)
+		if fetchErr == nil {
+			s.rememberBranch(info, result.Branch)
+			return result.Branch, result.Method, nil
+		}
+		if errors.Is(fetchErr, ErrInsufficientSpace) || errors.Is(fetchErr, ErrArchiveTooLarge) {
+			// Another branch's archive would not fare better.
+			return "", "", fetchErr
+		}
+		err = fetchErr
 	}
 
+	if s.branches == nil {
+		return "", "", err
+	}
+	remote, remoteErr := s.branches.FromRemote(ctx, url)
+	if remoteErr != nil {
+		if s.logger != nil {
+			s.logger.Warn().Err(remoteErr).Msg("Failed to detect branch")
+		}
+		return "", "", err
+	}
+	if tried[remote] {
+		return "", "", err
+	}
+	result, err := s.archiveFetcher.Fetch(ctx, info, remote, destDir)
+	if err != nil {
+		return "", "", err
+	}
+	s.rememberBranch(info, result.Branch)
 	return result.Branch, result.Method, nil
 }
 
+func (s *Strategy) rememberBranch(info *RepoInfo, branch string) {
+	if s.branches != nil {
+		s.branches.Remember(info, branch)
+	}
+}
+
 // CloneRepository clones a repository into destDir and returns the detected branch.
 func (s *Strategy) CloneRepository(ctx context.Context, url, destDir string) (string, error) {
+	return s.cloneRepository(ctx, url, "", "", destDir, false, 0)
+}
+
+func (s *Strategy) cloneRepository(ctx context.Context, url, ref string, refType RefType, destDir string, submodules bool, timeout time.Duration) (string, error) {
+	fetcher := s.cloneFetcher
+	if submodules || timeout > 0 {
+		fetcher = NewCloneFetcher(CloneFetcherOptions{
+			Logger:     s.logger,
+			Submodules: submodules,
+			Timeout:    timeout,
+			SpaceCheck: s.spaceCheck,
+		})
+	}
 	info := &RepoInfo{URL: url}
-	result, err := s.cloneFetcher.Fetch(ctx, info, "", destDir)
+	result, err := fetcher.FetchRef(ctx, info, ref, refType, destDir)
 	if err != nil {
 		return "", err
 	}
@@ -256,9 +510,19 @@ func isWikiURL(url string) bool {
 		strings.HasSuffix(lower, ".wiki.git")
 }
 
-func createDefaultHTTPClient() *http.Client {
+// NewHTTPClient returns the client archives, tree listings and API lookups
+// use by default. Requests go through proxyURL when it is set and through the
+// proxies of the environment (HTTPS_PROXY and friends) otherwise.
+func NewHTTPClient(proxyURL string) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != "" {
+		if u, err := url.Parse(proxyURL); err == nil {
+			transport.Proxy = http.ProxyURL(u)
+		}
+	}
 	return &http.Client{
-		Timeout: 10 * time.Minute,
+		Transport: transport,
+		Timeout:   10 * time.Minute,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
 				return fmt.Errorf("too many redirects")
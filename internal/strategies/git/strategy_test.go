@@ -9,12 +9,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/quantmind-br/repodocs/internal/converter"
 	"github.com/quantmind-br/repodocs/internal/domain"
 	"github.com/quantmind-br/repodocs/internal/output"
 	"github.com/quantmind-br/repodocs/internal/state"
@@ -159,6 +163,18 @@ func TestCanHandle_BitbucketURL(t *testing.T) {
 	}
 }
 
+func TestCanHandle_SelfHosted(t *testing.T) {
+	tmpDir := t.TempDir()
+	deps := setupTestDependencies(t, tmpDir)
+	assert.False(t, gitstrat.NewStrategy(deps).CanHandle("https://gitlab.internal.corp/team/docs"))
+
+	deps.SelfHostedHosts = map[string]gitstrat.Platform{"gitlab.internal.corp": gitstrat.PlatformGitLab}
+	strategy := gitstrat.NewStrategy(deps)
+	assert.True(t, strategy.CanHandle("https://gitlab.internal.corp/team/docs"))
+	assert.True(t, strategy.CanHandle("git@gitlab.internal.corp:team/docs.git"))
+	assert.False(t, strategy.CanHandle("https://gitlab.internal.corp/team/docs/-/blob/main/README.md"))
+}
+
 func TestCanHandle_SSH(t *testing.T) {
 	tmpDir := t.TempDir()
 	deps := setupTestDependencies(t, tmpDir)
@@ -194,6 +210,37 @@ func TestCanHandle_GitURL(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	strategy := gitstrat.NewStrategy(setupTestDependencies(t, t.TempDir()))
+
+	valid := []string{
+		"https://github.com/user/repo",
+		"https://github.com/user/repo.git",
+		"https://github.com/user/repo/tree/main/docs",
+		"https://gitlab.com/group/project",
+		"https://bitbucket.org/team/repo",
+		"https://git.example.com/team/repo.git",
+		"git@github.com:user/repo.git",
+	}
+	for _, url := range valid {
+		assert.NoError(t, strategy.Validate(context.Background(), url), url)
+	}
+
+	invalid := map[string]string{
+		"https://github.com/user":    "invalid GitHub repo path",
+		"https://github.com/":        "invalid GitHub repo path",
+		"https://gitlab.com/group":   "invalid GitLab repo path",
+		"https://bitbucket.org/team": "invalid Bitbucket repo path",
+	}
+	for url, want := range invalid {
+		err := strategy.Validate(context.Background(), url)
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr, url)
+		assert.Equal(t, "url", validationErr.Field)
+		assert.Contains(t, err.Error(), want, url)
+	}
+}
+
 func TestCanHandle_NonGitURL(t *testing.T) {
 	tmpDir := t.TempDir()
 	deps := setupTestDependencies(t, tmpDir)
@@ -362,6 +409,34 @@ func TestExecute_TempDirError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestExecute_RemovesTempDirOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("TMPDIR is not used on Windows")
+	}
+	for _, keep := range []bool{false, true} {
+		tempRoot := t.TempDir()
+		t.Setenv("TMPDIR", tempRoot)
+
+		deps := setupTestDependencies(t, t.TempDir())
+		deps.KeepTemp = keep
+		strategy := gitstrat.NewStrategy(deps)
+
+		// A cancelled context fails every download method.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := strategy.Execute(ctx, "https://github.com/user/repo", gitstrat.ExecuteOptions{Output: t.TempDir()})
+		require.Error(t, err)
+
+		leftovers, err := filepath.Glob(filepath.Join(tempRoot, "repodocs-git-*"))
+		require.NoError(t, err)
+		if keep {
+			assert.Len(t, leftovers, 1, "KeepTemp retains the working directory")
+		} else {
+			assert.Empty(t, leftovers, "the working directory is removed")
+		}
+	}
+}
+
 func TestTryArchiveDownload_SSHURL(t *testing.T) {
 	tmpDir := t.TempDir()
 	deps := setupTestDependencies(t, tmpDir)
@@ -425,6 +500,67 @@ func TestCloneRepository_DirectoryError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCloneFetcher_Timeout(t *testing.T) {
+	// The server accepts the clone but never answers.
+	stalled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-stalled:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(stalled)
+
+	destDir := t.TempDir()
+	fetcher := gitstrat.NewCloneFetcher(gitstrat.CloneFetcherOptions{Timeout: 200 * time.Millisecond})
+
+	start := time.Now()
+	_, err := fetcher.Fetch(context.Background(), &gitstrat.RepoInfo{URL: server.URL + "/owner/repo.git"}, "", destDir)
+	require.ErrorIs(t, err, gitstrat.ErrCloneTimeout)
+	assert.Contains(t, err.Error(), "after 200ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	entries, err := os.ReadDir(destDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the partial clone is removed")
+}
+
+func TestDetectDefaultBranchWithOptions_TimeoutRetriesOnce(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	// The server accepts ls-remote requests but never answers.
+	var mu sync.Mutex
+	requests := 0
+	stalled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		select {
+		case <-stalled:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(stalled)
+
+	start := time.Now()
+	_, err := gitstrat.DetectDefaultBranchWithOptions(context.Background(), server.URL+"/owner/repo.git", gitstrat.LsRemoteOptions{
+		Timeout: 200 * time.Millisecond,
+		Backoff: 10 * time.Millisecond,
+	})
+	require.ErrorIs(t, err, gitstrat.ErrLsRemoteTimeout)
+	assert.Contains(t, err.Error(), "after 200ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, requests, "one attempt and one retry")
+}
+
 func TestStrategy_Name(t *testing.T) {
 	strategy := gitstrat.NewStrategy(nil)
 	assert.Equal(t, "git", strategy.Name())
@@ -670,6 +806,43 @@ func TestArchiveFetcher_BuildArchiveURL_Generic(t *testing.T) {
 	assert.Equal(t, "https://github.com/user/repo/archive/refs/heads/main.tar.gz", url)
 }
 
+func TestArchiveFetcher_BuildArchiveURL_SelfHosted(t *testing.T) {
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{})
+
+	tests := []struct {
+		platform gitstrat.Platform
+		want     string
+	}{
+		{gitstrat.PlatformGitHub, "https://git.corp/user/repo/archive/refs/heads/main.tar.gz"},
+		{gitstrat.PlatformGitLab, "https://git.corp/user/repo/-/archive/main/repo-main.tar.gz"},
+		{gitstrat.PlatformBitbucket, "https://git.corp/user/repo/get/main.tar.gz"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.platform), func(t *testing.T) {
+			info := &gitstrat.RepoInfo{Platform: tc.platform, Owner: "user", Repo: "repo", BaseURL: "https://git.corp"}
+			assert.Equal(t, tc.want, fetcher.BuildArchiveURL(info, "main"))
+		})
+	}
+}
+
+func TestArchiveFetcher_BuildRefArchiveURL(t *testing.T) {
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{})
+	github := &gitstrat.RepoInfo{Platform: gitstrat.PlatformGitHub, Owner: "user", Repo: "repo"}
+	gitlab := &gitstrat.RepoInfo{Platform: gitstrat.PlatformGitLab, Owner: "user", Repo: "repo"}
+
+	assert.Equal(t, "https://github.com/user/repo/archive/refs/heads/main.tar.gz",
+		fetcher.BuildRefArchiveURL(github, "main", gitstrat.RefBranch))
+	assert.Equal(t, "https://github.com/user/repo/archive/refs/tags/v2.3.0.tar.gz",
+		fetcher.BuildRefArchiveURL(github, "v2.3.0", gitstrat.RefTag))
+	assert.Equal(t, "https://github.com/user/repo/archive/abc1234.tar.gz",
+		fetcher.BuildRefArchiveURL(github, "abc1234", gitstrat.RefCommit))
+	assert.Equal(t, "https://github.com/user/repo/archive/3.12.tar.gz",
+		fetcher.BuildRefArchiveURL(github, "3.12", gitstrat.RefName))
+	assert.Equal(t, "https://gitlab.com/user/repo/-/archive/v2.3.0/repo-v2.3.0.tar.gz",
+		fetcher.BuildRefArchiveURL(gitlab, "v2.3.0", gitstrat.RefTag))
+}
+
 func TestArchiveFetcher_DownloadAndExtract_404(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -721,6 +894,246 @@ func TestArchiveFetcher_DownloadAndExtract_500(t *testing.T) {
 	assert.Contains(t, err.Error(), "download failed with status")
 }
 
+func TestArchiveFetcher_DownloadAndExtract_InsufficientSpace(t *testing.T) {
+	tarGz := createTestTarGz(t, map[string]string{"repo-main/README.md": "# Readme"}).Bytes()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(tarGz)
+	}))
+	defer server.Close()
+
+	size := uint64(len(tarGz))
+	newFetcher := func(free uint64) *gitstrat.ArchiveFetcher {
+		return gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{
+			HTTPClient: server.Client(),
+			SpaceCheck: &gitstrat.SpaceChecker{
+				FreeSpace: func(string) (uint64, error) { return free, nil },
+			},
+		})
+	}
+
+	tmpDir := t.TempDir()
+	err := newFetcher(size).DownloadAndExtract(context.Background(), server.URL+"/archive.tar.gz", tmpDir)
+	require.ErrorIs(t, err, gitstrat.ErrInsufficientSpace, "the extracted archive is estimated larger than the download")
+	assert.Contains(t, err.Error(), "--no-space-check")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "README.md"), "nothing is extracted")
+
+	require.NoError(t, newFetcher(1<<30).DownloadAndExtract(context.Background(), server.URL+"/archive.tar.gz", tmpDir))
+	assert.FileExists(t, filepath.Join(tmpDir, "README.md"))
+}
+
+// truncatingArchiveServer serves archive, breaking off the first truncate
+// responses halfway. With ranges it advertises byte range support and
+// answers Range requests with 206. It records the Range header of every
+// request.
+func truncatingArchiveServer(t *testing.T, archive []byte, truncate int, ranges bool) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Range"))
+		n := len(seen)
+		mu.Unlock()
+
+		body := archive
+		status := http.StatusOK
+		if ranges {
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("ETag", `"v1"`)
+			var start int
+			if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start); err == nil && r.Header.Get("If-Range") == `"v1"` {
+				body = archive[start:]
+				status = http.StatusPartialContent
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(archive)-1, len(archive)))
+			}
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.WriteHeader(status)
+		if n <= truncate {
+			// Closing the connection short of Content-Length breaks the
+			// transfer off.
+			w.Write(body[:len(body)/2])
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func largeTestTarGz(t *testing.T) []byte {
+	files := map[string]string{}
+	for i := 0; i < 50; i++ {
+		files[fmt.Sprintf("repo-main/docs/page%02d.md", i)] = fmt.Sprintf("# Page %d\n\n%s", i, strings.Repeat(fmt.Sprintf("line %d\n", i), 200))
+	}
+	return createTestTarGz(t, files).Bytes()
+}
+
+func TestArchiveFetcher_DownloadAndExtract_ResumesWithRange(t *testing.T) {
+	archive := largeTestTarGz(t)
+	server, requests := truncatingArchiveServer(t, archive, 1, true)
+
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{
+		HTTPClient:   server.Client(),
+		RetryBackoff: time.Millisecond,
+	})
+	tmpDir := t.TempDir()
+	require.NoError(t, fetcher.DownloadAndExtract(context.Background(), server.URL+"/archive.tar.gz", tmpDir))
+	assert.FileExists(t, filepath.Join(tmpDir, "docs", "page49.md"))
+
+	seen := requests()
+	require.Len(t, seen, 2)
+	assert.Empty(t, seen[0])
+	assert.Equal(t, fmt.Sprintf("bytes=%d-", len(archive)/2), seen[1], "the retry resumes after the received bytes")
+}
+
+func TestArchiveFetcher_DownloadAndExtract_RestartsWithoutRanges(t *testing.T) {
+	server, requests := truncatingArchiveServer(t, largeTestTarGz(t), 1, false)
+
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{
+		HTTPClient:   server.Client(),
+		RetryBackoff: time.Millisecond,
+	})
+	tmpDir := t.TempDir()
+	require.NoError(t, fetcher.DownloadAndExtract(context.Background(), server.URL+"/archive.tar.gz", tmpDir))
+	assert.FileExists(t, filepath.Join(tmpDir, "docs", "page49.md"))
+	assert.Equal(t, []string{"", ""}, requests(), "without range support the archive is downloaded again in full")
+}
+
+func TestArchiveFetcher_DownloadAndExtract_GivesUp(t *testing.T) {
+	server, requests := truncatingArchiveServer(t, largeTestTarGz(t), 10, true)
+
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{
+		HTTPClient:   server.Client(),
+		MaxRetries:   1,
+		RetryBackoff: time.Millisecond,
+	})
+	tmpDir := t.TempDir()
+	err := fetcher.DownloadAndExtract(context.Background(), server.URL+"/archive.tar.gz", tmpDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "archive download failed after 2 attempts")
+	assert.Len(t, requests(), 2)
+
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing is extracted")
+}
+
+// flakyArchiveServer serves archive after failing the first failures
+// requests with fail, and counts the requests.
+func flakyArchiveServer(t *testing.T, archive []byte, failures int32, fail func(w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			fail(w)
+			return
+		}
+		w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestArchiveFetcher_DownloadAndExtract_RetriesTransientFailures(t *testing.T) {
+	archive := createTestTarGz(t, map[string]string{"repo-main/README.md": "# Readme"}).Bytes()
+	failures := map[string]func(w http.ResponseWriter){
+		"429": func(w http.ResponseWriter) { w.WriteHeader(http.StatusTooManyRequests) },
+		"502": func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+		"503": func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+		"504": func(w http.ResponseWriter) { w.WriteHeader(http.StatusGatewayTimeout) },
+		"connection reset": func(w http.ResponseWriter) {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		},
+	}
+	for name, fail := range failures {
+		t.Run(name, func(t *testing.T) {
+			server, requests := flakyArchiveServer(t, archive, 2, fail)
+			fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{
+				HTTPClient:   server.Client(),
+				MaxRetries:   2,
+				RetryBackoff: time.Millisecond,
+			})
+
+			tmpDir := t.TempDir()
+			require.NoError(t, fetcher.DownloadAndExtract(context.Background(), server.URL+"/archive.tar.gz", tmpDir))
+			assert.FileExists(t, filepath.Join(tmpDir, "README.md"))
+			assert.Equal(t, int32(3), requests.Load())
+		})
+	}
+}
+
+func TestArchiveFetcher_DownloadAndExtract_DoesNotRetryDeterministicFailures(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusUnauthorized} {
+		server, requests := flakyArchiveServer(t, nil, 10, func(w http.ResponseWriter) { w.WriteHeader(status) })
+		fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{
+			HTTPClient:   server.Client(),
+			MaxRetries:   3,
+			RetryBackoff: time.Millisecond,
+		})
+
+		err := fetcher.DownloadAndExtract(context.Background(), server.URL+"/archive.tar.gz", t.TempDir())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprint(status))
+		assert.Equal(t, int32(1), requests.Load(), "status %d", status)
+	}
+}
+
+func TestArchiveFetcher_DownloadAndExtract_HonorsRetryAfter(t *testing.T) {
+	server, requests := flakyArchiveServer(t, nil, 10, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{
+		HTTPClient:   server.Client(),
+		RetryBackoff: time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := fetcher.DownloadAndExtract(ctx, server.URL+"/archive.tar.gz", t.TempDir())
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the retry waits for Retry-After, not the backoff")
+	assert.Less(t, time.Since(start), 5*time.Second, "cancellation interrupts the wait")
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestSpaceChecker_Check(t *testing.T) {
+	free := map[string]uint64{"tmp": 1 << 30, "out": 1 << 20}
+	checker := &gitstrat.SpaceChecker{
+		OutputDir: "out",
+		FreeSpace: func(dir string) (uint64, error) { return free[dir], nil },
+	}
+
+	assert.NoError(t, checker.Check("tmp", 1<<19))
+	err := checker.Check("tmp", 2<<20)
+	require.ErrorIs(t, err, gitstrat.ErrInsufficientSpace)
+	assert.Contains(t, err.Error(), "out has 1.0 MB free")
+
+	// Clones have no known size and need a minimum of free space.
+	assert.ErrorIs(t, checker.Check("tmp", -1), gitstrat.ErrInsufficientSpace)
+
+	unknown := &gitstrat.SpaceChecker{FreeSpace: func(string) (uint64, error) { return 0, utils.ErrFreeSpaceUnsupported }}
+	assert.NoError(t, unknown.Check("tmp", 1<<40), "unmeasurable directories are not checked")
+
+	var disabled *gitstrat.SpaceChecker
+	assert.NoError(t, disabled.Check("tmp", 1<<40))
+}
+
+func TestCloneFetcher_InsufficientSpace(t *testing.T) {
+	fetcher := gitstrat.NewCloneFetcher(gitstrat.CloneFetcherOptions{
+		SpaceCheck: &gitstrat.SpaceChecker{FreeSpace: func(string) (uint64, error) { return 1 << 20, nil }},
+	})
+
+	_, err := fetcher.Fetch(context.Background(), &gitstrat.RepoInfo{URL: "https://example.invalid/owner/repo.git"}, "", t.TempDir())
+	assert.ErrorIs(t, err, gitstrat.ErrInsufficientSpace)
+}
+
 func TestArchiveFetcher_DownloadAndExtract_InvalidTarGz(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -869,11 +1282,128 @@ func TestProcessor_FindDocumentationFiles_EmptyDir(t *testing.T) {
 
 	tmpDir := t.TempDir()
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
 	require.NoError(t, err)
 	assert.Empty(t, files)
 }
 
+func TestProcessor_FindFiles_IgnoreFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	for path, content := range map[string]string{
+		".repodocsignore":          "# internal notes\ndocs/internal/\n*.generated.md\n!keep.generated.md\n",
+		".gitignore":               "scratch.md\n",
+		"README.md":                "# Readme",
+		"scratch.md":               "# Scratch",
+		"api.generated.md":         "# Generated",
+		"keep.generated.md":        "# Kept",
+		"docs/guide.md":            "# Guide",
+		"docs/internal/secret.md":  "# Secret",
+		"node_modules/pkg/docs.md": "# Dependency",
+	} {
+		full := filepath.Join(tmpDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	relative := func(files []string) []string {
+		var rel []string
+		for _, f := range files {
+			r, err := filepath.Rel(tmpDir, f)
+			require.NoError(t, err)
+			rel = append(rel, filepath.ToSlash(r))
+		}
+		return rel
+	}
+
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
+
+	files, err := processor.FindFiles(context.Background(), tmpDir, gitstrat.DiscoveryOptions{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"README.md", "scratch.md", "keep.generated.md", "docs/guide.md"}, relative(files),
+		".repodocsignore extends the built-in ignored directories")
+
+	files, err = processor.FindFiles(context.Background(), tmpDir, gitstrat.DiscoveryOptions{HonorGitignore: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"README.md", "keep.generated.md", "docs/guide.md"}, relative(files))
+
+	files, err = processor.FindFiles(context.Background(), tmpDir, gitstrat.DiscoveryOptions{FilterPath: "docs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs/guide.md"}, relative(files), "patterns stay anchored at the repository root")
+}
+
+func TestProcessor_FindDocumentationFiles_IgnoreDirsOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, path := range []string{"README.md", "examples/demo.md", "testdata/case.md", "vendor/lib/README.md"} {
+		full := filepath.Join(tmpDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte("# Doc"), 0644))
+	}
+
+	tests := []struct {
+		name string
+		opts gitstrat.ProcessorOptions
+		want []string
+	}{
+		{name: "defaults", want: []string{"README.md", "examples/demo.md", "testdata/case.md"}},
+		{name: "merged with defaults", opts: gitstrat.ProcessorOptions{IgnoreDirs: []string{"examples", "testdata/"}}, want: []string{"README.md"}},
+		{name: "replacing defaults", opts: gitstrat.ProcessorOptions{IgnoreDirs: []string{"examples"}, ReplaceIgnoreDirs: true}, want: []string{"README.md", "testdata/case.md", "vendor/lib/README.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := gitstrat.NewProcessor(tt.opts).FindDocumentationFiles(context.Background(), tmpDir, "")
+			require.NoError(t, err)
+
+			var rel []string
+			for _, f := range files {
+				r, err := filepath.Rel(tmpDir, f)
+				require.NoError(t, err)
+				rel = append(rel, filepath.ToSlash(r))
+			}
+			assert.ElementsMatch(t, tt.want, rel)
+		})
+	}
+	assert.True(t, gitstrat.IgnoreDirs["vendor"], "package defaults are not modified")
+}
+
+func TestProcessor_FindFiles_GitHubMeta(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, path := range []string{
+		".github/CONTRIBUTING.md",
+		".github/PULL_REQUEST_TEMPLATE.md",
+		".github/ISSUE_TEMPLATE/bug_report.md",
+		".github/ISSUE_TEMPLATE/feature.yml",
+		".github/CODEOWNERS",
+		".github/workflows/ci.yml",
+		".github/dependabot.yml",
+	} {
+		full := filepath.Join(tmpDir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte("content"), 0644))
+	}
+
+	relative := func(files []string) []string {
+		var rel []string
+		for _, f := range files {
+			r, err := filepath.Rel(tmpDir, f)
+			require.NoError(t, err)
+			rel = append(rel, filepath.ToSlash(r))
+		}
+		return rel
+	}
+	markdown := []string{".github/CONTRIBUTING.md", ".github/PULL_REQUEST_TEMPLATE.md", ".github/ISSUE_TEMPLATE/bug_report.md"}
+
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
+	files, err := processor.FindFiles(context.Background(), tmpDir, gitstrat.DiscoveryOptions{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, markdown, relative(files))
+
+	files, err = processor.FindFiles(context.Background(), tmpDir, gitstrat.DiscoveryOptions{IncludeGitHubMeta: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, append(markdown, ".github/ISSUE_TEMPLATE/feature.yml", ".github/CODEOWNERS"), relative(files),
+		"workflows and other CI configuration are never extracted")
+}
+
 func TestProcessor_FindDocumentationFiles_MarkdownOnly(t *testing.T) {
 	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
 
@@ -883,7 +1413,7 @@ func TestProcessor_FindDocumentationFiles_MarkdownOnly(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "style.css"), []byte("body{}"), 0644))
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
 	require.NoError(t, err)
 	assert.Len(t, files, 2)
 }
@@ -897,7 +1427,7 @@ func TestProcessor_FindDocumentationFiles_RST(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "guide.rst"), []byte("Guide\n====="), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main"), 0644))
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
 	require.NoError(t, err)
 	assert.Len(t, files, 3)
 	var rstCount int
@@ -909,6 +1439,73 @@ func TestProcessor_FindDocumentationFiles_RST(t *testing.T) {
 	assert.Equal(t, 2, rstCount)
 }
 
+func TestProcessor_FindDocumentationFiles_AsciiDoc(t *testing.T) {
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Test"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "index.adoc"), []byte("= Index"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "guide.asciidoc"), []byte("= Guide"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "NOTES.TXT"), []byte("notes"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main"), 0644))
+
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
+	require.NoError(t, err)
+	var names []string
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	assert.ElementsMatch(t, []string{"README.md", "index.adoc", "guide.asciidoc", "NOTES.TXT"}, names)
+}
+
+func TestProcessor_FindDocumentationFiles_ExtensionsOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"README.md", "index.adoc", "notes.txt", "api.rst", "config.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte("content"), 0644))
+	}
+
+	find := func(exts map[string]bool) []string {
+		processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{Extensions: exts})
+		files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
+		require.NoError(t, err)
+		var names []string
+		for _, f := range files {
+			names = append(names, filepath.Base(f))
+		}
+		return names
+	}
+
+	assert.ElementsMatch(t, []string{"README.md", "config.yaml"}, find(map[string]bool{".md": true}),
+		"an override restricts the set; config files are still found")
+	assert.ElementsMatch(t, []string{"index.adoc", "notes.txt", "config.yaml"}, find(map[string]bool{"ADOC": true, ".txt": true, ".md": false}),
+		"extensions are normalized and false entries are ignored")
+	assert.Len(t, find(nil), 5, "nil keeps the defaults")
+}
+
+func TestProcessor_ProcessFile_AsciiDocWrapped(t *testing.T) {
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "guide.adoc")
+	require.NoError(t, os.WriteFile(path, []byte("= Guide\n\nHello."), 0644))
+
+	var written *domain.Document
+	opts := gitstrat.ProcessOptions{
+		RepoURL: "https://github.com/user/repo",
+		Branch:  "main",
+		WriteFunc: func(ctx context.Context, doc *domain.Document) error {
+			written = doc
+			return nil
+		},
+		Result: domain.NewStrategyResult("git", "https://github.com/user/repo"),
+	}
+
+	require.NoError(t, processor.ProcessFile(context.Background(), path, tmpDir, opts))
+	require.NotNil(t, written)
+	assert.Equal(t, "```\n= Guide\n\nHello.\n```", written.Content)
+	assert.False(t, written.IsRawFile)
+}
+
 func TestProcessor_FindDocumentationFiles_WithSubdirectories(t *testing.T) {
 	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
 
@@ -919,7 +1516,7 @@ func TestProcessor_FindDocumentationFiles_WithSubdirectories(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Root"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(docsDir, "guide.md"), []byte("# Guide"), 0644))
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
 	require.NoError(t, err)
 	assert.Len(t, files, 2)
 }
@@ -937,7 +1534,7 @@ func TestProcessor_FindDocumentationFiles_ExcludedDirs(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(nodeModules, "package.md"), []byte("# Package"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "hooks.md"), []byte("# Hooks"), 0644))
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
 	require.NoError(t, err)
 	assert.Len(t, files, 1)
 }
@@ -955,7 +1552,7 @@ func TestProcessor_FindDocumentationFiles_WithFilterPath(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(docsDir, "guide.md"), []byte("# Guide"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(apiDir, "reference.md"), []byte("# API"), 0644))
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "docs")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "docs")
 	require.NoError(t, err)
 	assert.Len(t, files, 1)
 }
@@ -965,7 +1562,7 @@ func TestProcessor_FindDocumentationFiles_FilterPathNotExists(t *testing.T) {
 
 	tmpDir := t.TempDir()
 
-	_, err := processor.FindDocumentationFiles(tmpDir, "nonexistent")
+	_, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "nonexistent")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "filter path does not exist")
 }
@@ -977,7 +1574,7 @@ func TestProcessor_FindDocumentationFiles_FilterPathNotDir(t *testing.T) {
 	filePath := filepath.Join(tmpDir, "file.md")
 	require.NoError(t, os.WriteFile(filePath, []byte("# Test"), 0644))
 
-	_, err := processor.FindDocumentationFiles(tmpDir, "file.md")
+	_, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "file.md")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "filter path is not a directory")
 }
@@ -993,7 +1590,7 @@ func TestProcessor_FindDocumentationFiles_IncludesConfigFiles(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("VAR=value"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main"), 0644))
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
 	require.NoError(t, err)
 
 	filenames := make(map[string]bool)
@@ -1051,6 +1648,7 @@ func TestParser_ParseURL_GitHub(t *testing.T) {
 		{"https://github.com/user/repo", "user", "repo", gitstrat.PlatformGitHub},
 		{"https://github.com/user/repo.git", "user", "repo", gitstrat.PlatformGitHub},
 		{"git@github.com:user/repo.git", "user", "repo", gitstrat.PlatformGitHub},
+		{"https://GitHub.com/user/repo.git/", "user", "repo", gitstrat.PlatformGitHub},
 	}
 
 	for _, tc := range tests {
@@ -1114,6 +1712,61 @@ func TestParser_ParseURL_Bitbucket(t *testing.T) {
 	}
 }
 
+func TestParser_SelfHostedHosts(t *testing.T) {
+	parser := gitstrat.NewParserWithOptions(gitstrat.ParserOptions{
+		SelfHostedHosts: map[string]gitstrat.Platform{
+			"gitlab.internal.corp": gitstrat.PlatformGitLab,
+			"GHE.Corp.example":     gitstrat.PlatformGitHub,
+			"git.corp.example":     gitstrat.PlatformGeneric,
+		},
+	})
+
+	info, err := parser.ParseURL("https://gitlab.internal.corp/team/docs.git")
+	require.NoError(t, err)
+	assert.Equal(t, gitstrat.PlatformGitLab, info.Platform)
+	assert.Equal(t, "team", info.Owner)
+	assert.Equal(t, "docs", info.Repo)
+	assert.Equal(t, "https://gitlab.internal.corp", info.BaseURL)
+
+	info, err = parser.ParseURL("git@ghe.corp.example:team/docs.git")
+	require.NoError(t, err)
+	assert.Equal(t, gitstrat.PlatformGitHub, info.Platform)
+	assert.Equal(t, "https://ghe.corp.example", info.BaseURL)
+
+	info, err = parser.ParseURL("https://github.com/user/repo")
+	require.NoError(t, err)
+	assert.Empty(t, info.BaseURL, "public hosts have no base URL")
+
+	urlInfo, err := parser.ParseURLWithPath("http://gitlab.internal.corp/team/docs/-/tree/develop/guides/setup")
+	require.NoError(t, err)
+	assert.Equal(t, gitstrat.PlatformGitLab, urlInfo.Platform)
+	assert.Equal(t, "http://gitlab.internal.corp/team/docs", urlInfo.RepoURL)
+	assert.Equal(t, "develop", urlInfo.Branch)
+	assert.Equal(t, "guides/setup", urlInfo.SubPath)
+	assert.Equal(t, "http://gitlab.internal.corp", urlInfo.BaseURL)
+
+	assert.True(t, parser.CanHandle("https://gitlab.internal.corp/team/docs"))
+	assert.True(t, parser.CanHandle("https://github.com/user/repo"))
+	assert.False(t, parser.CanHandle("https://git.corp.example/team/docs"), "hosts of unsupported platforms are ignored")
+	assert.True(t, parser.IsSelfHosted("git@gitlab.internal.corp:team/docs.git"))
+	assert.False(t, parser.IsSelfHosted("https://gitlab.com/team/docs"))
+
+	var validationErr *domain.ValidationError
+	assert.ErrorAs(t, parser.Validate("https://gitlab.internal.corp/team"), &validationErr)
+}
+
+func TestParser_SelfHostedHosts_DefaultUnchanged(t *testing.T) {
+	parser := gitstrat.NewParser()
+
+	_, err := parser.ParseURL("https://gitlab.internal.corp/team/docs")
+	assert.Error(t, err)
+	assert.False(t, parser.CanHandle("https://gitlab.internal.corp/team/docs"))
+
+	urlInfo, err := parser.ParseURLWithPath("https://gitlab.internal.corp/team/docs/-/tree/main")
+	require.NoError(t, err)
+	assert.Equal(t, gitstrat.PlatformGeneric, urlInfo.Platform)
+}
+
 func TestParser_ParseURL_Invalid(t *testing.T) {
 	parser := gitstrat.NewParser()
 
@@ -1158,6 +1811,14 @@ func TestParser_ParseURLWithPath_GitHub(t *testing.T) {
 			subPath:  "",
 			platform: gitstrat.PlatformGitHub,
 		},
+		{
+			name:     "mixed-case host",
+			url:      "https://GitHub.com/user/repo.git/",
+			repoURL:  "https://github.com/user/repo",
+			branch:   "",
+			subPath:  "",
+			platform: gitstrat.PlatformGitHub,
+		},
 		{
 			name:     "repo with tree/branch",
 			url:      "https://github.com/user/repo/tree/main",
@@ -1303,6 +1964,50 @@ func TestParser_ParseURLWithPath_Generic(t *testing.T) {
 	assert.Equal(t, gitstrat.PlatformGeneric, info.Platform)
 }
 
+func TestParser_ParseURLWithPath_LocalRepository(t *testing.T) {
+	parser := gitstrat.NewParser()
+
+	info, err := parser.ParseURLWithPath("file:///srv/repos/docs")
+	require.NoError(t, err)
+	assert.Equal(t, "file:///srv/repos/docs", info.RepoURL)
+	assert.Equal(t, gitstrat.PlatformGeneric, info.Platform)
+}
+
+func TestParser_ParseURLWithPath_Refs(t *testing.T) {
+	parser := gitstrat.NewParser()
+	sha := "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		url     string
+		ref     string
+		refType gitstrat.RefType
+		branch  string
+		subPath string
+	}{
+		{"https://github.com/user/repo", "", "", "", ""},
+		{"https://github.com/user/repo/tree/develop/docs", "develop", gitstrat.RefBranch, "develop", "docs"},
+		{"https://github.com/user/repo/tree/v2.3.0/docs", "v2.3.0", gitstrat.RefName, "", "docs"},
+		{"https://github.com/user/repo/tree/1.0.0-rc1", "1.0.0-rc1", gitstrat.RefName, "", ""},
+		{"https://github.com/python/cpython/tree/3.12/Doc", "3.12", gitstrat.RefName, "", "Doc"},
+		{"https://github.com/user/repo/tree/" + sha + "/docs", sha, gitstrat.RefCommit, "", "docs"},
+		{"https://github.com/user/repo/commit/" + sha, sha, gitstrat.RefCommit, "", ""},
+		{"https://github.com/user/repo/commit/abc1234", "abc1234", gitstrat.RefCommit, "", ""},
+		{"https://gitlab.com/user/repo/-/commit/" + sha, sha, gitstrat.RefCommit, "", ""},
+		{"https://bitbucket.org/user/repo/commits/" + sha, sha, gitstrat.RefCommit, "", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.url, func(t *testing.T) {
+			info, err := parser.ParseURLWithPath(tc.url)
+			require.NoError(t, err)
+			assert.Equal(t, tc.ref, info.Ref)
+			assert.Equal(t, tc.refType, info.RefType)
+			assert.Equal(t, tc.branch, info.Branch)
+			assert.Equal(t, tc.subPath, info.SubPath)
+		})
+	}
+}
+
 func TestParser_ParseURLWithPath_Invalid(t *testing.T) {
 	parser := gitstrat.NewParser()
 
@@ -1540,6 +2245,95 @@ func TestProcessor_ProcessFile_LargeFile(t *testing.T) {
 	assert.False(t, writeCalled)
 }
 
+func TestProcessor_ProcessFile_BinarySkipped(t *testing.T) {
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
+
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "notes.txt")
+	require.NoError(t, os.WriteFile(path, []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0644))
+
+	written := false
+	result := domain.NewStrategyResult("git", "https://github.com/user/repo")
+	opts := gitstrat.ProcessOptions{
+		RepoURL: "https://github.com/user/repo",
+		Branch:  "main",
+		WriteFunc: func(ctx context.Context, doc *domain.Document) error {
+			written = true
+			return nil
+		},
+		Result: result,
+	}
+
+	require.NoError(t, processor.ProcessFile(context.Background(), path, tmpDir, opts))
+	assert.False(t, written)
+	assert.Equal(t, 1, result.Snapshot().DocsSkipped)
+}
+
+func TestProcessor_ProcessFile_LFSPointerSkipped(t *testing.T) {
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
+
+	tmpDir := t.TempDir()
+	pointer := filepath.Join(tmpDir, "guide.md")
+	require.NoError(t, os.WriteFile(pointer, []byte("version https://git-lfs.github.com/spec/v1\n"+
+		"oid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393\n"+
+		"size 12345\n"), 0644))
+	regular := filepath.Join(tmpDir, "about.md")
+	require.NoError(t, os.WriteFile(regular, []byte("# About\n\nversion https://git-lfs.github.com/spec/v1 is a spec URL.\n"), 0644))
+
+	var written []string
+	result := domain.NewStrategyResult("git", "https://github.com/user/repo")
+	opts := gitstrat.ProcessOptions{
+		RepoURL: "https://github.com/user/repo",
+		Branch:  "main",
+		WriteFunc: func(ctx context.Context, doc *domain.Document) error {
+			written = append(written, doc.RelativePath)
+			return nil
+		},
+		Result: result,
+	}
+
+	require.NoError(t, processor.ProcessFile(context.Background(), pointer, tmpDir, opts))
+	require.NoError(t, processor.ProcessFile(context.Background(), regular, tmpDir, opts))
+	assert.Equal(t, []string{"about.md"}, written)
+	assert.Equal(t, 1, result.Snapshot().DocsSkipped)
+}
+
+func TestProcessor_ProcessFile_Encodings(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content []byte
+		want    string
+	}{
+		{name: "utf-8 bom removed", file: "bom.md", content: []byte("\xEF\xBB\xBF# Título"), want: "# Título"},
+		{name: "utf-16le transcoded", file: "wide.md", content: []byte("\xFF\xFE#\x00 \x00H\x00i\x00"), want: "# Hi"},
+		{name: "latin-1 transcoded", file: "legacy.md", content: []byte("# Caf\xe9"), want: "# Café"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
+			tmpDir := t.TempDir()
+			path := filepath.Join(tmpDir, tt.file)
+			require.NoError(t, os.WriteFile(path, tt.content, 0644))
+
+			var capturedDoc *domain.Document
+			opts := gitstrat.ProcessOptions{
+				RepoURL: "https://github.com/user/repo",
+				Branch:  "main",
+				WriteFunc: func(ctx context.Context, doc *domain.Document) error {
+					capturedDoc = doc
+					return nil
+				},
+			}
+
+			require.NoError(t, processor.ProcessFile(context.Background(), path, tmpDir, opts))
+			require.NotNil(t, capturedDoc)
+			assert.Equal(t, tt.want, capturedDoc.Content)
+		})
+	}
+}
+
 func TestProcessor_ProcessFile_WithStateManager(t *testing.T) {
 	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
 
@@ -1584,6 +2378,40 @@ func TestProcessor_ProcessFile_WithStateManager(t *testing.T) {
 	assert.Equal(t, 1, writeCalls)
 }
 
+func TestProcessor_ProcessFile_SyncWithBLAKE3(t *testing.T) {
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
+
+	tmpDir := t.TempDir()
+	mdPath := filepath.Join(tmpDir, "guide.md")
+	require.NoError(t, os.WriteFile(mdPath, []byte("# Guide\n\nText."), 0644))
+
+	stateManager := state.NewManager(state.ManagerOptions{
+		BaseDir:       filepath.Join(tmpDir, "state"),
+		HashAlgorithm: "blake3",
+	})
+
+	var written []*domain.Document
+	opts := gitstrat.ProcessOptions{
+		RepoURL: "https://github.com/user/repo",
+		Branch:  "main",
+		WriteFunc: func(ctx context.Context, doc *domain.Document) error {
+			written = append(written, doc)
+			stateManager.Update(doc.URL, state.PageState{ContentHash: doc.ContentHash})
+			return nil
+		},
+		StateManager:  stateManager,
+		HashAlgorithm: converter.HashBLAKE3,
+	}
+
+	require.NoError(t, processor.ProcessFile(context.Background(), mdPath, tmpDir, opts))
+	require.Len(t, written, 1)
+	assert.Equal(t, converter.ContentHashWith(written[0].Content, converter.HashBLAKE3), written[0].ContentHash)
+	assert.Equal(t, "blake3", written[0].HashAlgorithm)
+
+	require.NoError(t, processor.ProcessFile(context.Background(), mdPath, tmpDir, opts))
+	assert.Len(t, written, 1, "unchanged file is skipped on the next sync")
+}
+
 func TestProcessor_ProcessFiles_Empty(t *testing.T) {
 	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
 
@@ -1776,6 +2604,147 @@ func TestArchiveFetcher_ExtractTarGz_PathTraversal(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// tarGzEntries builds a tar.gz of headers in order; regular files get
+// bodies[name] as content.
+func tarGzEntries(t *testing.T, headers []*tar.Header, bodies map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, hdr := range headers {
+		body := bodies[hdr.Name]
+		if hdr.Typeflag == tar.TypeReg || hdr.Typeflag == 0 {
+			hdr.Size = int64(len(body))
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		if hdr.Size > 0 {
+			_, err := tw.Write([]byte(body))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return &buf
+}
+
+func TestArchiveFetcher_ExtractTarGz_TotalSizeLimit(t *testing.T) {
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{MaxExtractBytes: 2500})
+	kb := strings.Repeat("x", 1000)
+	archive := tarGzEntries(t, []*tar.Header{
+		{Name: "repo-main/a.md", Mode: 0644},
+		{Name: "repo-main/b.md", Mode: 0644},
+		{Name: "repo-main/c.md", Mode: 0644},
+	}, map[string]string{"repo-main/a.md": kb, "repo-main/b.md": kb, "repo-main/c.md": kb})
+
+	tmpDir := t.TempDir()
+	err := fetcher.ExtractTarGz(archive, tmpDir)
+	require.ErrorIs(t, err, gitstrat.ErrArchiveTooLarge)
+	assert.Contains(t, err.Error(), "c.md")
+	assert.FileExists(t, filepath.Join(tmpDir, "a.md"))
+	assert.FileExists(t, filepath.Join(tmpDir, "b.md"))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "c.md"), "extraction stops before the file over the limit")
+
+	large := tarGzEntries(t, []*tar.Header{
+		{Name: "repo-main/a.md", Mode: 0644},
+		{Name: "repo-main/b.md", Mode: 0644},
+		{Name: "repo-main/c.md", Mode: 0644},
+	}, map[string]string{"repo-main/a.md": kb, "repo-main/b.md": kb, "repo-main/c.md": kb}).Bytes()
+	server, _ := flakyArchiveServer(t, large, 0, nil)
+	fetcher = gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{HTTPClient: server.Client(), MaxExtractBytes: 2500})
+	tmpDir = t.TempDir()
+	err = fetcher.DownloadAndExtract(context.Background(), server.URL+"/archive.tar.gz", tmpDir)
+	require.ErrorIs(t, err, gitstrat.ErrArchiveTooLarge)
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a failed extraction leaves nothing behind")
+
+	unlimited := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{MaxExtractBytes: -1})
+	archive = tarGzEntries(t, []*tar.Header{{Name: "repo-main/a.md", Mode: 0644}}, map[string]string{"repo-main/a.md": kb})
+	assert.NoError(t, unlimited.ExtractTarGz(archive, t.TempDir()))
+}
+
+func TestArchiveFetcher_ExtractTarGz_FileSizeLimit(t *testing.T) {
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{MaxExtractFileBytes: 100})
+	archive := tarGzEntries(t, []*tar.Header{
+		{Name: "repo-main/video.bin", Mode: 0644},
+		{Name: "repo-main/README.md", Mode: 0644},
+	}, map[string]string{"repo-main/video.bin": strings.Repeat("x", 101), "repo-main/README.md": "# Readme"})
+
+	tmpDir := t.TempDir()
+	require.NoError(t, fetcher.ExtractTarGz(archive, tmpDir))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "video.bin"), "oversized files are skipped")
+	assert.FileExists(t, filepath.Join(tmpDir, "README.md"))
+}
+
+func TestArchiveFetcher_ExtractTarGz_Symlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links need privileges on Windows")
+	}
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{})
+	archive := tarGzEntries(t, []*tar.Header{
+		{Name: "repo-main/README.md", Mode: 0644},
+		{Name: "repo-main/docs/index.md", Typeflag: tar.TypeSymlink, Linkname: "../README.md"},
+		{Name: "repo-main/passwd", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"},
+		{Name: "repo-main/up", Typeflag: tar.TypeSymlink, Linkname: "../../outside"},
+		{Name: "repo-main/self", Typeflag: tar.TypeSymlink, Linkname: "."},
+		{Name: "repo-main/escape", Typeflag: tar.TypeSymlink, Linkname: "self/.."},
+		{Name: "repo-main/escape/evil.md", Mode: 0644},
+	}, map[string]string{"repo-main/README.md": "# Readme", "repo-main/escape/evil.md": "evil"})
+
+	parent := t.TempDir()
+	tmpDir := filepath.Join(parent, "repo")
+	require.NoError(t, os.Mkdir(tmpDir, 0755))
+	require.NoError(t, fetcher.ExtractTarGz(archive, tmpDir))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "docs", "index.md"))
+	require.NoError(t, err, "links inside the destination are kept")
+	assert.Equal(t, "# Readme", string(content))
+
+	for _, name := range []string{"passwd", "up", "escape"} {
+		_, err := os.Lstat(filepath.Join(tmpDir, name))
+		assert.True(t, os.IsNotExist(err), "%s is rejected", name)
+	}
+	assert.NoFileExists(t, filepath.Join(parent, "evil.md"), "nothing is written through a link chain")
+}
+
+func TestArchiveFetcher_ExtractTarGz_DanglingLinkChain(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links need privileges on Windows")
+	}
+	fetcher := gitstrat.NewArchiveFetcher(gitstrat.ArchiveFetcherOptions{})
+	archive := tarGzEntries(t, []*tar.Header{
+		{Name: "repo-main/d", Typeflag: tar.TypeSymlink, Linkname: "."},
+		{Name: "repo-main/e", Typeflag: tar.TypeSymlink, Linkname: "d/.."},
+		{Name: "repo-main/a", Typeflag: tar.TypeSymlink, Linkname: "e/escaped.txt"},
+		{Name: "repo-main/a", Mode: 0644},
+		{Name: "repo-main/README.md", Typeflag: tar.TypeSymlink, Linkname: "docs/index.md"},
+		{Name: "repo-main/docs/index.md", Mode: 0644},
+	}, map[string]string{"repo-main/a": "evil", "repo-main/docs/index.md": "# Index"})
+
+	parent := t.TempDir()
+	tmpDir := filepath.Join(parent, "repo")
+	require.NoError(t, os.Mkdir(tmpDir, 0755))
+	require.NoError(t, fetcher.ExtractTarGz(archive, tmpDir))
+
+	assert.NoFileExists(t, filepath.Join(parent, "escaped.txt"), "nothing is written through a dangling link chain")
+	info, err := os.Lstat(filepath.Join(tmpDir, "a"))
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular(), "the later file entry replaces the link")
+	_, err = os.Lstat(filepath.Join(tmpDir, "e"))
+	assert.True(t, os.IsNotExist(err), "a link resolving outside the destination is removed")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	require.NoError(t, err, "a link to a later entry is kept")
+	assert.Equal(t, "# Index", string(content))
+
+	// A link already planted in the destination is not followed.
+	planted := t.TempDir()
+	require.NoError(t, os.Symlink(filepath.Join(parent, "planted.txt"), filepath.Join(planted, "a")))
+	archive = tarGzEntries(t, []*tar.Header{{Name: "repo-main/a", Mode: 0644}}, map[string]string{"repo-main/a": "evil"})
+	require.NoError(t, fetcher.ExtractTarGz(archive, planted))
+	assert.NoFileExists(t, filepath.Join(parent, "planted.txt"))
+}
+
 func TestArchiveFetcher_Fetch_WithLogger(t *testing.T) {
 	logger := utils.NewLogger(utils.LoggerOptions{Level: "debug"})
 	tarGz := createTestTarGz(t, map[string]string{
@@ -1808,7 +2777,7 @@ func TestExecute_WithFilterPath(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(docsDir, "guide.md"), []byte("# Guide"), 0644))
 	require.NoError(t, os.WriteFile(filepath.Join(docsDir, "api.md"), []byte("# API"), 0644))
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "docs")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "docs")
 	require.NoError(t, err)
 	assert.Len(t, files, 2)
 }
@@ -1822,7 +2791,7 @@ func TestExecute_WithLimit(t *testing.T) {
 	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "doc4.md"), []byte("# Doc 4"), 0644))
 
 	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
-	files, err := processor.FindDocumentationFiles(tmpDir, "")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
 	require.NoError(t, err)
 	assert.Len(t, files, 4)
 
@@ -1960,6 +2929,94 @@ func TestProcessor_ProcessFiles_WithError(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// globRepo creates files at the slash-separated paths under a temporary
+// directory and returns it.
+func globRepo(t *testing.T, paths ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, path := range paths {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte("# Doc"), 0644))
+	}
+	return dir
+}
+
+// findRelative runs FindFiles on dir and returns slash-separated paths
+// relative to it.
+func findRelative(t *testing.T, dir string, opts gitstrat.DiscoveryOptions) []string {
+	t.Helper()
+	files, err := gitstrat.NewProcessor(gitstrat.ProcessorOptions{}).FindFiles(context.Background(), dir, opts)
+	require.NoError(t, err)
+	rel := make([]string, 0, len(files))
+	for _, f := range files {
+		r, err := filepath.Rel(dir, f)
+		require.NoError(t, err)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	return rel
+}
+
+func TestProcessor_FindFiles_IncludeExclude(t *testing.T) {
+	dir := globRepo(t,
+		"README.md",
+		"docs/index.md",
+		"docs/guide/setup.md",
+		"docs/guide/deep/nested.rst",
+		"docs/archive/old.md",
+		"docs/archive/2019/older.md",
+		"docs/config.yaml",
+		"src/notes.md",
+	)
+
+	assert.ElementsMatch(t, []string{
+		"docs/index.md", "docs/guide/setup.md", "docs/guide/deep/nested.rst", "docs/config.yaml",
+	}, findRelative(t, dir, gitstrat.DiscoveryOptions{
+		Include: []string{"docs/**"},
+		Exclude: []string{"docs/archive/**"},
+	}))
+
+	assert.ElementsMatch(t, []string{
+		"README.md", "docs/index.md", "docs/guide/setup.md", "docs/archive/old.md", "docs/archive/2019/older.md", "src/notes.md",
+	}, findRelative(t, dir, gitstrat.DiscoveryOptions{Include: []string{"*.md"}}),
+		"a pattern without a slash matches at any depth")
+
+	assert.ElementsMatch(t, []string{"docs/guide/setup.md", "docs/guide/deep/nested.rst"},
+		findRelative(t, dir, gitstrat.DiscoveryOptions{Include: []string{"docs/guide/**/*.md", "docs/guide/**/*.rst"}}))
+
+	assert.Len(t, findRelative(t, dir, gitstrat.DiscoveryOptions{Include: []string{"", "  "}}), 8,
+		"blank patterns leave every file in")
+}
+
+func TestProcessor_FindFiles_GlobNegationOrder(t *testing.T) {
+	dir := globRepo(t, "docs/a.md", "docs/b.md", "docs/internal/c.md", "docs/internal/public.md")
+
+	assert.ElementsMatch(t, []string{"docs/a.md", "docs/b.md", "docs/internal/public.md"},
+		findRelative(t, dir, gitstrat.DiscoveryOptions{
+			Exclude: []string{"docs/internal/*", "!docs/internal/public.md"},
+		}), "a later negation re-adds a file")
+
+	assert.ElementsMatch(t, []string{"docs/a.md", "docs/b.md"},
+		findRelative(t, dir, gitstrat.DiscoveryOptions{
+			Exclude: []string{"!docs/internal/public.md", "docs/internal/*"},
+		}), "an earlier negation is overridden")
+
+	assert.ElementsMatch(t, []string{"docs/a.md"},
+		findRelative(t, dir, gitstrat.DiscoveryOptions{
+			Include: []string{"docs/**", "!docs/b.md", "!docs/internal/**"},
+		}), "negations narrow the include list")
+}
+
+func TestProcessor_FindFiles_GlobWindowsSeparators(t *testing.T) {
+	dir := globRepo(t, "docs/index.md", "docs/archive/old.md", "guide.md")
+
+	assert.Equal(t, []string{"docs/index.md"},
+		findRelative(t, dir, gitstrat.DiscoveryOptions{
+			Include: []string{`docs\**`},
+			Exclude: []string{`docs\archive\**`},
+		}))
+}
+
 func TestProcessor_FindDocumentationFiles_WalkError(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("Unix file permissions not supported on Windows")
@@ -1974,7 +3031,7 @@ func TestProcessor_FindDocumentationFiles_WalkError(t *testing.T) {
 	require.NoError(t, os.Chmod(subDir, 0000))
 	defer os.Chmod(subDir, 0755)
 
-	_, err := processor.FindDocumentationFiles(tmpDir, "")
+	_, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "")
 	assert.Error(t, err)
 }
 
@@ -1989,7 +3046,7 @@ func TestProcessor_FindDocumentationFiles_WithLogger(t *testing.T) {
 	require.NoError(t, os.MkdirAll(docsDir, 0755))
 	require.NoError(t, os.WriteFile(filepath.Join(docsDir, "test.md"), []byte("# Test"), 0644))
 
-	files, err := processor.FindDocumentationFiles(tmpDir, "docs")
+	files, err := processor.FindDocumentationFiles(context.Background(), tmpDir, "docs")
 	require.NoError(t, err)
 	assert.Len(t, files, 1)
 }
@@ -2182,7 +3239,10 @@ func TestFetchResult_Fields(t *testing.T) {
 func TestDocumentExtensions(t *testing.T) {
 	assert.True(t, gitstrat.DocumentExtensions[".md"])
 	assert.True(t, gitstrat.DocumentExtensions[".mdx"])
-	assert.False(t, gitstrat.DocumentExtensions[".txt"])
+	assert.True(t, gitstrat.DocumentExtensions[".rst"])
+	assert.True(t, gitstrat.DocumentExtensions[".adoc"])
+	assert.True(t, gitstrat.DocumentExtensions[".asciidoc"])
+	assert.True(t, gitstrat.DocumentExtensions[".txt"])
 	assert.False(t, gitstrat.DocumentExtensions[".go"])
 }
 
@@ -2244,6 +3304,116 @@ func TestProcessor_ProcessFile_MdxExtension(t *testing.T) {
 	assert.Contains(t, capturedDoc.Content, "# MDX Content")
 }
 
+func TestProcessor_ProcessFile_MdxExtension_CleanMDX(t *testing.T) {
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{
+		CleanMDX:        true,
+		FrontMatterKeys: []string{"sidebar_label"},
+	})
+
+	mdx := `---
+title: Getting Started
+description: Install and configure the CLI.
+sidebar_label: Start
+---
+import Tabs from '@theme/Tabs';
+import {
+  TabItem,
+  Callout,
+} from '@theme/components';
+export const meta = {
+  author: 'docs',
+};
+
+<!-- TODO: add screenshots -->
+
+# Getting Started
+
+Install the CLI first.
+
+<Tabs groupId="os">
+<TabItem value="mac" label="macOS">
+
+Use Homebrew.
+
+</TabItem>
+</Tabs>
+
+<Callout
+  type="info"
+/>
+
+` + "```js" + `
+import fs from 'fs';
+<Tabs />
+` + "```" + `
+
+{/* hidden note */}
+Done.
+`
+
+	tmpDir := t.TempDir()
+	mdxPath := filepath.Join(tmpDir, "start.mdx")
+	require.NoError(t, os.WriteFile(mdxPath, []byte(mdx), 0644))
+
+	var capturedDoc *domain.Document
+	opts := gitstrat.ProcessOptions{
+		RepoURL: "https://github.com/user/repo",
+		Branch:  "main",
+		WriteFunc: func(ctx context.Context, doc *domain.Document) error {
+			capturedDoc = doc
+			return nil
+		},
+	}
+
+	require.NoError(t, processor.ProcessFile(context.Background(), mdxPath, tmpDir, opts))
+	require.NotNil(t, capturedDoc)
+
+	content := capturedDoc.Content
+	assert.Contains(t, content, "# Getting Started")
+	assert.Contains(t, content, "Install the CLI first.")
+	assert.Contains(t, content, "Use Homebrew.")
+	assert.Contains(t, content, "Done.")
+	assert.Contains(t, content, "import fs from 'fs';", "code blocks are kept")
+	assert.Contains(t, content, "<Tabs />", "code blocks are kept")
+	assert.NotContains(t, content, "@theme")
+	assert.NotContains(t, content, "TabItem")
+	assert.NotContains(t, content, "export const")
+	assert.NotContains(t, content, "author:")
+	assert.NotContains(t, content, "TODO")
+	assert.NotContains(t, content, "hidden note")
+	assert.NotContains(t, content, "type=\"info\"")
+	assert.NotContains(t, content, "sidebar_label")
+	assert.NotContains(t, content, "\n\n\n")
+
+	assert.Equal(t, "Getting Started", capturedDoc.Title)
+	assert.Equal(t, "Install and configure the CLI.", capturedDoc.Description)
+	assert.Equal(t, map[string]string{"sidebar_label": "Start"}, capturedDoc.Metadata)
+}
+
+func TestProcessor_ProcessFile_MdxExtension_StripsImports(t *testing.T) {
+	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{CleanMDX: true})
+
+	tmpDir := t.TempDir()
+	mdxPath := filepath.Join(tmpDir, "component.mdx")
+	require.NoError(t, os.WriteFile(mdxPath, []byte("# MDX Content\n\nimport Component from './Component'"), 0644))
+
+	var capturedDoc *domain.Document
+	opts := gitstrat.ProcessOptions{
+		RepoURL: "https://github.com/user/repo",
+		Branch:  "main",
+		WriteFunc: func(ctx context.Context, doc *domain.Document) error {
+			capturedDoc = doc
+			return nil
+		},
+	}
+
+	require.NoError(t, processor.ProcessFile(context.Background(), mdxPath, tmpDir, opts))
+	require.NotNil(t, capturedDoc)
+	assert.Contains(t, capturedDoc.Content, "# MDX Content")
+	assert.NotContains(t, capturedDoc.Content, "import Component")
+	assert.Equal(t, "Component", capturedDoc.Title)
+}
+
 func TestProcessor_ProcessFile_RstExtension(t *testing.T) {
 	processor := gitstrat.NewProcessor(gitstrat.ProcessorOptions{})
 
@@ -2383,3 +3553,115 @@ func TestProcessorOptions_Fields(t *testing.T) {
 
 	assert.Equal(t, logger, opts.Logger)
 }
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "protocol.file.allow=always"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestCloneFetcher_Submodules(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tmpDir := t.TempDir()
+
+	subDir := filepath.Join(tmpDir, "sub")
+	require.NoError(t, os.MkdirAll(filepath.Join(subDir, "docs"), 0755))
+	runGit(t, subDir, "init", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, "docs", "guide.md"), []byte("# Guide"), 0644))
+	runGit(t, subDir, "add", ".")
+	runGit(t, subDir, "commit", "-m", "docs")
+
+	mainDir := filepath.Join(tmpDir, "main")
+	require.NoError(t, os.MkdirAll(mainDir, 0755))
+	runGit(t, mainDir, "init", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(mainDir, "README.md"), []byte("# Main"), 0644))
+	runGit(t, mainDir, "submodule", "add", subDir, "vendored")
+	runGit(t, mainDir, "add", ".")
+	runGit(t, mainDir, "commit", "-m", "add submodule")
+
+	info := &gitstrat.RepoInfo{URL: mainDir}
+
+	plainDir := filepath.Join(tmpDir, "plain")
+	_, err := gitstrat.NewCloneFetcher(gitstrat.CloneFetcherOptions{}).Fetch(context.Background(), info, "", plainDir)
+	require.NoError(t, err)
+	assert.True(t, gitstrat.HasSubmodules(plainDir))
+	assert.NoFileExists(t, filepath.Join(plainDir, "vendored", "docs", "guide.md"))
+
+	recursiveDir := filepath.Join(tmpDir, "recursive")
+	_, err = gitstrat.NewCloneFetcher(gitstrat.CloneFetcherOptions{Submodules: true}).Fetch(context.Background(), info, "", recursiveDir)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(recursiveDir, "vendored", "docs", "guide.md"))
+}
+
+func TestCloneFetcher_FetchRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "-b", "main")
+	commit := func(content string) string {
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, "README.md"), []byte(content), 0644))
+		runGit(t, repoDir, "add", ".")
+		runGit(t, repoDir, "commit", "-m", content)
+		out, err := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+		require.NoError(t, err)
+		return strings.TrimSpace(string(out))
+	}
+	first := commit("# v1")
+	runGit(t, repoDir, "tag", "v1.0.0")
+	commit("# v2")
+	runGit(t, repoDir, "branch", "stable", first)
+	runGit(t, repoDir, "branch", "3.12", first)
+	commit("# v3")
+
+	info := &gitstrat.RepoInfo{URL: repoDir}
+	fetcher := gitstrat.NewCloneFetcher(gitstrat.CloneFetcherOptions{})
+	tests := []struct {
+		name    string
+		ref     string
+		refType gitstrat.RefType
+		want    string
+	}{
+		{"default branch", "", "", "# v3"},
+		{"branch", "stable", gitstrat.RefBranch, "# v1"},
+		{"tag", "v1.0.0", gitstrat.RefTag, "# v1"},
+		{"version-named branch", "3.12", gitstrat.RefName, "# v1"},
+		{"version-named tag", "v1.0.0", gitstrat.RefName, "# v1"},
+		{"tag given as branch", "v1.0.0", gitstrat.RefBranch, "# v1"},
+		{"commit", first, gitstrat.RefCommit, "# v1"},
+		{"abbreviated commit", first[:10], gitstrat.RefCommit, "# v1"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			destDir := t.TempDir()
+			result, err := fetcher.FetchRef(context.Background(), info, tc.ref, tc.refType, destDir)
+			require.NoError(t, err)
+			content, err := os.ReadFile(filepath.Join(destDir, "README.md"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(content))
+			if tc.ref != "" {
+				assert.Equal(t, tc.ref, result.Branch)
+			}
+		})
+	}
+
+	destDir := t.TempDir()
+	_, err := fetcher.FetchRef(context.Background(), info, strings.Repeat("0", 40), gitstrat.RefCommit, destDir)
+	require.Error(t, err)
+	entries, err := os.ReadDir(destDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a failed checkout leaves nothing behind")
+}
+
+func TestHasSubmodules(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.False(t, gitstrat.HasSubmodules(tmpDir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".gitmodules"), []byte("[submodule \"docs\"]\n\tpath = docs\n"), 0644))
+	assert.True(t, gitstrat.HasSubmodules(tmpDir))
+}
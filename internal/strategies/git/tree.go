@@ -0,0 +1,365 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// DefaultTreeMaxFiles is the number of matching files above which a
+// subdirectory is considered too large for per-file downloads.
+const DefaultTreeMaxFiles = 300
+
+// DefaultGitHubRawBaseURL serves raw file contents for GitHub repositories.
+const DefaultGitHubRawBaseURL = "https://raw.githubusercontent.com"
+
+// treeRootFiles are fetched from the repository root alongside the
+// subdirectory so discovery honors ignore files and notices submodules as it
+// does for full downloads.
+var treeRootFiles = []string{RepodocsIgnoreFile, ".gitignore", ".gitmodules"}
+
+// TreeFetcher lists a repository subdirectory through the GitHub or GitLab API
+// and downloads only its documentation and configuration files, avoiding a
+// full archive download or clone.
+type TreeFetcher struct {
+	httpClient  *http.Client
+	logger      *utils.Logger
+	apiBaseURLs map[Platform]string
+	rawBaseURL  string
+	maxFiles    int
+	concurrency int
+}
+
+// TreeFetcherOptions configures a TreeFetcher.
+type TreeFetcherOptions struct {
+	// HTTPClient lists trees and downloads files; nil uses NewHTTPClient
+	// with ProxyURL.
+	HTTPClient *http.Client
+	ProxyURL   string
+	Logger     *utils.Logger
+	// APIBaseURLs overrides entries of DefaultAPIBaseURLs.
+	APIBaseURLs map[Platform]string
+	// RawBaseURL overrides DefaultGitHubRawBaseURL.
+	RawBaseURL string
+	// MaxFiles is the largest number of matching files fetched one by one;
+	// 0 uses DefaultTreeMaxFiles.
+	MaxFiles int
+	// Concurrency is the number of parallel file downloads; 0 uses 5.
+	Concurrency int
+}
+
+// NewTreeFetcher creates an API-backed subdirectory fetcher.
+func NewTreeFetcher(opts TreeFetcherOptions) *TreeFetcher {
+	client := opts.HTTPClient
+	if client == nil {
+		client = NewHTTPClient(opts.ProxyURL)
+	}
+	apiBaseURLs := make(map[Platform]string, len(DefaultAPIBaseURLs))
+	for platform, base := range DefaultAPIBaseURLs {
+		apiBaseURLs[platform] = base
+	}
+	for platform, base := range opts.APIBaseURLs {
+		apiBaseURLs[platform] = strings.TrimSuffix(base, "/")
+	}
+	rawBaseURL := strings.TrimSuffix(opts.RawBaseURL, "/")
+	if rawBaseURL == "" {
+		rawBaseURL = DefaultGitHubRawBaseURL
+	}
+	maxFiles := opts.MaxFiles
+	if maxFiles <= 0 {
+		maxFiles = DefaultTreeMaxFiles
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 5
+	}
+
+	return &TreeFetcher{
+		httpClient:  client,
+		logger:      opts.Logger,
+		apiBaseURLs: apiBaseURLs,
+		rawBaseURL:  rawBaseURL,
+		maxFiles:    maxFiles,
+		concurrency: concurrency,
+	}
+}
+
+// Name returns the fetch method name used in FetchResult values and logs.
+func (f *TreeFetcher) Name() string {
+	return "api"
+}
+
+// Supports reports whether the repository's platform has a tree API.
+// Repositories on self-hosted instances are not supported.
+func (f *TreeFetcher) Supports(info *RepoInfo) bool {
+	return (info.Platform == PlatformGitHub || info.Platform == PlatformGitLab) &&
+		info.Owner != "" && info.Repo != "" && info.BaseURL == ""
+}
+
+// Fetch downloads the documentation and configuration files under subPath
+// of branch into destDir, keeping their repository paths. It fails when the
+// API is unavailable or rate limited, or when the subdirectory holds more than
+// the configured maximum of files; the caller removes destDir on failure.
+func (f *TreeFetcher) Fetch(ctx context.Context, info *RepoInfo, branch, subPath, destDir string) (*FetchResult, error) {
+	if !f.Supports(info) {
+		return nil, fmt.Errorf("no tree API for platform %q", info.Platform)
+	}
+	subPath = strings.Trim(subPath, "/")
+	if subPath == "" {
+		return nil, fmt.Errorf("tree API download requires a subdirectory")
+	}
+
+	var files []string
+	var remaining int
+	var err error
+	if info.Platform == PlatformGitLab {
+		files, remaining, err = f.listGitLab(ctx, info, branch, subPath)
+	} else {
+		files, remaining, err = f.listGitHub(ctx, info, branch, subPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no documentation files found under path: %s", subPath)
+	}
+	// GitLab serves raw files through the rate-limited API; GitHub raw
+	// downloads are not counted against the API limit.
+	if info.Platform == PlatformGitLab && remaining >= 0 && remaining < len(files)+len(treeRootFiles) {
+		return nil, fmt.Errorf("API rate limit too low for %d files (%d requests remaining)", len(files), remaining)
+	}
+
+	if f.logger != nil {
+		f.logger.Debug().Str("path", subPath).Int("files", len(files)).Msg("Downloading files through tree API")
+	}
+
+	errs := utils.ParallelForEach(ctx, files, f.concurrency, func(ctx context.Context, file string) error {
+		return f.download(ctx, info, branch, file, destDir, true)
+	})
+	if err := utils.FirstError(errs); err != nil {
+		return nil, err
+	}
+	for _, name := range treeRootFiles {
+		if err := f.download(ctx, info, branch, name, destDir, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return &FetchResult{
+		LocalPath: destDir,
+		Branch:    branch,
+		Method:    f.Name(),
+	}, nil
+}
+
+// listGitHub lists matching files with the recursive Git Trees API.
+func (f *TreeFetcher) listGitHub(ctx context.Context, info *RepoInfo, branch, subPath string) ([]string, int, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/git/trees/%s?recursive=1",
+		f.apiBaseURLs[PlatformGitHub], info.Owner, info.Repo, url.PathEscape(branch))
+
+	var body struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		} `json:"tree"`
+		Truncated bool `json:"truncated"`
+	}
+	header, err := f.getJSON(ctx, info, apiURL, &body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if body.Truncated {
+		return nil, 0, fmt.Errorf("repository tree is too large for the API")
+	}
+
+	var files []string
+	for _, entry := range body.Tree {
+		if entry.Type == "blob" && treeFileWanted(entry.Path, subPath) {
+			files = append(files, entry.Path)
+		}
+	}
+	if len(files) > f.maxFiles {
+		return nil, 0, fmt.Errorf("%d files under %s exceed the API download limit of %d", len(files), subPath, f.maxFiles)
+	}
+	return files, rateLimitRemaining(header, "X-RateLimit-Remaining"), nil
+}
+
+// listGitLab lists matching files with the paginated Repository Tree API.
+func (f *TreeFetcher) listGitLab(ctx context.Context, info *RepoInfo, branch, subPath string) ([]string, int, error) {
+	query := url.Values{}
+	query.Set("path", subPath)
+	query.Set("ref", branch)
+	query.Set("recursive", "true")
+	query.Set("per_page", "100")
+
+	var files []string
+	remaining := -1
+	for page := "1"; page != ""; {
+		query.Set("page", page)
+		apiURL := fmt.Sprintf("%s/projects/%s/repository/tree?%s",
+			f.apiBaseURLs[PlatformGitLab], gitLabProjectID(info), query.Encode())
+
+		var entries []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		}
+		header, err := f.getJSON(ctx, info, apiURL, &entries)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, entry := range entries {
+			if entry.Type == "blob" && treeFileWanted(entry.Path, subPath) {
+				files = append(files, entry.Path)
+			}
+		}
+		if len(files) > f.maxFiles {
+			return nil, 0, fmt.Errorf("more than %d files under %s exceed the API download limit", f.maxFiles, subPath)
+		}
+		remaining = rateLimitRemaining(header, "RateLimit-Remaining")
+		page = header.Get("X-Next-Page")
+	}
+	return files, remaining, nil
+}
+
+// getJSON requests apiURL with the platform token and decodes the response.
+func (f *TreeFetcher) getJSON(ctx context.Context, info *RepoInfo, apiURL string, v interface{}) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	setPlatformToken(req, info.Platform)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tree API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := treeStatusError(resp); err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("failed to decode tree API response: %w", err)
+	}
+	return resp.Header, nil
+}
+
+// download writes one repository file to its path under destDir. A missing
+// optional file is skipped.
+func (f *TreeFetcher) download(ctx context.Context, info *RepoInfo, branch, file, destDir string, required bool) error {
+	targetPath := filepath.Join(destDir, filepath.FromSlash(file))
+	if !strings.HasPrefix(filepath.Clean(targetPath), filepath.Clean(destDir)+string(filepath.Separator)) {
+		return fmt.Errorf("invalid repository path: %s", file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.rawURL(info, branch, file), nil)
+	if err != nil {
+		return err
+	}
+	setPlatformToken(req, info.Platform)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("download request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && !required {
+		return nil
+	}
+	if err := treeStatusError(resp); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return fmt.Errorf("mkdir failed: %w", err)
+	}
+	out, err := os.Create(targetPath)
+	if err != nil {
+		return fmt.Errorf("create file failed: %w", err)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return fmt.Errorf("copy failed: %w", err)
+	}
+	return nil
+}
+
+// rawURL returns the URL serving the raw contents of file at branch.
+func (f *TreeFetcher) rawURL(info *RepoInfo, branch, file string) string {
+	if info.Platform == PlatformGitLab {
+		return fmt.Sprintf("%s/projects/%s/repository/files/%s/raw?ref=%s",
+			f.apiBaseURLs[PlatformGitLab], gitLabProjectID(info), url.PathEscape(file), url.QueryEscape(branch))
+	}
+
+	segments := strings.Split(file, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/%s",
+		f.rawBaseURL, info.Owner, info.Repo, branch, strings.Join(segments, "/"))
+}
+
+// treeFileWanted reports whether a repository file under subPath is one that
+// discovery would extract.
+func treeFileWanted(file, subPath string) bool {
+	if !strings.HasPrefix(file, subPath+"/") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(file))
+	return DocumentExtensions[ext] || ConfigExtensions[ext] || path.Base(file) == "CODEOWNERS"
+}
+
+func gitLabProjectID(info *RepoInfo) string {
+	return url.PathEscape(info.Owner + "/" + info.Repo)
+}
+
+func setPlatformToken(req *http.Request, platform Platform) {
+	switch platform {
+	case PlatformGitHub:
+		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+			req.Header.Set("Authorization", "token "+token)
+		}
+	case PlatformGitLab:
+		if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		}
+	}
+}
+
+func treeStatusError(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+		return fmt.Errorf("API rate limit exceeded (%d)", resp.StatusCode)
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("not found (404)")
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("authentication required (401)")
+	default:
+		return fmt.Errorf("request failed with status: %d", resp.StatusCode)
+	}
+}
+
+// rateLimitRemaining parses a remaining-requests header, returning -1 when
+// the server does not report one.
+func rateLimitRemaining(header http.Header, name string) int {
+	remaining, err := strconv.Atoi(header.Get(name))
+	if err != nil {
+		return -1
+	}
+	return remaining
+}
@@ -0,0 +1,260 @@
+package git_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	gitstrat "github.com/quantmind-br/repodocs/internal/strategies/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGitHubTreeServer(t *testing.T, tree string, truncated bool) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/api/repos/acme/docs/git/trees/main":
+			fmt.Fprintf(w, `{"truncated":%t,"tree":%s}`, truncated, tree)
+		case "/raw/acme/docs/main/docs/guide.md":
+			fmt.Fprint(w, "# Guide")
+		case "/raw/acme/docs/main/docs/api/config.yaml":
+			fmt.Fprint(w, "key: value")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+const githubTree = `[
+	{"path":"README.md","type":"blob"},
+	{"path":"docs","type":"tree"},
+	{"path":"docs/guide.md","type":"blob"},
+	{"path":"docs/logo.png","type":"blob"},
+	{"path":"docs/api/config.yaml","type":"blob"},
+	{"path":"docsite/index.md","type":"blob"}
+]`
+
+func TestTreeFetcher_GitHub(t *testing.T) {
+	server, requests := newGitHubTreeServer(t, githubTree, false)
+	fetcher := gitstrat.NewTreeFetcher(gitstrat.TreeFetcherOptions{
+		HTTPClient:  server.Client(),
+		APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitHub: server.URL + "/api"},
+		RawBaseURL:  server.URL + "/raw",
+	})
+	destDir := t.TempDir()
+
+	result, err := fetcher.Fetch(context.Background(), &gitstrat.RepoInfo{
+		Platform: gitstrat.PlatformGitHub,
+		Owner:    "acme",
+		Repo:     "docs",
+	}, "main", "/docs/", destDir)
+	require.NoError(t, err)
+	assert.Equal(t, "api", result.Method)
+	assert.Equal(t, "main", result.Branch)
+
+	content, err := os.ReadFile(filepath.Join(destDir, "docs", "guide.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Guide", string(content))
+	assert.FileExists(t, filepath.Join(destDir, "docs", "api", "config.yaml"))
+	assert.NoFileExists(t, filepath.Join(destDir, "README.md"))
+	assert.NoFileExists(t, filepath.Join(destDir, "docs", "logo.png"))
+	assert.NoFileExists(t, filepath.Join(destDir, "docsite", "index.md"))
+
+	for _, path := range *requests {
+		assert.NotContains(t, path, "logo.png")
+		assert.NotContains(t, path, "docsite")
+	}
+}
+
+func TestTreeFetcher_Proxy(t *testing.T) {
+	// The server stands in for a proxy: requests for the unresolvable host
+	// only succeed when they are sent to it.
+	proxy, requests := newGitHubTreeServer(t, githubTree, false)
+	fetcher := gitstrat.NewTreeFetcher(gitstrat.TreeFetcherOptions{
+		ProxyURL:    proxy.URL,
+		APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitHub: "http://github.invalid/api"},
+		RawBaseURL:  "http://github.invalid/raw",
+	})
+
+	_, err := fetcher.Fetch(context.Background(), &gitstrat.RepoInfo{
+		Platform: gitstrat.PlatformGitHub,
+		Owner:    "acme",
+		Repo:     "docs",
+	}, "main", "docs", t.TempDir())
+	require.NoError(t, err)
+	assert.Contains(t, *requests, "/api/repos/acme/docs/git/trees/main")
+	assert.Contains(t, *requests, "/raw/acme/docs/main/docs/guide.md")
+}
+
+func TestTreeFetcher_GitHubFallbackConditions(t *testing.T) {
+	tests := []struct {
+		name      string
+		tree      string
+		truncated bool
+		maxFiles  int
+	}{
+		{name: "truncated tree", tree: githubTree, truncated: true},
+		{name: "too many files", tree: githubTree, maxFiles: 1},
+		{name: "no matching files", tree: `[{"path":"README.md","type":"blob"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newGitHubTreeServer(t, tt.tree, tt.truncated)
+			fetcher := gitstrat.NewTreeFetcher(gitstrat.TreeFetcherOptions{
+				HTTPClient:  server.Client(),
+				APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitHub: server.URL + "/api"},
+				RawBaseURL:  server.URL + "/raw",
+				MaxFiles:    tt.maxFiles,
+			})
+
+			_, err := fetcher.Fetch(context.Background(), &gitstrat.RepoInfo{
+				Platform: gitstrat.PlatformGitHub,
+				Owner:    "acme",
+				Repo:     "docs",
+			}, "main", "docs", t.TempDir())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTreeFetcher_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	fetcher := gitstrat.NewTreeFetcher(gitstrat.TreeFetcherOptions{
+		HTTPClient:  server.Client(),
+		APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitHub: server.URL},
+	})
+
+	_, err := fetcher.Fetch(context.Background(), &gitstrat.RepoInfo{
+		Platform: gitstrat.PlatformGitHub,
+		Owner:    "acme",
+		Repo:     "docs",
+	}, "main", "docs", t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limit")
+}
+
+func TestTreeFetcher_GitLab(t *testing.T) {
+	remaining := "100"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("RateLimit-Remaining", remaining)
+		switch {
+		case r.URL.EscapedPath() == "/projects/acme%2Fdocs/repository/tree":
+			assert.Equal(t, "docs", r.URL.Query().Get("path"))
+			assert.Equal(t, "develop", r.URL.Query().Get("ref"))
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[{"path":"docs/guide.md","type":"blob"},{"path":"docs/api","type":"tree"}]`)
+				return
+			}
+			fmt.Fprint(w, `[{"path":"docs/api/reference.md","type":"blob"}]`)
+		case strings.HasSuffix(r.URL.EscapedPath(), "/raw") && strings.HasPrefix(r.URL.EscapedPath(), "/projects/acme%2Fdocs/repository/files/docs%2F"):
+			assert.Equal(t, "develop", r.URL.Query().Get("ref"))
+			fmt.Fprint(w, "# Doc")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := gitstrat.NewTreeFetcher(gitstrat.TreeFetcherOptions{
+		HTTPClient:  server.Client(),
+		APIBaseURLs: map[gitstrat.Platform]string{gitstrat.PlatformGitLab: server.URL},
+	})
+	info := &gitstrat.RepoInfo{Platform: gitstrat.PlatformGitLab, Owner: "acme", Repo: "docs"}
+
+	destDir := t.TempDir()
+	_, err := fetcher.Fetch(context.Background(), info, "develop", "docs", destDir)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(destDir, "docs", "guide.md"))
+	assert.FileExists(t, filepath.Join(destDir, "docs", "api", "reference.md"))
+
+	remaining = "2"
+	_, err = fetcher.Fetch(context.Background(), info, "develop", "docs", t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limit")
+}
+
+func TestTreeFetcher_Supports(t *testing.T) {
+	fetcher := gitstrat.NewTreeFetcher(gitstrat.TreeFetcherOptions{})
+
+	assert.True(t, fetcher.Supports(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGitHub, Owner: "a", Repo: "b"}))
+	assert.True(t, fetcher.Supports(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGitLab, Owner: "a", Repo: "b"}))
+	assert.False(t, fetcher.Supports(&gitstrat.RepoInfo{Platform: gitstrat.PlatformBitbucket, Owner: "a", Repo: "b"}))
+	assert.False(t, fetcher.Supports(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGeneric, URL: "https://git.example.com/a/b.git"}))
+	assert.False(t, fetcher.Supports(&gitstrat.RepoInfo{Platform: gitstrat.PlatformGitLab, Owner: "a", Repo: "b", BaseURL: "https://gitlab.internal.corp"}))
+}
+
+func TestExecute_SubPathUsesTreeAPI(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/repos/acme/docs/git/trees/main":
+			fmt.Fprint(w, `{"tree":[
+				{"path":"README.md","type":"blob"},
+				{"path":"docs/guide.md","type":"blob"},
+				{"path":"docs/api/reference.md","type":"blob"}
+			]}`)
+		case "/acme/docs/main/docs/guide.md":
+			fmt.Fprint(w, "# Guide\n\nHello.")
+		case "/acme/docs/main/docs/api/reference.md":
+			fmt.Fprint(w, "# Reference\n\nDetails.")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	var docsMu sync.Mutex
+	var docs []string
+	deps := setupTestDependencies(t, t.TempDir())
+	deps.HTTPClient = &http.Client{Transport: rewriteTransport{target: target}}
+	deps.WriteFunc = func(ctx context.Context, doc *domain.Document) error {
+		docsMu.Lock()
+		defer docsMu.Unlock()
+		docs = append(docs, doc.RelativePath)
+		return nil
+	}
+	strategy := gitstrat.NewStrategy(deps)
+
+	err = strategy.Execute(context.Background(), "https://github.com/acme/docs/tree/main/docs", gitstrat.ExecuteOptions{
+		Output:      t.TempDir(),
+		Concurrency: 2,
+	})
+	require.NoError(t, err)
+
+	sort.Strings(docs)
+	assert.Equal(t, []string{"docs/api/reference.md", "docs/guide.md"}, docs)
+	for _, path := range requests {
+		assert.NotContains(t, path, ".tar.gz", "no archive download for a subpath served by the API")
+	}
+}
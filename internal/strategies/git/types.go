@@ -14,12 +14,29 @@ const (
 	PlatformGeneric Platform = "generic"
 )
 
+// RefType identifies the kind of git reference a URL pins.
+type RefType string
+
+const (
+	// RefBranch identifies a branch name.
+	RefBranch RefType = "branch"
+	// RefTag identifies a tag name, such as a release version.
+	RefTag RefType = "tag"
+	// RefName identifies a branch or tag name that a URL does not tell
+	// apart, such as the version-like 3.12 in a tree URL. Branches are
+	// preferred over tags of the same name.
+	RefName RefType = "ref"
+	// RefCommit identifies a commit SHA.
+	RefCommit RefType = "commit"
+)
+
 // RepoInfo contains parsed repository information
 type RepoInfo struct {
 	Platform Platform
 	Owner    string
 	Repo     string
 	URL      string // Original URL
+	BaseURL  string // Scheme and host of a self-hosted instance (empty for public hosts)
 }
 
 // GitURLInfo contains parsed Git URL information including optional path
@@ -28,8 +45,11 @@ type GitURLInfo struct {
 	Platform Platform
 	Owner    string
 	Repo     string
-	Branch   string // Branch from URL (empty if not specified)
+	Branch   string // Branch from URL (empty if not specified or the URL pins a tag or commit)
 	SubPath  string // Subdirectory path (empty if root)
+	BaseURL  string // Scheme and host of a self-hosted instance (empty for public hosts)
+	Ref      string // Branch, tag, or commit SHA from URL (empty if not specified)
+	RefType  RefType
 }
 
 // FetchResult contains the result of a repository fetch operation
@@ -39,13 +59,17 @@ type FetchResult struct {
 	Method    string // "archive" or "clone"
 }
 
-// DocumentExtensions are file extensions to process as Markdown documents.
+// DocumentExtensions are file extensions to process as documents.
 // `.rst` files are converted to Markdown by `converter.ConvertRST` in the
-// processor before being written.
+// processor before being written; AsciiDoc and plain text files are wrapped
+// in a code block.
 var DocumentExtensions = map[string]bool{
-	".md":  true,
-	".mdx": true,
-	".rst": true,
+	".md":       true,
+	".mdx":      true,
+	".rst":      true,
+	".adoc":     true,
+	".asciidoc": true,
+	".txt":      true,
 }
 
 // ConfigExtensions are configuration file extensions to include as raw files.
@@ -7,6 +7,7 @@ import (
 
 	"github.com/quantmind-br/repodocs/internal/domain"
 	"github.com/quantmind-br/repodocs/internal/strategies/git"
+	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
 var DocumentExtensions = git.DocumentExtensions
@@ -29,6 +30,7 @@ type GitStrategy struct {
 func NewGitStrategy(deps *Dependencies) *GitStrategy {
 	var gitDeps *git.StrategyDependencies
 	var httpClient *http.Client
+	var selfHosted map[string]git.Platform
 
 	if deps != nil {
 		gitDeps = &git.StrategyDependencies{
@@ -37,12 +39,30 @@ func NewGitStrategy(deps *Dependencies) *GitStrategy {
 			HTTPClient:   deps.HTTPClient,
 			WriteFunc:    deps.WriteDocument,
 			StateManager: deps.StateManager,
+			Branches:     deps.gitBranches,
+
+			HashAlgorithm:   deps.hashAlgorithm,
+			KeepTemp:        deps.keepTemp,
+			SelfHostedHosts: deps.gitSelfHostedHosts,
+			ProxyURL:        deps.proxyURL,
+		}
+		if !deps.noSpaceCheck && deps.Writer != nil {
+			gitDeps.SpaceCheck = &git.SpaceChecker{OutputDir: deps.Writer.BaseDir()}
 		}
 		httpClient = deps.HTTPClient
+		selfHosted = deps.gitSelfHostedHosts
+		if httpClient == nil && deps.injectedFetcher {
+			httpClient = fetcherHTTPClient(deps.Fetcher)
+			gitDeps.HTTPClient = httpClient
+		}
 	}
 
 	if httpClient == nil {
-		httpClient = http.DefaultClient
+		var proxyURL string
+		if deps != nil {
+			proxyURL = deps.proxyURL
+		}
+		httpClient = git.NewHTTPClient(proxyURL)
 	}
 
 	var logger = deps.Logger
@@ -53,7 +73,7 @@ func NewGitStrategy(deps *Dependencies) *GitStrategy {
 	return &GitStrategy{
 		strategy: git.NewStrategy(gitDeps),
 		deps:     deps,
-		parser:   git.NewParser(),
+		parser:   git.NewParserWithOptions(git.ParserOptions{SelfHostedHosts: selfHosted}),
 		archiveFetcher: git.NewArchiveFetcher(git.ArchiveFetcherOptions{
 			HTTPClient: httpClient,
 			Logger:     logger,
@@ -65,6 +85,27 @@ func NewGitStrategy(deps *Dependencies) *GitStrategy {
 	}
 }
 
+// fetcherHTTPClient returns a client whose requests go through f.
+func fetcherHTTPClient(f domain.Fetcher) *http.Client {
+	return &http.Client{Transport: f.Transport()}
+}
+
+// SetFetcher routes archive downloads, GitHub tree API and default-branch
+// lookups through f (see domain.Fetcher). Clones use git's own transport,
+// which honors the HTTPS_PROXY environment variable.
+func (s *GitStrategy) SetFetcher(f domain.Fetcher) {
+	var logger *utils.Logger
+	if s.deps != nil {
+		logger = s.deps.Logger
+	}
+	s.httpClient = fetcherHTTPClient(f)
+	s.archiveFetcher = git.NewArchiveFetcher(git.ArchiveFetcherOptions{
+		HTTPClient: s.httpClient,
+		Logger:     logger,
+	})
+	s.strategy.SetHTTPClient(s.httpClient)
+}
+
 func (s *GitStrategy) Name() string {
 	return s.strategy.Name()
 }
@@ -73,15 +114,32 @@ func (s *GitStrategy) CanHandle(url string) bool {
 	return s.strategy.CanHandle(url)
 }
 
+func (s *GitStrategy) Validate(ctx context.Context, url string) error {
+	return s.strategy.Validate(ctx, url)
+}
+
 func (s *GitStrategy) Execute(ctx context.Context, rawURL string, opts Options) (*domain.StrategyResult, error) {
-	result := domain.NewStrategyResult(s.Name(), rawURL)
+	result := s.deps.NewResult(s.Name(), rawURL)
 	gitOpts := git.ExecuteOptions{
-		Output:      opts.Output,
-		Concurrency: opts.Concurrency,
-		Limit:       opts.Limit,
-		DryRun:      opts.DryRun,
-		FilterURL:   opts.FilterURL,
-		Result:      result,
+		Output:            opts.Output,
+		Concurrency:       opts.Concurrency,
+		Limit:             opts.Limit,
+		DryRun:            opts.DryRun,
+		FilterURL:         opts.FilterURL,
+		PreserveTree:      opts.PreserveTree,
+		HonorGitignore:    opts.HonorGitignore,
+		IgnoreDirs:        opts.IgnoreDirs,
+		ReplaceIgnoreDirs: opts.ReplaceIgnoreDirs,
+		IncludeGitHubMeta: opts.IncludeGitHubMeta,
+		Include:           opts.Include,
+		Exclude:           opts.ExcludePaths,
+		Submodules:        opts.Submodules,
+		LFS:               opts.LFS,
+		CloneTimeout:      opts.CloneTimeout,
+		CleanMDX:          opts.CleanMDX,
+		FrontMatterKeys:   opts.FrontMatterKeys,
+		NoProgress:        opts.NoProgress,
+		Result:            result,
 	}
 	err := s.strategy.Execute(ctx, rawURL, gitOpts)
 	if err != nil {
@@ -110,7 +168,7 @@ func (s *GitStrategy) extractTarGz(r io.Reader, destDir string) error {
 }
 
 func (s *GitStrategy) findDocumentationFiles(dir string, filterPath string) ([]string, error) {
-	return s.processor.FindDocumentationFiles(dir, filterPath)
+	return s.processor.FindDocumentationFiles(context.Background(), dir, filterPath)
 }
 
 func (s *GitStrategy) processFiles(ctx context.Context, files []string, tmpDir, repoURL, branch string, opts Options) error {
@@ -120,8 +178,11 @@ func (s *GitStrategy) processFiles(ctx context.Context, files []string, tmpDir,
 		Concurrency:  opts.Concurrency,
 		Limit:        opts.Limit,
 		DryRun:       opts.DryRun,
+		NoProgress:   opts.NoProgress,
 		WriteFunc:    s.deps.WriteDocument,
 		StateManager: s.deps.StateManager,
+
+		HashAlgorithm: s.deps.hashAlgorithm,
 	}
 	return s.processor.ProcessFiles(ctx, files, tmpDir, processOpts)
 }
@@ -133,6 +194,8 @@ func (s *GitStrategy) processFile(ctx context.Context, path, tmpDir, repoURL, br
 		DryRun:       opts.DryRun,
 		WriteFunc:    s.deps.WriteDocument,
 		StateManager: s.deps.StateManager,
+
+		HashAlgorithm: s.deps.hashAlgorithm,
 	}
 	return s.processor.ProcessFile(ctx, path, tmpDir, processOpts)
 }
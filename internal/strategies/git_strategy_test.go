@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -1281,6 +1282,56 @@ func TestExecute_ArchiveFailCloneSuccess(t *testing.T) {
 	// The important thing is we tested the fallback logic
 }
 
+// TestExecute_IncludeExcludeGlobs tests that Include and ExcludePaths reach
+// git discovery
+func TestExecute_IncludeExcludeGlobs(t *testing.T) {
+	repoDir := t.TempDir()
+	for path, content := range map[string]string{
+		"README.md":            "# Readme",
+		"docs/guide.md":        "# Guide",
+		"docs/archive/old.md":  "# Old",
+		"examples/example.txt": "example",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repoDir, filepath.Dir(path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, path), []byte(content), 0644))
+	}
+	for _, args := range [][]string{
+		{"init"},
+		{"add", "."},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-m", "Initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		require.NoError(t, cmd.Run())
+	}
+
+	outDir := t.TempDir()
+	deps := &Dependencies{
+		Writer: output.NewWriter(output.WriterOptions{BaseDir: outDir, Force: true}),
+		Logger: utils.NewLogger(utils.LoggerOptions{Level: "error"}),
+	}
+	opts := Options{
+		Concurrency:  1,
+		Include:      []string{"docs/**"},
+		ExcludePaths: []string{"docs/archive/**"},
+	}
+
+	_, err := NewGitStrategy(deps).Execute(context.Background(), "file://"+repoDir, opts)
+	require.NoError(t, err)
+
+	var contents []string
+	require.NoError(t, filepath.WalkDir(outDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".md" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		contents = append(contents, string(data))
+		return err
+	}))
+	require.Len(t, contents, 1)
+	assert.Contains(t, contents[0], "# Guide")
+}
+
 // TestExecute_BothMethodsFail tests when both archive and clone fail
 func TestExecute_BothMethodsFail(t *testing.T) {
 	logger := utils.NewLogger(utils.LoggerOptions{Level: "error"})
@@ -2,6 +2,7 @@ package strategies
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"regexp"
@@ -63,7 +64,7 @@ func NewGitHubPagesStrategy(deps *Dependencies) *GitHubPagesStrategy {
 		deps:           deps,
 		fetcher:        deps.Fetcher,
 		converter:      deps.Converter,
-		markdownReader: converter.NewMarkdownReader(),
+		markdownReader: deps.Converter.MarkdownReader(),
 		writer:         deps.Writer,
 		logger:         deps.Logger,
 	}
@@ -74,6 +75,12 @@ func (s *GitHubPagesStrategy) Name() string {
 	return "github_pages"
 }
 
+// SetFetcher replaces the fetcher the strategy requests pages with, such as
+// a client for an authenticated proxy (see domain.Fetcher).
+func (s *GitHubPagesStrategy) SetFetcher(f domain.Fetcher) {
+	s.fetcher = f
+}
+
 // CanHandle returns true if URL is a GitHub Pages site
 func (s *GitHubPagesStrategy) CanHandle(rawURL string) bool {
 	return IsGitHubPagesURL(rawURL)
@@ -91,7 +98,8 @@ func IsGitHubPagesURL(rawURL string) bool {
 
 // Execute runs the GitHub Pages extraction strategy
 func (s *GitHubPagesStrategy) Execute(ctx context.Context, inputURL string, opts Options) (*domain.StrategyResult, error) {
-	result := domain.NewStrategyResult(s.Name(), inputURL)
+	result := s.deps.NewResult(s.Name(), inputURL)
+	s.converter = converterFor(s.converter, opts)
 	err := s.execute(ctx, inputURL, opts, result)
 	result.Finish()
 	return result, err
@@ -424,7 +432,7 @@ func (s *GitHubPagesStrategy) filterURLs(urls []string, baseURL string, opts Opt
 
 // processURLs processes all URLs using HTTP-first extraction with browser fallback
 func (s *GitHubPagesStrategy) processURLs(ctx context.Context, urls []string, opts Options, result *domain.StrategyResult) error {
-	bar := utils.NewProgressBar(len(urls), utils.DescExtracting)
+	bar := newProgressBar(opts, len(urls), utils.DescExtracting)
 
 	// Limit browser concurrency for stability
 	concurrency := opts.Concurrency
@@ -449,22 +457,35 @@ func (s *GitHubPagesStrategy) processURLs(ctx context.Context, urls []string, op
 		}()
 
 		// Check if already exists
+		s.deps.MarkSeen(pageURL)
 		if !opts.Force && s.writer.Exists(pageURL) {
 			result.IncSkipped()
 			return nil
 		}
 
+		if !s.deps.TakeHostSlot(pageURL) {
+			result.IncSkipped()
+			return nil
+		}
+
 		// HTTP-first fetch with browser fallback
 		html, usedBrowser, err := s.fetchOrRenderPage(ctx, pageURL, opts)
 		if err != nil {
-			result.IncFailed()
+			result.FailDocument(pageURL, err)
+			if s.deps.RecordFailure(result, pageURL, err) {
+				s.logger.Debug().Err(err).Str("url", pageURL).Msg("Fetch failed transiently, queued for retry")
+				return nil
+			}
 			s.logger.Warn().Err(err).Str("url", pageURL).Msg("Failed to fetch/render page")
 			return nil
 		}
 
 		// Validate content
+		if s.deps.SkipAuthWall(result, pageURL, "", html) {
+			return nil
+		}
 		if s.isEmptyOrErrorContent(html) {
-			result.IncFailed()
+			result.FailDocument(pageURL, errors.New("empty or error page content"))
 			s.logger.Debug().Str("url", pageURL).Msg("Empty or error content, skipping")
 			return nil
 		}
@@ -472,17 +493,19 @@ func (s *GitHubPagesStrategy) processURLs(ctx context.Context, urls []string, op
 		// Convert HTML to document
 		doc, err := s.converter.Convert(ctx, html, pageURL)
 		if err != nil {
-			result.IncFailed()
-			s.logger.Warn().Err(err).Str("url", pageURL).Msg("Failed to convert page")
+			s.deps.RecordConvertError(result, pageURL, err)
 			return nil
 		}
 
 		// Validate converted content
 		if len(strings.TrimSpace(doc.Content)) < 50 {
-			result.IncFailed()
+			result.FailDocument(pageURL, errors.New("converted content too short"))
 			s.logger.Debug().Str("url", pageURL).Msg("Converted content too short, skipping")
 			return nil
 		}
+		if s.deps.SkipDuplicateCanonical(result, doc) {
+			return nil
+		}
 
 		// Set metadata
 		doc.SourceStrategy = s.Name()
@@ -495,10 +518,14 @@ func (s *GitHubPagesStrategy) processURLs(ctx context.Context, urls []string, op
 				"The site uses client-side rendering; JS rendering adds latency")
 		}
 
+		if s.deps.SkipUnchanged(result, doc) {
+			return nil
+		}
+
 		// Write document
 		if !opts.DryRun {
 			if err := s.deps.WriteDocument(ctx, doc); err != nil {
-				result.IncFailed()
+				result.FailDocument(pageURL, err)
 				s.logger.Warn().Err(err).Str("url", pageURL).Msg("Failed to write document")
 				return nil
 			}
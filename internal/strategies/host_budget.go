@@ -0,0 +1,68 @@
+package strategies
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// hostBudget caps how many pages are processed per host over the lifetime of
+// a Dependencies set, so one host cannot consume a whole multi-host crawl or
+// manifest batch. It is independent of the per-run --limit.
+type hostBudget struct {
+	max int
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// newHostBudget returns nil (no cap) when max is not positive.
+func newHostBudget(max int) *hostBudget {
+	if max <= 0 {
+		return nil
+	}
+	return &hostBudget{max: max, counts: make(map[string]int)}
+}
+
+// take reserves a page for rawURL's host and reports whether it was within
+// the cap. URLs without a host are never capped.
+func (b *hostBudget) take(rawURL string) bool {
+	if b == nil {
+		return true
+	}
+	host := budgetHost(rawURL)
+	if host == "" {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.counts[host] >= b.max {
+		return false
+	}
+	b.counts[host]++
+	return true
+}
+
+// exhausted reports whether rawURL's host has already reached the cap.
+func (b *hostBudget) exhausted(rawURL string) bool {
+	if b == nil {
+		return false
+	}
+	host := budgetHost(rawURL)
+	if host == "" {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.counts[host] >= b.max
+}
+
+func budgetHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
@@ -0,0 +1,49 @@
+package strategies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostBudget(t *testing.T) {
+	assert.Nil(t, newHostBudget(0), "zero disables the cap")
+
+	var unlimited *hostBudget
+	assert.True(t, unlimited.take("https://a.example.com/1"))
+	assert.False(t, unlimited.exhausted("https://a.example.com/1"))
+
+	b := newHostBudget(2)
+	assert.True(t, b.take("https://a.example.com/1"))
+	assert.False(t, b.exhausted("https://a.example.com/2"))
+	assert.True(t, b.take("https://A.example.com/2"), "hosts are case-insensitive")
+	assert.True(t, b.exhausted("https://a.example.com/3"))
+	assert.False(t, b.take("https://a.example.com/3"))
+
+	assert.True(t, b.take("https://b.example.com/1"), "each host has its own cap")
+	assert.True(t, b.take("not a url with host"), "URLs without a host are not capped")
+}
+
+func TestDependencies_TakeHostSlot(t *testing.T) {
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:         10 * time.Second,
+		Concurrency:     1,
+		OutputDir:       t.TempDir(),
+		MaxPagesPerHost: 1,
+		CommonOptions:   domain.CommonOptions{DryRun: true},
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	assert.True(t, deps.TakeHostSlot("https://docs.example.com/a"))
+	assert.True(t, deps.HostExhausted("https://docs.example.com/b"))
+	assert.False(t, deps.TakeHostSlot("https://docs.example.com/b"))
+	assert.True(t, deps.TakeHostSlot("https://other.example.com/a"))
+
+	var none *Dependencies
+	assert.True(t, none.TakeHostSlot("https://docs.example.com/c"))
+	assert.False(t, none.HostExhausted("https://docs.example.com/c"))
+}
@@ -0,0 +1,34 @@
+package strategies
+
+import (
+	"context"
+	"maps"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// labelsKey is the context key of the labels attached by WithLabels.
+type labelsKey struct{}
+
+// WithLabels returns a context whose written documents are labelled with
+// labels. Manifest sources run concurrently on shared Dependencies, so the
+// labels of each source travel with its context rather than with the
+// Dependencies.
+func WithLabels(ctx context.Context, labels map[string]string) context.Context {
+	if len(labels) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, labelsKey{}, labels)
+}
+
+// applyLabels adds the labels of ctx to doc. Labels the document already
+// carries are kept.
+func applyLabels(ctx context.Context, doc *domain.Document) {
+	labels, _ := ctx.Value(labelsKey{}).(map[string]string)
+	if len(labels) == 0 {
+		return
+	}
+	merged := maps.Clone(labels)
+	maps.Copy(merged, doc.Labels)
+	doc.Labels = merged
+}
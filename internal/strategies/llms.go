@@ -34,7 +34,7 @@ func NewLLMSStrategy(deps *Dependencies) *LLMSStrategy {
 		deps:            deps,
 		fetcher:         deps.Fetcher,
 		converter:       deps.Converter,
-		markdownReader:  converter.NewMarkdownReader(),
+		markdownReader:  deps.Converter.MarkdownReader(),
 		plainTextReader: converter.NewPlainTextReader(),
 		writer:          deps.Writer,
 		logger:          deps.Logger,
@@ -46,6 +46,12 @@ func (s *LLMSStrategy) Name() string {
 	return "llms"
 }
 
+// SetFetcher replaces the fetcher the strategy requests pages with, such as
+// a client for an authenticated proxy (see domain.Fetcher).
+func (s *LLMSStrategy) SetFetcher(f domain.Fetcher) {
+	s.fetcher = f
+}
+
 // CanHandle returns true if this strategy can handle the given URL
 func (s *LLMSStrategy) CanHandle(url string) bool {
 	// Only handle HTTP/HTTPS URLs
@@ -59,7 +65,8 @@ func (s *LLMSStrategy) CanHandle(url string) bool {
 
 // Execute runs the LLMS extraction strategy
 func (s *LLMSStrategy) Execute(ctx context.Context, url string, opts Options) (*domain.StrategyResult, error) {
-	result := domain.NewStrategyResult(s.Name(), url)
+	result := s.deps.NewResult(s.Name(), url)
+	s.converter = converterFor(s.converter, opts)
 	err := s.execute(ctx, url, opts, result)
 	result.Finish()
 	return result, err
@@ -135,46 +142,57 @@ func (s *LLMSStrategy) execute(ctx context.Context, url string, opts Options, re
 	result.AddAttempted(len(links))
 
 	// Create progress bar
-	bar := utils.NewProgressBar(len(links), utils.DescExtracting)
+	bar := newProgressBar(opts, len(links), utils.DescExtracting)
 
 	// Process links concurrently
 	errors := utils.ParallelForEach(ctx, links, opts.Concurrency, func(ctx context.Context, link domain.LLMSLink) error {
 		defer bar.Add(1)
 
 		// Check if already exists
+		s.deps.MarkSeen(link.URL)
 		if !opts.Force && s.writer.Exists(link.URL) {
 			result.IncSkipped()
 			return nil
 		}
 
+		if !s.deps.TakeHostSlot(link.URL) {
+			result.IncSkipped()
+			return nil
+		}
+
 		// Fetch page
 		pageResp, err := s.fetcher.Get(ctx, link.URL)
 		if err != nil {
-			result.IncFailed()
+			result.FailDocument(link.URL, err)
+			if s.deps.RecordFailure(result, link.URL, err) {
+				s.logger.Debug().Err(err).Str("url", link.URL).Msg("Fetch failed transiently, queued for retry")
+				return nil
+			}
 			s.logger.Warn().Err(err).Str("url", link.URL).Msg("Failed to fetch page")
 			return nil // Continue with other pages
 		}
 
+		// Documents are written under the URL a redirect ended at.
+		pageURL := pageResp.PageURL()
 		var doc *domain.Document
-		if converter.IsMarkdownContent(pageResp.ContentType, link.URL) {
-			doc, err = s.markdownReader.Read(string(pageResp.Body), link.URL)
+		if converter.IsMarkdownContent(pageResp.ContentType, pageURL) {
+			doc, err = s.markdownReader.Read(string(pageResp.Body), pageURL)
 			if err != nil {
-				result.IncFailed()
+				result.FailDocument(link.URL, err)
 				s.logger.Warn().Err(err).Str("url", link.URL).Msg("Failed to read markdown")
 				return nil
 			}
-		} else if converter.IsPlainTextContent(pageResp.ContentType, link.URL) {
-			doc, err = s.plainTextReader.Read(string(pageResp.Body), link.URL)
+		} else if converter.IsPlainTextContent(pageResp.ContentType, pageURL) {
+			doc, err = s.plainTextReader.Read(string(pageResp.Body), pageURL)
 			if err != nil {
-				result.IncFailed()
+				result.FailDocument(link.URL, err)
 				s.logger.Warn().Err(err).Str("url", link.URL).Msg("Failed to read plain text")
 				return nil
 			}
 		} else {
-			doc, err = s.converter.Convert(ctx, string(pageResp.Body), link.URL)
+			doc, err = s.converter.Convert(ctx, string(pageResp.Body), pageURL)
 			if err != nil {
-				result.IncFailed()
-				s.logger.Warn().Err(err).Str("url", link.URL).Msg("Failed to convert page")
+				s.deps.RecordConvertError(result, link.URL, err)
 				return nil
 			}
 		}
@@ -191,17 +209,20 @@ func (s *LLMSStrategy) execute(ctx context.Context, url string, opts Options, re
 		if doc.Description == "" && link.Description != "" {
 			doc.Description = link.Description
 		}
+		if s.deps.SkipUnchanged(result, doc) {
+			return nil
+		}
 
 		if !opts.DryRun {
 			if s.deps != nil {
 				if err := s.deps.WriteDocument(ctx, doc); err != nil {
-					result.IncFailed()
+					result.FailDocument(link.URL, err)
 					s.logger.Warn().Err(err).Str("url", link.URL).Msg("Failed to write document")
 					return nil
 				}
 			} else {
 				if err := s.writer.Write(ctx, doc); err != nil {
-					result.IncFailed()
+					result.FailDocument(link.URL, err)
 					s.logger.Warn().Err(err).Str("url", link.URL).Msg("Failed to write document")
 					return nil
 				}
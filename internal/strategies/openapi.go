@@ -0,0 +1,275 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/output"
+	"github.com/quantmind-br/repodocs/internal/utils"
+)
+
+// openAPIProbePaths are the locations, relative to the site root, where
+// APIs commonly publish their OpenAPI spec, most common first.
+var openAPIProbePaths = []string{
+	"/openapi.json",
+	"/openapi.yaml",
+	"/openapi.yml",
+	"/swagger.json",
+	"/swagger.yaml",
+	"/v3/api-docs",
+	"/v2/api-docs",
+	"/swagger/v1/swagger.json",
+	"/api-docs",
+	"/api/openapi.json",
+	"/docs/openapi.json",
+}
+
+// OpenAPIStrategy renders an OpenAPI or Swagger spec into markdown
+type OpenAPIStrategy struct {
+	deps    *Dependencies
+	fetcher domain.Fetcher
+	writer  *output.Writer
+	logger  *utils.Logger
+}
+
+// NewOpenAPIStrategy creates a new OpenAPI strategy
+func NewOpenAPIStrategy(deps *Dependencies) *OpenAPIStrategy {
+	if deps == nil {
+		return &OpenAPIStrategy{}
+	}
+	return &OpenAPIStrategy{
+		deps:    deps,
+		fetcher: deps.Fetcher,
+		writer:  deps.Writer,
+		logger:  deps.Logger,
+	}
+}
+
+// Name returns the strategy name
+func (s *OpenAPIStrategy) Name() string {
+	return "openapi"
+}
+
+// CanHandle returns true for URLs of OpenAPI or Swagger spec files
+func (s *OpenAPIStrategy) CanHandle(url string) bool {
+	return IsOpenAPIURL(url)
+}
+
+// SetFetcher replaces the fetcher the strategy requests pages with, such as
+// a client for an authenticated proxy (see domain.Fetcher).
+func (s *OpenAPIStrategy) SetFetcher(f domain.Fetcher) {
+	s.fetcher = f
+}
+
+// IsOpenAPIURL reports whether rawURL points at a spec file named like
+// openapi.json, swagger.yaml or openapi.v2.yml.
+func IsOpenAPIURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	base := strings.ToLower(path.Base(u.Path))
+	switch path.Ext(base) {
+	case ".json", ".yaml", ".yml":
+	default:
+		return false
+	}
+	return strings.HasPrefix(base, "openapi") || strings.HasPrefix(base, "swagger")
+}
+
+// Execute fetches the spec at url, or discovers one under common paths of
+// its site, and writes an overview, one page per tag and a schemas page.
+func (s *OpenAPIStrategy) Execute(ctx context.Context, url string, opts Options) (*domain.StrategyResult, error) {
+	result := s.deps.NewResult(s.Name(), url)
+	err := s.execute(ctx, url, opts, result)
+	result.Finish()
+	return result, err
+}
+
+func (s *OpenAPIStrategy) execute(ctx context.Context, url string, opts Options, result *domain.StrategyResult) error {
+	if s.fetcher == nil {
+		return fmt.Errorf("openapi strategy fetcher is nil")
+	}
+	if s.writer == nil {
+		return fmt.Errorf("openapi strategy writer is nil")
+	}
+
+	s.logger.Info().Str("url", url).Msg("Starting OpenAPI extraction")
+
+	specURL, spec, err := s.loadSpec(ctx, url)
+	if err != nil {
+		// Discovery-phase failures (no document attempted yet) are reported via
+		// the returned error; do not inflate the DocsFailed counter.
+		return err
+	}
+
+	s.logger.Info().
+		Str("spec", specURL).
+		Str("title", spec.Info.Title).
+		Int("paths", len(spec.Paths)).
+		Msg("Parsed OpenAPI spec")
+
+	docs := s.buildDocuments(specURL, spec)
+	result.AddDiscovered(len(docs))
+
+	if opts.Limit > 0 && len(docs) > opts.Limit {
+		docs = docs[:opts.Limit]
+		s.logger.Info().Int("limit", opts.Limit).Msg("Applied page limit")
+	}
+
+	result.AddAttempted(len(docs))
+
+	for _, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.deps.MarkSeen(doc.URL)
+		if !opts.Force && s.writer.Exists(doc.URL) {
+			result.IncSkipped()
+			continue
+		}
+		if s.deps.SkipUnchanged(result, doc) {
+			continue
+		}
+		if opts.DryRun {
+			continue
+		}
+		if err := s.deps.WriteDocument(ctx, doc); err != nil {
+			result.FailDocument(doc.URL, err)
+			s.logger.Warn().Err(err).Str("url", doc.URL).Msg("Failed to write document")
+			continue
+		}
+		result.IncWritten()
+		result.AddBytesWritten(int64(len(doc.Content)))
+	}
+
+	s.logger.Info().Int("pages", len(docs)).Msg("OpenAPI extraction completed")
+	return nil
+}
+
+// loadSpec fetches and parses the spec at rawURL. When rawURL is not itself
+// a spec, the common spec locations of its site are probed in order.
+func (s *OpenAPIStrategy) loadSpec(ctx context.Context, rawURL string) (string, *OpenAPISpec, error) {
+	spec, err := s.fetchSpec(ctx, rawURL)
+	if err == nil {
+		return rawURL, spec, nil
+	}
+	if IsOpenAPIURL(rawURL) {
+		return "", nil, err
+	}
+
+	for _, candidate := range openAPIProbeURLs(rawURL) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", nil, ctxErr
+		}
+		spec, probeErr := s.fetchSpec(ctx, candidate)
+		if probeErr != nil {
+			s.logger.Debug().Err(probeErr).Str("url", candidate).Msg("No OpenAPI spec at probe path")
+			continue
+		}
+		s.logger.Info().Str("url", candidate).Msg("Discovered OpenAPI spec")
+		return candidate, spec, nil
+	}
+	return "", nil, fmt.Errorf("no OpenAPI spec found at %s or its common spec paths: %w", rawURL, err)
+}
+
+func (s *OpenAPIStrategy) fetchSpec(ctx context.Context, specURL string) (*OpenAPISpec, error) {
+	resp, err := s.fetcher.Get(ctx, specURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseOpenAPISpec(resp.Body)
+}
+
+// openAPIProbeURLs returns the probe locations under the URL's own path,
+// then under the site root.
+func openAPIProbeURLs(rawURL string) []string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	origin := u.Scheme + "://" + u.Host
+	prefixes := []string{origin}
+	if dir := strings.TrimRight(u.Path, "/"); dir != "" {
+		prefixes = append([]string{origin + dir}, prefixes...)
+	}
+
+	var urls []string
+	for _, prefix := range prefixes {
+		for _, p := range openAPIProbePaths {
+			urls = append(urls, prefix+p)
+		}
+	}
+	return urls
+}
+
+// buildDocuments renders the spec into its pages, which share the spec URL
+// without its extension as their base: the overview at the base itself,
+// tags under base/tags/ and schemas at base/schemas.
+func (s *OpenAPIStrategy) buildDocuments(specURL string, spec *OpenAPISpec) []*domain.Document {
+	base := openAPIDocBase(specURL)
+	renderer := NewOpenAPIRenderer(spec)
+	title := renderer.Title()
+	now := time.Now()
+
+	newDoc := func(docURL, docTitle, description, content string, tags ...string) *domain.Document {
+		return &domain.Document{
+			URL:            docURL,
+			Title:          docTitle,
+			Description:    description,
+			Content:        content,
+			SourceStrategy: s.Name(),
+			FetchedAt:      now,
+			WordCount:      len(strings.Fields(content)),
+			CharCount:      len(content),
+			Tags:           append([]string{"openapi"}, tags...),
+		}
+	}
+
+	docs := []*domain.Document{
+		newDoc(base, title, oneLine(spec.Info.Description), renderer.RenderOverview()),
+	}
+	slugs := make(map[string]bool)
+	for i, tag := range renderer.Tags() {
+		slug := utils.Slugify(tag.Name)
+		if slug == "" || slugs[slug] {
+			slug = strings.TrimPrefix(fmt.Sprintf("%s-%d", slug, i+1), "-")
+		}
+		slugs[slug] = true
+		docs = append(docs, newDoc(
+			base+"/tags/"+slug,
+			title+" - "+tag.Name,
+			oneLine(tag.Description),
+			renderer.RenderTag(tag),
+			tag.Name,
+		))
+	}
+	if schemas := renderer.RenderSchemas(); schemas != "" {
+		docs = append(docs, newDoc(base+"/schemas", title+" - Schemas", "", schemas, "schemas"))
+	}
+	return docs
+}
+
+// openAPIDocBase returns specURL without query, fragment and file extension
+func openAPIDocBase(specURL string) string {
+	u, err := url.Parse(specURL)
+	if err != nil {
+		return specURL
+	}
+	u.RawQuery = ""
+	u.Fragment = ""
+	switch ext := path.Ext(u.Path); strings.ToLower(ext) {
+	case ".json", ".yaml", ".yml":
+		u.Path = strings.TrimSuffix(u.Path, ext)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+	if u.Path == "" {
+		u.Path = "/openapi"
+	}
+	return u.String()
+}
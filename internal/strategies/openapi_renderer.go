@@ -0,0 +1,508 @@
+package strategies
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// openAPIDefaultTag groups operations that have no tag
+const openAPIDefaultTag = "default"
+
+// OpenAPIRenderer renders an OpenAPI spec into markdown pages: an overview,
+// one page per tag with its operations, and one page of schemas.
+type OpenAPIRenderer struct {
+	spec *OpenAPISpec
+}
+
+// NewOpenAPIRenderer creates a renderer for spec
+func NewOpenAPIRenderer(spec *OpenAPISpec) *OpenAPIRenderer {
+	return &OpenAPIRenderer{spec: spec}
+}
+
+// openAPIEndpoint is one operation together with its path
+type openAPIEndpoint struct {
+	Path      string
+	Method    string
+	Operation *OpenAPIOperation
+	Shared    []*OpenAPIParameter
+}
+
+// Tags returns the tags that have operations, in the order the spec
+// declares them followed by undeclared tags alphabetically.
+func (r *OpenAPIRenderer) Tags() []OpenAPITag {
+	grouped := r.endpointsByTag()
+	var tags []OpenAPITag
+	seen := make(map[string]bool)
+	for _, tag := range r.spec.Tags {
+		if len(grouped[tag.Name]) > 0 && !seen[tag.Name] {
+			tags = append(tags, tag)
+			seen[tag.Name] = true
+		}
+	}
+	var rest []string
+	for name := range grouped {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		tags = append(tags, OpenAPITag{Name: name})
+	}
+	return tags
+}
+
+// endpoints returns every operation ordered by path, then method
+func (r *OpenAPIRenderer) endpoints() []openAPIEndpoint {
+	paths := make([]string, 0, len(r.spec.Paths))
+	for path, item := range r.spec.Paths {
+		if item != nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var endpoints []openAPIEndpoint
+	for _, path := range paths {
+		item := r.spec.Paths[path]
+		for _, mo := range item.Operations() {
+			endpoints = append(endpoints, openAPIEndpoint{
+				Path:      path,
+				Method:    mo.Method,
+				Operation: mo.Operation,
+				Shared:    item.Parameters,
+			})
+		}
+	}
+	return endpoints
+}
+
+// endpointsByTag groups operations under their first tag
+func (r *OpenAPIRenderer) endpointsByTag() map[string][]openAPIEndpoint {
+	grouped := make(map[string][]openAPIEndpoint)
+	for _, ep := range r.endpoints() {
+		tag := openAPIDefaultTag
+		if len(ep.Operation.Tags) > 0 && ep.Operation.Tags[0] != "" {
+			tag = ep.Operation.Tags[0]
+		}
+		grouped[tag] = append(grouped[tag], ep)
+	}
+	return grouped
+}
+
+// RenderOverview renders the API description, servers, authentication
+// schemes and an index of all endpoints.
+func (r *OpenAPIRenderer) RenderOverview() string {
+	var sb strings.Builder
+	info := r.spec.Info
+	sb.WriteString("# " + r.Title() + "\n\n")
+	if info.Version != "" {
+		sb.WriteString("**Version:** " + info.Version + "\n\n")
+	}
+	if info.Description != "" {
+		sb.WriteString(strings.TrimSpace(info.Description) + "\n\n")
+	}
+
+	if len(r.spec.Servers) > 0 {
+		sb.WriteString("## Servers\n\n")
+		for _, server := range r.spec.Servers {
+			sb.WriteString("- `" + server.URL + "`")
+			if server.Description != "" {
+				sb.WriteString(" - " + oneLine(server.Description))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if schemes := r.spec.Components.SecuritySchemes; len(schemes) > 0 {
+		sb.WriteString("## Authentication\n\n")
+		for _, name := range sortedKeys(schemes) {
+			scheme := schemes[name]
+			if scheme == nil {
+				continue
+			}
+			sb.WriteString("- **" + name + "**: " + describeSecurity(scheme))
+			if scheme.Description != "" {
+				sb.WriteString(" - " + oneLine(scheme.Description))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if endpoints := r.endpoints(); len(endpoints) > 0 {
+		sb.WriteString("## Endpoints\n\n")
+		sb.WriteString("| Method | Path | Summary |\n|---|---|---|\n")
+		for _, ep := range endpoints {
+			fmt.Fprintf(&sb, "| %s | `%s` | %s |\n", ep.Method, ep.Path, tableCell(ep.Operation.Summary))
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// Title returns the API title, or a generic title when the spec has none
+func (r *OpenAPIRenderer) Title() string {
+	if r.spec.Info.Title != "" {
+		return r.spec.Info.Title
+	}
+	return "API Reference"
+}
+
+// RenderTag renders every operation grouped under tag
+func (r *OpenAPIRenderer) RenderTag(tag OpenAPITag) string {
+	var sb strings.Builder
+	sb.WriteString("# " + tag.Name + "\n\n")
+	if tag.Description != "" {
+		sb.WriteString(strings.TrimSpace(tag.Description) + "\n\n")
+	}
+	for _, ep := range r.endpointsByTag()[tag.Name] {
+		r.renderOperation(&sb, ep)
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func (r *OpenAPIRenderer) renderOperation(sb *strings.Builder, ep openAPIEndpoint) {
+	op := ep.Operation
+	fmt.Fprintf(sb, "## %s %s\n\n", ep.Method, ep.Path)
+	if op.Deprecated {
+		sb.WriteString("> **Deprecated.**\n\n")
+	}
+	if op.Summary != "" {
+		sb.WriteString("**" + oneLine(op.Summary) + "**\n\n")
+	}
+	if op.Description != "" {
+		sb.WriteString(strings.TrimSpace(op.Description) + "\n\n")
+	}
+	if op.OperationID != "" {
+		sb.WriteString("Operation ID: `" + op.OperationID + "`\n\n")
+	}
+
+	if params := r.operationParameters(ep); len(params) > 0 {
+		sb.WriteString("### Parameters\n\n")
+		sb.WriteString("| Name | In | Type | Required | Description |\n|---|---|---|---|---|\n")
+		for _, param := range params {
+			desc := param.Description
+			if param.Deprecated {
+				desc = strings.TrimSpace("Deprecated. " + desc)
+			}
+			if param.Schema != nil {
+				desc = appendEnum(desc, param.Schema.Enum)
+			}
+			fmt.Fprintf(sb, "| `%s` | %s | %s | %s | %s |\n",
+				param.Name, param.In, tableCell(schemaTypeName(param.Schema)), yesNo(param.Required), tableCell(desc))
+		}
+		sb.WriteString("\n")
+	}
+
+	if body := r.spec.resolveRequestBody(op.RequestBody); body != nil {
+		sb.WriteString("### Request body\n\n")
+		if body.Required {
+			sb.WriteString("Required.")
+			if body.Description != "" {
+				sb.WriteString(" ")
+			}
+		}
+		if body.Description != "" {
+			sb.WriteString(strings.TrimSpace(body.Description))
+		}
+		if body.Required || body.Description != "" {
+			sb.WriteString("\n\n")
+		}
+		renderContent(sb, body.Content, "")
+	}
+
+	if len(op.Responses) > 0 {
+		sb.WriteString("### Responses\n\n")
+		sb.WriteString("| Status | Description | Body |\n|---|---|---|\n")
+		statuses := sortedKeys(op.Responses)
+		for _, status := range statuses {
+			resp := r.spec.resolveResponse(op.Responses[status])
+			if resp == nil {
+				continue
+			}
+			fmt.Fprintf(sb, "| `%s` | %s | %s |\n", status, tableCell(resp.Description), tableCell(contentSummary(resp.Content)))
+		}
+		sb.WriteString("\n")
+		for _, status := range statuses {
+			if resp := r.spec.resolveResponse(op.Responses[status]); resp != nil {
+				renderContent(sb, resp.Content, status)
+			}
+		}
+	}
+}
+
+// operationParameters merges path-level and operation parameters; an
+// operation parameter overrides a path parameter with the same name and
+// location.
+func (r *OpenAPIRenderer) operationParameters(ep openAPIEndpoint) []*OpenAPIParameter {
+	var params []*OpenAPIParameter
+	index := make(map[string]int)
+	for _, list := range [][]*OpenAPIParameter{ep.Shared, ep.Operation.Parameters} {
+		for _, param := range list {
+			param = r.spec.resolveParameter(param)
+			if param == nil || param.Name == "" {
+				continue
+			}
+			key := param.In + "\x00" + param.Name
+			if i, ok := index[key]; ok {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// renderContent renders the schema of each content type with inline object
+// properties and examples. status labels response examples.
+func renderContent(sb *strings.Builder, content map[string]*OpenAPIMediaType, status string) {
+	for _, ct := range sortedKeys(content) {
+		mt := content[ct]
+		if mt == nil {
+			continue
+		}
+		if status == "" {
+			fmt.Fprintf(sb, "`%s`: %s\n\n", ct, schemaTypeName(mt.Schema))
+			if mt.Schema != nil && mt.Schema.Ref == "" && len(mt.Schema.Properties) > 0 {
+				renderProperties(sb, mt.Schema)
+			}
+		}
+
+		example, label := mediaTypeExample(mt)
+		if example == nil {
+			continue
+		}
+		heading := "Example"
+		if status != "" {
+			heading = "Example `" + status + "` response"
+		}
+		if label != "" {
+			heading += " (" + oneLine(label) + ")"
+		}
+		fmt.Fprintf(sb, "%s, `%s`:\n\n", heading, ct)
+		renderExample(sb, example)
+	}
+}
+
+// mediaTypeExample returns the first example of a media type and its label
+func mediaTypeExample(mt *OpenAPIMediaType) (any, string) {
+	if mt.Example != nil {
+		return mt.Example, ""
+	}
+	for _, name := range sortedKeys(mt.Examples) {
+		ex := mt.Examples[name]
+		if ex.Value == nil {
+			continue
+		}
+		if ex.Summary != "" {
+			return ex.Value, ex.Summary
+		}
+		return ex.Value, name
+	}
+	if mt.Schema != nil && mt.Schema.Example != nil {
+		return mt.Schema.Example, ""
+	}
+	return nil, ""
+}
+
+// renderExample writes an example value as a fenced block, as JSON unless
+// it is already a string.
+func renderExample(sb *strings.Builder, example any) {
+	if text, ok := example.(string); ok {
+		sb.WriteString("```\n" + strings.TrimRight(text, "\n") + "\n```\n\n")
+		return
+	}
+	data, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		sb.WriteString("```\n" + fmt.Sprint(example) + "\n```\n\n")
+		return
+	}
+	sb.WriteString("```json\n" + string(data) + "\n```\n\n")
+}
+
+// RenderSchemas renders every component schema with its properties
+func (r *OpenAPIRenderer) RenderSchemas() string {
+	schemas := r.spec.Components.Schemas
+	if len(schemas) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("# Schemas\n\n")
+	for _, name := range sortedKeys(schemas) {
+		schema := schemas[name]
+		if schema == nil {
+			continue
+		}
+		sb.WriteString("## " + name + "\n\n")
+		if schema.Deprecated {
+			sb.WriteString("> **Deprecated.**\n\n")
+		}
+		if schema.Description != "" {
+			sb.WriteString(strings.TrimSpace(schema.Description) + "\n\n")
+		}
+		if len(schema.Properties) == 0 || schema.Ref != "" || len(schema.AllOf) > 0 {
+			sb.WriteString("Type: " + schemaTypeName(schema) + "\n\n")
+		}
+		if len(schema.Enum) > 0 {
+			sb.WriteString(appendEnum("", schema.Enum) + "\n\n")
+		}
+		if len(schema.Properties) > 0 {
+			renderProperties(&sb, schema)
+		}
+		if schema.Example != nil {
+			sb.WriteString("Example:\n\n")
+			renderExample(&sb, schema.Example)
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// renderProperties writes a table of an object schema's properties
+func renderProperties(sb *strings.Builder, schema *OpenAPISchema) {
+	sb.WriteString("| Property | Type | Required | Description |\n|---|---|---|---|\n")
+	for _, name := range sortedKeys(schema.Properties) {
+		prop := schema.Properties[name]
+		if prop == nil {
+			continue
+		}
+		desc := prop.Description
+		if prop.Deprecated {
+			desc = strings.TrimSpace("Deprecated. " + desc)
+		}
+		desc = appendEnum(desc, prop.Enum)
+		required := slices.Contains(schema.Required, name)
+		fmt.Fprintf(sb, "| `%s` | %s | %s | %s |\n", name, tableCell(schemaTypeName(prop)), yesNo(required), tableCell(desc))
+	}
+	sb.WriteString("\n")
+}
+
+// schemaTypeName describes a schema's type in one line; referenced schemas
+// are named rather than expanded, so cyclic schemas render finitely.
+func schemaTypeName(schema *OpenAPISchema) string {
+	if schema == nil {
+		return ""
+	}
+	if schema.Ref != "" {
+		return "`" + openAPIRefName(schema.Ref) + "`"
+	}
+	for _, composite := range []struct {
+		parts []*OpenAPISchema
+		sep   string
+	}{{schema.AllOf, " & "}, {schema.OneOf, " | "}, {schema.AnyOf, " | "}} {
+		if len(composite.parts) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(composite.parts))
+		for _, part := range composite.parts {
+			if name := schemaTypeName(part); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, composite.sep)
+	}
+
+	nullable := schema.Nullable
+	var types []string
+	for _, typ := range schema.Type {
+		if typ == "null" {
+			nullable = true
+			continue
+		}
+		types = append(types, typ)
+	}
+
+	var name string
+	switch {
+	case schema.Type.Has("array"):
+		name = "array"
+		if item := schemaTypeName(schema.Items); item != "" {
+			name = "array of " + item
+		}
+	case len(types) == 0 && len(schema.Properties) > 0:
+		name = "object"
+	case len(types) == 0 && schema.AdditionalProperties != nil:
+		name = "map"
+	default:
+		name = strings.Join(types, " | ")
+		if schema.Format != "" {
+			name = strings.TrimSpace(name + " (" + schema.Format + ")")
+		}
+	}
+	if nullable && name != "" {
+		name += " | null"
+	}
+	return name
+}
+
+// contentSummary lists each content type of a body with its schema type
+func contentSummary(content map[string]*OpenAPIMediaType) string {
+	var parts []string
+	for _, ct := range sortedKeys(content) {
+		part := "`" + ct + "`"
+		if mt := content[ct]; mt != nil && mt.Schema != nil {
+			part += ": " + schemaTypeName(mt.Schema)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeSecurity summarizes an authentication scheme
+func describeSecurity(scheme *OpenAPISecurity) string {
+	switch scheme.Type {
+	case "apiKey":
+		return fmt.Sprintf("API key in %s `%s`", scheme.In, scheme.Name)
+	case "http":
+		return "HTTP " + scheme.Scheme
+	case "basic":
+		return "HTTP basic"
+	default:
+		return scheme.Type
+	}
+}
+
+// appendEnum appends the allowed values of an enum to desc
+func appendEnum(desc string, enum []any) string {
+	if len(enum) == 0 {
+		return desc
+	}
+	values := make([]string, len(enum))
+	for i, v := range enum {
+		values[i] = fmt.Sprintf("`%v`", v)
+	}
+	return strings.TrimSpace(desc + " One of: " + strings.Join(values, ", ") + ".")
+}
+
+// tableCell makes text safe inside a markdown table cell
+func tableCell(text string) string {
+	return strings.ReplaceAll(oneLine(text), "|", "\\|")
+}
+
+// oneLine collapses text onto a single line
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
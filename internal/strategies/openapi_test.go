@@ -0,0 +1,256 @@
+package strategies
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstoreSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Petstore", "version": "1.2.0", "description": "Manage pets."},
+  "servers": [{"url": "https://api.example.com/v1"}],
+  "tags": [{"name": "pets", "description": "Everything about pets"}],
+  "paths": {
+    "/pets/{petId}": {
+      "parameters": [{"$ref": "#/components/parameters/PetId"}],
+      "get": {
+        "tags": ["pets"],
+        "summary": "Get a pet",
+        "operationId": "getPet",
+        "responses": {
+          "200": {"description": "The pet", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}, "example": {"id": 1, "name": "Rex"}}}},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/pets": {
+      "post": {
+        "tags": ["pets"],
+        "summary": "Create a pet",
+        "requestBody": {"$ref": "#/components/requestBodies/NewPet"},
+        "responses": {"201": {"description": "Created"}}
+      },
+      "get": {
+        "tags": ["pets"],
+        "summary": "List pets",
+        "parameters": [{"name": "limit", "in": "query", "schema": {"type": "integer", "format": "int32"}, "description": "Page size | max 100"}],
+        "responses": {"200": {"description": "Pets", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}}}
+      }
+    },
+    "/health": {"get": {"summary": "Health check", "deprecated": true, "responses": {"200": {"description": "OK"}}}}
+  },
+  "components": {
+    "parameters": {"PetId": {"name": "petId", "in": "path", "required": true, "schema": {"type": "string"}}},
+    "requestBodies": {"NewPet": {"required": true, "content": {"application/json": {"schema": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "tag": {"type": ["string", "null"]}}}}}}},
+    "responses": {"NotFound": {"description": "Not found"}},
+    "schemas": {
+      "Pet": {"type": "object", "required": ["id", "name"], "properties": {"id": {"type": "integer", "format": "int64"}, "name": {"type": "string"}, "status": {"type": "string", "enum": ["available", "sold"]}, "owner": {"$ref": "#/components/schemas/Pet"}}}
+    },
+    "securitySchemes": {"apiKey": {"type": "apiKey", "in": "header", "name": "X-API-Key"}}
+  }
+}`
+
+func TestIsOpenAPIURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"https://api.example.com/openapi.json", true},
+		{"https://example.com/docs/swagger.yaml", true},
+		{"https://example.com/openapi.v2.yml?raw=1", true},
+		{"HTTPS://EXAMPLE.COM/OPENAPI.JSON", true},
+		{"https://example.com/openapi", false},
+		{"https://example.com/data.json", false},
+		{"file:///tmp/openapi.json", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsOpenAPIURL(tt.url))
+			assert.Equal(t, tt.expected, NewOpenAPIStrategy(nil).CanHandle(tt.url))
+		})
+	}
+}
+
+func TestParseOpenAPISpec_NotASpec(t *testing.T) {
+	for _, body := range []string{`<html><body>Swagger UI</body></html>`, `{"name": "package"}`} {
+		_, err := ParseOpenAPISpec([]byte(body))
+		assert.Error(t, err, body)
+	}
+}
+
+func TestParseOpenAPISpec_Swagger2(t *testing.T) {
+	spec, err := ParseOpenAPISpec([]byte(`
+swagger: "2.0"
+info: {title: Legacy, version: "1"}
+host: legacy.example.com
+basePath: /api
+schemes: [https]
+paths:
+  /users:
+    post:
+      tags: [users]
+      parameters:
+        - {name: body, in: body, required: true, schema: {$ref: "#/definitions/User"}}
+        - {name: dryRun, in: query, type: boolean}
+      responses:
+        200:
+          description: Created user
+          schema: {$ref: "#/definitions/User"}
+  /avatars:
+    post:
+      consumes: [multipart/form-data]
+      parameters:
+        - {name: file, in: formData, type: file, required: true, description: Image}
+      responses:
+        204: {description: Uploaded}
+definitions:
+  User:
+    type: object
+    properties:
+      email: {type: string, format: email}
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []OpenAPIServer{{URL: "https://legacy.example.com/api"}}, spec.Servers)
+	require.Contains(t, spec.Components.Schemas, "User")
+
+	post := spec.Paths["/users"].Post
+	require.Len(t, post.Parameters, 1)
+	assert.Equal(t, "dryRun", post.Parameters[0].Name)
+	assert.Equal(t, "boolean", schemaTypeName(post.Parameters[0].Schema))
+	require.NotNil(t, post.RequestBody)
+	assert.True(t, post.RequestBody.Required)
+	assert.Equal(t, "`User`", schemaTypeName(post.RequestBody.Content["application/json"].Schema))
+	require.Contains(t, post.Responses, "200")
+	assert.Equal(t, "`User`", schemaTypeName(post.Responses["200"].Content["application/json"].Schema))
+
+	upload := spec.Paths["/avatars"].Post
+	require.NotNil(t, upload.RequestBody)
+	form := upload.RequestBody.Content["multipart/form-data"].Schema
+	require.NotNil(t, form)
+	assert.Equal(t, []string{"file"}, form.Required)
+	assert.Equal(t, "file", schemaTypeName(form.Properties["file"]))
+}
+
+func TestOpenAPIRenderer(t *testing.T) {
+	spec, err := ParseOpenAPISpec([]byte(petstoreSpec))
+	require.NoError(t, err)
+	r := NewOpenAPIRenderer(spec)
+
+	assert.Equal(t, []OpenAPITag{{Name: "pets", Description: "Everything about pets"}, {Name: "default"}}, r.Tags())
+
+	overview := r.RenderOverview()
+	assert.Contains(t, overview, "# Petstore\n\n**Version:** 1.2.0\n\nManage pets.")
+	assert.Contains(t, overview, "- `https://api.example.com/v1`")
+	assert.Contains(t, overview, "- **apiKey**: API key in header `X-API-Key`")
+	assert.Contains(t, overview, "| GET | `/health` | Health check |\n| GET | `/pets` | List pets |\n| POST | `/pets` | Create a pet |")
+
+	pets := r.RenderTag(r.Tags()[0])
+	assert.Contains(t, pets, "# pets\n\nEverything about pets")
+	assert.Contains(t, pets, "| `limit` | query | integer (int32) | no | Page size \\| max 100 |")
+	assert.Contains(t, pets, "| `petId` | path | string | yes |  |")
+	assert.Contains(t, pets, "Operation ID: `getPet`")
+	assert.Contains(t, pets, "### Request body\n\nRequired.\n\n`application/json`: object")
+	assert.Contains(t, pets, "| `tag` | string \\| null | no |  |")
+	assert.Contains(t, pets, "| `200` | Pets | `application/json`: array of `Pet` |")
+	assert.Contains(t, pets, "| `404` | Not found |  |")
+	assert.Contains(t, pets, "Example `200` response, `application/json`:\n\n```json\n{\n  \"id\": 1,\n  \"name\": \"Rex\"\n}\n```")
+	assert.Less(t, strings.Index(pets, "## GET /pets\n"), strings.Index(pets, "## POST /pets\n"))
+
+	health := r.RenderTag(r.Tags()[1])
+	assert.Contains(t, health, "## GET /health\n\n> **Deprecated.**")
+
+	schemas := r.RenderSchemas()
+	assert.Contains(t, schemas, "## Pet\n\n| Property | Type | Required | Description |")
+	assert.Contains(t, schemas, "| `id` | integer (int64) | yes |  |")
+	assert.Contains(t, schemas, "| `owner` | `Pet` | no |  |")
+	assert.Contains(t, schemas, "| `status` | string | no | One of: `available`, `sold`. |")
+}
+
+func TestOpenAPIStrategy_Execute_DiscoversSpec(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><body><div id="swagger-ui"></div></body></html>`))
+		case "/v3/api-docs":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(petstoreSpec))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:     5 * time.Second,
+		Concurrency: 1,
+		OutputDir:   tmpDir,
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	result, err := NewOpenAPIStrategy(deps).Execute(context.Background(), server.URL+"/", Options{})
+	require.NoError(t, err)
+
+	snap := result.Snapshot()
+	assert.Equal(t, 4, snap.URLsDiscovered)
+	assert.Equal(t, 4, snap.DocsWritten)
+	assert.NotContains(t, requests, "/v2/api-docs", "probing stops at the first spec found")
+
+	for _, name := range []string{"v3/api-docs.md", "v3/api-docs/tags/pets.md", "v3/api-docs/tags/default.md", "v3/api-docs/schemas.md"} {
+		_, err := os.Stat(filepath.Join(tmpDir, name))
+		assert.NoError(t, err, name)
+	}
+	pets, err := os.ReadFile(filepath.Join(tmpDir, "v3/api-docs/tags/pets.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(pets), "## GET /pets/{petId}")
+}
+
+func TestOpenAPIStrategy_Execute_Limit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(petstoreSpec))
+	}))
+	defer server.Close()
+
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:   5 * time.Second,
+		OutputDir: t.TempDir(),
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	opts := Options{CommonOptions: domain.CommonOptions{Limit: 2}}
+	result, err := NewOpenAPIStrategy(deps).Execute(context.Background(), server.URL+"/openapi.json", opts)
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Snapshot().URLsDiscovered)
+	assert.Equal(t, 2, result.Snapshot().DocsWritten)
+}
+
+func TestOpenAPIStrategy_Execute_NoSpec(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:   5 * time.Second,
+		OutputDir: t.TempDir(),
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	_, err = NewOpenAPIStrategy(deps).Execute(context.Background(), server.URL+"/openapi.json", Options{})
+	assert.Error(t, err)
+}
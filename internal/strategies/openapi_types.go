@@ -0,0 +1,409 @@
+package strategies
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPISpec represents an OpenAPI 3.x document. Swagger 2.0 documents are
+// parsed into the same shape by ParseOpenAPISpec.
+type OpenAPISpec struct {
+	OpenAPI    string                      `yaml:"openapi"`
+	Swagger    string                      `yaml:"swagger"`
+	Info       OpenAPIInfo                 `yaml:"info"`
+	Servers    []OpenAPIServer             `yaml:"servers"`
+	Tags       []OpenAPITag                `yaml:"tags"`
+	Paths      map[string]*OpenAPIPathItem `yaml:"paths"`
+	Components OpenAPIComponents           `yaml:"components"`
+
+	// Swagger 2.0 fields, folded into the 3.x fields above.
+	Host                string                       `yaml:"host"`
+	BasePath            string                       `yaml:"basePath"`
+	Schemes             []string                     `yaml:"schemes"`
+	Consumes            []string                     `yaml:"consumes"`
+	Produces            []string                     `yaml:"produces"`
+	Definitions         map[string]*OpenAPISchema    `yaml:"definitions"`
+	Parameters          map[string]*OpenAPIParameter `yaml:"parameters"`
+	Responses           map[string]*OpenAPIResponse  `yaml:"responses"`
+	SecurityDefinitions map[string]*OpenAPISecurity  `yaml:"securityDefinitions"`
+}
+
+// OpenAPIInfo holds the API title, version and description
+type OpenAPIInfo struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+}
+
+// OpenAPIServer is a base URL the API is served from
+type OpenAPIServer struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+}
+
+// OpenAPITag groups operations
+type OpenAPITag struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+// OpenAPIComponents holds the reusable objects operations refer to
+type OpenAPIComponents struct {
+	Schemas         map[string]*OpenAPISchema      `yaml:"schemas"`
+	Parameters      map[string]*OpenAPIParameter   `yaml:"parameters"`
+	RequestBodies   map[string]*OpenAPIRequestBody `yaml:"requestBodies"`
+	Responses       map[string]*OpenAPIResponse    `yaml:"responses"`
+	SecuritySchemes map[string]*OpenAPISecurity    `yaml:"securitySchemes"`
+}
+
+// OpenAPIPathItem holds the operations of one path
+type OpenAPIPathItem struct {
+	Parameters []*OpenAPIParameter `yaml:"parameters"`
+	Get        *OpenAPIOperation   `yaml:"get"`
+	Post       *OpenAPIOperation   `yaml:"post"`
+	Put        *OpenAPIOperation   `yaml:"put"`
+	Patch      *OpenAPIOperation   `yaml:"patch"`
+	Delete     *OpenAPIOperation   `yaml:"delete"`
+	Head       *OpenAPIOperation   `yaml:"head"`
+	Options    *OpenAPIOperation   `yaml:"options"`
+	Trace      *OpenAPIOperation   `yaml:"trace"`
+}
+
+// Operations returns the operations of the path keyed by upper-case HTTP
+// method, in conventional method order.
+func (p *OpenAPIPathItem) Operations() []OpenAPIMethodOperation {
+	var ops []OpenAPIMethodOperation
+	for _, candidate := range []OpenAPIMethodOperation{
+		{"GET", p.Get}, {"POST", p.Post}, {"PUT", p.Put}, {"PATCH", p.Patch},
+		{"DELETE", p.Delete}, {"HEAD", p.Head}, {"OPTIONS", p.Options}, {"TRACE", p.Trace},
+	} {
+		if candidate.Operation != nil {
+			ops = append(ops, candidate)
+		}
+	}
+	return ops
+}
+
+// OpenAPIMethodOperation pairs an operation with its HTTP method
+type OpenAPIMethodOperation struct {
+	Method    string
+	Operation *OpenAPIOperation
+}
+
+// OpenAPIOperation describes a single API operation
+type OpenAPIOperation struct {
+	Summary     string                      `yaml:"summary"`
+	Description string                      `yaml:"description"`
+	OperationID string                      `yaml:"operationId"`
+	Tags        []string                    `yaml:"tags"`
+	Deprecated  bool                        `yaml:"deprecated"`
+	Parameters  []*OpenAPIParameter         `yaml:"parameters"`
+	RequestBody *OpenAPIRequestBody         `yaml:"requestBody"`
+	Responses   map[string]*OpenAPIResponse `yaml:"responses"`
+
+	// Swagger 2.0 fields, folded into RequestBody and Responses.
+	Consumes []string `yaml:"consumes"`
+	Produces []string `yaml:"produces"`
+}
+
+// OpenAPIParameter describes a path, query, header or cookie parameter
+type OpenAPIParameter struct {
+	Ref         string         `yaml:"$ref"`
+	Name        string         `yaml:"name"`
+	In          string         `yaml:"in"`
+	Description string         `yaml:"description"`
+	Required    bool           `yaml:"required"`
+	Deprecated  bool           `yaml:"deprecated"`
+	Schema      *OpenAPISchema `yaml:"schema"`
+	Example     any            `yaml:"example"`
+
+	// Swagger 2.0 fields, folded into Schema.
+	Type   OpenAPITypes   `yaml:"type"`
+	Format string         `yaml:"format"`
+	Items  *OpenAPISchema `yaml:"items"`
+	Enum   []any          `yaml:"enum"`
+}
+
+// OpenAPIRequestBody describes the body an operation accepts
+type OpenAPIRequestBody struct {
+	Ref         string                       `yaml:"$ref"`
+	Description string                       `yaml:"description"`
+	Required    bool                         `yaml:"required"`
+	Content     map[string]*OpenAPIMediaType `yaml:"content"`
+}
+
+// OpenAPIResponse describes one response of an operation
+type OpenAPIResponse struct {
+	Ref         string                       `yaml:"$ref"`
+	Description string                       `yaml:"description"`
+	Content     map[string]*OpenAPIMediaType `yaml:"content"`
+
+	// Swagger 2.0 fields, folded into Content.
+	Schema   *OpenAPISchema `yaml:"schema"`
+	Examples map[string]any `yaml:"examples"`
+}
+
+// OpenAPIMediaType describes a body in one content type
+type OpenAPIMediaType struct {
+	Schema   *OpenAPISchema            `yaml:"schema"`
+	Example  any                       `yaml:"example"`
+	Examples map[string]OpenAPIExample `yaml:"examples"`
+}
+
+// OpenAPIExample is a named example value
+type OpenAPIExample struct {
+	Summary string `yaml:"summary"`
+	Value   any    `yaml:"value"`
+}
+
+// OpenAPISecurity describes an authentication scheme
+type OpenAPISecurity struct {
+	Type        string `yaml:"type"`
+	Description string `yaml:"description"`
+	Name        string `yaml:"name"`
+	In          string `yaml:"in"`
+	Scheme      string `yaml:"scheme"`
+}
+
+// OpenAPISchema describes a data type. Only the keywords rendered into
+// markdown are decoded.
+type OpenAPISchema struct {
+	Ref                  string                    `yaml:"$ref"`
+	Type                 OpenAPITypes              `yaml:"type"`
+	Format               string                    `yaml:"format"`
+	Title                string                    `yaml:"title"`
+	Description          string                    `yaml:"description"`
+	Properties           map[string]*OpenAPISchema `yaml:"properties"`
+	Required             []string                  `yaml:"required"`
+	Items                *OpenAPISchema            `yaml:"items"`
+	AdditionalProperties any                       `yaml:"additionalProperties"`
+	Enum                 []any                     `yaml:"enum"`
+	AllOf                []*OpenAPISchema          `yaml:"allOf"`
+	OneOf                []*OpenAPISchema          `yaml:"oneOf"`
+	AnyOf                []*OpenAPISchema          `yaml:"anyOf"`
+	Nullable             bool                      `yaml:"nullable"`
+	Deprecated           bool                      `yaml:"deprecated"`
+	Default              any                       `yaml:"default"`
+	Example              any                       `yaml:"example"`
+}
+
+// OpenAPITypes holds a schema's type, which OpenAPI 3.1 allows to be a list
+type OpenAPITypes []string
+
+// UnmarshalYAML accepts both a single type and a list of types
+func (t *OpenAPITypes) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*t = OpenAPITypes{node.Value}
+		return nil
+	case yaml.SequenceNode:
+		var types []string
+		if err := node.Decode(&types); err != nil {
+			return err
+		}
+		*t = types
+		return nil
+	default:
+		return fmt.Errorf("invalid schema type at line %d", node.Line)
+	}
+}
+
+// Has reports whether t includes name
+func (t OpenAPITypes) Has(name string) bool {
+	return slices.Contains(t, name)
+}
+
+// ParseOpenAPISpec parses a JSON or YAML OpenAPI 3.x or Swagger 2.0
+// document. Swagger 2.0 documents are converted to the 3.x layout.
+func ParseOpenAPISpec(data []byte) (*OpenAPISpec, error) {
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	switch {
+	case strings.HasPrefix(spec.OpenAPI, "3."):
+	case strings.HasPrefix(spec.Swagger, "2."):
+		spec.convertSwagger2()
+	default:
+		return nil, fmt.Errorf("not an OpenAPI 3.x or Swagger 2.0 document")
+	}
+	return &spec, nil
+}
+
+// convertSwagger2 folds the Swagger 2.0 fields into their 3.x equivalents
+func (s *OpenAPISpec) convertSwagger2() {
+	if s.Host != "" {
+		schemes := s.Schemes
+		if len(schemes) == 0 {
+			schemes = []string{"https"}
+		}
+		for _, scheme := range schemes {
+			s.Servers = append(s.Servers, OpenAPIServer{URL: scheme + "://" + s.Host + s.BasePath})
+		}
+	} else if s.BasePath != "" {
+		s.Servers = append(s.Servers, OpenAPIServer{URL: s.BasePath})
+	}
+
+	s.Components.Schemas = s.Definitions
+	s.Components.SecuritySchemes = s.SecurityDefinitions
+	s.Components.Parameters = s.Parameters
+	for _, param := range s.Parameters {
+		convertSwagger2Parameter(param)
+	}
+	s.Components.Responses = s.Responses
+	for _, resp := range s.Responses {
+		convertSwagger2Response(resp, s.Produces)
+	}
+
+	for _, item := range s.Paths {
+		if item == nil {
+			continue
+		}
+		for _, param := range item.Parameters {
+			convertSwagger2Parameter(param)
+		}
+		for _, mo := range item.Operations() {
+			s.convertSwagger2Operation(mo.Operation)
+		}
+	}
+}
+
+// convertSwagger2Operation moves body and formData parameters into a request
+// body and response schemas into response content.
+func (s *OpenAPISpec) convertSwagger2Operation(op *OpenAPIOperation) {
+	consumes := firstNonEmpty(op.Consumes, s.Consumes, []string{"application/json"})
+	produces := firstNonEmpty(op.Produces, s.Produces, []string{"application/json"})
+
+	var params []*OpenAPIParameter
+	var form *OpenAPISchema
+	for _, param := range op.Parameters {
+		param = s.resolveParameter(param)
+		if param == nil {
+			continue
+		}
+		switch param.In {
+		case "body":
+			op.RequestBody = &OpenAPIRequestBody{
+				Description: param.Description,
+				Required:    param.Required,
+				Content:     map[string]*OpenAPIMediaType{consumes[0]: {Schema: param.Schema}},
+			}
+		case "formData":
+			convertSwagger2Parameter(param)
+			if form == nil {
+				form = &OpenAPISchema{Type: OpenAPITypes{"object"}, Properties: map[string]*OpenAPISchema{}}
+			}
+			prop := *param.Schema
+			prop.Description = param.Description
+			form.Properties[param.Name] = &prop
+			if param.Required {
+				form.Required = append(form.Required, param.Name)
+			}
+		default:
+			convertSwagger2Parameter(param)
+			params = append(params, param)
+		}
+	}
+	op.Parameters = params
+	if form != nil && op.RequestBody == nil {
+		mediaType := "application/x-www-form-urlencoded"
+		for _, ct := range consumes {
+			if ct == "multipart/form-data" {
+				mediaType = ct
+			}
+		}
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: len(form.Required) > 0,
+			Content:  map[string]*OpenAPIMediaType{mediaType: {Schema: form}},
+		}
+	}
+
+	for _, resp := range op.Responses {
+		convertSwagger2Response(resp, produces)
+	}
+}
+
+// convertSwagger2Parameter builds a schema from a non-body parameter's
+// inline type.
+func convertSwagger2Parameter(param *OpenAPIParameter) {
+	if param == nil || param.Schema != nil || param.Ref != "" {
+		return
+	}
+	param.Schema = &OpenAPISchema{
+		Type:   param.Type,
+		Format: param.Format,
+		Items:  param.Items,
+		Enum:   param.Enum,
+	}
+}
+
+// convertSwagger2Response moves a response schema and its per-content-type
+// examples into response content.
+func convertSwagger2Response(resp *OpenAPIResponse, produces []string) {
+	if resp == nil || resp.Ref != "" || resp.Content != nil {
+		return
+	}
+	if resp.Schema == nil && len(resp.Examples) == 0 {
+		return
+	}
+	mediaType := "application/json"
+	if len(produces) > 0 {
+		mediaType = produces[0]
+	}
+	resp.Content = map[string]*OpenAPIMediaType{mediaType: {Schema: resp.Schema}}
+	for ct, example := range resp.Examples {
+		mt, ok := resp.Content[ct]
+		if !ok {
+			mt = &OpenAPIMediaType{Schema: resp.Schema}
+			resp.Content[ct] = mt
+		}
+		mt.Example = example
+	}
+}
+
+// firstNonEmpty returns the first non-empty list
+func firstNonEmpty(lists ...[]string) []string {
+	for _, list := range lists {
+		if len(list) > 0 {
+			return list
+		}
+	}
+	return nil
+}
+
+// openAPIRefName returns the component name a local $ref points to, e.g.
+// "Pet" for "#/components/schemas/Pet".
+func openAPIRefName(ref string) string {
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+}
+
+// resolveParameter follows a parameter $ref into the components
+func (s *OpenAPISpec) resolveParameter(param *OpenAPIParameter) *OpenAPIParameter {
+	for depth := 0; param != nil && param.Ref != "" && depth < maxOpenAPIRefDepth; depth++ {
+		param = s.Components.Parameters[openAPIRefName(param.Ref)]
+	}
+	return param
+}
+
+// resolveRequestBody follows a request body $ref into the components
+func (s *OpenAPISpec) resolveRequestBody(body *OpenAPIRequestBody) *OpenAPIRequestBody {
+	for depth := 0; body != nil && body.Ref != "" && depth < maxOpenAPIRefDepth; depth++ {
+		body = s.Components.RequestBodies[openAPIRefName(body.Ref)]
+	}
+	return body
+}
+
+// resolveResponse follows a response $ref into the components
+func (s *OpenAPISpec) resolveResponse(resp *OpenAPIResponse) *OpenAPIResponse {
+	for depth := 0; resp != nil && resp.Ref != "" && depth < maxOpenAPIRefDepth; depth++ {
+		resp = s.Components.Responses[openAPIRefName(resp.Ref)]
+	}
+	return resp
+}
+
+// maxOpenAPIRefDepth bounds $ref chains so cyclic references terminate
+const maxOpenAPIRefDepth = 8
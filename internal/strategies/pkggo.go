@@ -3,6 +3,8 @@ package strategies
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -41,7 +43,8 @@ func (s *PkgGoStrategy) Name() string {
 	return "pkggo"
 }
 
-// SetFetcher allows setting a custom fetcher (used for testing)
+// SetFetcher replaces the fetcher the strategy requests pages with, such as
+// a client for an authenticated proxy (see domain.Fetcher).
 func (s *PkgGoStrategy) SetFetcher(f domain.Fetcher) {
 	s.fetcher = f
 }
@@ -51,9 +54,30 @@ func (s *PkgGoStrategy) CanHandle(url string) bool {
 	return strings.Contains(url, "pkg.go.dev")
 }
 
+// pkgGoPathPattern matches a Go import path with an optional @version.
+var pkgGoPathPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~+-]*(/[A-Za-z0-9._~+-]+)*(@[A-Za-z0-9._+-]+)?$`)
+
+// Validate rejects pkg.go.dev URLs that do not name a package, such as the
+// home or search pages.
+func (s *PkgGoStrategy) Validate(_ context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return domain.NewValidationError("url", fmt.Sprintf("invalid pkg.go.dev URL %q: %v", rawURL, err))
+	}
+	path := strings.Trim(u.Path, "/")
+	if path == "" || path == "search" {
+		return domain.NewValidationError("url", "missing package path: expected https://pkg.go.dev/{import path}, e.g. https://pkg.go.dev/net/http")
+	}
+	if !pkgGoPathPattern.MatchString(path) {
+		return domain.NewValidationError("url", fmt.Sprintf("invalid pkg.go.dev package path %q: expected an import path such as net/http or github.com/owner/repo", path))
+	}
+	return nil
+}
+
 // Execute runs the pkg.go.dev extraction strategy
 func (s *PkgGoStrategy) Execute(ctx context.Context, url string, opts Options) (*domain.StrategyResult, error) {
-	result := domain.NewStrategyResult(s.Name(), url)
+	result := s.deps.NewResult(s.Name(), url)
+	s.converter = converterFor(s.converter, opts)
 	err := s.execute(ctx, url, opts, result)
 	result.Finish()
 	return result, err
@@ -85,14 +109,14 @@ func (s *PkgGoStrategy) execute(ctx context.Context, url string, opts Options, r
 	// Fetch page
 	resp, err := s.fetcher.Get(ctx, url)
 	if err != nil {
-		result.IncFailed()
+		result.FailDocument(url, err)
 		return err
 	}
 
 	// Parse HTML
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(resp.Body)))
 	if err != nil {
-		result.IncFailed()
+		result.FailDocument(url, err)
 		return err
 	}
 
@@ -121,7 +145,7 @@ func (s *PkgGoStrategy) execute(ctx context.Context, url string, opts Options, r
 
 	contentHTML, err := content.Html()
 	if err != nil {
-		result.IncFailed()
+		result.FailDocument(url, err)
 		return err
 	}
 
@@ -131,8 +155,9 @@ func (s *PkgGoStrategy) execute(ctx context.Context, url string, opts Options, r
 	// Convert to document
 	document, err := s.converter.Convert(ctx, contentHTML, url)
 	if err != nil {
-		result.IncFailed()
-		return err
+		result.FailDocument(url, err)
+		s.logger.Warn().Err(err).Str("url", url).Msg("Failed to convert page")
+		return nil
 	}
 
 	// Set metadata
@@ -140,20 +165,25 @@ func (s *PkgGoStrategy) execute(ctx context.Context, url string, opts Options, r
 	document.SourceStrategy = s.Name()
 	document.CacheHit = resp.FromCache
 	document.FetchedAt = time.Now()
+	if s.deps.SkipUnchanged(result, document) {
+		return nil
+	}
 
 	if !opts.DryRun {
 		if s.deps != nil {
 			if err := s.deps.WriteDocument(ctx, document); err != nil {
-				result.IncFailed()
-				return err
+				result.FailDocument(url, err)
+				s.logger.Warn().Err(err).Str("url", url).Msg("Failed to write document")
+				return nil
 			}
 			result.IncWritten()
 			result.AddBytesWritten(int64(len(document.Content)))
 			return nil
 		}
 		if err := s.writer.Write(ctx, document); err != nil {
-			result.IncFailed()
-			return err
+			result.FailDocument(url, err)
+			s.logger.Warn().Err(err).Str("url", url).Msg("Failed to write document")
+			return nil
 		}
 		result.IncWritten()
 		result.AddBytesWritten(int64(len(document.Content)))
@@ -214,7 +244,7 @@ func (s *PkgGoStrategy) extractSections(ctx context.Context, doc *goquery.Docume
 		// Get section HTML
 		sectionHTML, err := content.Html()
 		if err != nil {
-			result.IncFailed()
+			result.FailDocument(baseURL+section.selector, err)
 			continue
 		}
 
@@ -229,7 +259,7 @@ func (s *PkgGoStrategy) extractSections(ctx context.Context, doc *goquery.Docume
 		// Convert to document
 		document, err := s.converter.Convert(ctx, sectionHTML, sectionURL)
 		if err != nil {
-			result.IncFailed()
+			result.FailDocument(sectionURL, err)
 			s.logger.Warn().Err(err).Str("section", section.name).Msg("Failed to convert section")
 			continue
 		}
@@ -238,17 +268,20 @@ func (s *PkgGoStrategy) extractSections(ctx context.Context, doc *goquery.Docume
 		document.Title = packageName + " - " + section.name
 		document.SourceStrategy = s.Name()
 		document.FetchedAt = time.Now()
+		if s.deps.SkipUnchanged(result, document) {
+			continue
+		}
 
 		if !opts.DryRun {
 			if s.deps != nil {
 				if err := s.deps.WriteDocument(ctx, document); err != nil {
-					result.IncFailed()
+					result.FailDocument(sectionURL, err)
 					s.logger.Warn().Err(err).Str("section", section.name).Msg("Failed to write section")
 					continue
 				}
 			} else {
 				if err := s.writer.Write(ctx, document); err != nil {
-					result.IncFailed()
+					result.FailDocument(sectionURL, err)
 					s.logger.Warn().Err(err).Str("section", section.name).Msg("Failed to write section")
 					continue
 				}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -530,3 +532,57 @@ func TestPkgGoStrategy_Execute_MissingPackageTitle(t *testing.T) {
 	_, err = strategy.Execute(ctx, server.URL+"/github.com/example/test-package", opts)
 	assert.NoError(t, err)
 }
+
+// TestPkgGoStrategy_Execute_ExcludeSelector tests that exclude selectors strip
+// navigation chrome before conversion
+func TestPkgGoStrategy_Execute_ExcludeSelector(t *testing.T) {
+	htmlContent := `
+<!DOCTYPE html>
+<html>
+<head><title>test/package - pkg.go.dev</title></head>
+<body>
+	<h1 class="UnitHeader-title">github.com/example/test-package</h1>
+	<div class="Documentation-content">
+		<nav><a href="/pkg">Jump to index</a></nav>
+		<div class="sidebar">Related packages</div>
+		<h2>Overview</h2>
+		<p>This is a test package.</p>
+	</div>
+</body>
+</html>
+`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(htmlContent))
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:        5 * time.Second,
+		EnableCache:    false,
+		EnableRenderer: false,
+		Concurrency:    1,
+		OutputDir:      tmpDir,
+		Flat:           true,
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	strategy := NewPkgGoStrategy(deps)
+	_, err = strategy.Execute(context.Background(), server.URL+"/github.com/example/test-package", Options{
+		ExcludeSelector: "nav, .sidebar",
+	})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(tmpDir, "*.md"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "This is a test package.")
+	assert.NotContains(t, string(content), "Jump to index")
+	assert.NotContains(t, string(content), "Related packages")
+}
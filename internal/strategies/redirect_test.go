@@ -0,0 +1,137 @@
+package strategies
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCrossHostServers starts a server whose /away page redirects to a second
+// server addressed as localhost, and /moved to /new on the same host. The
+// counter reports requests to the second server.
+func newCrossHostServers(t *testing.T, page func(w http.ResponseWriter, r *http.Request)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var otherHits atomic.Int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherHits.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><body><h1>Elsewhere</h1><p>Another site.</p></body></html>`))
+	}))
+	t.Cleanup(other.Close)
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/away":
+			http.Redirect(w, r, otherURL+"/docs", http.StatusFound)
+		default:
+			page(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &otherHits
+}
+
+func TestCrawlerStrategy_Execute_DropsCrossHostRedirects(t *testing.T) {
+	server, otherHits := newCrossHostServers(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<html><body><h1>Docs</h1><a href="/moved">Moved</a><a href="/away">Away</a></body></html>`))
+		case "/new":
+			w.Write([]byte(`<html><body><h1>New</h1><p>The page moved here.</p></body></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:              5 * time.Second,
+		Concurrency:          1,
+		OutputDir:            t.TempDir(),
+		Flat:                 true,
+		MaxRedirects:         10,
+		NoCrossHostRedirects: true,
+		CommonOptions: domain.CommonOptions{
+			DryRun: true,
+		},
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	result, err := NewCrawlerStrategy(deps).Execute(context.Background(), server.URL+"/", Options{
+		CommonOptions: domain.CommonOptions{DryRun: true},
+		Concurrency:   1,
+		MaxDepth:      2,
+	})
+	require.NoError(t, err)
+	snap := result.Snapshot()
+	require.Len(t, snap.Failures, 1)
+	assert.Equal(t, server.URL+"/away", snap.Failures[0].URL)
+	assert.Contains(t, snap.Failures[0].Error, "cross-host redirect not followed")
+	assert.Zero(t, otherHits.Load())
+}
+
+func TestSitemapStrategy_Execute_WritesRedirectedPagesAtFinalURL(t *testing.T) {
+	var server *httptest.Server
+	server, otherHits := newCrossHostServers(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sitemap.xml":
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+<url><loc>` + server.URL + `/moved</loc></url>
+<url><loc>` + server.URL + `/away</loc></url>
+</urlset>`))
+		case "/new":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head><title>New</title></head><body><article><h1>New</h1>
+<p>The page moved here and is documented under its new address.</p></article></body></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	outputDir := t.TempDir()
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:              5 * time.Second,
+		Concurrency:          1,
+		OutputDir:            outputDir,
+		Flat:                 true,
+		MaxRedirects:         10,
+		NoCrossHostRedirects: true,
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	result, err := NewSitemapStrategy(deps).Execute(context.Background(), server.URL+"/sitemap.xml", Options{
+		Concurrency: 1,
+	})
+	require.NoError(t, err)
+	snap := result.Snapshot()
+	assert.Equal(t, 1, snap.DocsWritten)
+	require.Len(t, snap.Failures, 1)
+	assert.Equal(t, server.URL+"/away", snap.Failures[0].URL)
+	assert.Zero(t, otherHits.Load())
+
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Contains(t, names, "new.md")
+	assert.NoFileExists(t, filepath.Join(outputDir, "moved.md"))
+}
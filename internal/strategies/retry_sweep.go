@@ -0,0 +1,233 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/quantmind-br/repodocs/internal/converter"
+	"github.com/quantmind-br/repodocs/internal/domain"
+)
+
+// maxPageAttempts bounds how often one page is tried across the main pass and
+// the end-of-run sweep. Each attempt already includes the fetcher's inline
+// retries with backoff.
+const maxPageAttempts = 3
+
+// maxSweepWait caps how long the sweep honors a Retry-After hint before
+// fetching a throttled page again.
+const maxSweepWait = time.Minute
+
+// FailedPage is a page whose fetch failed with a retryable error.
+type FailedPage struct {
+	URL      string
+	Attempts int
+	Err      error
+}
+
+// retryQueue collects pages that failed transiently during the main pass,
+// grouped by the StrategyResult of the run they belong to so parallel runs
+// sharing one Dependencies (manifest sources) each sweep only their own pages.
+type retryQueue struct {
+	mu     sync.Mutex
+	byRun  map[*domain.StrategyResult][]*FailedPage
+	byPage map[*domain.StrategyResult]map[string]*FailedPage
+}
+
+func (q *retryQueue) add(run *domain.StrategyResult, pageURL string, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.byRun == nil {
+		q.byRun = make(map[*domain.StrategyResult][]*FailedPage)
+		q.byPage = make(map[*domain.StrategyResult]map[string]*FailedPage)
+	}
+	if page, ok := q.byPage[run][pageURL]; ok {
+		page.Attempts++
+		page.Err = err
+		return
+	}
+	page := &FailedPage{URL: pageURL, Attempts: 1, Err: err}
+	if q.byPage[run] == nil {
+		q.byPage[run] = make(map[string]*FailedPage)
+	}
+	q.byPage[run][pageURL] = page
+	q.byRun[run] = append(q.byRun[run], page)
+}
+
+func (q *retryQueue) drain(run *domain.StrategyResult) []*FailedPage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := q.byRun[run]
+	delete(q.byRun, run)
+	delete(q.byPage, run)
+	return pending
+}
+
+// RecordFailure queues pageURL, which failed during the run reported by
+// result, for the end-of-run retry sweep when err is retryable (throttling,
+// gateway errors, timeouts). It reports whether the page was queued;
+// permanent errors are left to the caller to report.
+func (d *Dependencies) RecordFailure(result *domain.StrategyResult, pageURL string, err error) bool {
+	if d == nil || !domain.IsRetryable(err) {
+		return false
+	}
+	d.retries.add(result, pageURL, err)
+	return true
+}
+
+// RetryFailedPages runs a bounded sweep over the pages queued by
+// RecordFailure for result. Each page is fetched again through the fetcher (and so its
+// retry policy) at most until it has had maxPageAttempts attempts. Recovered
+// pages are converted and written, and result is updated to move them from
+// failed to written. It returns the pages that still failed.
+func (d *Dependencies) RetryFailedPages(ctx context.Context, opts Options, result *domain.StrategyResult) []FailedPage {
+	if d == nil {
+		return nil
+	}
+	pending := d.retries.drain(result)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	d.Logger.Info().Int("pages", len(pending)).Msg("Retrying transiently failed pages")
+
+	var permanent []FailedPage
+	recovered := 0
+	for len(pending) > 0 {
+		var next []*FailedPage
+		for _, page := range pending {
+			if ctx.Err() != nil || page.Attempts >= maxPageAttempts {
+				permanent = append(permanent, *page)
+				continue
+			}
+			if err := sleepCtx(ctx, retryWait(page.Err)); err != nil {
+				permanent = append(permanent, *page)
+				continue
+			}
+
+			page.Attempts++
+			doc, err := d.fetchDocument(ctx, page.URL, opts)
+			if err != nil {
+				page.Err = err
+				if domain.IsRetryable(err) {
+					next = append(next, page)
+				} else {
+					permanent = append(permanent, *page)
+				}
+				continue
+			}
+
+			result.ClearFailure(page.URL)
+			recovered++
+			d.Logger.Debug().Str("url", page.URL).Int("attempts", page.Attempts).Msg("Recovered page in retry sweep")
+
+			doc.SourceStrategy = result.Snapshot().Strategy
+			if d.SkipUnchanged(result, doc) {
+				continue
+			}
+			if opts.DryRun {
+				continue
+			}
+			if err := d.WriteDocument(ctx, doc); err != nil {
+				result.FailDocument(page.URL, err)
+				d.Logger.Warn().Err(err).Str("url", page.URL).Msg("Failed to write document")
+				continue
+			}
+			result.IncWritten()
+			result.AddBytesWritten(int64(len(doc.Content)))
+		}
+		pending = next
+	}
+
+	for _, page := range permanent {
+		d.Logger.Warn().Err(page.Err).Str("url", page.URL).Int("attempts", page.Attempts).
+			Msg("Page failed permanently")
+	}
+	d.Logger.Info().Int("recovered", recovered).Int("failed", len(permanent)).Msg("Retry sweep completed")
+
+	return permanent
+}
+
+// fetchDocument fetches and converts a single page, rendering it with
+// JavaScript when required. Pages of hosts known to need rendering skip the
+// static fetch.
+func (d *Dependencies) fetchDocument(ctx context.Context, pageURL string, opts Options) (*domain.Document, error) {
+	if html, ok := d.renderKnownJSPage(ctx, pageURL); ok {
+		doc, err := d.Converter.Convert(ctx, html, pageURL)
+		if err != nil {
+			return nil, err
+		}
+		doc.RenderedWithJS = true
+		doc.FetchedAt = time.Now()
+		return doc, nil
+	}
+
+	resp, err := d.Fetcher.Get(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc *domain.Document
+	switch {
+	case converter.IsMarkdownContent(resp.ContentType, pageURL):
+		doc, err = d.Converter.MarkdownReader().Read(string(resp.Body), pageURL)
+	case converter.IsPlainTextContent(resp.ContentType, pageURL):
+		doc, err = converter.NewPlainTextReader().Read(string(resp.Body), pageURL)
+	default:
+		html := string(resp.Body)
+		renderedWithJS := false
+		if opts.RenderJS || d.NeedsJSRendering(pageURL, html) {
+			if r, rerr := d.GetRenderer(); rerr == nil {
+				rendered, rerr := r.Render(ctx, pageURL, domain.RenderOptions{
+					Timeout:     60 * time.Second,
+					WaitStable:  2 * time.Second,
+					ScrollToEnd: true,
+				})
+				if rerr == nil {
+					html = rendered
+					renderedWithJS = true
+				}
+			}
+		}
+		doc, err = d.Converter.Convert(ctx, html, pageURL)
+		if err == nil {
+			doc.RenderedWithJS = renderedWithJS
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc.CacheHit = resp.FromCache
+	doc.FetchedAt = time.Now()
+	return doc, nil
+}
+
+// retryWait returns the Retry-After delay carried by err, capped at
+// maxSweepWait.
+func retryWait(err error) time.Duration {
+	var retryable *domain.RetryableError
+	if !errors.As(err, &retryable) || retryable.RetryAfter <= 0 {
+		return 0
+	}
+	wait := time.Duration(retryable.RetryAfter) * time.Second
+	if wait > maxSweepWait {
+		wait = maxSweepWait
+	}
+	return wait
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/converter"
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/quantmind-br/repodocs/internal/output"
+	"github.com/quantmind-br/repodocs/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedFetcher returns queued errors for a URL before serving its page.
+type scriptedFetcher struct {
+	mu     sync.Mutex
+	errs   map[string][]error
+	bodies map[string]string
+	calls  map[string]int
+}
+
+func (f *scriptedFetcher) Get(_ context.Context, url string) (*domain.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[url]++
+	if errs := f.errs[url]; len(errs) > 0 {
+		f.errs[url] = errs[1:]
+		return nil, errs[0]
+	}
+	return &domain.Response{StatusCode: 200, Body: []byte(f.bodies[url]), ContentType: "text/markdown", URL: url}, nil
+}
+
+func (f *scriptedFetcher) GetWithHeaders(ctx context.Context, url string, _ map[string]string) (*domain.Response, error) {
+	return f.Get(ctx, url)
+}
+func (f *scriptedFetcher) GetCookies(string) []*http.Cookie { return nil }
+func (f *scriptedFetcher) Transport() http.RoundTripper     { return http.DefaultTransport }
+func (f *scriptedFetcher) Close() error                     { return nil }
+
+func throttled(url string) error {
+	return &domain.RetryableError{Err: &domain.FetchError{URL: url, StatusCode: 429, Err: errors.New("HTTP 429")}}
+}
+
+func TestDependencies_RecordFailure(t *testing.T) {
+	deps := &Dependencies{}
+	run := domain.NewStrategyResult("crawler", "https://a.example.com")
+	other := domain.NewStrategyResult("crawler", "https://b.example.com")
+
+	assert.True(t, deps.RecordFailure(run, "https://a.example.com/x", throttled("https://a.example.com/x")))
+	assert.False(t, deps.RecordFailure(run, "https://a.example.com/y", &domain.FetchError{StatusCode: 404}),
+		"permanent errors are not queued")
+	deps.RecordFailure(run, "https://a.example.com/x", throttled("https://a.example.com/x"))
+	deps.RecordFailure(other, "https://b.example.com/x", throttled("https://b.example.com/x"))
+
+	pending := deps.retries.drain(run)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 2, pending[0].Attempts)
+	assert.Len(t, deps.retries.drain(other), 1, "runs are queued separately")
+
+	var none *Dependencies
+	assert.False(t, none.RecordFailure(run, "https://a.example.com/x", throttled("https://a.example.com/x")))
+}
+
+func TestDependencies_RetryFailedPages(t *testing.T) {
+	recoverURL := "https://docs.example.com/recover.md"
+	stuckURL := "https://docs.example.com/stuck.md"
+	fetcher := &scriptedFetcher{
+		errs: map[string][]error{
+			recoverURL: {throttled(recoverURL)},
+			stuckURL:   {throttled(stuckURL), throttled(stuckURL), throttled(stuckURL)},
+		},
+		bodies: map[string]string{recoverURL: "# Recovered\n\nBody text."},
+		calls:  map[string]int{},
+	}
+	dir := t.TempDir()
+	deps := &Dependencies{
+		Fetcher:   fetcher,
+		Converter: converter.NewPipeline(converter.PipelineOptions{}),
+		Writer:    output.NewWriter(output.WriterOptions{BaseDir: dir}),
+		Logger:    utils.NewLogger(utils.LoggerOptions{Level: "error"}),
+	}
+
+	result := domain.NewStrategyResult("sitemap", "https://docs.example.com/sitemap.xml")
+	for _, u := range []string{recoverURL, stuckURL} {
+		_, err := fetcher.Get(context.Background(), u)
+		result.FailDocument(u, err)
+		require.True(t, deps.RecordFailure(result, u, err))
+	}
+
+	permanent := deps.RetryFailedPages(context.Background(), Options{}, result)
+
+	require.Len(t, permanent, 1)
+	assert.Equal(t, stuckURL, permanent[0].URL)
+	assert.Equal(t, maxPageAttempts, permanent[0].Attempts)
+	assert.Equal(t, maxPageAttempts, fetcher.calls[stuckURL], "sweep attempts are bounded")
+
+	snap := result.Snapshot()
+	assert.Equal(t, 1, snap.DocsWritten)
+	assert.Equal(t, 1, snap.DocsFailed)
+	require.Len(t, snap.Failures, 1, "recovered pages are no longer reported as failed")
+	assert.Equal(t, stuckURL, snap.Failures[0].URL)
+
+	data, err := os.ReadFile(deps.Writer.GetPath(recoverURL))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Body text.")
+
+	assert.Empty(t, deps.RetryFailedPages(context.Background(), Options{}, result), "queue is drained")
+}
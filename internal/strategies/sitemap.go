@@ -12,7 +12,6 @@ import (
 	"github.com/quantmind-br/repodocs/internal/converter"
 	"github.com/quantmind-br/repodocs/internal/domain"
 	"github.com/quantmind-br/repodocs/internal/output"
-	"github.com/quantmind-br/repodocs/internal/renderer"
 	"github.com/quantmind-br/repodocs/internal/utils"
 )
 
@@ -39,7 +38,7 @@ func NewSitemapStrategy(deps *Dependencies) *SitemapStrategy {
 		fetcher:        deps.Fetcher,
 		renderer:       deps.Renderer,
 		converter:      deps.Converter,
-		markdownReader: converter.NewMarkdownReader(),
+		markdownReader: deps.Converter.MarkdownReader(),
 		writer:         deps.Writer,
 		logger:         deps.Logger,
 	}
@@ -50,7 +49,8 @@ func (s *SitemapStrategy) Name() string {
 	return "sitemap"
 }
 
-// SetFetcher allows setting a custom fetcher (used for testing)
+// SetFetcher replaces the fetcher the strategy requests pages with, such as
+// a client for an authenticated proxy (see domain.Fetcher).
 func (s *SitemapStrategy) SetFetcher(f domain.Fetcher) {
 	s.fetcher = f
 }
@@ -65,7 +65,8 @@ func (s *SitemapStrategy) CanHandle(url string) bool {
 
 // Execute runs the sitemap extraction strategy
 func (s *SitemapStrategy) Execute(ctx context.Context, url string, opts Options) (*domain.StrategyResult, error) {
-	result := domain.NewStrategyResult(s.Name(), url)
+	result := s.deps.NewResult(s.Name(), url)
+	s.converter = converterFor(s.converter, opts)
 	err := s.execute(ctx, url, opts, result)
 	result.Finish()
 	return result, err
@@ -77,7 +78,7 @@ func (s *SitemapStrategy) execute(ctx context.Context, url string, opts Options,
 	// Fetch sitemap
 	resp, err := s.fetcher.Get(ctx, url)
 	if err != nil {
-		result.IncFailed()
+		result.FailDocument(url, err)
 		return err
 	}
 
@@ -86,7 +87,7 @@ func (s *SitemapStrategy) execute(ctx context.Context, url string, opts Options,
 	if strings.HasSuffix(strings.ToLower(url), ".gz") {
 		content, err = decompressGzip(resp.Body)
 		if err != nil {
-			result.IncFailed()
+			result.FailDocument(url, err)
 			return err
 		}
 	}
@@ -94,7 +95,7 @@ func (s *SitemapStrategy) execute(ctx context.Context, url string, opts Options,
 	// Parse sitemap
 	sitemap, err := parseSitemap(content, url)
 	if err != nil {
-		result.IncFailed()
+		result.FailDocument(url, err)
 		return err
 	}
 
@@ -261,71 +262,78 @@ func (s *SitemapStrategy) collectURLsFromSitemap(ctx context.Context, url string
 
 func (s *SitemapStrategy) processURLs(ctx context.Context, urls []domain.SitemapURL, opts Options, result *domain.StrategyResult) error {
 	result.AddAttempted(len(urls))
-	bar := utils.NewProgressBar(len(urls), utils.DescExtracting)
+	bar := newProgressBar(opts, len(urls), utils.DescExtracting)
 
 	errors := utils.ParallelForEach(ctx, urls, opts.Concurrency, func(ctx context.Context, sitemapURL domain.SitemapURL) error {
 		defer bar.Add(1)
 
+		s.deps.MarkSeen(sitemapURL.Loc)
 		if !opts.Force && s.writer.Exists(sitemapURL.Loc) {
 			result.IncSkipped()
 			return nil
 		}
 
+		if !s.deps.TakeHostSlot(sitemapURL.Loc) {
+			result.IncSkipped()
+			return nil
+		}
+
+		// Hosts already known to need JS rendering skip the static fetch and go
+		// straight to the browser; a failed render falls back to fetching.
+		if html, ok := s.deps.renderKnownJSPage(ctx, sitemapURL.Loc); ok {
+			if s.deps.SkipAuthWall(result, sitemapURL.Loc, "", html) {
+				return nil
+			}
+			doc, err := s.converter.Convert(ctx, html, sitemapURL.Loc)
+			if err != nil {
+				s.deps.RecordConvertError(result, sitemapURL.Loc, err)
+				return nil
+			}
+			doc.RenderedWithJS = true
+			return s.finishDocument(ctx, doc, false, opts, result)
+		}
+
 		pageResp, err := s.fetcher.Get(ctx, sitemapURL.Loc)
 		if err != nil {
-			result.IncFailed()
+			result.FailDocument(sitemapURL.Loc, err)
+			if s.deps.RecordFailure(result, sitemapURL.Loc, err) {
+				s.logger.Debug().Err(err).Str("url", sitemapURL.Loc).Msg("Fetch failed transiently, queued for retry")
+				return nil
+			}
 			s.logger.Warn().Err(err).Str("url", sitemapURL.Loc).Msg("Failed to fetch page")
 			return nil
 		}
 
+		// Documents are written under the URL a redirect ended at.
+		pageURL := pageResp.PageURL()
 		var doc *domain.Document
-		if converter.IsMarkdownContent(pageResp.ContentType, sitemapURL.Loc) {
-			doc, err = s.markdownReader.Read(string(pageResp.Body), sitemapURL.Loc)
+		if converter.IsMarkdownContent(pageResp.ContentType, pageURL) {
+			doc, err = s.markdownReader.Read(string(pageResp.Body), pageURL)
 			if err != nil {
-				result.IncFailed()
+				result.FailDocument(sitemapURL.Loc, err)
 				s.logger.Warn().Err(err).Str("url", sitemapURL.Loc).Msg("Failed to read markdown")
 				return nil
 			}
 		} else {
 			html := string(pageResp.Body)
+			if s.deps.SkipAuthWall(result, sitemapURL.Loc, pageResp.FinalURL, html) {
+				return nil
+			}
 
-			if opts.RenderJS || renderer.NeedsJSRendering(html) {
-				if r, err := s.deps.GetRenderer(); err == nil {
-					s.renderer = r
-					rendered, err := s.renderer.Render(ctx, sitemapURL.Loc, domain.RenderOptions{
-						Timeout:     60 * time.Second,
-						WaitStable:  2 * time.Second,
-						ScrollToEnd: true,
-					})
-					if err == nil {
-						html = rendered
-					}
+			if opts.RenderJS || s.deps.NeedsJSRendering(pageURL, html) {
+				if rendered, err := s.renderPage(ctx, pageURL); err == nil {
+					html = rendered
 				}
 			}
 
-			doc, err = s.converter.Convert(ctx, html, sitemapURL.Loc)
+			doc, err = s.converter.Convert(ctx, html, pageURL)
 			if err != nil {
-				result.IncFailed()
-				s.logger.Warn().Err(err).Str("url", sitemapURL.Loc).Msg("Failed to convert page")
-				return nil
-			}
-		}
-
-		doc.SourceStrategy = s.Name()
-		doc.CacheHit = pageResp.FromCache
-		doc.FetchedAt = time.Now()
-
-		if !opts.DryRun {
-			if err := s.deps.WriteDocument(ctx, doc); err != nil {
-				result.IncFailed()
-				s.logger.Warn().Err(err).Str("url", sitemapURL.Loc).Msg("Failed to write document")
+				s.deps.RecordConvertError(result, sitemapURL.Loc, err)
 				return nil
 			}
-			result.IncWritten()
-			result.AddBytesWritten(int64(len(doc.Content)))
 		}
 
-		return nil
+		return s.finishDocument(ctx, doc, pageResp.FromCache, opts, result)
 	})
 
 	if err := utils.FirstError(errors); err != nil {
@@ -336,6 +344,46 @@ func (s *SitemapStrategy) processURLs(ctx context.Context, urls []domain.Sitemap
 	return nil
 }
 
+// renderPage renders url with the shared browser renderer.
+func (s *SitemapStrategy) renderPage(ctx context.Context, url string) (string, error) {
+	r, err := s.deps.GetRenderer()
+	if err != nil {
+		return "", err
+	}
+	s.renderer = r
+	return s.renderer.Render(ctx, url, domain.RenderOptions{
+		Timeout:     60 * time.Second,
+		WaitStable:  2 * time.Second,
+		ScrollToEnd: true,
+	})
+}
+
+// finishDocument stamps a converted page and writes it unless in dry-run mode.
+func (s *SitemapStrategy) finishDocument(ctx context.Context, doc *domain.Document, cacheHit bool, opts Options, result *domain.StrategyResult) error {
+	if s.deps.SkipDuplicateCanonical(result, doc) {
+		return nil
+	}
+
+	doc.SourceStrategy = s.Name()
+	doc.CacheHit = cacheHit
+	doc.FetchedAt = time.Now()
+	if s.deps.SkipUnchanged(result, doc) {
+		return nil
+	}
+
+	if !opts.DryRun {
+		if err := s.deps.WriteDocument(ctx, doc); err != nil {
+			result.FailDocument(doc.URL, err)
+			s.logger.Warn().Err(err).Str("url", doc.URL).Msg("Failed to write document")
+			return nil
+		}
+		result.IncWritten()
+		result.AddBytesWritten(int64(len(doc.Content)))
+	}
+
+	return nil
+}
+
 // sitemapXML represents the XML structure of a sitemap
 type sitemapXML struct {
 	XMLName xml.Name     `xml:"urlset"`
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -20,7 +22,9 @@ import (
 	"github.com/quantmind-br/repodocs/internal/output"
 	"github.com/quantmind-br/repodocs/internal/renderer"
 	"github.com/quantmind-br/repodocs/internal/state"
+	"github.com/quantmind-br/repodocs/internal/strategies/git"
 	"github.com/quantmind-br/repodocs/internal/utils"
+	"github.com/schollz/progressbar/v3"
 )
 
 // Strategy defines the interface for documentation extraction strategies
@@ -33,6 +37,32 @@ type Strategy interface {
 	Execute(ctx context.Context, url string, opts Options) (*domain.StrategyResult, error)
 }
 
+// Validator is implemented by strategies that check a URL in more depth than
+// CanHandle before Execute, such as a git repository path missing its repo.
+type Validator interface {
+	// Validate returns a *domain.ValidationError describing why url cannot
+	// be extracted, or nil.
+	Validate(ctx context.Context, url string) error
+}
+
+// Validate runs the Validate hook of s, if it has one. Strategies without a
+// hook accept every URL.
+func Validate(ctx context.Context, s Strategy, url string) error {
+	v, ok := s.(Validator)
+	if !ok {
+		return nil
+	}
+	return v.Validate(ctx, url)
+}
+
+// Execute runs s and returns only its error, for callers that do not use the
+// StrategyResult. The result is finished either way.
+func Execute(ctx context.Context, s Strategy, url string, opts Options) error {
+	result, err := s.Execute(ctx, url, opts)
+	result.Finish()
+	return err
+}
+
 // Options contains common options for all strategies
 type Options struct {
 	domain.CommonOptions
@@ -47,6 +77,48 @@ type Options struct {
 	ExcludeSelector string
 	CacheTTL        string
 	FilterURL       string
+	// PreserveTree makes git extractions mirror the repository tree exactly.
+	PreserveTree bool
+	// HonorGitignore makes git extractions skip paths in the repository's
+	// .gitignore as well as its .repodocsignore.
+	HonorGitignore bool
+	// IgnoreDirs adds directories skipped by git discovery, or replaces the
+	// defaults when ReplaceIgnoreDirs is set.
+	IgnoreDirs        []string
+	ReplaceIgnoreDirs bool
+	// IncludeGitHubMeta makes git extractions include .github issue and
+	// discussion templates and CODEOWNERS.
+	IncludeGitHubMeta bool
+	// Include and ExcludePaths filter the files of git repositories with
+	// gitignore-style globs (see git.DiscoveryOptions). ExcludePaths holds
+	// the user's exclude patterns, without the configured URL regexes.
+	Include      []string
+	ExcludePaths []string
+	// Submodules clones git repositories with their submodules initialized.
+	Submodules bool
+	// LFS fetches Git LFS content of git repositories with git-lfs.
+	LFS bool
+	// CloneTimeout bounds a git clone independently of the request timeout
+	// (0 = no limit).
+	CloneTimeout time.Duration
+	// CleanMDX strips MDX-only syntax from .mdx files of git repositories,
+	// keeping FrontMatterKeys of their front-matter as document metadata.
+	CleanMDX        bool
+	FrontMatterKeys []string
+	// FollowNext makes the crawler walk a documentation sequence through each
+	// page's rel="next" link instead of crawling every link breadth-first.
+	FollowNext bool
+	// NoProgress hides the progress bars of the extraction.
+	NoProgress bool
+}
+
+// newProgressBar returns a progress bar for an extraction with opts, hidden
+// when opts.NoProgress is set.
+func newProgressBar(opts Options, total int, description string) *progressbar.ProgressBar {
+	if opts.NoProgress {
+		return utils.NewProgressBarTo(io.Discard, total, description)
+	}
+	return utils.NewProgressBar(total, description)
 }
 
 // DefaultOptions returns default strategy options
@@ -74,30 +146,102 @@ type Dependencies struct {
 	Collector        *output.MetadataCollector
 	HTTPClient       *http.Client
 	StateManager     *state.Manager
-
-	rendererOnce sync.Once
-	rendererOpts renderer.RendererOptions
-	rendererErr  error
+	// Sitemap builds sitemap.xml from the written documents; nil when no
+	// site base URL is configured.
+	Sitemap *output.SitemapBuilder
+	// Chunks writes the written documents' chunks to chunks.jsonl; nil when
+	// chunking is disabled.
+	Chunks *output.ChunkWriter
+	// RenderDecisions memoizes per-host JS rendering verdicts for the run.
+	// Nil disables the memo and every page is evaluated on its own.
+	RenderDecisions *renderer.RenderDecisions
+	// HostBreaker fails fetches fast to hosts that keep failing; nil when
+	// disabled.
+	HostBreaker *fetcher.HostBreaker
+
+	// gitBranches caches repository default branches for the run.
+	gitBranches *git.BranchDetector
+	hostBudget  *hostBudget
+	errorBudget *errorBudget
+	canonicals  *canonicalSet
+	noEnrich    bool
+	// noSpaceCheck skips the disk space check before git downloads.
+	noSpaceCheck bool
+	// keepTemp keeps the temporary directories of git and wiki downloads.
+	keepTemp bool
+	// gitSelfHostedHosts maps self-hosted git hosts to their platform.
+	gitSelfHostedHosts map[string]git.Platform
+	// proxyURL is the proxy git downloads and API lookups go through.
+	proxyURL string
+	// detectAuthWalls enables SkipAuthWall.
+	detectAuthWalls bool
+	// redirects decides which redirects the fetcher and crawler follow.
+	redirects fetcher.RedirectPolicy
+	// hashAlgorithm is the digest of content hashes; documents are hashed
+	// with it before sync checks and writes (see hashDocument).
+	hashAlgorithm converter.HashAlgorithm
+	retries       retryQueue
+	rendererOnce  sync.Once
+	rendererOpts  renderer.RendererOptions
+	rendererErr   error
+
+	// forceContentType overrides the content type of every page when set.
+	forceContentType string
+	// injectedFetcher is set when Fetcher came from DependencyOptions, so
+	// git archive downloads go through it too.
+	injectedFetcher bool
 }
 
 // NewDependencies creates new dependencies for strategies
 func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
-	// Create fetcher
-	fetcherClient, err := fetcher.NewClient(fetcher.ClientOptions{
-		Timeout:     opts.Timeout,
-		MaxRetries:  3,
-		EnableCache: opts.EnableCache,
-		CacheTTL:    opts.CacheTTL,
-		UserAgent:   opts.UserAgent,
-		ProxyURL:    opts.ProxyURL,
+	logger := opts.Logger
+	if logger == nil {
+		logger = utils.NewLogger(utils.LoggerOptions{
+			Level:   "info",
+			Format:  "pretty",
+			Verbose: opts.Verbose,
+		})
+	}
+
+	hostBreaker := fetcher.NewHostBreaker(fetcher.HostBreakerOptions{
+		Threshold: opts.HostBreakerThreshold,
+		Cooldown:  opts.HostBreakerCooldown,
 	})
-	if err != nil {
-		return nil, err
+
+	redirects := fetcher.RedirectPolicy{
+		MaxRedirects: opts.MaxRedirects,
+		NoCrossHost:  opts.NoCrossHostRedirects,
+		Logger:       logger,
+	}
+
+	// Create fetcher
+	var fetcherClient *fetcher.Client
+	fetcherImpl := opts.Fetcher
+	var err error
+	if fetcherImpl == nil {
+		fetcherClient, err = fetcher.NewClient(fetcher.ClientOptions{
+			Timeout:     opts.Timeout,
+			MaxRetries:  opts.MaxRetries,
+			EnableCache: opts.EnableCache,
+			CacheTTL:    opts.CacheTTL,
+			UserAgent:   opts.UserAgent,
+			UserAgents:  opts.UserAgents,
+			ProxyURL:    opts.ProxyURL,
+
+			ForceContentType: opts.ForceContentType,
+			PreferMarkdown:   opts.PreferMarkdown,
+			HostBreaker:      hostBreaker,
+			Redirects:        redirects,
+		})
+		if err != nil {
+			return nil, err
+		}
+		fetcherImpl = fetcherClient
 	}
 
-	// Create cache if enabled
+	// Create cache if enabled; an injected fetcher does its own caching.
 	var cacheImpl domain.Cache
-	if opts.EnableCache {
+	if opts.EnableCache && fetcherClient != nil {
 		cacheImpl, err = cache.NewBadgerCache(cache.Options{
 			Directory: opts.CacheDir,
 		})
@@ -117,6 +261,11 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 	}
 	rendererOpts.ProxyURL = opts.ProxyURL
 	rendererOpts.CDPEndpoint = opts.CDPEndpoint
+	rendererOpts.ExtraArgs = opts.ChromeArgs
+	rendererOpts.UserAgents = opts.UserAgents
+	rendererOpts.TruncationRetries = opts.TruncationRetries
+	rendererOpts.IncludeHidden = opts.IncludeHidden
+	rendererOpts.Logger = logger
 
 	// Create renderer eagerly only if explicitly requested
 	var rendererImpl domain.Renderer
@@ -129,9 +278,17 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 
 	// Create converter
 	converterPipeline := converter.NewPipeline(converter.PipelineOptions{
-		BaseURL:         "",
-		ContentSelector: opts.ContentSelector,
-		ExcludeSelector: opts.ExcludeSelector,
+		BaseURL:             "",
+		ContentSelector:     opts.ContentSelector,
+		ExcludeSelector:     opts.ExcludeSelector,
+		StripFrontMatter:    opts.StripFrontMatter,
+		FrontMatterKeys:     opts.FrontMatterKeys,
+		NormalizeWhitespace: opts.NormalizeWhitespace,
+		ImageHandling:       opts.ImageHandling,
+		HeadingShift:        opts.HeadingShift,
+		PreserveAdmonitions: opts.PreserveAdmonitions,
+
+		ContentSelectorStrict: opts.ContentSelectorStrict,
 	})
 
 	var collector *output.MetadataCollector
@@ -140,9 +297,26 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 			BaseDir:   opts.OutputDir,
 			SourceURL: opts.SourceURL,
 			Enabled:   true,
+
+			LineEndings: opts.LineEndings,
 		})
 	}
 
+	sitemap := output.NewSitemapBuilder(output.SitemapOptions{
+		BaseDir: opts.OutputDir,
+		BaseURL: opts.SiteBaseURL,
+
+		LineEndings: opts.LineEndings,
+	})
+
+	chunks := output.NewChunkWriter(output.ChunkWriterOptions{
+		BaseDir: opts.OutputDir,
+		Chunk:   opts.Chunks,
+		Keep:    !opts.DryRun,
+
+		LineEndings: opts.LineEndings,
+	})
+
 	// Create writer
 	writer := output.NewWriter(output.WriterOptions{
 		BaseDir:      opts.OutputDir,
@@ -151,14 +325,51 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 		Force:        opts.Force,
 		DryRun:       opts.DryRun,
 		Collector:    collector,
+		Sitemap:      sitemap,
+		Chunks:       chunks,
+		OutputName:   opts.OutputName,
+		SlugFrom:     opts.SlugFrom,
+		LineEndings:  opts.LineEndings,
+		Format:       opts.OutputFormat,
 	})
 
-	// Create logger
-	logger := utils.NewLogger(utils.LoggerOptions{
-		Level:   "info",
-		Format:  "pretty",
-		Verbose: opts.Verbose,
+	throttle := fetcher.NewHostThrottle(fetcher.HostThrottleOptions{
+		Delay:  opts.CrawlDelay,
+		Jitter: opts.RequestJitter,
+		Logger: logger,
+	})
+	if throttle != nil {
+		logger.Debug().
+			Dur("crawl_delay", throttle.Delay()).
+			Dur("request_jitter", throttle.Jitter()).
+			Msg("Per-host crawl delay enabled")
+	}
+	limiter := fetcher.NewHostRateLimiter(fetcher.HostRateLimiterOptions{
+		Rate:   opts.RateLimit,
+		Logger: logger,
 	})
+	if limiter != nil {
+		logger.Debug().
+			Float64("rate_limit", limiter.Rate()).
+			Msg("Per-host rate limit enabled")
+	}
+	if fetcherClient != nil {
+		fetcherClient.SetHostThrottle(throttle)
+		fetcherClient.SetHostRateLimiter(limiter)
+	} else {
+		// An injected fetcher keeps to the same per-host limits.
+		fetcherImpl = fetcher.NewLimitedFetcher(fetcherImpl, fetcher.LimitedFetcherOptions{
+			RateLimiter: limiter,
+			Throttle:    throttle,
+			Breaker:     hostBreaker,
+		})
+	}
+
+	// Default-branch lookups go through the injected fetcher too.
+	branchOpts := git.BranchDetectorOptions{Logger: logger, ProxyURL: opts.ProxyURL}
+	if opts.Fetcher != nil {
+		branchOpts.HTTPClient = fetcherHTTPClient(fetcherImpl)
+	}
 
 	// Surface proxy status and warn about Chrome's inability to authenticate
 	// SOCKS5 proxies when JS rendering is in play (the HTTP fetcher is unaffected).
@@ -181,6 +392,9 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 	// to the HTTP fetcher.
 	if opts.CDPEndpoint != "" {
 		logger.Info().Str("endpoint", opts.CDPEndpoint).Msg("External CDP browser configured for JS rendering; proxy and stealth delegated to the sidecar")
+		if len(opts.ChromeArgs) > 0 {
+			logger.Warn().Strs("chrome_args", opts.ChromeArgs).Msg("Chrome launch flags are ignored when connecting to an external CDP browser")
+		}
 	}
 
 	var llmProvider domain.LLMProvider
@@ -221,6 +435,11 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 		}
 	}
 
+	hashAlgorithm := opts.HashAlgorithm
+	if hashAlgorithm == "" {
+		hashAlgorithm = converter.HashSHA256
+	}
+
 	var stateManager *state.Manager
 	if opts.Sync && !opts.FullSync {
 		stateManager = state.NewManager(state.ManagerOptions{
@@ -228,6 +447,8 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 			SourceURL: opts.SourceURL,
 			Logger:    logger,
 			Disabled:  false,
+
+			HashAlgorithm: string(hashAlgorithm),
 		})
 		if err := stateManager.Load(context.Background()); err != nil {
 			if !errors.Is(err, state.ErrStateNotFound) {
@@ -235,9 +456,10 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 			}
 		}
 	}
+	seedSitemap(sitemap, stateManager)
 
 	return &Dependencies{
-		Fetcher:          fetcherClient,
+		Fetcher:          fetcherImpl,
 		Renderer:         rendererImpl,
 		Cache:            cacheImpl,
 		Converter:        converterPipeline,
@@ -246,11 +468,48 @@ func NewDependencies(opts DependencyOptions) (*Dependencies, error) {
 		LLMProvider:      llmProvider,
 		MetadataEnhancer: metadataEnhancer,
 		Collector:        collector,
+		Sitemap:          sitemap,
+		Chunks:           chunks,
 		StateManager:     stateManager,
-		rendererOpts:     rendererOpts,
+		RenderDecisions:  renderer.NewRenderDecisions(opts.RenderDecisionTTL),
+		HostBreaker:      hostBreaker,
+		forceContentType: opts.ForceContentType,
+		injectedFetcher:  opts.Fetcher != nil,
+		gitBranches:      git.NewBranchDetector(branchOpts),
+		hostBudget:       newHostBudget(opts.MaxPagesPerHost),
+		errorBudget:      newErrorBudget(opts.MaxErrors),
+		canonicals:       newCanonicalSet(),
+		noEnrich:         opts.NoEnrich,
+		noSpaceCheck:     opts.NoSpaceCheck,
+		keepTemp:         opts.KeepTemp,
+
+		gitSelfHostedHosts: opts.GitSelfHostedHosts,
+		proxyURL:           opts.ProxyURL,
+		detectAuthWalls:    opts.DetectAuthWalls,
+		redirects:          redirects,
+		hashAlgorithm:      hashAlgorithm,
+		rendererOpts:       rendererOpts,
 	}, nil
 }
 
+// seedSitemap records the pages of the stored sync state whose files still
+// exist in sitemap, so the pages an incremental run skips as unchanged stay
+// listed.
+func seedSitemap(sitemap *output.SitemapBuilder, stateManager *state.Manager) {
+	if sitemap == nil || stateManager == nil {
+		return
+	}
+	for pageURL, page := range stateManager.Pages() {
+		if page.FilePath == "" {
+			continue
+		}
+		if _, err := os.Stat(page.FilePath); err != nil {
+			continue
+		}
+		sitemap.Add(&domain.Document{URL: pageURL, FetchedAt: page.FetchedAt}, page.FilePath)
+	}
+}
+
 // Close releases all resources
 func (d *Dependencies) Close() error {
 	if d.Fetcher != nil {
@@ -265,6 +524,7 @@ func (d *Dependencies) Close() error {
 	if d.LLMProvider != nil {
 		d.LLMProvider.Close()
 	}
+	d.Chunks.Close()
 	return nil
 }
 
@@ -275,6 +535,22 @@ func (d *Dependencies) FlushMetadata() error {
 	return nil
 }
 
+// FlushSitemap writes sitemap.xml for the documents written so far.
+// ResolveContentType returns the content type to process a page with: the
+// forced type when one is configured, else the header resolved by
+// converter.ResolveContentType. It is used for responses that bypass the
+// Fetcher, such as the crawler's.
+func (d *Dependencies) ResolveContentType(header, pageURL string, body []byte) string {
+	if d != nil && d.forceContentType != "" {
+		return d.forceContentType
+	}
+	return converter.ResolveContentType(header, pageURL, body)
+}
+
+func (d *Dependencies) FlushSitemap() error {
+	return d.Sitemap.Flush()
+}
+
 func (d *Dependencies) SaveState(ctx context.Context) error {
 	if d.StateManager != nil {
 		return d.StateManager.Save(ctx)
@@ -282,8 +558,20 @@ func (d *Dependencies) SaveState(ctx context.Context) error {
 	return nil
 }
 
+// MarkSeen records that pageURL is still part of the source in this run, so
+// PruneDeletedFiles keeps its output even when the page is skipped.
+func (d *Dependencies) MarkSeen(pageURL string) {
+	if d != nil && d.StateManager != nil {
+		d.StateManager.MarkSeen(pageURL)
+	}
+}
+
+// PruneDeletedFiles removes the output files of pages recorded in the state
+// but not seen in this run, then drops them from the state. Only regular
+// files inside the output directory are removed, never a file that a page
+// of this run was written to, and directories left empty are removed too.
 func (d *Dependencies) PruneDeletedFiles(ctx context.Context) (int, error) {
-	if d.StateManager == nil || d.StateManager.IsDisabled() {
+	if d.StateManager == nil || d.StateManager.IsDisabled() || d.Writer == nil {
 		return 0, nil
 	}
 
@@ -291,17 +579,36 @@ func (d *Dependencies) PruneDeletedFiles(ctx context.Context) (int, error) {
 	if len(deleted) == 0 {
 		return 0, nil
 	}
+	live := d.StateManager.SeenFilePaths()
 
 	var pruned int
 	for _, page := range deleted {
-		if err := os.Remove(page.FilePath); err != nil {
-			if !os.IsNotExist(err) {
-				d.Logger.Warn().Err(err).Str("file", page.FilePath).Msg("Failed to remove deleted page")
-				continue
-			}
+		if page.FilePath == "" || live[page.FilePath] {
+			continue
+		}
+		if !d.Writer.Contains(page.FilePath) {
+			d.Logger.Warn().Str("file", page.FilePath).Msg("Not removing deleted page outside the output directory")
+			continue
+		}
+		info, err := os.Lstat(page.FilePath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil && !info.Mode().IsRegular() {
+			d.Logger.Warn().Str("file", page.FilePath).Msg("Not removing deleted page that is not a regular file")
+			continue
+		}
+		if err == nil {
+			err = os.Remove(page.FilePath)
+		}
+		if err != nil {
+			d.Logger.Warn().Err(err).Str("file", page.FilePath).Msg("Failed to remove deleted page")
+			continue
 		}
 		pruned++
+		d.Sitemap.Remove(page.FilePath)
 		d.Logger.Info().Str("file", page.FilePath).Msg("Removed deleted page")
+		d.Writer.RemoveEmptyDirs(filepath.Dir(page.FilePath))
 	}
 
 	d.StateManager.RemoveDeletedFromState()
@@ -324,6 +631,121 @@ func (d *Dependencies) SetSourceURL(url string) {
 	}
 }
 
+// NeedsJSRendering reports whether a statically fetched page should be
+// rendered with JavaScript. A cached host verdict is used when available;
+// otherwise the HTML is inspected and the result recorded for the host.
+func (d *Dependencies) NeedsJSRendering(pageURL, html string) bool {
+	if needsJS, ok := d.RenderDecisions.Lookup(pageURL); ok {
+		return needsJS
+	}
+	needsJS := renderer.NeedsJSRendering(html)
+	d.RenderDecisions.Record(pageURL, needsJS)
+	return needsJS
+}
+
+// renderKnownJSPage renders pageURL without a static fetch when its host is
+// already known to need JavaScript rendering. ok is false when the host
+// verdict is unknown or static, or the render failed; the caller then
+// fetches the page as usual.
+func (d *Dependencies) renderKnownJSPage(ctx context.Context, pageURL string) (html string, ok bool) {
+	if needsJS, known := d.RenderDecisions.Lookup(pageURL); !known || !needsJS {
+		return "", false
+	}
+	r, err := d.GetRenderer()
+	if err != nil {
+		return "", false
+	}
+	html, err = r.Render(ctx, pageURL, domain.RenderOptions{
+		Timeout:     60 * time.Second,
+		WaitStable:  2 * time.Second,
+		ScrollToEnd: true,
+	})
+	return html, err == nil
+}
+
+// TakeHostSlot reserves one page of pageURL's host budget. It returns false
+// once the host has reached MaxPagesPerHost, and the caller should drop the
+// URL. Without a cap every URL is allowed.
+func (d *Dependencies) TakeHostSlot(pageURL string) bool {
+	if d == nil || d.hostBudget.take(pageURL) {
+		return true
+	}
+	if d.Logger != nil {
+		d.Logger.Debug().Str("url", pageURL).Int("max_pages_per_host", d.hostBudget.max).
+			Msg("Dropping URL: host page cap reached")
+	}
+	return false
+}
+
+// HostExhausted reports whether pageURL's host has already used its whole
+// page budget, so queued URLs for it can be dropped without fetching.
+func (d *Dependencies) HostExhausted(pageURL string) bool {
+	return d != nil && d.hostBudget.exhausted(pageURL)
+}
+
+// converterFor returns base, or a copy of it using the content and exclude
+// selectors of opts when a run sets its own (e.g. a manifest source's
+// content_selector or exclude_selector). Empty selectors keep base's.
+func converterFor(base *converter.Pipeline, opts Options) *converter.Pipeline {
+	if base == nil {
+		return nil
+	}
+	contentSelector, excludeSelector := base.ContentSelector(), base.ExcludeSelector()
+	if opts.ContentSelector != "" {
+		contentSelector = opts.ContentSelector
+	}
+	if opts.ExcludeSelector != "" {
+		excludeSelector = opts.ExcludeSelector
+	}
+	if contentSelector == base.ContentSelector() && excludeSelector == base.ExcludeSelector() {
+		return base
+	}
+	return base.WithSelectors(contentSelector, excludeSelector)
+}
+
+// RecordConvertError counts and logs a page whose conversion failed. A page
+// dropped because the strict content selector matched nothing counts as
+// skipped rather than failed.
+func (d *Dependencies) RecordConvertError(result *domain.StrategyResult, pageURL string, err error) {
+	if errors.Is(err, converter.ErrSelectorNotFound) {
+		result.IncSkipped()
+		if d != nil && d.Logger != nil {
+			d.Logger.Info().Err(err).Str("url", pageURL).Msg("Skipping page: content selector matched no content")
+		}
+		return
+	}
+	result.FailDocument(pageURL, err)
+	if d != nil && d.Logger != nil {
+		d.Logger.Warn().Err(err).Str("url", pageURL).Msg("Failed to convert page")
+	}
+}
+
+// NewResult creates the result of one strategy execution, counting its
+// document failures against the run's error budget.
+func (d *Dependencies) NewResult(strategy, entryURL string) *domain.StrategyResult {
+	result := domain.NewStrategyResult(strategy, entryURL)
+	if d == nil || d.errorBudget == nil {
+		return result
+	}
+	result.OnFailure(func() {
+		if d.errorBudget.fail() && d.Logger != nil {
+			d.Logger.Warn().Int("max_errors", d.errorBudget.max).
+				Msg("Document error budget exhausted, aborting run")
+		}
+	})
+	return result
+}
+
+// WithErrorBudget returns a context that is cancelled with
+// domain.ErrTooManyErrors once MaxErrors document failures accumulate. The
+// count restarts on every call. Without a budget ctx is only made cancelable.
+func (d *Dependencies) WithErrorBudget(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d == nil {
+		return context.WithCancel(ctx)
+	}
+	return d.errorBudget.start(ctx)
+}
+
 func (d *Dependencies) GetRenderer() (domain.Renderer, error) {
 	d.rendererOnce.Do(func() {
 		if d.Renderer != nil {
@@ -349,8 +771,21 @@ func (d *Dependencies) GetRenderer() (domain.Renderer, error) {
 	return d.Renderer, nil
 }
 
-// WriteDocument enhances metadata (if configured) and writes the document
+// WriteDocument assigns the document its ID and content hash (see
+// hashDocument), labels it with the labels of ctx (see WithLabels), enriches
+// it with reading time and language (unless disabled), enhances metadata (if
+// configured) and writes the document
 func (d *Dependencies) WriteDocument(ctx context.Context, doc *domain.Document) error {
+	if doc.ID == "" {
+		doc.ID = utils.DocumentID(doc.URL)
+	}
+	d.hashDocument(doc)
+	applyLabels(ctx, doc)
+
+	if !d.noEnrich {
+		converter.Enrich(doc)
+	}
+
 	if d.MetadataEnhancer != nil {
 		if err := d.MetadataEnhancer.Enhance(ctx, doc); err != nil {
 			d.Logger.Warn().Err(err).Str("url", doc.URL).Msg("Failed to enhance metadata, writing without enhancement")
@@ -365,6 +800,7 @@ func (d *Dependencies) WriteDocument(ctx context.Context, doc *domain.Document)
 		return err
 	}
 
+	d.MarkSeen(doc.URL)
 	if d.StateManager != nil && doc.ContentHash != "" {
 		filePath := d.Writer.GetPath(doc.URL)
 		d.StateManager.Update(doc.URL, state.PageState{
@@ -377,28 +813,178 @@ func (d *Dependencies) WriteDocument(ctx context.Context, doc *domain.Document)
 	return nil
 }
 
+// SkipUnchanged reports whether doc should be skipped because it matches its
+// page in the stored sync state. Every strategy calls it before writing, dry
+// runs included, so the comparison is recorded for the --dry-run-state delta
+// (see state.Manager.Delta). Without a state nothing is skipped; with one,
+// doc is hashed with the configured algorithm first. Skipped pages are
+// counted in result as skipped.
+func (d *Dependencies) SkipUnchanged(result *domain.StrategyResult, doc *domain.Document) bool {
+	if d == nil || d.StateManager == nil || doc == nil {
+		return false
+	}
+	if doc.ContentHash == "" {
+		doc.ContentHash = converter.ContentHash(doc.Content)
+		doc.HashAlgorithm = string(converter.HashSHA256)
+	}
+	d.hashDocument(doc)
+	d.MarkSeen(doc.URL)
+	if d.StateManager.ShouldProcess(doc.URL, doc.ContentHash) {
+		return false
+	}
+
+	result.IncSkipped()
+	if d.Logger != nil {
+		d.Logger.Debug().Str("url", doc.URL).Msg("Skipping unchanged page")
+	}
+	return true
+}
+
+// hashDocument re-hashes a hashed document with the configured algorithm
+// (documents are hashed with sha256 when converted) and records the algorithm
+// on it. Documents without a content hash are left untouched.
+func (d *Dependencies) hashDocument(doc *domain.Document) {
+	algo := d.hashAlgorithm
+	if algo == "" {
+		algo = converter.HashSHA256
+	}
+	if doc.ContentHash == "" || doc.HashAlgorithm == string(algo) {
+		return
+	}
+	if algo != converter.HashSHA256 {
+		doc.ContentHash = converter.ContentHashWith(doc.Content, algo)
+	}
+	doc.HashAlgorithm = string(algo)
+}
+
 // DependencyOptions contains options for creating dependencies
 type DependencyOptions struct {
 	domain.CommonOptions
-	Timeout         time.Duration
-	EnableCache     bool
-	CacheTTL        time.Duration
-	CacheDir        string
-	UserAgent       string
+	// Logger is shared by the strategies and their fetcher, renderer and
+	// state; nil creates an info-level logger.
+	Logger *utils.Logger
+	// Fetcher replaces the built-in fetcher.Client for every strategy, e.g.
+	// to route requests through an authenticated egress proxy. The fetch
+	// options below (timeout, retries, cache, user agents and proxy) only
+	// configure the built-in client; the crawl delay, rate limit and host
+	// breaker wrap the injected fetcher too. Dependencies.Close closes it.
+	Fetcher domain.Fetcher
+	Timeout time.Duration
+	// MaxRetries is the number of retries for failed requests (0 uses the
+	// fetcher default).
+	MaxRetries  int
+	EnableCache bool
+	CacheTTL    time.Duration
+	CacheDir    string
+	UserAgent   string
+	// UserAgents are rotated per request by the fetcher and per page by the
+	// renderer when set (see StealthConfig.ResolveUserAgents).
+	UserAgents      []string
 	EnableRenderer  bool
 	RendererTimeout time.Duration
 	Concurrency     int
 	ContentSelector string
 	ExcludeSelector string
-	OutputDir       string
-	Flat            bool
-	JSONMetadata    bool
-	LLMConfig       *config.LLMConfig
-	SourceURL       string
+	// StripFrontMatter removes leaked YAML front-matter from converted HTML,
+	// preserving FrontMatterKeys as document metadata.
+	StripFrontMatter bool
+	FrontMatterKeys  []string
+	// NormalizeWhitespace tidies blank lines and spacing in converted markdown.
+	NormalizeWhitespace bool
+	OutputDir           string
+	Flat                bool
+	JSONMetadata        bool
+	// SiteBaseURL is the URL the output directory is published under; when
+	// set, a sitemap.xml of the written documents is generated.
+	SiteBaseURL string
+	// LineEndings is the line terminator of every written file.
+	LineEndings output.LineEndings
+	// OutputFormat selects markdown or plain-text documents.
+	OutputFormat output.Format
+	// Chunks splits written documents into chunks.jsonl; a zero Size
+	// disables chunking.
+	Chunks output.ChunkOptions
+	// HashAlgorithm is the digest of document content hashes (empty means
+	// sha256).
+	HashAlgorithm converter.HashAlgorithm
+	// OutputName fixes the output filename for single-document runs;
+	// SlugFrom chooses "url" or "title" based filenames.
+	OutputName string
+	SlugFrom   string
+	LLMConfig  *config.LLMConfig
+	SourceURL  string
 	// ProxyURL is the resolved proxy URL (scheme://[user:pass@]host:port) shared
 	// by the HTTP fetcher and the JS renderer. Empty disables proxying.
 	ProxyURL string
 	// CDPEndpoint, when set, makes the JS renderer attach to an external CDP
 	// browser (sidecar) instead of launching local Chrome. Empty launches Chrome.
 	CDPEndpoint string
+	// ChromeArgs are extra launch flags for a locally launched Chrome.
+	ChromeArgs []string
+	// RenderDecisionTTL is how long a per-host "needs JS rendering" verdict is
+	// reused before pages of that host are evaluated again. Zero disables it.
+	RenderDecisionTTL time.Duration
+	// TruncationRetries is the number of extra wait-and-scroll passes given to
+	// a rendered page that looks truncated (0 disables them).
+	TruncationRetries int
+	// IncludeHidden makes the renderer expand collapsed content before
+	// capturing a page.
+	IncludeHidden bool
+	// MaxPagesPerHost caps the pages processed per host across every run that
+	// shares these dependencies (e.g. all sources of a manifest). Zero means
+	// no cap.
+	MaxPagesPerHost int
+	// ForceContentType overrides the content type of every fetched page
+	// (a media type such as "text/markdown"). Empty sniffs generic types.
+	ForceContentType string
+	// PreferMarkdown makes the fetcher request raw markdown before HTML.
+	PreferMarkdown bool
+	// ImageHandling keeps, drops or replaces images with their alt text in
+	// converted and passed-through markdown.
+	ImageHandling converter.ImageHandling
+	// HeadingShift moves every heading of converted and passed-through
+	// markdown by this many levels (positive demotes).
+	HeadingShift int
+	// PreserveAdmonitions renders admonition containers of converted pages
+	// as GitHub callouts (> [!NOTE]).
+	PreserveAdmonitions bool
+	// HostBreakerThreshold is the number of consecutive failures to a host
+	// that opens its circuit breaker for HostBreakerCooldown (0 disables it).
+	HostBreakerThreshold int
+	HostBreakerCooldown  time.Duration
+	// CrawlDelay is the minimum time between requests to one host (0
+	// disables it). Requests to different hosts are not delayed.
+	CrawlDelay time.Duration
+	// RequestJitter is the maximum random delay added before each request,
+	// on top of CrawlDelay (0 disables it).
+	RequestJitter time.Duration
+	// RateLimit is the requests per second allowed to each host (0 disables
+	// it). Every strategy shares the limiter through the fetcher.
+	RateLimit float64
+	// MaxRedirects is the longest redirect chain followed for a page. 0
+	// uses fetcher.DefaultMaxRedirects and a negative value follows none.
+	MaxRedirects int
+	// NoCrossHostRedirects drops redirects to a host other than the one
+	// requested; such pages fail with fetcher.ErrCrossHostRedirect.
+	NoCrossHostRedirects bool
+	// ContentSelectorStrict skips pages where ContentSelector yields no
+	// content instead of falling back to common content containers.
+	ContentSelectorStrict bool
+	// MaxErrors aborts a run once this many documents have failed (0 means
+	// unlimited).
+	MaxErrors int
+	// NoEnrich skips converter.Enrich when documents are written.
+	NoEnrich bool
+	// NoSpaceCheck skips the free disk space check before git repositories
+	// are downloaded.
+	NoSpaceCheck bool
+	// KeepTemp leaves the temporary directories git repositories and wikis
+	// are downloaded to in place, for troubleshooting.
+	KeepTemp bool
+	// GitSelfHostedHosts maps the hosts of self-hosted GitHub, GitLab and
+	// Bitbucket instances to their platform, for the git strategy.
+	GitSelfHostedHosts map[string]git.Platform
+	// DetectAuthWalls skips fetched pages that look like login walls or
+	// paywall stubs instead of writing them (see SkipAuthWall).
+	DetectAuthWalls bool
 }
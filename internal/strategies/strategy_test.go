@@ -2,6 +2,11 @@ package strategies
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +17,8 @@ import (
 	"github.com/quantmind-br/repodocs/internal/fetcher"
 	"github.com/quantmind-br/repodocs/internal/llm"
 	"github.com/quantmind-br/repodocs/internal/output"
+	"github.com/quantmind-br/repodocs/internal/renderer"
+	"github.com/quantmind-br/repodocs/internal/state"
 	"github.com/quantmind-br/repodocs/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -385,6 +392,75 @@ func TestDependencies_SetSourceURL(t *testing.T) {
 	})
 }
 
+// TestDependencies_NeedsJSRendering tests the per-host rendering verdict memo
+func TestDependencies_NeedsJSRendering(t *testing.T) {
+	spaShell := `<html><body><div id="root"></div><script src="/main.js"></script></body></html>`
+	staticPage := `<html><body><main><h1>Guide</h1><p>` + strings.Repeat("Plenty of static content. ", 50) + `</p></main></body></html>`
+
+	t.Run("memo reuses host verdict", func(t *testing.T) {
+		deps := &Dependencies{RenderDecisions: renderer.NewRenderDecisions(time.Minute)}
+		for i := 0; i < 3; i++ {
+			assert.True(t, deps.NeedsJSRendering("https://spa.example.com/p", spaShell))
+		}
+		assert.True(t, deps.NeedsJSRendering("https://spa.example.com/q", staticPage),
+			"decided host skips per-page evaluation")
+	})
+
+	t.Run("without memo every page is evaluated", func(t *testing.T) {
+		deps := &Dependencies{}
+		for i := 0; i < 3; i++ {
+			assert.True(t, deps.NeedsJSRendering("https://spa.example.com/p", spaShell))
+		}
+		assert.False(t, deps.NeedsJSRendering("https://spa.example.com/q", staticPage))
+	})
+}
+
+// stubRenderer renders every page as html and counts the renders.
+type stubRenderer struct {
+	html    string
+	renders int
+}
+
+func (r *stubRenderer) Render(ctx context.Context, url string, opts domain.RenderOptions) (string, error) {
+	r.renders++
+	return r.html, nil
+}
+
+func (r *stubRenderer) Close() error { return nil }
+
+func TestDependencies_FetchDocument_KnownJSHostSkipsStaticFetch(t *testing.T) {
+	deps, err := NewDependencies(DependencyOptions{
+		OutputDir:         t.TempDir(),
+		RenderDecisionTTL: time.Minute,
+		CommonOptions:     domain.CommonOptions{DryRun: true},
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	var fetches int
+	deps.Fetcher = &mockFetcher{getFunc: func(ctx context.Context, url string) (*domain.Response, error) {
+		fetches++
+		return &domain.Response{StatusCode: 200, Body: []byte("<html><body><main><h1>Static</h1></main></body></html>"), ContentType: "text/html", URL: url}, nil
+	}}
+	r := &stubRenderer{html: "<html><body><main><h1>Rendered</h1><p>Built by scripts.</p></main></body></html>"}
+	deps.Renderer = r
+
+	_, err = deps.fetchDocument(context.Background(), "https://spa.example.com/a", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetches, "an undecided host is fetched statically")
+	assert.Zero(t, r.renders)
+
+	for i := 0; i < 3; i++ {
+		deps.RenderDecisions.Record("https://spa.example.com/a", true)
+	}
+	doc, err := deps.fetchDocument(context.Background(), "https://spa.example.com/b", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetches, "a host known to need rendering skips the static fetch")
+	assert.Equal(t, 1, r.renders)
+	assert.True(t, doc.RenderedWithJS)
+	assert.Contains(t, doc.Content, "Rendered")
+}
+
 // TestDependencies_WriteDocument tests WriteDocument
 func TestDependencies_WriteDocument(t *testing.T) {
 	t.Run("without metadata enhancer", func(t *testing.T) {
@@ -515,8 +591,276 @@ func TestDependencies_WriteDocument_EnhancementFailure(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// TestDependencies_WriteDocument_Enrich tests reading-time and language
+// enrichment of written documents
+func TestDependencies_WriteDocument_Enrich(t *testing.T) {
+	for _, noEnrich := range []bool{false, true} {
+		deps, err := NewDependencies(DependencyOptions{
+			Timeout:   10 * time.Second,
+			OutputDir: t.TempDir(),
+			Flat:      true,
+			NoEnrich:  noEnrich,
+		})
+		require.NoError(t, err)
+
+		doc := &domain.Document{
+			URL:       "https://example.com/page",
+			Title:     "Test Page",
+			Content:   "# Test\n\nThis page explains how the tool works and what you can do with it.",
+			WordCount: 250,
+		}
+		require.NoError(t, deps.WriteDocument(context.Background(), doc))
+		deps.Close()
+
+		if noEnrich {
+			assert.Zero(t, doc.ReadingTime)
+			assert.Empty(t, doc.Language)
+		} else {
+			assert.Equal(t, 2, doc.ReadingTime)
+			assert.Equal(t, "en", doc.Language)
+		}
+	}
+}
+
+func TestDependencies_WriteDocument_Labels(t *testing.T) {
+	tmpDir := t.TempDir()
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:   10 * time.Second,
+		OutputDir: tmpDir,
+		Flat:      true,
+		NoEnrich:  true,
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	ctx := WithLabels(context.Background(), map[string]string{"team": "platform", "product": "billing"})
+	doc := &domain.Document{
+		URL:     "https://example.com/page",
+		Title:   "Test Page",
+		Content: "# Test",
+		Labels:  map[string]string{"product": "search"},
+	}
+	require.NoError(t, deps.WriteDocument(ctx, doc))
+
+	assert.Equal(t, map[string]string{"team": "platform", "product": "search"}, doc.Labels, "document labels win over run labels")
+	data, err := os.ReadFile(deps.Writer.GetPath(doc.URL))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "labels:\n    product: search\n    team: platform\n")
+
+	unlabelled := &domain.Document{URL: "https://example.com/other", Title: "Other", Content: "# Other"}
+	require.NoError(t, deps.WriteDocument(context.Background(), unlabelled))
+	assert.Nil(t, unlabelled.Labels)
+}
+
+func TestDependencies_WriteDocument_HashAlgorithm(t *testing.T) {
+	tmpDir := t.TempDir()
+	deps, err := NewDependencies(DependencyOptions{
+		Timeout:       10 * time.Second,
+		OutputDir:     tmpDir,
+		Flat:          true,
+		NoEnrich:      true,
+		HashAlgorithm: converter.HashBLAKE3,
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	doc := &domain.Document{
+		URL:         "https://example.com/page",
+		Title:       "Test Page",
+		Content:     "# Test",
+		ContentHash: converter.ContentHash("# Test"),
+	}
+	require.NoError(t, deps.WriteDocument(context.Background(), doc))
+
+	assert.Equal(t, utils.DocumentID("https://example.com/page"), doc.ID)
+	assert.Equal(t, converter.ContentHashWith("# Test", converter.HashBLAKE3), doc.ContentHash)
+	assert.Equal(t, "blake3", doc.HashAlgorithm)
+	data, err := os.ReadFile(deps.Writer.GetPath(doc.URL))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "id: "+doc.ID+"\n")
+
+	unhashed := &domain.Document{URL: "https://example.com/other", Title: "Other", Content: "# Other"}
+	require.NoError(t, deps.WriteDocument(context.Background(), unhashed))
+	assert.Empty(t, unhashed.ContentHash)
+	assert.Empty(t, unhashed.HashAlgorithm)
+}
+
+func TestDependencies_PruneDeletedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	outsideDir := t.TempDir()
+	deps, err := NewDependencies(DependencyOptions{
+		CommonOptions: domain.CommonOptions{Sync: true},
+		Timeout:       10 * time.Second,
+		OutputDir:     tmpDir,
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	write := func(path string) string {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("# Page"), 0644))
+		return path
+	}
+	removed := write(filepath.Join(tmpDir, "old", "removed.md"))
+	kept := write(filepath.Join(tmpDir, "kept.md"))
+	outside := write(filepath.Join(outsideDir, "outside.md"))
+
+	sm := deps.StateManager
+	sm.Update("https://example.com/old/removed", state.PageState{ContentHash: "a", FilePath: removed})
+	sm.Update("https://example.com/kept", state.PageState{ContentHash: "b", FilePath: kept})
+	sm.Update("https://example.com/renamed", state.PageState{ContentHash: "c", FilePath: kept})
+	sm.Update("https://example.com/outside", state.PageState{ContentHash: "d", FilePath: outside})
+	deps.MarkSeen("https://example.com/kept")
+
+	pruned, err := deps.PruneDeletedFiles(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, pruned)
+	assert.NoFileExists(t, removed)
+	assert.NoDirExists(t, filepath.Join(tmpDir, "old"), "directories left empty are removed")
+	assert.FileExists(t, kept, "a file still owned by a seen page is kept")
+	assert.FileExists(t, outside, "files outside the output directory are never removed")
+	total, _ := sm.Stats()
+	assert.Equal(t, 1, total)
+}
+
+func TestNewDependencies_SyncSeedsSitemap(t *testing.T) {
+	tmpDir := t.TempDir()
+	guide := filepath.Join(tmpDir, "guide.md")
+	require.NoError(t, os.WriteFile(guide, []byte("# Guide"), 0644))
+	removed := filepath.Join(tmpDir, "removed.md")
+	require.NoError(t, os.WriteFile(removed, []byte("# Removed"), 0644))
+
+	previous := state.NewManager(state.ManagerOptions{BaseDir: tmpDir})
+	fetched := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	previous.Update("https://example.com/guide", state.PageState{ContentHash: "a", FetchedAt: fetched, FilePath: guide})
+	previous.Update("https://example.com/removed", state.PageState{ContentHash: "b", FilePath: removed})
+	previous.Update("https://example.com/missing", state.PageState{ContentHash: "c", FilePath: filepath.Join(tmpDir, "missing.md")})
+	require.NoError(t, previous.Save(context.Background()))
+
+	deps, err := NewDependencies(DependencyOptions{
+		CommonOptions: domain.CommonOptions{Sync: true},
+		Timeout:       10 * time.Second,
+		OutputDir:     tmpDir,
+		SiteBaseURL:   "https://kb.example.com",
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+	assert.Equal(t, 2, deps.Sitemap.Count(), "pages of the stored state whose files exist are listed")
+
+	deps.MarkSeen("https://example.com/guide")
+	deps.MarkSeen("https://example.com/missing")
+	pruned, err := deps.PruneDeletedFiles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	require.NoError(t, deps.FlushSitemap())
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, output.SitemapFilename))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<loc>https://kb.example.com/guide.md</loc>")
+	assert.Contains(t, string(data), "<lastmod>2026-03-01T12:00:00Z</lastmod>")
+	assert.NotContains(t, string(data), "removed.md", "pruned pages are dropped")
+}
+
+func TestDependencies_SkipUnchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	deps, err := NewDependencies(DependencyOptions{
+		CommonOptions: domain.CommonOptions{Sync: true, DryRun: true},
+		Timeout:       10 * time.Second,
+		OutputDir:     tmpDir,
+		HashAlgorithm: converter.HashBLAKE3,
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	stored := &domain.Document{URL: "https://example.com/stored", Content: "# Stored"}
+	deps.StateManager.Update(stored.URL, state.PageState{ContentHash: converter.ContentHashWith(stored.Content, converter.HashBLAKE3)})
+	deps.StateManager.Update("https://example.com/edited", state.PageState{ContentHash: "old"})
+	deps.StateManager.Update("https://example.com/gone", state.PageState{ContentHash: "gone"})
+
+	result := domain.NewStrategyResult("test", "https://example.com")
+	assert.True(t, deps.SkipUnchanged(result, stored), "documents built without a hash are hashed first")
+	assert.Equal(t, string(converter.HashBLAKE3), stored.HashAlgorithm)
+	assert.False(t, deps.SkipUnchanged(result, &domain.Document{URL: "https://example.com/edited", Content: "# New"}))
+	assert.False(t, deps.SkipUnchanged(result, &domain.Document{URL: "https://example.com/new", Content: "# New"}))
+	assert.Equal(t, 1, result.Snapshot().DocsSkipped)
+
+	delta := deps.StateManager.Delta()
+	assert.Equal(t, []string{"https://example.com/stored"}, delta.Unchanged)
+	assert.Equal(t, []string{"https://example.com/edited"}, delta.Changed)
+	assert.Equal(t, []string{"https://example.com/new"}, delta.New)
+	assert.Equal(t, []string{"https://example.com/gone"}, delta.Deleted, "checked pages count as seen, even in dry runs")
+
+	var noState *Dependencies
+	assert.False(t, noState.SkipUnchanged(result, stored))
+}
+
+// TestValidate tests the optional Validate hook of strategies
+func TestValidate(t *testing.T) {
+	deps := &Dependencies{Logger: utils.NewLogger(utils.LoggerOptions{Level: "error"})}
+	ctx := context.Background()
+
+	assert.NoError(t, Validate(ctx, NewCrawlerStrategy(deps), "https://example.com"), "strategies without a hook accept every URL")
+
+	pkggo := NewPkgGoStrategy(deps)
+	assert.NoError(t, Validate(ctx, pkggo, "https://pkg.go.dev/net/http"))
+	assert.NoError(t, Validate(ctx, pkggo, "https://pkg.go.dev/github.com/owner/repo@v1.2.3#section"))
+	for url, want := range map[string]string{
+		"https://pkg.go.dev/":            "missing package path",
+		"https://pkg.go.dev/search?q=io": "missing package path",
+		"https://pkg.go.dev/bad path":    "invalid pkg.go.dev package path",
+	} {
+		err := Validate(ctx, pkggo, url)
+		var validationErr *domain.ValidationError
+		require.ErrorAs(t, err, &validationErr, url)
+		assert.Contains(t, err.Error(), want, url)
+	}
+
+	wiki := NewWikiStrategy(deps)
+	assert.NoError(t, Validate(ctx, wiki, "https://github.com/owner/repo/wiki"))
+	err := Validate(ctx, wiki, "https://bitbucket.org/owner/repo/wiki")
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, err.Error(), "github.com/{owner}/{repo}/wiki")
+
+	git := NewGitStrategy(deps)
+	assert.Error(t, Validate(ctx, git, "https://github.com/owner"))
+}
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+
+	ok := &stubStrategy{}
+	require.NoError(t, Execute(ctx, ok, "https://example.com", DefaultOptions()))
+	assert.NotZero(t, ok.result.Duration, "the result is finished")
+
+	failing := &stubStrategy{err: errors.New("boom")}
+	assert.EqualError(t, Execute(ctx, failing, "https://example.com", DefaultOptions()), "boom")
+
+	noResult := &stubStrategy{err: errors.New("setup"), noResult: true}
+	assert.EqualError(t, Execute(ctx, noResult, "https://example.com", DefaultOptions()), "setup")
+}
+
 // Mock types for testing
 
+// stubStrategy returns a fixed result and error from Execute.
+type stubStrategy struct {
+	err      error
+	noResult bool
+	result   *domain.StrategyResult
+}
+
+func (s *stubStrategy) Name() string          { return "stub" }
+func (s *stubStrategy) CanHandle(string) bool { return true }
+func (s *stubStrategy) Execute(_ context.Context, url string, _ Options) (*domain.StrategyResult, error) {
+	if s.noResult {
+		return nil, s.err
+	}
+	s.result = domain.NewStrategyResult(s.Name(), url)
+	time.Sleep(time.Millisecond)
+	return s.result, s.err
+}
+
 type mockLLMProvider struct {
 	fail bool
 }
@@ -592,6 +936,21 @@ func TestNewDependencies_WithContentSelector(t *testing.T) {
 	deps.Close()
 }
 
+func TestNewDependencies_Logger(t *testing.T) {
+	logger := utils.NewLogger(utils.LoggerOptions{Level: "error"})
+	deps, err := NewDependencies(DependencyOptions{
+		Logger:    logger,
+		OutputDir: t.TempDir(),
+		CommonOptions: domain.CommonOptions{
+			DryRun: true,
+		},
+	})
+	require.NoError(t, err)
+	defer deps.Close()
+
+	assert.Same(t, logger, deps.Logger, "strategies log at the level of the injected logger")
+}
+
 // TestNewDependencies_WithExcludeSelector tests with exclude selector
 func TestNewDependencies_WithExcludeSelector(t *testing.T) {
 	deps, err := NewDependencies(DependencyOptions{
@@ -613,6 +972,40 @@ func TestNewDependencies_WithExcludeSelector(t *testing.T) {
 	deps.Close()
 }
 
+// TestDependencies_RecordConvertError tests that strict selector misses count
+// as skipped pages and other conversion errors as failures
+func TestDependencies_RecordConvertError(t *testing.T) {
+	deps := &Dependencies{Logger: utils.NewLogger(utils.LoggerOptions{Level: "error"})}
+	result := domain.NewStrategyResult("crawler", "https://example.com")
+
+	deps.RecordConvertError(result, "https://example.com/a", fmt.Errorf("%w: .docs", converter.ErrSelectorNotFound))
+	deps.RecordConvertError(result, "https://example.com/b", errors.New("parse failed"))
+
+	snapshot := result.Snapshot()
+	assert.Equal(t, 1, snapshot.DocsSkipped)
+	assert.Equal(t, 1, snapshot.DocsFailed)
+	assert.Equal(t, []domain.DocumentFailure{{URL: "https://example.com/b", Error: "parse failed"}}, snapshot.Failures)
+}
+
+// TestConverterFor tests per-run content selector overrides
+func TestConverterFor(t *testing.T) {
+	base := converter.NewPipeline(converter.PipelineOptions{ContentSelector: "article"})
+
+	assert.Same(t, base, converterFor(base, Options{}))
+	assert.Same(t, base, converterFor(base, Options{ContentSelector: "article"}))
+	assert.Nil(t, converterFor(nil, Options{ContentSelector: "main"}))
+
+	override := converterFor(base, Options{ContentSelector: ".md-content, main"})
+	assert.NotSame(t, base, override)
+	assert.Equal(t, ".md-content, main", override.ContentSelector())
+	assert.Equal(t, "article", base.ContentSelector())
+
+	excluding := converterFor(base, Options{ExcludeSelector: "nav, .sidebar"})
+	assert.Equal(t, "article", excluding.ContentSelector())
+	assert.Equal(t, "nav, .sidebar", excluding.ExcludeSelector())
+	assert.Equal(t, "", base.ExcludeSelector())
+}
+
 // TestDependencies_Integration tests integration of all components
 func TestDependencies_Integration(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -76,9 +76,18 @@ func IsWikiURL(url string) bool {
 	return false
 }
 
+// Validate rejects wiki URLs that ParseWikiURL cannot resolve to a GitHub
+// wiki repository.
+func (s *WikiStrategy) Validate(_ context.Context, url string) error {
+	if _, err := ParseWikiURL(url); err != nil {
+		return domain.NewValidationError("url", fmt.Sprintf("%v: expected https://github.com/{owner}/{repo}/wiki", err))
+	}
+	return nil
+}
+
 // Execute runs the wiki extraction strategy
 func (s *WikiStrategy) Execute(ctx context.Context, url string, opts Options) (*domain.StrategyResult, error) {
-	result := domain.NewStrategyResult(s.Name(), url)
+	result := s.deps.NewResult(s.Name(), url)
 	err := s.execute(ctx, url, opts, result)
 	result.Finish()
 	return result, err
@@ -100,11 +109,12 @@ func (s *WikiStrategy) execute(ctx context.Context, url string, opts Options, re
 		Msg("Parsed wiki URL")
 
 	// Step 2: Create temporary directory
-	tmpDir, err := os.MkdirTemp("", "repodocs-wiki-*")
+	keepTemp := s.deps != nil && s.deps.keepTemp
+	tmpDir, cleanup, err := utils.MakeTempDir("repodocs-wiki-*", keepTemp, s.logger)
 	if err != nil {
 		return fmt.Errorf("failed to create temp dir: %w", err)
 	}
-	defer os.RemoveAll(tmpDir)
+	defer cleanup()
 
 	// Step 3: Clone wiki repository
 	if err := s.cloneWiki(ctx, wikiInfo.CloneURL, tmpDir); err != nil {
@@ -246,7 +256,7 @@ func (s *WikiStrategy) processPages(
 	result.AddAttempted(len(processablePages))
 
 	// Create progress bar
-	bar := utils.NewProgressBar(len(processablePages), utils.DescExtracting)
+	bar := newProgressBar(opts, len(processablePages), utils.DescExtracting)
 
 	// Build base wiki URL for references
 	baseWikiURL := fmt.Sprintf("https://github.com/%s/%s/wiki", wikiInfo.Owner, wikiInfo.Repo)
@@ -302,10 +312,13 @@ func (s *WikiStrategy) processPage(
 		SourceStrategy: s.Name(),
 		RelativePath:   relativePath,
 	}
+	if s.deps.SkipUnchanged(result, doc) {
+		return nil
+	}
 
 	if !opts.DryRun {
 		if err := s.deps.WriteDocument(ctx, doc); err != nil {
-			result.IncFailed()
+			result.FailDocument(doc.URL, err)
 			return err
 		}
 		result.IncWritten()
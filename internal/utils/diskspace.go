@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ErrFreeSpaceUnsupported is returned by FreeSpace on platforms where the
+// free space of a filesystem cannot be queried.
+var ErrFreeSpaceUnsupported = errors.New("free disk space is not available on this platform")
+
+// FreeSpace returns the bytes available to unprivileged users on the
+// filesystem holding dir. A dir that does not exist yet is measured at its
+// nearest existing parent.
+func FreeSpace(dir string) (uint64, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return freeSpace(dir)
+}
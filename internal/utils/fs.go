@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // MaxFilenameLength is the maximum length for a filename
@@ -159,6 +160,50 @@ func GeneratePath(baseDir, rawURL string, flat bool) string {
 	return filepath.Join(baseDir, relativePath)
 }
 
+// Slugify converts a title into a lowercase, dash-separated filename stem.
+// It returns "" when the title has no letters or digits.
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+
+	slug := b.String()
+	if limit := MaxFilenameLength - len(".md"); len(slug) > limit {
+		// Cut on a rune boundary so a multi-byte letter is never split.
+		for limit > 0 && !utf8.RuneStart(slug[limit]) {
+			limit--
+		}
+		slug = strings.TrimRight(slug[:limit], "-")
+	}
+	return slug
+}
+
+// GenerateTitlePath generates the output path for a URL using a slug of the
+// document title as the filename. In nested mode the file stays in the
+// directory derived from the URL path. It falls back to GeneratePath when the
+// title yields an empty slug.
+func GenerateTitlePath(baseDir, rawURL, title string, flat bool) string {
+	slug := Slugify(title)
+	if slug == "" {
+		return GeneratePath(baseDir, rawURL, flat)
+	}
+	if flat {
+		return filepath.Join(baseDir, slug+".md")
+	}
+	dir := filepath.Dir(URLToPath(rawURL))
+	return filepath.Join(baseDir, dir, slug+".md")
+}
+
 // GeneratePathFromRelative generates the output path from a relative file path
 // Used for Git-sourced files to preserve the repository's directory structure
 func GeneratePathFromRelative(baseDir, relPath string, flat bool) string {
@@ -215,6 +260,28 @@ func GeneratePathFromRelative(baseDir, relPath string, flat bool) string {
 	return filepath.Join(baseDir, result)
 }
 
+// GenerateTreePath mirrors relPath under baseDir without sanitizing any path
+// component. Markdown (.md/.mdx) and raw files keep their name; other converted
+// files get ".md" appended (README.rst -> README.rst.md). Components that
+// would escape baseDir ("..", a leading "/") are dropped.
+func GenerateTreePath(baseDir, relPath string, raw bool) string {
+	local := filepath.Clean(filepath.FromSlash(relPath))
+	if !filepath.IsLocal(local) {
+		var parts []string
+		for _, part := range strings.Split(filepath.ToSlash(local), "/") {
+			if part != "" && part != "." && part != ".." {
+				parts = append(parts, part)
+			}
+		}
+		local = filepath.Join(parts...)
+	}
+
+	if ext := strings.ToLower(filepath.Ext(local)); !raw && ext != ".md" && ext != ".mdx" {
+		local += ".md"
+	}
+	return filepath.Join(baseDir, local)
+}
+
 // GenerateRawPathFromRelative generates the output path preserving the original file extension.
 // Used for config files (.json, .yaml, .yml, .toml, .env) that should not be converted to markdown.
 func GenerateRawPathFromRelative(baseDir, relPath string, flat bool) string {
@@ -1,10 +1,12 @@
 package utils
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -318,6 +320,31 @@ func TestGeneratePathFromRelative(t *testing.T) {
 	}
 }
 
+func TestGenerateTreePath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		relPath  string
+		raw      bool
+		expected string
+	}{
+		{name: "markdown keeps its name", relPath: "docs/My Guide.md", expected: "/output/docs/My Guide.md"},
+		{name: "mdx keeps its extension", relPath: "docs/intro.mdx", expected: "/output/docs/intro.mdx"},
+		{name: "converted file appends md", relPath: "README.rst", expected: "/output/README.rst.md"},
+		{name: "raw file keeps its name", relPath: "config/app settings.yaml", raw: true, expected: "/output/config/app settings.yaml"},
+		{name: "escaping components are dropped", relPath: "../etc/passwd.md", expected: "/output/etc/passwd.md"},
+		{name: "absolute path stays under base", relPath: "/abs/doc.md", expected: "/output/abs/doc.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GenerateTreePath("/output", tt.relPath, tt.raw)
+			assert.Equal(t, filepath.FromSlash(tt.expected), result)
+		})
+	}
+}
+
 func TestJSONPath(t *testing.T) {
 	t.Parallel()
 
@@ -488,3 +515,55 @@ func TestExpandPath(t *testing.T) {
 		})
 	}
 }
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Getting Started", "getting-started"},
+		{"  API Reference: v2.0 ", "api-reference-v2-0"},
+		{"Café & Crème", "café-crème"},
+		{"---", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.want, Slugify(tt.title))
+		})
+	}
+
+	t.Run("long multi-byte title", func(t *testing.T) {
+		// 98 two-byte runes end at byte 196; the 99th straddles the limit.
+		slug := Slugify(strings.Repeat("é", 100))
+		assert.True(t, utf8.ValidString(slug))
+		assert.Equal(t, strings.Repeat("é", 98), slug)
+		assert.LessOrEqual(t, len(slug), MaxFilenameLength-len(".md"))
+	})
+}
+
+func TestGenerateTitlePath(t *testing.T) {
+	base := filepath.Join("out")
+
+	assert.Equal(t, filepath.Join(base, "guide", "getting-started.md"),
+		GenerateTitlePath(base, "https://example.com/guide/intro", "Getting Started", false))
+	assert.Equal(t, filepath.Join(base, "getting-started.md"),
+		GenerateTitlePath(base, "https://example.com/guide/intro", "Getting Started", true))
+	assert.Equal(t, GeneratePath(base, "https://example.com/guide/intro", false),
+		GenerateTitlePath(base, "https://example.com/guide/intro", "!!!", false))
+}
+
+func TestFreeSpace(t *testing.T) {
+	dir := t.TempDir()
+	free, err := FreeSpace(dir)
+	if errors.Is(err, ErrFreeSpaceUnsupported) {
+		t.Skip(err)
+	}
+	require.NoError(t, err)
+	assert.Positive(t, free)
+
+	missing, err := FreeSpace(filepath.Join(dir, "not", "created"))
+	require.NoError(t, err, "missing directories are measured at their parent")
+	assert.Positive(t, missing)
+}
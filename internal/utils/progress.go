@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/schollz/progressbar/v3"
+import (
+	"io"
+
+	"github.com/schollz/progressbar/v3"
+)
 
 // Standard progress bar descriptions
 const (
@@ -31,6 +35,17 @@ const (
 //	    bar.Add(1)
 //	}
 func NewProgressBar(total int, description string) *progressbar.ProgressBar {
+	return progressbar.NewOptions(total, progressBarOptions(total, description)...)
+}
+
+// NewProgressBarTo is NewProgressBar drawn to w; io.Discard hides the bar.
+func NewProgressBarTo(w io.Writer, total int, description string) *progressbar.ProgressBar {
+	opts := append(progressBarOptions(total, description), progressbar.OptionSetWriter(w))
+	return progressbar.NewOptions(total, opts...)
+}
+
+// progressBarOptions returns the options of NewProgressBar.
+func progressBarOptions(total int, description string) []progressbar.Option {
 	// Build common options
 	opts := []progressbar.Option{
 		progressbar.OptionSetDescription(description),
@@ -51,5 +66,5 @@ func NewProgressBar(total int, description string) *progressbar.ProgressBar {
 		)
 	}
 
-	return progressbar.NewOptions(total, opts...)
+	return opts
 }
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// MakeTempDir creates a temporary working directory (see os.MkdirTemp) and
+// returns it with the function that cleans it up. Cleanup removes the
+// directory, including read-only entries such as those extracted from an
+// archive, and logs when it cannot. With keep, cleanup leaves the directory
+// in place and logs its path for troubleshooting instead. Callers defer the
+// cleanup right away, so the directory is removed on errors, cancellation and
+// panics alike.
+func MakeTempDir(pattern string, keep bool, logger *Logger) (string, func(), error) {
+	dir, err := os.MkdirTemp("", pattern)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := func() {
+		if keep {
+			if logger != nil {
+				logger.Info().Str("dir", dir).Msg("Keeping temporary directory")
+			}
+			return
+		}
+		if err := removeAll(dir); err != nil && logger != nil {
+			logger.Warn().Err(err).Str("dir", dir).Msg("Failed to remove temporary directory")
+		}
+	}
+	return dir, cleanup, nil
+}
+
+// removeAll removes dir like os.RemoveAll, making read-only entries
+// writable when they block the removal.
+func removeAll(dir string) error {
+	if err := os.RemoveAll(dir); err == nil {
+		return nil
+	}
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			_ = os.Chmod(path, 0700)
+		} else if d.Type().IsRegular() {
+			_ = os.Chmod(path, 0600)
+		}
+		return nil
+	})
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeTempDir(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	dir, cleanup, err := MakeTempDir("repodocs-test-*", false, nil)
+	require.NoError(t, err)
+	assert.DirExists(t, dir)
+
+	// Read-only entries, as extracted from an archive, are removed too.
+	readOnly := filepath.Join(dir, "docs")
+	require.NoError(t, os.MkdirAll(readOnly, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(readOnly, "README.md"), []byte("# Readme"), 0444))
+	require.NoError(t, os.Chmod(readOnly, 0555))
+
+	cleanup()
+	assert.NoDirExists(t, dir)
+}
+
+func TestMakeTempDir_Keep(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	dir, cleanup, err := MakeTempDir("repodocs-test-*", true, nil)
+	require.NoError(t, err)
+	cleanup()
+	assert.DirExists(t, dir)
+}
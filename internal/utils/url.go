@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/url"
 	"path"
 	"regexp"
@@ -76,6 +78,18 @@ func NormalizeURLWithoutQuery(rawURL string) (string, error) {
 	return u.String(), nil
 }
 
+// DocumentID returns a stable identifier for the document at rawURL: the
+// SHA-256 hex digest of its normalized URL (see NormalizeURL), so cosmetic
+// URL differences such as host case or fragments map to the same ID.
+func DocumentID(rawURL string) string {
+	canonical, err := NormalizeURL(rawURL)
+	if err != nil {
+		canonical = rawURL
+	}
+	hash := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(hash[:])
+}
+
 // ResolveURL resolves a relative URL against a base URL
 func ResolveURL(base, ref string) (string, error) {
 	// If the base doesn't end with / and doesn't have a file extension,
@@ -191,6 +205,33 @@ func IsHTTPURL(rawURL string) bool {
 	return u.Scheme == "http" || u.Scheme == "https"
 }
 
+// CanonicalRepoURL returns the form of a repository or documentation URL
+// used to tell whether two URLs name the same source: surrounding space,
+// trailing slashes and a trailing ".git" are removed and the scheme and host
+// are lower-cased, so "https://GitHub.com/org/repo.git/" and
+// "https://github.com/org/repo" are equal. SCP-like SSH URLs
+// (git@host:org/repo) get their host lower-cased too.
+func CanonicalRepoURL(rawURL string) string {
+	canonical := strings.TrimRight(strings.TrimSpace(rawURL), "/")
+	canonical = strings.TrimRight(strings.TrimSuffix(canonical, ".git"), "/")
+
+	// Lower-case everything up to the path, leaving the path as written.
+	var hostEnd int
+	if scheme, rest, ok := strings.Cut(canonical, "://"); ok && !strings.ContainsAny(scheme, "/?#") {
+		hostEnd = len(scheme) + len("://")
+		if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+			hostEnd += i
+		} else {
+			hostEnd = len(canonical)
+		}
+	} else if rest, ok := strings.CutPrefix(canonical, "git@"); ok {
+		if i := strings.IndexByte(rest, ':'); i >= 0 {
+			hostEnd = len("git@") + i
+		}
+	}
+	return strings.ToLower(canonical[:hostEnd]) + canonical[hostEnd:]
+}
+
 // IsGitURL checks if a URL is a git repository URL
 func IsGitURL(rawURL string) bool {
 	return strings.HasPrefix(rawURL, "git@") ||
@@ -136,6 +136,16 @@ func TestNormalizeURLWithoutQuery(t *testing.T) {
 	}
 }
 
+func TestDocumentID(t *testing.T) {
+	id := DocumentID("https://example.com/docs/page")
+
+	assert.Len(t, id, 64)
+	assert.Equal(t, id, DocumentID("https://EXAMPLE.com/docs/page/#intro"))
+	assert.Equal(t, id, DocumentID("https://example.com:443/docs/page"))
+	assert.NotEqual(t, id, DocumentID("https://example.com/docs/other"))
+	assert.NotEqual(t, id, DocumentID("https://example.com/docs/page?v=2"))
+}
+
 func TestResolveURL(t *testing.T) {
 	t.Parallel()
 
@@ -422,6 +432,31 @@ func TestIsHTTPURL(t *testing.T) {
 	}
 }
 
+func TestCanonicalRepoURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://github.com/user/repo", expected: "https://github.com/user/repo"},
+		{url: "https://github.com/user/repo.git", expected: "https://github.com/user/repo"},
+		{url: " https://github.com/user/repo.git/ ", expected: "https://github.com/user/repo"},
+		{url: "https://docs.example.com//", expected: "https://docs.example.com"},
+		{url: "git@github.com:user/repo.git", expected: "git@github.com:user/repo"},
+		{url: "https://example.com/repo.github", expected: "https://example.com/repo.github"},
+		{url: "HTTPS://GitHub.com/User/Repo.git", expected: "https://github.com/User/Repo"},
+		{url: "https://Docs.Example.com", expected: "https://docs.example.com"},
+		{url: "git@GitHub.com:User/Repo.git", expected: "git@github.com:User/Repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CanonicalRepoURL(tt.url))
+		})
+	}
+}
+
 func TestIsGitURL(t *testing.T) {
 	t.Parallel()
 
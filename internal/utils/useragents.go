@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// UserAgentRotator hands out User-Agent strings from a fixed list in
+// round-robin order. A nil rotator has no list and returns "".
+type UserAgentRotator struct {
+	agents []string
+	next   atomic.Uint64
+}
+
+// NewUserAgentRotator returns a rotator over agents, ignoring blank entries
+// and duplicates. It returns nil when no User-Agent remains.
+func NewUserAgentRotator(agents []string) *UserAgentRotator {
+	seen := make(map[string]bool, len(agents))
+	var unique []string
+	for _, agent := range agents {
+		agent = strings.TrimSpace(agent)
+		if agent == "" || seen[agent] {
+			continue
+		}
+		seen[agent] = true
+		unique = append(unique, agent)
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+	return &UserAgentRotator{agents: unique}
+}
+
+// Next returns the next User-Agent of the rotation, or "" for a nil rotator.
+// It is safe for concurrent use.
+func (r *UserAgentRotator) Next() string {
+	if r == nil {
+		return ""
+	}
+	n := r.next.Add(1) - 1
+	return r.agents[n%uint64(len(r.agents))]
+}
+
+// Len returns the number of User-Agents in the rotation
+func (r *UserAgentRotator) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.agents)
+}
+
+// ReadUserAgents reads one User-Agent per line, skipping blank lines and
+// lines starting with #.
+func ReadUserAgents(reader io.Reader) ([]string, error) {
+	var agents []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		agents = append(agents, line)
+	}
+	return agents, scanner.Err()
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAgentRotator(t *testing.T) {
+	assert.Nil(t, NewUserAgentRotator(nil))
+	assert.Nil(t, NewUserAgentRotator([]string{"", "  "}))
+
+	var nilRotator *UserAgentRotator
+	assert.Equal(t, "", nilRotator.Next())
+	assert.Equal(t, 0, nilRotator.Len())
+
+	r := NewUserAgentRotator([]string{"agent-a", " agent-b ", "agent-a", ""})
+	require.NotNil(t, r)
+	assert.Equal(t, 2, r.Len())
+	assert.Equal(t, []string{"agent-a", "agent-b", "agent-a", "agent-b"},
+		[]string{r.Next(), r.Next(), r.Next(), r.Next()})
+}
+
+func TestUserAgentRotator_Concurrent(t *testing.T) {
+	r := NewUserAgentRotator([]string{"a", "b"})
+
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			agent := r.Next()
+			mu.Lock()
+			counts[agent]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]int{"a": 50, "b": 50}, counts)
+}
+
+func TestReadUserAgents(t *testing.T) {
+	agents, err := ReadUserAgents(strings.NewReader("# polite bots\nagent-a\n\n  agent-b  \n#agent-c\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent-a", "agent-b"}, agents)
+}
@@ -228,6 +228,11 @@ func ParallelForEach[T any](ctx context.Context, items []T, workers int, fn func
 					if !ok {
 						return
 					}
+					// select picks at random when a task is queued after
+					// cancellation; never start one then.
+					if ctx.Err() != nil {
+						return
+					}
 					err := fn(ctx, items[idx])
 					mu.Lock()
 					errors[idx] = err
@@ -293,6 +293,19 @@ func TestParallelForEach(t *testing.T) {
 		assert.NoError(t, errors[0])
 		assert.NoError(t, errors[1])
 	})
+
+	t.Run("no item starts after cancellation", func(t *testing.T) {
+		for range 50 {
+			ctx, cancel := context.WithCancel(context.Background())
+			var calls int
+			ParallelForEach(ctx, []int{1, 2, 3}, 1, func(ctx context.Context, item int) error {
+				calls++
+				cancel()
+				return nil
+			})
+			require.Equal(t, 1, calls)
+		}
+	})
 }
 
 func TestFirstError(t *testing.T) {
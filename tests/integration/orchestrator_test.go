@@ -132,7 +132,7 @@ func TestFullPipeline_GitRepo(t *testing.T) {
 		strategy := orchestrator.GetStrategyName("https://github.com/user/repo")
 		assert.Equal(t, "git", strategy)
 
-		err := orchestrator.ValidateURL("https://github.com/user/repo")
+		err := orchestrator.ValidateURL(context.Background(), "https://github.com/user/repo")
 		assert.NoError(t, err)
 	})
 
@@ -140,7 +140,7 @@ func TestFullPipeline_GitRepo(t *testing.T) {
 		strategy := orchestrator.GetStrategyName("https://gitlab.com/user/repo")
 		assert.Equal(t, "git", strategy)
 
-		err := orchestrator.ValidateURL("https://gitlab.com/user/repo")
+		err := orchestrator.ValidateURL(context.Background(), "https://gitlab.com/user/repo")
 		assert.NoError(t, err)
 	})
 
@@ -148,7 +148,7 @@ func TestFullPipeline_GitRepo(t *testing.T) {
 		strategy := orchestrator.GetStrategyName("https://github.com/user/repo.git")
 		assert.Equal(t, "git", strategy)
 
-		err := orchestrator.ValidateURL("https://github.com/user/repo.git")
+		err := orchestrator.ValidateURL(context.Background(), "https://github.com/user/repo.git")
 		assert.NoError(t, err)
 	})
 }
@@ -239,7 +239,7 @@ func TestFullPipeline_PkgGo(t *testing.T) {
 		strategy := orchestrator.GetStrategyName(url)
 		assert.Equal(t, "pkggo", strategy)
 
-		err := orchestrator.ValidateURL(url)
+		err := orchestrator.ValidateURL(context.Background(), url)
 		assert.NoError(t, err)
 	})
 
@@ -248,7 +248,7 @@ func TestFullPipeline_PkgGo(t *testing.T) {
 		strategy := orchestrator.GetStrategyName(url)
 		assert.Equal(t, "pkggo", strategy)
 
-		err := orchestrator.ValidateURL(url)
+		err := orchestrator.ValidateURL(context.Background(), url)
 		assert.NoError(t, err)
 	})
 
@@ -257,7 +257,7 @@ func TestFullPipeline_PkgGo(t *testing.T) {
 		strategy := orchestrator.GetStrategyName(url)
 		assert.Equal(t, "pkggo", strategy)
 
-		err := orchestrator.ValidateURL(url)
+		err := orchestrator.ValidateURL(context.Background(), url)
 		assert.NoError(t, err)
 	})
 }
@@ -221,13 +221,13 @@ func TestGetAllStrategies_Ordering(t *testing.T) {
 
 	strategies := app.GetAllStrategies(deps)
 
-	// Should have exactly 8 strategies
-	assert.Len(t, strategies, 8, "Should have exactly 8 strategies")
+	// Should have exactly 9 strategies
+	assert.Len(t, strategies, 9, "Should have exactly 9 strategies")
 
 	// Check expected order (priority order for detection)
-	// Order must match DetectStrategy priority: llms > pkggo > docsrs > sitemap > wiki > github_pages > git > crawler
+	// Order must match DetectStrategy priority: llms > openapi > pkggo > docsrs > sitemap > wiki > github_pages > git > crawler
 	// pkggo must come before git because pkg.go.dev URLs contain github.com in the path
-	expectedOrder := []string{"llms", "pkggo", "docsrs", "sitemap", "wiki", "github_pages", "git", "crawler"}
+	expectedOrder := []string{"llms", "openapi", "pkggo", "docsrs", "sitemap", "wiki", "github_pages", "git", "crawler"}
 	actualNames := make([]string, len(strategies))
 
 	for i, strategy := range strategies {
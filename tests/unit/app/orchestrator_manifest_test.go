@@ -2,7 +2,10 @@ package app_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -83,6 +86,36 @@ func TestOrchestrator_RunManifest_AllSuccess(t *testing.T) {
 	assert.Contains(t, mock.execCalls, "https://example3.com")
 }
 
+func TestOrchestrator_RunManifest_Results(t *testing.T) {
+	mock := &manifestTestStrategy{name: "mock"}
+	orchestrator := createTestOrchestrator(t, mock)
+	defer orchestrator.Close()
+	assert.Nil(t, orchestrator.ManifestResults())
+
+	disabled := false
+	manifestCfg := &manifest.Config{
+		Sources: []manifest.Source{
+			{URL: "https://example1.com"},
+			{URL: "https://example2.com", Enabled: &disabled},
+		},
+		Options: manifest.Options{Output: t.TempDir()},
+	}
+
+	cfg := config.Default()
+	cfg.Cache.Enabled = false
+	err := orchestrator.RunManifest(context.Background(), manifestCfg, app.OrchestratorOptions{Config: cfg})
+	require.NoError(t, err)
+
+	results := orchestrator.ManifestResults()
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Error)
+	assert.Equal(t, "mock", results[0].Result.Strategy)
+	assert.Equal(t, "https://example1.com", results[0].Result.EntryURL)
+	assert.Equal(t, 1, results[0].Result.DocsWritten)
+	assert.True(t, results[1].Skipped)
+	assert.Zero(t, results[1].Result.DocsWritten)
+}
+
 func TestOrchestrator_RunManifest_ContinueOnError_True(t *testing.T) {
 	mock := &manifestTestStrategy{
 		name: "mock",
@@ -404,3 +437,92 @@ func TestOrchestrator_Run_InvalidStrategyOverride(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unknown strategy override")
 }
+
+func TestOrchestrator_RunManifest_DisabledSources(t *testing.T) {
+	mock := &manifestTestStrategy{name: "mock"}
+	orchestrator := createTestOrchestrator(t, mock)
+	defer orchestrator.Close()
+
+	disabled := false
+	manifestCfg := &manifest.Config{
+		Sources: []manifest.Source{
+			{URL: "https://enabled.com"},
+			{URL: "https://disabled.com", Enabled: &disabled},
+		},
+		Options: manifest.Options{Output: t.TempDir()},
+	}
+
+	cfg := config.Default()
+	cfg.Cache.Enabled = false
+	err := orchestrator.RunManifest(context.Background(), manifestCfg, app.OrchestratorOptions{Config: cfg})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://enabled.com"}, mock.execCalls)
+}
+
+func TestOrchestrator_RunManifest_AllSourcesDisabled(t *testing.T) {
+	mock := &manifestTestStrategy{name: "mock"}
+	orchestrator := createTestOrchestrator(t, mock)
+	defer orchestrator.Close()
+
+	disabled := false
+	manifestCfg := &manifest.Config{
+		Sources: []manifest.Source{{URL: "https://disabled.com", Enabled: &disabled}},
+		Options: manifest.Options{Output: t.TempDir()},
+	}
+
+	cfg := config.Default()
+	cfg.Cache.Enabled = false
+	err := orchestrator.RunManifest(context.Background(), manifestCfg, app.OrchestratorOptions{Config: cfg})
+
+	require.NoError(t, err, "disabled sources are skipped, not failed")
+	assert.Empty(t, mock.execCalls)
+}
+
+func TestOrchestrator_RunManifest_WritesReport(t *testing.T) {
+	mock := &manifestTestStrategy{
+		name: "mock",
+		execFunc: func(ctx context.Context, url string, opts strategies.Options) error {
+			if url == "https://fail.com" {
+				return errors.New("simulated failure")
+			}
+			return nil
+		},
+	}
+	orchestrator := createTestOrchestrator(t, mock)
+	defer orchestrator.Close()
+
+	manifestCfg := &manifest.Config{
+		Sources: []manifest.Source{
+			{URL: "https://success.com"},
+			{URL: "https://fail.com"},
+			{URL: "https://disabled.com", Disabled: true},
+		},
+		Options: manifest.Options{ContinueOnError: true, Output: t.TempDir()},
+	}
+
+	cfg := config.Default()
+	cfg.Cache.Enabled = false
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	err := orchestrator.RunManifest(context.Background(), manifestCfg, app.OrchestratorOptions{Config: cfg, ReportPath: reportPath})
+	require.Error(t, err)
+
+	data, err := os.ReadFile(reportPath)
+	require.NoError(t, err, "the report is written when sources fail")
+	var report app.ManifestReport
+	require.NoError(t, json.Unmarshal(data, &report))
+
+	assert.Equal(t, app.ReportPartial, report.Status)
+	assert.True(t, report.ContinueOnError)
+	assert.NotEmpty(t, report.Error)
+	assert.Equal(t, 3, report.Totals.Sources)
+	assert.Equal(t, 1, report.Totals.Succeeded)
+	assert.Equal(t, 1, report.Totals.Failed)
+	assert.Equal(t, 1, report.Totals.Skipped)
+	assert.Equal(t, 2, report.Totals.DocsWritten)
+	require.Len(t, report.Sources, 3)
+	assert.Equal(t, app.SourceReport{URL: "https://success.com", Strategy: "mock", Status: app.SourceSucceeded, DocsWritten: 1, DurationMS: report.Sources[0].DurationMS}, report.Sources[0])
+	assert.Equal(t, app.SourceFailed, report.Sources[1].Status)
+	assert.Contains(t, report.Sources[1].Error, "strategy execution failed")
+	assert.Equal(t, app.SourceSkipped, report.Sources[2].Status)
+}
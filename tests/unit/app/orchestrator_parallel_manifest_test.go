@@ -3,6 +3,9 @@ package app_test
 import (
 	"context"
 	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -421,3 +424,58 @@ func TestRunManifest_Parallel_ConcurrencyCap(t *testing.T) {
 	assert.LessOrEqual(t, maxConcurrent.Load(), int32(3),
 		"Concurrency should be capped at 3 for manifest processing")
 }
+
+// initDocsRepo creates a git repository under dir with a README and one
+// guide and returns its file:// URL.
+func initDocsRepo(t *testing.T, dir, name string) string {
+	t.Helper()
+	repo := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "README.md"), []byte("# "+name), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "docs", "guide.md"), []byte("# Guide for "+name), 0o644))
+	for _, args := range [][]string{
+		{"init", "-b", "main"},
+		{"add", "."},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-m", "docs"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return "file://" + filepath.ToSlash(repo)
+}
+
+// TestRunManifest_Parallel_GitSources extracts three repositories at once
+// through the real git strategy, which share the writer, state manager and
+// branch cache; run it with -race.
+func TestRunManifest_Parallel_GitSources(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	reposDir := t.TempDir()
+	names := []string{"alpha", "beta", "gamma"}
+	sources := make([]manifest.Source, len(names))
+	for i, name := range names {
+		sources[i] = manifest.Source{URL: initDocsRepo(t, reposDir, name), Strategy: "git", Output: name}
+	}
+
+	cfg := config.Default()
+	cfg.Cache.Enabled = false
+	cfg.Output.Directory = t.TempDir()
+	orchestrator, err := app.NewOrchestrator(app.OrchestratorOptions{Config: cfg})
+	require.NoError(t, err)
+	defer orchestrator.Close()
+
+	err = orchestrator.RunManifest(context.Background(), &manifest.Config{
+		Sources: sources,
+		Options: manifest.Options{Output: cfg.Output.Directory},
+	}, app.OrchestratorOptions{Config: cfg, ConcurrencySources: len(names)})
+	require.NoError(t, err)
+
+	for i, result := range orchestrator.ManifestResults() {
+		require.NoError(t, result.Error, names[i])
+		assert.Equal(t, 2, result.Result.DocsWritten, names[i])
+		assert.FileExists(t, filepath.Join(cfg.Output.Directory, names[i], "README.md"))
+	}
+}
@@ -284,7 +284,7 @@ func TestOrchestrator_ValidateURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := orchestrator.ValidateURL(tt.url)
+			err := orchestrator.ValidateURL(context.Background(), tt.url)
 			if tt.wantErr {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.errorMsg)
@@ -540,7 +540,7 @@ func TestOrchestrator_ValidateURL_EdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := orchestrator.ValidateURL(tt.url)
+			err := orchestrator.ValidateURL(context.Background(), tt.url)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), "unsupported URL format")
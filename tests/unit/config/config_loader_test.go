@@ -749,7 +749,7 @@ func TestLoad_WithInvalidValuesInConfig(t *testing.T) {
 concurrency:
   workers: 0  # Invalid, should be corrected
   timeout: 100ms  # Invalid, should be corrected
-  max_depth: -5  # Invalid, should be corrected
+  max_depth: 0  # Unset, should be defaulted
 
 cache:
   ttl: 10s  # Invalid, should be corrected
@@ -779,6 +779,35 @@ rendering:
 	assert.Equal(t, config.DefaultJSTimeout, cfg.Rendering.JSTimeout)
 }
 
+func TestLoad_WithNegativeValuesInConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+concurrency:
+  max_depth: -5
+cache:
+  ttl: -1h
+`
+	require.NoError(t, os.WriteFile(configFile, []byte(configContent), 0644))
+
+	originalWd, err := os.Getwd()
+	require.NoError(t, err)
+	defer os.Chdir(originalWd)
+
+	require.NoError(t, os.Chdir(tmpDir))
+
+	cfg, _, err := config.LoadWithViper()
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+
+	var fields []string
+	for _, ve := range config.ValidationErrors(err) {
+		fields = append(fields, ve.Field)
+	}
+	assert.ElementsMatch(t, []string{"concurrency.max_depth", "cache.ttl"}, fields)
+}
+
 // ============================================================================
 // Concurrent Load Tests
 // ============================================================================
@@ -6,6 +6,7 @@ import (
 
 	"github.com/quantmind-br/repodocs/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfig_DefaultValues(t *testing.T) {
@@ -346,14 +347,20 @@ func TestConfig_Validate_NegativeValues(t *testing.T) {
 	}
 
 	err := cfg.Validate()
-	assert.NoError(t, err)
+	require.Error(t, err)
 
-	// Negative values should be corrected to defaults
-	assert.Equal(t, config.DefaultWorkers, cfg.Concurrency.Workers)
-	assert.Equal(t, config.DefaultMaxDepth, cfg.Concurrency.MaxDepth)
-	assert.Equal(t, config.DefaultTimeout, cfg.Concurrency.Timeout)
-	assert.Equal(t, config.DefaultCacheTTL, cfg.Cache.TTL)
-	assert.Equal(t, config.DefaultJSTimeout, cfg.Rendering.JSTimeout)
+	// Negative values are reported, one validation error per field
+	var fields []string
+	for _, ve := range config.ValidationErrors(err) {
+		fields = append(fields, ve.Field)
+	}
+	assert.ElementsMatch(t, []string{
+		"concurrency.workers",
+		"concurrency.max_depth",
+		"concurrency.timeout",
+		"cache.ttl",
+		"rendering.js_timeout",
+	}, fields)
 }
 
 func TestConfig_Validate_BoundaryValues(t *testing.T) {
@@ -468,7 +475,6 @@ func TestConfig_Validate_WorkersTable(t *testing.T) {
 		expected int
 	}{
 		{"zero workers", 0, config.DefaultWorkers},
-		{"negative workers", -10, config.DefaultWorkers},
 		{"one worker", 1, 1},
 		{"valid workers", 10, 10},
 		{"large workers", 100, 100},
@@ -498,7 +504,6 @@ func TestConfig_Validate_MaxDepthTable(t *testing.T) {
 		expected int
 	}{
 		{"zero depth", 0, config.DefaultMaxDepth},
-		{"negative depth", -5, config.DefaultMaxDepth},
 		{"depth of one", 1, 1},
 		{"valid depth", 5, 5},
 		{"large depth", 50, 50},
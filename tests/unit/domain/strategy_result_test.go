@@ -0,0 +1,35 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/quantmind-br/repodocs/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyResult_FailDocument(t *testing.T) {
+	result := domain.NewStrategyResult("sitemap", "https://example.com/sitemap.xml")
+	budget := 0
+	result.OnFailure(func() { budget++ })
+
+	result.FailDocument("https://example.com/a", errors.New("HTTP 500"))
+	result.FailDocument("https://example.com/b", nil)
+
+	snapshot := result.Snapshot()
+	assert.Equal(t, 2, snapshot.DocsFailed)
+	assert.Equal(t, 2, budget, "recorded failures count against the error budget")
+	assert.Equal(t, []domain.DocumentFailure{
+		{URL: "https://example.com/a", Error: "HTTP 500"},
+		{URL: "https://example.com/b", Error: "unknown error"},
+	}, snapshot.Failures)
+
+	result.ClearFailure("https://example.com/a")
+	snapshot = result.Snapshot()
+	assert.Equal(t, 1, snapshot.DocsFailed)
+	assert.Equal(t, []domain.DocumentFailure{{URL: "https://example.com/b", Error: "unknown error"}}, snapshot.Failures)
+
+	var none *domain.StrategyResult
+	none.FailDocument("https://example.com/c", nil)
+	none.ClearFailure("https://example.com/c")
+}
@@ -1,13 +1,19 @@
 package fetcher_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/andybalholm/brotli"
+	"github.com/quantmind-br/repodocs/internal/converter"
 	"github.com/quantmind-br/repodocs/internal/domain"
 	"github.com/quantmind-br/repodocs/internal/fetcher"
 	"github.com/stretchr/testify/assert"
@@ -640,3 +646,169 @@ func (m *mockCache) Size() int {
 func (m *mockCache) Stats() map[string]interface{} {
 	return nil
 }
+
+func TestClient_Get_FinalURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/private" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer server.Close()
+
+	client, err := fetcher.NewClient(fetcher.ClientOptions{
+		EnableCache: false,
+		MaxRetries:  0,
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Get(context.Background(), server.URL+"/private")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/private", resp.URL)
+	assert.Equal(t, server.URL+"/login", resp.FinalURL)
+
+	resp, err = client.Get(context.Background(), server.URL+"/docs")
+	require.NoError(t, err)
+	assert.Empty(t, resp.FinalURL, "not redirected")
+}
+
+func TestClient_Get_DecodesContentEncoding(t *testing.T) {
+	const html = `<html><head><title>Encoded</title></head><body><article><h1>Encoded</h1>
+<p>This page was compressed by the server before it was sent.</p>
+<h2>Install</h2><p>Download the binary and put it on your path.</p></article></body></html>`
+
+	encoders := map[string]func(w io.Writer) io.WriteCloser{
+		"br":      func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) },
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+	}
+
+	for encoding, newWriter := range encoders {
+		t.Run(encoding, func(t *testing.T) {
+			var compressed bytes.Buffer
+			w := newWriter(&compressed)
+			_, err := w.Write([]byte(html))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "gzip, deflate, br", r.Header.Get("Accept-Encoding"))
+				w.Header().Set("Content-Encoding", encoding)
+				w.Write(compressed.Bytes())
+			}))
+			defer server.Close()
+
+			client, err := fetcher.NewClient(fetcher.ClientOptions{
+				EnableCache: false,
+				MaxRetries:  0,
+			})
+			require.NoError(t, err)
+
+			resp, err := client.Get(context.Background(), server.URL+"/docs")
+			require.NoError(t, err)
+			assert.Equal(t, html, string(resp.Body))
+			assert.Empty(t, resp.Headers.Get("Content-Encoding"))
+			assert.Contains(t, resp.ContentType, "text/html")
+
+			doc, err := converter.NewPipeline(converter.PipelineOptions{BaseURL: server.URL}).
+				Convert(context.Background(), string(resp.Body), resp.URL)
+			require.NoError(t, err)
+			assert.Equal(t, "Encoded", doc.Title)
+			assert.Contains(t, doc.Content, "## Install")
+			assert.Contains(t, doc.Content, "compressed by the server")
+		})
+	}
+}
+
+func TestClient_Get_RedirectPolicy(t *testing.T) {
+	var otherHits int
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherHits++
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>elsewhere</body></html>"))
+	}))
+	defer other.Close()
+	// Same server, but a different host name than 127.0.0.1.
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusMovedPermanently)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, otherURL+"/docs", http.StatusFound)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><body>final</body></html>"))
+		}
+	}))
+	defer server.Close()
+
+	newClient := func(policy fetcher.RedirectPolicy) *fetcher.Client {
+		client, err := fetcher.NewClient(fetcher.ClientOptions{
+			EnableCache: false,
+			MaxRetries:  0,
+			Redirects:   policy,
+		})
+		require.NoError(t, err)
+		return client
+	}
+
+	t.Run("chain followed", func(t *testing.T) {
+		resp, err := newClient(fetcher.RedirectPolicy{MaxRedirects: 5}).Get(context.Background(), server.URL+"/a")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "final")
+		assert.Equal(t, server.URL+"/a", resp.URL)
+		assert.Equal(t, server.URL+"/c", resp.FinalURL)
+		assert.Equal(t, server.URL+"/c", resp.PageURL())
+	})
+
+	t.Run("zero limit uses the default", func(t *testing.T) {
+		resp, err := newClient(fetcher.RedirectPolicy{}).Get(context.Background(), server.URL+"/a")
+		require.NoError(t, err)
+		assert.Equal(t, server.URL+"/c", resp.FinalURL)
+	})
+
+	t.Run("negative limit follows none", func(t *testing.T) {
+		resp, err := newClient(fetcher.RedirectPolicy{MaxRedirects: -1}).Get(context.Background(), server.URL+"/a")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	})
+
+	t.Run("chain longer than the limit", func(t *testing.T) {
+		_, err := newClient(fetcher.RedirectPolicy{MaxRedirects: 1}).Get(context.Background(), server.URL+"/a")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "too many redirects")
+	})
+
+	t.Run("cross-host redirect dropped", func(t *testing.T) {
+		otherHits = 0
+		_, err := newClient(fetcher.RedirectPolicy{MaxRedirects: 5, NoCrossHost: true}).Get(context.Background(), server.URL+"/away")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, fetcher.ErrCrossHostRedirect)
+		assert.Zero(t, otherHits)
+	})
+
+	t.Run("cross-host redirect followed by default", func(t *testing.T) {
+		resp, err := newClient(fetcher.RedirectPolicy{MaxRedirects: 5}).Get(context.Background(), server.URL+"/away")
+		require.NoError(t, err)
+		assert.Contains(t, string(resp.Body), "elsewhere")
+		assert.Equal(t, otherURL+"/docs", resp.FinalURL)
+	})
+
+	t.Run("transport leaves redirects to its caller", func(t *testing.T) {
+		transport := newClient(fetcher.RedirectPolicy{MaxRedirects: 5}).Transport()
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/a", nil)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+		assert.Equal(t, "/b", resp.Header.Get("Location"))
+	})
+}
@@ -1,6 +1,7 @@
 package git_test
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -125,7 +126,7 @@ func TestProcessFile_ProcessesNormalFiles(t *testing.T) {
 
 		// 10MB exactly should be processed (limit is > 10MB, not >=)
 		justUnderSize := 10 * 1024 * 1024
-		err := os.WriteFile(justUnderPath, make([]byte, justUnderSize), 0644)
+		err := os.WriteFile(justUnderPath, bytes.Repeat([]byte("a"), justUnderSize), 0644)
 		require.NoError(t, err)
 
 		writeCalled := false
@@ -564,16 +564,19 @@ func TestPipeline_ContentSelector_CommaSeparated(t *testing.T) {
 	</body>
 	</html>`
 
+	// Comma-separated selectors are alternatives tried in order; the first
+	// one with non-empty content wins.
 	pipeline := converter.NewPipeline(converter.PipelineOptions{
-		ContentSelector: "#title, #body",
+		ContentSelector: "#missing, #body, #title",
 	})
 
 	doc, err := pipeline.Convert(context.Background(), html, "https://example.com/comma")
 	require.NoError(t, err)
 	require.NotNil(t, doc)
 
-	assert.Contains(t, doc.Content, "Page Title")
 	assert.Contains(t, doc.Content, "Main content")
+	assert.NotContains(t, doc.Content, "Page Title")
+	assert.NotContains(t, doc.Content, "Navigation")
 }
 
 func TestPipeline_ExcludeSelector(t *testing.T) {
@@ -30,6 +30,22 @@ func TestOutcomeError_Error_FilterZeroed(t *testing.T) {
 	assert.Contains(t, msg, "filtered URL as the entry point")
 }
 
+func TestOutcomeError_Error_ListsFailedDocuments(t *testing.T) {
+	result := domain.NewStrategyResult("crawler", "https://example.com")
+	result.IncAttempted()
+	result.FailDocument("https://example.com/broken", errors.New("HTTP 500"))
+	result.Finish()
+
+	err := recovery.NewOutcomeError(recovery.VerdictHardFail{
+		Reason: "extraction produced 0 documents",
+		Cause:  domain.ErrInsufficientOutput,
+	}, result)
+
+	msg := err.Error()
+	assert.Contains(t, msg, "Failed:      1 docs")
+	assert.Contains(t, msg, "https://example.com/broken: HTTP 500")
+}
+
 func TestOutcomeError_Unwrap_HardFail(t *testing.T) {
 	base := errors.New("base")
 	err := recovery.NewOutcomeError(recovery.VerdictHardFail{Reason: "failed", Cause: base}, nil)
@@ -2,9 +2,12 @@ package git_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,7 +27,7 @@ func TestProcessor_FindDocumentationFiles_EmptyDir(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	p := git.NewProcessor(git.ProcessorOptions{})
-	files, err := p.FindDocumentationFiles(tmpDir, "")
+	files, err := p.FindDocumentationFiles(context.Background(), tmpDir, "")
 
 	assert.NoError(t, err)
 	assert.Empty(t, files)
@@ -39,8 +42,8 @@ func TestProcessor_FindDocumentationFiles_MarkdownOnly(t *testing.T) {
 	os.WriteFile(filepath.Join(tmpDir, "config.txt"), []byte("config"), 0644)
 	os.WriteFile(filepath.Join(tmpDir, "image.png"), []byte("image"), 0644)
 
-	p := git.NewProcessor(git.ProcessorOptions{})
-	files, err := p.FindDocumentationFiles(tmpDir, "")
+	p := git.NewProcessor(git.ProcessorOptions{Extensions: map[string]bool{".md": true}})
+	files, err := p.FindDocumentationFiles(context.Background(), tmpDir, "")
 
 	assert.NoError(t, err)
 	assert.Len(t, files, 3)
@@ -60,7 +63,7 @@ func TestProcessor_FindDocumentationFiles_WithSubdirectories(t *testing.T) {
 	os.WriteFile(filepath.Join(guideDir, "install.md"), []byte("# Install"), 0644)
 
 	p := git.NewProcessor(git.ProcessorOptions{})
-	files, err := p.FindDocumentationFiles(tmpDir, "")
+	files, err := p.FindDocumentationFiles(context.Background(), tmpDir, "")
 
 	assert.NoError(t, err)
 	assert.Len(t, files, 3)
@@ -95,7 +98,7 @@ func TestProcessor_FindDocumentationFiles_ExcludeDirs(t *testing.T) {
 	os.WriteFile(filepath.Join(docs, "docs.md"), []byte("# Docs"), 0644)
 
 	p := git.NewProcessor(git.ProcessorOptions{})
-	files, err := p.FindDocumentationFiles(tmpDir, "")
+	files, err := p.FindDocumentationFiles(context.Background(), tmpDir, "")
 
 	assert.NoError(t, err)
 	assert.Len(t, files, 1)
@@ -116,7 +119,7 @@ func TestProcessor_FindDocumentationFiles_WithFilterPath(t *testing.T) {
 	os.WriteFile(filepath.Join(docsDir, "README.md"), []byte("# Readme"), 0644)
 
 	p := git.NewProcessor(git.ProcessorOptions{})
-	files, err := p.FindDocumentationFiles(tmpDir, "docs/api")
+	files, err := p.FindDocumentationFiles(context.Background(), tmpDir, "docs/api")
 
 	assert.NoError(t, err)
 	assert.Len(t, files, 1)
@@ -127,7 +130,7 @@ func TestProcessor_FindDocumentationFiles_FilterPathNotExists(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	p := git.NewProcessor(git.ProcessorOptions{})
-	_, err := p.FindDocumentationFiles(tmpDir, "nonexistent")
+	_, err := p.FindDocumentationFiles(context.Background(), tmpDir, "nonexistent")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "filter path does not exist")
@@ -139,12 +142,81 @@ func TestProcessor_FindDocumentationFiles_FilterPathIsFile(t *testing.T) {
 	os.WriteFile(filePath, []byte("# Readme"), 0644)
 
 	p := git.NewProcessor(git.ProcessorOptions{})
-	_, err := p.FindDocumentationFiles(tmpDir, "README.md")
+	_, err := p.FindDocumentationFiles(context.Background(), tmpDir, "README.md")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "filter path is not a directory")
 }
 
+// writeLargeTree creates dirs directories of files markdown files each.
+func writeLargeTree(t *testing.T, dirs, files int) string {
+	t.Helper()
+	root := t.TempDir()
+	for d := 0; d < dirs; d++ {
+		dir := filepath.Join(root, fmt.Sprintf("section-%03d", d))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		for f := 0; f < files; f++ {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("page-%03d.md", f)), []byte("# Page"), 0644))
+		}
+	}
+	return root
+}
+
+func TestProcessor_FindDocumentationFiles_Cancelled(t *testing.T) {
+	root := writeLargeTree(t, 50, 60)
+	p := git.NewProcessor(git.ProcessorOptions{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	files, err := p.FindDocumentationFiles(ctx, root, "")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, files)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestProcessor_ProcessFiles_Cancelled(t *testing.T) {
+	root := writeLargeTree(t, 50, 60)
+	p := git.NewProcessor(git.ProcessorOptions{})
+	files, err := p.FindDocumentationFiles(context.Background(), root, "")
+	require.NoError(t, err)
+	require.Len(t, files, 3000)
+
+	var written atomic.Int32
+	opts := git.ProcessOptions{
+		RepoURL:     "https://github.com/owner/repo",
+		Branch:      "main",
+		Concurrency: 4,
+		WriteFunc: func(ctx context.Context, doc *domain.Document) error {
+			written.Add(1)
+			return nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	err = p.ProcessFiles(ctx, files, root, opts)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, written.Load(), "no file is processed after cancellation")
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Cancelling mid-run stops handing out the remaining files.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	written.Store(0)
+	opts.WriteFunc = func(ctx context.Context, doc *domain.Document) error {
+		if written.Add(1) == 10 {
+			cancel()
+		}
+		return nil
+	}
+	err = p.ProcessFiles(ctx, files, root, opts)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, int(written.Load()), 10+opts.Concurrency)
+}
+
 func TestExtractTitleFromPath(t *testing.T) {
 	tests := []struct {
 		name     string